@@ -5,6 +5,9 @@
 package main
 
 import (
+	// embed the time zone database to evaluate maintenance windows, in case it is missing from the operator image
+	_ "time/tzdata"
+
 	"github.com/spf13/cobra"
 
 	"github.com/elastic/cloud-on-k8s/v2/cmd/manager"
@@ -33,6 +33,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -64,6 +65,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/autoscaling"
 	esavalidation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/autoscaling/elasticsearch/validation"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/beat"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
 	commonlicense "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
@@ -206,6 +208,21 @@ func Command() *cobra.Command {
 		false,
 		"Disable watching the configuration file for changes",
 	)
+	cmd.Flags().Duration(
+		operator.ElasticsearchClientHealthTimeout,
+		0,
+		"Timeout for the health and info requests made by the Elasticsearch client. Defaults to the Elasticsearch client timeout if not set.",
+	)
+	cmd.Flags().Duration(
+		operator.ElasticsearchClientLongTimeout,
+		0,
+		"Timeout for the long-running requests made by the Elasticsearch client, such as flushes and secure settings reloads. Only applied if greater than the Elasticsearch client timeout.",
+	)
+	cmd.Flags().Int(
+		operator.ElasticsearchClientMaxConnsPerHost,
+		0,
+		"Maximum number of connections opened by the Elasticsearch client to each Elasticsearch cluster. No limit if not set.",
+	)
 	cmd.Flags().Duration(
 		operator.ElasticsearchClientTimeout,
 		3*time.Minute,
@@ -231,11 +248,31 @@ func Command() *cobra.Command {
 		false, // Set to false for backward compatibility
 		"Restrict cross-namespace resource association through RBAC (eg. referencing Elasticsearch from Kibana)",
 	)
+	cmd.Flags().StringSlice(
+		operator.CrossNamespaceRefsAllowlistFlag,
+		[]string{},
+		"Comma separated list of <source namespace>:<target namespace> pairs restricting cross-namespace resource association, * matches any namespace. All cross-namespace associations are allowed if empty",
+	)
 	cmd.Flags().Bool(
 		operator.EnableLeaderElection,
 		true,
 		"Enable leader election. Enabling this will ensure there is only one active operator.",
 	)
+	cmd.Flags().Duration(
+		operator.LeaderElectionLeaseDurationFlag,
+		15*time.Second,
+		"Duration that non-leader operator candidates wait after observing a leadership renewal before attempting to acquire leadership.",
+	)
+	cmd.Flags().Duration(
+		operator.LeaderElectionRenewDeadlineFlag,
+		10*time.Second,
+		"Duration that the leading operator retries refreshing leadership before giving up. Must be lower than the lease duration.",
+	)
+	cmd.Flags().Duration(
+		operator.LeaderElectionRetryPeriodFlag,
+		2*time.Second,
+		"Duration the operator candidates wait between tries of actions. Must be lower than the renew deadline divided by 1.2.",
+	)
 	cmd.Flags().Bool(
 		operator.EnableTracingFlag,
 		false,
@@ -303,6 +340,15 @@ func Command() *cobra.Command {
 		"",
 		"Kubernetes namespace the operator runs in",
 	)
+	cmd.Flags().Duration(
+		operator.ResyncPeriodFlag,
+		0,
+		fmt.Sprintf(
+			"Default period at which resources are reconciled again after a successful reconciliation, bounded to [%s, %s]. "+
+				"It can be overridden per resource with the %s annotation. 0 disables the periodic reconciliation.",
+			common.MinResyncPeriod, common.MaxResyncPeriod, common.ResyncPeriodAnnotation,
+		),
+	)
 	cmd.Flags().Duration(
 		operator.TelemetryIntervalFlag,
 		1*time.Hour,
@@ -342,7 +388,7 @@ func Command() *cobra.Command {
 	cmd.Flags().String(
 		operator.SetDefaultSecurityContextFlag,
 		"auto-detect",
-		"Enables setting the default security context with fsGroup=1000 for Elasticsearch 8.0+ Pods. Ignored pre-8.0. Possible values: true, false, auto-detect",
+		"Enables setting the default security context with fsGroup=1000 for Elasticsearch 8.0+ Pods, and with runAsUser=1000 and fsGroup=1000 for Kibana Pods. Ignored pre-8.0 for Elasticsearch. Possible values: true, false, auto-detect",
 	)
 
 	// hide development mode flags from the usage message
@@ -543,6 +589,9 @@ func startOperator(ctx context.Context) error {
 	cfg.Timeout = viper.GetDuration(operator.KubeClientTimeout)
 	// set the timeout for Elasticsearch requests
 	esclient.DefaultESClientTimeout = viper.GetDuration(operator.ElasticsearchClientTimeout)
+	esclient.DefaultESClientHealthTimeout = viper.GetDuration(operator.ElasticsearchClientHealthTimeout)
+	esclient.DefaultESClientLongRequestTimeout = viper.GetDuration(operator.ElasticsearchClientLongTimeout)
+	esclient.DefaultESClientMaxConnsPerHost = viper.GetInt(operator.ElasticsearchClientMaxConnsPerHost)
 
 	// Setup Scheme for all resources
 	log.Info("Setting up scheme")
@@ -550,6 +599,12 @@ func startOperator(ctx context.Context) error {
 	// also set up the v1beta1 scheme, used by the v1beta1 webhook
 	controllerscheme.SetupV1beta1Scheme()
 
+	leaseDuration, renewDeadline, retryPeriod, err := validateLeaderElectionFlags()
+	if err != nil {
+		log.Error(err, "Invalid leader election parameters")
+		return err
+	}
+
 	// Create a new Cmd to provide shared dependencies and start components
 	opts := ctrl.Options{
 		Scheme:                     clientgoscheme.Scheme,
@@ -557,6 +612,9 @@ func startOperator(ctx context.Context) error {
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaderElectionID:           LeaderElectionLeaseName,
 		LeaderElectionNamespace:    operatorNamespace,
+		LeaseDuration:              &leaseDuration,
+		RenewDeadline:              &renewDeadline,
+		RetryPeriod:                &retryPeriod,
 		Logger:                     log.WithName("eck-operator"),
 	}
 
@@ -655,12 +713,24 @@ func startOperator(ctx context.Context) error {
 		return err
 	}
 
+	crossNamespaceRefsAllowlist, err := rbac.NewNamespaceAllowlist(viper.GetStringSlice(operator.CrossNamespaceRefsAllowlistFlag))
+	if err != nil {
+		log.Error(err, "Failed to parse cross-namespace references allowlist")
+		return err
+	}
+
 	setDefaultSecurityContext, err := determineSetDefaultSecurityContext(viper.GetString(operator.SetDefaultSecurityContextFlag), clientset)
 	if err != nil {
 		log.Error(err, "failed to determine how to set default security context")
 		return err
 	}
 
+	resyncPeriod := viper.GetDuration(operator.ResyncPeriodFlag)
+	if clamped := common.ClampResyncPeriod(resyncPeriod); clamped != resyncPeriod && resyncPeriod > 0 {
+		log.Info("Resync period out of bounds, using the closest bound", "resync_period", resyncPeriod, "used", clamped)
+		resyncPeriod = clamped
+	}
+
 	// default hash cache is arbitrarily set to 5 x MaxConcurrentReconcilesFlag
 	hashCacheSize := viper.GetInt(operator.MaxConcurrentReconcilesFlag) * 5
 	if viper.IsSet(operator.PasswordHashCacheSize) {
@@ -673,6 +743,7 @@ func startOperator(ctx context.Context) error {
 	}
 
 	params := operator.Parameters{
+		CrossNamespaceRefsAllowlist:      crossNamespaceRefsAllowlist,
 		Dialer:                           dialer,
 		ElasticsearchObservationInterval: viper.GetDuration(operator.ElasticsearchObservationIntervalFlag),
 		ExposedNodeLabels:                exposedNodeLabels,
@@ -690,6 +761,7 @@ func startOperator(ctx context.Context) error {
 		},
 		PasswordHasher:            passwordHasher,
 		MaxConcurrentReconciles:   viper.GetInt(operator.MaxConcurrentReconcilesFlag),
+		ResyncPeriod:              resyncPeriod,
 		SetDefaultSecurityContext: setDefaultSecurityContext,
 		ValidateStorageClass:      viper.GetBool(operator.ValidateStorageClassFlag),
 		Tracer:                    tracer,
@@ -929,17 +1001,45 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 	return nil
 }
 
+// validateCertExpirationFlags returns the validity and rotation lead time of the certificates set by the given flags.
+// The validity must be positive, and larger than the rotation lead time.
 func validateCertExpirationFlags(validityFlag string, rotateBeforeFlag string) (time.Duration, time.Duration, error) {
 	certValidity := viper.GetDuration(validityFlag)
 	certRotateBefore := viper.GetDuration(rotateBeforeFlag)
 
-	if certRotateBefore > certValidity {
+	switch {
+	case certValidity <= 0:
+		return certValidity, certRotateBefore, fmt.Errorf("%s must be positive", validityFlag)
+	case certRotateBefore < 0:
+		return certValidity, certRotateBefore, fmt.Errorf("%s must not be negative", rotateBeforeFlag)
+	case certRotateBefore >= certValidity:
+		// certificates would be reissued right after their creation
 		return certValidity, certRotateBefore, fmt.Errorf("%s must be larger than %s", validityFlag, rotateBeforeFlag)
 	}
 
 	return certValidity, certRotateBefore, nil
 }
 
+// validateLeaderElectionFlags returns the lease duration, renew deadline and retry period of the leader election. The
+// retry period must be positive, the renew deadline larger than the jittered retry period and the lease duration larger
+// than the renew deadline.
+func validateLeaderElectionFlags() (time.Duration, time.Duration, time.Duration, error) {
+	leaseDuration := viper.GetDuration(operator.LeaderElectionLeaseDurationFlag)
+	renewDeadline := viper.GetDuration(operator.LeaderElectionRenewDeadlineFlag)
+	retryPeriod := viper.GetDuration(operator.LeaderElectionRetryPeriodFlag)
+
+	switch {
+	case retryPeriod <= 0:
+		return leaseDuration, renewDeadline, retryPeriod, fmt.Errorf("%s must be positive", operator.LeaderElectionRetryPeriodFlag)
+	case renewDeadline <= time.Duration(leaderelection.JitterFactor*float64(retryPeriod)):
+		return leaseDuration, renewDeadline, retryPeriod, fmt.Errorf("%s must be larger than %v times %s", operator.LeaderElectionRenewDeadlineFlag, leaderelection.JitterFactor, operator.LeaderElectionRetryPeriodFlag)
+	case leaseDuration <= renewDeadline:
+		return leaseDuration, renewDeadline, retryPeriod, fmt.Errorf("%s must be larger than %s", operator.LeaderElectionLeaseDurationFlag, operator.LeaderElectionRenewDeadlineFlag)
+	}
+
+	return leaseDuration, renewDeadline, retryPeriod, nil
+}
+
 func garbageCollectUsers(ctx context.Context, cfg *rest.Config, managedNamespaces []string) error {
 	span, ctx := apm.StartSpan(ctx, "gc_users", tracing.SpanTypeApp)
 	defer span.End()
@@ -8,7 +8,9 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -25,6 +27,8 @@ import (
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
@@ -337,3 +341,130 @@ func newFakeK8sClientsetWithDiscovery(resources []*metav1.APIResourceList, disco
 	}
 	return client
 }
+
+func Test_validateCertExpirationFlags(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		wantValidity     time.Duration
+		wantRotateBefore time.Duration
+		wantErr          bool
+	}{
+		{
+			name:             "defaults",
+			args:             nil,
+			wantValidity:     certificates.DefaultCertValidity,
+			wantRotateBefore: certificates.DefaultRotateBefore,
+		},
+		{
+			name:             "short-lived certificates",
+			args:             []string{"--cert-validity=72h", "--cert-rotate-before=12h"},
+			wantValidity:     72 * time.Hour,
+			wantRotateBefore: 12 * time.Hour,
+		},
+		{
+			name:    "rotation lead time equal to the validity",
+			args:    []string{"--cert-validity=24h", "--cert-rotate-before=24h"},
+			wantErr: true,
+		},
+		{
+			name:    "rotation lead time larger than the validity",
+			args:    []string{"--cert-validity=12h"},
+			wantErr: true,
+		},
+		{
+			name:    "zero validity",
+			args:    []string{"--cert-validity=0s", "--cert-rotate-before=0s"},
+			wantErr: true,
+		},
+		{
+			name:    "negative rotation lead time",
+			args:    []string{"--cert-rotate-before=-1h"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer viper.Reset()
+			cmd := Command()
+			require.NoError(t, cmd.Flags().Parse(tt.args))
+			require.NoError(t, viper.BindPFlags(cmd.Flags()))
+
+			validity, rotateBefore, err := validateCertExpirationFlags(operator.CertValidityFlag, operator.CertRotateBeforeFlag)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantValidity, validity)
+			require.Equal(t, tt.wantRotateBefore, rotateBefore)
+		})
+	}
+}
+
+func Test_validateLeaderElectionFlags(t *testing.T) {
+	tests := []struct {
+		name              string
+		args              []string
+		wantLeaseDuration time.Duration
+		wantRenewDeadline time.Duration
+		wantRetryPeriod   time.Duration
+		wantErr           bool
+	}{
+		{
+			name:              "defaults",
+			args:              nil,
+			wantLeaseDuration: 15 * time.Second,
+			wantRenewDeadline: 10 * time.Second,
+			wantRetryPeriod:   2 * time.Second,
+		},
+		{
+			name:              "custom values",
+			args:              []string{"--leader-election-lease-duration=6s", "--leader-election-renew-deadline=4s", "--leader-election-retry-period=1s"},
+			wantLeaseDuration: 6 * time.Second,
+			wantRenewDeadline: 4 * time.Second,
+			wantRetryPeriod:   1 * time.Second,
+		},
+		{
+			name:    "renew deadline not lower than the lease duration",
+			args:    []string{"--leader-election-lease-duration=10s", "--leader-election-renew-deadline=10s"},
+			wantErr: true,
+		},
+		{
+			name:    "renew deadline lower than the jittered retry period",
+			args:    []string{"--leader-election-renew-deadline=5s", "--leader-election-retry-period=5s"},
+			wantErr: true,
+		},
+		{
+			name:    "zero retry period",
+			args:    []string{"--leader-election-retry-period=0s"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			args:    []string{"--leader-election-lease-duration=fast"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer viper.Reset()
+			cmd := Command()
+			if err := cmd.Flags().Parse(tt.args); err != nil {
+				require.True(t, tt.wantErr, "unexpected flag parsing error: %v", err)
+				return
+			}
+			require.NoError(t, viper.BindPFlags(cmd.Flags()))
+
+			leaseDuration, renewDeadline, retryPeriod, err := validateLeaderElectionFlags()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantLeaseDuration, leaseDuration)
+			require.Equal(t, tt.wantRenewDeadline, renewDeadline)
+			require.Equal(t, tt.wantRetryPeriod, retryPeriod)
+		})
+	}
+}
@@ -30,6 +30,7 @@ var (
 		checkEmptyConfigForFleetMode,
 		checkFleetServerOnlyInFleetMode,
 		checkHTTPConfigOnlyForFleetServer,
+		checkTLSOptions,
 		checkFleetServerOrFleetServerRef,
 		checkReferenceSetForMode,
 		checkSingleESRefInFleetMode,
@@ -256,6 +257,10 @@ func checkHTTPConfigOnlyForFleetServer(a *Agent) field.ErrorList {
 	return nil
 }
 
+func checkTLSOptions(a *Agent) field.ErrorList {
+	return commonv1.CheckNoElasticsearchOnlyTLSOptions(field.NewPath("spec").Child("http", "tls"), a.Spec.HTTP.TLS)
+}
+
 func checkReferenceSetForMode(a *Agent) field.ErrorList {
 	var errors field.ErrorList
 	if a.Spec.StandaloneModeEnabled() {
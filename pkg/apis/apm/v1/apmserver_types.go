@@ -39,6 +39,12 @@ type ApmServerSpec struct {
 	// HTTP holds the HTTP layer configuration for the APM Server resource.
 	HTTP commonv1.HTTPConfig `json:"http,omitempty"`
 
+	// RUM configures the Real User Monitoring endpoint of the APM Server, which receives the events of the APM agents
+	// running in web browsers. Settings also specified in Config take precedence, lists specified in Config being
+	// appended to the ones specified here.
+	// +kubebuilder:validation:Optional
+	RUM *RUMConfig `json:"rum,omitempty"`
+
 	// ElasticsearchRef is a reference to the output Elasticsearch cluster running in the same Kubernetes cluster.
 	ElasticsearchRef commonv1.ObjectSelector `json:"elasticsearchRef,omitempty"`
 
@@ -63,6 +69,22 @@ type ApmServerSpec struct {
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 }
 
+// RUMConfig holds the Real User Monitoring configuration of the APM Server.
+type RUMConfig struct {
+	// Enabled enables the Real User Monitoring endpoint. Defaults to false.
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// AllowOrigins is the list of origins allowed to send events, such as https://*.example.com. Origins may contain
+	// the * wildcard, a single * allowing any origin. Defaults to any origin.
+	// +kubebuilder:validation:Optional
+	AllowOrigins []string `json:"allowOrigins,omitempty"`
+
+	// AllowHeaders is the list of additional HTTP headers the agents are allowed to send, on top of the ones allowed by default.
+	// +kubebuilder:validation:Optional
+	AllowHeaders []string `json:"allowHeaders,omitempty"`
+}
+
 // ApmServerStatus defines the observed state of ApmServer
 type ApmServerStatus struct {
 	commonv1.DeploymentStatus `json:",inline"`
@@ -79,6 +101,12 @@ type ApmServerStatus struct {
 	// KibanaAssociationStatus is the status of any auto-linking to Kibana.
 	KibanaAssociationStatus commonv1.AssociationStatus `json:"kibanaAssociationStatus,omitempty"`
 
+	// Conditions holds the conditions reporting the status of the associations of the APM Server, one per association type.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
 	// ObservedGeneration represents the .metadata.generation that the status is based upon.
 	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
 	// If the generation observed in status diverges from the generation in metadata, the APM Server
@@ -200,6 +228,11 @@ func (as *ApmServer) SetAssociationStatusMap(typ commonv1.AssociationType, statu
 	}
 }
 
+// AssociationConditions returns a pointer to the conditions reporting the status of the associations of the APM Server.
+func (as *ApmServer) AssociationConditions() *[]metav1.Condition {
+	return &as.Status.Conditions
+}
+
 // GetObservedGeneration will return the observedGeneration from the Elastic APM Server's status.
 func (as *ApmServer) GetObservedGeneration() int64 {
 	return as.Status.ObservedGeneration
@@ -308,3 +341,4 @@ func (akb *ApmKibanaAssociation) AssociationID() string {
 }
 
 var _ commonv1.Associated = &ApmServer{}
+var _ commonv1.AssociationConditionsHolder = &ApmServer{}
@@ -7,6 +7,10 @@ package v1
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -38,6 +42,8 @@ var (
 		checkSupportedVersion,
 		checkAgentConfigurationMinVersion,
 		checkAssociations,
+		checkRUM,
+		checkTLSOptions,
 	}
 
 	updateChecks = []func(old, curr *ApmServer) field.ErrorList{
@@ -152,3 +158,46 @@ func checkAssociations(as *ApmServer) field.ErrorList {
 	err2 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("kibanaRef"), as.Spec.KibanaRef)
 	return append(err1, err2...)
 }
+
+func checkRUM(as *ApmServer) field.ErrorList {
+	if as.Spec.RUM == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("rum")
+	for i, origin := range as.Spec.RUM.AllowOrigins {
+		if err := validateRUMOrigin(origin); err != nil {
+			errs = append(errs, field.Invalid(path.Child("allowOrigins").Index(i), origin, err.Error()))
+		}
+	}
+	for i, header := range as.Spec.RUM.AllowHeaders {
+		if !httpguts.ValidHeaderFieldName(header) {
+			errs = append(errs, field.Invalid(path.Child("allowHeaders").Index(i), header, "must be a valid HTTP header name"))
+		}
+	}
+	return errs
+}
+
+func checkTLSOptions(as *ApmServer) field.ErrorList {
+	return commonv1.CheckNoElasticsearchOnlyTLSOptions(field.NewPath("spec").Child("http", "tls"), as.Spec.HTTP.TLS)
+}
+
+// validateRUMOrigin checks that the given origin pattern is either a single * wildcard, or a scheme and a host, with an
+// optional port, where the host may contain * wildcards.
+func validateRUMOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	// wildcards are not valid in URLs, replace them to validate the rest of the pattern
+	u, err := url.Parse(strings.ReplaceAll(origin, "*", "x"))
+	if err != nil {
+		return errors.New("must be a valid origin")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("must start with http:// or https://")
+	}
+	if u.Host == "" || u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return errors.New("must only contain a scheme, a host and an optional port")
+	}
+	return nil
+}
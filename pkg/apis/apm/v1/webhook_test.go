@@ -230,6 +230,54 @@ func TestWebhook(t *testing.T) {
 				`spec.elasticsearchRef: Forbidden: Invalid association reference: serviceName or namespace can only be used in combination with name, not with secretName`,
 			),
 		},
+		{
+			Name:      "rum-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				apm := mkApmServer(uid)
+				apm.Spec.RUM = &apmv1.RUMConfig{
+					Enabled:      true,
+					AllowOrigins: []string{"*", "https://*.example.com", "http://localhost:8080", "https://example.com/"},
+					AllowHeaders: []string{"X-Custom-Header"},
+				}
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "rum-invalid-origins",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				apm := mkApmServer(uid)
+				apm.Spec.RUM = &apmv1.RUMConfig{
+					Enabled:      true,
+					AllowOrigins: []string{"example.com", "ftp://example.com", "https://example.com/app", "https://", "https://exa mple.com"},
+				}
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.rum.allowOrigins\[0\]: Invalid value: "example.com": must start with http:// or https://`,
+				`spec.rum.allowOrigins\[1\]: Invalid value: "ftp://example.com": must start with http:// or https://`,
+				`spec.rum.allowOrigins\[2\]: Invalid value: "https://example.com/app": must only contain a scheme, a host and an optional port`,
+				`spec.rum.allowOrigins\[3\]: Invalid value: "https://": must only contain a scheme, a host and an optional port`,
+				`spec.rum.allowOrigins\[4\]: Invalid value: "https://exa mple.com": must be a valid origin`,
+			),
+		},
+		{
+			Name:      "rum-invalid-headers",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				apm := mkApmServer(uid)
+				apm.Spec.RUM = &apmv1.RUMConfig{Enabled: true, AllowHeaders: []string{"X Custom"}}
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.rum.allowHeaders\[0\]: Invalid value: "X Custom": must be a valid HTTP header name`,
+			),
+		},
 	}
 
 	validator := &apmv1.ApmServer{}
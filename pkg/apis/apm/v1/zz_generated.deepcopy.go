@@ -10,6 +10,7 @@ package v1
 
 import (
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -59,7 +60,7 @@ func (in *ApmServer) DeepCopyInto(out *ApmServer) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	if in.esAssocConf != nil {
 		in, out := &in.esAssocConf, &out.esAssocConf
 		*out = new(commonv1.AssociationConf)
@@ -130,6 +131,11 @@ func (in *ApmServerSpec) DeepCopyInto(out *ApmServerSpec) {
 		*out = (*in).DeepCopy()
 	}
 	in.HTTP.DeepCopyInto(&out.HTTP)
+	if in.RUM != nil {
+		in, out := &in.RUM, &out.RUM
+		*out = new(RUMConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	out.ElasticsearchRef = in.ElasticsearchRef
 	out.KibanaRef = in.KibanaRef
 	in.PodTemplate.DeepCopyInto(&out.PodTemplate)
@@ -161,6 +167,13 @@ func (in *ApmServerSpec) DeepCopy() *ApmServerSpec {
 func (in *ApmServerStatus) DeepCopyInto(out *ApmServerStatus) {
 	*out = *in
 	out.DeploymentStatus = in.DeploymentStatus
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApmServerStatus.
@@ -172,3 +185,28 @@ func (in *ApmServerStatus) DeepCopy() *ApmServerStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RUMConfig) DeepCopyInto(out *RUMConfig) {
+	*out = *in
+	if in.AllowOrigins != nil {
+		in, out := &in.AllowOrigins, &out.AllowOrigins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowHeaders != nil {
+		in, out := &in.AllowHeaders, &out.AllowHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RUMConfig.
+func (in *RUMConfig) DeepCopy() *RUMConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RUMConfig)
+	in.DeepCopyInto(out)
+	return out
+}
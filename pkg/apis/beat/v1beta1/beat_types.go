@@ -89,6 +89,55 @@ type BeatSpec struct {
 
 	// RevisionHistoryLimit is the number of revisions to retain to allow rollback in the underlying DaemonSet or Deployment.
 	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// Autodiscover configures the Kubernetes autodiscover provider of the Beat, to discover the pods to collect data from.
+	// Only supported by filebeat, metricbeat and heartbeat. Providers specified in Config are appended to this one.
+	// +kubebuilder:validation:Optional
+	Autodiscover *AutodiscoverSpec `json:"autodiscover,omitempty"`
+}
+
+// AutodiscoverScope is the scope of the Kubernetes autodiscover provider.
+type AutodiscoverScope string
+
+const (
+	// AutodiscoverNodeScope discovers the pods running on the same Kubernetes node as the Beat.
+	AutodiscoverNodeScope AutodiscoverScope = "node"
+	// AutodiscoverClusterScope discovers the pods running on any node of the Kubernetes cluster.
+	AutodiscoverClusterScope AutodiscoverScope = "cluster"
+
+	// AutodiscoverNodeNameEnvVar is the environment variable holding the name of the node running the Beat, used by
+	// the autodiscover provider with the node scope.
+	AutodiscoverNodeNameEnvVar = "NODE_NAME"
+)
+
+// AutodiscoverBeatTypes are the types of Beats supporting autodiscover.
+var AutodiscoverBeatTypes = []string{"filebeat", "metricbeat", "heartbeat"}
+
+// AutodiscoverSpec holds the configuration of the Kubernetes autodiscover provider.
+// The service account of the Beat pods must be allowed to get, list and watch pods, nodes and namespaces.
+type AutodiscoverSpec struct {
+	// HintsEnabled enables hints-based autodiscover, configuring the collection from the co.elastic.* annotations of the pods.
+	// For filebeat, /var/log/containers must be mounted into the filebeat container to collect the logs of the pods without hints.
+	// +kubebuilder:validation:Optional
+	HintsEnabled bool `json:"hintsEnabled,omitempty"`
+
+	// Scope of the discovery, either node to discover the pods running on the same node as each Beat, usually deployed
+	// as a DaemonSet, or cluster to discover the pods of the whole cluster. Defaults to node.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=node;cluster
+	Scope AutodiscoverScope `json:"scope,omitempty"`
+
+	// Namespace restricts the discovery to the pods of the given namespace. Defaults to all namespaces.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// GetScope returns the scope of the discovery.
+func (a AutodiscoverSpec) GetScope() AutodiscoverScope {
+	if a.Scope == "" {
+		return AutodiscoverNodeScope
+	}
+	return a.Scope
 }
 
 type DaemonSetSpec struct {
@@ -129,6 +178,12 @@ type BeatStatus struct {
 	// +kubebuilder:validation:Optional
 	MonitoringAssociationsStatus commonv1.AssociationStatusMap `json:"monitoringAssociationStatus,omitempty"`
 
+	// Conditions holds the conditions reporting the status of the associations of the Beat, one per association type.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
 	// ObservedGeneration represents the .metadata.generation that the status is based upon.
 	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
 	// If the generation observed in status diverges from the generation in metadata, the Beats
@@ -218,7 +273,13 @@ func (b *Beat) SetAssociationStatusMap(typ commonv1.AssociationType, status comm
 	}
 }
 
+// AssociationConditions returns a pointer to the conditions reporting the status of the associations of the Beat.
+func (b *Beat) AssociationConditions() *[]metav1.Condition {
+	return &b.Status.Conditions
+}
+
 var _ commonv1.Associated = &Beat{}
+var _ commonv1.AssociationConditionsHolder = &Beat{}
 
 func (b *Beat) ElasticServiceAccount() (commonv1.ServiceAccountName, error) {
 	return "", nil
@@ -5,8 +5,14 @@
 package v1beta1
 
 import (
+	"fmt"
+	"path"
 	"regexp"
+	"slices"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -26,6 +32,7 @@ var (
 		checkSpec,
 		checkAssociations,
 		checkMonitoring,
+		checkAutodiscover,
 	}
 
 	updateChecks = []func(old, curr *Beat) field.ErrorList{
@@ -123,3 +130,51 @@ func checkAssociations(b *Beat) field.ErrorList {
 func checkMonitoring(b *Beat) field.ErrorList {
 	return validations.Validate(b, b.Spec.Version, validations.MinStackVersion)
 }
+
+func checkAutodiscover(b *Beat) field.ErrorList {
+	if b.Spec.Autodiscover == nil {
+		return nil
+	}
+	if !slices.Contains(AutodiscoverBeatTypes, b.Spec.Type) {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("autodiscover"),
+			fmt.Sprintf("autodiscover is only supported by %s", strings.Join(AutodiscoverBeatTypes, ", ")))}
+	}
+	var errs field.ErrorList
+	if ns := b.Spec.Autodiscover.Namespace; ns != "" {
+		for _, msg := range validation.IsDNS1123Label(ns) {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("autodiscover", "namespace"), ns, msg))
+		}
+	}
+	if b.Spec.Autodiscover.HintsEnabled && b.Spec.Type == "filebeat" && !mountsContainerLogs(b) {
+		errs = append(errs, field.Required(field.NewPath("spec").Child("autodiscover", "hintsEnabled"),
+			fmt.Sprintf("hints-based autodiscover of filebeat collects the container logs from %s, which must be mounted into the filebeat container", ContainerLogsPath)))
+	}
+	return errs
+}
+
+// ContainerLogsPath is the directory of the node holding the logs of the containers, read by filebeat with
+// hints-based autodiscover.
+const ContainerLogsPath = "/var/log/containers"
+
+// mountsContainerLogs returns true if the Beat container mounts the container logs directory, or one of its parents.
+func mountsContainerLogs(b *Beat) bool {
+	var podTemplate corev1.PodTemplateSpec
+	switch {
+	case b.Spec.DaemonSet != nil:
+		podTemplate = b.Spec.DaemonSet.PodTemplate
+	case b.Spec.Deployment != nil:
+		podTemplate = b.Spec.Deployment.PodTemplate
+	}
+	for _, container := range podTemplate.Spec.Containers {
+		if container.Name != b.Spec.Type {
+			continue
+		}
+		for _, mount := range container.VolumeMounts {
+			mountPath := path.Clean(mount.MountPath)
+			if mountPath == ContainerLogsPath || mountPath == "/" || strings.HasPrefix(ContainerLogsPath, mountPath+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
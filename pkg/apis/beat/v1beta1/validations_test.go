@@ -10,6 +10,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -352,3 +353,89 @@ func Test_checkMonitoring(t *testing.T) {
 		})
 	}
 }
+
+func Test_checkAutodiscover(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		spec    BeatSpec
+		wantErr bool
+	}{
+		{
+			name: "no autodiscover",
+			spec: BeatSpec{Type: "packetbeat"},
+		},
+		{
+			name: "filebeat",
+			spec: BeatSpec{Type: "filebeat", Autodiscover: &AutodiscoverSpec{}},
+		},
+		{
+			name: "filebeat with hints and container logs mounted",
+			spec: BeatSpec{
+				Type:         "filebeat",
+				Autodiscover: &AutodiscoverSpec{HintsEnabled: true},
+				DaemonSet:    &DaemonSetSpec{PodTemplate: podTemplateWithMount("filebeat", "/var/log/containers")},
+			},
+		},
+		{
+			name: "filebeat with hints and a parent of the container logs mounted",
+			spec: BeatSpec{
+				Type:         "filebeat",
+				Autodiscover: &AutodiscoverSpec{HintsEnabled: true},
+				Deployment:   &DeploymentSpec{PodTemplate: podTemplateWithMount("filebeat", "/var/log/")},
+			},
+		},
+		{
+			name:    "filebeat with hints and no container logs mounted",
+			spec:    BeatSpec{Type: "filebeat", Autodiscover: &AutodiscoverSpec{HintsEnabled: true}},
+			wantErr: true,
+		},
+		{
+			name: "filebeat with hints and container logs mounted into another container",
+			spec: BeatSpec{
+				Type:         "filebeat",
+				Autodiscover: &AutodiscoverSpec{HintsEnabled: true},
+				DaemonSet:    &DaemonSetSpec{PodTemplate: podTemplateWithMount("sidecar", "/var/log/containers")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "filebeat with hints and a sibling of the container logs mounted",
+			spec: BeatSpec{
+				Type:         "filebeat",
+				Autodiscover: &AutodiscoverSpec{HintsEnabled: true},
+				DaemonSet:    &DaemonSetSpec{PodTemplate: podTemplateWithMount("filebeat", "/var/log/containers-old")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "metricbeat with hints",
+			spec: BeatSpec{Type: "metricbeat", Autodiscover: &AutodiscoverSpec{HintsEnabled: true}},
+		},
+		{
+			name: "metricbeat restricted to a namespace",
+			spec: BeatSpec{Type: "metricbeat", Autodiscover: &AutodiscoverSpec{Scope: AutodiscoverClusterScope, Namespace: "default"}},
+		},
+		{
+			name:    "unsupported type",
+			spec:    BeatSpec{Type: "auditbeat", Autodiscover: &AutodiscoverSpec{}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid namespace",
+			spec:    BeatSpec{Type: "filebeat", Autodiscover: &AutodiscoverSpec{Namespace: "Not_A_Namespace"}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkAutodiscover(&Beat{Spec: tt.spec})
+			require.Equal(t, tt.wantErr, len(got) > 0)
+		})
+	}
+}
+
+func podTemplateWithMount(containerName, mountPath string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		Name:         containerName,
+		VolumeMounts: []corev1.VolumeMount{{Name: "logs", MountPath: mountPath}},
+	}}}}
+}
@@ -10,9 +10,25 @@ package v1beta1
 
 import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutodiscoverSpec) DeepCopyInto(out *AutodiscoverSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutodiscoverSpec.
+func (in *AutodiscoverSpec) DeepCopy() *AutodiscoverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutodiscoverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Beat) DeepCopyInto(out *Beat) {
 	*out = *in
@@ -187,6 +203,11 @@ func (in *BeatSpec) DeepCopyInto(out *BeatSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Autodiscover != nil {
+		in, out := &in.Autodiscover, &out.Autodiscover
+		*out = new(AutodiscoverSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BeatSpec.
@@ -209,6 +230,13 @@ func (in *BeatStatus) DeepCopyInto(out *BeatStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BeatStatus.
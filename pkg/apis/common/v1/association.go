@@ -116,6 +116,8 @@ const (
 	AssociationPending     AssociationStatus = "Pending"
 	AssociationEstablished AssociationStatus = "Established"
 	AssociationFailed      AssociationStatus = "Failed"
+	// AssociationPaused is the status of the associations of a resource which is not managed by the operator.
+	AssociationPaused AssociationStatus = "Paused"
 
 	// SingletonAssociationID is an `AssociationID` used for Associations for resources that can have only a single
 	// Association of each type. For example, Kibana can only have a single ES Association, so Kibana-ES Associations
@@ -147,6 +149,36 @@ type Associated interface {
 	SetAssociationStatusMap(typ AssociationType, statusMap AssociationStatusMap) error
 }
 
+// AssociationConditionsHolder is implemented by the associated resources publishing the status of their associations
+// as conditions of their status, in addition to the association status fields.
+// +kubebuilder:object:generate=false
+type AssociationConditionsHolder interface {
+	// AssociationConditions returns a pointer to the conditions of the status of the associated resource.
+	AssociationConditions() *[]metav1.Condition
+}
+
+// AssociationConditionType returns the type of the condition reporting the status of the associations of the given
+// type, for example ElasticsearchAssociation or EsMonitoringAssociation.
+func AssociationConditionType(typ AssociationType) string {
+	var sb strings.Builder
+	for _, word := range strings.Split(string(typ), "-") {
+		if word == "" {
+			continue
+		}
+		sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	sb.WriteString("Association")
+	return sb.String()
+}
+
+// ServiceAccountTokenSecretNamer is implemented by the associations letting users choose the name of the Secret holding
+// the service account token generated by the operator.
+// +kubebuilder:object:generate=false
+type ServiceAccountTokenSecretNamer interface {
+	// ServiceAccountTokenSecretName returns the name of the Secret, or an empty string to use the default one.
+	ServiceAccountTokenSecretName() string
+}
+
 // Association interface helps to manage the Spec fields involved in an association.
 // +kubebuilder:object:generate=false
 type Association interface {
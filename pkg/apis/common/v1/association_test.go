@@ -317,3 +317,19 @@ func TestAssociationConf_AuthIsConfigured(t *testing.T) {
 		})
 	}
 }
+
+func TestAssociationConditionType(t *testing.T) {
+	for _, tt := range []struct {
+		typ    AssociationType
+		wanted string
+	}{
+		{typ: ElasticsearchAssociationType, wanted: "ElasticsearchAssociation"},
+		{typ: EntAssociationType, wanted: "EntAssociation"},
+		{typ: KbMonitoringAssociationType, wanted: "KbMonitoringAssociation"},
+		{typ: BeatMonitoringAssociationType, wanted: "BeatMonitoringAssociation"},
+	} {
+		t.Run(string(tt.typ), func(t *testing.T) {
+			require.Equal(t, tt.wanted, AssociationConditionType(tt.typ))
+		})
+	}
+}
@@ -219,6 +219,18 @@ type TLSOptions struct {
 	// - `tls.crt`: The certificate (or a chain).
 	// - `tls.key`: The private key to the first certificate in the certificate chain.
 	Certificate SecretRef `json:"certificate,omitempty"`
+
+	// MinVersion is the minimum TLS protocol version accepted on the HTTP layer.
+	// Only supported by Elasticsearch, defaults to the Elasticsearch defaults.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=TLSv1.1;TLSv1.2;TLSv1.3
+	MinVersion string `json:"minVersion,omitempty"`
+
+	// CipherSuites is the list of cipher suites accepted on the HTTP layer, using their IANA names (for example
+	// TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384).
+	// Only supported by Elasticsearch, defaults to the Elasticsearch defaults.
+	// +kubebuilder:validation:Optional
+	CipherSuites []string `json:"cipherSuites,omitempty"`
 }
 
 // Enabled returns true when TLS is enabled based on this option struct.
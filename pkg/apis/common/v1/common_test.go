@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 func TestTLSOptions_Enabled(t *testing.T) {
@@ -73,6 +74,43 @@ func TestTLSOptions_Enabled(t *testing.T) {
 	}
 }
 
+func TestCheckNoElasticsearchOnlyTLSOptions(t *testing.T) {
+	path := field.NewPath("spec").Child("http", "tls")
+	tests := []struct {
+		name       string
+		tls        TLSOptions
+		wantFields []string
+	}{
+		{
+			name: "no TLS options",
+		},
+		{
+			name: "certificate only",
+			tls:  TLSOptions{Certificate: SecretRef{SecretName: "my-cert"}},
+		},
+		{
+			name:       "min version",
+			tls:        TLSOptions{MinVersion: "TLSv1.3"},
+			wantFields: []string{"spec.http.tls.minVersion"},
+		},
+		{
+			name:       "min version and cipher suites",
+			tls:        TLSOptions{MinVersion: "TLSv1.2", CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}},
+			wantFields: []string{"spec.http.tls.minVersion", "spec.http.tls.cipherSuites"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := CheckNoElasticsearchOnlyTLSOptions(path, tt.tls)
+			fields := make([]string, 0, len(errs))
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			assert.ElementsMatch(t, tt.wantFields, fields)
+		})
+	}
+}
+
 func TestHTTPConfig_Scheme(t *testing.T) {
 	type fields struct {
 		TLS TLSOptions
@@ -0,0 +1,119 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaxMaintenanceWindowDuration is the maximum duration of a time window of a maintenance window.
+const MaxMaintenanceWindowDuration = 7 * 24 * time.Hour
+
+// MaintenanceWindow restricts the disruptive operations of the operator, such as rolling restarts, to recurring time
+// windows. Outside of them, these operations are deferred while the rest of the resource is still reconciled.
+type MaintenanceWindow struct {
+	// TimeZone is the name of the IANA time zone the start times of the windows are expressed in, for example
+	// Europe/Paris. Defaults to UTC.
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Windows are the recurring time windows during which disruptive operations are allowed.
+	// +kubebuilder:validation:MinItems=1
+	Windows []TimeWindow `json:"windows"`
+}
+
+// TimeWindow is a time window recurring on some days of the week.
+type TimeWindow struct {
+	// Days are the days of the week on which the window opens. Defaults to every day.
+	// +kubebuilder:validation:Optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start is the time of the day at which the window opens, in the HH:MM format.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration is how long the window stays open once opened, at most 168h (7 days).
+	Duration metav1.Duration `json:"duration"`
+}
+
+// Weekday is a day of the week.
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
+// weekdays are the supported values of Weekday.
+var weekdays = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+func (d Weekday) isValid() bool {
+	for _, day := range weekdays {
+		if string(d) == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Location returns the time zone the start times of the windows are expressed in.
+func (m MaintenanceWindow) Location() (*time.Location, error) {
+	if m.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(m.TimeZone)
+}
+
+// Check returns true if one of the windows is open at the given time. Otherwise, it returns how long it takes for the
+// next window to open.
+func (m MaintenanceWindow) Check(now time.Time) (bool, time.Duration, error) {
+	loc, err := m.Location()
+	if err != nil {
+		return false, 0, err
+	}
+	now = now.In(loc)
+	var untilNext time.Duration
+	for _, w := range m.Windows {
+		hour, minute, err := w.startTime()
+		if err != nil {
+			return false, 0, err
+		}
+		// a window lasts at most a week, consider the ones opened during the last week and the ones opening the next week
+		for day := -8; day <= 8; day++ {
+			start := time.Date(now.Year(), now.Month(), now.Day()+day, hour, minute, 0, 0, loc)
+			if !w.opensOn(start.Weekday()) {
+				continue
+			}
+			if !now.Before(start) && now.Before(start.Add(w.Duration.Duration)) {
+				return true, 0, nil
+			}
+			if until := start.Sub(now); until > 0 && (untilNext == 0 || until < untilNext) {
+				untilNext = until
+			}
+		}
+	}
+	return false, untilNext, nil
+}
+
+// startTime parses the time of the day at which the window opens.
+func (w TimeWindow) startTime() (int, int, error) {
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid start time %q: %w", w.Start, err)
+	}
+	return start.Hour(), start.Minute(), nil
+}
+
+// opensOn returns true if the window opens on the given day of the week.
+func (w TimeWindow) opensOn(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if string(d) == day.String() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestMaintenanceWindow_Check(t *testing.T) {
+	// 2024-06-01 is a Saturday
+	nightly := TimeWindow{Start: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour}}
+	weekend := TimeWindow{Days: []Weekday{"Sunday"}, Start: "08:00", Duration: metav1.Duration{Duration: 2 * time.Hour}}
+	tests := []struct {
+		name          string
+		window        MaintenanceWindow
+		now           time.Time
+		wantOpen      bool
+		wantUntilOpen time.Duration
+	}{
+		{
+			name:     "open",
+			window:   MaintenanceWindow{Windows: []TimeWindow{nightly}},
+			now:      time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC),
+			wantOpen: true,
+		},
+		{
+			name:     "open since the day before",
+			window:   MaintenanceWindow{Windows: []TimeWindow{nightly}},
+			now:      time.Date(2024, 6, 2, 1, 59, 0, 0, time.UTC),
+			wantOpen: true,
+		},
+		{
+			name:          "closed at the end of the window",
+			window:        MaintenanceWindow{Windows: []TimeWindow{nightly}},
+			now:           time.Date(2024, 6, 2, 2, 0, 0, 0, time.UTC),
+			wantUntilOpen: 20 * time.Hour,
+		},
+		{
+			name:          "closed: next window on a given day",
+			window:        MaintenanceWindow{Windows: []TimeWindow{weekend}},
+			now:           time.Date(2024, 6, 2, 10, 30, 0, 0, time.UTC),
+			wantUntilOpen: 7*24*time.Hour - 150*time.Minute,
+		},
+		{
+			name:          "closed: the closest window opens first",
+			window:        MaintenanceWindow{Windows: []TimeWindow{nightly, weekend}},
+			now:           time.Date(2024, 6, 2, 7, 0, 0, 0, time.UTC),
+			wantUntilOpen: time.Hour,
+		},
+		{
+			name:          "time zone",
+			window:        MaintenanceWindow{TimeZone: "Europe/Paris", Windows: []TimeWindow{nightly}},
+			now:           time.Date(2024, 6, 1, 19, 0, 0, 0, time.UTC),
+			wantUntilOpen: time.Hour,
+		},
+		{
+			name:     "time zone: open",
+			window:   MaintenanceWindow{TimeZone: "Europe/Paris", Windows: []TimeWindow{nightly}},
+			now:      time.Date(2024, 6, 1, 20, 0, 0, 0, time.UTC),
+			wantOpen: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, untilOpen, err := tt.window.Check(tt.now)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOpen, open)
+			require.Equal(t, tt.wantUntilOpen, untilOpen)
+		})
+	}
+}
+
+func TestCheckMaintenanceWindow(t *testing.T) {
+	path := field.NewPath("spec").Child("maintenanceWindow")
+	valid := TimeWindow{Days: []Weekday{"Monday"}, Start: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour}}
+	tests := []struct {
+		name       string
+		window     *MaintenanceWindow
+		wantFields []string
+	}{
+		{
+			name: "no maintenance window",
+		},
+		{
+			name:   "valid",
+			window: &MaintenanceWindow{TimeZone: "America/New_York", Windows: []TimeWindow{valid}},
+		},
+		{
+			name:       "unknown time zone",
+			window:     &MaintenanceWindow{TimeZone: "Mars/Olympus_Mons", Windows: []TimeWindow{valid}},
+			wantFields: []string{"spec.maintenanceWindow.timeZone"},
+		},
+		{
+			name:       "no window",
+			window:     &MaintenanceWindow{},
+			wantFields: []string{"spec.maintenanceWindow.windows"},
+		},
+		{
+			name: "invalid windows",
+			window: &MaintenanceWindow{Windows: []TimeWindow{
+				valid,
+				{Days: []Weekday{"Monday", "Funday"}, Start: "24:00", Duration: metav1.Duration{Duration: 8 * 24 * time.Hour}},
+				{Start: "00:00"},
+			}},
+			wantFields: []string{
+				"spec.maintenanceWindow.windows[1].days[1]",
+				"spec.maintenanceWindow.windows[1].start",
+				"spec.maintenanceWindow.windows[1].duration",
+				"spec.maintenanceWindow.windows[2].duration",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := CheckMaintenanceWindow(path, tt.window)
+			fields := make([]string, 0, len(errs))
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			require.ElementsMatch(t, tt.wantFields, fields)
+		})
+	}
+}
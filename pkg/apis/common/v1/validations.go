@@ -105,6 +105,50 @@ func CheckAssociationRefs(path *field.Path, refs ...ObjectSelector) field.ErrorL
 	return nil
 }
 
+// CheckMaintenanceWindow checks that the time zone and the windows of the given maintenance window are valid.
+func CheckMaintenanceWindow(path *field.Path, window *MaintenanceWindow) field.ErrorList {
+	if window == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if _, err := window.Location(); err != nil {
+		errs = append(errs, field.Invalid(path.Child("timeZone"), window.TimeZone, fmt.Sprintf("Unknown time zone: %v", err)))
+	}
+	if len(window.Windows) == 0 {
+		errs = append(errs, field.Required(path.Child("windows"), "At least one window is required"))
+	}
+	for i, w := range window.Windows {
+		for j, day := range w.Days {
+			if !day.isValid() {
+				errs = append(errs, field.NotSupported(path.Child("windows").Index(i).Child("days").Index(j), day, weekdays))
+			}
+		}
+		if _, _, err := w.startTime(); err != nil {
+			errs = append(errs, field.Invalid(path.Child("windows").Index(i).Child("start"), w.Start, "Start must be a time of the day in the HH:MM format"))
+		}
+		if w.Duration.Duration <= 0 || w.Duration.Duration > MaxMaintenanceWindowDuration {
+			errs = append(errs, field.Invalid(
+				path.Child("windows").Index(i).Child("duration"), w.Duration.Duration.String(),
+				fmt.Sprintf("Duration must be greater than 0 and at most %s", MaxMaintenanceWindowDuration),
+			))
+		}
+	}
+	return errs
+}
+
+// CheckNoElasticsearchOnlyTLSOptions checks that the TLS options only supported by Elasticsearch are not set in the given
+// TLS options of another resource.
+func CheckNoElasticsearchOnlyTLSOptions(path *field.Path, tls TLSOptions) field.ErrorList {
+	var errs field.ErrorList
+	if tls.MinVersion != "" {
+		errs = append(errs, field.Forbidden(path.Child("minVersion"), "minVersion is only supported by Elasticsearch"))
+	}
+	if len(tls.CipherSuites) > 0 {
+		errs = append(errs, field.Forbidden(path.Child("cipherSuites"), "cipherSuites is only supported by Elasticsearch"))
+	}
+	return errs
+}
+
 func ParseVersion(ver string) (*version.Version, field.ErrorList) {
 	v, err := version.Parse(ver)
 	if err != nil {
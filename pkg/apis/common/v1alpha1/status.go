@@ -14,6 +14,9 @@ import (
 type Condition struct {
 	Type   ConditionType          `json:"type"`
 	Status corev1.ConditionStatus `json:"status"`
+	// Reason is a machine-readable explanation of the condition status, in CamelCase.
+	// +optional
+	Reason string `json:"reason,omitempty"`
 	// +optional
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 	// +optional
@@ -41,6 +44,7 @@ func (c Conditions) MergeWith(nextConditions ...Condition) Conditions {
 		if index := cp.Index(nextCondition.Type); index >= 0 {
 			currentCondition := c[index]
 			if currentCondition.Status != nextCondition.Status ||
+				currentCondition.Reason != nextCondition.Reason ||
 				currentCondition.Message != nextCondition.Message {
 				// Update condition
 				cp[index] = nextCondition
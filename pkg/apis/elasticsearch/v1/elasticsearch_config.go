@@ -28,6 +28,10 @@ const (
 	RemoteClusterClientRole NodeRole = "remote_cluster_client"
 	TransformRole           NodeRole = "transform"
 	VotingOnlyRole          NodeRole = "voting_only"
+
+	// CoordinatingOnlyRole is not an Elasticsearch role but a shortcut to declare coordinating only nodes in node.roles.
+	// The operator expands it into the configuration matching the Elasticsearch version.
+	CoordinatingOnlyRole NodeRole = "coordinating"
 )
 
 const (
@@ -68,6 +72,19 @@ func (n *Node) CanContainData() bool {
 		n.HasRole(DataContentRole)
 }
 
+// IsDedicatedFrozen returns true if the node holds the data_frozen role and no other data role.
+func (n *Node) IsDedicatedFrozen() bool {
+	if !n.IsConfiguredWithRole(DataFrozenRole) {
+		return false
+	}
+	for _, role := range []NodeRole{DataRole, DataContentRole, DataHotRole, DataWarmRole, DataColdRole} {
+		if n.IsConfiguredWithRole(role) {
+			return false
+		}
+	}
+	return true
+}
+
 // HasRole returns true if the node runs with the given role.
 func (n *Node) HasRole(role NodeRole) bool {
 	switch role {
@@ -229,6 +229,26 @@ func TestConfig_HasRole(t *testing.T) {
 	}
 }
 
+func TestNode_IsDedicatedFrozen(t *testing.T) {
+	testCases := []struct {
+		name string
+		node *Node
+		want bool
+	}{
+		{name: "default roles", node: nil, want: false},
+		{name: "data_frozen only", node: &Node{Roles: []string{"data_frozen"}}, want: true},
+		{name: "data_frozen with non-data roles", node: &Node{Roles: []string{"data_frozen", "remote_cluster_client"}}, want: true},
+		{name: "data_frozen with data_cold", node: &Node{Roles: []string{"data_cold", "data_frozen"}}, want: false},
+		{name: "data", node: &Node{Roles: []string{"data"}}, want: false},
+		{name: "no data role", node: &Node{Roles: []string{"master"}}, want: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.node.IsDedicatedFrozen())
+		})
+	}
+}
+
 func TestConfig_IsConfiguredWithRole(t *testing.T) {
 	testCases := []struct {
 		name      string
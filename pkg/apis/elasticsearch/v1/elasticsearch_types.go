@@ -6,14 +6,17 @@ package v1
 
 import (
 	"strings"
+	"time"
 
 	"github.com/blang/semver/v4"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
@@ -33,6 +36,22 @@ const (
 	DisableUpgradePredicatesAnnotation = "eck.k8s.elastic.co/disable-upgrade-predicates"
 	// DownwardNodeLabelsAnnotation holds an optional list of expected node labels to be set as annotations on the Elasticsearch Pods.
 	DownwardNodeLabelsAnnotation = "eck.k8s.elastic.co/downward-node-labels"
+	// NodeAttributesAnnotation holds an optional comma-separated list of <attribute>=<node label> pairs. Each pair sets
+	// the Elasticsearch node attribute to the value of the label of the Kubernetes node the Pod is running on, and adds
+	// the attribute to the shard allocation awareness attributes.
+	//
+	// Example:
+	//
+	//   metadata:
+	//     annotations:
+	//       eck.k8s.elastic.co/node-attributes="zone=topology.kubernetes.io/zone"
+	NodeAttributesAnnotation = "eck.k8s.elastic.co/node-attributes"
+	// ReadinessProbeWaitForShardRecoveryAnnotation can be set to "true" on the Elasticsearch resource to only report
+	// Elasticsearch Pods as ready once no shard is being recovered onto them anymore.
+	ReadinessProbeWaitForShardRecoveryAnnotation = "eck.k8s.elastic.co/readiness-probe-wait-for-shard-recovery"
+	// SkipUpgradeHealthCheckAnnotation can be set to "true" on the Elasticsearch resource to start rolling upgrades
+	// regardless of the cluster health required by the update strategy. Meant to be used in emergencies only.
+	SkipUpgradeHealthCheckAnnotation = "eck.k8s.elastic.co/skip-upgrade-health-check"
 	// SuspendAnnotation allows users to annotate the Elasticsearch resource with the names of Pods they want to suspend
 	// for debugging purposes.
 	SuspendAnnotation = "eck.k8s.elastic.co/suspend"
@@ -79,6 +98,12 @@ type ElasticsearchSpec struct {
 	// Image is the Elasticsearch Docker image to deploy.
 	Image string `json:"image,omitempty"`
 
+	// ClusterName is the name of the Elasticsearch cluster, set as cluster.name in the Elasticsearch configuration.
+	// Defaults to the name of the Elasticsearch resource. Cannot be changed after the creation of the cluster.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[^:]+$`
+	ClusterName string `json:"clusterName,omitempty"`
+
 	// HTTP holds HTTP layer settings for Elasticsearch.
 	// +kubebuilder:validation:Optional
 	HTTP commonv1.HTTPConfig `json:"http,omitempty"`
@@ -87,6 +112,10 @@ type ElasticsearchSpec struct {
 	// +kubebuilder:validation:Optional
 	Transport TransportConfig `json:"transport,omitempty"`
 
+	// Ports allows overriding the default ports used by Elasticsearch for the HTTP and transport layers.
+	// +kubebuilder:validation:Optional
+	Ports PortsConfig `json:"ports,omitempty"`
+
 	// NodeSets allow specifying groups of Elasticsearch nodes sharing the same configuration and Pod templates.
 	// +kubebuilder:validation:MinItems=1
 	NodeSets []NodeSet `json:"nodeSets"`
@@ -95,6 +124,13 @@ type ElasticsearchSpec struct {
 	// +kubebuilder:validation:Optional
 	UpdateStrategy UpdateStrategy `json:"updateStrategy,omitempty"`
 
+	// MaintenanceWindow restricts the start of the rolling upgrades and full cluster restarts of the Elasticsearch
+	// nodes to the given recurring time windows. Outside of them, the Pods to upgrade are left untouched while the rest
+	// of the cluster is still reconciled. A rolling upgrade in progress, or the replacement of unhealthy Pods, is not
+	// deferred. Upgrades are not restricted if not specified.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindow *commonv1.MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
 	// PodDisruptionBudget provides access to the default Pod disruption budget for the Elasticsearch cluster.
 	// The default budget doesn't allow any Pod to be removed in case the cluster is not green or if there is only one node of type `data` or `master`.
 	// In all other cases the default PodDisruptionBudget sets `minUnavailable` equal to the total number of nodes minus 1.
@@ -110,6 +146,12 @@ type ElasticsearchSpec struct {
 	// +kubebuilder:validation:Optional
 	SecureSettings []commonv1.SecretSource `json:"secureSettings,omitempty"`
 
+	// KeystorePassword references a Secret in the same namespace holding under the password key the password protecting
+	// the Elasticsearch keystore. It requires Elasticsearch 7.9.0 or later.
+	// Updating the password triggers a rolling restart of the Elasticsearch nodes.
+	// +kubebuilder:validation:Optional
+	KeystorePassword *commonv1.SecretRef `json:"keystorePassword,omitempty"`
+
 	// ServiceAccountName is used to check access from the current resource to a resource (for ex. a remote Elasticsearch cluster) in a different namespace.
 	// Can only be used if ECK is enforcing RBAC on references.
 	// +optional
@@ -119,12 +161,49 @@ type ElasticsearchSpec struct {
 	// +optional
 	RemoteClusters []RemoteCluster `json:"remoteClusters,omitempty"`
 
+	// SnapshotRepositories are registered in Elasticsearch by the operator once the cluster is healthy.
+	// +kubebuilder:validation:Optional
+	SnapshotRepositories []SnapshotRepository `json:"snapshotRepositories,omitempty"`
+
+	// IngestPipelines are created in Elasticsearch by the operator once the cluster is healthy.
+	// +kubebuilder:validation:Optional
+	IngestPipelines []IngestPipeline `json:"ingestPipelines,omitempty"`
+
+	// PruneIngestPipelines, when true, deletes from Elasticsearch the ingest pipelines previously created by the operator
+	// which are not declared in IngestPipelines anymore. Defaults to false.
+	// +kubebuilder:validation:Optional
+	PruneIngestPipelines bool `json:"pruneIngestPipelines,omitempty"`
+
+	// SecurityRoles are created in the native realm of Elasticsearch by the operator once the cluster is healthy.
+	// +kubebuilder:validation:Optional
+	SecurityRoles []SecurityRole `json:"securityRoles,omitempty"`
+
+	// RoleMappings are created in Elasticsearch by the operator once the cluster is healthy.
+	// +kubebuilder:validation:Optional
+	RoleMappings []RoleMapping `json:"roleMappings,omitempty"`
+
+	// PruneSecurityRoles, when true, deletes from Elasticsearch the roles and role mappings previously created by the
+	// operator which are not declared in SecurityRoles and RoleMappings anymore. Defaults to false.
+	// +kubebuilder:validation:Optional
+	PruneSecurityRoles bool `json:"pruneSecurityRoles,omitempty"`
+
+	// IndexLifecyclePolicies are created in Elasticsearch by the operator once the cluster is healthy.
+	// They are ignored if the version of Elasticsearch does not support index lifecycle management.
+	// +kubebuilder:validation:Optional
+	IndexLifecyclePolicies []IndexLifecyclePolicy `json:"indexLifecyclePolicies,omitempty"`
+
 	// VolumeClaimDeletePolicy sets the policy for handling deletion of PersistentVolumeClaims for all NodeSets.
 	// Possible values are DeleteOnScaledownOnly and DeleteOnScaledownAndClusterDeletion. Defaults to DeleteOnScaledownAndClusterDeletion.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Enum=DeleteOnScaledownOnly;DeleteOnScaledownAndClusterDeletion
 	VolumeClaimDeletePolicy VolumeClaimDeletePolicy `json:"volumeClaimDeletePolicy,omitempty"`
 
+	// VolumeClaimScaleDownRetention is how long the PersistentVolumeClaims of the Pods removed on scale down are retained
+	// before being deleted, for example "24h". They are attached again to the Pods with the same names if the NodeSets
+	// are scaled up during that period. Defaults to 0, deleting them right away.
+	// +kubebuilder:validation:Optional
+	VolumeClaimScaleDownRetention *metav1.Duration `json:"volumeClaimScaleDownRetention,omitempty"`
+
 	// Monitoring enables you to collect and ship log and monitoring data of this Elasticsearch cluster.
 	// See https://www.elastic.co/guide/en/elasticsearch/reference/current/monitor-elasticsearch-cluster.html.
 	// Metricbeat and Filebeat are deployed in the same Pod as sidecars and each one sends data to one or two different
@@ -134,6 +213,150 @@ type ElasticsearchSpec struct {
 
 	// RevisionHistoryLimit is the number of revisions to retain to allow rollback in the underlying StatefulSets.
 	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// Audit holds the audit logging settings of the Elasticsearch cluster.
+	// +kubebuilder:validation:Optional
+	Audit AuditConfig `json:"audit,omitempty"`
+
+	// LogFormat selects a built-in log4j2 configuration printing the Elasticsearch logs to the console in the given format.
+	// Cannot be combined with a custom Log4j2 configuration.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=json
+	LogFormat LogFormat `json:"logFormat,omitempty"`
+
+	// Log4j2 holds a custom log4j2 configuration replacing the default log4j2.properties file of Elasticsearch.
+	// +kubebuilder:validation:Optional
+	Log4j2 *Log4j2Config `json:"log4j2,omitempty"`
+
+	// LogSidecar injects a container shipping the Elasticsearch logs in all the Elasticsearch Pods. Elasticsearch writes
+	// its logs to files in the logs volume shared with the sidecar. It requires Elasticsearch 7.14.0 or later.
+	// The sidecar can be customized in the PodTemplate of the node sets, through a container named log-sidecar.
+	// Cannot be combined with a log format.
+	// +kubebuilder:validation:Optional
+	LogSidecar *LogSidecar `json:"logSidecar,omitempty"`
+
+	// TrustedCertificateAuthorities references a Secret in the same namespace holding PEM-encoded CA certificates under
+	// the ca.crt key, trusted by the JVM of Elasticsearch in addition to the default ones for its outbound connections,
+	// for example to S3-compatible snapshot repositories. It requires Elasticsearch 7.0.0 or later.
+	// Updating the certificates triggers a rolling restart of the Elasticsearch nodes.
+	// +kubebuilder:validation:Optional
+	TrustedCertificateAuthorities *commonv1.SecretRef `json:"trustedCertificateAuthorities,omitempty"`
+
+	// Discovery holds the configuration of the discovery of the seed hosts the Elasticsearch nodes contact to join
+	// the cluster. Defaults to the file-based seed hosts provider managed by the operator.
+	// +kubebuilder:validation:Optional
+	Discovery DiscoveryConfig `json:"discovery,omitempty"`
+
+	// OfflinePlugins installs Elasticsearch plugins from archives stored in a volume of the Pods, for environments where
+	// the Elasticsearch nodes cannot download plugins. The plugins are installed by an init container before
+	// Elasticsearch starts.
+	// +kubebuilder:validation:Optional
+	OfflinePlugins *OfflinePlugins `json:"offlinePlugins,omitempty"`
+}
+
+// LogFormat is a built-in log format of the Elasticsearch logs.
+type LogFormat string
+
+const (
+	// JSONLogFormat prints the Elasticsearch logs to the console as JSON documents.
+	JSONLogFormat LogFormat = "json"
+)
+
+// Log4j2ConfigFileName is the name of the log4j2 configuration file of Elasticsearch, also used as the key of
+// the Secret referenced in Log4j2Config.
+const Log4j2ConfigFileName = "log4j2.properties"
+
+// Log4j2Config holds a custom log4j2 configuration, either inline or from a Secret.
+type Log4j2Config struct {
+	// Config is the inline content of the log4j2.properties file.
+	// +kubebuilder:validation:Optional
+	Config string `json:"config,omitempty"`
+	// SecretName is the name of a Secret in the same namespace holding the content of the log4j2.properties file
+	// under the log4j2.properties key.
+	// +kubebuilder:validation:Optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// LogSidecarContainerName is the name of the container shipping the Elasticsearch logs.
+const LogSidecarContainerName = "log-sidecar"
+
+// LogSidecarConfigMountPath is the directory where the configuration of the log shipping sidecar is mounted.
+const LogSidecarConfigMountPath = "/etc/log-sidecar"
+
+// LogSidecar is a container shipping the Elasticsearch logs.
+type LogSidecar struct {
+	// Image is the container image of the log shipping sidecar.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// ConfigSecretName is the name of a Secret in the same namespace holding the configuration files of the sidecar,
+	// mounted in the /etc/log-sidecar directory of the sidecar container.
+	// +kubebuilder:validation:Optional
+	ConfigSecretName string `json:"configSecretName,omitempty"`
+}
+
+// OfflinePlugins are Elasticsearch plugins installed from archives stored in a volume.
+type OfflinePlugins struct {
+	// VolumeName is the name of the volume holding the plugin archives. It must be declared in the PodTemplate of all
+	// the node sets.
+	// +kubebuilder:validation:MinLength=1
+	VolumeName string `json:"volumeName"`
+	// Path is the path of the directory holding the plugin archives in the volume. Defaults to the root of the volume.
+	// +kubebuilder:validation:Optional
+	Path string `json:"path,omitempty"`
+	// Archives are the paths of the plugin archives to install, relative to the directory holding them, for example
+	// analysis-icu-8.13.0.zip. Plugins are installed in the given order.
+	// +kubebuilder:validation:MinItems=1
+	Archives []string `json:"archives"`
+}
+
+// SeedHostsProvider selects how the Elasticsearch nodes discover the seed hosts.
+type SeedHostsProvider string
+
+const (
+	// FileSeedHostsProvider discovers the master-eligible nodes listed by the operator in the unicast_hosts.txt file,
+	// in addition to the seed hosts set in the configuration.
+	FileSeedHostsProvider SeedHostsProvider = "file"
+	// SettingsSeedHostsProvider discovers the seed hosts set in the configuration only.
+	SettingsSeedHostsProvider SeedHostsProvider = "settings"
+	// CustomSeedHostsProvider discovers the seed hosts through a custom list of seed hosts providers, for example the
+	// ones of discovery plugins, in addition to the seed hosts set in the configuration.
+	CustomSeedHostsProvider SeedHostsProvider = "custom"
+)
+
+// DiscoveryConfig holds the configuration of the seed hosts discovery.
+type DiscoveryConfig struct {
+	// SeedHostsProvider selects how the nodes discover the seed hosts: file, settings, or custom. Defaults to file.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=file;settings;custom
+	SeedHostsProvider SeedHostsProvider `json:"seedHostsProvider,omitempty"`
+	// SeedHosts is a list of transport addresses of master-eligible nodes, set in discovery.seed_hosts, or
+	// discovery.zen.ping.unicast.hosts before Elasticsearch 7.0.0. Required with the settings provider.
+	// +kubebuilder:validation:Optional
+	SeedHosts []string `json:"seedHosts,omitempty"`
+	// SeedProviders is the list of seed hosts providers set in discovery.seed_providers, or
+	// discovery.zen.hosts_provider before Elasticsearch 7.0.0. Required with the custom provider, and only allowed with it.
+	// +kubebuilder:validation:Optional
+	SeedProviders []string `json:"seedProviders,omitempty"`
+}
+
+// Provider returns the seed hosts provider, which defaults to the file-based one managed by the operator.
+func (d DiscoveryConfig) Provider() SeedHostsProvider {
+	if d.SeedHostsProvider == "" {
+		return FileSeedHostsProvider
+	}
+	return d.SeedHostsProvider
+}
+
+// AuditConfig holds the audit logging settings for Elasticsearch.
+// See https://www.elastic.co/guide/en/elasticsearch/reference/current/auditing-settings.html.
+type AuditConfig struct {
+	// Enabled enables audit logging of security events on all nodes of the cluster.
+	// Audit logging requires an appropriate license.
+	Enabled bool `json:"enabled,omitempty"`
+	// Include is the list of event types to print in the audit log. Defaults to the Elasticsearch default event types.
+	Include []string `json:"include,omitempty"`
+	// Exclude is the list of event types to exclude from the audit log.
+	Exclude []string `json:"exclude,omitempty"`
 }
 
 // VolumeClaimDeletePolicy describes the delete policy for handling PersistentVolumeClaims that hold Elasticsearch data.
@@ -156,6 +379,20 @@ type TransportConfig struct {
 	TLS TransportTLSOptions `json:"tls,omitempty"`
 }
 
+// PortsConfig holds the ports used by Elasticsearch for the HTTP and transport layers.
+type PortsConfig struct {
+	// HTTP is the port used by Elasticsearch for the REST API. Defaults to 9200.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	HTTP int32 `json:"http,omitempty"`
+	// Transport is the port used by Elasticsearch for node to node communication. Defaults to 9300.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Transport int32 `json:"transport,omitempty"`
+}
+
 type TransportTLSOptions struct {
 	// OtherNameSuffix when defined will be prefixed with the Pod name and used as the common name,
 	// and the first DNSName, as well as an OtherName required by Elasticsearch in the Subject Alternative Name
@@ -191,6 +428,14 @@ type RemoteCluster struct {
 	// ElasticsearchRef is a reference to an Elasticsearch cluster running within the same k8s cluster.
 	ElasticsearchRef commonv1.LocalObjectSelector `json:"elasticsearchRef,omitempty"`
 
+	// ExternalAddress is the transport address, in the host:port format, of a remote Elasticsearch cluster running
+	// outside of this k8s cluster, for example exposed through a LoadBalancer transport Service. The connection is then
+	// established in proxy mode through this address, which requires Elasticsearch 7.7.0 or later. Cannot be used along
+	// with ElasticsearchRef.
+	// The trust between both clusters must be established through the transport TLS settings.
+	// +kubebuilder:validation:Optional
+	ExternalAddress string `json:"externalAddress,omitempty"`
+
 	// TODO: Allow the user to specify some options (transport.compress, transport.ping_schedule)
 
 }
@@ -199,6 +444,87 @@ func (r RemoteCluster) ConfigHash() string {
 	return hash.HashObject(r)
 }
 
+// IsExternal returns true if the remote cluster is reached through a user-provided address rather than referenced.
+func (r RemoteCluster) IsExternal() bool {
+	return r.ExternalAddress != ""
+}
+
+// SnapshotRepository declares a snapshot repository to register in Elasticsearch.
+type SnapshotRepository struct {
+	// Name is the name of the snapshot repository in Elasticsearch.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Type is the type of the snapshot repository, for example s3, gcs or azure.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+
+	// Settings holds the settings of the snapshot repository.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Settings *commonv1.Config `json:"settings,omitempty"`
+
+	// SecureSettings is a list of references to Kubernetes secrets containing the credentials of the snapshot repository.
+	// They are added to the Elasticsearch keystore alongside the other secure settings.
+	// +kubebuilder:validation:Optional
+	SecureSettings []commonv1.SecretSource `json:"secureSettings,omitempty"`
+}
+
+// IngestPipeline declares an ingest pipeline to create in Elasticsearch.
+type IngestPipeline struct {
+	// Name is the identifier of the ingest pipeline in Elasticsearch.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Definition is the body of the Elasticsearch request creating the ingest pipeline, including its processors.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Definition *commonv1.Config `json:"definition"`
+}
+
+// SecurityRole declares a role to create in the native realm of Elasticsearch.
+type SecurityRole struct {
+	// Name is the identifier of the role in Elasticsearch.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Definition is the body of the Elasticsearch request creating the role, including its cluster and indices
+	// privileges.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Definition *commonv1.Config `json:"definition"`
+}
+
+// RoleMapping declares a role mapping to create in Elasticsearch.
+type RoleMapping struct {
+	// Name is the identifier of the role mapping in Elasticsearch.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Definition is the body of the Elasticsearch request creating the role mapping, including its roles and rules.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Definition *commonv1.Config `json:"definition"`
+}
+
+// IndexLifecyclePolicy declares an index lifecycle policy to create in Elasticsearch.
+type IndexLifecyclePolicy struct {
+	// Name is the identifier of the index lifecycle policy in Elasticsearch.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Policy is the definition of the index lifecycle policy, including its phases.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Policy *commonv1.Config `json:"policy"`
+}
+
 // NodeCount returns the total number of nodes of the Elasticsearch cluster
 func (es ElasticsearchSpec) NodeCount() int32 {
 	count := int32(0)
@@ -215,12 +541,68 @@ func (es ElasticsearchSpec) VolumeClaimDeletePolicyOrDefault() VolumeClaimDelete
 	return es.VolumeClaimDeletePolicy
 }
 
+// VolumeClaimScaleDownRetentionOrDefault returns how long the PersistentVolumeClaims of the Pods removed on scale down
+// are retained, 0 by default.
+func (es ElasticsearchSpec) VolumeClaimScaleDownRetentionOrDefault() time.Duration {
+	if es.VolumeClaimScaleDownRetention == nil {
+		return 0
+	}
+	return es.VolumeClaimScaleDownRetention.Duration
+}
+
 // Auth contains user authentication and authorization security settings for Elasticsearch.
 type Auth struct {
 	// Roles to propagate to the Elasticsearch cluster.
 	Roles []RoleSource `json:"roles,omitempty"`
 	// FileRealm to propagate to the Elasticsearch cluster.
 	FileRealm []FileRealmSource `json:"fileRealm,omitempty"`
+	// BuiltinUserPasswords references the passwords of built-in users provided by the user instead of being generated
+	// by the operator.
+	BuiltinUserPasswords []BuiltinUserPasswordSource `json:"builtinUserPasswords,omitempty"`
+	// Anonymous enables the anonymous access to the Elasticsearch cluster with the given roles.
+	// +kubebuilder:validation:Optional
+	Anonymous *AnonymousAccess `json:"anonymous,omitempty"`
+	// Realms configures SAML and OpenID Connect authentication realms.
+	// +kubebuilder:validation:Optional
+	Realms Realms `json:"realms,omitempty"`
+}
+
+// AnonymousAccess holds the settings of the requests received without authentication credentials.
+// See https://www.elastic.co/guide/en/elasticsearch/reference/current/anonymous-access.html.
+type AnonymousAccess struct {
+	// Username is the name of the anonymous user. Defaults to _es_anonymous_user.
+	// +kubebuilder:validation:Optional
+	Username string `json:"username,omitempty"`
+	// Roles are the roles granted to the anonymous user.
+	// +kubebuilder:validation:MinItems=1
+	Roles []string `json:"roles"`
+	// AuthzException, when true, makes Elasticsearch respond with a 403 status code when the anonymous user is not
+	// allowed to perform an action, instead of a 401 status code prompting for credentials. Defaults to true.
+	// +kubebuilder:validation:Optional
+	AuthzException *bool `json:"authzException,omitempty"`
+}
+
+// BuiltinUserPassword returns the user-provided password source of the given built-in user, if any.
+func (a Auth) BuiltinUserPassword(username string) *BuiltinUserPasswordSource {
+	for i, source := range a.BuiltinUserPasswords {
+		if source.Username == username && source.SecretName != "" {
+			return &a.BuiltinUserPasswords[i]
+		}
+	}
+	return nil
+}
+
+// BuiltinUserPasswordSource references the password of a built-in user stored in a Kubernetes secret.
+type BuiltinUserPasswordSource struct {
+	// Username is the name of the built-in user.
+	// The password of the elastic user is set in the file realm, the passwords of the other built-in users are set
+	// through the Elasticsearch change password API.
+	// +kubebuilder:validation:Enum=elastic;kibana_system;logstash_system;beats_system;apm_system;remote_monitoring_user
+	Username string `json:"username"`
+	// SecretName references a Kubernetes secret in the same namespace as the Elasticsearch resource, holding the
+	// password of the user under a "password" entry.
+	// The operator applies the password but never modifies the secret, it can be rotated by updating the secret.
+	commonv1.SecretRef `json:",inline"`
 }
 
 // RoleSource references roles to create in the Elasticsearch cluster.
@@ -309,6 +691,96 @@ type NodeSet struct {
 	// Items defined here take precedence over any default claims added by the operator with the same name.
 	// +kubebuilder:validation:Optional
 	VolumeClaimTemplates []corev1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
+
+	// SpreadAcrossZones adds a default topology spread constraint to the Pods of this NodeSet, so that they are evenly
+	// distributed across the zones identified by the topology.kubernetes.io/zone node label.
+	// The default constraint is not added if topology spread constraints are defined in the PodTemplate. Defaults to false.
+	// +kubebuilder:validation:Optional
+	SpreadAcrossZones bool `json:"spreadAcrossZones,omitempty"`
+
+	// ZoneSpreadWhenUnsatisfiable defines how the default topology spread constraint added by SpreadAcrossZones deals
+	// with Pods which cannot be evenly spread across zones. With ScheduleAnyway, the Pods are still scheduled, preferably
+	// in the zones reducing the skew. With DoNotSchedule, the Pods stay Pending until they can be evenly spread, for
+	// example if the Kubernetes cluster has fewer zones than the NodeSet has Pods. Defaults to ScheduleAnyway.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=ScheduleAnyway;DoNotSchedule
+	ZoneSpreadWhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"zoneSpreadWhenUnsatisfiable,omitempty"`
+
+	// MaxUnavailable is the maximum number of Pods of this NodeSet that can be unavailable during a rolling upgrade.
+	// spec.updateStrategy.changeBudget.maxUnavailable still bounds the number of unavailable Pods of the whole cluster,
+	// including the Pods of this NodeSet. Pods holding copies of the same shards are never restarted at the same time,
+	// which may lower the actual number of concurrent restarts. Only the cluster-wide budget applies if not specified.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+
+	// JVMOptions are additional JVM options for the Elasticsearch nodes of this NodeSet, such as Java system properties.
+	// They are written one per line to a file of the jvm.options.d directory, which requires Elasticsearch 7.7.0 or later.
+	// Setting the heap size through -Xms or -Xmx disables the heap size computed by the operator. Options also set in
+	// the ES_JAVA_OPTS environment variable of the Elasticsearch container are overridden by the environment variable.
+	// +kubebuilder:validation:Optional
+	JVMOptions []string `json:"jvmOptions,omitempty"`
+
+	// SharedCacheSize is the size of the shared cache used by the nodes of this NodeSet with the data_frozen role to
+	// hold the data of partially mounted searchable snapshots. It requires Elasticsearch 7.12.0 or later.
+	// Defaults to 90% of the storage requested by the elasticsearch-data volume claim, or of the size limit of the
+	// ephemeral data volume, for dedicated frozen nodes.
+	// +kubebuilder:validation:Optional
+	SharedCacheSize *resource.Quantity `json:"sharedCacheSize,omitempty"`
+
+	// VolumeOwnership configures the group ownership of the volumes of the Pods of this NodeSet, such as the data
+	// volume. Its fields take precedence over the ones of the security context of the PodTemplate.
+	// +kubebuilder:validation:Optional
+	VolumeOwnership *VolumeOwnership `json:"volumeOwnership,omitempty"`
+
+	// EphemeralDataVolume stores the data of the Pods of this NodeSet in an emptyDir volume instead of a
+	// PersistentVolumeClaim, for nodes which do not need persistent storage, such as search nodes rebuilding their
+	// data from snapshots. The data of a node is lost whenever its Pod is restarted, including during rolling upgrades.
+	// It cannot be combined with an elasticsearch-data volume claim template or Pod template volume.
+	// +kubebuilder:validation:Optional
+	EphemeralDataVolume *EphemeralDataVolume `json:"ephemeralDataVolume,omitempty"`
+}
+
+// HasEphemeralDataVolume returns true if the data of the Pods of this NodeSet is stored in an emptyDir volume.
+func (n NodeSet) HasEphemeralDataVolume() bool {
+	return n.EphemeralDataVolume != nil
+}
+
+// VolumeOwnership configures the group ownership of the volumes of the Elasticsearch Pods.
+type VolumeOwnership struct {
+	// FSGroup is the group owning the volumes which support ownership management, also added to the groups of the
+	// container processes. Defaults to 1000 on Elasticsearch 8.0.0 and later if the operator sets the default
+	// security context.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+	// FSGroupChangePolicy defines how the ownership and permissions of the volumes are changed before being exposed
+	// inside the Pods. OnRootMismatch skips the recursive change, which can be slow on large volumes, if the root
+	// directory of the volume already has the expected ownership and permissions. Defaults to Always.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Always;OnRootMismatch
+	FSGroupChangePolicy *corev1.PodFSGroupChangePolicy `json:"fsGroupChangePolicy,omitempty"`
+	// SupplementalGroups are groups added to the container processes, in addition to their primary group and to the
+	// FSGroup.
+	// +kubebuilder:validation:Optional
+	SupplementalGroups []int64 `json:"supplementalGroups,omitempty"`
+	// FixDataVolumePermissions runs an init container as root before Elasticsearch starts, to give the ownership of the
+	// data volume to the user and group of the Elasticsearch process, and make it writable by them. It does nothing if
+	// the root directory of the volume already has the expected ownership and permissions. Intended for storage whose
+	// default permissions prevent Elasticsearch from writing to the data volume despite the FSGroup.
+	// +kubebuilder:validation:Optional
+	FixDataVolumePermissions bool `json:"fixDataVolumePermissions,omitempty"`
+}
+
+// EphemeralDataVolume configures the emptyDir data volume of the Elasticsearch Pods.
+type EphemeralDataVolume struct {
+	// SizeLimit is the total amount of local storage the data volume can use, after which the Pod is evicted.
+	// Defaults to no limit.
+	// +kubebuilder:validation:Optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+	// AcknowledgeDataLoss acknowledges that the data of the nodes is lost whenever their Pods are restarted, including
+	// during rolling upgrades. It must be set to true.
+	AcknowledgeDataLoss bool `json:"acknowledgeDataLoss"`
 }
 
 // +kubebuilder:object:generate=false
@@ -336,6 +808,50 @@ func (n NodeSet) GetESContainerTemplate() *corev1.Container {
 type UpdateStrategy struct {
 	// ChangeBudget defines the constraints to consider when applying changes to the Elasticsearch cluster.
 	ChangeBudget ChangeBudget `json:"changeBudget,omitempty"`
+
+	// RequiredHealth is the minimum health the Elasticsearch cluster must report for the operator to start a rolling
+	// upgrade. It is not considered anymore once the rolling upgrade is in progress. Defaults to green.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=green;yellow
+	RequiredHealth ElasticsearchHealth `json:"requiredHealth,omitempty"`
+
+	// RestartInPlace restarts the Elasticsearch nodes of a rolling upgrade relying on delayed allocation, so that the
+	// shards of a restarting node are not reallocated to other nodes while it is down.
+	// +kubebuilder:validation:Optional
+	RestartInPlace RestartInPlace `json:"restartInPlace,omitempty"`
+}
+
+// DefaultRestartInPlaceAllocationDelay is the default delay before the shards of a node restarted in place are
+// reallocated to other nodes.
+const DefaultRestartInPlaceAllocationDelay = 10 * time.Minute
+
+// RestartInPlace lets Elasticsearch wait for restarting nodes to come back rather than reallocating their shards.
+// It is only applied if every index has at least one replica and Elasticsearch supports the node shutdown API, the
+// default restart procedure is used otherwise.
+type RestartInPlace struct {
+	// Enabled restarts the Elasticsearch nodes in place. Defaults to false.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// AllocationDelay is how long Elasticsearch waits for a restarting node to come back before reallocating its shards
+	// to other nodes. Defaults to 10m.
+	// +kubebuilder:validation:Optional
+	AllocationDelay *metav1.Duration `json:"allocationDelay,omitempty"`
+}
+
+// GetAllocationDelay returns the allocation delay of nodes restarted in place, or its default value if not set.
+func (r RestartInPlace) GetAllocationDelay() time.Duration {
+	if r.AllocationDelay == nil {
+		return DefaultRestartInPlaceAllocationDelay
+	}
+	return r.AllocationDelay.Duration
+}
+
+// RequiredHealthOrDefault returns the minimum health required to start a rolling upgrade, green if not specified.
+func (s UpdateStrategy) RequiredHealthOrDefault() ElasticsearchHealth {
+	if s.RequiredHealth == "" {
+		return ElasticsearchGreenHealth
+	}
+	return s.RequiredHealth
 }
 
 // ChangeBudget defines the constraints to consider when applying changes to the Elasticsearch cluster.
@@ -425,6 +941,101 @@ func (es Elasticsearch) HasDownwardNodeLabels() bool {
 	return len(es.DownwardNodeLabels()) > 0
 }
 
+// NodeAttributes returns the Elasticsearch node attributes to set from node labels, indexed by attribute name.
+// Malformed entries are ignored, they are expected to be rejected by the validation webhook.
+func (es Elasticsearch) NodeAttributes() map[string]string {
+	value := strings.TrimSpace(es.Annotations[NodeAttributesAnnotation])
+	if value == "" {
+		return nil
+	}
+	attributes := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		name, nodeLabel, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || name == "" || nodeLabel == "" {
+			continue
+		}
+		attributes[name] = nodeLabel
+	}
+	return attributes
+}
+
+// NodeLabelsAsPodAnnotations returns the node labels to be copied as annotations on the Elasticsearch Pods: the
+// downward node labels, in their original order, followed by the sorted node labels only used as node attributes.
+func (es Elasticsearch) NodeLabelsAsPodAnnotations() []string {
+	nodeLabels := es.DownwardNodeLabels()
+	attributeLabels := set.Make()
+	for _, nodeLabel := range es.NodeAttributes() {
+		attributeLabels.Add(nodeLabel)
+	}
+	return append(nodeLabels, attributeLabels.Diff(set.Make(nodeLabels...)).AsSortedSlice()...)
+}
+
+// ReadinessProbeWaitsForShardRecovery returns true if the readiness probe should wait for shard recoveries targeting
+// the local node to complete before reporting the Pod as ready.
+func (es Elasticsearch) ReadinessProbeWaitsForShardRecovery() bool {
+	val, exists := es.Annotations[ReadinessProbeWaitForShardRecoveryAnnotation]
+	return exists && val == "true"
+}
+
+// IsUpgradeHealthCheckSkipped returns true if rolling upgrades can start regardless of the cluster health.
+func (es Elasticsearch) IsUpgradeHealthCheckSkipped() bool {
+	val, exists := es.Annotations[SkipUpgradeHealthCheckAnnotation]
+	return exists && val == "true"
+}
+
+// HasLog4j2Config returns true if the default log4j2 configuration of Elasticsearch is replaced, either by a built-in
+// configuration selected through the log format, or by a custom configuration.
+func (es Elasticsearch) HasLog4j2Config() bool {
+	return es.Spec.LogFormat != "" || es.Spec.Log4j2 != nil
+}
+
+// ClusterName returns the name of the Elasticsearch cluster, which defaults to the name of the Elasticsearch resource.
+func (es Elasticsearch) ClusterName() string {
+	if es.Spec.ClusterName != "" {
+		return es.Spec.ClusterName
+	}
+	return es.Name
+}
+
+// HasOfflinePlugins returns true if plugins are installed from archives stored in a volume of the Elasticsearch Pods.
+func (es Elasticsearch) HasOfflinePlugins() bool {
+	return es.Spec.OfflinePlugins != nil
+}
+
+// HasLogSidecar returns true if a log shipping sidecar is injected in the Elasticsearch Pods.
+func (es Elasticsearch) HasLogSidecar() bool {
+	return es.Spec.LogSidecar != nil
+}
+
+// HasTrustedCertificateAuthorities returns true if additional CA certificates are trusted by the JVM of Elasticsearch.
+func (es Elasticsearch) HasTrustedCertificateAuthorities() bool {
+	return es.Spec.TrustedCertificateAuthorities != nil && es.Spec.TrustedCertificateAuthorities.SecretName != ""
+}
+
+// KeystorePassword returns the reference to the Secret holding the password protecting the keystore, if any.
+func (es Elasticsearch) KeystorePassword() *commonv1.SecretRef {
+	if es.Spec.KeystorePassword == nil || es.Spec.KeystorePassword.SecretName == "" {
+		return nil
+	}
+	return es.Spec.KeystorePassword
+}
+
+// HTTPPort returns the port used by Elasticsearch for the REST API.
+func (es Elasticsearch) HTTPPort() int32 {
+	if es.Spec.Ports.HTTP != 0 {
+		return es.Spec.Ports.HTTP
+	}
+	return network.HTTPPort
+}
+
+// TransportPort returns the port used by Elasticsearch for node to node communication.
+func (es Elasticsearch) TransportPort() int32 {
+	if es.Spec.Ports.Transport != 0 {
+		return es.Spec.Ports.Transport
+	}
+	return network.TransportPort
+}
+
 // IsMarkedForDeletion returns true if the Elasticsearch is going to be deleted
 func (es Elasticsearch) IsMarkedForDeletion() bool {
 	return !es.DeletionTimestamp.IsZero()
@@ -450,8 +1061,19 @@ func (es Elasticsearch) IsAutoscalingAnnotationSet() bool {
 	return ok
 }
 
+// SecureSettings returns the secure settings of the Elasticsearch keystore, including the credentials of the snapshot
+// repositories and of the authentication realms.
 func (es Elasticsearch) SecureSettings() []commonv1.SecretSource {
-	return es.Spec.SecureSettings
+	realmsSecureSettings := es.Spec.Auth.Realms.SecureSettings()
+	if len(es.Spec.SnapshotRepositories) == 0 && len(realmsSecureSettings) == 0 {
+		return es.Spec.SecureSettings
+	}
+	secureSettings := make([]commonv1.SecretSource, 0, len(es.Spec.SecureSettings))
+	secureSettings = append(secureSettings, es.Spec.SecureSettings...)
+	for _, repository := range es.Spec.SnapshotRepositories {
+		secureSettings = append(secureSettings, repository.SecureSettings...)
+	}
+	return append(secureSettings, realmsSecureSettings...)
 }
 
 func (es Elasticsearch) SuspendedPodNames() set.StringSet {
@@ -396,3 +396,102 @@ func Test_AssociationConfs(t *testing.T) {
 	}
 	assert.Equal(t, 2, len(esMon.AssocConfs))
 }
+
+func TestElasticsearch_SecureSettings(t *testing.T) {
+	specSecret := commonv1.SecretSource{SecretName: "spec-secure-settings"}
+	repositorySecret := commonv1.SecretSource{SecretName: "repository-credentials"}
+	tests := []struct {
+		name string
+		spec ElasticsearchSpec
+		want []commonv1.SecretSource
+	}{
+		{
+			name: "no secure settings",
+			want: nil,
+		},
+		{
+			name: "secure settings from the spec only",
+			spec: ElasticsearchSpec{SecureSettings: []commonv1.SecretSource{specSecret}},
+			want: []commonv1.SecretSource{specSecret},
+		},
+		{
+			name: "secure settings from the spec and the snapshot repositories",
+			spec: ElasticsearchSpec{
+				SecureSettings: []commonv1.SecretSource{specSecret},
+				SnapshotRepositories: []SnapshotRepository{
+					{Name: "fs", Type: "fs"},
+					{Name: "s3", Type: "s3", SecureSettings: []commonv1.SecretSource{repositorySecret}},
+				},
+			},
+			want: []commonv1.SecretSource{specSecret, repositorySecret},
+		},
+		{
+			name: "secure settings from the spec and the realms",
+			spec: ElasticsearchSpec{
+				SecureSettings: []commonv1.SecretSource{specSecret},
+				Auth: Auth{Realms: Realms{
+					SAML: []SAMLRealm{{
+						Name:       "saml1",
+						Signing:    &RealmCredentials{SecretRef: commonv1.SecretRef{SecretName: "saml-signing"}, KeyPassphraseSecretRef: &commonv1.SecretRef{SecretName: "saml-signing-passphrase"}},
+						Encryption: &RealmCredentials{SecretRef: commonv1.SecretRef{SecretName: "saml-encryption"}},
+					}},
+					OIDC: []OIDCRealm{{Name: "oidc1", RP: OIDCRelyingParty{ClientSecretRef: commonv1.SecretRef{SecretName: "oidc-client"}}}},
+				}},
+			},
+			want: []commonv1.SecretSource{
+				specSecret,
+				{
+					SecretName: "saml-signing-passphrase",
+					Entries:    []commonv1.KeyToPath{{Key: "passphrase", Path: "xpack.security.authc.realms.saml.saml1.signing.secure_key_passphrase"}},
+				},
+				{
+					SecretName: "oidc-client",
+					Entries:    []commonv1.KeyToPath{{Key: "client_secret", Path: "xpack.security.authc.realms.oidc.oidc1.rp.client_secret"}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := Elasticsearch{Spec: tt.spec}
+			require.Equal(t, tt.want, es.SecureSettings())
+		})
+	}
+}
+
+func TestElasticsearch_NodeAttributes(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string]string
+		wantLabels  []string
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name: "node attributes",
+			annotations: map[string]string{
+				NodeAttributesAnnotation: "zone=topology.kubernetes.io/zone, rack=example.com/rack,malformed",
+			},
+			want:       map[string]string{"zone": "topology.kubernetes.io/zone", "rack": "example.com/rack"},
+			wantLabels: []string{"example.com/rack", "topology.kubernetes.io/zone"},
+		},
+		{
+			name: "node attributes and downward node labels",
+			annotations: map[string]string{
+				DownwardNodeLabelsAnnotation: "topology.kubernetes.io/zone,topology.kubernetes.io/region",
+				NodeAttributesAnnotation:     "zone=topology.kubernetes.io/zone,rack=example.com/rack",
+			},
+			want:       map[string]string{"zone": "topology.kubernetes.io/zone", "rack": "example.com/rack"},
+			wantLabels: []string{"topology.kubernetes.io/zone", "topology.kubernetes.io/region", "example.com/rack"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := Elasticsearch{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			require.Equal(t, tt.want, es.NodeAttributes())
+			require.Equal(t, tt.wantLabels, es.NodeLabelsAsPodAnnotations())
+		})
+	}
+}
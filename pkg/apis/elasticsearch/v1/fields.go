@@ -10,13 +10,16 @@ const (
 	DiscoveryZenMinimumMasterNodes = "discovery.zen.minimum_master_nodes"
 	ClusterInitialMasterNodes      = "cluster.initial_master_nodes"
 
-	DiscoveryZenHostsProvider = "discovery.zen.hosts_provider" // ES < 7.X
-	DiscoverySeedProviders    = "discovery.seed_providers"     // ES >= 7.X
-	DiscoverySeedHosts        = "discovery.seed_hosts"         // ES >= 7.X
+	DiscoveryZenHostsProvider    = "discovery.zen.hosts_provider"     // ES < 7.X
+	DiscoveryZenPingUnicastHosts = "discovery.zen.ping.unicast.hosts" // ES < 7.X
+	DiscoverySeedProviders       = "discovery.seed_providers"         // ES >= 7.X
+	DiscoverySeedHosts           = "discovery.seed_hosts"             // ES >= 7.X
 
 	NetworkHost        = "network.host"
 	NetworkPublishHost = "network.publish_host"
 	HTTPPublishHost    = "http.publish_host"
+	HTTPPort           = "http.port"
+	TransportPort      = "transport.port"
 
 	NodeName = "node.name"
 
@@ -37,15 +40,33 @@ const (
 	XPackSecurityEnabled                            = "xpack.security.enabled"
 	XPackSecurityHttpSslCertificate                 = "xpack.security.http.ssl.certificate"             //nolint:revive
 	XPackSecurityHttpSslCertificateAuthorities      = "xpack.security.http.ssl.certificate_authorities" //nolint:revive
+	XPackSecurityHttpSslCipherSuites                = "xpack.security.http.ssl.cipher_suites"           //nolint:revive
 	XPackSecurityHttpSslClientAuthentication        = "xpack.security.http.ssl.client_authentication"   //nolint:revive
 	XPackSecurityHttpSslEnabled                     = "xpack.security.http.ssl.enabled"                 //nolint:revive
 	XPackSecurityHttpSslKey                         = "xpack.security.http.ssl.key"                     //nolint:revive
+	XPackSecurityHttpSslSupportedProtocols          = "xpack.security.http.ssl.supported_protocols"     //nolint:revive
 	XPackSecurityTransportSslCertificate            = "xpack.security.transport.ssl.certificate"
 	XPackSecurityTransportSslCertificateAuthorities = "xpack.security.transport.ssl.certificate_authorities"
 	XPackSecurityTransportSslEnabled                = "xpack.security.transport.ssl.enabled"
 	XPackSecurityTransportSslKey                    = "xpack.security.transport.ssl.key"
 	XPackSecurityTransportSslVerificationMode       = "xpack.security.transport.ssl.verification_mode"
 
+	XPackSecurityAuditEnabled              = "xpack.security.audit.enabled"
+	XPackSecurityAuditOutputs              = "xpack.security.audit.outputs" // ES < 7.X
+	XPackSecurityAuditLogfileEventsInclude = "xpack.security.audit.logfile.events.include"
+	XPackSecurityAuditLogfileEventsExclude = "xpack.security.audit.logfile.events.exclude"
+
+	XPackSecurityAuthcAnonymousUsername       = "xpack.security.authc.anonymous.username"
+	XPackSecurityAuthcAnonymousRoles          = "xpack.security.authc.anonymous.roles"
+	XPackSecurityAuthcAnonymousAuthzException = "xpack.security.authc.anonymous.authz_exception"
+
+	XPackSecurityAuthcRealmsSAML = "xpack.security.authc.realms.saml"
+	XPackSecurityAuthcRealmsOIDC = "xpack.security.authc.realms.oidc"
+
+	XPackMLMaxMachineMemoryPercent = "xpack.ml.max_machine_memory_percent"
+
+	XPackSearchableSnapshotSharedCacheSize = "xpack.searchable.snapshot.shared_cache.size"
+
 	XPackLicenseUploadTypes = "xpack.license.upload.types" // supported >= 7.6.0 used as of 7.8.1
 )
 
@@ -30,6 +30,8 @@ const (
 	licenseSecretSuffix                          = "license"
 	defaultPodDisruptionBudget                   = "default"
 	scriptsConfigMapSuffix                       = "scripts"
+	log4j2ConfigSecretSuffix                     = "log4j2-config"
+	trustedCASecretSuffix                        = "trusted-ca"
 	legacyTransportCertsSecretSuffix             = "transport-certificates"
 	statefulSetTransportCertificatesSecretSuffix = "transport-certs"
 
@@ -58,6 +60,8 @@ var (
 		licenseSecretSuffix,
 		defaultPodDisruptionBudget,
 		scriptsConfigMapSuffix,
+		log4j2ConfigSecretSuffix,
+		trustedCASecretSuffix,
 		statefulSetTransportCertificatesSecretSuffix,
 		remoteCaNameSuffix,
 	}
@@ -169,6 +173,16 @@ func DefaultPodDisruptionBudget(esName string) string {
 	return ESNamer.Suffix(esName, defaultPodDisruptionBudget)
 }
 
+// Log4j2ConfigSecret returns the name of the Secret holding the log4j2 configuration of the Elasticsearch nodes.
+func Log4j2ConfigSecret(esName string) string {
+	return ESNamer.Suffix(esName, log4j2ConfigSecretSuffix)
+}
+
+// TrustedCASecret returns the name of the Secret holding the CA certificates trusted by the JVM of the Elasticsearch nodes.
+func TrustedCASecret(esName string) string {
+	return ESNamer.Suffix(esName, trustedCASecretSuffix)
+}
+
 func RemoteCaSecretName(esName string) string {
 	return ESNamer.Suffix(esName, remoteCaNameSuffix)
 }
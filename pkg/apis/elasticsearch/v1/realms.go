@@ -0,0 +1,207 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// SAMLMetadataSecretKey is the key of the secret holding the metadata of a SAML identity provider.
+	SAMLMetadataSecretKey = "metadata.xml"
+	// RealmKeyPassphraseSecretKey is the key of the secret holding the passphrase of an encrypted private key.
+	RealmKeyPassphraseSecretKey = "passphrase"
+	// OIDCClientSecretKey is the key of the secret holding the client secret of an OpenID Connect relying party.
+	OIDCClientSecretKey = "client_secret" //nolint:gosec
+)
+
+// Realms holds the SAML and OpenID Connect authentication realms of the Elasticsearch cluster.
+type Realms struct {
+	// SAML realms, configured under xpack.security.authc.realms.saml.
+	// +kubebuilder:validation:Optional
+	SAML []SAMLRealm `json:"saml,omitempty"`
+	// OIDC realms, configured under xpack.security.authc.realms.oidc.
+	// +kubebuilder:validation:Optional
+	OIDC []OIDCRealm `json:"oidc,omitempty"`
+}
+
+// SAMLRealm configures a SAML realm.
+// See https://www.elastic.co/guide/en/elasticsearch/reference/current/saml-realm.html.
+type SAMLRealm struct {
+	// Name of the realm. It must be unique among all the realms of the cluster.
+	// +kubebuilder:validation:MaxLength=40
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Order of the realm in the realm chain.
+	Order int32 `json:"order"`
+	// IdP describes the SAML identity provider.
+	IdP SAMLIdentityProvider `json:"idp"`
+	// SP describes Kibana as a SAML service provider.
+	SP SAMLServiceProvider `json:"sp"`
+	// Attributes maps the user properties (principal, groups, name, mail, dn) to the SAML attributes holding them.
+	// +kubebuilder:validation:Optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Signing references the certificate and key used to sign the SAML messages sent to the identity provider.
+	// +kubebuilder:validation:Optional
+	Signing *RealmCredentials `json:"signing,omitempty"`
+	// Encryption references the certificate and key used to decrypt the SAML messages sent by the identity provider.
+	// +kubebuilder:validation:Optional
+	Encryption *RealmCredentials `json:"encryption,omitempty"`
+	// Config holds additional settings of the realm, relative to xpack.security.authc.realms.saml.<name>.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Config *commonv1.Config `json:"config,omitempty"`
+}
+
+// SAMLIdentityProvider describes a SAML identity provider.
+type SAMLIdentityProvider struct {
+	// EntityID is the SAML entity ID of the identity provider.
+	EntityID string `json:"entityID"`
+	// MetadataURL is the URL serving the metadata of the identity provider.
+	// Exactly one of MetadataURL or MetadataSecretRef must be set.
+	// +kubebuilder:validation:Optional
+	MetadataURL string `json:"metadataURL,omitempty"`
+	// MetadataSecretRef references a Kubernetes secret in the same namespace as the Elasticsearch resource, holding the
+	// metadata of the identity provider under a "metadata.xml" entry. The secret is mounted in the Elasticsearch Pods.
+	// Exactly one of MetadataURL or MetadataSecretRef must be set.
+	// +kubebuilder:validation:Optional
+	MetadataSecretRef *commonv1.SecretRef `json:"metadataSecretRef,omitempty"`
+}
+
+// SAMLServiceProvider describes Kibana as a SAML service provider.
+type SAMLServiceProvider struct {
+	// EntityID is the SAML entity ID of the service provider.
+	EntityID string `json:"entityID"`
+	// ACS is the URL of the assertion consumer service of Kibana.
+	ACS string `json:"acs"`
+	// Logout is the URL of the logout service of Kibana.
+	// +kubebuilder:validation:Optional
+	Logout string `json:"logout,omitempty"`
+}
+
+// RealmCredentials references a certificate and its private key used by a realm.
+type RealmCredentials struct {
+	// SecretName references a Kubernetes secret in the same namespace as the Elasticsearch resource, holding the PEM
+	// encoded certificate and private key under the "tls.crt" and "tls.key" entries. The secret is mounted in the
+	// Elasticsearch Pods.
+	commonv1.SecretRef `json:",inline"`
+	// KeyPassphraseSecretRef references a Kubernetes secret in the same namespace as the Elasticsearch resource,
+	// holding the passphrase of an encrypted private key under a "passphrase" entry. The passphrase is added to the
+	// Elasticsearch keystore.
+	// +kubebuilder:validation:Optional
+	KeyPassphraseSecretRef *commonv1.SecretRef `json:"keyPassphraseSecretRef,omitempty"`
+}
+
+// OIDCRealm configures an OpenID Connect realm.
+// See https://www.elastic.co/guide/en/elasticsearch/reference/current/oidc-realm.html.
+type OIDCRealm struct {
+	// Name of the realm. It must be unique among all the realms of the cluster.
+	// +kubebuilder:validation:MaxLength=40
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Order of the realm in the realm chain.
+	Order int32 `json:"order"`
+	// RP describes Kibana as an OpenID Connect relying party.
+	RP OIDCRelyingParty `json:"rp"`
+	// OP describes the OpenID Connect provider.
+	OP OIDCProvider `json:"op"`
+	// Claims maps the user properties (principal, groups, name, mail, dn) to the OpenID Connect claims holding them.
+	// +kubebuilder:validation:Optional
+	Claims map[string]string `json:"claims,omitempty"`
+	// Config holds additional settings of the realm, relative to xpack.security.authc.realms.oidc.<name>.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Config *commonv1.Config `json:"config,omitempty"`
+}
+
+// OIDCRelyingParty describes Kibana as an OpenID Connect relying party.
+type OIDCRelyingParty struct {
+	// ClientID is the OAuth 2.0 client identifier of the relying party.
+	ClientID string `json:"clientID"`
+	// ClientSecretRef references a Kubernetes secret in the same namespace as the Elasticsearch resource, holding the
+	// client secret of the relying party under a "client_secret" entry. The client secret is added to the
+	// Elasticsearch keystore.
+	ClientSecretRef commonv1.SecretRef `json:"clientSecretRef"`
+	// ResponseType is the OAuth 2.0 response type of the authentication requests.
+	// +kubebuilder:validation:Enum=code;id_token;id_token token
+	ResponseType string `json:"responseType"`
+	// RedirectURI is the URL of Kibana where the OpenID Connect provider redirects the users after authentication.
+	RedirectURI string `json:"redirectURI"`
+	// PostLogoutRedirectURI is the URL of Kibana where the OpenID Connect provider redirects the users after logout.
+	// +kubebuilder:validation:Optional
+	PostLogoutRedirectURI string `json:"postLogoutRedirectURI,omitempty"`
+	// RequestedScopes are the scopes requested in addition to the openid scope.
+	// +kubebuilder:validation:Optional
+	RequestedScopes []string `json:"requestedScopes,omitempty"`
+}
+
+// OIDCProvider describes an OpenID Connect provider.
+type OIDCProvider struct {
+	// Issuer is the issuer identifier of the OpenID Connect provider.
+	Issuer string `json:"issuer"`
+	// AuthorizationEndpoint is the URL of the authorization endpoint of the OpenID Connect provider.
+	AuthorizationEndpoint string `json:"authorizationEndpoint"`
+	// TokenEndpoint is the URL of the token endpoint of the OpenID Connect provider.
+	// +kubebuilder:validation:Optional
+	TokenEndpoint string `json:"tokenEndpoint,omitempty"`
+	// UserinfoEndpoint is the URL of the user info endpoint of the OpenID Connect provider.
+	// +kubebuilder:validation:Optional
+	UserinfoEndpoint string `json:"userinfoEndpoint,omitempty"`
+	// EndSessionEndpoint is the URL of the logout endpoint of the OpenID Connect provider.
+	// +kubebuilder:validation:Optional
+	EndSessionEndpoint string `json:"endSessionEndpoint,omitempty"`
+	// JWKSetPath is the URL of the JSON Web Key Set of the OpenID Connect provider.
+	JWKSetPath string `json:"jwkSetPath"`
+}
+
+// Names returns the names of all the realms.
+func (r Realms) Names() []string {
+	names := make([]string, 0, len(r.SAML)+len(r.OIDC))
+	for _, realm := range r.SAML {
+		names = append(names, realm.Name)
+	}
+	for _, realm := range r.OIDC {
+		names = append(names, realm.Name)
+	}
+	return names
+}
+
+// Setting returns the full name of the given setting of the SAML realm.
+func (r SAMLRealm) Setting(name string) string {
+	return XPackSecurityAuthcRealmsSAML + "." + r.Name + "." + name
+}
+
+// Setting returns the full name of the given setting of the OpenID Connect realm.
+func (r OIDCRealm) Setting(name string) string {
+	return XPackSecurityAuthcRealmsOIDC + "." + r.Name + "." + name
+}
+
+// SecureSettings returns the secure settings of the realms, to be added to the Elasticsearch keystore.
+func (r Realms) SecureSettings() []commonv1.SecretSource {
+	var secureSettings []commonv1.SecretSource
+	keyPassphrase := func(realm SAMLRealm, credentials *RealmCredentials, setting string) {
+		if credentials == nil || credentials.KeyPassphraseSecretRef == nil || credentials.KeyPassphraseSecretRef.SecretName == "" {
+			return
+		}
+		secureSettings = append(secureSettings, commonv1.SecretSource{
+			SecretName: credentials.KeyPassphraseSecretRef.SecretName,
+			Entries:    []commonv1.KeyToPath{{Key: RealmKeyPassphraseSecretKey, Path: realm.Setting(setting)}},
+		})
+	}
+	for _, realm := range r.SAML {
+		keyPassphrase(realm, realm.Signing, "signing.secure_key_passphrase")
+		keyPassphrase(realm, realm.Encryption, "encryption.secure_key_passphrase")
+	}
+	for _, realm := range r.OIDC {
+		if realm.RP.ClientSecretRef.SecretName == "" {
+			continue
+		}
+		secureSettings = append(secureSettings, commonv1.SecretSource{
+			SecretName: realm.RP.ClientSecretRef.SecretName,
+			Entries:    []commonv1.KeyToPath{{Key: OIDCClientSecretKey, Path: realm.Setting("rp.client_secret")}},
+		})
+	}
+	return secureSettings
+}
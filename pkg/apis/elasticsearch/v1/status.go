@@ -66,6 +66,10 @@ type ElasticsearchStatus struct {
 
 	MonitoringAssociationsStatus commonv1.AssociationStatusMap `json:"monitoringAssociationStatus,omitempty"`
 
+	// +optional
+	// NodeSets holds the desired, current, and ready replica counts of each nodeSet, including the ones being removed.
+	NodeSets []NodeSetStatus `json:"nodeSets,omitempty"`
+
 	// +optional
 	// Conditions holds the current service state of an Elasticsearch cluster.
 	// **This API is in technical preview and may be changed or removed in a future release.**
@@ -83,6 +87,18 @@ type ElasticsearchStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// NodeSetStatus holds the replica counts of a nodeSet, as observed in the status of its StatefulSet.
+type NodeSetStatus struct {
+	// Name of the nodeSet.
+	Name string `json:"name"`
+	// DesiredReplicas is the number of nodes specified for the nodeSet, or 0 if the nodeSet is being removed.
+	DesiredReplicas int32 `json:"desiredReplicas"`
+	// CurrentReplicas is the number of Pods currently created for the nodeSet.
+	CurrentReplicas int32 `json:"currentReplicas"`
+	// ReadyReplicas is the number of Pods of the nodeSet which are ready.
+	ReadyReplicas int32 `json:"readyReplicas"`
+}
+
 // IsDegraded returns true if the current status is worse than the previous.
 func (es ElasticsearchStatus) IsDegraded(prev ElasticsearchStatus) bool {
 	return es.Health.Less(prev.Health)
@@ -107,9 +123,37 @@ func (es *Elasticsearch) SetAssociationStatusMap(typ commonv1.AssociationType, s
 
 const (
 	ElasticsearchIsReachable v1alpha1.ConditionType = "ElasticsearchIsReachable"
+	HTTPCertificatesValid    v1alpha1.ConditionType = "HTTPCertificatesValid"
 	ReconciliationComplete   v1alpha1.ConditionType = "ReconciliationComplete"
+	ReconciliationDegraded   v1alpha1.ConditionType = "ReconciliationDegraded"
+	ReconciliationPaused     v1alpha1.ConditionType = "ReconciliationPaused"
 	ResourcesAwareManagement v1alpha1.ConditionType = "ResourcesAwareManagement"
 	RunningDesiredVersion    v1alpha1.ConditionType = "RunningDesiredVersion"
+
+	// Bootstrapping is true until the nodes have formed the cluster for the first time.
+	Bootstrapping v1alpha1.ConditionType = "Bootstrapping"
+	// Upgrading is true while some nodes are running a version older than the desired one.
+	Upgrading v1alpha1.ConditionType = "Upgrading"
+	// ReconciliationError is true if the last reconciliation failed.
+	ReconciliationError v1alpha1.ConditionType = "ReconciliationError"
+	// Ready is true once the cluster is bootstrapped, healthy, and running at the desired spec.
+	Ready v1alpha1.ConditionType = "Ready"
+	// CertificatesExpiring is true if the HTTP certificate or the transport CA certificate expires within its rotation
+	// threshold.
+	CertificatesExpiring v1alpha1.ConditionType = "CertificatesExpiring"
+)
+
+// Reasons of the Bootstrapping, Upgrading, ReconciliationError and Ready conditions.
+const (
+	ClusterBootstrappingReason    = "ClusterBootstrapping"
+	ClusterBootstrappedReason     = "ClusterBootstrapped"
+	UpgradeInProgressReason       = "UpgradeInProgress"
+	DesiredVersionRunningReason   = "DesiredVersionRunning"
+	RunningVersionUnknownReason   = "RunningVersionUnknown"
+	ReconciliationFailedReason    = "ReconciliationFailed"
+	ReconciliationSucceededReason = "ReconciliationSucceeded"
+	ClusterNotHealthyReason       = "ClusterNotHealthy"
+	ClusterReadyReason            = "ClusterReady"
 )
 
 // NewNodeStatus provides details about the status of nodes which are expected to be created and added to the Elasticsearch cluster.
@@ -12,9 +12,60 @@ import (
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnonymousAccess) DeepCopyInto(out *AnonymousAccess) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AuthzException != nil {
+		in, out := &in.AuthzException, &out.AuthzException
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnonymousAccess.
+func (in *AnonymousAccess) DeepCopy() *AnonymousAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(AnonymousAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditConfig) DeepCopyInto(out *AuditConfig) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditConfig.
+func (in *AuditConfig) DeepCopy() *AuditConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Auth) DeepCopyInto(out *Auth) {
 	*out = *in
@@ -28,6 +79,17 @@ func (in *Auth) DeepCopyInto(out *Auth) {
 		*out = make([]FileRealmSource, len(*in))
 		copy(*out, *in)
 	}
+	if in.BuiltinUserPasswords != nil {
+		in, out := &in.BuiltinUserPasswords, &out.BuiltinUserPasswords
+		*out = make([]BuiltinUserPasswordSource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Anonymous != nil {
+		in, out := &in.Anonymous, &out.Anonymous
+		*out = new(AnonymousAccess)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Realms.DeepCopyInto(&out.Realms)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Auth.
@@ -72,6 +134,22 @@ func (in AutoscaledNodeSets) DeepCopy() AutoscaledNodeSets {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuiltinUserPasswordSource) DeepCopyInto(out *BuiltinUserPasswordSource) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuiltinUserPasswordSource.
+func (in *BuiltinUserPasswordSource) DeepCopy() *BuiltinUserPasswordSource {
+	if in == nil {
+		return nil
+	}
+	out := new(BuiltinUserPasswordSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChangeBudget) DeepCopyInto(out *ChangeBudget) {
 	*out = *in
@@ -117,6 +195,31 @@ func (in *ClusterSettings) DeepCopy() *ClusterSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscoveryConfig) DeepCopyInto(out *DiscoveryConfig) {
+	*out = *in
+	if in.SeedHosts != nil {
+		in, out := &in.SeedHosts, &out.SeedHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SeedProviders != nil {
+		in, out := &in.SeedProviders, &out.SeedProviders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoveryConfig.
+func (in *DiscoveryConfig) DeepCopy() *DiscoveryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DiscoveryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DownscaleOperation) DeepCopyInto(out *DownscaleOperation) {
 	*out = *in
@@ -257,6 +360,7 @@ func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 	*out = *in
 	in.HTTP.DeepCopyInto(&out.HTTP)
 	in.Transport.DeepCopyInto(&out.Transport)
+	out.Ports = in.Ports
 	if in.NodeSets != nil {
 		in, out := &in.NodeSets, &out.NodeSets
 		*out = make([]NodeSet, len(*in))
@@ -265,6 +369,11 @@ func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 		}
 	}
 	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(commonv1.MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PodDisruptionBudget != nil {
 		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
 		*out = new(commonv1.PodDisruptionBudgetTemplate)
@@ -278,17 +387,84 @@ func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.KeystorePassword != nil {
+		in, out := &in.KeystorePassword, &out.KeystorePassword
+		*out = new(commonv1.SecretRef)
+		**out = **in
+	}
 	if in.RemoteClusters != nil {
 		in, out := &in.RemoteClusters, &out.RemoteClusters
 		*out = make([]RemoteCluster, len(*in))
 		copy(*out, *in)
 	}
+	if in.SnapshotRepositories != nil {
+		in, out := &in.SnapshotRepositories, &out.SnapshotRepositories
+		*out = make([]SnapshotRepository, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IngestPipelines != nil {
+		in, out := &in.IngestPipelines, &out.IngestPipelines
+		*out = make([]IngestPipeline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecurityRoles != nil {
+		in, out := &in.SecurityRoles, &out.SecurityRoles
+		*out = make([]SecurityRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RoleMappings != nil {
+		in, out := &in.RoleMappings, &out.RoleMappings
+		*out = make([]RoleMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IndexLifecyclePolicies != nil {
+		in, out := &in.IndexLifecyclePolicies, &out.IndexLifecyclePolicies
+		*out = make([]IndexLifecyclePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeClaimScaleDownRetention != nil {
+		in, out := &in.VolumeClaimScaleDownRetention, &out.VolumeClaimScaleDownRetention
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	in.Monitoring.DeepCopyInto(&out.Monitoring)
 	if in.RevisionHistoryLimit != nil {
 		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
 		*out = new(int32)
 		**out = **in
 	}
+	in.Audit.DeepCopyInto(&out.Audit)
+	if in.Log4j2 != nil {
+		in, out := &in.Log4j2, &out.Log4j2
+		*out = new(Log4j2Config)
+		**out = **in
+	}
+	if in.LogSidecar != nil {
+		in, out := &in.LogSidecar, &out.LogSidecar
+		*out = new(LogSidecar)
+		**out = **in
+	}
+	if in.TrustedCertificateAuthorities != nil {
+		in, out := &in.TrustedCertificateAuthorities, &out.TrustedCertificateAuthorities
+		*out = new(commonv1.SecretRef)
+		**out = **in
+	}
+	in.Discovery.DeepCopyInto(&out.Discovery)
+	if in.OfflinePlugins != nil {
+		in, out := &in.OfflinePlugins, &out.OfflinePlugins
+		*out = new(OfflinePlugins)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSpec.
@@ -311,6 +487,11 @@ func (in *ElasticsearchStatus) DeepCopyInto(out *ElasticsearchStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.NodeSets != nil {
+		in, out := &in.NodeSets, &out.NodeSets
+		*out = make([]NodeSetStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(v1alpha1.Conditions, len(*in))
@@ -331,6 +512,26 @@ func (in *ElasticsearchStatus) DeepCopy() *ElasticsearchStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EphemeralDataVolume) DeepCopyInto(out *EphemeralDataVolume) {
+	*out = *in
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralDataVolume.
+func (in *EphemeralDataVolume) DeepCopy() *EphemeralDataVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(EphemeralDataVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EsMonitoringAssociation) DeepCopyInto(out *EsMonitoringAssociation) {
 	*out = *in
@@ -386,6 +587,74 @@ func (in *InProgressOperations) DeepCopy() *InProgressOperations {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexLifecyclePolicy) DeepCopyInto(out *IndexLifecyclePolicy) {
+	*out = *in
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexLifecyclePolicy.
+func (in *IndexLifecyclePolicy) DeepCopy() *IndexLifecyclePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexLifecyclePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngestPipeline) DeepCopyInto(out *IngestPipeline) {
+	*out = *in
+	if in.Definition != nil {
+		in, out := &in.Definition, &out.Definition
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngestPipeline.
+func (in *IngestPipeline) DeepCopy() *IngestPipeline {
+	if in == nil {
+		return nil
+	}
+	out := new(IngestPipeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Log4j2Config) DeepCopyInto(out *Log4j2Config) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Log4j2Config.
+func (in *Log4j2Config) DeepCopy() *Log4j2Config {
+	if in == nil {
+		return nil
+	}
+	out := new(Log4j2Config)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogSidecar) DeepCopyInto(out *LogSidecar) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogSidecar.
+func (in *LogSidecar) DeepCopy() *LogSidecar {
+	if in == nil {
+		return nil
+	}
+	out := new(LogSidecar)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NewNode) DeepCopyInto(out *NewNode) {
 	*out = *in
@@ -476,6 +745,31 @@ func (in *NodeSet) DeepCopyInto(out *NodeSet) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(int32)
+		**out = **in
+	}
+	if in.JVMOptions != nil {
+		in, out := &in.JVMOptions, &out.JVMOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SharedCacheSize != nil {
+		in, out := &in.SharedCacheSize, &out.SharedCacheSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.VolumeOwnership != nil {
+		in, out := &in.VolumeOwnership, &out.VolumeOwnership
+		*out = new(VolumeOwnership)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EphemeralDataVolume != nil {
+		in, out := &in.EphemeralDataVolume, &out.EphemeralDataVolume
+		*out = new(EphemeralDataVolume)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSet.
@@ -488,6 +782,170 @@ func (in *NodeSet) DeepCopy() *NodeSet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetStatus) DeepCopyInto(out *NodeSetStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetStatus.
+func (in *NodeSetStatus) DeepCopy() *NodeSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCProvider) DeepCopyInto(out *OIDCProvider) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCProvider.
+func (in *OIDCProvider) DeepCopy() *OIDCProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCRealm) DeepCopyInto(out *OIDCRealm) {
+	*out = *in
+	in.RP.DeepCopyInto(&out.RP)
+	out.OP = in.OP
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCRealm.
+func (in *OIDCRealm) DeepCopy() *OIDCRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCRelyingParty) DeepCopyInto(out *OIDCRelyingParty) {
+	*out = *in
+	out.ClientSecretRef = in.ClientSecretRef
+	if in.RequestedScopes != nil {
+		in, out := &in.RequestedScopes, &out.RequestedScopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCRelyingParty.
+func (in *OIDCRelyingParty) DeepCopy() *OIDCRelyingParty {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCRelyingParty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OfflinePlugins) DeepCopyInto(out *OfflinePlugins) {
+	*out = *in
+	if in.Archives != nil {
+		in, out := &in.Archives, &out.Archives
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OfflinePlugins.
+func (in *OfflinePlugins) DeepCopy() *OfflinePlugins {
+	if in == nil {
+		return nil
+	}
+	out := new(OfflinePlugins)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortsConfig) DeepCopyInto(out *PortsConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortsConfig.
+func (in *PortsConfig) DeepCopy() *PortsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PortsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RealmCredentials) DeepCopyInto(out *RealmCredentials) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	if in.KeyPassphraseSecretRef != nil {
+		in, out := &in.KeyPassphraseSecretRef, &out.KeyPassphraseSecretRef
+		*out = new(commonv1.SecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RealmCredentials.
+func (in *RealmCredentials) DeepCopy() *RealmCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(RealmCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Realms) DeepCopyInto(out *Realms) {
+	*out = *in
+	if in.SAML != nil {
+		in, out := &in.SAML, &out.SAML
+		*out = make([]SAMLRealm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = make([]OIDCRealm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Realms.
+func (in *Realms) DeepCopy() *Realms {
+	if in == nil {
+		return nil
+	}
+	out := new(Realms)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RemoteCluster) DeepCopyInto(out *RemoteCluster) {
 	*out = *in
@@ -504,6 +962,45 @@ func (in *RemoteCluster) DeepCopy() *RemoteCluster {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestartInPlace) DeepCopyInto(out *RestartInPlace) {
+	*out = *in
+	if in.AllocationDelay != nil {
+		in, out := &in.AllocationDelay, &out.AllocationDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartInPlace.
+func (in *RestartInPlace) DeepCopy() *RestartInPlace {
+	if in == nil {
+		return nil
+	}
+	out := new(RestartInPlace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleMapping) DeepCopyInto(out *RoleMapping) {
+	*out = *in
+	if in.Definition != nil {
+		in, out := &in.Definition, &out.Definition
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleMapping.
+func (in *RoleMapping) DeepCopy() *RoleMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoleSource) DeepCopyInto(out *RoleSource) {
 	*out = *in
@@ -520,6 +1017,124 @@ func (in *RoleSource) DeepCopy() *RoleSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLIdentityProvider) DeepCopyInto(out *SAMLIdentityProvider) {
+	*out = *in
+	if in.MetadataSecretRef != nil {
+		in, out := &in.MetadataSecretRef, &out.MetadataSecretRef
+		*out = new(commonv1.SecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLIdentityProvider.
+func (in *SAMLIdentityProvider) DeepCopy() *SAMLIdentityProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLIdentityProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLRealm) DeepCopyInto(out *SAMLRealm) {
+	*out = *in
+	in.IdP.DeepCopyInto(&out.IdP)
+	out.SP = in.SP
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Signing != nil {
+		in, out := &in.Signing, &out.Signing
+		*out = new(RealmCredentials)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(RealmCredentials)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLRealm.
+func (in *SAMLRealm) DeepCopy() *SAMLRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLServiceProvider) DeepCopyInto(out *SAMLServiceProvider) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLServiceProvider.
+func (in *SAMLServiceProvider) DeepCopy() *SAMLServiceProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLServiceProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityRole) DeepCopyInto(out *SecurityRole) {
+	*out = *in
+	if in.Definition != nil {
+		in, out := &in.Definition, &out.Definition
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityRole.
+func (in *SecurityRole) DeepCopy() *SecurityRole {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRepository) DeepCopyInto(out *SnapshotRepository) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = (*in).DeepCopy()
+	}
+	if in.SecureSettings != nil {
+		in, out := &in.SecureSettings, &out.SecureSettings
+		*out = make([]commonv1.SecretSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRepository.
+func (in *SnapshotRepository) DeepCopy() *SnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TransportConfig) DeepCopyInto(out *TransportConfig) {
 	*out = *in
@@ -563,6 +1178,7 @@ func (in *TransportTLSOptions) DeepCopy() *TransportTLSOptions {
 func (in *UpdateStrategy) DeepCopyInto(out *UpdateStrategy) {
 	*out = *in
 	in.ChangeBudget.DeepCopyInto(&out.ChangeBudget)
+	in.RestartInPlace.DeepCopyInto(&out.RestartInPlace)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStrategy.
@@ -645,3 +1261,33 @@ func (in *UpscaleOperation) DeepCopy() *UpscaleOperation {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeOwnership) DeepCopyInto(out *VolumeOwnership) {
+	*out = *in
+	if in.FSGroup != nil {
+		in, out := &in.FSGroup, &out.FSGroup
+		*out = new(int64)
+		**out = **in
+	}
+	if in.FSGroupChangePolicy != nil {
+		in, out := &in.FSGroupChangePolicy, &out.FSGroupChangePolicy
+		*out = new(corev1.PodFSGroupChangePolicy)
+		**out = **in
+	}
+	if in.SupplementalGroups != nil {
+		in, out := &in.SupplementalGroups, &out.SupplementalGroups
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeOwnership.
+func (in *VolumeOwnership) DeepCopy() *VolumeOwnership {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeOwnership)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -33,6 +33,7 @@ var (
 		checkNameLength,
 		checkSupportedVersion,
 		checkAssociation,
+		checkTLSOptions,
 	}
 
 	updateChecks = []func(old, curr *EnterpriseSearch) field.ErrorList{
@@ -123,3 +124,7 @@ func checkNoDowngrade(prev, curr *EnterpriseSearch) field.ErrorList {
 func checkAssociation(ent *EnterpriseSearch) field.ErrorList {
 	return commonv1.CheckAssociationRefs(field.NewPath("spec").Child("elasticsearchRef"), ent.Spec.ElasticsearchRef)
 }
+
+func checkTLSOptions(ent *EnterpriseSearch) field.ErrorList {
+	return commonv1.CheckNoElasticsearchOnlyTLSOptions(field.NewPath("spec").Child("http", "tls"), ent.Spec.HTTP.TLS)
+}
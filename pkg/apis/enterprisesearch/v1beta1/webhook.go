@@ -32,6 +32,7 @@ var (
 		checkNoUnknownFields,
 		checkNameLength,
 		checkSupportedVersion,
+		checkTLSOptions,
 	}
 
 	updateChecks = []func(old, curr *EnterpriseSearch) field.ErrorList{
@@ -118,3 +119,7 @@ func checkNoDowngrade(prev, curr *EnterpriseSearch) field.ErrorList {
 	}
 	return commonv1.CheckNoDowngrade(prev.Spec.Version, curr.Spec.Version)
 }
+
+func checkTLSOptions(ent *EnterpriseSearch) field.ErrorList {
+	return commonv1.CheckNoElasticsearchOnlyTLSOptions(field.NewPath("spec").Child("http", "tls"), ent.Spec.HTTP.TLS)
+}
@@ -6,9 +6,11 @@ package v1
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/blang/semver/v4"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -76,6 +78,19 @@ type KibanaSpec struct {
 	// ElasticsearchRef is a reference to an Elasticsearch cluster running in the same Kubernetes cluster.
 	ElasticsearchRef commonv1.ObjectSelector `json:"elasticsearchRef,omitempty"`
 
+	// ElasticsearchCredentialsSecretName is the name of a Secret, in the same namespace as Kibana, holding the credentials
+	// used by Kibana to authenticate to the Elasticsearch cluster referenced by ElasticsearchRef, instead of the user
+	// created by the operator. The Secret must contain either a `token` entry with a service account token, or
+	// `username` and `password` entries.
+	// +kubebuilder:validation:Optional
+	ElasticsearchCredentialsSecretName string `json:"elasticsearchCredentialsSecretName,omitempty"`
+
+	// ServiceAccountTokenSecretName is the name of the Secret created by the operator, in the same namespace as Kibana,
+	// to hold the Elasticsearch service account token Kibana authenticates with from version 8.0.0, instead of a name
+	// derived from the name of Kibana. The token is regenerated in place if the content of the Secret becomes invalid.
+	// +kubebuilder:validation:Optional
+	ServiceAccountTokenSecretName string `json:"serviceAccountTokenSecretName,omitempty"`
+
 	// EnterpriseSearchRef is a reference to an EnterpriseSearch running in the same Kubernetes cluster.
 	// Kibana provides the default Enterprise Search UI starting version 7.14.
 	EnterpriseSearchRef commonv1.ObjectSelector `json:"enterpriseSearchRef,omitempty"`
@@ -84,14 +99,39 @@ type KibanaSpec struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Config *commonv1.Config `json:"config,omitempty"`
 
+	// ConfigMountPath is the absolute path of the directory of the Kibana container holding the Kibana configuration,
+	// including the generated kibana.yml file and the keystore. The environment variable Kibana reads its configuration
+	// path from is set accordingly. Defaults to /usr/share/kibana/config.
+	// +kubebuilder:validation:Optional
+	ConfigMountPath string `json:"configMountPath,omitempty"`
+
 	// HTTP holds the HTTP layer configuration for Kibana.
 	HTTP commonv1.HTTPConfig `json:"http,omitempty"`
 
+	// PublicBaseURL is the URL at which Kibana is publicly available, for example behind an ingress. It is used to set
+	// server.publicBaseUrl for Kibana versions supporting it (7.10+). Not set by default.
+	// +kubebuilder:validation:Optional
+	PublicBaseURL string `json:"publicBaseUrl,omitempty"`
+
 	// PodTemplate provides customisation options (labels, annotations, affinity rules, resource requests, and so on) for the Kibana pods
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
 	PodTemplate corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
 
+	// PodDisruptionBudget provides access to the default Pod disruption budget for the Kibana Pods.
+	// The default budget sets `minAvailable` to the number of Kibana instances minus 1, which allows a single
+	// Kibana instance to be disrupted at a time, including when there is a single instance.
+	// To disable, set `PodDisruptionBudget` to the empty value (`{}` in YAML).
+	// +kubebuilder:validation:Optional
+	PodDisruptionBudget *commonv1.PodDisruptionBudgetTemplate `json:"podDisruptionBudget,omitempty"`
+
+	// MaintenanceWindow restricts the rollout of changes to the Kibana Pods to the given recurring time windows.
+	// Outside of them, the underlying Deployment is paused: changes to the Pod template are only rolled out once a
+	// window opens, while the rest of Kibana, including the number of instances, is still reconciled. A rollout in
+	// progress is not interrupted. Rollouts are not restricted if not specified.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindow *commonv1.MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
 	// RevisionHistoryLimit is the number of revisions to retain to allow rollback in the underlying Deployment.
 	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
 
@@ -109,8 +149,209 @@ type KibanaSpec struct {
 	// Elasticsearch monitoring clusters running in the same Kubernetes cluster.
 	// +kubebuilder:validation:Optional
 	Monitoring commonv1.Monitoring `json:"monitoring,omitempty"`
+
+	// Plugins is a list of Kibana plugins to install before Kibana starts. Each entry is passed to the kibana-plugin install
+	// command run by an init container, and can be a plugin name or a URL to the plugin archive, for example on an internal mirror.
+	// +kubebuilder:validation:Optional
+	Plugins []string `json:"plugins,omitempty"`
+
+	// LivenessProbe configures the liveness probe managed by the operator for the Kibana container.
+	// +kubebuilder:validation:Optional
+	LivenessProbe LivenessProbe `json:"livenessProbe,omitempty"`
+
+	// WaitForElasticsearch adds an init container to the Kibana pods that waits for the Elasticsearch cluster referenced
+	// by ElasticsearchRef to be reachable before Kibana starts.
+	// +kubebuilder:validation:Optional
+	WaitForElasticsearch WaitForElasticsearch `json:"waitForElasticsearch,omitempty"`
+
+	// MetricsExporter attaches a sidecar container to the Kibana pods that exposes the Kibana status API as Prometheus metrics.
+	// No sidecar is deployed if not specified.
+	// +kubebuilder:validation:Optional
+	MetricsExporter *MetricsExporter `json:"metricsExporter,omitempty"`
+
+	// Server holds tunables of the Kibana HTTP server, set in the Kibana configuration.
+	// Settings which are not specified are left to the Kibana defaults.
+	// +kubebuilder:validation:Optional
+	Server *ServerSettings `json:"server,omitempty"`
+
+	// Reporting configures Kibana Reporting, which generates PDF and PNG reports with a headless Chromium browser running
+	// in the Kibana container. If specified, Kibana Reporting is configured to reach the local Kibana server, and a
+	// memory-backed volume is mounted as the shared memory of the Kibana container, which Chromium relies on.
+	// +kubebuilder:validation:Optional
+	Reporting *ReportingSettings `json:"reporting,omitempty"`
+
+	// SAMLProviders configures the generation of the Kibana SAML authentication providers matching the SAML realms of the
+	// associated Elasticsearch cluster managed by ECK. If specified, a SAML provider is generated in
+	// xpack.security.authc.providers for each of the selected SAML realms, along with a basic provider unless disabled.
+	// Supported from Kibana 7.10.0.
+	// +kubebuilder:validation:Optional
+	SAMLProviders *SAMLProvidersSettings `json:"samlProviders,omitempty"`
+
+	// ConfigReloadStrategy defines how changes to the Kibana configuration are applied. With Restart, any configuration
+	// change rolls the Kibana pods. With Reload, changes restricted to settings Kibana can reload at runtime (logging)
+	// are propagated to the configuration file of the running pods without restarting them, and are applied by Kibana
+	// when it receives the SIGHUP signal sent by a config-reloader sidecar container. Defaults to Restart.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Restart;Reload
+	ConfigReloadStrategy ConfigReloadStrategy `json:"configReloadStrategy,omitempty"`
 }
 
+// ConfigReloadStrategy defines how changes to the Kibana configuration are applied.
+type ConfigReloadStrategy string
+
+const (
+	// RestartConfigReloadStrategy restarts Kibana on any configuration change.
+	RestartConfigReloadStrategy ConfigReloadStrategy = "Restart"
+	// ReloadConfigReloadStrategy does not restart Kibana on changes to settings it can reload at runtime.
+	ReloadConfigReloadStrategy ConfigReloadStrategy = "Reload"
+)
+
+// ServerSettings holds tunables of the Kibana HTTP server.
+type ServerSettings struct {
+	// MaxPayload is the maximum payload size in bytes of incoming server requests, for example to import large
+	// saved objects. It sets server.maxPayload, or server.maxPayloadBytes before Kibana 7.13.0.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxPayload *int64 `json:"maxPayload,omitempty"`
+
+	// KeepaliveTimeout is the number of milliseconds to wait for additional data before restarting the socket
+	// timeout counter. It sets server.keepaliveTimeout.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2147483647
+	KeepaliveTimeout *int64 `json:"keepaliveTimeout,omitempty"`
+
+	// SocketTimeout is the number of milliseconds to wait before closing an inactive socket. It sets server.socketTimeout.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2147483647
+	SocketTimeout *int64 `json:"socketTimeout,omitempty"`
+}
+
+// ReportingSettings holds the configuration of Kibana Reporting.
+type ReportingSettings struct {
+	// EncryptionKeySecretName is the name of a Secret, in the same namespace as Kibana, holding the key used to encrypt the
+	// reporting jobs under the `encryptionKey` entry. It sets xpack.reporting.encryptionKey instead of the key generated by
+	// the operator. The key must be at least 32 characters long, and can be rotated by updating the Secret.
+	// +kubebuilder:validation:Optional
+	EncryptionKeySecretName string `json:"encryptionKeySecretName,omitempty"`
+
+	// QueueTimeout is the number of milliseconds a reporting job is allowed to run before it is considered failed.
+	// It sets xpack.reporting.queue.timeout.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	QueueTimeout *int64 `json:"queueTimeout,omitempty"`
+
+	// CaptureMaxAttempts is the number of times a report capture is attempted before the reporting job fails.
+	// It sets xpack.reporting.capture.maxAttempts.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	CaptureMaxAttempts *int32 `json:"captureMaxAttempts,omitempty"`
+
+	// SharedMemorySize is the size limit of the memory-backed volume mounted at /dev/shm in the Kibana container.
+	// The volume counts against the memory limit of the Kibana container. Defaults to 1Gi.
+	// +kubebuilder:validation:Optional
+	SharedMemorySize *resource.Quantity `json:"sharedMemorySize,omitempty"`
+}
+
+// SAMLProvidersSettings configures the generation of the Kibana SAML authentication providers.
+type SAMLProvidersSettings struct {
+	// Realms restricts the generated SAML providers to the given SAML realms of the associated Elasticsearch cluster.
+	// Defaults to the SAML realms whose assertion consumer service URL is the SAML callback URL of the Kibana public
+	// base URL, or to all the SAML realms if the public base URL is unknown.
+	// +kubebuilder:validation:Optional
+	Realms []string `json:"realms,omitempty"`
+
+	// DisableBasicProvider disables the basic provider, which lets the users of the other realms log in with a
+	// username and a password, generated after the SAML providers.
+	// +kubebuilder:validation:Optional
+	DisableBasicProvider bool `json:"disableBasicProvider,omitempty"`
+}
+
+// DefaultReportingSharedMemorySize is the default size limit of the shared memory volume of the Kibana container.
+var DefaultReportingSharedMemorySize = resource.MustParse("1Gi")
+
+// GetSharedMemorySize returns the size limit of the shared memory volume of the Kibana container.
+func (r ReportingSettings) GetSharedMemorySize() resource.Quantity {
+	if r.SharedMemorySize == nil {
+		return DefaultReportingSharedMemorySize
+	}
+	return *r.SharedMemorySize
+}
+
+// MetricsExporter holds the configuration of the Prometheus metrics exporter sidecar container.
+type MetricsExporter struct {
+	// Image is the Docker image of the metrics exporter.
+	// The exporter is expected to read the Kibana URL from the KIBANA_URL environment variable and to serve metrics
+	// on the port specified in the METRICS_PORT environment variable. The metrics port is exposed on the Kibana HTTP
+	// Service.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// CredentialsSecretName is the name of a Secret, in the same namespace as Kibana, holding the credentials the metrics
+	// exporter authenticates to the Kibana status API with: either a token entry, or username and password entries.
+	// They are provided in the KIBANA_TOKEN, or in the KIBANA_USERNAME and KIBANA_PASSWORD environment variables.
+	// The credentials Kibana uses to connect to Elasticsearch are never provided to the exporter: the user should only
+	// be granted the monitoring privileges the exporter needs. No credentials are provided if not specified.
+	// +kubebuilder:validation:Optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+
+	// Port on which the metrics exporter serves Prometheus metrics. Defaults to 9684.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+}
+
+// DefaultMetricsExporterPort is the port the metrics exporter serves metrics on if not specified.
+const DefaultMetricsExporterPort int32 = 9684
+
+// MetricsPort returns the port the metrics exporter serves metrics on.
+func (m MetricsExporter) MetricsPort() int32 {
+	if m.Port == 0 {
+		return DefaultMetricsExporterPort
+	}
+	return m.Port
+}
+
+// LivenessProbe holds the configuration of the liveness probe managed by the operator for the Kibana container.
+type LivenessProbe struct {
+	// Enabled attaches a liveness probe to the Kibana container, checking that the Kibana status endpoint answers
+	// HTTP requests. The status endpoint is requested under server.basePath if server.rewriteBasePath is enabled.
+	// Probe thresholds can be adjusted through the liveness probe of the Kibana container in the pod template.
+	// Defaults to false.
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// WaitForElasticsearch holds the configuration of the init container waiting for Elasticsearch to be reachable.
+type WaitForElasticsearch struct {
+	// Enabled adds the init container to the Kibana pods, if Kibana is associated with an Elasticsearch cluster.
+	// Defaults to false.
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Timeout is the maximum duration to wait for Elasticsearch to be reachable, after which the init container fails
+	// and is restarted according to the restart policy of the pod. Defaults to 5m.
+	// +kubebuilder:validation:Optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// DefaultWaitForElasticsearchTimeout is the default maximum duration to wait for Elasticsearch to be reachable.
+const DefaultWaitForElasticsearchTimeout = 5 * time.Minute
+
+// GetTimeout returns the maximum duration to wait for Elasticsearch to be reachable.
+func (w WaitForElasticsearch) GetTimeout() time.Duration {
+	if w.Timeout == nil {
+		return DefaultWaitForElasticsearchTimeout
+	}
+	return w.Timeout.Duration
+}
+
+// ReconciliationPausedCondition is the type of the condition reporting that the Kibana instance is not managed by the
+// operator anymore.
+const ReconciliationPausedCondition = "ReconciliationPaused"
+
 // KibanaStatus defines the observed state of Kibana
 type KibanaStatus struct {
 	commonv1.DeploymentStatus `json:",inline"`
@@ -128,6 +369,13 @@ type KibanaStatus struct {
 	// MonitoringAssociationStatus is the status of any auto-linking to monitoring Elasticsearch clusters.
 	MonitoringAssociationStatus commonv1.AssociationStatusMap `json:"monitoringAssociationStatus,omitempty"`
 
+	// Conditions holds the conditions reporting the status of the associations of the Kibana instance, one per association type,
+	// and whether its reconciliation is paused.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
 	// ObservedGeneration is the most recent generation observed for this Kibana instance.
 	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
 	// If the generation observed in status diverges from the generation in metadata, the Kibana
@@ -153,6 +401,7 @@ var KibanaServiceAccountMinVersion = semver.MustParse("7.17.0")
 // -- associations
 
 var _ commonv1.Associated = &Kibana{}
+var _ commonv1.AssociationConditionsHolder = &Kibana{}
 
 func (k *Kibana) Associated() commonv1.Associated {
 	return k
@@ -244,6 +493,11 @@ func (k *Kibana) SetAssociationStatusMap(typ commonv1.AssociationType, status co
 	}
 }
 
+// AssociationConditions returns a pointer to the conditions reporting the status of the associations of the Kibana instance.
+func (k *Kibana) AssociationConditions() *[]metav1.Condition {
+	return &k.Status.Conditions
+}
+
 // GetObservedGeneration will return the observed generation from the Kibana status.
 func (k *Kibana) GetObservedGeneration() int64 {
 	return k.Status.ObservedGeneration
@@ -273,6 +527,11 @@ func (kbes *KibanaEsAssociation) ElasticServiceAccount() (commonv1.ServiceAccoun
 	return "", nil
 }
 
+// ServiceAccountTokenSecretName returns the user-provided name of the Secret holding the service account token.
+func (kbes *KibanaEsAssociation) ServiceAccountTokenSecretName() string {
+	return kbes.Spec.ServiceAccountTokenSecretName
+}
+
 func (kbes *KibanaEsAssociation) Associated() commonv1.Associated {
 	if kbes == nil {
 		return nil
@@ -5,16 +5,22 @@
 package v1
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"path"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/monitoring"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
@@ -36,6 +42,14 @@ var (
 		checkSupportedVersion,
 		checkMonitoring,
 		checkAssociations,
+		checkElasticsearchCredentials,
+		checkServiceAccountTokenSecretName,
+		checkServerSettings,
+		checkReportingSettings,
+		checkWaitForElasticsearch,
+		checkConfigMountPath,
+		checkMaintenanceWindow,
+		checkTLSOptions,
 	}
 
 	updateChecks = []func(old, curr *Kibana) field.ErrorList{
@@ -141,3 +155,119 @@ func checkAssociations(k *Kibana) field.ErrorList {
 	err4 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("enterpriseSearchRef"), k.Spec.EnterpriseSearchRef)
 	return append(err1, append(err2, append(err3, err4...)...)...)
 }
+
+func checkElasticsearchCredentials(k *Kibana) field.ErrorList {
+	if k.Spec.ElasticsearchCredentialsSecretName == "" || k.Spec.ElasticsearchRef.IsDefined() {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(field.NewPath("spec").Child("elasticsearchCredentialsSecretName"), k.Spec.ElasticsearchCredentialsSecretName,
+		"elasticsearchCredentialsSecretName can only be used with elasticsearchRef")}
+}
+
+func checkServiceAccountTokenSecretName(k *Kibana) field.ErrorList {
+	name := k.Spec.ServiceAccountTokenSecretName
+	if name == "" {
+		return nil
+	}
+	path := field.NewPath("spec").Child("serviceAccountTokenSecretName")
+	if !k.Spec.ElasticsearchRef.IsDefined() || k.Spec.ElasticsearchRef.IsExternal() || k.Spec.ElasticsearchCredentialsSecretName != "" {
+		return field.ErrorList{field.Invalid(path, name,
+			"serviceAccountTokenSecretName can only be used with an elasticsearchRef to a managed cluster, without elasticsearchCredentialsSecretName")}
+	}
+	var errs field.ErrorList
+	for _, msg := range validation.IsDNS1123Subdomain(name) {
+		errs = append(errs, field.Invalid(path, name, msg))
+	}
+	return errs
+}
+
+// maxServerTimeout is the maximum timeout in milliseconds supported by the Kibana server.
+const maxServerTimeout = 2147483647
+
+func checkServerSettings(k *Kibana) field.ErrorList {
+	if k.Spec.Server == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("server")
+	if v := k.Spec.Server.MaxPayload; v != nil && *v < 1 {
+		errs = append(errs, field.Invalid(path.Child("maxPayload"), *v, "must be greater than 0"))
+	}
+	if v := k.Spec.Server.KeepaliveTimeout; v != nil && (*v < 1 || *v > maxServerTimeout) {
+		errs = append(errs, field.Invalid(path.Child("keepaliveTimeout"), *v, fmt.Sprintf("must be between 1 and %d", maxServerTimeout)))
+	}
+	if v := k.Spec.Server.SocketTimeout; v != nil && (*v < 1 || *v > maxServerTimeout) {
+		errs = append(errs, field.Invalid(path.Child("socketTimeout"), *v, fmt.Sprintf("must be between 1 and %d", maxServerTimeout)))
+	}
+	return errs
+}
+
+func checkReportingSettings(k *Kibana) field.ErrorList {
+	if k.Spec.Reporting == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("reporting")
+	if v := k.Spec.Reporting.QueueTimeout; v != nil && *v < 1 {
+		errs = append(errs, field.Invalid(path.Child("queueTimeout"), *v, "must be greater than 0"))
+	}
+	if v := k.Spec.Reporting.CaptureMaxAttempts; v != nil && *v < 1 {
+		errs = append(errs, field.Invalid(path.Child("captureMaxAttempts"), *v, "must be greater than 0"))
+	}
+	if v := k.Spec.Reporting.SharedMemorySize; v != nil && v.Sign() <= 0 {
+		errs = append(errs, field.Invalid(path.Child("sharedMemorySize"), v.String(), "must be greater than 0"))
+	}
+	return errs
+}
+
+func checkWaitForElasticsearch(k *Kibana) field.ErrorList {
+	timeout := k.Spec.WaitForElasticsearch.Timeout
+	if timeout == nil || timeout.Duration > 0 {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(field.NewPath("spec").Child("waitForElasticsearch", "timeout"), timeout.Duration.String(), "must be greater than 0")}
+}
+
+func checkConfigMountPath(k *Kibana) field.ErrorList {
+	mountPath := k.Spec.ConfigMountPath
+	if mountPath == "" {
+		return nil
+	}
+	if !path.IsAbs(mountPath) || path.Clean(mountPath) == "/" {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("configMountPath"), mountPath, "must be an absolute path to a directory other than /")}
+	}
+	return nil
+}
+
+func checkMaintenanceWindow(k *Kibana) field.ErrorList {
+	return commonv1.CheckMaintenanceWindow(field.NewPath("spec").Child("maintenanceWindow"), k.Spec.MaintenanceWindow)
+}
+
+func checkTLSOptions(k *Kibana) field.ErrorList {
+	return commonv1.CheckNoElasticsearchOnlyTLSOptions(field.NewPath("spec").Child("http", "tls"), k.Spec.HTTP.TLS)
+}
+
+// maxReplicasPerElasticsearchNode is the number of Kibana instances per node of the associated Elasticsearch cluster
+// above which a warning is returned.
+const maxReplicasPerElasticsearchNode = 2
+
+// GetClusterWarnings returns informational warnings that depend on the associated Elasticsearch cluster. They do not
+// prevent the resource from being applied.
+func (k *Kibana) GetClusterWarnings(ctx context.Context, c client.Reader) []string {
+	if !k.Spec.ElasticsearchRef.IsDefined() || k.Spec.ElasticsearchRef.IsExternal() {
+		return nil
+	}
+	var es esv1.Elasticsearch
+	if err := c.Get(ctx, k.Spec.ElasticsearchRef.WithDefaultNamespace(k.Namespace).NamespacedName(), &es); err != nil {
+		// the Elasticsearch cluster may not exist yet, do not warn about it
+		return nil
+	}
+	nodeCount := es.Spec.NodeCount()
+	if nodeCount == 0 || k.Spec.Count <= nodeCount*maxReplicasPerElasticsearchNode {
+		return nil
+	}
+	return []string{fmt.Sprintf(
+		"%s: %d Kibana instances for %d Elasticsearch nodes, consider scaling Elasticsearch up or Kibana down to avoid overloading Elasticsearch",
+		field.NewPath("spec").Child("count"), k.Spec.Count, nodeCount,
+	)}
+}
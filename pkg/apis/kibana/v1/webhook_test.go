@@ -5,18 +5,24 @@
 package v1_test
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
 )
 
@@ -99,6 +105,217 @@ func TestWebhook(t *testing.T) {
 				`spec.version: Invalid value: "300.1.2": Unsupported version: version 300.1.2 is higher than the highest supported version`,
 			),
 		},
+		{
+			Name:      "elasticsearch-credentials-with-elasticsearch-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "es"}
+				k.Spec.ElasticsearchCredentialsSecretName = "kibana-es-credentials"
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "elasticsearch-credentials-without-elasticsearch-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.ElasticsearchCredentialsSecretName = "kibana-es-credentials"
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchCredentialsSecretName: Invalid value: "kibana-es-credentials": elasticsearchCredentialsSecretName can only be used with elasticsearchRef`,
+			),
+		},
+		{
+			Name:      "service-account-token-secret-name",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "es"}
+				k.Spec.ServiceAccountTokenSecretName = "kibana-token"
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "service-account-token-secret-name-without-elasticsearch-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.ServiceAccountTokenSecretName = "kibana-token"
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.serviceAccountTokenSecretName: Invalid value: "kibana-token": serviceAccountTokenSecretName can only be used with an elasticsearchRef to a managed cluster, without elasticsearchCredentialsSecretName`,
+			),
+		},
+		{
+			Name:      "service-account-token-secret-name-with-elasticsearch-credentials",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "es"}
+				k.Spec.ElasticsearchCredentialsSecretName = "kibana-es-credentials"
+				k.Spec.ServiceAccountTokenSecretName = "kibana-token"
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.serviceAccountTokenSecretName: Invalid value: "kibana-token": serviceAccountTokenSecretName can only be used with an elasticsearchRef to a managed cluster, without elasticsearchCredentialsSecretName`,
+			),
+		},
+		{
+			Name:      "service-account-token-secret-name-invalid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "es"}
+				k.Spec.ServiceAccountTokenSecretName = "Kibana_Token"
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.serviceAccountTokenSecretName: Invalid value: "Kibana_Token": a lowercase RFC 1123 subdomain`,
+			),
+		},
+		{
+			Name:      "server-settings",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Server = &kbv1.ServerSettings{
+					MaxPayload:       ptr.To[int64](10485760),
+					KeepaliveTimeout: ptr.To[int64](120000),
+					SocketTimeout:    ptr.To[int64](2147483647),
+				}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "server-settings-out-of-range",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Server = &kbv1.ServerSettings{
+					MaxPayload:       ptr.To[int64](0),
+					KeepaliveTimeout: ptr.To[int64](-1),
+					SocketTimeout:    ptr.To[int64](2147483648),
+				}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.server.maxPayload: Invalid value: 0: must be greater than 0`,
+				`spec.server.keepaliveTimeout: Invalid value: -1: must be between 1 and 2147483647`,
+				`spec.server.socketTimeout: Invalid value: 2147483648: must be between 1 and 2147483647`,
+			),
+		},
+		{
+			Name:      "reporting-settings",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Reporting = &kbv1.ReportingSettings{
+					EncryptionKeySecretName: "reporting-key",
+					QueueTimeout:            ptr.To[int64](240000),
+					CaptureMaxAttempts:      ptr.To[int32](3),
+					SharedMemorySize:        ptr.To(resource.MustParse("512Mi")),
+				}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "reporting-settings-out-of-range",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Reporting = &kbv1.ReportingSettings{
+					QueueTimeout:       ptr.To[int64](0),
+					CaptureMaxAttempts: ptr.To[int32](-1),
+					SharedMemorySize:   ptr.To(resource.MustParse("0")),
+				}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.reporting.queueTimeout: Invalid value: 0: must be greater than 0`,
+				`spec.reporting.captureMaxAttempts: Invalid value: -1: must be greater than 0`,
+				`spec.reporting.sharedMemorySize: Invalid value: "0": must be greater than 0`,
+			),
+		},
+		{
+			Name:      "server-settings-unknown-field",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.SetAnnotations(map[string]string{
+					corev1.LastAppliedConfigAnnotation: `{"metadata":{"name": "ekesn", "namespace": "default", "uid": "e7a18cfb-b017-475c-8da2-1ec941b1f285", "creationTimestamp":"2020-03-24T13:43:20Z" },"spec":{"version":"7.6.1", "server": {"maxPayload": 10485760, "host": "0.0.0.0"}}}`,
+				})
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`host: Invalid value: "host": host field found in the kubectl.kubernetes.io/last-applied-configuration annotation is unknown`,
+			),
+		},
+		{
+			Name:      "wait-for-elasticsearch",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.WaitForElasticsearch = kbv1.WaitForElasticsearch{Enabled: true, Timeout: &metav1.Duration{Duration: 10 * time.Minute}}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "wait-for-elasticsearch-invalid-timeout",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.WaitForElasticsearch = kbv1.WaitForElasticsearch{Enabled: true, Timeout: &metav1.Duration{}}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.waitForElasticsearch.timeout: Invalid value: "0s": must be greater than 0`,
+			),
+		},
+		{
+			Name:      "config-mount-path",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.ConfigMountPath = "/etc/kibana"
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "config-mount-path-relative",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.ConfigMountPath = "etc/kibana"
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.configMountPath: Invalid value: "etc/kibana": must be an absolute path to a directory other than /`,
+			),
+		},
 		{
 			Name:      "update-valid",
 			Operation: admissionv1beta1.Update,
@@ -155,6 +372,59 @@ func TestWebhook(t *testing.T) {
 			},
 			Check: test.ValidationWebhookSucceeded,
 		},
+		{
+			Name:      "update-same-version",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Version = "7.10.2"
+				return serialize(t, k)
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Version = "7.10.2"
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "update-patch-upgrade",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Version = "7.10.1"
+				return serialize(t, k)
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Version = "7.10.2"
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "version-patch-downgrade",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Version = "7.10.2"
+				return serialize(t, k)
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Version = "7.10.1"
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.version: Forbidden: Version downgrades are not supported`,
+			),
+		},
 		{
 			Name:      "named-es-ref",
 			Operation: admissionv1beta1.Create,
@@ -318,6 +588,21 @@ func TestWebhook(t *testing.T) {
 				`spec.monitoring.logs: Forbidden: Invalid association reference: serviceName or namespace can only be used in combination with name, not with secretName`,
 			),
 		},
+		{
+			Name:      "elasticsearch-only-tls-options",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.HTTP.TLS.MinVersion = "TLSv1.3"
+				k.Spec.HTTP.TLS.CipherSuites = []string{"TLS_AES_256_GCM_SHA384"}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.http.tls.minVersion: Forbidden: minVersion is only supported by Elasticsearch`,
+				`spec.http.tls.cipherSuites: Forbidden: cipherSuites is only supported by Elasticsearch`,
+			),
+		},
 	}
 
 	validator := &kbv1.Kibana{}
@@ -345,3 +630,65 @@ func serialize(t *testing.T, k *kbv1.Kibana) []byte {
 
 	return objBytes
 }
+
+func TestKibana_GetClusterWarnings(t *testing.T) {
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			NodeSets: []esv1.NodeSet{{Name: "default", Count: 2}},
+		},
+	}
+	tests := []struct {
+		name         string
+		count        int32
+		esRef        commonv1.ObjectSelector
+		wantWarnings bool
+	}{
+		{
+			name:  "no Elasticsearch reference",
+			count: 10,
+		},
+		{
+			name:  "referenced Elasticsearch does not exist",
+			count: 10,
+			esRef: commonv1.ObjectSelector{Name: "unknown"},
+		},
+		{
+			name:  "external Elasticsearch",
+			count: 10,
+			esRef: commonv1.ObjectSelector{SecretName: "external-es"},
+		},
+		{
+			name:  "below the threshold",
+			count: 2,
+			esRef: commonv1.ObjectSelector{Name: "es"},
+		},
+		{
+			name:  "at the threshold",
+			count: 4,
+			esRef: commonv1.ObjectSelector{Name: "es"},
+		},
+		{
+			name:         "above the threshold",
+			count:        5,
+			esRef:        commonv1.ObjectSelector{Name: "es"},
+			wantWarnings: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := mkKibana("")
+			kb.Namespace = "ns"
+			kb.Spec.Count = tt.count
+			kb.Spec.ElasticsearchRef = tt.esRef
+			warnings := kb.GetClusterWarnings(context.Background(), k8s.NewFakeClient(es))
+			if !tt.wantWarnings {
+				require.Empty(t, warnings)
+				return
+			}
+			require.Equal(t, []string{
+				"spec.count: 5 Kibana instances for 2 Elasticsearch nodes, consider scaling Elasticsearch up or Kibana down to avoid overloading Elasticsearch",
+			}, warnings)
+		})
+	}
+}
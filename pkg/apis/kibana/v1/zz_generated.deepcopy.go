@@ -10,6 +10,7 @@ package v1
 
 import (
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -161,6 +162,16 @@ func (in *KibanaSpec) DeepCopyInto(out *KibanaSpec) {
 	}
 	in.HTTP.DeepCopyInto(&out.HTTP)
 	in.PodTemplate.DeepCopyInto(&out.PodTemplate)
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(commonv1.PodDisruptionBudgetTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(commonv1.MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RevisionHistoryLimit != nil {
 		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
 		*out = new(int32)
@@ -174,6 +185,33 @@ func (in *KibanaSpec) DeepCopyInto(out *KibanaSpec) {
 		}
 	}
 	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.LivenessProbe = in.LivenessProbe
+	in.WaitForElasticsearch.DeepCopyInto(&out.WaitForElasticsearch)
+	if in.MetricsExporter != nil {
+		in, out := &in.MetricsExporter, &out.MetricsExporter
+		*out = new(MetricsExporter)
+		**out = **in
+	}
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = new(ServerSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reporting != nil {
+		in, out := &in.Reporting, &out.Reporting
+		*out = new(ReportingSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SAMLProviders != nil {
+		in, out := &in.SAMLProviders, &out.SAMLProviders
+		*out = new(SAMLProvidersSettings)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaSpec.
@@ -197,6 +235,13 @@ func (in *KibanaStatus) DeepCopyInto(out *KibanaStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaStatus.
@@ -208,3 +253,133 @@ func (in *KibanaStatus) DeepCopy() *KibanaStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LivenessProbe) DeepCopyInto(out *LivenessProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LivenessProbe.
+func (in *LivenessProbe) DeepCopy() *LivenessProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(LivenessProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsExporter) DeepCopyInto(out *MetricsExporter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsExporter.
+func (in *MetricsExporter) DeepCopy() *MetricsExporter {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsExporter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportingSettings) DeepCopyInto(out *ReportingSettings) {
+	*out = *in
+	if in.QueueTimeout != nil {
+		in, out := &in.QueueTimeout, &out.QueueTimeout
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CaptureMaxAttempts != nil {
+		in, out := &in.CaptureMaxAttempts, &out.CaptureMaxAttempts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SharedMemorySize != nil {
+		in, out := &in.SharedMemorySize, &out.SharedMemorySize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportingSettings.
+func (in *ReportingSettings) DeepCopy() *ReportingSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportingSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLProvidersSettings) DeepCopyInto(out *SAMLProvidersSettings) {
+	*out = *in
+	if in.Realms != nil {
+		in, out := &in.Realms, &out.Realms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLProvidersSettings.
+func (in *SAMLProvidersSettings) DeepCopy() *SAMLProvidersSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLProvidersSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerSettings) DeepCopyInto(out *ServerSettings) {
+	*out = *in
+	if in.MaxPayload != nil {
+		in, out := &in.MaxPayload, &out.MaxPayload
+		*out = new(int64)
+		**out = **in
+	}
+	if in.KeepaliveTimeout != nil {
+		in, out := &in.KeepaliveTimeout, &out.KeepaliveTimeout
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SocketTimeout != nil {
+		in, out := &in.SocketTimeout, &out.SocketTimeout
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSettings.
+func (in *ServerSettings) DeepCopy() *ServerSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WaitForElasticsearch) DeepCopyInto(out *WaitForElasticsearch) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WaitForElasticsearch.
+func (in *WaitForElasticsearch) DeepCopy() *WaitForElasticsearch {
+	if in == nil {
+		return nil
+	}
+	out := new(WaitForElasticsearch)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -31,6 +31,7 @@ var (
 		checkNameLength,
 		checkSupportedVersion,
 		checkAssociation,
+		checkTLSOptions,
 	}
 )
 
@@ -95,3 +96,7 @@ func checkSupportedVersion(ems *ElasticMapsServer) field.ErrorList {
 func checkAssociation(ems *ElasticMapsServer) field.ErrorList {
 	return commonv1.CheckAssociationRefs(field.NewPath("spec").Child("elasticsearchRef"), ems.Spec.ElasticsearchRef)
 }
+
+func checkTLSOptions(ems *ElasticMapsServer) field.ErrorList {
+	return commonv1.CheckNoElasticsearchOnlyTLSOptions(field.NewPath("spec").Child("http", "tls"), ems.Spec.HTTP.TLS)
+}
@@ -39,7 +39,7 @@ const (
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	r := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, controllerName, r, params)
+	c, err := common.NewController(mgr, controllerName, r, &agentv1alpha1.Agent{}, params)
 	if err != nil {
 		return err
 	}
@@ -165,7 +165,7 @@ func (r *ReconcileAgent) Reconcile(ctx context.Context, request reconcile.Reques
 	result, err := results.Aggregate()
 	k8s.MaybeEmitErrorEvent(r.recorder, err, agent, events.EventReconciliationError, "Reconciliation error: %v", err)
 
-	return result, err
+	return common.WithResync(ctx, agent, r.ResyncPeriod, result), err
 }
 
 func (r *ReconcileAgent) doReconcile(ctx context.Context, agent agentv1alpha1.Agent) (*reconciler.Results, agentv1alpha1.AgentStatus) {
@@ -162,7 +162,7 @@ func internalReconcile(params Params) (*reconciler.Results, agentv1alpha1.AgentS
 }
 
 func reconcileService(params Params) (*corev1.Service, error) {
-	svc := newService(params.Agent)
+	svc := newService(params.Context, params.Agent)
 
 	// setup Service only when Fleet Server is enabled
 	if !params.Agent.Spec.FleetServerEnabled {
@@ -180,7 +180,7 @@ func reconcileService(params Params) (*corev1.Service, error) {
 	return common.ReconcileService(params.Context, params.Client, svc, &params.Agent)
 }
 
-func newService(agent agentv1alpha1.Agent) *corev1.Service {
+func newService(ctx context.Context, agent agentv1alpha1.Agent) *corev1.Service {
 	svc := corev1.Service{
 		ObjectMeta: agent.Spec.HTTP.Service.ObjectMeta,
 		Spec:       agent.Spec.HTTP.Service.Spec,
@@ -197,5 +197,5 @@ func newService(agent agentv1alpha1.Agent) *corev1.Service {
 			Port:     FleetServerPort,
 		},
 	}
-	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
+	return defaults.SetServiceDefaults(ctx, &svc, labels, labels, ports)
 }
@@ -35,6 +35,10 @@ const (
 	APMServerSSLKey         = "apm-server.ssl.key"
 	APMServerSSLCertificate = "apm-server.ssl.certificate"
 
+	APMServerRUMEnabled      = "apm-server.rum.enabled"
+	APMServerRUMAllowOrigins = "apm-server.rum.allow_origins"
+	APMServerRUMAllowHeaders = "apm-server.rum.allow_headers"
+
 	ApmCfgSecretKey = "apm-server.yml" //nolint:gosec
 )
 
@@ -105,6 +109,7 @@ func newConfigFromSpec(ctx context.Context, c k8s.Client, as *apmv1.ApmServer, v
 		esConfig,
 		kibanaConfig,
 		settings.MustCanonicalConfig(tlsSettings(as)),
+		settings.MustCanonicalConfig(rumSettings(as)),
 		userSettings,
 	)
 	if err != nil {
@@ -179,3 +184,19 @@ func tlsSettings(as *apmv1.ApmServer) map[string]interface{} {
 		APMServerSSLKey:         path.Join(certificates.HTTPCertificatesSecretVolumeMountPath, certificates.KeyFileName),
 	}
 }
+
+func rumSettings(as *apmv1.ApmServer) map[string]interface{} {
+	if as.Spec.RUM == nil {
+		return nil
+	}
+	rum := map[string]interface{}{
+		APMServerRUMEnabled: as.Spec.RUM.Enabled,
+	}
+	if len(as.Spec.RUM.AllowOrigins) > 0 {
+		rum[APMServerRUMAllowOrigins] = as.Spec.RUM.AllowOrigins
+	}
+	if len(as.Spec.RUM.AllowHeaders) > 0 {
+		rum[APMServerRUMAllowHeaders] = as.Spec.RUM.AllowHeaders
+	}
+	return rum
+}
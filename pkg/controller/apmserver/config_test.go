@@ -26,6 +26,7 @@ func TestNewConfigFromSpec(t *testing.T) {
 		configOverrides map[string]interface{}
 		esAssocConf     *commonv1.AssociationConf
 		kbAssocConf     *commonv1.AssociationConf
+		rum             *apmv1.RUMConfig
 		version         version.Version
 		wantConf        map[string]interface{}
 		wantErr         bool
@@ -54,6 +55,45 @@ func TestNewConfigFromSpec(t *testing.T) {
 			},
 			version: version.MinFor(7, 0, 0),
 		},
+		{
+			name:    "with RUM enabled",
+			version: version.MinFor(8, 0, 0),
+			rum: &apmv1.RUMConfig{
+				Enabled:      true,
+				AllowOrigins: []string{"https://*.example.com", "http://localhost:8080"},
+				AllowHeaders: []string{"X-Custom-Header"},
+			},
+			wantConf: map[string]interface{}{
+				"apm-server.auth.secret_token": "${SECRET_TOKEN}",
+				"apm-server.rum.enabled":       true,
+				"apm-server.rum.allow_origins": []string{"https://*.example.com", "http://localhost:8080"},
+				"apm-server.rum.allow_headers": []string{"X-Custom-Header"},
+			},
+		},
+		{
+			name:    "with RUM disabled",
+			version: version.MinFor(8, 0, 0),
+			rum:     &apmv1.RUMConfig{},
+			wantConf: map[string]interface{}{
+				"apm-server.auth.secret_token": "${SECRET_TOKEN}",
+				"apm-server.rum.enabled":       false,
+			},
+		},
+		{
+			name:    "with RUM overridden in the config",
+			version: version.MinFor(8, 0, 0),
+			rum:     &apmv1.RUMConfig{Enabled: true, AllowOrigins: []string{"https://example.com"}},
+			configOverrides: map[string]interface{}{
+				"apm-server.rum.enabled":       false,
+				"apm-server.rum.allow_origins": []string{"https://other.example.com"},
+			},
+			wantConf: map[string]interface{}{
+				"apm-server.auth.secret_token": "${SECRET_TOKEN}",
+				"apm-server.rum.enabled":       false,
+				// lists are appended to
+				"apm-server.rum.allow_origins": []string{"https://example.com", "https://other.example.com"},
+			},
+		},
 		{
 			name: "without Elasticsearch CA cert",
 			esAssocConf: &commonv1.AssociationConf{
@@ -135,6 +175,39 @@ func TestNewConfigFromSpec(t *testing.T) {
 			},
 			version: version.MinFor(8, 0, 0),
 		},
+		{
+			name: "Kibana association not configured yet",
+			kbAssocConf: &commonv1.AssociationConf{
+				AuthSecretName: "test-kb-elastic-user",
+				AuthSecretKey:  "apm-kb-user",
+				CASecretName:   "test-kb-http-ca-public",
+				CACertProvided: true,
+			},
+			wantConf: map[string]interface{}{
+				// no Kibana configuration until the URL is known
+				"apm-server.auth.secret_token": "${SECRET_TOKEN}",
+			},
+			version: version.MinFor(8, 0, 0),
+		},
+		{
+			name: "Kibana configuration without Elasticsearch association",
+			kbAssocConf: &commonv1.AssociationConf{
+				AuthSecretName: "test-kb-elastic-user",
+				AuthSecretKey:  "apm-kb-user",
+				CASecretName:   "test-kb-http-ca-public",
+				CACertProvided: true,
+				URL:            "https://test-kb-http.default.svc:5601",
+			},
+			wantConf: map[string]interface{}{
+				"apm-server.kibana.enabled":                     true,
+				"apm-server.kibana.host":                        "https://test-kb-http.default.svc:5601",
+				"apm-server.kibana.username":                    "apm-kb-user",
+				"apm-server.kibana.password":                    "password-kb-user",
+				"apm-server.kibana.ssl.certificate_authorities": []string{"config/kibana-certs/ca.crt"},
+				"apm-server.auth.secret_token":                  "${SECRET_TOKEN}",
+			},
+			version: version.MinFor(8, 0, 0),
+		},
 		{
 			name: "Elasticsearch fully configured and Kibana configuration without CA",
 			esAssocConf: &commonv1.AssociationConf{
@@ -178,6 +251,7 @@ func TestNewConfigFromSpec(t *testing.T) {
 				},
 				Spec: apmv1.ApmServerSpec{
 					Config: &commonv1.Config{Data: tc.configOverrides},
+					RUM:    tc.rum,
 				},
 			}
 
@@ -79,7 +79,7 @@ var (
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	reconciler := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, controllerName, reconciler, params)
+	c, err := common.NewController(mgr, controllerName, reconciler, &apmv1.ApmServer{}, params)
 	if err != nil {
 		return err
 	}
@@ -205,7 +205,8 @@ func (r *ReconcileApmServer) Reconcile(ctx context.Context, request reconcile.Re
 
 	results, state := r.doReconcile(ctx, &as)
 
-	return results.WithError(r.updateStatus(ctx, state)).Aggregate()
+	result, err := results.WithError(r.updateStatus(ctx, state)).Aggregate()
+	return common.WithResync(ctx, &as, r.ResyncPeriod, result), err
 }
 
 func (r *ReconcileApmServer) doReconcile(ctx context.Context, as *apmv1.ApmServer) (*reconciler.Results, State) {
@@ -225,7 +226,7 @@ func (r *ReconcileApmServer) doReconcile(ctx context.Context, as *apmv1.ApmServe
 		return results.WithError(err), state
 	}
 
-	svc, err := common.ReconcileService(ctx, r.Client, NewService(*as), as)
+	svc, err := common.ReconcileService(ctx, r.Client, NewService(ctx, *as), as)
 	if err != nil {
 		return results.WithError(err), state
 	}
@@ -349,7 +350,7 @@ func (r *ReconcileApmServer) updateStatus(ctx context.Context, state State) erro
 }
 
 // NewService returns the service used by the APM Server.
-func NewService(as apmv1.ApmServer) *corev1.Service {
+func NewService(ctx context.Context, as apmv1.ApmServer) *corev1.Service {
 	svc := corev1.Service{
 		ObjectMeta: as.Spec.HTTP.Service.ObjectMeta,
 		Spec:       as.Spec.HTTP.Service.Spec,
@@ -366,5 +367,5 @@ func NewService(as apmv1.ApmServer) *corev1.Service {
 			Port:     HTTPPort,
 		},
 	}
-	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
+	return defaults.SetServiceDefaults(ctx, &svc, labels, labels, ports)
 }
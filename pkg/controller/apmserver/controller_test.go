@@ -209,7 +209,7 @@ func TestNewService(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			apm := mkAPMServer(tc.httpConf)
-			haveSvc := NewService(apm)
+			haveSvc := NewService(context.Background(), apm)
 			compare.JSONEqual(t, tc.wantSvc(), haveSvc)
 		})
 	}
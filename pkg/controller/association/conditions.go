@@ -0,0 +1,115 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package association
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+// conditionStatuses maps the aggregated association status to the status of the association condition. A pending
+// association may still be established later, its condition status is unknown.
+var conditionStatuses = map[commonv1.AssociationStatus]metav1.ConditionStatus{
+	commonv1.AssociationEstablished: metav1.ConditionTrue,
+	commonv1.AssociationPending:     metav1.ConditionUnknown,
+	commonv1.AssociationFailed:      metav1.ConditionFalse,
+	commonv1.AssociationPaused:      metav1.ConditionUnknown,
+}
+
+// aggregateStatus returns Failed if any of the associations failed, Paused if any of them is paused, Pending if any of
+// them is not established yet, Established otherwise.
+func aggregateStatus(statusMap commonv1.AssociationStatusMap) commonv1.AssociationStatus {
+	result := commonv1.AssociationEstablished
+	for _, status := range statusMap {
+		switch status {
+		case commonv1.AssociationFailed:
+			return commonv1.AssociationFailed
+		case commonv1.AssociationPaused:
+			result = commonv1.AssociationPaused
+		case commonv1.AssociationEstablished:
+		default:
+			if result != commonv1.AssociationPaused {
+				result = commonv1.AssociationPending
+			}
+		}
+	}
+	return result
+}
+
+// newAssociationCondition returns the condition reporting the status of the associations of the given type. The
+// reason of the condition is the aggregated association status, its message details the associations which are not
+// established, using the given messages indexed by association reference.
+func newAssociationCondition(
+	typ commonv1.AssociationType,
+	statusMap commonv1.AssociationStatusMap,
+	messages map[string]string,
+	generation int64,
+) metav1.Condition {
+	status := aggregateStatus(statusMap)
+
+	refs := make([]string, 0, len(statusMap))
+	for ref := range statusMap {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	var details []string
+	for _, ref := range refs {
+		refStatus := statusMap[ref]
+		if refStatus == commonv1.AssociationEstablished {
+			continue
+		}
+		message := messages[ref]
+		if message == "" {
+			message = fmt.Sprintf("association is %s", strings.ToLower(string(statusOrPending(refStatus))))
+		}
+		details = append(details, fmt.Sprintf("%s: %s", ref, message))
+	}
+	message := "Association established"
+	if len(details) > 0 {
+		message = strings.Join(details, "; ")
+	}
+
+	return metav1.Condition{
+		Type:               commonv1.AssociationConditionType(typ),
+		Status:             conditionStatuses[status],
+		ObservedGeneration: generation,
+		Reason:             string(status),
+		Message:            message,
+	}
+}
+
+func statusOrPending(status commonv1.AssociationStatus) commonv1.AssociationStatus {
+	if status == commonv1.AssociationUnknown {
+		return commonv1.AssociationPending
+	}
+	return status
+}
+
+// setAssociationCondition sets the condition reporting the status of the associations of the given type on the
+// associated resource, if it publishes association conditions. The condition is removed if there is no association of
+// this type. It returns true if the conditions have been updated.
+func setAssociationCondition(
+	associated commonv1.Associated,
+	typ commonv1.AssociationType,
+	statusMap commonv1.AssociationStatusMap,
+	messages map[string]string,
+) bool {
+	holder, ok := associated.(commonv1.AssociationConditionsHolder)
+	if !ok {
+		return false
+	}
+	conditions := holder.AssociationConditions()
+	if len(statusMap) == 0 {
+		return meta.RemoveStatusCondition(conditions, commonv1.AssociationConditionType(typ))
+	}
+	return meta.SetStatusCondition(conditions, newAssociationCondition(typ, statusMap, messages, associated.GetGeneration()))
+}
@@ -0,0 +1,78 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package association
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+func Test_newAssociationCondition(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		statusMap   commonv1.AssociationStatusMap
+		messages    map[string]string
+		wantStatus  metav1.ConditionStatus
+		wantReason  string
+		wantMessage string
+	}{
+		{
+			name:        "established",
+			statusMap:   commonv1.AssociationStatusMap{"ns/es": commonv1.AssociationEstablished},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  "Established",
+			wantMessage: "Association established",
+		},
+		{
+			name:        "pending without error",
+			statusMap:   commonv1.AssociationStatusMap{"ns/es": commonv1.AssociationPending},
+			wantStatus:  metav1.ConditionUnknown,
+			wantReason:  "Pending",
+			wantMessage: "ns/es: association is pending",
+		},
+		{
+			name:        "pending with error",
+			statusMap:   commonv1.AssociationStatusMap{"ns/es": commonv1.AssociationPending},
+			messages:    map[string]string{"ns/es": "secret not found"},
+			wantStatus:  metav1.ConditionUnknown,
+			wantReason:  "Pending",
+			wantMessage: "ns/es: secret not found",
+		},
+		{
+			name:        "unknown is reported as pending",
+			statusMap:   commonv1.AssociationStatusMap{"ns/es": commonv1.AssociationUnknown},
+			wantStatus:  metav1.ConditionUnknown,
+			wantReason:  "Pending",
+			wantMessage: "ns/es: association is pending",
+		},
+		{
+			name: "failed takes precedence over pending",
+			statusMap: commonv1.AssociationStatusMap{
+				"ns/es1": commonv1.AssociationEstablished,
+				"ns/es2": commonv1.AssociationPending,
+				"ns/es3": commonv1.AssociationFailed,
+			},
+			messages:    map[string]string{"ns/es3": "invalid configuration"},
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  "Failed",
+			wantMessage: "ns/es2: association is pending; ns/es3: invalid configuration",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newAssociationCondition(commonv1.EsMonitoringAssociationType, tt.statusMap, tt.messages, 3)
+			require.Equal(t, metav1.Condition{
+				Type:               "EsMonitoringAssociation",
+				Status:             tt.wantStatus,
+				ObservedGeneration: 3,
+				Reason:             tt.wantReason,
+				Message:            tt.wantMessage,
+			}, got)
+		})
+	}
+}
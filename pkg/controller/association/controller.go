@@ -33,7 +33,7 @@ func AddAssociationController(
 		recorder:        mgr.GetEventRecorderFor(controllerName),
 		Parameters:      params,
 	}
-	c, err := common.NewController(mgr, controllerName, r, params)
+	c, err := common.NewController(mgr, controllerName, r, r.AssociatedObjTemplate(), params)
 	if err != nil {
 		return err
 	}
@@ -6,6 +6,8 @@ package association
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -24,6 +26,59 @@ type Unbinder interface {
 	Unbind(ctx context.Context, association commonv1.Association) error
 }
 
+// referenceNotAllowedError is returned when a reference is not allowed, either by the cross-namespace references
+// allowlist of the operator or by RBAC. It is reported in the association condition rather than as a reconciliation
+// error, since retrying does not help until the configuration changes.
+type referenceNotAllowedError struct {
+	msg string
+}
+
+func (e *referenceNotAllowedError) Error() string {
+	return e.msg
+}
+
+// isReferenceNotAllowed returns true if the given error is a referenceNotAllowedError.
+func isReferenceNotAllowed(err error) bool {
+	var notAllowed *referenceNotAllowedError
+	return errors.As(err, &notAllowed)
+}
+
+// checkCrossNamespaceRef checks if the reference is allowed by the cross-namespace references allowlist of the operator
+// and unbinds the association if it is not the case. It returns a referenceNotAllowedError if the reference is not
+// allowed.
+func checkCrossNamespaceRef(
+	ctx context.Context,
+	allowlist rbac.NamespaceAllowlist,
+	association commonv1.Association,
+	unbinder Unbinder,
+	eventRecorder record.EventRecorder,
+) error {
+	ref := association.AssociationRef().NamespacedName()
+	if allowlist.Allowed(association.GetNamespace(), ref.Namespace) {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Cross-namespace association not allowed",
+		"associated_kind", association.GetObjectKind().GroupVersionKind().Kind,
+		"associated_name", association.GetName(),
+		"associated_namespace", association.GetNamespace(),
+		"remote_namespace", ref.Namespace,
+		"remote_name", ref.Name,
+	)
+	eventRecorder.Eventf(
+		association,
+		corev1.EventTypeWarning,
+		events.EventAssociationError,
+		"Cross-namespace association not allowed: %s/%s to %s",
+		association.GetNamespace(), association.GetName(), ref,
+	)
+	if err := unbinder.Unbind(ctx, association); err != nil {
+		return err
+	}
+	return &referenceNotAllowedError{
+		msg: fmt.Sprintf("references from namespace %s to namespace %s are not allowed by the operator configuration", association.GetNamespace(), ref.Namespace),
+	}
+}
+
 // CheckAndUnbind checks if a reference is allowed and unbinds the association if it is not the case
 func CheckAndUnbind(
 	ctx context.Context,
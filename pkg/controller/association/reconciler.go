@@ -164,13 +164,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 
 	associatedKey := k8s.ExtractNamespacedName(associated)
 
-	if common.IsUnmanaged(ctx, associated) {
-		log.Info("Object is currently not managed by this controller. Skipping reconciliation")
+	if !associated.GetDeletionTimestamp().IsZero() {
+		// Object is being deleted, short-circuit reconciliation
 		return reconcile.Result{}, nil
 	}
 
-	if !associated.GetDeletionTimestamp().IsZero() {
-		// Object is being deleted, short-circuit reconciliation
+	if common.IsUnmanaged(ctx, associated) {
+		log.Info("Object is currently not managed by this controller. Skipping reconciliation")
+		if err := r.reportPaused(ctx, associated); apierrors.IsConflict(err) {
+			return reconcile.Result{Requeue: true}, nil
+		} else if err != nil {
+			return defaultRequeue, tracing.CaptureError(ctx, errors.Wrapf(err, "while updating status"))
+		}
 		return reconcile.Result{}, nil
 	}
 
@@ -199,17 +204,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 
 	results := reconciler.NewResult(ctx)
 	newStatusMap := commonv1.AssociationStatusMap{}
+	messages := map[string]string{}
 	for _, association := range associations {
 		newStatus, err := r.reconcileAssociation(ctx, association)
+		ref := association.AssociationRef().NamespacedName().String()
 		if err != nil {
-			results.WithError(err)
+			messages[ref] = err.Error()
+			if !isReferenceNotAllowed(err) {
+				results.WithError(err)
+			}
 		}
 
-		newStatusMap[association.AssociationRef().NamespacedName().String()] = newStatus
+		newStatusMap[ref] = newStatus
 	}
 
 	// we want to attempt a status update even in the presence of errors
-	if err := r.updateStatus(ctx, associated, newStatusMap); err != nil && apierrors.IsConflict(err) {
+	if err := r.updateStatus(ctx, associated, newStatusMap, messages); err != nil && apierrors.IsConflict(err) {
 		log.V(1).Info(
 			"Conflict while updating status",
 			"namespace", associatedKey.Namespace,
@@ -228,6 +238,11 @@ func (r *Reconciler) reconcileAssociation(ctx context.Context, association commo
 	assocRef := association.AssociationRef()
 	log := ulog.FromContext(ctx)
 
+	// check if the reference is allowed by the operator configuration
+	if err := checkCrossNamespaceRef(ctx, r.CrossNamespaceRefsAllowlist, association, r, r.recorder); err != nil {
+		return commonv1.AssociationFailed, err
+	}
+
 	// the referenced object can be an Elastic resource or a custom Secret
 	referencedObj := r.ReferencedObjTemplate()
 	if assocRef.IsExternal() {
@@ -341,6 +356,9 @@ func (r *Reconciler) reconcileAssociation(ctx context.Context, association commo
 
 	// check if reference to Elasticsearch is allowed to be established
 	if allowed, err := CheckAndUnbind(ctx, r.accessReviewer, association, &es, r, r.recorder); err != nil || !allowed {
+		if err == nil {
+			err = &referenceNotAllowedError{msg: fmt.Sprintf("access to %s is not allowed by RBAC", esAssocRef.NamespacedName())}
+		}
 		return commonv1.AssociationPending, err
 	}
 
@@ -365,7 +383,7 @@ func (r *Reconciler) reconcileAssociation(ctx context.Context, association commo
 	// If it is the case create the related Secrets and update the association configuration on the associated resource.
 	assocLabels := r.AssociationResourceLabels(k8s.ExtractNamespacedName(association.Associated()), assocRef.NamespacedName())
 	if len(serviceAccount) > 0 && esHints.ServiceAccounts.IsTrue() {
-		applicationSecretName := secretKey(association, r.ElasticsearchUserCreation.UserSecretSuffix)
+		applicationSecretName := serviceAccountTokenSecretKey(association, r.ElasticsearchUserCreation.UserSecretSuffix)
 		log.V(1).Info("Ensure service account exists", "sa", serviceAccount)
 		err := ReconcileServiceAccounts(
 			ctx,
@@ -379,13 +397,24 @@ func (r *Reconciler) reconcileAssociation(ctx context.Context, association commo
 			association.GetUID(),
 		)
 		if err != nil {
+			k8s.MaybeEmitErrorEvent(r.recorder, err, association.Associated(), events.EventAssociationError,
+				"Failed to reconcile service account token Secret %s: %v", applicationSecretName, err)
 			return commonv1.AssociationFailed, err
 		}
 		expectedAssocConf.AuthSecretName = applicationSecretName.Name
 		expectedAssocConf.AuthSecretKey = "token"
 		expectedAssocConf.IsServiceAccount = true
+		previousAssocConf, err := association.AssociationConf()
+		if err != nil {
+			return commonv1.AssociationPending, err
+		}
 		// update the association configuration if necessary
-		return r.updateAssocConf(ctx, expectedAssocConf, association)
+		status, err := r.updateAssocConf(ctx, expectedAssocConf, association)
+		if err != nil || status != commonv1.AssociationEstablished {
+			return status, err
+		}
+		// the token Secret may have been renamed
+		return status, deletePreviousServiceAccountTokenSecret(ctx, r.Client, previousAssocConf, applicationSecretName)
 	}
 
 	userRole, err := r.ElasticsearchUserCreation.ESUserRole(association.Associated())
@@ -485,12 +514,19 @@ func (r *Reconciler) updateAssocConf(
 	return commonv1.AssociationEstablished, nil
 }
 
-// updateStatus updates the associated resource status.
-func (r *Reconciler) updateStatus(ctx context.Context, associated commonv1.Associated, newStatus commonv1.AssociationStatusMap) error {
+// updateStatus updates the associated resource status, including the association condition if the associated resource
+// publishes one. The given messages detail why the associations are not established.
+func (r *Reconciler) updateStatus(
+	ctx context.Context,
+	associated commonv1.Associated,
+	newStatus commonv1.AssociationStatusMap,
+	messages map[string]string,
+) error {
 	span, _ := apm.StartSpan(ctx, "update_association_status", tracing.SpanTypeApp)
 	defer span.End()
 
 	oldStatus := associated.AssociationStatusMap(r.AssociationType)
+	conditionsUpdated := setAssociationCondition(associated, r.AssociationType, newStatus, messages)
 
 	// To correctly compare statuses without making the reconciler aware of singleton vs multiple associations status
 	// differences we: set new status, get it from associated and only then compare with the oldStatus. Setting the
@@ -501,13 +537,16 @@ func (r *Reconciler) updateStatus(ctx context.Context, associated commonv1.Assoc
 	newStatus = associated.AssociationStatusMap(r.AssociationType)
 
 	// shortcut if the two maps are nil or empty
-	if len(oldStatus) == 0 && len(newStatus) == 0 {
+	if len(oldStatus) == 0 && len(newStatus) == 0 && !conditionsUpdated {
 		return nil
 	}
-	if !reflect.DeepEqual(oldStatus, newStatus) {
+	statusChanged := !reflect.DeepEqual(oldStatus, newStatus)
+	if statusChanged || conditionsUpdated {
 		if err := r.Status().Update(ctx, associated); err != nil {
 			return err
 		}
+	}
+	if statusChanged {
 		annotations, err := annotation.ForAssociationStatusChange(oldStatus, newStatus)
 		if err != nil {
 			return err
@@ -522,6 +561,40 @@ func (r *Reconciler) updateStatus(ctx context.Context, associated commonv1.Assoc
 	return nil
 }
 
+// reportPaused sets the status of the associations of the given resource, whose reconciliation is skipped since it is
+// not managed by the operator, to Paused.
+func (r *Reconciler) reportPaused(ctx context.Context, associated commonv1.Associated) error {
+	statusMap := commonv1.AssociationStatusMap{}
+	messages := map[string]string{}
+	for _, association := range associated.GetAssociations() {
+		if association.AssociationType() != r.AssociationType {
+			continue
+		}
+		ref := association.AssociationRef().NamespacedName().String()
+		statusMap[ref] = commonv1.AssociationPaused
+		messages[ref] = common.PausedMessage
+	}
+	if len(statusMap) == 0 || isPaused(associated.AssociationStatusMap(r.AssociationType), len(statusMap)) {
+		// nothing to report, or already reported
+		return nil
+	}
+	r.recorder.Event(associated, corev1.EventTypeNormal, events.EventReasonPaused, common.PausedMessage)
+	return r.updateStatus(ctx, associated, statusMap, messages)
+}
+
+// isPaused returns true if the given status map holds the given number of paused associations.
+func isPaused(statusMap commonv1.AssociationStatusMap, count int) bool {
+	if len(statusMap) != count {
+		return false
+	}
+	for _, status := range statusMap {
+		if status != commonv1.AssociationPaused {
+			return false
+		}
+	}
+	return true
+}
+
 func resultFromStatuses(statusMap commonv1.AssociationStatusMap) reconcile.Result {
 	for _, status := range statusMap {
 		if status == commonv1.AssociationPending {
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -28,6 +29,7 @@ import (
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/comparison"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	common_name "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
@@ -304,9 +306,32 @@ func TestReconciler_Reconcile_Unmanaged(t *testing.T) {
 	kb.Annotations = map[string]string{common.ManagedAnnotation: "false"}
 	r := testReconciler(&kb)
 	res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
-	// should do nothing
+	// should do nothing but report the association as paused
 	require.NoError(t, err)
 	require.Equal(t, reconcile.Result{}, res)
+	var updatedKibana kbv1.Kibana
+	require.NoError(t, r.Get(context.Background(), k8s.ExtractNamespacedName(&kb), &updatedKibana))
+	require.Equal(t, commonv1.AssociationPaused, updatedKibana.Status.AssociationStatus)
+	condition := meta.FindStatusCondition(updatedKibana.Status.Conditions, "ElasticsearchAssociation")
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionUnknown, condition.Status)
+	require.Equal(t, string(commonv1.AssociationPaused), condition.Reason)
+	// the paused event is only emitted once
+	_, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+	require.NoError(t, err)
+	recorder := r.recorder.(*record.FakeRecorder) //nolint:forcetypeassert
+	require.Len(t, recorder.Events, 2)
+	require.Contains(t, <-recorder.Events, events.EventReasonPaused)
+	require.Contains(t, <-recorder.Events, events.EventAssociationStatusChange)
+
+	// removing the annotation resumes the reconciliation
+	updatedKibana.Annotations = nil
+	require.NoError(t, r.Update(context.Background(), &updatedKibana))
+	_, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+	require.NoError(t, err)
+	require.NoError(t, r.Get(context.Background(), k8s.ExtractNamespacedName(&kb), &updatedKibana))
+	// the referenced Elasticsearch cluster does not exist
+	require.Equal(t, commonv1.AssociationPending, updatedKibana.Status.AssociationStatus)
 }
 
 func TestReconciler_Reconcile_DeletionTimestamp(t *testing.T) {
@@ -411,6 +436,50 @@ func TestReconciler_Reconcile_RBACNotAllowed(t *testing.T) {
 	require.True(t, apierrors.IsNotFound(err))
 }
 
+func TestReconciler_Reconcile_CrossNamespaceRefsAllowlist(t *testing.T) {
+	t.Run("allowed cross-namespace association", func(t *testing.T) {
+		kb := sampleKibanaWithESRef()
+		r := testReconciler(&kb, &sampleES, &esHTTPPublicCertsSecret, esHTTPService())
+		allowlist, err := rbac.NewNamespaceAllowlist([]string{kibanaNamespace + ":" + esNamespace})
+		require.NoError(t, err)
+		r.CrossNamespaceRefsAllowlist = allowlist
+
+		_, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+		require.NoError(t, err)
+		var updatedKibana kbv1.Kibana
+		require.NoError(t, r.Get(context.Background(), k8s.ExtractNamespacedName(&kb), &updatedKibana))
+		require.Equal(t, commonv1.AssociationEstablished, updatedKibana.Status.AssociationStatus)
+		require.NotEmpty(t, updatedKibana.Annotations[kb.EsAssociation().AssociationConfAnnotationName()])
+		require.True(t, meta.IsStatusConditionTrue(updatedKibana.Status.Conditions, "ElasticsearchAssociation"))
+	})
+	t.Run("disallowed cross-namespace association", func(t *testing.T) {
+		kb := sampleAssociatedKibana()
+		r := testReconciler(&kb, &sampleES, &kibanaUserInESNamespace, &esHTTPPublicCertsSecret, esHTTPService())
+		allowlist, err := rbac.NewNamespaceAllowlist([]string{"other:" + esNamespace})
+		require.NoError(t, err)
+		r.CrossNamespaceRefsAllowlist = allowlist
+
+		results, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+		// the reference is rejected without being retried
+		require.NoError(t, err)
+		require.Equal(t, reconcile.Result{}, results)
+		var updatedKibana kbv1.Kibana
+		require.NoError(t, r.Get(context.Background(), k8s.ExtractNamespacedName(&kb), &updatedKibana))
+		require.Equal(t, commonv1.AssociationFailed, updatedKibana.Status.AssociationStatus)
+		// the association conf and the user should be removed
+		require.Empty(t, updatedKibana.Annotations[kb.EsAssociation().AssociationConfAnnotationName()])
+		var secret corev1.Secret
+		err = r.Get(context.Background(), k8s.ExtractNamespacedName(&kibanaUserInESNamespace), &secret)
+		require.True(t, apierrors.IsNotFound(err))
+		// the condition should report the reason
+		condition := meta.FindStatusCondition(updatedKibana.Status.Conditions, "ElasticsearchAssociation")
+		require.NotNil(t, condition)
+		require.Equal(t, metav1.ConditionFalse, condition.Status)
+		require.Equal(t, "Failed", condition.Reason)
+		require.Equal(t, "esns/esname: references from namespace kbns to namespace esns are not allowed by the operator configuration", condition.Message)
+	})
+}
+
 func TestReconciler_Reconcile_NewAssociation(t *testing.T) {
 	// Kibana references ES, but no secret nor association conf exist yet
 	kb := sampleKibanaWithESRef()
@@ -464,6 +533,59 @@ func TestReconciler_Reconcile_NewAssociation(t *testing.T) {
 	require.Equal(t, commonv1.AssociationEstablished, updatedKibana.Status.AssociationStatus)
 }
 
+func TestReconciler_Reconcile_AssociationCondition(t *testing.T) {
+	kb := sampleKibanaWithESRef()
+	kb.Generation = 2
+	r := testReconciler(&kb)
+	getCondition := func() *metav1.Condition {
+		var updatedKibana kbv1.Kibana
+		require.NoError(t, r.Get(context.Background(), k8s.ExtractNamespacedName(&kb), &updatedKibana))
+		return meta.FindStatusCondition(updatedKibana.Status.Conditions, "ElasticsearchAssociation")
+	}
+
+	// es resource does not exist yet: the association is pending
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+	require.NoError(t, err)
+	condition := getCondition()
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionUnknown, condition.Status)
+	require.Equal(t, "Pending", condition.Reason)
+	require.Equal(t, "esns/esname: association is pending", condition.Message)
+	require.Equal(t, int64(2), condition.ObservedGeneration)
+	require.False(t, condition.LastTransitionTime.IsZero())
+
+	// es resource is created: the association is established
+	for _, obj := range []client.Object{sampleES.DeepCopy(), esHTTPPublicCertsSecret.DeepCopy(), esHTTPService()} {
+		obj.SetResourceVersion("")
+		require.NoError(t, r.Create(context.Background(), obj))
+	}
+	_, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+	require.NoError(t, err)
+	condition = getCondition()
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+	require.Equal(t, "Established", condition.Reason)
+	require.Equal(t, "Association established", condition.Message)
+
+	// access to es is not allowed anymore: the association is pending again
+	r.accessReviewer = denyAllAccessReviewer{}
+	_, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+	require.NoError(t, err)
+	condition = getCondition()
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionUnknown, condition.Status)
+	require.Equal(t, "Pending", condition.Reason)
+
+	// es reference is removed: the condition is removed
+	var updatedKibana kbv1.Kibana
+	require.NoError(t, r.Get(context.Background(), k8s.ExtractNamespacedName(&kb), &updatedKibana))
+	updatedKibana.Spec.ElasticsearchRef = commonv1.ObjectSelector{}
+	require.NoError(t, r.Update(context.Background(), &updatedKibana))
+	_, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+	require.NoError(t, err)
+	require.Nil(t, getCondition())
+}
+
 func TestReconciler_Reconcile_noESAuth(t *testing.T) {
 	// Kibana references Enterprise Search, the association controller is configured to not
 	// create an Elasticsearch user
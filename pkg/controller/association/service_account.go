@@ -41,6 +41,31 @@ const (
 	ServiceAccountTokenValueField = "token"
 )
 
+// serviceAccountTokenSecretKey returns the name of the Secret holding the service account token of the given association,
+// which may be chosen by the user.
+func serviceAccountTokenSecretKey(association commonv1.Association, userSuffix string) types.NamespacedName {
+	key := secretKey(association, userSuffix)
+	if namer, ok := association.(commonv1.ServiceAccountTokenSecretNamer); ok && namer.ServiceAccountTokenSecretName() != "" {
+		key.Name = namer.ServiceAccountTokenSecretName()
+	}
+	return key
+}
+
+// deletePreviousServiceAccountTokenSecret deletes the Secret which held the service account token according to the
+// previous association configuration, if the token is now held by another Secret.
+func deletePreviousServiceAccountTokenSecret(
+	ctx context.Context,
+	client k8s.Client,
+	previousAssocConf *commonv1.AssociationConf,
+	current types.NamespacedName,
+) error {
+	if previousAssocConf == nil || !previousAssocConf.IsServiceAccount || previousAssocConf.AuthSecretName == current.Name {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Deleting previous service account token secret", "namespace", current.Namespace, "secret_name", previousAssocConf.AuthSecretName)
+	return k8s.DeleteSecretIfExists(ctx, client, types.NamespacedName{Namespace: current.Namespace, Name: previousAssocConf.AuthSecretName})
+}
+
 func applicationSecretLabels(es esv1.Elasticsearch) map[string]string {
 	return labels.AddCredentialsLabel(map[string]string{
 		label.ClusterNamespaceLabelName: es.Namespace,
@@ -74,6 +99,10 @@ func reconcileApplicationSecret(
 	if err != nil && !k8serrors.IsNotFound(err) {
 		return nil, err
 	}
+	if err == nil && !isApplicationSecretOwned(applicationStore, es, commonLabels) {
+		// the name of the Secret may be chosen by the user, do not take over a Secret which belongs to someone else
+		return nil, fmt.Errorf("secret %s/%s already exists and does not belong to the association", applicationSecretName.Namespace, applicationSecretName.Name)
+	}
 
 	var token *Token
 	if k8serrors.IsNotFound(err) || len(applicationStore.Data) == 0 {
@@ -115,6 +144,26 @@ func reconcileApplicationSecret(
 	return token, err
 }
 
+// isApplicationSecretOwned returns true if the given existing application Secret belongs to the association: it must
+// carry the association labels and must not be controlled by another resource. The labels identifying the Elasticsearch
+// cluster are not compared as they change along with the Elasticsearch reference of the association.
+func isApplicationSecretOwned(secret corev1.Secret, es esv1.Elasticsearch, commonLabels map[string]string) bool {
+	if metav1.GetControllerOf(&secret) != nil {
+		// the operator does not set a controller reference on the application Secret
+		return false
+	}
+	esLabels := applicationSecretLabels(es)
+	for labelName, labelValue := range commonLabels {
+		if _, isESLabel := esLabels[labelName]; isESLabel {
+			continue
+		}
+		if value, exists := secret.Labels[labelName]; !exists || value != labelValue {
+			return false
+		}
+	}
+	return true
+}
+
 func getOrCreateToken(
 	ctx context.Context,
 	es *esv1.Elasticsearch,
@@ -17,8 +17,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/pbkdf2"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -257,6 +259,172 @@ func Test_ReconcileServiceAccounts(t *testing.T) {
 	}
 }
 
+func Test_serviceAccountTokenSecretKey(t *testing.T) {
+	kb := existingKibana.DeepCopy()
+	assert.Equal(t,
+		types.NamespacedName{Namespace: "e2e-venus", Name: "kibana-sample-kibana-user"},
+		serviceAccountTokenSecretKey(kb.EsAssociation(), "kibana-user"),
+	)
+	kb.Spec.ServiceAccountTokenSecretName = "kibana-token"
+	assert.Equal(t,
+		types.NamespacedName{Namespace: "e2e-venus", Name: "kibana-token"},
+		serviceAccountTokenSecretKey(kb.EsAssociation(), "kibana-user"),
+	)
+}
+
+func Test_ReconcileServiceAccounts_customSecretName(t *testing.T) {
+	ctx := context.Background()
+	c := k8s.NewFakeClient(existingElasticsearch.DeepCopy(), existingKibana.DeepCopy())
+	kb := existingKibana.DeepCopy()
+	kb.Spec.ServiceAccountTokenSecretName = "kibana-token"
+	applicationSecretName := serviceAccountTokenSecretKey(kb.EsAssociation(), "kibana-user")
+	elasticsearchSecretName := types.NamespacedName{Namespace: "e2e-mercury", Name: "e2e-venus-kibana-sample-kibana-user"}
+	reconcile := func() (corev1.Secret, corev1.Secret) {
+		t.Helper()
+		assert.NoError(t, ReconcileServiceAccounts(ctx, c, existingElasticsearch, nil, applicationSecretName,
+			elasticsearchSecretName, "kibana", kb.Name, kb.UID))
+		var applicationSecret, elasticsearchSecret corev1.Secret
+		assert.NoError(t, c.Get(ctx, applicationSecretName, &applicationSecret))
+		assert.NoError(t, c.Get(ctx, elasticsearchSecretName, &elasticsearchSecret))
+		return applicationSecret, elasticsearchSecret
+	}
+
+	// the token is created in the Secret with the user-provided name
+	applicationSecret, elasticsearchSecret := reconcile()
+	token := string(applicationSecret.Data["token"])
+	verifyToken(t, token, string(applicationSecret.Data["hash"]), "kibana", string(applicationSecret.Data["name"]))
+	assert.Equal(t, applicationSecret.Data["hash"], elasticsearchSecret.Data["hash"])
+
+	// the token is regenerated in the same Secret once its content is invalid
+	delete(applicationSecret.Data, "hash")
+	assert.NoError(t, c.Update(ctx, &applicationSecret))
+	applicationSecret, elasticsearchSecret = reconcile()
+	assert.NotEqual(t, token, string(applicationSecret.Data["token"]))
+	verifyToken(t, string(applicationSecret.Data["token"]), string(applicationSecret.Data["hash"]), "kibana", string(applicationSecret.Data["name"]))
+	assert.Equal(t, applicationSecret.Data["hash"], elasticsearchSecret.Data["hash"])
+	token = string(applicationSecret.Data["token"])
+
+	// as well as once it is emptied
+	applicationSecret.Data = nil
+	assert.NoError(t, c.Update(ctx, &applicationSecret))
+	applicationSecret, elasticsearchSecret = reconcile()
+	assert.NotEqual(t, token, string(applicationSecret.Data["token"]))
+	verifyToken(t, string(applicationSecret.Data["token"]), string(applicationSecret.Data["hash"]), "kibana", string(applicationSecret.Data["name"]))
+	assert.Equal(t, applicationSecret.Data["hash"], elasticsearchSecret.Data["hash"])
+
+	// the Secret with the default name is never created
+	var secret corev1.Secret
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, types.NamespacedName{Namespace: "e2e-venus", Name: "kibana-sample-kibana-user"}, &secret)))
+}
+
+func Test_ReconcileServiceAccounts_secretNotOwned(t *testing.T) {
+	commonLabels := map[string]string{
+		"elasticsearch.k8s.elastic.co/cluster-name":      "elasticsearch-sample",
+		"elasticsearch.k8s.elastic.co/cluster-namespace": "e2e-mercury",
+		"kibanaassociation.k8s.elastic.co/type":          "elasticsearch",
+		"kibanaassociation.k8s.elastic.co/name":          "kibana-sample",
+		"kibanaassociation.k8s.elastic.co/namespace":     "e2e-venus",
+	}
+	applicationSecretName := types.NamespacedName{Namespace: "e2e-venus", Name: "kibana-token"}
+	elasticsearchSecretName := types.NamespacedName{Namespace: "e2e-mercury", Name: "e2e-venus-kibana-sample-kibana-user"}
+	tests := []struct {
+		name    string
+		secret  func(secret *corev1.Secret)
+		wantErr bool
+	}{
+		{
+			name:   "Secret created by the association",
+			secret: func(secret *corev1.Secret) {},
+		},
+		{
+			name: "Secret created by the association for another Elasticsearch cluster",
+			secret: func(secret *corev1.Secret) {
+				secret.Labels["elasticsearch.k8s.elastic.co/cluster-name"] = "other"
+			},
+		},
+		{
+			name: "Secret without the association labels",
+			secret: func(secret *corev1.Secret) {
+				secret.Labels = map[string]string{"app": "gitops"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Secret of another association",
+			secret: func(secret *corev1.Secret) {
+				secret.Labels["kibanaassociation.k8s.elastic.co/name"] = "other"
+			},
+			wantErr: true,
+		},
+		{
+			name: "Secret controlled by another resource",
+			secret: func(secret *corev1.Secret) {
+				secret.OwnerReferences = []metav1.OwnerReference{{
+					APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "c8a5c6e4", Controller: ptr.To(true),
+				}}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := expectedKibanaUserSecret.DeepCopy()
+			existing.Name = applicationSecretName.Name
+			tt.secret(existing)
+			c := k8s.NewFakeClient(existingElasticsearch.DeepCopy(), existingKibana.DeepCopy(), existing)
+			err := ReconcileServiceAccounts(context.Background(), c, existingElasticsearch, commonLabels, applicationSecretName,
+				elasticsearchSecretName, "kibana", existingKibana.Name, existingKibana.UID)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			var secret corev1.Secret
+			assert.NoError(t, c.Get(context.Background(), applicationSecretName, &secret))
+			if tt.wantErr {
+				// the Secret is left untouched
+				assert.Equal(t, existing.ResourceVersion, secret.ResourceVersion)
+				return
+			}
+			assert.Equal(t, expectedKibanaUserSecret.Labels, secret.Labels)
+			assert.Equal(t, expectedKibanaUserSecret.Data["token"], secret.Data["token"])
+		})
+	}
+}
+
+func Test_deletePreviousServiceAccountTokenSecret(t *testing.T) {
+	ctx := context.Background()
+	current := types.NamespacedName{Namespace: "e2e-venus", Name: "kibana-token"}
+	tests := []struct {
+		name              string
+		previousAssocConf *commonv1.AssociationConf
+		wantDeleted       bool
+	}{
+		{
+			name: "no previous association configuration",
+		},
+		{
+			name:              "previous configuration does not use a service account",
+			previousAssocConf: &commonv1.AssociationConf{AuthSecretName: "kibana-sample-kibana-user"},
+		},
+		{
+			name:              "Secret not renamed",
+			previousAssocConf: &commonv1.AssociationConf{AuthSecretName: "kibana-token", IsServiceAccount: true},
+		},
+		{
+			name:              "Secret renamed",
+			previousAssocConf: &commonv1.AssociationConf{AuthSecretName: "kibana-sample-kibana-user", IsServiceAccount: true},
+			wantDeleted:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(expectedKibanaUserSecret.DeepCopy())
+			assert.NoError(t, deletePreviousServiceAccountTokenSecret(ctx, c, tt.previousAssocConf, current))
+			var secret corev1.Secret
+			err := c.Get(ctx, k8s.ExtractNamespacedName(&expectedKibanaUserSecret), &secret)
+			assert.Equal(t, tt.wantDeleted, apierrors.IsNotFound(err))
+		})
+	}
+}
+
 func Test_newApplicationToken(t *testing.T) {
 	type args struct {
 		serviceAccountName commonv1.ServiceAccountName
@@ -23,7 +23,7 @@ func Add(mgr manager.Manager, p operator.Parameters) error {
 
 	// The CRD based controller watches for changes on both the ElasticsearchAutoscaler CRD, and on the Elasticsearch resources to make sure the
 	// NodeSets resources are reconciled with the required resources.
-	controller, err := common.NewController(mgr, elasticsearch.ControllerName, reconciler, p)
+	controller, err := common.NewController(mgr, elasticsearch.ControllerName, reconciler, &v1alpha1.ElasticsearchAutoscaler{}, p)
 	if err != nil {
 		return err
 	}
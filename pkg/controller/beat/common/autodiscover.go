@@ -0,0 +1,181 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+)
+
+// autodiscoverResources are the resources the Kubernetes autodiscover provider reads to discover the pods and
+// enrich the events with their metadata.
+var autodiscoverResources = []string{"pods", "nodes", "namespaces"}
+
+// autodiscoverVerbs are the verbs the Kubernetes autodiscover provider needs on each of the autodiscoverResources.
+var autodiscoverVerbs = []string{"get", "list", "watch"}
+
+// checkedGeneration identifies the generation of a Beat whose autodiscover permissions were checked.
+type checkedGeneration struct {
+	uid        types.UID
+	generation int64
+}
+
+// AutodiscoverRBACChecks holds the last generation of each Beat whose autodiscover permissions were checked, so that
+// the permissions are only checked again when the spec of the Beat changes.
+type AutodiscoverRBACChecks struct {
+	mutex   sync.Mutex
+	checked map[types.NamespacedName]checkedGeneration
+}
+
+// NewAutodiscoverRBACChecks returns an empty set of autodiscover permissions checks.
+func NewAutodiscoverRBACChecks() *AutodiscoverRBACChecks {
+	return &AutodiscoverRBACChecks{checked: map[types.NamespacedName]checkedGeneration{}}
+}
+
+func (c *AutodiscoverRBACChecks) isChecked(beat types.NamespacedName, checked checkedGeneration) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	last, exists := c.checked[beat]
+	return exists && last == checked
+}
+
+func (c *AutodiscoverRBACChecks) set(beat types.NamespacedName, checked checkedGeneration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.checked[beat] = checked
+}
+
+// Forget forgets the autodiscover permissions check of the given Beat, once it is deleted.
+func (c *AutodiscoverRBACChecks) Forget(beat types.NamespacedName) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.checked, beat)
+}
+
+// buildAutodiscoverConfig expands the autodiscover specification of the Beat into the configuration of a Kubernetes
+// autodiscover provider.
+func buildAutodiscoverConfig(beat beatv1beta1.Beat) (*settings.CanonicalConfig, error) {
+	spec := beat.Spec.Autodiscover
+	if spec == nil {
+		return settings.NewCanonicalConfig(), nil
+	}
+	provider := map[string]interface{}{
+		"type":          "kubernetes",
+		"scope":         string(spec.GetScope()),
+		"hints.enabled": spec.HintsEnabled,
+	}
+	if spec.HintsEnabled && beat.Spec.Type == "filebeat" {
+		// collect the logs of the containers without hints
+		provider["hints.default_config"] = map[string]interface{}{
+			"type":  "container",
+			"paths": []string{"/var/log/containers/*${data.kubernetes.container.id}.log"},
+		}
+	}
+	if spec.GetScope() == beatv1beta1.AutodiscoverNodeScope {
+		provider["node"] = fmt.Sprintf("${%s}", beatv1beta1.AutodiscoverNodeNameEnvVar)
+	}
+	if spec.Namespace != "" {
+		provider["namespace"] = spec.Namespace
+	}
+	return settings.NewCanonicalConfigFrom(map[string]interface{}{
+		fmt.Sprintf("%s.autodiscover.providers", beat.Spec.Type): []interface{}{provider},
+	})
+}
+
+// autodiscoverEnv returns the environment variables referenced by the autodiscover provider configuration.
+func autodiscoverEnv(beat beatv1beta1.Beat) []corev1.EnvVar {
+	if beat.Spec.Autodiscover == nil || beat.Spec.Autodiscover.GetScope() != beatv1beta1.AutodiscoverNodeScope {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name: beatv1beta1.AutodiscoverNodeNameEnvVar,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+		},
+	}}
+}
+
+// checkAutodiscoverRBAC checks that the service account of the Beat pods is allowed to read the resources required by
+// the autodiscover provider, and emits a warning event listing the missing permissions otherwise.
+// The operator does not create the RBAC resources itself, as it is not allowed to grant permissions.
+// Permissions are checked once per generation of the Beat, as each check creates several SubjectAccessReviews.
+func checkAutodiscoverRBAC(params DriverParams) error {
+	if params.Beat.Spec.Autodiscover == nil {
+		return nil
+	}
+	key := k8s.ExtractNamespacedName(&params.Beat)
+	checked := checkedGeneration{uid: params.Beat.UID, generation: params.Beat.Generation}
+	if params.AutodiscoverRBACChecks.isChecked(key, checked) {
+		return nil
+	}
+	podSpec := params.GetPodTemplate().Spec
+	serviceAccount := podSpec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = podSpec.DeprecatedServiceAccount
+	}
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	missing, err := missingAutodiscoverPermissions(params.Context, params.Client, params.Beat, serviceAccount)
+	if err != nil {
+		return err
+	}
+	params.AutodiscoverRBACChecks.set(key, checked)
+	if len(missing) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf(
+		"Service account %s is missing the permissions to %s required by autodiscover",
+		serviceAccount, strings.Join(missing, ", "),
+	)
+	ulog.FromContext(params.Context).Info(msg, "namespace", params.Beat.Namespace, "beat_name", params.Beat.Name)
+	params.EventRecorder.Event(&params.Beat, corev1.EventTypeWarning, events.EventReasonValidation, msg)
+	return nil
+}
+
+// missingAutodiscoverPermissions returns the permissions required by the autodiscover provider that the given service
+// account is not granted, formatted as "<verb> <resource>".
+func missingAutodiscoverPermissions(ctx context.Context, c k8s.Client, beat beatv1beta1.Beat, serviceAccount string) ([]string, error) {
+	var missing []string
+	for _, resource := range autodiscoverResources {
+		// pods are only read from the namespace the discovery is restricted to, if any
+		var namespace string
+		if resource == "pods" {
+			namespace = beat.Spec.Autodiscover.Namespace
+		}
+		for _, verb := range autodiscoverVerbs {
+			review := authorizationv1.SubjectAccessReview{
+				Spec: authorizationv1.SubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Namespace: namespace,
+						Verb:      verb,
+						Resource:  resource,
+					},
+					User: rbac.ServiceAccountUsernamePrefix + beat.Namespace + ":" + serviceAccount,
+				},
+			}
+			if err := c.Create(ctx, &review); err != nil {
+				return nil, err
+			}
+			if !review.Status.Allowed || review.Status.Denied {
+				missing = append(missing, verb+" "+resource)
+			}
+		}
+	}
+	return missing, nil
+}
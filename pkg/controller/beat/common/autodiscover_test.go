@@ -0,0 +1,266 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_buildAutodiscoverConfig(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		typ          string
+		autodiscover *beatv1beta1.AutodiscoverSpec
+		want         *settings.CanonicalConfig
+	}{
+		{
+			name: "no autodiscover",
+			typ:  "filebeat",
+			want: settings.NewCanonicalConfig(),
+		},
+		{
+			name:         "defaults to the node scope",
+			typ:          "filebeat",
+			autodiscover: &beatv1beta1.AutodiscoverSpec{},
+			want: settings.MustParseConfig([]byte(`filebeat.autodiscover.providers:
+- type: kubernetes
+  scope: node
+  node: ${NODE_NAME}
+  hints.enabled: false
+`)),
+		},
+		{
+			name:         "hints enabled",
+			typ:          "filebeat",
+			autodiscover: &beatv1beta1.AutodiscoverSpec{HintsEnabled: true, Scope: beatv1beta1.AutodiscoverNodeScope},
+			want: settings.MustParseConfig([]byte(`filebeat.autodiscover.providers:
+- type: kubernetes
+  scope: node
+  node: ${NODE_NAME}
+  hints.enabled: true
+  hints.default_config:
+    type: container
+    paths:
+    - /var/log/containers/*${data.kubernetes.container.id}.log
+`)),
+		},
+		{
+			name:         "cluster scope restricted to a namespace",
+			typ:          "metricbeat",
+			autodiscover: &beatv1beta1.AutodiscoverSpec{HintsEnabled: true, Scope: beatv1beta1.AutodiscoverClusterScope, Namespace: "apps"},
+			want: settings.MustParseConfig([]byte(`metricbeat.autodiscover.providers:
+- type: kubernetes
+  scope: cluster
+  namespace: apps
+  hints.enabled: true
+`)),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			beat := beatv1beta1.Beat{Spec: beatv1beta1.BeatSpec{Type: tt.typ, Autodiscover: tt.autodiscover}}
+			got, err := buildAutodiscoverConfig(beat)
+			require.NoError(t, err)
+			require.Empty(t, tt.want.Diff(got, nil))
+		})
+	}
+}
+
+func Test_buildBeatConfig_autodiscover(t *testing.T) {
+	beat := beatv1beta1.Beat{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "beat"},
+		Spec: beatv1beta1.BeatSpec{
+			Type:         "filebeat",
+			Autodiscover: &beatv1beta1.AutodiscoverSpec{HintsEnabled: true},
+			Config: &commonv1.Config{Data: map[string]interface{}{
+				"filebeat.autodiscover.providers": []interface{}{map[string]interface{}{"type": "docker"}},
+			}},
+		},
+	}
+	got, err := buildBeatConfig(DriverParams{
+		Client:  k8s.NewFakeClient(),
+		Watches: watches.NewDynamicWatches(),
+		Beat:    beat,
+	}, nil)
+	require.NoError(t, err)
+	// the user-provided providers are appended to the one expanded from the autodiscover specification
+	want := settings.MustParseConfig([]byte(`filebeat.autodiscover.providers:
+- type: kubernetes
+  scope: node
+  node: ${NODE_NAME}
+  hints.enabled: true
+  hints.default_config:
+    type: container
+    paths:
+    - /var/log/containers/*${data.kubernetes.container.id}.log
+- type: docker
+`))
+	require.Empty(t, want.Diff(settings.MustParseConfig(got), nil))
+}
+
+func Test_autodiscoverEnv(t *testing.T) {
+	require.Nil(t, autodiscoverEnv(beatv1beta1.Beat{}))
+	require.Nil(t, autodiscoverEnv(beatv1beta1.Beat{Spec: beatv1beta1.BeatSpec{
+		Autodiscover: &beatv1beta1.AutodiscoverSpec{Scope: beatv1beta1.AutodiscoverClusterScope},
+	}}))
+	require.Equal(t, []corev1.EnvVar{{
+		Name:      "NODE_NAME",
+		ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}},
+	}}, autodiscoverEnv(beatv1beta1.Beat{Spec: beatv1beta1.BeatSpec{Autodiscover: &beatv1beta1.AutodiscoverSpec{}}}))
+}
+
+// newAccessReviewClient returns a client answering the subject access reviews with the given function.
+func newAccessReviewClient(allowed func(authorizationv1.SubjectAccessReviewSpec) bool, reviews *[]authorizationv1.SubjectAccessReviewSpec) k8s.Client {
+	return fake.NewClientBuilder().
+		WithScheme(clientgoscheme.Scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				review, ok := obj.(*authorizationv1.SubjectAccessReview)
+				if !ok {
+					return c.Create(ctx, obj, opts...)
+				}
+				*reviews = append(*reviews, review.Spec)
+				review.Status.Allowed = allowed(review.Spec)
+				return nil
+			},
+		}).
+		Build()
+}
+
+func Test_checkAutodiscoverRBAC(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		autodiscover  *beatv1beta1.AutodiscoverSpec
+		podTemplate   corev1.PodTemplateSpec
+		allowed       func(authorizationv1.SubjectAccessReviewSpec) bool
+		wantUser      string
+		wantPodsNs    string
+		wantEvent     string
+		wantNoReviews bool
+	}{
+		{
+			name:          "no autodiscover",
+			allowed:       func(authorizationv1.SubjectAccessReviewSpec) bool { return false },
+			wantNoReviews: true,
+		},
+		{
+			name:         "all permissions granted to the default service account",
+			autodiscover: &beatv1beta1.AutodiscoverSpec{},
+			allowed:      func(authorizationv1.SubjectAccessReviewSpec) bool { return true },
+			wantUser:     "system:serviceaccount:ns:default",
+		},
+		{
+			name:         "missing permissions on nodes",
+			autodiscover: &beatv1beta1.AutodiscoverSpec{Namespace: "apps"},
+			podTemplate:  corev1.PodTemplateSpec{Spec: corev1.PodSpec{DeprecatedServiceAccount: "filebeat"}},
+			allowed: func(spec authorizationv1.SubjectAccessReviewSpec) bool {
+				return spec.ResourceAttributes.Resource != "nodes" || spec.ResourceAttributes.Verb == "get"
+			},
+			wantUser:   "system:serviceaccount:ns:filebeat",
+			wantPodsNs: "apps",
+			wantEvent:  "Warning Validation Service account filebeat is missing the permissions to list nodes, watch nodes required by autodiscover",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var reviews []authorizationv1.SubjectAccessReviewSpec
+			recorder := record.NewFakeRecorder(10)
+			params := DriverParams{
+				Context:       context.Background(),
+				Client:        newAccessReviewClient(tt.allowed, &reviews),
+				EventRecorder: recorder,
+
+				AutodiscoverRBACChecks: NewAutodiscoverRBACChecks(),
+				Beat: beatv1beta1.Beat{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "beat"},
+					Spec: beatv1beta1.BeatSpec{
+						Type:         "filebeat",
+						Autodiscover: tt.autodiscover,
+						DaemonSet:    &beatv1beta1.DaemonSetSpec{PodTemplate: tt.podTemplate},
+					},
+				},
+			}
+			require.NoError(t, checkAutodiscoverRBAC(params))
+
+			if tt.wantNoReviews {
+				require.Empty(t, reviews)
+			} else {
+				require.Len(t, reviews, 9)
+				for _, review := range reviews {
+					require.Equal(t, tt.wantUser, review.User)
+					if review.ResourceAttributes.Resource == "pods" {
+						require.Equal(t, tt.wantPodsNs, review.ResourceAttributes.Namespace)
+					} else {
+						require.Empty(t, review.ResourceAttributes.Namespace)
+					}
+				}
+			}
+
+			if tt.wantEvent == "" {
+				require.Empty(t, recorder.Events)
+				return
+			}
+			require.Len(t, recorder.Events, 1)
+			require.Equal(t, tt.wantEvent, <-recorder.Events)
+		})
+	}
+}
+
+func Test_checkAutodiscoverRBAC_oncePerGeneration(t *testing.T) {
+	var reviews []authorizationv1.SubjectAccessReviewSpec
+	recorder := record.NewFakeRecorder(10)
+	params := DriverParams{
+		Context:       context.Background(),
+		Client:        newAccessReviewClient(func(authorizationv1.SubjectAccessReviewSpec) bool { return false }, &reviews),
+		EventRecorder: recorder,
+
+		AutodiscoverRBACChecks: NewAutodiscoverRBACChecks(),
+		Beat: beatv1beta1.Beat{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "beat", UID: "uid", Generation: 1},
+			Spec: beatv1beta1.BeatSpec{
+				Type:         "filebeat",
+				Autodiscover: &beatv1beta1.AutodiscoverSpec{},
+				DaemonSet:    &beatv1beta1.DaemonSetSpec{},
+			},
+		},
+	}
+
+	// the permissions of a given generation are checked once
+	require.NoError(t, checkAutodiscoverRBAC(params))
+	require.NoError(t, checkAutodiscoverRBAC(params))
+	require.Len(t, reviews, 9)
+	require.Len(t, recorder.Events, 1)
+
+	// a spec change triggers a new check
+	params.Beat.Generation = 2
+	require.NoError(t, checkAutodiscoverRBAC(params))
+	require.Len(t, reviews, 18)
+	require.Len(t, recorder.Events, 2)
+
+	// so does the re-creation of the Beat
+	params.Beat.UID = "other-uid"
+	require.NoError(t, checkAutodiscoverRBAC(params))
+	require.Len(t, reviews, 27)
+
+	// forgotten checks are run again
+	params.AutodiscoverRBACChecks.Forget(k8s.ExtractNamespacedName(&params.Beat))
+	require.NoError(t, checkAutodiscoverRBAC(params))
+	require.Len(t, reviews, 36)
+}
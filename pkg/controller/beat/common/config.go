@@ -104,7 +104,11 @@ func buildBeatConfig(
 	if err != nil {
 		return nil, err
 	}
-	err = cfg.MergeWith(outputCfg, managedConfig)
+	autodiscoverCfg, err := buildAutodiscoverConfig(params.Beat)
+	if err != nil {
+		return nil, err
+	}
+	err = cfg.MergeWith(outputCfg, managedConfig, autodiscoverCfg)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +42,8 @@ type DriverParams struct {
 
 	Status *beatv1beta1.BeatStatus
 	Beat   beatv1beta1.Beat
+
+	AutodiscoverRBACChecks *AutodiscoverRBACChecks
 }
 
 func (dp DriverParams) K8sClient() k8s.Client {
@@ -95,6 +97,10 @@ func Reconcile(
 		return results.WithError(err), params.Status
 	}
 
+	if err := checkAutodiscoverRBAC(params); err != nil {
+		return results.WithError(err), params.Status
+	}
+
 	// we need to deref the secret here (if any) to include it in the configHash otherwise Beat will not be rolled on content changes
 	if err := commonassociation.WriteAssocsToConfigHash(params.Client, params.Beat.GetAssociations(), configHash); err != nil {
 		return results.WithError(err), params.Status
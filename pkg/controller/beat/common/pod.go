@@ -208,6 +208,7 @@ func buildPodTemplate(
 		WithDockerImage(spec.Image, container.ImageRepository(defaultImage, v)).
 		WithVolumes(volumes...).
 		WithVolumeMounts(volumeMounts...).
+		WithEnv(autodiscoverEnv(params.Beat)...).
 		WithInitContainers(initContainers...).
 		WithInitContainerDefaults().
 		WithContainers(sideCars...)
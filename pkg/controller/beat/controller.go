@@ -48,7 +48,7 @@ const (
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	r := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, controllerName, r, params)
+	c, err := common.NewController(mgr, controllerName, r, &beatv1beta1.Beat{}, params)
 	if err != nil {
 		return err
 	}
@@ -59,10 +59,11 @@ func Add(mgr manager.Manager, params operator.Parameters) error {
 func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileBeat {
 	client := mgr.GetClient()
 	return &ReconcileBeat{
-		Client:         client,
-		recorder:       mgr.GetEventRecorderFor(controllerName),
-		dynamicWatches: watches.NewDynamicWatches(),
-		Parameters:     params,
+		Client:                 client,
+		recorder:               mgr.GetEventRecorderFor(controllerName),
+		dynamicWatches:         watches.NewDynamicWatches(),
+		autodiscoverRBACChecks: beatcommon.NewAutodiscoverRBACChecks(),
+		Parameters:             params,
 	}
 }
 
@@ -117,6 +118,8 @@ type ReconcileBeat struct {
 	k8s.Client
 	recorder       record.EventRecorder
 	dynamicWatches watches.DynamicWatches
+	// autodiscoverRBACChecks caches the autodiscover permissions checks of the Beats
+	autodiscoverRBACChecks *beatcommon.AutodiscoverRBACChecks
 	operator.Parameters
 	// iteration is the number of times this controller has run its Reconcile method
 	iteration uint64
@@ -162,7 +165,7 @@ func (r *ReconcileBeat) Reconcile(ctx context.Context, request reconcile.Request
 	res, err := results.Aggregate()
 	k8s.MaybeEmitErrorEvent(r.recorder, err, &beat, events.EventReconciliationError, "Reconciliation error: %v", err)
 
-	return res, err
+	return common.WithResync(ctx, &beat, r.ResyncPeriod, res), err
 }
 
 func (r *ReconcileBeat) doReconcile(ctx context.Context, beat beatv1beta1.Beat) (*reconciler.Results, *beatv1beta1.BeatStatus) {
@@ -182,7 +185,7 @@ func (r *ReconcileBeat) doReconcile(ctx context.Context, beat beatv1beta1.Beat)
 		return results.WithError(err), &status
 	}
 
-	driverResults, updatedStatus := newDriver(ctx, r.recorder, r.Client, r.dynamicWatches, beat, status).Reconcile()
+	driverResults, updatedStatus := newDriver(ctx, r.recorder, r.Client, r.dynamicWatches, r.autodiscoverRBACChecks, beat, status).Reconcile()
 	return results.WithResults(driverResults), updatedStatus
 }
 
@@ -200,6 +203,7 @@ func (r *ReconcileBeat) validate(ctx context.Context, beat *beatv1beta1.Beat) er
 }
 
 func (r *ReconcileBeat) onDelete(ctx context.Context, obj types.NamespacedName) error {
+	r.autodiscoverRBACChecks.Forget(obj)
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(obj))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(common.ConfigRefWatchName(obj))
 	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, obj, beatv1beta1.Kind)
@@ -210,6 +214,7 @@ func newDriver(
 	recorder record.EventRecorder,
 	client k8s.Client,
 	dynamicWatches watches.DynamicWatches,
+	autodiscoverRBACChecks *beatcommon.AutodiscoverRBACChecks,
 	beat beatv1beta1.Beat,
 	status beatv1beta1.BeatStatus,
 ) beatcommon.Driver {
@@ -220,6 +225,8 @@ func newDriver(
 		EventRecorder: recorder,
 		Status:        &status,
 		Beat:          beat,
+
+		AutodiscoverRBACChecks: autodiscoverRBACChecks,
 	}
 
 	switch beat.Spec.Type {
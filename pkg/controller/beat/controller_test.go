@@ -18,6 +18,7 @@ import (
 
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	beatcommon "github.com/elastic/cloud-on-k8s/v2/pkg/controller/beat/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
@@ -172,10 +173,11 @@ func TestReconcileBeat_Reconcile(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &ReconcileBeat{
-				Client:         tt.Client,
-				recorder:       record.NewFakeRecorder(100),
-				dynamicWatches: watches.NewDynamicWatches(),
-				Parameters:     operator.Parameters{},
+				Client:                 tt.Client,
+				recorder:               record.NewFakeRecorder(100),
+				dynamicWatches:         watches.NewDynamicWatches(),
+				autodiscoverRBACChecks: beatcommon.NewAutodiscoverRBACChecks(),
+				Parameters:             operator.Parameters{},
 			}
 			got, err := r.Reconcile(context.Background(), tt.request)
 			if (err != nil) != tt.wantErr {
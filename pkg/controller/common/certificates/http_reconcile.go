@@ -26,6 +26,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
 	netutil "github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
 )
 
@@ -58,11 +59,6 @@ func (r Reconciler) ReconcileInternalHTTPCerts(ctx context.Context, ca *CA, cust
 	log := ulog.FromContext(ctx)
 	ownerNSN := k8s.ExtractNamespacedName(r.Owner)
 
-	watchKey := CertificateWatchKey(r.Namer, ownerNSN.Name)
-	if err := ReconcileCustomCertWatch(r.DynamicWatches, watchKey, ownerNSN, r.TLSOptions.Certificate); err != nil {
-		return nil, err
-	}
-
 	secret := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: ownerNSN.Namespace,
@@ -81,16 +77,20 @@ func (r Reconciler) ReconcileInternalHTTPCerts(ctx context.Context, ca *CA, cust
 		secret.Labels = make(map[string]string)
 	}
 
-	// TODO: reconcile annotations?
 	needsUpdate := false
 
-	// ensure our labels are set on the secret.
-	for k, v := range r.Labels {
+	// ensure our labels, and the ones configured by the user for the secrets of the owner, are set on the secret.
+	userLabels, userAnnotations := reconciler.UserSecretMetadata(ctx, r.Owner)
+	for k, v := range maps.Merge(userLabels, r.Labels) {
 		if current, ok := secret.Labels[k]; !ok || current != v {
 			secret.Labels[k] = v
 			needsUpdate = true
 		}
 	}
+	if !maps.IsSubset(userAnnotations, secret.Annotations) {
+		secret.Annotations = maps.Merge(secret.Annotations, userAnnotations)
+		needsUpdate = true
+	}
 
 	if err := controllerutil.SetControllerReference(r.Owner, &secret, scheme.Scheme); err != nil {
 		return nil, err
@@ -468,6 +468,110 @@ func TestReconcileInternalHTTPCerts(t *testing.T) {
 	}
 }
 
+func TestReconcileInternalHTTPCerts_SubjectAlternativeNames(t *testing.T) {
+	c := k8s.NewFakeClient()
+	es := testES.DeepCopy()
+	reconcile := func(sans ...commonv1.SubjectAlternativeName) *x509.Certificate {
+		t.Helper()
+		es.Spec.HTTP.TLS.SelfSignedCertificate = &commonv1.SelfSignedCertificate{SubjectAlternativeNames: sans}
+		got, err := Reconciler{
+			K8sClient:      c,
+			DynamicWatches: watches.NewDynamicWatches(),
+			Owner:          es,
+			TLSOptions:     es.Spec.HTTP.TLS,
+			Namer:          esv1.ESNamer,
+			Labels:         map[string]string{},
+			Services:       []corev1.Service{testSvc},
+			CertRotation: RotationParams{
+				Validity:     DefaultCertValidity,
+				RotateBefore: DefaultRotateBefore,
+			},
+		}.ReconcileInternalHTTPCerts(context.Background(), testCA, nil)
+		require.NoError(t, err)
+		certs, err := ParsePEMCerts(got.Data[CertFileName])
+		require.NoError(t, err)
+		require.NotEmpty(t, certs)
+		return certs[0]
+	}
+
+	// the user-provided SANs are included in the generated certificate
+	cert := reconcile(commonv1.SubjectAlternativeName{DNS: "search.example.com"}, commonv1.SubjectAlternativeName{IP: "10.0.0.1"})
+	require.Contains(t, cert.DNSNames, "search.example.com")
+	require.Contains(t, cert.IPAddresses, net.ParseIP("10.0.0.1").To4())
+
+	// the certificate is kept as long as the SANs do not change
+	require.Equal(t, cert.Raw, reconcile(commonv1.SubjectAlternativeName{DNS: "search.example.com"}, commonv1.SubjectAlternativeName{IP: "10.0.0.1"}).Raw)
+
+	// and reissued with the new SANs otherwise
+	reissued := reconcile(commonv1.SubjectAlternativeName{DNS: "logs.example.com"})
+	require.NotEqual(t, cert.SerialNumber, reissued.SerialNumber)
+	require.Contains(t, reissued.DNSNames, "logs.example.com")
+	require.NotContains(t, reissued.DNSNames, "search.example.com")
+	require.NotContains(t, reissued.IPAddresses, net.ParseIP("10.0.0.1").To4())
+}
+
+func TestReconcileInternalHTTPCerts_RotationParams(t *testing.T) {
+	c := k8s.NewFakeClient()
+	reconcile := func(rotation RotationParams) *x509.Certificate {
+		t.Helper()
+		got, err := Reconciler{
+			K8sClient:      c,
+			DynamicWatches: watches.NewDynamicWatches(),
+			Owner:          &testES,
+			TLSOptions:     testES.Spec.HTTP.TLS,
+			Namer:          esv1.ESNamer,
+			Labels:         map[string]string{},
+			Services:       []corev1.Service{testSvc},
+			CertRotation:   rotation,
+		}.ReconcileInternalHTTPCerts(context.Background(), testCA, nil)
+		require.NoError(t, err)
+		certs, err := ParsePEMCerts(got.Data[CertFileName])
+		require.NoError(t, err)
+		require.NotEmpty(t, certs)
+		return certs[0]
+	}
+
+	// the certificate is issued with the configured validity
+	now := time.Now()
+	cert := reconcile(RotationParams{Validity: 2 * time.Hour, RotateBefore: 30 * time.Minute})
+	require.WithinDuration(t, now.Add(2*time.Hour), cert.NotAfter, time.Minute)
+
+	// and kept as long as it does not expire within the configured lead time
+	require.Equal(t, cert.Raw, reconcile(RotationParams{Validity: 4 * time.Hour, RotateBefore: time.Hour}).Raw)
+
+	// it is reissued once it expires within the lead time
+	now = time.Now()
+	reissued := reconcile(RotationParams{Validity: 4 * time.Hour, RotateBefore: 3 * time.Hour})
+	require.NotEqual(t, cert.SerialNumber, reissued.SerialNumber)
+	require.WithinDuration(t, now.Add(4*time.Hour), reissued.NotAfter, time.Minute)
+	require.Equal(t, reissued.Raw, reconcile(RotationParams{Validity: 4 * time.Hour, RotateBefore: 3 * time.Hour}).Raw)
+}
+
+func TestReconcileInternalHTTPCerts_UserSecretMetadata(t *testing.T) {
+	c := k8s.NewFakeClient()
+	es := testES.DeepCopy()
+	es.Annotations = map[string]string{
+		reconciler.SecretLabelsAnnotation:      `{"backup.example.com/include": "true", "common.k8s.elastic.co/type": "other"}`,
+		reconciler.SecretAnnotationsAnnotation: `{"scanner.example.com/policy": "strict"}`,
+	}
+	got, err := Reconciler{
+		K8sClient:      c,
+		DynamicWatches: watches.NewDynamicWatches(),
+		Owner:          es,
+		TLSOptions:     es.Spec.HTTP.TLS,
+		Namer:          esv1.ESNamer,
+		Labels:         map[string]string{"common.k8s.elastic.co/type": "elasticsearch"},
+		Services:       []corev1.Service{testSvc},
+		CertRotation: RotationParams{
+			Validity:     DefaultCertValidity,
+			RotateBefore: DefaultRotateBefore,
+		},
+	}.ReconcileInternalHTTPCerts(context.Background(), testCA, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"backup.example.com/include": "true", "common.k8s.elastic.co/type": "elasticsearch"}, got.Labels)
+	require.Equal(t, map[string]string{"scanner.example.com/policy": "strict"}, got.Annotations)
+}
+
 func Test_createValidatedHTTPCertificateTemplate(t *testing.T) {
 	sanDNS1 := "my.dns.com"
 	sanDNS2 := "my.second.dns.com"
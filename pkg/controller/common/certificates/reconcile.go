@@ -61,8 +61,14 @@ func (r Reconciler) ReconcileCAAndHTTPCerts(ctx context.Context) (*CertificatesS
 		return nil, results.WithError(r.removeCAAndHTTPCertsSecrets(ctx))
 	}
 
+	// watch the user-provided certificate secret before validating it, so that fixing an invalid one triggers a reconciliation
+	ownerNSN := k8s.ExtractNamespacedName(r.Owner)
+	if err := ReconcileCustomCertWatch(r.DynamicWatches, CertificateWatchKey(r.Namer, ownerNSN.Name), ownerNSN, r.TLSOptions.Certificate); err != nil {
+		return nil, results.WithError(err)
+	}
+
 	// check for custom certificates first
-	customCerts, err := validCustomCertificatesOrNil(r.K8sClient, k8s.ExtractNamespacedName(r.Owner), r.TLSOptions)
+	customCerts, err := validCustomCertificatesOrNil(r.K8sClient, ownerNSN, r.TLSOptions)
 	if err != nil {
 		return nil, results.WithError(err)
 	}
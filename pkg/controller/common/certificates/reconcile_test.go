@@ -13,6 +13,9 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -137,3 +140,42 @@ func TestReconcileCAAndHTTPCerts(t *testing.T) {
 		require.True(t, apierrors.IsNotFound(c.Get(context.Background(), nsn, &s)))
 	}
 }
+
+func TestReconcileCAAndHTTPCerts_InvalidCustomCertificate(t *testing.T) {
+	invalidCert := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: obj.Namespace, Name: "my-cert"},
+		Data: map[string][]byte{
+			CertFileName: []byte("not a certificate"),
+		},
+	}
+	dynamicWatches := watches.NewDynamicWatches()
+	r := Reconciler{
+		K8sClient:      k8s.NewFakeClient(&invalidCert),
+		DynamicWatches: dynamicWatches,
+		Owner:          &obj,
+		TLSOptions:     commonv1.TLSOptions{Certificate: commonv1.SecretRef{SecretName: "my-cert"}},
+		Namer:          esv1.ESNamer,
+		Labels:         labels,
+		CACertRotation: rotation,
+		CertRotation:   rotation,
+	}
+	_, results := r.ReconcileCAAndHTTPCerts(context.Background())
+	_, err := results.Aggregate()
+	require.ErrorIs(t, err, ErrInvalidCustomCertificate)
+
+	// the user-provided secret is watched nevertheless, fixing it triggers a reconciliation of the owner
+	require.Equal(t, []string{CertificateWatchKey(esv1.ESNamer, obj.Name)}, dynamicWatches.Secrets.Registrations())
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	fixedCert := invalidCert.DeepCopy()
+	fixedCert.Data = map[string][]byte{CertFileName: []byte("fixed")}
+	dynamicWatches.Secrets.Update(context.Background(), event.UpdateEvent{ObjectOld: &invalidCert, ObjectNew: fixedCert}, q)
+	require.Equal(t, 1, q.Len())
+	item, _ := q.Get()
+	require.Equal(t, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&obj)}, item)
+
+	// a missing secret is also reported as an invalid custom certificate
+	r.K8sClient = k8s.NewFakeClient()
+	_, results = r.ReconcileCAAndHTTPCerts(context.Background())
+	_, err = results.Aggregate()
+	require.ErrorIs(t, err, ErrInvalidCustomCertificate)
+}
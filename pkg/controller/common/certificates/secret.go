@@ -11,6 +11,7 @@ import (
 
 	pkgerrors "github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -191,6 +192,9 @@ func GetSecretFromRef(c k8s.Client, owner types.NamespacedName, secretRef common
 	return &secret, nil
 }
 
+// ErrInvalidCustomCertificate is returned when the user-provided certificate secret is missing or cannot be used.
+var ErrInvalidCustomCertificate = errors.New("invalid user-provided certificate")
+
 // validCustomCertificatesOrNil returns the custom certificates to use or nil if there is none specified
 func validCustomCertificatesOrNil(
 	c k8s.Client,
@@ -198,8 +202,15 @@ func validCustomCertificatesOrNil(
 	tls commonv1.TLSOptions,
 ) (*CertificatesSecret, error) {
 	secret, err := GetSecretFromRef(c, owner, tls.Certificate)
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCustomCertificate, err)
+	}
 	if err != nil || secret == nil {
 		return nil, err
 	}
-	return NewCertificatesSecret(*secret)
+	certs, err := NewCertificatesSecret(*secret)
+	if err != nil {
+		return nil, fmt.Errorf("%w in secret %s/%s: %w", ErrInvalidCustomCertificate, secret.Namespace, secret.Name, err)
+	}
+	return certs, nil
 }
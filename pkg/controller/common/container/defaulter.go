@@ -96,6 +96,57 @@ func (d Defaulter) WithReadinessProbe(readinessProbe *corev1.Probe) Defaulter {
 	return d
 }
 
+// WithMergedReadinessProbe sets up the given readiness probe, using the values already provided in the base container
+// to override the corresponding defaults. Thresholds left unset and an empty probe handler inherit the given defaults.
+func (d Defaulter) WithMergedReadinessProbe(readinessProbe *corev1.Probe) Defaulter {
+	switch {
+	case d.base.ReadinessProbe == nil:
+		d.base.ReadinessProbe = readinessProbe
+	case readinessProbe != nil:
+		d.base.ReadinessProbe = mergeProbe(*d.base.ReadinessProbe, *readinessProbe)
+	}
+	return d
+}
+
+// WithMergedLivenessProbe sets up the given liveness probe, using the values already provided in the base container
+// to override the corresponding defaults. Thresholds left unset and an empty probe handler inherit the given defaults.
+func (d Defaulter) WithMergedLivenessProbe(livenessProbe *corev1.Probe) Defaulter {
+	switch {
+	case d.base.LivenessProbe == nil:
+		d.base.LivenessProbe = livenessProbe
+	case livenessProbe != nil:
+		d.base.LivenessProbe = mergeProbe(*d.base.LivenessProbe, *livenessProbe)
+	}
+	return d
+}
+
+// mergeProbe returns a copy of the given probe where zero-valued fields are replaced by the default ones.
+func mergeProbe(probe corev1.Probe, defaults corev1.Probe) *corev1.Probe {
+	merged := probe.DeepCopy()
+	if merged.ProbeHandler == (corev1.ProbeHandler{}) {
+		merged.ProbeHandler = *defaults.ProbeHandler.DeepCopy()
+	}
+	if merged.InitialDelaySeconds == 0 {
+		merged.InitialDelaySeconds = defaults.InitialDelaySeconds
+	}
+	if merged.TimeoutSeconds == 0 {
+		merged.TimeoutSeconds = defaults.TimeoutSeconds
+	}
+	if merged.PeriodSeconds == 0 {
+		merged.PeriodSeconds = defaults.PeriodSeconds
+	}
+	if merged.SuccessThreshold == 0 {
+		merged.SuccessThreshold = defaults.SuccessThreshold
+	}
+	if merged.FailureThreshold == 0 {
+		merged.FailureThreshold = defaults.FailureThreshold
+	}
+	if merged.TerminationGracePeriodSeconds == nil {
+		merged.TerminationGracePeriodSeconds = defaults.TerminationGracePeriodSeconds
+	}
+	return merged
+}
+
 // envExists checks if an env var with the given name already exists in the provided slice.
 func (d Defaulter) envExists(name string) bool {
 	for _, v := range d.base.Env {
@@ -11,18 +11,46 @@ import (
 
 	"go.elastic.co/apm/module/apmzap/v2"
 	"go.elastic.co/apm/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	logconf "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 // NewController creates a new controller with the given name, reconciler and parameters and registers it with the manager.
-func NewController(mgr manager.Manager, name string, r reconcile.Reconciler, p operator.Parameters) (controller.Controller, error) {
-	return controller.New(name, mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: p.MaxConcurrentReconciles})
+// The reconciliations are instrumented with Prometheus metrics, which are not reported anymore once the reconciled
+// resource, of the same type as the given one, is deleted.
+func NewController(
+	mgr manager.Manager,
+	name string,
+	r reconcile.Reconciler,
+	resource client.Object,
+	p operator.Parameters,
+) (controller.Controller, error) {
+	return NewControllerWithRateLimiter(mgr, name, r, resource, p, nil)
+}
+
+// NewControllerWithRateLimiter creates a new controller like NewController, whose failed reconciliations are requeued
+// according to the given rate limiter. The default controller rate limiter applies if nil.
+func NewControllerWithRateLimiter(
+	mgr manager.Manager,
+	name string,
+	r reconcile.Reconciler,
+	resource client.Object,
+	p operator.Parameters,
+	rateLimiter ratelimiter.RateLimiter,
+) (controller.Controller, error) {
+	return controller.New(name, mgr, controller.Options{
+		Reconciler:              metrics.InstrumentReconciler(name, r, mgr.GetClient(), resource),
+		MaxConcurrentReconciles: p.MaxConcurrentReconciles,
+		RateLimiter:             rateLimiter,
+	})
 }
 
 // NewReconciliationContext increments iteration, creates an apm transaction and initiates the logger. Returns context
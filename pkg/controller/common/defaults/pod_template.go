@@ -121,6 +121,20 @@ func (b *PodTemplateBuilder) WithReadinessProbe(readinessProbe corev1.Probe) *Po
 	return b
 }
 
+// WithMergedReadinessProbe sets up the given readiness probe. If a readiness probe is already provided in the template,
+// the values it specifies take precedence, and the ones it leaves unset are inherited from the given probe.
+func (b *PodTemplateBuilder) WithMergedReadinessProbe(readinessProbe corev1.Probe) *PodTemplateBuilder {
+	b.containerDefaulter.WithMergedReadinessProbe(&readinessProbe)
+	return b
+}
+
+// WithMergedLivenessProbe sets up the given liveness probe. If a liveness probe is already provided in the template,
+// the values it specifies take precedence, and the ones it leaves unset are inherited from the given probe.
+func (b *PodTemplateBuilder) WithMergedLivenessProbe(livenessProbe corev1.Probe) *PodTemplateBuilder {
+	b.containerDefaulter.WithMergedLivenessProbe(&livenessProbe)
+	return b
+}
+
 // WithAffinity sets a default affinity, unless already provided in the template.
 // An empty affinity in the spec is not overridden.
 func (b *PodTemplateBuilder) WithAffinity(affinity *corev1.Affinity) *PodTemplateBuilder {
@@ -130,6 +144,14 @@ func (b *PodTemplateBuilder) WithAffinity(affinity *corev1.Affinity) *PodTemplat
 	return b
 }
 
+// WithTopologySpreadConstraints sets default topology spread constraints, unless already provided in the template.
+func (b *PodTemplateBuilder) WithTopologySpreadConstraints(constraints ...corev1.TopologySpreadConstraint) *PodTemplateBuilder {
+	if len(b.PodTemplate.Spec.TopologySpreadConstraints) == 0 {
+		b.PodTemplate.Spec.TopologySpreadConstraints = constraints
+	}
+	return b
+}
+
 // WithPorts appends the given ports to the Container ports, unless already provided in the template.
 func (b *PodTemplateBuilder) WithPorts(ports []corev1.ContainerPort) *PodTemplateBuilder {
 	b.containerDefaulter.WithPorts(ports)
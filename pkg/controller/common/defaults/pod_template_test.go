@@ -313,6 +313,51 @@ func TestPodTemplateBuilder_WithAffinity(t *testing.T) {
 	}
 }
 
+func TestPodTemplateBuilder_WithTopologySpreadConstraints(t *testing.T) {
+	defaultConstraint := corev1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       "topology.kubernetes.io/zone",
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+	}
+
+	containerName := "mycontainer"
+	tests := []struct {
+		name        string
+		PodTemplate corev1.PodTemplateSpec
+		constraints []corev1.TopologySpreadConstraint
+		want        []corev1.TopologySpreadConstraint
+	}{
+		{
+			name:        "set default topology spread constraints",
+			PodTemplate: corev1.PodTemplateSpec{},
+			constraints: []corev1.TopologySpreadConstraint{defaultConstraint},
+			want:        []corev1.TopologySpreadConstraint{defaultConstraint},
+		},
+		{
+			name: "don't override user-provided topology spread constraints",
+			PodTemplate: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+						{MaxSkew: 2, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.ScheduleAnyway},
+					},
+				},
+			},
+			constraints: []corev1.TopologySpreadConstraint{defaultConstraint},
+			want: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 2, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.ScheduleAnyway},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewPodTemplateBuilder(tt.PodTemplate, containerName)
+			if got := b.WithTopologySpreadConstraints(tt.constraints...).PodTemplate.Spec.TopologySpreadConstraints; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PodTemplateBuilder.WithTopologySpreadConstraints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPodTemplateBuilder_WithPorts(t *testing.T) {
 	containerName := "mycontainer"
 	tests := []struct {
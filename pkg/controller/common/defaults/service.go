@@ -5,19 +5,31 @@
 package defaults
 
 import (
+	"context"
+
 	v1 "k8s.io/api/core/v1"
 
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
 )
 
 // SetServiceDefaults updates the service with the provided defaults if they are not already set.
+// User-provided labels of the service take precedence over the default labels they are merged with.
 func SetServiceDefaults(
+	ctx context.Context,
 	svc *v1.Service,
 	defaultLabels map[string]string,
 	defaultSelector map[string]string,
 	defaultPorts []v1.ServicePort,
 ) *v1.Service {
-	svc.Labels = maps.MergePreservingExistingKeys(svc.Labels, defaultLabels)
+	for k, v := range defaultLabels {
+		if existing, exists := svc.Labels[k]; exists && existing != v {
+			ulog.FromContext(ctx).V(1).Info("User-provided service label overrides the operator default",
+				"namespace", svc.Namespace, "service_name", svc.Name, "label", k, "value", existing, "default", v)
+		}
+	}
+	// do not mutate the labels of the service template the service may have been built from
+	svc.Labels = maps.MergePreservingExistingKeys(maps.Merge(nil, svc.Labels), defaultLabels)
 
 	if svc.Spec.Selector == nil {
 		svc.Spec.Selector = defaultSelector
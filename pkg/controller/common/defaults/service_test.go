@@ -5,6 +5,7 @@
 package defaults
 
 import (
+	"context"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -51,11 +52,27 @@ func TestSetServiceDefaults(t *testing.T) {
 				return svc
 			},
 		},
+		{
+			name: "user-provided labels and annotations are merged with default labels",
+			inSvc: func() *corev1.Service {
+				svc := mkService()
+				svc.Labels = map[string]string{"mesh": "enabled"}
+				svc.Annotations = map[string]string{"cost-center": "search"}
+				return svc
+			},
+			defaultLabels: map[string]string{"foo": "bar"},
+			wantSvc: func() *corev1.Service {
+				svc := mkService()
+				svc.Labels = map[string]string{"mesh": "enabled", "foo": "bar"}
+				svc.Annotations = map[string]string{"cost-center": "search"}
+				return svc
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			haveSvc := SetServiceDefaults(tc.inSvc(), tc.defaultLabels, tc.defaultSelector, tc.defaultPorts)
+			haveSvc := SetServiceDefaults(context.Background(), tc.inSvc(), tc.defaultLabels, tc.defaultSelector, tc.defaultPorts)
 			compare.JSONEqual(t, tc.wantSvc(), haveSvc)
 		})
 	}
@@ -28,6 +28,8 @@ type Params struct {
 	Replicas             int32
 	RevisionHistoryLimit *int32
 	Strategy             appsv1.DeploymentStrategy
+	// Paused prevents changes to the Pod template from being rolled out.
+	Paused bool
 }
 
 // New creates a Deployment from the given params.
@@ -46,6 +48,7 @@ func New(params Params) appsv1.Deployment {
 			Template: params.PodTemplateSpec,
 			Replicas: &params.Replicas,
 			Strategy: params.Strategy,
+			Paused:   params.Paused,
 		},
 	}
 }
@@ -8,10 +8,15 @@ package events
 const (
 	// EventReasonDeprecated describes events that were due to a deprecated resource being submitted by the user.
 	EventReasonDeprecated = "Deprecated"
+	// EventReasonDryRun describes events reporting a change the operator would apply outside of dry-run mode.
+	EventReasonDryRun = "DryRun"
 	// EventReasonDelayed describes events where a requested change was delayed e.g. to prevent data loss.
 	EventReasonDelayed = "Delayed"
 	// EventReasonInvalidLicense describes events where a user configured an invalid license for the operator.
 	EventReasonInvalidLicense = "InvalidLicense"
+	// EventReasonPaused describes events where the reconciliation of a resource is skipped because it is not managed
+	// by the operator anymore.
+	EventReasonPaused = "Paused"
 	// EventReasonStalled describes events where a requested change is stalled and may not make progress without user
 	// intervention. There are transient states e.g. during a nodeSet rename where shards still do not have a place to
 	// move to until the new nodes come up and Elasticsearch will report a stalled shutdown. There are however also
@@ -6,6 +6,7 @@ package keystore
 
 import (
 	"bytes"
+	"errors"
 	"text/template"
 
 	corev1 "k8s.io/api/core/v1"
@@ -27,6 +28,10 @@ type InitContainerParameters struct {
 	KeystoreAddCommand string
 	// Keystore create command
 	KeystoreCreateCommand string
+	// Keystore add command used if the keystore is protected by the password of the KEYSTORE_PASSWORD environment variable
+	PasswordProtectedKeystoreAddCommand string
+	// Keystore create command used if the keystore is protected by the password of the KEYSTORE_PASSWORD environment variable
+	PasswordProtectedKeystoreCreateCommand string
 	// CustomScript is the bash script to overrides the default Keystore script
 	CustomScript string
 	// Resources for the init container
@@ -76,14 +81,25 @@ echo "Keystore initialization successful."
 var scriptTemplate = template.Must(template.New("").Parse(script))
 
 // initContainer returns an init container that executes a bash script
-// to load secure settings in a Keystore.
+// to load secure settings in a Keystore, optionally protected by the given password.
 func initContainer(
 	secureSettingsSecret volume.SecretVolume,
 	parameters InitContainerParameters,
+	password *Password,
 ) (corev1.Container, error) {
 	privileged := false
 	tplBuffer := bytes.Buffer{}
 
+	var env []corev1.EnvVar
+	if password != nil {
+		if parameters.PasswordProtectedKeystoreCreateCommand == "" || parameters.PasswordProtectedKeystoreAddCommand == "" {
+			return corev1.Container{}, errors.New("password protected keystores are not supported")
+		}
+		parameters.KeystoreCreateCommand = parameters.PasswordProtectedKeystoreCreateCommand
+		parameters.KeystoreAddCommand = parameters.PasswordProtectedKeystoreAddCommand
+		env = append(env, password.EnvVar)
+	}
+
 	if err := getScriptTemplate(parameters.CustomScript).Execute(&tplBuffer, parameters); err != nil {
 		return corev1.Container{}, err
 	}
@@ -100,6 +116,7 @@ func initContainer(
 			// access secure settings
 			secureSettingsSecret.VolumeMount(),
 		},
+		Env:       env,
 		Resources: parameters.Resources,
 	}
 
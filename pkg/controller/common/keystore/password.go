@@ -0,0 +1,88 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package keystore
+
+import (
+	"context"
+	"fmt"
+
+	pkgerrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	// PasswordEnvVarName is the environment variable holding the password protecting the keystore, read by the keystore
+	// init container and by the Docker entrypoint of the application.
+	PasswordEnvVarName = "KEYSTORE_PASSWORD"
+	// PasswordSecretKey is the key of the user-provided Secret holding the password protecting the keystore.
+	PasswordSecretKey = "password"
+)
+
+// HasPassword is implemented by the Elastic Stack applications whose keystore can be protected by a password provided
+// by the user in a Secret.
+type HasPassword interface {
+	KeystorePassword() *commonv1.SecretRef
+}
+
+// Password is the password protecting a keystore.
+type Password struct {
+	// EnvVar exposes the password to the keystore init container and to the application.
+	EnvVar corev1.EnvVar
+	// Version is the resource version of the Secret holding the password, to rotate the Pods when it changes.
+	Version string
+}
+
+// PasswordWatchName returns the name of the watch registered on the Secret holding the keystore password.
+func PasswordWatchName(namespacedName types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-keystore-password", namespacedName.Namespace, namespacedName.Name)
+}
+
+// reconcilePassword returns the password protecting the keystore of the given application, or nil if its keystore is
+// not password protected. The user-provided Secret holding the password is watched to rotate the Pods on any change.
+func reconcilePassword(ctx context.Context, r driver.Interface, hasKeystore HasKeystore) (*Password, error) {
+	var secretRef *commonv1.SecretRef
+	if hasPassword, ok := hasKeystore.(HasPassword); ok {
+		secretRef = hasPassword.KeystorePassword()
+	}
+
+	watcher := k8s.ExtractNamespacedName(hasKeystore)
+	var userSecrets []string
+	if secretRef != nil {
+		userSecrets = append(userSecrets, secretRef.SecretName)
+	}
+	if err := watches.WatchUserProvidedSecrets(watcher, r.DynamicWatches(), PasswordWatchName(watcher), userSecrets); err != nil {
+		return nil, err
+	}
+	if secretRef == nil {
+		return nil, nil
+	}
+
+	var secret corev1.Secret
+	secretKey := types.NamespacedName{Namespace: hasKeystore.GetNamespace(), Name: secretRef.SecretName}
+	if err := r.K8sClient().Get(ctx, secretKey, &secret); err != nil {
+		return nil, err
+	}
+	if len(secret.Data[PasswordSecretKey]) == 0 {
+		return nil, pkgerrors.Errorf("no %s entry found in keystore password secret %s", PasswordSecretKey, secretKey)
+	}
+	return &Password{
+		EnvVar: corev1.EnvVar{
+			Name: PasswordEnvVarName,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretRef.SecretName},
+					Key:                  PasswordSecretKey,
+				},
+			},
+		},
+		Version: secret.GetResourceVersion(),
+	}, nil
+}
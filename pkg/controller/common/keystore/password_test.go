@@ -0,0 +1,228 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package keystore
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+var (
+	testPasswordSecret = corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "namespace",
+			Name:            "keystore-password",
+			ResourceVersion: "42",
+		},
+		Data: map[string][]byte{
+			PasswordSecretKey: []byte("s3cr3t"),
+		},
+	}
+	testPasswordEnvVar = corev1.EnvVar{
+		Name: "KEYSTORE_PASSWORD",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "keystore-password"},
+				Key:                  "password",
+			},
+		},
+	}
+)
+
+func fakePasswordProtectedInitContainersParameters() InitContainerParameters {
+	parameters := fakeFlagInitContainersParameters(true)
+	parameters.PasswordProtectedKeystoreCreateCommand = `/keystore/bin/keystore create -p <<< "${KEYSTORE_PASSWORD}"`
+	parameters.PasswordProtectedKeystoreAddCommand = `/keystore/bin/keystore add "$key" "$filename" <<< "${KEYSTORE_PASSWORD}"`
+	return parameters
+}
+
+func testElasticsearch(keystorePassword *commonv1.SecretRef, secureSettings ...commonv1.SecretSource) esv1.Elasticsearch {
+	return esv1.Elasticsearch{
+		TypeMeta:   metav1.TypeMeta{Kind: esv1.Kind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "elasticsearch"},
+		Spec: esv1.ElasticsearchSpec{
+			SecureSettings:   secureSettings,
+			KeystorePassword: keystorePassword,
+		},
+	}
+}
+
+func TestReconcileResources_password(t *testing.T) {
+	tests := []struct {
+		name                    string
+		client                  k8s.Client
+		es                      esv1.Elasticsearch
+		initContainerParameters InitContainerParameters
+		wantNil                 bool
+		wantScript              string
+		wantPassword            *Password
+		wantSecureSettings      map[string][]byte
+		wantErr                 bool
+	}{
+		{
+			name:                    "no password and no secure settings: no resources",
+			client:                  k8s.NewFakeClient(&testPasswordSecret),
+			es:                      testElasticsearch(nil),
+			initContainerParameters: fakePasswordProtectedInitContainersParameters(),
+			wantNil:                 true,
+		},
+		{
+			name:                    "no password: unprotected keystore",
+			client:                  k8s.NewFakeClient(&testSecureSettingsSecret),
+			es:                      testElasticsearch(nil, testSecureSettingsSecretRef),
+			initContainerParameters: fakePasswordProtectedInitContainersParameters(),
+			wantScript: `#!/usr/bin/env bash
+
+set -eux
+
+echo "Initializing keystore."
+
+# create a keystore in the default data path
+/keystore/bin/keystore create
+
+# add all existing secret entries into it
+for filename in  /foo/secret/*; do
+	[[ -e "$filename" ]] || continue # glob does not match
+	key=$(basename "$filename")
+	echo "Adding "$key" to the keystore."
+	/keystore/bin/keystore add "$key" "$filename"
+done
+
+echo "Keystore initialization successful."
+`,
+			wantSecureSettings: testSecureSettingsSecret.Data,
+		},
+		{
+			name:                    "password and secure settings: protected keystore",
+			client:                  k8s.NewFakeClient(&testSecureSettingsSecret, &testPasswordSecret),
+			es:                      testElasticsearch(&commonv1.SecretRef{SecretName: "keystore-password"}, testSecureSettingsSecretRef),
+			initContainerParameters: fakePasswordProtectedInitContainersParameters(),
+			wantScript: `#!/usr/bin/env bash
+
+set -eux
+
+echo "Initializing keystore."
+
+# create a keystore in the default data path
+/keystore/bin/keystore create -p <<< "${KEYSTORE_PASSWORD}"
+
+# add all existing secret entries into it
+for filename in  /foo/secret/*; do
+	[[ -e "$filename" ]] || continue # glob does not match
+	key=$(basename "$filename")
+	echo "Adding "$key" to the keystore."
+	/keystore/bin/keystore add "$key" "$filename" <<< "${KEYSTORE_PASSWORD}"
+done
+
+echo "Keystore initialization successful."
+`,
+			wantPassword:       &Password{EnvVar: testPasswordEnvVar, Version: "42"},
+			wantSecureSettings: testSecureSettingsSecret.Data,
+		},
+		{
+			name:                    "password without secure settings: empty protected keystore",
+			client:                  k8s.NewFakeClient(&testPasswordSecret),
+			es:                      testElasticsearch(&commonv1.SecretRef{SecretName: "keystore-password"}),
+			initContainerParameters: fakePasswordProtectedInitContainersParameters(),
+			wantScript: `#!/usr/bin/env bash
+
+set -eux
+
+echo "Initializing keystore."
+
+# create a keystore in the default data path
+/keystore/bin/keystore create -p <<< "${KEYSTORE_PASSWORD}"
+
+# add all existing secret entries into it
+for filename in  /foo/secret/*; do
+	[[ -e "$filename" ]] || continue # glob does not match
+	key=$(basename "$filename")
+	echo "Adding "$key" to the keystore."
+	/keystore/bin/keystore add "$key" "$filename" <<< "${KEYSTORE_PASSWORD}"
+done
+
+echo "Keystore initialization successful."
+`,
+			wantPassword:       &Password{EnvVar: testPasswordEnvVar, Version: "42"},
+			wantSecureSettings: map[string][]byte{},
+		},
+		{
+			name:                    "password secret not found: error",
+			client:                  k8s.NewFakeClient(),
+			es:                      testElasticsearch(&commonv1.SecretRef{SecretName: "keystore-password"}),
+			initContainerParameters: fakePasswordProtectedInitContainersParameters(),
+			wantErr:                 true,
+		},
+		{
+			name: "no password entry in the password secret: error",
+			client: k8s.NewFakeClient(&corev1.Secret{
+				ObjectMeta: testPasswordSecret.ObjectMeta,
+				Data:       map[string][]byte{"pwd": []byte("s3cr3t")},
+			}),
+			es:                      testElasticsearch(&commonv1.SecretRef{SecretName: "keystore-password"}),
+			initContainerParameters: fakePasswordProtectedInitContainersParameters(),
+			wantErr:                 true,
+		},
+		{
+			name:                    "password protection not supported by the application: error",
+			client:                  k8s.NewFakeClient(&testPasswordSecret),
+			es:                      testElasticsearch(&commonv1.SecretRef{SecretName: "keystore-password"}),
+			initContainerParameters: fakeFlagInitContainersParameters(true),
+			wantErr:                 true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDriver := driver.TestDriver{
+				Client:       tt.client,
+				Watches:      watches.NewDynamicWatches(),
+				FakeRecorder: record.NewFakeRecorder(1000),
+			}
+			resources, err := ReconcileResources(context.Background(), testDriver, &tt.es, esv1.ESNamer, nil, tt.initContainerParameters)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			// the password secret is watched to rotate the Pods on any change
+			esKey := k8s.ExtractNamespacedName(&tt.es)
+			require.Equal(t, tt.es.KeystorePassword() != nil, slices.Contains(testDriver.Watches.Secrets.Registrations(), PasswordWatchName(esKey)))
+
+			if tt.wantNil {
+				require.Nil(t, resources)
+				return
+			}
+			require.NotNil(t, resources)
+			require.Equal(t, []string{"/usr/bin/env", "bash", "-c", tt.wantScript}, resources.InitContainer.Command)
+			require.Equal(t, tt.wantPassword, resources.Password)
+			if tt.wantPassword == nil {
+				require.Empty(t, resources.InitContainer.Env)
+			} else {
+				require.Equal(t, []corev1.EnvVar{testPasswordEnvVar}, resources.InitContainer.Env)
+			}
+
+			var secureSettings corev1.Secret
+			require.NoError(t, tt.client.Get(context.Background(), types.NamespacedName{Namespace: "namespace", Name: secureSettingsSecretName(esv1.ESNamer, &tt.es)}, &secureSettings))
+			require.Equal(t, len(tt.wantSecureSettings), len(secureSettings.Data))
+			for k, v := range tt.wantSecureSettings {
+				require.Equal(t, v, secureSettings.Data[k])
+			}
+		})
+	}
+}
@@ -24,6 +24,8 @@ type Resources struct {
 	InitContainer corev1.Container
 	// version of the secret provided by the user
 	Version string
+	// password protecting the keystore, if any
+	Password *Password
 }
 
 // HasKeystore interface represents an Elastic Stack application that offers a keystore which in ECK
@@ -51,6 +53,7 @@ func WatchedSecretNames(hasKeystore HasKeystore) []commonv1.NamespacedSecretSour
 // in order to create a Keystore from a Secret containing secure settings provided by
 // the user and referenced in the Elastic Stack application spec.
 // It reconciles the backing secret with the API server and sets up the necessary watches.
+// If the application keystore is protected by a password, the keystore is created even if there is no secure setting.
 func ReconcileResources(
 	ctx context.Context,
 	r driver.Interface,
@@ -59,8 +62,13 @@ func ReconcileResources(
 	labels map[string]string,
 	initContainerParams InitContainerParameters,
 ) (*Resources, error) {
+	password, err := reconcilePassword(ctx, r, hasKeystore)
+	if err != nil {
+		return nil, err
+	}
+
 	// setup a volume from the user-provided secure settings secret
-	secretVolume, version, err := secureSettingsVolume(ctx, r, hasKeystore, labels, namer)
+	secretVolume, version, err := secureSettingsVolume(ctx, r, hasKeystore, labels, namer, password != nil)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +78,7 @@ func ReconcileResources(
 	}
 
 	// build an init container to create the keystore from the secure settings volume
-	initContainer, err := initContainer(*secretVolume, initContainerParams)
+	initContainer, err := initContainer(*secretVolume, initContainerParams, password)
 	if err != nil {
 		return nil, err
 	}
@@ -79,5 +87,6 @@ func ReconcileResources(
 		Volume:        secretVolume.Volume(),
 		InitContainer: initContainer,
 		Version:       version,
+		Password:      password,
 	}, nil
 }
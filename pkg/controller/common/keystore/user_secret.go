@@ -37,12 +37,14 @@ const secureSettingsSecretSuffix = "secure-settings"
 // The user-provided secrets are watched to reconcile on any change.
 // The user secret resource version is returned along with the volume, so that
 // any change in the user secret leads to pod rotation.
+// If keepEmpty is true, the volume is created even if there is no secure setting.
 func secureSettingsVolume(
 	ctx context.Context,
 	r driver.Interface,
 	hasKeystore HasKeystore,
 	labels map[string]string,
 	namer name.Namer,
+	keepEmpty bool,
 ) (*volume.SecretVolume, string, error) {
 	// setup (or remove) watches for the user-provided secret to reconcile on any change
 	watcher := k8s.ExtractNamespacedName(hasKeystore)
@@ -70,7 +72,7 @@ func secureSettingsVolume(
 		return nil, "", err
 	}
 
-	secret, err := reconcileSecureSettings(ctx, r.K8sClient(), hasKeystore, secrets, namer, labels)
+	secret, err := reconcileSecureSettings(ctx, r.K8sClient(), hasKeystore, secrets, namer, labels, keepEmpty)
 	if err != nil {
 		return nil, "", err
 	}
@@ -98,11 +100,20 @@ func reconcileSecureSettings(
 	hasKeystore HasKeystore,
 	userSecrets []corev1.Secret,
 	namer name.Namer,
-	labels map[string]string) (*corev1.Secret, error) {
+	labels map[string]string,
+	keepEmpty bool,
+) (*corev1.Secret, error) {
 	aggregatedData := map[string][]byte{}
+	// keep track of the secret each key comes from to detect collisions across secrets
+	keySources := map[string]types.NamespacedName{}
 
 	for _, s := range userSecrets {
+		source := k8s.ExtractNamespacedName(&s)
 		for k, v := range s.Data {
+			if existing, exists := keySources[k]; exists && existing != source {
+				return nil, pkgerrors.Errorf("secure setting %s is defined in both secure settings secrets %s and %s", k, existing, source)
+			}
+			keySources[k] = source
 			aggregatedData[k] = v
 		}
 	}
@@ -116,7 +127,7 @@ func reconcileSecureSettings(
 		},
 		Data: aggregatedData,
 	}
-	if len(aggregatedData) == 0 {
+	if len(aggregatedData) == 0 && !keepEmpty {
 		// no secure settings specified, delete any existing operator-managed settings secret
 		err := k8s.DeleteSecretIfExists(ctx, c, k8s.ExtractNamespacedName(&expected))
 		return nil, err
@@ -16,6 +16,7 @@ import (
 	"k8s.io/client-go/tools/record"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/comparison"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
@@ -102,7 +103,7 @@ func Test_secureSettingsVolume(t *testing.T) {
 				Watches:      tt.w,
 				FakeRecorder: record.NewFakeRecorder(1000),
 			}
-			vol, version, err := secureSettingsVolume(context.Background(), testDriver, &tt.kb, nil, kbNamer)
+			vol, version, err := secureSettingsVolume(context.Background(), testDriver, &tt.kb, nil, kbNamer, false)
 			require.NoError(t, err)
 			assert.Equal(t, tt.wantVolume, vol)
 			assert.Equal(t, tt.wantVersion, version)
@@ -273,17 +274,19 @@ func Test_reconcileSecureSettings(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "multiple user secrets, key conflict, last in wins",
+			name: "multiple user secrets, key conflict",
 			args: args{
 				c:           k8s.NewFakeClient(),
 				hasKeystore: kibanaFixture,
 				userSecrets: []corev1.Secret{
 					{
+						ObjectMeta: metav1.ObjectMeta{Name: "secret1", Namespace: "ns"},
 						Data: map[string][]byte{
 							"key1": []byte("value1"),
 						},
 					},
 					{
+						ObjectMeta: metav1.ObjectMeta{Name: "secret2", Namespace: "ns"},
 						Data: map[string][]byte{
 							"key1": []byte("value2"),
 						},
@@ -291,10 +294,34 @@ func Test_reconcileSecureSettings(t *testing.T) {
 				},
 				namer: kbNamer,
 			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "same user secret referenced twice",
+			args: args{
+				c:           k8s.NewFakeClient(),
+				hasKeystore: kibanaFixture,
+				userSecrets: []corev1.Secret{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "secret1", Namespace: "ns"},
+						Data: map[string][]byte{
+							"key1": []byte("value1"),
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "secret1", Namespace: "ns"},
+						Data: map[string][]byte{
+							"key1": []byte("value1"),
+						},
+					},
+				},
+				namer: kbNamer,
+			},
 			want: &corev1.Secret{
 				ObjectMeta: expectedMeta,
 				Data: map[string][]byte{
-					"key1": []byte("value2"),
+					"key1": []byte("value1"),
 				},
 			},
 			wantErr: false,
@@ -302,7 +329,7 @@ func Test_reconcileSecureSettings(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := reconcileSecureSettings(context.Background(), tt.args.c, tt.args.hasKeystore, tt.args.userSecrets, tt.args.namer, nil)
+			got, err := reconcileSecureSettings(context.Background(), tt.args.c, tt.args.hasKeystore, tt.args.userSecrets, tt.args.namer, nil, false)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("reconcileSecureSettings() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -440,3 +467,61 @@ func Test_retrieveUserSecrets(t *testing.T) {
 		})
 	}
 }
+
+func Test_secureSettingsVolumeWithSnapshotRepositories(t *testing.T) {
+	repositoryCredentials := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "namespace",
+			Name:      "s3-credentials",
+		},
+		Data: map[string][]byte{
+			"access-key": []byte("my-access-key"),
+			"secret-key": []byte("my-secret-key"),
+		},
+	}
+	es := esv1.Elasticsearch{
+		TypeMeta: metav1.TypeMeta{
+			Kind: esv1.Kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "namespace",
+			Name:      "elasticsearch",
+		},
+		Spec: esv1.ElasticsearchSpec{
+			SecureSettings: []commonv1.SecretSource{testSecureSettingsSecretRef},
+			SnapshotRepositories: []esv1.SnapshotRepository{
+				{
+					Name: "s3-backups",
+					Type: "s3",
+					SecureSettings: []commonv1.SecretSource{
+						{
+							SecretName: repositoryCredentials.Name,
+							Entries: []commonv1.KeyToPath{
+								{Key: "access-key", Path: "s3.client.default.access_key"},
+								{Key: "secret-key", Path: "s3.client.default.secret_key"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	c := k8s.NewFakeClient(&testSecureSettingsSecret, &repositoryCredentials)
+	testDriver := driver.TestDriver{
+		Client:       c,
+		Watches:      watches.NewDynamicWatches(),
+		FakeRecorder: record.NewFakeRecorder(1000),
+	}
+	vol, _, err := secureSettingsVolume(context.Background(), testDriver, &es, nil, esv1.ESNamer, false)
+	require.NoError(t, err)
+	require.NotNil(t, vol)
+
+	// repository credentials are aggregated with the other secure settings in the keystore Secret
+	var secret corev1.Secret
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "namespace", Name: secureSettingsSecretName(esv1.ESNamer, &es)}, &secret))
+	require.Equal(t, map[string][]byte{
+		"key1":                         []byte("value1"),
+		"s3.client.default.access_key": []byte("my-access-key"),
+		"s3.client.default.secret_key": []byte("my-secret-key"),
+	}, secret.Data)
+}
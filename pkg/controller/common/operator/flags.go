@@ -15,10 +15,14 @@ const (
 	ContainerRegistryFlag                = "container-registry"
 	ContainerRepositoryFlag              = "container-repository"
 	ContainerSuffixFlag                  = "container-suffix"
+	CrossNamespaceRefsAllowlistFlag      = "cross-namespace-refs-allowlist"
 	DebugHTTPListenFlag                  = "debug-http-listen"
 	DisableConfigWatch                   = "disable-config-watch"
 	DisableTelemetryFlag                 = "disable-telemetry"
 	DistributionChannelFlag              = "distribution-channel"
+	ElasticsearchClientHealthTimeout     = "elasticsearch-client-health-timeout"
+	ElasticsearchClientLongTimeout       = "elasticsearch-client-long-request-timeout"
+	ElasticsearchClientMaxConnsPerHost   = "elasticsearch-client-max-conns-per-host"
 	ElasticsearchClientTimeout           = "elasticsearch-client-timeout"
 	ElasticsearchObservationIntervalFlag = "elasticsearch-observation-interval"
 	EnableLeaderElection                 = "enable-leader-election"
@@ -30,12 +34,16 @@ const (
 	IPFamilyFlag                         = "ip-family"
 	KubeClientTimeout                    = "kube-client-timeout"
 	KubeClientQPS                        = "kube-client-qps"
+	LeaderElectionLeaseDurationFlag      = "leader-election-lease-duration"
+	LeaderElectionRenewDeadlineFlag      = "leader-election-renew-deadline"
+	LeaderElectionRetryPeriodFlag        = "leader-election-retry-period"
 	ManageWebhookCertsFlag               = "manage-webhook-certs"
 	MaxConcurrentReconcilesFlag          = "max-concurrent-reconciles"
 	MetricsPortFlag                      = "metrics-port"
 	MetricsHostFlag                      = "metrics-host"
 	NamespacesFlag                       = "namespaces"
 	OperatorNamespaceFlag                = "operator-namespace"
+	ResyncPeriodFlag                     = "resync-period"
 	SetDefaultSecurityContextFlag        = "set-default-security-context"
 	TelemetryIntervalFlag                = "telemetry-interval"
 	UBIOnlyFlag                          = "ubi-only"
@@ -15,12 +15,15 @@ import (
 	esvalidation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/validation"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/cryptutil"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
 )
 
 // Parameters contain parameters to create new operators.
 type Parameters struct {
 	// ElasticsearchObservationInterval is the interval between (asynchronous) observations of Elasticsearch health.
 	ElasticsearchObservationInterval time.Duration
+	// CrossNamespaceRefsAllowlist restricts the namespaces between which associations can be established.
+	CrossNamespaceRefsAllowlist rbac.NamespaceAllowlist
 	// ExposedNodeLabels holds regular expressions of node labels which are allowed to be automatically set as annotations on Elasticsearch Pods.
 	ExposedNodeLabels esvalidation.NodeLabels
 	// OperatorNamespace is the control plane namespace of the operator.
@@ -41,6 +44,9 @@ type Parameters struct {
 	CertRotation certificates.RotationParams
 	// MaxConcurrentReconciles controls the number of goroutines per controller.
 	MaxConcurrentReconciles int
+	// ResyncPeriod is the default period at which the controllers reconcile again the resources they successfully
+	// reconciled. It can be overridden per resource with an annotation. 0 disables the periodic reconciliation.
+	ResyncPeriod time.Duration
 	// SetDefaultSecurityContext enables setting the default security context
 	// with fsGroup=1000 for Elasticsearch 8.0+ Pods. Ignored pre-8.0
 	SetDefaultSecurityContext bool
@@ -0,0 +1,52 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// ReconcileNowAnnotation can be set by users to any new value, typically a timestamp, to force an immediate
+	// reconciliation of a resource, for example after fixing an external dependency.
+	ReconcileNowAnnotation = "eck.k8s.elastic.co/reconcile-now"
+	// ObservedReconcileNowAnnotation records the last value of ReconcileNowAnnotation handled by the operator.
+	ObservedReconcileNowAnnotation = "eck.k8s.elastic.co/observed-reconcile-now"
+)
+
+// ReconcileNowRequested returns the value of the reconcile-now annotation of the given resource, and whether it
+// differs from the last observed value, in which case a reconciliation was explicitly requested.
+func ReconcileNowRequested(object client.Object) (string, bool) {
+	requested, exists := object.GetAnnotations()[ReconcileNowAnnotation]
+	if !exists || requested == "" {
+		return "", false
+	}
+	return requested, object.GetAnnotations()[ObservedReconcileNowAnnotation] != requested
+}
+
+// ObserveReconcileNow records the value of the reconcile-now annotation of the given resource as observed, once it has
+// been reconciled. The resource is not updated if the value was already observed, which prevents reconciliation loops.
+func ObserveReconcileNow(ctx context.Context, c client.Client, object client.Object) error {
+	requested, isRequested := ReconcileNowRequested(object)
+	if !isRequested {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Reconciliation requested through annotation", "namespace", object.GetNamespace(), "name", object.GetName(), "value", requested)
+	mergePatch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{ObservedReconcileNowAnnotation: requested},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, object, client.RawPatch(types.MergePatchType, mergePatch))
+}
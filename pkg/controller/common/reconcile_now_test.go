@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestReconcileNowRequested(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		wantValue     string
+		wantRequested bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:        "empty value",
+			annotations: map[string]string{ReconcileNowAnnotation: ""},
+		},
+		{
+			name:          "value not observed yet",
+			annotations:   map[string]string{ReconcileNowAnnotation: "2024-01-01T00:00:00Z"},
+			wantValue:     "2024-01-01T00:00:00Z",
+			wantRequested: true,
+		},
+		{
+			name: "value changed since last observed",
+			annotations: map[string]string{
+				ReconcileNowAnnotation:         "2024-01-02T00:00:00Z",
+				ObservedReconcileNowAnnotation: "2024-01-01T00:00:00Z",
+			},
+			wantValue:     "2024-01-02T00:00:00Z",
+			wantRequested: true,
+		},
+		{
+			name: "value already observed",
+			annotations: map[string]string{
+				ReconcileNowAnnotation:         "2024-01-01T00:00:00Z",
+				ObservedReconcileNowAnnotation: "2024-01-01T00:00:00Z",
+			},
+			wantValue: "2024-01-01T00:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			value, requested := ReconcileNowRequested(obj)
+			require.Equal(t, tt.wantValue, value)
+			require.Equal(t, tt.wantRequested, requested)
+		})
+	}
+}
+
+func TestObserveReconcileNow(t *testing.T) {
+	ctx := context.Background()
+	obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "ns",
+		Name:        "name",
+		Annotations: map[string]string{ReconcileNowAnnotation: "1"},
+	}}
+	c := k8s.NewFakeClient(obj)
+	get := func() *corev1.Secret {
+		var current corev1.Secret
+		require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(obj), &current))
+		return &current
+	}
+
+	// the requested value is recorded as observed
+	require.NoError(t, ObserveReconcileNow(ctx, c, get()))
+	observed := get()
+	require.Equal(t, "1", observed.Annotations[ObservedReconcileNowAnnotation])
+	_, requested := ReconcileNowRequested(observed)
+	require.False(t, requested)
+
+	// the resource is not updated again while the value does not change, which would trigger another reconciliation
+	require.NoError(t, ObserveReconcileNow(ctx, c, observed))
+	require.Equal(t, observed.ResourceVersion, get().ResourceVersion)
+
+	// a new value is requested and observed again
+	observed.Annotations[ReconcileNowAnnotation] = "2"
+	require.NoError(t, c.Update(ctx, observed))
+	_, requested = ReconcileNowRequested(get())
+	require.True(t, requested)
+	require.NoError(t, ObserveReconcileNow(ctx, c, get()))
+	require.Equal(t, "2", get().Annotations[ObservedReconcileNowAnnotation])
+}
@@ -0,0 +1,139 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// DefaultBackoffInitialInterval is the requeue interval applied after the second successive failure.
+	DefaultBackoffInitialInterval = 5 * time.Second
+	// DefaultBackoffMaxInterval caps the requeue interval of resources whose reconciliations keep failing.
+	DefaultBackoffMaxInterval = 5 * time.Minute
+	// DefaultDegradedThreshold is the number of successive failures after which a resource is reported as degraded.
+	DefaultDegradedThreshold = 5
+)
+
+// failures tracks the successive reconciliation failures of a resource.
+type failures struct {
+	generation int64
+	reason     string
+	count      int
+}
+
+// Backoff computes exponentially growing requeue intervals for resources whose successive reconciliations fail for
+// the same reason, to avoid hammering the API server and flooding the logs while a resource is stuck.
+// The backoff of a resource is reset by a successful reconciliation, a different failure reason, or a change of the
+// resource generation, which reflects a spec change.
+type Backoff struct {
+	initialInterval   time.Duration
+	maxInterval       time.Duration
+	degradedThreshold int
+
+	mutex    sync.Mutex
+	failures map[types.NamespacedName]failures
+}
+
+// NewBackoff returns a Backoff starting at the given interval, capped at the given max interval, and reporting resources
+// as degraded after the given number of successive failures.
+func NewBackoff(initialInterval, maxInterval time.Duration, degradedThreshold int) *Backoff {
+	return &Backoff{
+		initialInterval:   initialInterval,
+		maxInterval:       maxInterval,
+		degradedThreshold: degradedThreshold,
+		failures:          make(map[types.NamespacedName]failures),
+	}
+}
+
+// NewDefaultBackoff returns a Backoff with the default intervals and degraded threshold.
+func NewDefaultBackoff() *Backoff {
+	return NewBackoff(DefaultBackoffInitialInterval, DefaultBackoffMaxInterval, DefaultDegradedThreshold)
+}
+
+// Failure records a failed reconciliation of the given resource. It returns the interval after which the resource
+// should be reconciled again, or 0 on the first failure for which the default requeue on error applies, and whether
+// the resource should be reported as degraded.
+func (b *Backoff) Failure(key types.NamespacedName, generation int64, reason string) (time.Duration, bool) {
+	if b == nil {
+		return 0, false
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	current, exists := b.failures[key]
+	if !exists || current.generation != generation || current.reason != reason {
+		current = failures{generation: generation, reason: reason}
+	}
+	current.count++
+	b.failures[key] = current
+
+	return b.interval(current.count), current.count >= b.degradedThreshold
+}
+
+// interval returns the requeue interval after the given number of successive failures.
+func (b *Backoff) interval(count int) time.Duration {
+	if count < 2 {
+		return 0
+	}
+	interval := b.initialInterval
+	for i := 2; i < count; i++ {
+		interval *= 2
+		if interval >= b.maxInterval {
+			return b.maxInterval
+		}
+	}
+	return min(interval, b.maxInterval)
+}
+
+// Interval returns the interval after which the given resource should be reconciled again, given the failures
+// recorded so far, or 0 if its last reconciliation did not fail repeatedly.
+func (b *Backoff) Interval(key types.NamespacedName) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	current, exists := b.failures[key]
+	if !exists {
+		return 0
+	}
+	return b.interval(current.count)
+}
+
+// RateLimiter returns a rate limiter for the work queue of a controller, which requeues the reconcile requests of
+// failing resources after the greater of the delay of the default controller rate limiter and the backoff interval.
+// This allows the reconciler to return its errors, reported by the controller error metrics, while backing off.
+func (b *Backoff) RateLimiter() workqueue.RateLimiter {
+	return &backoffRateLimiter{RateLimiter: workqueue.DefaultControllerRateLimiter(), backoff: b}
+}
+
+type backoffRateLimiter struct {
+	workqueue.RateLimiter
+	backoff *Backoff
+}
+
+func (r *backoffRateLimiter) When(item interface{}) time.Duration {
+	delay := r.RateLimiter.When(item)
+	if request, ok := item.(reconcile.Request); ok {
+		delay = max(delay, r.backoff.Interval(request.NamespacedName))
+	}
+	return delay
+}
+
+// Reset clears the failures recorded for the given resource, after a successful reconciliation or its deletion.
+func (b *Backoff) Reset(key types.NamespacedName) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	delete(b.failures, key)
+}
@@ -0,0 +1,107 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestBackoff_Failure(t *testing.T) {
+	key := types.NamespacedName{Namespace: "ns", Name: "es"}
+	b := NewBackoff(time.Second, 10*time.Second, 4)
+
+	// the interval grows exponentially with successive failures, up to the max interval
+	wantIntervals := []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, want := range wantIntervals {
+		interval, degraded := b.Failure(key, 1, "failure")
+		require.Equal(t, want, interval, "failure %d", i+1)
+		require.Equal(t, i+1 >= 4, degraded, "failure %d", i+1)
+	}
+
+	// other resources are not affected
+	interval, degraded := b.Failure(types.NamespacedName{Namespace: "ns", Name: "other"}, 1, "failure")
+	require.Equal(t, time.Duration(0), interval)
+	require.False(t, degraded)
+}
+
+func TestBackoff_Failure_reset(t *testing.T) {
+	key := types.NamespacedName{Namespace: "ns", Name: "es"}
+	tests := []struct {
+		name       string
+		generation int64
+		reason     string
+		reset      bool
+	}{
+		{name: "same failure", generation: 1, reason: "failure"},
+		{name: "spec change", generation: 2, reason: "failure", reset: true},
+		{name: "different failure", generation: 1, reason: "another failure", reset: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBackoff(time.Second, time.Minute, 3)
+			for i := 0; i < 3; i++ {
+				b.Failure(key, 1, "failure")
+			}
+			interval, degraded := b.Failure(key, tt.generation, tt.reason)
+			if tt.reset {
+				require.Equal(t, time.Duration(0), interval)
+				require.False(t, degraded)
+				return
+			}
+			require.Equal(t, 4*time.Second, interval)
+			require.True(t, degraded)
+		})
+	}
+}
+
+func TestBackoff_Reset(t *testing.T) {
+	key := types.NamespacedName{Namespace: "ns", Name: "es"}
+	b := NewBackoff(time.Second, time.Minute, 2)
+	b.Failure(key, 1, "failure")
+	_, degraded := b.Failure(key, 1, "failure")
+	require.True(t, degraded)
+
+	b.Reset(key)
+	interval, degraded := b.Failure(key, 1, "failure")
+	require.Equal(t, time.Duration(0), interval)
+	require.False(t, degraded)
+
+	// a nil backoff is a no-op
+	var nilBackoff *Backoff
+	nilBackoff.Reset(key)
+	interval, degraded = nilBackoff.Failure(key, 1, "failure")
+	require.Equal(t, time.Duration(0), interval)
+	require.False(t, degraded)
+}
+
+func TestBackoff_RateLimiter(t *testing.T) {
+	key := types.NamespacedName{Namespace: "ns", Name: "es"}
+	request := reconcile.Request{NamespacedName: key}
+	b := NewBackoff(time.Minute, 10*time.Minute, 5)
+	rateLimiter := b.RateLimiter()
+
+	// the default controller rate limiter applies until the reconciliation fails repeatedly
+	require.Less(t, rateLimiter.When(request), time.Second)
+	b.Failure(key, 1, "failure")
+	require.Equal(t, time.Duration(0), b.Interval(key))
+	require.Less(t, rateLimiter.When(request), time.Second)
+
+	// the backoff interval applies past the second failure
+	b.Failure(key, 1, "failure")
+	b.Failure(key, 1, "failure")
+	require.Equal(t, 2*time.Minute, b.Interval(key))
+	require.Equal(t, 2*time.Minute, rateLimiter.When(request))
+	// other resources are not affected
+	require.Less(t, rateLimiter.When(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "other"}}), time.Second)
+
+	b.Reset(key)
+	require.Equal(t, time.Duration(0), b.Interval(key))
+	require.Less(t, rateLimiter.When(request), time.Second)
+}
@@ -0,0 +1,236 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// DryRunAnnotation can be set to "true" on a resource to compute the changes the operator would apply to the
+// resources it manages, without applying them.
+const DryRunAnnotation = "eck.k8s.elastic.co/dry-run"
+
+// IsDryRun returns true if the given resource is annotated to be reconciled in dry-run mode.
+func IsDryRun(object metav1.Object) bool {
+	return object.GetAnnotations()[DryRunAnnotation] == "true"
+}
+
+// Action is the kind of change planned on a resource.
+type Action string
+
+const (
+	CreateAction Action = "Create"
+	UpdateAction Action = "Update"
+	PatchAction  Action = "Patch"
+	DeleteAction Action = "Delete"
+)
+
+// PlannedAction is a change the operator would have applied to a resource outside of dry-run mode.
+type PlannedAction struct {
+	Action    Action
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (a PlannedAction) String() string {
+	return fmt.Sprintf("%s %s %s/%s", a.Action, a.Kind, a.Namespace, a.Name)
+}
+
+// Plan holds the actions recorded by a dry-run client.
+type Plan struct {
+	mutex   sync.Mutex
+	actions []PlannedAction
+}
+
+func (p *Plan) record(action PlannedAction) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.actions = append(p.actions, action)
+}
+
+// Actions returns the actions recorded so far, in the order they were planned.
+func (p *Plan) Actions() []PlannedAction {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]PlannedAction(nil), p.actions...)
+}
+
+// dryRunClient is a k8s.Client that records write operations in a Plan instead of sending them to the API server.
+// Objects created, updated or deleted through the client are returned accordingly by subsequent Get calls, so that
+// reconciliation steps depending on the outcome of previous ones can still be computed. They are not reflected in
+// the results of List calls.
+type dryRunClient struct {
+	k8s.Client
+	plan *Plan
+
+	mutex   sync.Mutex
+	objects map[string]client.Object // nil values stand for deleted objects
+}
+
+var _ k8s.Client = &dryRunClient{}
+
+// NewDryRunClient returns a k8s.Client that reads from the given client but only records write operations in the
+// returned Plan. Requests already flagged as server-side dry-run are forwarded to the given client.
+func NewDryRunClient(c k8s.Client) (k8s.Client, *Plan) {
+	plan := &Plan{}
+	return &dryRunClient{Client: c, plan: plan, objects: map[string]client.Object{}}, plan
+}
+
+func (d *dryRunClient) key(obj client.Object) (string, schema.GroupVersionKind, error) {
+	return d.keyFor(obj, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()})
+}
+
+func (d *dryRunClient) keyFor(obj client.Object, nsn types.NamespacedName) (string, schema.GroupVersionKind, error) {
+	gvk, err := apiutil.GVKForObject(obj, d.Scheme())
+	if err != nil {
+		return "", gvk, err
+	}
+	return fmt.Sprintf("%s/%s", gvk.String(), nsn), gvk, nil
+}
+
+func (d *dryRunClient) store(action Action, obj client.Object, deleted bool) error {
+	key, gvk, err := d.key(obj)
+	if err != nil {
+		return err
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if deleted {
+		d.objects[key] = nil
+	} else {
+		d.objects[key] = obj.DeepCopyObject().(client.Object) //nolint:forcetypeassert
+	}
+	d.plan.record(PlannedAction{Action: action, Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName()})
+	return nil
+}
+
+func (d *dryRunClient) Get(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) error {
+	storeKey, gvk, err := d.keyFor(obj, key)
+	if err != nil {
+		return err
+	}
+	d.mutex.Lock()
+	stored, exists := d.objects[storeKey]
+	d.mutex.Unlock()
+	if !exists {
+		return d.Client.Get(ctx, key, obj, opts...)
+	}
+	if stored == nil {
+		return apierrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, key.Name)
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(stored.DeepCopyObject()).Elem())
+	return nil
+}
+
+func (d *dryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	createOpts := &client.CreateOptions{}
+	createOpts.ApplyOptions(opts)
+	if len(createOpts.DryRun) > 0 {
+		return d.Client.Create(ctx, obj, opts...)
+	}
+	return d.store(CreateAction, obj, false)
+}
+
+func (d *dryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	updateOpts := &client.UpdateOptions{}
+	updateOpts.ApplyOptions(opts)
+	if len(updateOpts.DryRun) > 0 {
+		return d.Client.Update(ctx, obj, opts...)
+	}
+	return d.store(UpdateAction, obj, false)
+}
+
+func (d *dryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	patchOpts := &client.PatchOptions{}
+	patchOpts.ApplyOptions(opts)
+	if len(patchOpts.DryRun) > 0 {
+		return d.Client.Patch(ctx, obj, patch, opts...)
+	}
+	// the patch is not applied to the object: only record it
+	_, gvk, err := d.key(obj)
+	if err != nil {
+		return err
+	}
+	d.plan.record(PlannedAction{Action: PatchAction, Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName()})
+	return nil
+}
+
+func (d *dryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	deleteOpts := &client.DeleteOptions{}
+	deleteOpts.ApplyOptions(opts)
+	if len(deleteOpts.DryRun) > 0 {
+		return d.Client.Delete(ctx, obj, opts...)
+	}
+	return d.store(DeleteAction, obj, true)
+}
+
+func (d *dryRunClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	deleteOpts := &client.DeleteAllOfOptions{}
+	deleteOpts.ApplyOptions(opts)
+	if len(deleteOpts.DryRun) > 0 {
+		return d.Client.DeleteAllOf(ctx, obj, opts...)
+	}
+	_, gvk, err := d.key(obj)
+	if err != nil {
+		return err
+	}
+	d.plan.record(PlannedAction{Action: DeleteAction, Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: "*"})
+	return nil
+}
+
+func (d *dryRunClient) Status() client.SubResourceWriter {
+	return &dryRunSubResourceWriter{c: d}
+}
+
+func (d *dryRunClient) SubResource(subResource string) client.SubResourceClient {
+	return &dryRunSubResourceClient{
+		SubResourceReader:       d.Client.SubResource(subResource),
+		dryRunSubResourceWriter: dryRunSubResourceWriter{c: d},
+	}
+}
+
+// dryRunSubResourceWriter records subresource write operations in a Plan.
+type dryRunSubResourceWriter struct {
+	c *dryRunClient
+}
+
+func (w *dryRunSubResourceWriter) record(action Action, obj client.Object) error {
+	_, gvk, err := w.c.key(obj)
+	if err != nil {
+		return err
+	}
+	w.c.plan.record(PlannedAction{Action: action, Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName()})
+	return nil
+}
+
+func (w *dryRunSubResourceWriter) Create(_ context.Context, obj client.Object, _ client.Object, _ ...client.SubResourceCreateOption) error {
+	return w.record(CreateAction, obj)
+}
+
+func (w *dryRunSubResourceWriter) Update(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+	return w.record(UpdateAction, obj)
+}
+
+func (w *dryRunSubResourceWriter) Patch(_ context.Context, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+	return w.record(PatchAction, obj)
+}
+
+type dryRunSubResourceClient struct {
+	client.SubResourceReader
+	dryRunSubResourceWriter
+}
@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestIsDryRun(t *testing.T) {
+	require.False(t, IsDryRun(&corev1.Secret{}))
+	require.False(t, IsDryRun(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{DryRunAnnotation: "false"}}}))
+	require.True(t, IsDryRun(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{DryRunAnnotation: "true"}}}))
+}
+
+func TestDryRunClient(t *testing.T) {
+	ctx := context.Background()
+	existing := createSecret("existing", sampleData, nil, nil)
+	k8sClient := k8s.NewFakeClient(existing.DeepCopy())
+	c, plan := NewDryRunClient(k8sClient)
+
+	// create a new secret
+	_, err := ReconcileSecret(ctx, c, *createSecret("new", sampleData, sampleLabels, nil), nil)
+	require.NoError(t, err)
+	// update the existing secret
+	_, err = ReconcileSecret(ctx, c, *createSecret("existing", sampleDataUpdated, nil, nil), nil)
+	require.NoError(t, err)
+	// delete another secret
+	require.NoError(t, c.Delete(ctx, createSecret("deleted", nil, nil, nil)))
+	// patch the existing secret
+	require.NoError(t, c.Patch(ctx, existing.DeepCopy(), client.MergeFrom(existing)))
+
+	require.Equal(t, []PlannedAction{
+		{Action: CreateAction, Kind: "Secret", Namespace: testNamespace, Name: "new"},
+		{Action: UpdateAction, Kind: "Secret", Namespace: testNamespace, Name: "existing"},
+		{Action: DeleteAction, Kind: "Secret", Namespace: testNamespace, Name: "deleted"},
+		{Action: PatchAction, Kind: "Secret", Namespace: testNamespace, Name: "existing"},
+	}, plan.Actions())
+
+	// no write reached the underlying client
+	var secrets corev1.SecretList
+	require.NoError(t, k8sClient.List(ctx, &secrets))
+	require.Len(t, secrets.Items, 1)
+	require.Equal(t, sampleData, secrets.Items[0].Data)
+
+	// but planned changes are visible through the dry-run client
+	var secret corev1.Secret
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "new"}, &secret))
+	require.Equal(t, sampleData, secret.Data)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "existing"}, &secret))
+	require.Equal(t, sampleDataUpdated, secret.Data)
+
+	// a planned deletion hides an existing resource
+	require.NoError(t, c.Delete(ctx, existing.DeepCopy()))
+	err = c.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "existing"}, &secret)
+	require.True(t, apierrors.IsNotFound(err))
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "existing"}, &secret))
+}
+
+func TestDryRunClient_ServerSideDryRun(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := k8s.NewFakeClient()
+	c, plan := NewDryRunClient(k8sClient)
+
+	// requests already flagged as server-side dry-run are forwarded and not recorded
+	require.NoError(t, c.Create(ctx, createSecret("secret", sampleData, nil, nil), client.DryRunAll))
+	require.Empty(t, plan.Actions())
+}
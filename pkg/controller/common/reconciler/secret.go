@@ -37,9 +37,13 @@ func WithPostUpdate(f func()) func(p *Params) {
 }
 
 // ReconcileSecret creates or updates the actual secret to match the expected one.
-// Existing annotations or labels that are not expected are preserved.
+// Existing annotations or labels that are not expected are preserved. Labels and annotations configured by the user on
+// the owner for its secrets are added to the expected ones.
 func ReconcileSecret(ctx context.Context, c k8s.Client, expected corev1.Secret, owner client.Object, opts ...func(*Params)) (corev1.Secret, error) {
 	var reconciled corev1.Secret
+	if owner != nil {
+		expected = WithUserSecretMetadata(ctx, owner, expected)
+	}
 
 	params := Params{
 		Context:    ctx,
@@ -104,6 +108,7 @@ func SoftOwnerRefFromLabels(labels map[string]string) (SoftOwnerRef, bool) {
 //
 // Since they won't have an ownerReference specified, reconciled secrets will not be deleted automatically on parent deletion.
 // To account for that, we add labels to reference the "soft owner", for garbage collection by the operator on parent resource deletion.
+// Labels and annotations configured by the user on the soft owner for its secrets are added to the expected ones.
 func ReconcileSecretNoOwnerRef(ctx context.Context, c k8s.Client, expected corev1.Secret, softOwner runtime.Object) (corev1.Secret, error) {
 	// this function is similar to "ReconcileSecret", but:
 	// - we don't pass an owner
@@ -116,6 +121,7 @@ func ReconcileSecretNoOwnerRef(ctx context.Context, c k8s.Client, expected corev
 
 	// don't mutate expected (no side effects), make a copy
 	expected = *expected.DeepCopy()
+	expected = WithUserSecretMetadata(ctx, ownerMeta, expected)
 	expected.Labels[SoftOwnerNamespaceLabel] = ownerMeta.GetNamespace()
 	expected.Labels[SoftOwnerNameLabel] = ownerMeta.GetName()
 	expected.Labels[SoftOwnerKindLabel] = softOwner.GetObjectKind().GroupVersionKind().Kind
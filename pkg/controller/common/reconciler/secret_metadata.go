@@ -0,0 +1,78 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	// SecretLabelsAnnotation can be set by users on a resource to a JSON object of labels to set on all the secrets
+	// created by the operator for this resource, for example '{"backup.example.com/include": "true"}'.
+	SecretLabelsAnnotation = "eck.k8s.elastic.co/secret-labels"
+	// SecretAnnotationsAnnotation can be set by users on a resource to a JSON object of annotations to set on all the
+	// secrets created by the operator for this resource.
+	SecretAnnotationsAnnotation = "eck.k8s.elastic.co/secret-annotations"
+
+	reservedMetadataDomain = "k8s.elastic.co"
+)
+
+// UserSecretMetadata returns the labels and annotations to set on the secrets of the given owner, as configured by the
+// user in the secret-labels and secret-annotations annotations of the owner. Invalid values and keys reserved to the
+// operator are ignored.
+func UserSecretMetadata(ctx context.Context, owner metav1.Object) (map[string]string, map[string]string) {
+	return parseSecretMetadataAnnotation(ctx, owner, SecretLabelsAnnotation),
+		parseSecretMetadataAnnotation(ctx, owner, SecretAnnotationsAnnotation)
+}
+
+// WithUserSecretMetadata returns a copy of the expected secret including the labels and annotations configured by the
+// user on the given owner. Labels and annotations already set on the expected secret by the operator take precedence.
+func WithUserSecretMetadata(ctx context.Context, owner metav1.Object, expected corev1.Secret) corev1.Secret {
+	labels, annotations := UserSecretMetadata(ctx, owner)
+	if len(labels) == 0 && len(annotations) == 0 {
+		return expected
+	}
+	expected = *expected.DeepCopy()
+	expected.Labels = maps.MergePreservingExistingKeys(expected.Labels, labels)
+	expected.Annotations = maps.MergePreservingExistingKeys(expected.Annotations, annotations)
+	return expected
+}
+
+func parseSecretMetadataAnnotation(ctx context.Context, owner metav1.Object, annotation string) map[string]string {
+	value, exists := owner.GetAnnotations()[annotation]
+	if !exists {
+		return nil
+	}
+	log := ulog.FromContext(ctx)
+	var metadata map[string]string
+	if err := json.Unmarshal([]byte(value), &metadata); err != nil {
+		log.Info("Ignoring invalid secret metadata annotation",
+			"namespace", owner.GetNamespace(), "name", owner.GetName(), "annotation", annotation, "error", err.Error())
+		return nil
+	}
+	for key := range metadata {
+		if isReservedMetadataKey(key) {
+			log.Info("Ignoring secret metadata key reserved to the operator",
+				"namespace", owner.GetNamespace(), "name", owner.GetName(), "annotation", annotation, "key", key)
+			delete(metadata, key)
+		}
+	}
+	return metadata
+}
+
+// isReservedMetadataKey returns true if the given label or annotation key belongs to the k8s.elastic.co domain, or one
+// of its subdomains, which are reserved to the operator.
+func isReservedMetadataKey(key string) bool {
+	prefix, _, hasPrefix := strings.Cut(key, "/")
+	return hasPrefix && (prefix == reservedMetadataDomain || strings.HasSuffix(prefix, "."+reservedMetadataDomain))
+}
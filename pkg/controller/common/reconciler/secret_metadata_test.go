@@ -0,0 +1,97 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestUserSecretMetadata(t *testing.T) {
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+	}{
+		{
+			name: "no secret metadata",
+		},
+		{
+			name: "labels and annotations",
+			annotations: map[string]string{
+				SecretLabelsAnnotation:      `{"backup.example.com/include": "true"}`,
+				SecretAnnotationsAnnotation: `{"scanner.example.com/policy": "strict", "team": "search"}`,
+			},
+			wantLabels:      map[string]string{"backup.example.com/include": "true"},
+			wantAnnotations: map[string]string{"scanner.example.com/policy": "strict", "team": "search"},
+		},
+		{
+			name: "reserved keys are ignored",
+			annotations: map[string]string{
+				SecretLabelsAnnotation: `{"common.k8s.elastic.co/type": "kibana", "k8s.elastic.co/x": "y", "my-k8s.elastic.co/x": "y"}`,
+				SecretAnnotationsAnnotation: `{"eck.k8s.elastic.co/managed": "false", "elasticsearch.k8s.elastic.co/cluster-name": "other",` +
+					` "k8s.elastic.co": "not a prefix"}`,
+			},
+			wantLabels:      map[string]string{"my-k8s.elastic.co/x": "y"},
+			wantAnnotations: map[string]string{"k8s.elastic.co": "not a prefix"},
+		},
+		{
+			name: "invalid values are ignored",
+			annotations: map[string]string{
+				SecretLabelsAnnotation:      `backup.example.com/include=true`,
+				SecretAnnotationsAnnotation: `{"scanner.example.com/policy": "strict"}`,
+			},
+			wantAnnotations: map[string]string{"scanner.example.com/policy": "strict"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner := &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "es", Annotations: tt.annotations}}
+			labels, annotations := UserSecretMetadata(context.Background(), owner)
+			require.Equal(t, tt.wantLabels, labels)
+			require.Equal(t, tt.wantAnnotations, annotations)
+		})
+	}
+}
+
+func TestReconcileSecret_UserSecretMetadata(t *testing.T) {
+	es := &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "es", Annotations: map[string]string{
+		SecretLabelsAnnotation:      `{"backup.example.com/include": "true", "label1": "user", "eck.k8s.elastic.co/owner-name": "other"}`,
+		SecretAnnotationsAnnotation: `{"scanner.example.com/policy": "strict", "annotation1": "user"}`,
+	}}}
+	wantLabels := map[string]string{"backup.example.com/include": "true", "label1": "value1", "label2": "value2"}
+	wantAnnotations := map[string]string{"scanner.example.com/policy": "strict", "annotation1": "value1", "annotation2": "value2"}
+
+	// the labels and annotations configured by the user are set on the secret, without overriding the operator ones
+	c := k8s.NewFakeClient()
+	got, err := ReconcileSecret(context.Background(), c, *createSecret("s", sampleData, sampleLabels, sampleAnnotations), es)
+	require.NoError(t, err)
+	require.Equal(t, wantLabels, got.Labels)
+	require.Equal(t, wantAnnotations, got.Annotations)
+	// the expected labels and annotations are not mutated
+	require.Equal(t, map[string]string{"label1": "value1", "label2": "value2"}, sampleLabels)
+
+	// the same is true for secrets without owner references
+	es.TypeMeta = metav1.TypeMeta{Kind: esv1.Kind}
+	got, err = ReconcileSecretNoOwnerRef(context.Background(), c, *createSecret("s2", sampleData, sampleLabels, sampleAnnotations), es)
+	require.NoError(t, err)
+	require.Equal(t, wantAnnotations, got.Annotations)
+	require.Equal(t, "true", got.Labels["backup.example.com/include"])
+	require.Equal(t, "value1", got.Labels["label1"])
+	// the soft owner labels reserved to the operator are untouched
+	require.Equal(t, "es", got.Labels[SoftOwnerNameLabel])
+
+	var retrieved corev1.Secret
+	require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(&got), &retrieved))
+	require.Equal(t, got.Labels, retrieved.Labels)
+}
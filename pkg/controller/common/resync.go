@@ -0,0 +1,72 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// ResyncPeriodAnnotation can be set by users to override, for a given resource, the period at which the operator
+	// reconciles it again after a successful reconciliation, for example "5m". "0" disables the periodic reconciliation.
+	ResyncPeriodAnnotation = "eck.k8s.elastic.co/resync-period"
+
+	// MinResyncPeriod is the lowest resync period, to avoid overloading the operator and the API server.
+	MinResyncPeriod = 30 * time.Second
+	// MaxResyncPeriod is the highest resync period.
+	MaxResyncPeriod = 24 * time.Hour
+)
+
+// ResyncPeriod returns the period at which the given resource is reconciled again after a successful reconciliation,
+// even if nothing changed in the meantime. It is read from the resync-period annotation of the resource, if valid, or
+// defaults to the given period. It is clamped to the resync period bounds, 0 meaning no periodic reconciliation.
+func ResyncPeriod(ctx context.Context, object metav1.Object, defaultPeriod time.Duration) time.Duration {
+	period := defaultPeriod
+	if value, exists := object.GetAnnotations()[ResyncPeriodAnnotation]; exists {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			ulog.FromContext(ctx).Info(
+				"Ignoring invalid resync period annotation", "namespace", object.GetNamespace(), "name", object.GetName(), "value", value,
+			)
+		} else {
+			period = parsed
+		}
+	}
+	return ClampResyncPeriod(period)
+}
+
+// ClampResyncPeriod bounds the given resync period to [MinResyncPeriod, MaxResyncPeriod], or returns 0 if it is not
+// positive.
+func ClampResyncPeriod(period time.Duration) time.Duration {
+	switch {
+	case period <= 0:
+		return 0
+	case period < MinResyncPeriod:
+		return MinResyncPeriod
+	case period > MaxResyncPeriod:
+		return MaxResyncPeriod
+	default:
+		return period
+	}
+}
+
+// WithResync returns the given reconciliation result of the given resource, requeued after the resync period of the
+// resource unless it is already requeued sooner.
+func WithResync(ctx context.Context, object metav1.Object, defaultPeriod time.Duration, result reconcile.Result) reconcile.Result {
+	period := ResyncPeriod(ctx, object, defaultPeriod)
+	if period == 0 || result.Requeue && result.RequeueAfter == 0 {
+		return result
+	}
+	if result.RequeueAfter == 0 || result.RequeueAfter > period {
+		result.RequeueAfter = period
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestResyncPeriod(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		defaultPeriod time.Duration
+		want          time.Duration
+	}{
+		{
+			name: "no resync by default",
+		},
+		{
+			name:          "default period",
+			defaultPeriod: 10 * time.Minute,
+			want:          10 * time.Minute,
+		},
+		{
+			name:          "default period clamped to the lower bound",
+			defaultPeriod: time.Second,
+			want:          MinResyncPeriod,
+		},
+		{
+			name:          "default period clamped to the upper bound",
+			defaultPeriod: 48 * time.Hour,
+			want:          MaxResyncPeriod,
+		},
+		{
+			name:          "negative default period disables the resync",
+			defaultPeriod: -time.Minute,
+			want:          0,
+		},
+		{
+			name:          "annotation overrides the default period",
+			annotations:   map[string]string{ResyncPeriodAnnotation: "2m"},
+			defaultPeriod: 10 * time.Minute,
+			want:          2 * time.Minute,
+		},
+		{
+			name:        "annotation clamped to the lower bound",
+			annotations: map[string]string{ResyncPeriodAnnotation: "1s"},
+			want:        MinResyncPeriod,
+		},
+		{
+			name:        "annotation clamped to the upper bound",
+			annotations: map[string]string{ResyncPeriodAnnotation: "720h"},
+			want:        MaxResyncPeriod,
+		},
+		{
+			name:          "annotation disables the resync",
+			annotations:   map[string]string{ResyncPeriodAnnotation: "0"},
+			defaultPeriod: 10 * time.Minute,
+			want:          0,
+		},
+		{
+			name:          "invalid annotation is ignored",
+			annotations:   map[string]string{ResyncPeriodAnnotation: "often"},
+			defaultPeriod: 10 * time.Minute,
+			want:          10 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "name", Annotations: tt.annotations}}
+			require.Equal(t, tt.want, ResyncPeriod(context.Background(), obj, tt.defaultPeriod))
+		})
+	}
+}
+
+func TestWithResync(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		period     time.Duration
+		result     reconcile.Result
+		want       reconcile.Result
+	}{
+		{
+			name:   "no resync",
+			result: reconcile.Result{},
+			want:   reconcile.Result{},
+		},
+		{
+			name:   "reconciled resource is requeued after the resync period",
+			period: 5 * time.Minute,
+			result: reconcile.Result{},
+			want:   reconcile.Result{RequeueAfter: 5 * time.Minute},
+		},
+		{
+			name:       "resync period from the annotation",
+			annotation: "1m",
+			period:     5 * time.Minute,
+			result:     reconcile.Result{},
+			want:       reconcile.Result{RequeueAfter: time.Minute},
+		},
+		{
+			name:       "resync period from the annotation, clamped",
+			annotation: "1ms",
+			result:     reconcile.Result{},
+			want:       reconcile.Result{RequeueAfter: MinResyncPeriod},
+		},
+		{
+			name:   "sooner requeue is kept",
+			period: 5 * time.Minute,
+			result: reconcile.Result{RequeueAfter: 10 * time.Second},
+			want:   reconcile.Result{RequeueAfter: 10 * time.Second},
+		},
+		{
+			name:   "later requeue is brought forward",
+			period: 5 * time.Minute,
+			result: reconcile.Result{RequeueAfter: time.Hour},
+			want:   reconcile.Result{RequeueAfter: 5 * time.Minute},
+		},
+		{
+			name:   "immediate requeue is kept",
+			period: 5 * time.Minute,
+			result: reconcile.Result{Requeue: true},
+			want:   reconcile.Result{Requeue: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "name"}}
+			if tt.annotation != "" {
+				obj.Annotations = map[string]string{ResyncPeriodAnnotation: tt.annotation}
+			}
+			require.Equal(t, tt.want, WithResync(context.Background(), obj, tt.period, tt.result))
+		})
+	}
+}
@@ -131,6 +131,14 @@ func (c *CanonicalConfig) Unpack(cfg interface{}) error {
 	return c.asUCfg().Unpack(cfg, Options...)
 }
 
+// Remove removes the given key from c. It returns true if the key was present.
+func (c *CanonicalConfig) Remove(key string) (bool, error) {
+	if c == nil {
+		return false, nil
+	}
+	return c.asUCfg().Remove(key, -1, Options...)
+}
+
 // MergeWith merges the content of c and c2.
 // In case of conflict, c2 is taking precedence.
 func (c *CanonicalConfig) MergeWith(cfgs ...*CanonicalConfig) error {
@@ -559,3 +559,54 @@ func TestCanonicalConfig_HasChildConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestCanonicalConfig_Remove(t *testing.T) {
+	tests := []struct {
+		name        string
+		c           *CanonicalConfig
+		key         string
+		wantRemoved bool
+		want        *CanonicalConfig
+	}{
+		{
+			name:        "nil config",
+			c:           nil,
+			key:         "x",
+			wantRemoved: false,
+			want:        nil,
+		},
+		{
+			name: "absent key",
+			c: MustCanonicalConfig(map[string]interface{}{
+				"x": "y",
+			}),
+			key:         "z",
+			wantRemoved: false,
+			want: MustCanonicalConfig(map[string]interface{}{
+				"x": "y",
+			}),
+		},
+		{
+			name: "nested key",
+			c: MustCanonicalConfig(map[string]interface{}{
+				"x.y": []string{"a", "b"},
+				"x.z": "2",
+			}),
+			key:         "x.y",
+			wantRemoved: true,
+			want: MustCanonicalConfig(map[string]interface{}{
+				"x.z": "2",
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			removed, err := tt.c.Remove(tt.key)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRemoved, removed)
+			if diff := tt.c.Diff(tt.want, nil); len(diff) > 0 {
+				t.Errorf("Remove() diff = %v", diff)
+			}
+		})
+	}
+}
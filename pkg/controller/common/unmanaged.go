@@ -17,6 +17,9 @@ const (
 	// ManagedAnnotation annotation
 	LegacyPauseAnnoation = "common.k8s.elastic.co/pause"
 	ManagedAnnotation    = "eck.k8s.elastic.co/managed"
+
+	// PausedMessage is reported on the resources whose reconciliation is skipped since they are currently unmanaged.
+	PausedMessage = "Reconciliation paused, remove the " + ManagedAnnotation + " annotation to resume it"
 )
 
 // IsUnmanaged checks if a given resource is currently unmanaged.
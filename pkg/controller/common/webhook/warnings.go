@@ -5,7 +5,10 @@
 package webhook
 
 import (
+	"context"
+
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type HasWarnings interface {
@@ -19,3 +22,17 @@ func MaybeGetWarnings(object runtime.Object) []string {
 	}
 	return nil
 }
+
+// HasClusterWarnings is implemented by objects whose warnings depend on other resources in the cluster.
+type HasClusterWarnings interface {
+	GetClusterWarnings(ctx context.Context, c client.Reader) []string
+}
+
+// MaybeGetClusterWarnings returns the warnings of objects implementing HasClusterWarnings, if a client is available.
+func MaybeGetClusterWarnings(ctx context.Context, c client.Reader, object runtime.Object) []string {
+	v, ok := object.(HasClusterWarnings)
+	if ok && c != nil {
+		return v.GetClusterWarnings(ctx, c)
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -36,6 +37,7 @@ func SetupValidatingWebhookWithConfig(config *Config) error {
 		config.WebhookPath,
 		&webhook.Admission{
 			Handler: &validatingWebhook{
+				client:            config.Manager.GetClient(),
 				decoder:           admission.NewDecoder(config.Manager.GetScheme()),
 				validator:         config.Validator,
 				licenseChecker:    config.LicenseChecker,
@@ -53,6 +55,7 @@ type Config struct {
 }
 
 type validatingWebhook struct {
+	client            client.Reader
 	decoder           *admission.Decoder
 	managedNamespaces set.StringSet
 	licenseChecker    license.Checker
@@ -82,6 +85,9 @@ func (v *validatingWebhook) Handle(ctx context.Context, req admission.Request) a
 	}
 
 	warnings := MaybeGetWarnings(obj)
+	if req.Operation != admissionv1.Delete {
+		warnings = append(warnings, MaybeGetClusterWarnings(ctx, v.client, obj)...)
+	}
 
 	if err := v.commonValidations(ctx, req, obj); err != nil {
 		return admission.Denied(err.Error()).WithWarnings(warnings...)
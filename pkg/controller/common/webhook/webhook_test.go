@@ -10,12 +10,16 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
@@ -317,3 +321,46 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 		})
 	}
 }
+
+func Test_validatingWebhook_Handle_ClusterWarnings(t *testing.T) {
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "elastic", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			Version:  "8.12.0",
+			NodeSets: []esv1.NodeSet{{Name: "default", Count: 1}},
+		},
+	}
+	kibana := func(count int32) []byte {
+		return asJSON(&kbv1.Kibana{
+			ObjectMeta: metav1.ObjectMeta{Name: "kb", Namespace: "elastic"},
+			Spec: kbv1.KibanaSpec{
+				Version:          "8.12.0",
+				Count:            count,
+				ElasticsearchRef: commonv1.ObjectSelector{Name: "es"},
+			},
+		})
+	}
+	v := &validatingWebhook{
+		client:            k8s.NewFakeClient(es),
+		decoder:           admission.NewDecoder(k8s.Scheme()),
+		managedNamespaces: set.Make("elastic"),
+		validator:         &kbv1.Kibana{},
+	}
+	request := func(count int32) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: kibana(count)},
+		}}
+	}
+
+	// warnings are informational and do not prevent the resource from being admitted
+	got := v.Handle(context.Background(), request(3))
+	require.True(t, got.Allowed)
+	require.Equal(t, []string{
+		"spec.count: 3 Kibana instances for 1 Elasticsearch nodes, consider scaling Elasticsearch up or Kibana down to avoid overloading Elasticsearch",
+	}, got.Warnings)
+
+	got = v.Handle(context.Background(), request(2))
+	require.True(t, got.Allowed)
+	require.Empty(t, got.Warnings)
+}
@@ -0,0 +1,46 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+const (
+	// HTTPCertificateType is the type of the certificate served on the HTTP layer.
+	HTTPCertificateType = "http"
+	// TransportCACertificateType is the type of the CA certificate issuing the transport certificates.
+	TransportCACertificateType = "transport_ca"
+)
+
+// Expiration is the expiration date of a certificate managed for an Elasticsearch cluster.
+type Expiration struct {
+	Type         string
+	NotAfter     time.Time
+	RotateBefore time.Duration // rotation threshold of the certificate
+}
+
+// RemainingValidity returns the remaining validity of the certificate, negative once expired.
+func (e Expiration) RemainingValidity(now time.Time) time.Duration {
+	return e.NotAfter.Sub(now)
+}
+
+// WithinRotationThreshold returns true if the certificate expires within its rotation threshold.
+func (e Expiration) WithinRotationThreshold(now time.Time) bool {
+	return e.RemainingValidity(now) <= e.RotateBefore
+}
+
+// ReportRemainingValidity reports the remaining validity of the certificates of the given cluster in the metrics.
+func ReportRemainingValidity(es types.NamespacedName, now time.Time, expirations []Expiration) {
+	remainingValidity := make(map[string]time.Duration, len(expirations))
+	for _, expiration := range expirations {
+		remainingValidity[expiration.Type] = expiration.RemainingValidity(now)
+	}
+	metrics.SetCertificatesRemainingValidity(es.Namespace, es.Name, remainingValidity)
+}
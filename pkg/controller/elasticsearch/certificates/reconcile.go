@@ -83,7 +83,8 @@ func ReconcileHTTP(
 	return trustedHTTPCertificates, nil
 }
 
-// ReconcileTransport reconciles the transport layer certificates of a cluster.
+// ReconcileTransport reconciles the transport layer certificates of a cluster. It returns the CA certificate issuing
+// the transport certificates.
 func ReconcileTransport(
 	ctx context.Context,
 	driver driver.Interface,
@@ -91,7 +92,7 @@ func ReconcileTransport(
 	globalCA *certificates.CA,
 	caRotation certificates.RotationParams,
 	certRotation certificates.RotationParams,
-) *reconciler.Results {
+) (*x509.Certificate, *reconciler.Results) {
 	span, ctx := apm.StartSpan(ctx, "reconcile_transport_certs", tracing.SpanTypeApp)
 	defer span.End()
 
@@ -105,7 +106,7 @@ func ReconcileTransport(
 	additionalCAs, err := transport.ReconcileAdditionalCAs(ctx, driver.K8sClient(), es, driver.DynamicWatches())
 	if err != nil {
 		driver.Recorder().Eventf(&es, corev1.EventTypeWarning, events.EventReasonUnexpected, err.Error())
-		return results.WithError(err)
+		return nil, results.WithError(err)
 	}
 
 	// reconcile transport CA and certs
@@ -118,7 +119,7 @@ func ReconcileTransport(
 		caRotation,
 	)
 	if err != nil {
-		return results.WithError(err)
+		return nil, results.WithError(err)
 	}
 	// make sure to requeue before the CA cert expires
 	results.WithReconciliationState(
@@ -129,7 +130,7 @@ func ReconcileTransport(
 
 	// reconcile transport public certs secret
 	if err := transport.ReconcileTransportCertsPublicSecret(ctx, driver.K8sClient(), es, transportCA, additionalCAs); err != nil {
-		return results.WithError(err)
+		return nil, results.WithError(err)
 	}
 
 	// reconcile transport certificates
@@ -148,8 +149,8 @@ func ReconcileTransport(
 	}
 
 	if results.WithResults(transportResults).HasError() {
-		return results
+		return nil, results
 	}
 
-	return results
+	return transportCA.Cert, results
 }
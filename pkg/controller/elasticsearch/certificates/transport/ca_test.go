@@ -0,0 +1,94 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package transport
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func newCustomCASecret(t *testing.T, commonName string) (*certificates.CA, *corev1.Secret) {
+	t.Helper()
+	ca, err := certificates.NewSelfSignedCA(certificates.CABuilderOptions{Subject: pkix.Name{CommonName: commonName}})
+	require.NoError(t, err)
+	key, err := certificates.EncodePEMPrivateKey(ca.PrivateKey)
+	require.NoError(t, err)
+	return ca, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "central-ca"},
+		Data: map[string][]byte{
+			certificates.CAFileName:    certificates.EncodePEMCert(ca.Cert.Raw),
+			certificates.CAKeyFileName: key,
+		},
+	}
+}
+
+func TestReconcileOrRetrieveCA_CustomCA(t *testing.T) {
+	es := newEsBuilder().addNodeSet("sset1", 1).build()
+	es.Spec.Transport.TLS.Certificate = commonv1.SecretRef{SecretName: "central-ca"}
+	pod := newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(0).withIP("1.1.1.2").build()
+	customCA, customCASecret := newCustomCASecret(t, "central-ca")
+	c := k8s.NewFakeClient(customCASecret, pod)
+	d := driver.TestDriver{Client: c, Watches: watches.NewDynamicWatches(), FakeRecorder: record.NewFakeRecorder(10)}
+	rotation := certificates.RotationParams{Validity: certificates.DefaultCertValidity, RotateBefore: certificates.DefaultRotateBefore}
+
+	// reconcile the transport certificates of the Pod, returning the certificate issued to the Pod
+	reconcile := func(expectedCA *certificates.CA) *x509.Certificate {
+		t.Helper()
+		ca, err := ReconcileOrRetrieveCA(context.Background(), d, *es, nil, nil, rotation)
+		require.NoError(t, err)
+		require.Equal(t, expectedCA.Cert.Raw, ca.Cert.Raw)
+		results := ReconcileTransportCertificatesSecrets(context.Background(), c, ca, nil, *es, rotation)
+		require.False(t, results.HasError())
+
+		var secret corev1.Secret
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{
+			Namespace: testNamespace,
+			Name:      esv1.StatefulSetTransportCertificatesSecret(esv1.StatefulSet(testEsName, "sset1")),
+		}, &secret))
+		require.Equal(t, certificates.EncodePEMCert(expectedCA.Cert.Raw), secret.Data[certificates.CAFileName])
+		certs, err := certificates.ParsePEMCerts(secret.Data[PodCertFileName(sset.PodName(esv1.StatefulSet(testEsName, "sset1"), 0))])
+		require.NoError(t, err)
+		require.NotEmpty(t, certs)
+		return certs[0]
+	}
+
+	// the transport certificates are signed by the provided CA
+	cert := reconcile(customCA)
+	require.NoError(t, cert.CheckSignatureFrom(customCA.Cert))
+	// the operator does not generate its own CA
+	err := c.Get(context.Background(), types.NamespacedName{
+		Namespace: testNamespace,
+		Name:      certificates.CAInternalSecretName(esv1.ESNamer, testEsName, certificates.TransportCAType),
+	}, &corev1.Secret{})
+	require.True(t, apierrors.IsNotFound(err))
+
+	// the certificates are kept as long as the provided CA does not change
+	require.Equal(t, cert.Raw, reconcile(customCA).Raw)
+
+	// and reissued with the new CA once it is rotated
+	rotatedCA, rotatedCASecret := newCustomCASecret(t, "central-ca-rotated")
+	require.NoError(t, c.Update(context.Background(), rotatedCASecret))
+	reissued := reconcile(rotatedCA)
+	require.NotEqual(t, cert.SerialNumber, reissued.SerialNumber)
+	require.NoError(t, reissued.CheckSignatureFrom(rotatedCA.Cert))
+	require.Error(t, reissued.CheckSignatureFrom(customCA.Cert))
+}
@@ -192,6 +192,7 @@ func ensureTransportCertificatesSecretExists(
 			},
 		},
 	}
+	expected = reconciler.WithUserSecretMetadata(ctx, &es, expected)
 	// reconcile the secret resource:
 	// - create it if it doesn't exist
 	// - update labels & annotations if they don't match
@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"k8s.io/apimachinery/pkg/types"
@@ -28,9 +29,75 @@ type baseClient struct {
 	es       types.NamespacedName
 	caCerts  []*x509.Certificate
 	version  version.Version
+	timeouts requestTimeouts
+	retry    retryParams
 	debug    bool
 }
 
+// requestKind is the kind of an Elasticsearch request, which determines its timeout.
+type requestKind int
+
+const (
+	defaultRequest requestKind = iota
+	// healthRequest is a request expected to be fast, such as a cluster health request.
+	healthRequest
+	// longRunningRequest is a request which may take longer than the other requests, such as a flush.
+	longRunningRequest
+)
+
+type requestKindKey struct{}
+
+// withRequestKind returns a context setting the kind of the Elasticsearch requests made with it.
+func withRequestKind(ctx context.Context, kind requestKind) context.Context {
+	return context.WithValue(ctx, requestKindKey{}, kind)
+}
+
+// requestTimeouts holds the timeouts of the different kinds of Elasticsearch requests. A zero timeout means no timeout.
+type requestTimeouts struct {
+	defaultTimeout time.Duration
+	health         time.Duration
+	longRunning    time.Duration
+}
+
+// newRequestTimeouts returns the request timeouts of a client whose default timeout is the given one.
+func newRequestTimeouts(timeout time.Duration) requestTimeouts {
+	timeouts := requestTimeouts{defaultTimeout: timeout, health: timeout, longRunning: timeout}
+	if DefaultESClientHealthTimeout > 0 {
+		timeouts.health = DefaultESClientHealthTimeout
+	}
+	if timeout > 0 && DefaultESClientLongRequestTimeout > timeout {
+		timeouts.longRunning = DefaultESClientLongRequestTimeout
+	}
+	return timeouts
+}
+
+// forRequest returns the timeout of the requests made with the given context.
+func (t requestTimeouts) forRequest(ctx context.Context) time.Duration {
+	kind, _ := ctx.Value(requestKindKey{}).(requestKind)
+	switch kind {
+	case healthRequest:
+		return t.health
+	case longRunningRequest:
+		return t.longRunning
+	default:
+		return t.defaultTimeout
+	}
+}
+
+// max returns the greatest timeout, or zero if any kind of request has no timeout.
+func (t requestTimeouts) max() time.Duration {
+	if t.defaultTimeout == 0 || t.health == 0 || t.longRunning == 0 {
+		return 0
+	}
+	result := t.defaultTimeout
+	for _, timeout := range []time.Duration{t.health, t.longRunning} {
+		if timeout > result {
+			result = timeout
+		}
+	}
+	return result
+}
+
 // Close idle connections in the underlying http client.
 // Should be called once this client is not used anymore.
 func (c *baseClient) Close() {
@@ -139,6 +206,12 @@ func (c *baseClient) request(
 	}
 	request.Header.Set(commonhttp.InternalProductRequestHeaderKey, commonhttp.InternalProductRequestHeaderValue)
 
+	if timeout := c.timeouts.forRequest(ctx); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	if c.debug {
 		q := request.URL.Query()
 		q.Add("error_trace", "true")
@@ -146,7 +219,7 @@ func (c *baseClient) request(
 	}
 
 	var skippedErr error
-	resp, err := c.doRequest(ctx, request)
+	resp, err := c.doRequestWithRetry(ctx, request)
 	if skipErrFunc != nil && skipErrFunc(err) {
 		skippedErr = err
 		err = nil
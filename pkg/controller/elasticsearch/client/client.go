@@ -30,6 +30,18 @@ const (
 // DefaultESClientTimeout is the default timeout value for Elasticsearch requests.
 var DefaultESClientTimeout = 3 * time.Minute
 
+var (
+	// DefaultESClientHealthTimeout is the timeout value for the Elasticsearch health and info requests, which are
+	// expected to be fast. The client timeout is used if it is not set.
+	DefaultESClientHealthTimeout time.Duration
+	// DefaultESClientLongRequestTimeout is the timeout value for the long-running Elasticsearch requests, such as
+	// flushes or secure settings reloads. It is only applied if it is greater than the client timeout.
+	DefaultESClientLongRequestTimeout time.Duration
+	// DefaultESClientMaxConnsPerHost is the maximum number of connections opened by the Elasticsearch client to a
+	// cluster. There is no limit if it is not set.
+	DefaultESClientMaxConnsPerHost int
+)
+
 // BasicAuth contains credentials for an Elasticsearch user.
 type BasicAuth struct {
 	Name     string
@@ -63,6 +75,10 @@ type Client interface {
 	ShardLister
 	LicenseClient
 	SecurityClient
+	SnapshotRepositoryClient
+	IngestPipelineClient
+	SecurityRoleClient
+	ILMPolicyClient
 	// Close idle connections in the underlying http client.
 	Close()
 	// Equal returns true if other can be considered as the same client.
@@ -114,9 +130,10 @@ type Client interface {
 	// GetShutdown returns information about ongoing node shutdowns.
 	// Introduced in: Elasticsearch 7.14.0
 	GetShutdown(ctx context.Context, nodeID *string) (ShutdownResponse, error)
-	// PutShutdown initiates a node shutdown procedure for the given node.
+	// PutShutdown initiates a node shutdown procedure for the given node. A zero allocationDelay relies on the default
+	// delay Elasticsearch applies before reallocating the shards of a restarting node.
 	// Introduced in: Elasticsearch 7.14.0
-	PutShutdown(ctx context.Context, nodeID string, shutdownType ShutdownType, reason string) error
+	PutShutdown(ctx context.Context, nodeID string, shutdownType ShutdownType, reason string, allocationDelay time.Duration) error
 	// DeleteShutdown attempts to cancel an ongoing node shutdown.
 	// Introduced in: Elasticsearch 7.14.0
 	DeleteShutdown(ctx context.Context, nodeID string) error
@@ -155,7 +172,14 @@ func NewElasticsearchClient(
 	timeout time.Duration,
 	debug bool,
 ) Client {
-	client := commonhttp.Client(dialer, caCerts, timeout)
+	timeouts := newRequestTimeouts(timeout)
+	// the timeout of the HTTP client only bounds the requests exposing the response to the caller, the timeout of the
+	// other requests depends on their kind
+	client := commonhttp.Client(dialer, caCerts, timeouts.max())
+	if transport, ok := client.Transport.(*http.Transport); ok && DefaultESClientMaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = DefaultESClientMaxConnsPerHost
+		transport.MaxIdleConnsPerHost = DefaultESClientMaxConnsPerHost
+	}
 	client.Transport = apmelasticsearch.WrapRoundTripper(client.Transport)
 	base := &baseClient{
 		Endpoint: esURL,
@@ -163,6 +187,8 @@ func NewElasticsearchClient(
 		caCerts:  caCerts,
 		HTTP:     client,
 		es:       es,
+		timeouts: timeouts,
+		retry:    retryParams{maxAttempts: DefaultESClientMaxAttempts, backoff: DefaultESClientRetryBackoff},
 		debug:    debug,
 	}
 	return versioned(base, v)
@@ -183,6 +183,28 @@ func TestClientUsesJsonContentType(t *testing.T) {
 	assert.NoError(t, testClient.SetMinimumMasterNodes(context.Background(), 0))
 }
 
+func TestClient_UpdateRemoteClusterSettings(t *testing.T) {
+	// Elasticsearch versions before 7.7.0 reject the proxy mode settings, even if null
+	testClient := NewMockClient(version.MustParse("7.6.2"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_cluster/settings", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"persistent":{"cluster":{"remote":{"added":{"seeds":["es2-es-transport.ns.svc:9300"]},"deleted":{"seeds":null}}}}}`, string(body))
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, testClient.UpdateRemoteClusterSettings(context.Background(), RemoteClustersSettings{
+		PersistentSettings: &SettingsGroup{
+			Cluster: RemoteClusters{
+				RemoteClusters: map[string]RemoteCluster{
+					"added":   {Seeds: []string{"es2-es-transport.ns.svc:9300"}},
+					"deleted": {Seeds: nil},
+				},
+			},
+		},
+	}))
+}
+
 func TestClientSupportsBasicAuth(t *testing.T) {
 	type expected struct {
 		user        BasicAuth
@@ -685,6 +707,114 @@ func TestTimeout(t *testing.T) {
 	require.Equal(t, 1*time.Minute, have)
 }
 
+func Test_newRequestTimeouts(t *testing.T) {
+	defer func(health, longRequest time.Duration) {
+		DefaultESClientHealthTimeout = health
+		DefaultESClientLongRequestTimeout = longRequest
+	}(DefaultESClientHealthTimeout, DefaultESClientLongRequestTimeout)
+
+	tests := []struct {
+		name        string
+		timeout     time.Duration
+		health      time.Duration
+		longRequest time.Duration
+		want        requestTimeouts
+		wantMax     time.Duration
+	}{
+		{
+			name:    "client timeout only",
+			timeout: time.Minute,
+			want:    requestTimeouts{defaultTimeout: time.Minute, health: time.Minute, longRunning: time.Minute},
+			wantMax: time.Minute,
+		},
+		{
+			name:        "health and long request timeouts",
+			timeout:     time.Minute,
+			health:      5 * time.Second,
+			longRequest: 10 * time.Minute,
+			want:        requestTimeouts{defaultTimeout: time.Minute, health: 5 * time.Second, longRunning: 10 * time.Minute},
+			wantMax:     10 * time.Minute,
+		},
+		{
+			name:        "long request timeout lower than the client timeout",
+			timeout:     time.Minute,
+			longRequest: 30 * time.Second,
+			want:        requestTimeouts{defaultTimeout: time.Minute, health: time.Minute, longRunning: time.Minute},
+			wantMax:     time.Minute,
+		},
+		{
+			name:        "no client timeout",
+			timeout:     0,
+			health:      5 * time.Second,
+			longRequest: 10 * time.Minute,
+			want:        requestTimeouts{health: 5 * time.Second},
+			wantMax:     0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			DefaultESClientHealthTimeout = tt.health
+			DefaultESClientLongRequestTimeout = tt.longRequest
+			got := newRequestTimeouts(tt.timeout)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.wantMax, got.max())
+		})
+	}
+}
+
+func TestClient_RequestTimeouts(t *testing.T) {
+	var timeout time.Duration
+	base := &baseClient{
+		HTTP: &http.Client{
+			Transport: requestAssertion(func(req *http.Request) {
+				deadline, ok := req.Context().Deadline()
+				require.True(t, ok)
+				timeout = time.Until(deadline)
+			}),
+		},
+		Endpoint: "http://example.com",
+		timeouts: requestTimeouts{defaultTimeout: time.Minute, health: 5 * time.Second, longRunning: 10 * time.Minute},
+	}
+	testClient := versioned(base, version.MustParse("7.17.0"))
+
+	tests := []struct {
+		name    string
+		request func() error
+		want    time.Duration
+	}{
+		{
+			name: "health request",
+			request: func() error {
+				_, err := testClient.GetClusterHealth(context.Background())
+				return err
+			},
+			want: 5 * time.Second,
+		},
+		{
+			name: "default request",
+			request: func() error {
+				_, err := testClient.GetNodes(context.Background())
+				return err
+			},
+			want: time.Minute,
+		},
+		{
+			name: "long-running request",
+			request: func() error {
+				return testClient.Flush(context.Background())
+			},
+			want: 10 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.request())
+			require.LessOrEqual(t, timeout, tt.want)
+			require.Greater(t, timeout, tt.want-time.Second)
+		})
+	}
+}
+
 func TestFormatAsSeconds(t *testing.T) {
 	have := formatAsSeconds(2 * time.Minute)
 	require.Equal(t, "120s", have)
@@ -0,0 +1,56 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+var ilmMinVersion = version.MinFor(6, 6, 0)
+
+type ILMPolicyClient interface {
+	// IsILMSupported returns true if the index lifecycle management API is available.
+	IsILMSupported() bool
+	// GetILMPolicies returns the index lifecycle policies of the cluster, indexed by name.
+	GetILMPolicies(ctx context.Context) (ILMPolicies, error)
+	// UpdateILMPolicy creates an index lifecycle policy, or updates it if it already exists.
+	UpdateILMPolicy(ctx context.Context, name string, policy ILMPolicy) error
+}
+
+// ILMPolicies maps index lifecycle policy names to their definition.
+type ILMPolicies map[string]ILMPolicy
+
+// ILMPolicy is an index lifecycle policy as exposed by the _ilm/policy API.
+type ILMPolicy struct {
+	Policy map[string]interface{} `json:"policy"`
+}
+
+func (c *baseClient) IsILMSupported() bool {
+	return c.version.GTE(ilmMinVersion)
+}
+
+func (c *baseClient) ilmNotAvailable() error {
+	return fmt.Errorf("the index lifecycle management API is not available in Elasticsearch %s, it requires %s", c.version, ilmMinVersion)
+}
+
+func (c *baseClient) GetILMPolicies(ctx context.Context) (ILMPolicies, error) {
+	if !c.IsILMSupported() {
+		return nil, c.ilmNotAvailable()
+	}
+	var policies ILMPolicies
+	err := c.get(ctx, "/_ilm/policy", &policies)
+	return policies, err
+}
+
+func (c *baseClient) UpdateILMPolicy(ctx context.Context, name string, policy ILMPolicy) error {
+	if !c.IsILMSupported() {
+		return c.ilmNotAvailable()
+	}
+	return c.put(ctx, fmt.Sprintf("/_ilm/policy/%s", url.PathEscape(name)), policy, nil)
+}
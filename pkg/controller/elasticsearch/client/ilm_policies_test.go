@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+const sampleILMPolicies = `{
+  "retention": {
+    "version": 2,
+    "modified_date": "2024-01-01T00:00:00.000Z",
+    "policy": {
+      "phases": {
+        "delete": {
+          "min_age": "30d",
+          "actions": {
+            "delete": {
+              "delete_searchable_snapshot": true
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestClient_GetILMPolicies(t *testing.T) {
+	for _, v := range []string{"6.8.0", "7.17.0", "8.11.0"} {
+		client := NewMockClient(version.MustParse(v), func(req *http.Request) *http.Response {
+			require.Equal(t, http.MethodGet, req.Method)
+			require.Equal(t, "/_ilm/policy", req.URL.Path)
+			return NewMockResponse(200, req, sampleILMPolicies)
+		})
+		require.True(t, client.IsILMSupported())
+		got, err := client.GetILMPolicies(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, ILMPolicies{
+			"retention": {Policy: map[string]interface{}{
+				"phases": map[string]interface{}{
+					"delete": map[string]interface{}{
+						"min_age": "30d",
+						"actions": map[string]interface{}{
+							"delete": map[string]interface{}{"delete_searchable_snapshot": true},
+						},
+					},
+				},
+			}},
+		}, got)
+	}
+}
+
+func TestClient_UpdateILMPolicy(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.11.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_ilm/policy/retention", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"policy":{"phases":{"delete":{"min_age":"30d","actions":{"delete":{}}}}}}`, string(body))
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	err := client.UpdateILMPolicy(context.Background(), "retention", ILMPolicy{Policy: map[string]interface{}{
+		"phases": map[string]interface{}{
+			"delete": map[string]interface{}{
+				"min_age": "30d",
+				"actions": map[string]interface{}{"delete": map[string]interface{}{}},
+			},
+		},
+	}})
+	require.NoError(t, err)
+}
+
+func TestClient_ILMNotSupported(t *testing.T) {
+	client := NewMockClient(version.MustParse("6.5.4"), func(req *http.Request) *http.Response {
+		t.Fatalf("unexpected request %s %s", req.Method, req.URL.Path)
+		return nil
+	})
+	require.False(t, client.IsILMSupported())
+	_, err := client.GetILMPolicies(context.Background())
+	require.Error(t, err)
+	require.Error(t, client.UpdateILMPolicy(context.Background(), "retention", ILMPolicy{}))
+}
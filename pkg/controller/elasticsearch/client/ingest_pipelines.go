@@ -0,0 +1,44 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+type IngestPipelineClient interface {
+	// GetIngestPipelines returns the ingest pipelines of the cluster, indexed by name.
+	GetIngestPipelines(ctx context.Context) (IngestPipelines, error)
+	// UpdateIngestPipeline creates an ingest pipeline, or updates it if it already exists.
+	UpdateIngestPipeline(ctx context.Context, name string, pipeline IngestPipeline) error
+	// DeleteIngestPipeline deletes an ingest pipeline.
+	DeleteIngestPipeline(ctx context.Context, name string) error
+}
+
+// IngestPipelines maps ingest pipeline names to their definition.
+type IngestPipelines map[string]IngestPipeline
+
+// IngestPipeline is the definition of an ingest pipeline as exposed by the _ingest/pipeline API.
+type IngestPipeline map[string]interface{}
+
+func (c *baseClient) GetIngestPipelines(ctx context.Context) (IngestPipelines, error) {
+	var pipelines IngestPipelines
+	err := c.get(ctx, "/_ingest/pipeline", &pipelines)
+	if IsNotFound(err) {
+		// Elasticsearch responds with a 404 status code when there is no pipeline
+		return IngestPipelines{}, nil
+	}
+	return pipelines, err
+}
+
+func (c *baseClient) UpdateIngestPipeline(ctx context.Context, name string, pipeline IngestPipeline) error {
+	return c.put(ctx, fmt.Sprintf("/_ingest/pipeline/%s", url.PathEscape(name)), pipeline, nil)
+}
+
+func (c *baseClient) DeleteIngestPipeline(ctx context.Context, name string) error {
+	return c.delete(ctx, fmt.Sprintf("/_ingest/pipeline/%s", url.PathEscape(name)))
+}
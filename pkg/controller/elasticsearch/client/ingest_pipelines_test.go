@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+const sampleIngestPipelines = `{
+  "add-timestamp": {
+    "description": "Add the ingest timestamp",
+    "processors": [
+      {
+        "set": {
+          "field": "ingested_at",
+          "value": "{{_ingest.timestamp}}"
+        }
+      }
+    ]
+  }
+}`
+
+func TestClient_GetIngestPipelines(t *testing.T) {
+	for _, v := range []string{"6.8.0", "7.17.0", "8.11.0"} {
+		client := NewMockClient(version.MustParse(v), func(req *http.Request) *http.Response {
+			require.Equal(t, http.MethodGet, req.Method)
+			require.Equal(t, "/_ingest/pipeline", req.URL.Path)
+			return NewMockResponse(200, req, sampleIngestPipelines)
+		})
+		got, err := client.GetIngestPipelines(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, IngestPipelines{
+			"add-timestamp": {
+				"description": "Add the ingest timestamp",
+				"processors": []interface{}{
+					map[string]interface{}{"set": map[string]interface{}{"field": "ingested_at", "value": "{{_ingest.timestamp}}"}},
+				},
+			},
+		}, got)
+	}
+}
+
+func TestClient_GetIngestPipelinesNoPipeline(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.11.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(404, req, `{}`)
+	})
+	got, err := client.GetIngestPipelines(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestClient_UpdateIngestPipeline(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.11.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_ingest/pipeline/add-timestamp", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"processors":[{"set":{"field":"ingested_at","value":"{{_ingest.timestamp}}"}}]}`, string(body))
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	err := client.UpdateIngestPipeline(context.Background(), "add-timestamp", IngestPipeline{
+		"processors": []interface{}{
+			map[string]interface{}{"set": map[string]interface{}{"field": "ingested_at", "value": "{{_ingest.timestamp}}"}},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestClient_DeleteIngestPipeline(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.11.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_ingest/pipeline/add-timestamp", req.URL.Path)
+		return NewMockResponse(200, req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, client.DeleteIngestPipeline(context.Background(), "add-timestamp"))
+}
@@ -394,6 +394,27 @@ type RemoteClusters struct {
 // RemoteCluster is the set of seeds to use in a remote cluster setting.
 type RemoteCluster struct {
 	Seeds []string `json:"seeds"`
+	// Mode and ProxyAddress are only set for remote clusters connected in proxy mode, which requires Elasticsearch 7.7.0
+	// or later.
+	Mode         *string `json:"mode,omitempty"`
+	ProxyAddress *string `json:"proxy_address,omitempty"`
+	// ResetProxyMode explicitly resets the mode and the proxy address of a remote cluster previously connected in proxy
+	// mode, when it is removed or connected in sniff mode.
+	ResetProxyMode bool `json:"-"`
+}
+
+// MarshalJSON serializes the remote cluster, with null mode and proxy address if the proxy mode must be reset.
+// They are omitted otherwise, as Elasticsearch versions before 7.7.0 reject these settings, even if null.
+func (rc RemoteCluster) MarshalJSON() ([]byte, error) {
+	type remoteCluster RemoteCluster
+	if !rc.ResetProxyMode || rc.Mode != nil {
+		return json.Marshal(remoteCluster(rc))
+	}
+	return json.Marshal(struct {
+		Seeds        []string `json:"seeds"`
+		Mode         *string  `json:"mode"`
+		ProxyAddress *string  `json:"proxy_address"`
+	}{Seeds: rc.Seeds})
 }
 
 // Hit represents a single search hit.
@@ -488,9 +509,9 @@ func (ns NodeShutdown) Is(t ShutdownType) bool {
 
 // ShutdownRequest is the body of a node shutdown request.
 type ShutdownRequest struct {
-	Type            ShutdownType  `json:"type"`
-	Reason          string        `json:"reason"`
-	AllocationDelay time.Duration `json:"allocation_delay,omitempty"`
+	Type            ShutdownType `json:"type"`
+	Reason          string       `json:"reason"`
+	AllocationDelay string       `json:"allocation_delay,omitempty"`
 }
 
 // ShutdownResponse is the response wrapper for retrieving the status of ongoing node shutdowns from Elasticsearch.
@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
 )
 
 func TestModel_RemoteCluster(t *testing.T) {
@@ -48,6 +49,54 @@ func TestModel_RemoteCluster(t *testing.T) {
 			},
 			want: `{"persistent":{"cluster":{"remote":{"leader":{"seeds":null}}}}}`,
 		},
+		{
+			name: "Deleted remote cluster previously in proxy mode",
+			arg: RemoteClustersSettings{
+				PersistentSettings: &SettingsGroup{
+					Cluster: RemoteClusters{
+						RemoteClusters: map[string]RemoteCluster{
+							"leader": {
+								Seeds:          nil,
+								ResetProxyMode: true,
+							},
+						},
+					},
+				},
+			},
+			want: `{"persistent":{"cluster":{"remote":{"leader":{"seeds":null,"mode":null,"proxy_address":null}}}}}`,
+		},
+		{
+			name: "Remote cluster previously in proxy mode connected in sniff mode",
+			arg: RemoteClustersSettings{
+				PersistentSettings: &SettingsGroup{
+					Cluster: RemoteClusters{
+						RemoteClusters: map[string]RemoteCluster{
+							"leader": {
+								Seeds:          []string{"127.0.0.1:9300"},
+								ResetProxyMode: true,
+							},
+						},
+					},
+				},
+			},
+			want: `{"persistent":{"cluster":{"remote":{"leader":{"seeds":["127.0.0.1:9300"],"mode":null,"proxy_address":null}}}}}`,
+		},
+		{
+			name: "Remote cluster in proxy mode",
+			arg: RemoteClustersSettings{
+				PersistentSettings: &SettingsGroup{
+					Cluster: RemoteClusters{
+						RemoteClusters: map[string]RemoteCluster{
+							"leader": {
+								Mode:         ptr.To("proxy"),
+								ProxyAddress: ptr.To("leader.example.com:9300"),
+							},
+						},
+					},
+				},
+			},
+			want: `{"persistent":{"cluster":{"remote":{"leader":{"seeds":null,"mode":"proxy","proxy_address":"leader.example.com:9300"}}}}}`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
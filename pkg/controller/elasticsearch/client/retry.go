@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"syscall"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+var (
+	// DefaultESClientMaxAttempts is the maximum number of attempts of the idempotent Elasticsearch requests failing
+	// with a transient error.
+	DefaultESClientMaxAttempts = 3
+	// DefaultESClientRetryBackoff is the initial delay between two attempts of an Elasticsearch request. It doubles
+	// with each attempt, with a random jitter.
+	DefaultESClientRetryBackoff = 100 * time.Millisecond
+)
+
+// retryParams controls the retries of the idempotent requests failing with a transient error.
+type retryParams struct {
+	// maxAttempts is the maximum number of attempts of a request, requests are not retried if lower than 2.
+	maxAttempts int
+	// backoff is the initial delay between two attempts.
+	backoff time.Duration
+}
+
+// isIdempotent returns true if requests with the given method can safely be sent again. Only read requests are
+// considered idempotent, since some Elasticsearch write APIs are not.
+func isIdempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// isRetryable returns true if the given error is likely to be transient: the Elasticsearch cluster is overloaded or
+// temporarily unavailable, or the connection was reset.
+func isRetryable(err error) bool {
+	apiErr := new(APIError)
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		default:
+			return false
+		}
+	}
+	return errors.Is(err, syscall.ECONNRESET)
+}
+
+// doRequestWithRetry performs the request, retrying the idempotent ones with an exponential backoff and jitter while
+// they fail with a transient error. The error of the last attempt is returned once the attempts are exhausted.
+func (c *baseClient) doRequestWithRetry(ctx context.Context, request *http.Request) (*http.Response, error) {
+	maxAttempts := 1
+	if isIdempotent(request.Method) && c.retry.maxAttempts > 1 {
+		maxAttempts = c.retry.maxAttempts
+	}
+	backoff := c.retry.backoff
+	for attempt := 1; ; attempt++ {
+		resp, err := c.doRequest(ctx, request.Clone(ctx))
+		if err == nil || attempt >= maxAttempts || !isRetryable(err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		delay := wait.Jitter(backoff, 1.0)
+		ulog.FromContext(ctx).V(1).Info(
+			"Retrying Elasticsearch request",
+			"method", request.Method,
+			"url", request.URL.Redacted(),
+			"namespace", c.es.Namespace,
+			"es_name", c.es.Name,
+			"attempt", attempt,
+			"delay", delay,
+			"error", err.Error(),
+		)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// attemptsRoundTripper returns the given results in order, one per attempt, repeating the last one.
+type attemptsRoundTripper struct {
+	results  []func(req *http.Request) (*http.Response, error)
+	attempts int
+}
+
+func (a *attemptsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	result := a.results[min(a.attempts, len(a.results)-1)]
+	a.attempts++
+	return result(req)
+}
+
+func status(code int) func(req *http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		return NewMockResponse(code, req, `{}`), nil
+	}
+}
+
+func connectionReset(_ *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("read tcp: %w", syscall.ECONNRESET)
+}
+
+func Test_baseClient_doRequestWithRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		results      []func(req *http.Request) (*http.Response, error)
+		wantErr      bool
+		wantStatus   int
+		wantAttempts int
+	}{
+		{
+			name:         "no retry on success",
+			method:       http.MethodGet,
+			results:      []func(req *http.Request) (*http.Response, error){status(200)},
+			wantAttempts: 1,
+		},
+		{
+			name:         "retry on 503",
+			method:       http.MethodGet,
+			results:      []func(req *http.Request) (*http.Response, error){status(503), status(200)},
+			wantAttempts: 2,
+		},
+		{
+			name:         "retry on connection reset",
+			method:       http.MethodGet,
+			results:      []func(req *http.Request) (*http.Response, error){connectionReset, status(200)},
+			wantAttempts: 2,
+		},
+		{
+			name:         "no retry on 400",
+			method:       http.MethodGet,
+			results:      []func(req *http.Request) (*http.Response, error){status(400), status(200)},
+			wantErr:      true,
+			wantStatus:   400,
+			wantAttempts: 1,
+		},
+		{
+			name:         "max attempts are respected",
+			method:       http.MethodGet,
+			results:      []func(req *http.Request) (*http.Response, error){status(503)},
+			wantErr:      true,
+			wantStatus:   503,
+			wantAttempts: 3,
+		},
+		{
+			name:         "no retry of non-idempotent requests",
+			method:       http.MethodPost,
+			results:      []func(req *http.Request) (*http.Response, error){status(503), status(200)},
+			wantErr:      true,
+			wantStatus:   503,
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &attemptsRoundTripper{results: tt.results}
+			c := &baseClient{
+				HTTP:     &http.Client{Transport: transport},
+				Endpoint: "http://example.com",
+				retry:    retryParams{maxAttempts: 3, backoff: time.Millisecond},
+			}
+			err := c.request(context.Background(), tt.method, "/_cluster/health", nil, nil, nil)
+			require.Equal(t, tt.wantAttempts, transport.attempts)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.True(t, isHTTPError(err, tt.wantStatus), err.Error())
+		})
+	}
+}
+
+func Test_baseClient_doRequestWithRetry_contextCancelled(t *testing.T) {
+	transport := &attemptsRoundTripper{results: []func(req *http.Request) (*http.Response, error){status(503)}}
+	c := &baseClient{
+		HTTP:     &http.Client{Transport: transport},
+		Endpoint: "http://example.com",
+		retry:    retryParams{maxAttempts: 3, backoff: time.Hour},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := c.get(ctx, "/_cluster/health", nil)
+	require.True(t, isHTTPError(err, 503))
+	require.Equal(t, 1, transport.attempts)
+}
@@ -7,6 +7,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/url"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
@@ -38,12 +39,29 @@ type SecurityClient interface {
 
 	// GetServiceAccountCredentials returns the service account credentials from the /_security/service API
 	GetServiceAccountCredentials(ctx context.Context, namespacedService string) (ServiceAccountCredential, error)
+	// ChangePassword changes the password of the given native or reserved user.
+	ChangePassword(ctx context.Context, username string, password []byte) error
+}
+
+// ChangePasswordRequest is the body of a request to the change password API.
+type ChangePasswordRequest struct {
+	Password string `json:"password"`
 }
 
 func (c *clientV6) GetServiceAccountCredentials(_ context.Context, _ string) (ServiceAccountCredential, error) {
 	return ServiceAccountCredential{}, errNotSupportedInEs6x
 }
 
+func (c *clientV6) ChangePassword(ctx context.Context, username string, password []byte) error {
+	path := fmt.Sprintf("/_xpack/security/user/%s/_password", url.PathEscape(username))
+	return c.post(ctx, path, ChangePasswordRequest{Password: string(password)}, nil)
+}
+
+func (c *clientV7) ChangePassword(ctx context.Context, username string, password []byte) error {
+	path := fmt.Sprintf("/_security/user/%s/_password", url.PathEscape(username))
+	return c.post(ctx, path, ChangePasswordRequest{Password: string(password)}, nil)
+}
+
 func (c *clientV7) GetServiceAccountCredentials(ctx context.Context, namespacedService string) (ServiceAccountCredential, error) {
 	var serviceAccountCredential ServiceAccountCredential
 	path := fmt.Sprintf("/_security/service/%s/credential", namespacedService)
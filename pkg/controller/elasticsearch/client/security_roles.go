@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+type SecurityRoleClient interface {
+	// GetSecurityRoles returns the roles of the native realm and the reserved roles, indexed by name.
+	GetSecurityRoles(ctx context.Context) (SecurityRoles, error)
+	// UpdateSecurityRole creates a role in the native realm, or updates it if it already exists.
+	UpdateSecurityRole(ctx context.Context, name string, role SecurityRole) error
+	// DeleteSecurityRole deletes a role from the native realm.
+	DeleteSecurityRole(ctx context.Context, name string) error
+	// GetRoleMappings returns the role mappings of the cluster, indexed by name.
+	GetRoleMappings(ctx context.Context) (RoleMappings, error)
+	// UpdateRoleMapping creates a role mapping, or updates it if it already exists.
+	UpdateRoleMapping(ctx context.Context, name string, mapping RoleMapping) error
+	// DeleteRoleMapping deletes a role mapping.
+	DeleteRoleMapping(ctx context.Context, name string) error
+}
+
+// SecurityRoles maps role names to their definition.
+type SecurityRoles map[string]SecurityRole
+
+// SecurityRole is the definition of a role as exposed by the _security/role API.
+type SecurityRole map[string]interface{}
+
+// RoleMappings maps role mapping names to their definition.
+type RoleMappings map[string]RoleMapping
+
+// RoleMapping is the definition of a role mapping as exposed by the _security/role_mapping API.
+type RoleMapping map[string]interface{}
+
+func (c *baseClient) GetSecurityRoles(ctx context.Context) (SecurityRoles, error) {
+	var roles SecurityRoles
+	err := c.get(ctx, "/_security/role", &roles)
+	return roles, err
+}
+
+func (c *baseClient) UpdateSecurityRole(ctx context.Context, name string, role SecurityRole) error {
+	return c.put(ctx, fmt.Sprintf("/_security/role/%s", url.PathEscape(name)), role, nil)
+}
+
+func (c *baseClient) DeleteSecurityRole(ctx context.Context, name string) error {
+	return c.delete(ctx, fmt.Sprintf("/_security/role/%s", url.PathEscape(name)))
+}
+
+func (c *baseClient) GetRoleMappings(ctx context.Context) (RoleMappings, error) {
+	var mappings RoleMappings
+	err := c.get(ctx, "/_security/role_mapping", &mappings)
+	if IsNotFound(err) {
+		// Elasticsearch responds with a 404 status code when there is no role mapping
+		return RoleMappings{}, nil
+	}
+	return mappings, err
+}
+
+func (c *baseClient) UpdateRoleMapping(ctx context.Context, name string, mapping RoleMapping) error {
+	return c.put(ctx, fmt.Sprintf("/_security/role_mapping/%s", url.PathEscape(name)), mapping, nil)
+}
+
+func (c *baseClient) DeleteRoleMapping(ctx context.Context, name string) error {
+	return c.delete(ctx, fmt.Sprintf("/_security/role_mapping/%s", url.PathEscape(name)))
+}
@@ -0,0 +1,126 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+const sampleSecurityRoles = `{
+  "logs-reader": {
+    "cluster": ["monitor"],
+    "indices": [
+      {
+        "names": ["logs-*"],
+        "privileges": ["read"],
+        "allow_restricted_indices": false
+      }
+    ],
+    "applications": [],
+    "run_as": [],
+    "metadata": {},
+    "transient_metadata": {"enabled": true}
+  }
+}`
+
+func TestClient_GetSecurityRoles(t *testing.T) {
+	for _, v := range []string{"6.8.0", "7.17.0", "8.11.0"} {
+		client := NewMockClient(version.MustParse(v), func(req *http.Request) *http.Response {
+			require.Equal(t, http.MethodGet, req.Method)
+			require.Equal(t, "/_security/role", req.URL.Path)
+			return NewMockResponse(200, req, sampleSecurityRoles)
+		})
+		got, err := client.GetSecurityRoles(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, SecurityRoles{
+			"logs-reader": {
+				"cluster": []interface{}{"monitor"},
+				"indices": []interface{}{
+					map[string]interface{}{"names": []interface{}{"logs-*"}, "privileges": []interface{}{"read"}, "allow_restricted_indices": false},
+				},
+				"applications":       []interface{}{},
+				"run_as":             []interface{}{},
+				"metadata":           map[string]interface{}{},
+				"transient_metadata": map[string]interface{}{"enabled": true},
+			},
+		}, got)
+	}
+}
+
+func TestClient_UpdateSecurityRole(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.11.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_security/role/logs-reader", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"cluster":["monitor"]}`, string(body))
+		return NewMockResponse(200, req, `{"role":{"created":true}}`)
+	})
+	require.NoError(t, client.UpdateSecurityRole(context.Background(), "logs-reader", SecurityRole{"cluster": []string{"monitor"}}))
+}
+
+func TestClient_DeleteSecurityRole(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.11.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_security/role/logs-reader", req.URL.Path)
+		return NewMockResponse(200, req, `{"found":true}`)
+	})
+	require.NoError(t, client.DeleteSecurityRole(context.Background(), "logs-reader"))
+}
+
+func TestClient_GetRoleMappings(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.11.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_security/role_mapping", req.URL.Path)
+		return NewMockResponse(200, req, `{"saml-readers":{"enabled":true,"roles":["logs-reader"],"rules":{"field":{"realm.name":"saml1"}},"metadata":{}}}`)
+	})
+	got, err := client.GetRoleMappings(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, RoleMappings{
+		"saml-readers": {
+			"enabled":  true,
+			"roles":    []interface{}{"logs-reader"},
+			"rules":    map[string]interface{}{"field": map[string]interface{}{"realm.name": "saml1"}},
+			"metadata": map[string]interface{}{},
+		},
+	}, got)
+}
+
+func TestClient_GetRoleMappingsNoMapping(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.11.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(404, req, `{}`)
+	})
+	got, err := client.GetRoleMappings(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestClient_UpdateRoleMapping(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.11.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_security/role_mapping/saml-readers", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"roles":["logs-reader"],"enabled":true}`, string(body))
+		return NewMockResponse(200, req, `{"role_mapping":{"created":true}}`)
+	})
+	require.NoError(t, client.UpdateRoleMapping(context.Background(), "saml-readers", RoleMapping{"roles": []string{"logs-reader"}, "enabled": true}))
+}
+
+func TestClient_DeleteRoleMapping(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.11.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_security/role_mapping/saml-readers", req.URL.Path)
+		return NewMockResponse(200, req, `{"found":true}`)
+	})
+	require.NoError(t, client.DeleteRoleMapping(context.Background(), "saml-readers"))
+}
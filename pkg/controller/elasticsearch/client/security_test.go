@@ -76,3 +76,32 @@ func Test_GetServiceAccountCredentials(t *testing.T) {
 		})
 	}
 }
+
+func Test_ChangePassword(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		version  string
+		wantPath string
+	}{
+		{name: "6.x", version: "6.8.0", wantPath: "/_xpack/security/user/kibana_system/_password"},
+		{name: "7.x", version: "7.17.0", wantPath: "/_security/user/kibana_system/_password"},
+		{name: "8.x", version: "8.13.0", wantPath: "/_security/user/kibana_system/_password"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewMockClient(version.MustParse(tt.version), func(req *http.Request) *http.Response {
+				require.Equal(t, http.MethodPost, req.Method)
+				require.Equal(t, tt.wantPath, req.URL.Path)
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, `{"password":"s3cr3t"}`, string(body))
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(`{}`)),
+					Header:     make(http.Header),
+					Request:    req,
+				}
+			})
+			require.NoError(t, client.ChangePassword(context.Background(), "kibana_system", []byte("s3cr3t")))
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+type SnapshotRepositoryClient interface {
+	// GetSnapshotRepositories returns the snapshot repositories registered in the cluster, indexed by name.
+	GetSnapshotRepositories(ctx context.Context) (SnapshotRepositories, error)
+	// UpdateSnapshotRepository registers a snapshot repository, or updates it if it already exists.
+	UpdateSnapshotRepository(ctx context.Context, name string, repository SnapshotRepository) error
+}
+
+// SnapshotRepositories maps snapshot repository names to their definition.
+type SnapshotRepositories map[string]SnapshotRepository
+
+// SnapshotRepository is the definition of a snapshot repository as exposed by the _snapshot API.
+type SnapshotRepository struct {
+	Type     string                 `json:"type"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+func (c *baseClient) GetSnapshotRepositories(ctx context.Context) (SnapshotRepositories, error) {
+	var repositories SnapshotRepositories
+	err := c.get(ctx, "/_snapshot", &repositories)
+	return repositories, err
+}
+
+func (c *baseClient) UpdateSnapshotRepository(ctx context.Context, name string, repository SnapshotRepository) error {
+	return c.put(ctx, fmt.Sprintf("/_snapshot/%s", url.PathEscape(name)), repository, nil)
+}
@@ -0,0 +1,74 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+const sampleSnapshotRepositories = `{
+  "s3-backups": {
+    "type": "s3",
+    "settings": {
+      "bucket": "my-bucket",
+      "compress": "true"
+    }
+  },
+  "fs-backups": {
+    "type": "fs",
+    "settings": {
+      "location": "/mnt/backups"
+    }
+  }
+}`
+
+func TestClient_GetSnapshotRepositories(t *testing.T) {
+	for _, v := range []string{"6.8.0", "7.17.0", "8.11.0"} {
+		client := NewMockClient(version.MustParse(v), func(req *http.Request) *http.Response {
+			require.Equal(t, http.MethodGet, req.Method)
+			require.Equal(t, "/_snapshot", req.URL.Path)
+			return NewMockResponse(200, req, sampleSnapshotRepositories)
+		})
+		got, err := client.GetSnapshotRepositories(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, SnapshotRepositories{
+			"s3-backups": {Type: "s3", Settings: map[string]interface{}{"bucket": "my-bucket", "compress": "true"}},
+			"fs-backups": {Type: "fs", Settings: map[string]interface{}{"location": "/mnt/backups"}},
+		}, got)
+	}
+}
+
+func TestClient_UpdateSnapshotRepository(t *testing.T) {
+	for _, v := range []string{"6.8.0", "7.17.0", "8.11.0"} {
+		client := NewMockClient(version.MustParse(v), func(req *http.Request) *http.Response {
+			require.Equal(t, http.MethodPut, req.Method)
+			require.Equal(t, "/_snapshot/s3-backups", req.URL.Path)
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"type":"s3","settings":{"bucket":"my-bucket","compress":true}}`, string(body))
+			return NewMockResponse(200, req, `{"acknowledged":true}`)
+		})
+		err := client.UpdateSnapshotRepository(context.Background(), "s3-backups", SnapshotRepository{
+			Type:     "s3",
+			Settings: map[string]interface{}{"bucket": "my-bucket", "compress": true},
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestClient_UpdateSnapshotRepositoryError(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.11.0"), func(req *http.Request) *http.Response {
+		return NewMockResponse(500, req, `{"error":{"type":"repository_verification_exception"}}`)
+	})
+	err := client.UpdateSnapshotRepository(context.Background(), "s3-backups", SnapshotRepository{Type: "s3"})
+	require.Error(t, err)
+}
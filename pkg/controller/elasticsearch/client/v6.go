@@ -8,6 +8,7 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -28,7 +29,7 @@ func (c *clientV6) Version() version.Version {
 
 func (c *clientV6) GetClusterInfo(ctx context.Context) (Info, error) {
 	var info Info
-	err := c.get(ctx, "/", &info)
+	err := c.get(withRequestKind(ctx, healthRequest), "/", &info)
 	return info, err
 }
 
@@ -72,16 +73,16 @@ func (c *clientV6) RemoveTransientAllocationSettings(ctx context.Context) error
 }
 
 func (c *clientV6) SyncedFlush(ctx context.Context) error {
-	return c.post(ctx, "/_flush/synced", nil, nil)
+	return c.post(withRequestKind(ctx, longRunningRequest), "/_flush/synced", nil, nil)
 }
 
 func (c *clientV6) Flush(ctx context.Context) error {
-	return c.post(ctx, "/_flush", nil, nil)
+	return c.post(withRequestKind(ctx, longRunningRequest), "/_flush", nil, nil)
 }
 
 func (c *clientV6) GetClusterHealth(ctx context.Context) (Health, error) {
 	var result Health
-	err := c.get(ctx, "/_cluster/health", &result)
+	err := c.get(withRequestKind(ctx, healthRequest), "/_cluster/health", &result)
 	return result, err
 }
 
@@ -92,7 +93,7 @@ func (c *clientV6) GetClusterHealthWaitForAllEvents(ctx context.Context) (Health
 	// ignore timeout errors as they are communicated in the returned payload and a timeout is to be expected
 	// given the query parameters. 408 for other reasons than the clients timeout parameter should not happen
 	// as they are expected only on idle connections https://go-review.googlesource.com/c/go/+/179457/4/src/net/http/transport.go#1931
-	err := c.request(ctx, http.MethodGet, pathWithQuery, nil, &result, IsTimeout)
+	err := c.request(withRequestKind(ctx, healthRequest), http.MethodGet, pathWithQuery, nil, &result, IsTimeout)
 	return result, err
 }
 
@@ -105,7 +106,7 @@ func (c *clientV6) SetMinimumMasterNodes(ctx context.Context, n int) error {
 }
 
 func (c *clientV6) ReloadSecureSettings(ctx context.Context) error {
-	return c.post(ctx, "/_nodes/reload_secure_settings", nil, nil)
+	return c.post(withRequestKind(ctx, longRunningRequest), "/_nodes/reload_secure_settings", nil, nil)
 }
 
 func (c *clientV6) GetNodes(ctx context.Context) (Nodes, error) {
@@ -184,7 +185,7 @@ func (c *clientV6) GetShutdown(context.Context, *string) (ShutdownResponse, erro
 	return ShutdownResponse{}, errNotSupportedInEs6x
 }
 
-func (c *clientV6) PutShutdown(context.Context, string, ShutdownType, string) error {
+func (c *clientV6) PutShutdown(context.Context, string, ShutdownType, string, time.Duration) error {
 	return errNotSupportedInEs6x
 }
 
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -68,11 +69,15 @@ func (c *clientV7) GetShutdown(ctx context.Context, nodeID *string) (ShutdownRes
 	return r, err
 }
 
-func (c *clientV7) PutShutdown(ctx context.Context, nodeID string, shutdownType ShutdownType, reason string) error {
+func (c *clientV7) PutShutdown(ctx context.Context, nodeID string, shutdownType ShutdownType, reason string, allocationDelay time.Duration) error {
 	request := ShutdownRequest{
 		Type:   shutdownType,
 		Reason: reason,
 	}
+	if allocationDelay > 0 {
+		// round up to whole seconds, so that a sub-second delay does not become 0s
+		request.AllocationDelay = fmt.Sprintf("%ds", (allocationDelay+time.Second-1)/time.Second)
+	}
 	return c.put(ctx, fmt.Sprintf("/_nodes/%s/shutdown", nodeID), request, nil)
 }
 
@@ -39,7 +39,12 @@ func ReconcileScriptsConfigMap(ctx context.Context, c k8s.Client, es esv1.Elasti
 	span, ctx := apm.StartSpan(ctx, "reconcile_scripts", tracing.SpanTypeApp)
 	defer span.End()
 
-	fsScript, err := initcontainer.RenderPrepareFsScript(es.DownwardNodeLabels())
+	fsScript, err := initcontainer.RenderPrepareFsScript(es.NodeLabelsAsPodAnnotations())
+	if err != nil {
+		return err
+	}
+
+	readinessProbeScript, err := nodespec.RenderReadinessProbeScript(es.HTTPPort(), es.ReadinessProbeWaitsForShardRecovery())
 	if err != nil {
 		return err
 	}
@@ -53,7 +58,7 @@ func ReconcileScriptsConfigMap(ctx context.Context, c k8s.Client, es esv1.Elasti
 		types.NamespacedName{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)},
 		k8s.ExtractNamespacedName(&es),
 		map[string]string{
-			nodespec.ReadinessProbeScriptConfigKey: nodespec.ReadinessProbeScript,
+			nodespec.ReadinessProbeScriptConfigKey: readinessProbeScript,
 			nodespec.PreStopHookScriptConfigKey:    preStopScript,
 			initcontainer.PrepareFsScriptConfigKey: fsScript,
 			initcontainer.SuspendScriptConfigKey:   initcontainer.SuspendScript,
@@ -68,7 +68,14 @@ func HandleDownscale(
 	// if leaving nodes is empty this should cancel any ongoing shutdowns
 	leavingNodes := leavingNodeNames(downscales)
 	terminatingNodes := k8s.PodNames(k8s.TerminatingPods(actualPods))
-	if err := downscaleCtx.nodeShutdown.ReconcileShutdowns(downscaleCtx.parentCtx, leavingNodes, terminatingNodes); err != nil {
+	// nodes with an ephemeral data volume are also shut down with a shutdown of type remove before being upgraded, do not
+	// cancel these shutdowns either
+	toUpgrade, err := podsToUpgrade(downscaleCtx.k8sClient, actualStatefulSets)
+	if err != nil {
+		return results.WithError(err)
+	}
+	upgradingNodes := podsWithEphemeralData(downscaleCtx.es, toUpgrade)
+	if err := downscaleCtx.nodeShutdown.ReconcileShutdowns(downscaleCtx.parentCtx, leavingNodes, append(terminatingNodes, upgradingNodes...)); err != nil {
 		return results.WithError(err)
 	}
 
@@ -17,13 +17,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/comparison"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
 	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/migration"
@@ -395,6 +398,77 @@ func TestHandleDownscale(t *testing.T) {
 	require.True(t, apierrors.IsNotFound(err))
 }
 
+func TestHandleDownscale_ephemeralDataNodesUpgrade(t *testing.T) {
+	tests := []struct {
+		name                 string
+		podRevision          string
+		wantShutdownDeletion bool
+	}{
+		{
+			name:                 "keep the shutdown of a node with ephemeral data being upgraded",
+			podRevision:          "old",
+			wantShutdownDeletion: false,
+		},
+		{
+			name:                 "cancel the shutdown of an upgraded node with ephemeral data",
+			podRevision:          "new",
+			wantShutdownDeletion: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+				Spec: esv1.ElasticsearchSpec{
+					Version: "7.17.0",
+					NodeSets: []esv1.NodeSet{
+						{Name: "search", Count: 1, EphemeralDataVolume: &esv1.EphemeralDataVolume{AcknowledgeDataLoss: true}},
+					},
+				},
+			}
+			statefulSet := sset.TestSset{
+				Namespace:   "ns",
+				Name:        esv1.StatefulSet("es", "search"),
+				ClusterName: "es",
+				Version:     "7.17.0",
+				Replicas:    1,
+				Data:        true,
+				Status:      appsv1.StatefulSetStatus{UpdateRevision: "new"},
+			}.Build()
+			pod := sset.TestPod{
+				Namespace:       "ns",
+				Name:            sset.PodName(statefulSet.Name, 0),
+				ClusterName:     "es",
+				StatefulSetName: statefulSet.Name,
+				Version:         "7.17.0",
+				Revision:        tt.podRevision,
+				Data:            true,
+				Ready:           true,
+			}.Build()
+			k8sClient := k8s.NewFakeClient(&statefulSet, &pod)
+			esClient := &fakeESClient{
+				version: version.MustParse("7.17.0"),
+				Shutdowns: map[string]esclient.NodeShutdown{
+					pod.Name: {NodeID: pod.Name, Type: string(esclient.Remove), Status: esclient.ShutdownInProgress},
+				},
+			}
+			downscaleCtx := downscaleContext{
+				k8sClient:      k8sClient,
+				expectations:   expectations.NewExpectations(k8sClient),
+				reconcileState: reconcile.MustNewState(es),
+				nodeShutdown:   shutdown.NewNodeShutdown(esClient, map[string]string{pod.Name: pod.Name}, esclient.Remove, "", crlog.Log),
+				esClient:       esClient,
+				es:             es,
+				parentCtx:      context.Background(),
+			}
+			statefulSets := es_sset.StatefulSetList{statefulSet}
+			results := HandleDownscale(downscaleCtx, statefulSets, statefulSets)
+			require.False(t, results.HasError())
+			require.Equal(t, tt.wantShutdownDeletion, esClient.DeleteShutdownCalled)
+		})
+	}
+}
+
 func Test_calculateDownscales(t *testing.T) {
 	ssets := es_sset.StatefulSetList{
 		{
@@ -1102,7 +1176,7 @@ func Test_doDownscale_zen1MinimumMasterNodes(t *testing.T) {
 func Test_deleteStatefulSetResources(t *testing.T) {
 	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster"}}
 	sset := sset.TestSset{Namespace: "ns", Name: "sset", ClusterName: es.Name}.Build()
-	cfg := settings.ConfigSecret(es, sset.Name, []byte("fake config data"))
+	cfg := settings.ConfigSecret(es, sset.Name, []byte("fake config data"), nil)
 	svc := nodespec.HeadlessService(&es, sset.Name)
 
 	tests := []struct {
@@ -1192,3 +1266,93 @@ func Test_deleteStatefulSets(t *testing.T) {
 		})
 	}
 }
+
+func Test_calculatePerformableDownscale_nodeShutdown(t *testing.T) {
+	tests := []struct {
+		name         string
+		shutdowns    map[string]esclient.NodeShutdown
+		wantReplicas int32
+		wantPhase    esv1.ElasticsearchOrchestrationPhase
+		wantEvent    events.Event
+		wantErr      bool
+	}{
+		{
+			name:         "shutdown complete: node can be removed",
+			shutdowns:    map[string]esclient.NodeShutdown{"id-2": {NodeID: "id-2", Type: "REMOVE", Status: esclient.ShutdownComplete}},
+			wantReplicas: 2,
+		},
+		{
+			name:         "shutdown in progress: node removal is delayed",
+			shutdowns:    map[string]esclient.NodeShutdown{"id-2": {NodeID: "id-2", Type: "REMOVE", Status: esclient.ShutdownInProgress}},
+			wantReplicas: 3,
+			wantPhase:    esv1.ElasticsearchMigratingDataPhase,
+			wantEvent: events.Event{
+				EventType: corev1.EventTypeNormal,
+				Reason:    events.EventReasonDelayed,
+				Message:   "Requested topology change delayed by data migration. Ensure index settings allow node removal.",
+			},
+		},
+		{
+			name: "shutdown stalled: node removal is delayed and the stall reported",
+			shutdowns: map[string]esclient.NodeShutdown{"id-2": {
+				NodeID:         "id-2",
+				Type:           "REMOVE",
+				Status:         esclient.ShutdownStalled,
+				ShardMigration: esclient.ShardMigration{Status: esclient.ShutdownStalled, Explanation: "no other node to allocate the shards to"},
+			}},
+			wantReplicas: 3,
+			wantPhase:    esv1.ElasticsearchNodeShutdownStalledPhase,
+			wantEvent: events.Event{
+				EventType: corev1.EventTypeWarning,
+				Reason:    events.EventReasonStalled,
+				Message:   "Requested topology change is stalled. User intervention maybe required if this condition persists. no other node to allocate the shards to",
+			},
+		},
+		{
+			name:         "shutdown not started: error",
+			shutdowns:    map[string]esclient.NodeShutdown{"id-2": {NodeID: "id-2", Type: "REMOVE", Status: esclient.ShutdownNotStarted}},
+			wantReplicas: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "no shutdown for the leaving node: error",
+			wantReplicas: 3,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconcileState := reconcile.MustNewState(esv1.Elasticsearch{})
+			ctx := downscaleContext{
+				parentCtx:      context.Background(),
+				reconcileState: reconcileState,
+				nodeShutdown: shutdown.NewNodeShutdown(
+					&fakeESClient{Shutdowns: tt.shutdowns},
+					map[string]string{"default-0": "id-0", "default-1": "id-1", "default-2": "id-2"},
+					esclient.Remove,
+					"",
+					crlog.Log,
+				),
+			}
+			downscale := ssetDownscale{
+				statefulSet:     sset.TestSset{Name: "default"}.Build(),
+				initialReplicas: 3,
+				targetReplicas:  2,
+				finalReplicas:   2,
+			}
+			got, err := calculatePerformableDownscale(ctx, downscale)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantReplicas, got.targetReplicas)
+			require.Equal(t, int32(2), got.finalReplicas)
+
+			gotEvents, gotES := reconcileState.Apply()
+			if tt.wantEvent == (events.Event{}) {
+				require.Empty(t, gotEvents)
+				return
+			}
+			require.Equal(t, []events.Event{tt.wantEvent}, gotEvents)
+			require.NotNil(t, gotES)
+			require.Equal(t, tt.wantPhase, gotES.Status.Phase)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	commoncerts "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	commondriver "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
@@ -36,6 +37,8 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/configmap"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/filesettings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/ilmpolicy"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/ingestpipeline"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/license"
@@ -43,8 +46,10 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/remotecluster"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/securitycontext"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/securityrole"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/snapshotrepository"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/stackmon"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/dev"
@@ -62,6 +67,8 @@ var (
 // Its lifecycle is bound to a single reconciliation attempt.
 type Driver interface {
 	Reconcile(context.Context) *reconciler.Results
+	// Plan computes the changes Reconcile would apply to the Kubernetes resources of the cluster.
+	Plan(context.Context) error
 }
 
 // NewDefaultDriver returns the default driver implementation.
@@ -129,22 +136,7 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		return results.WithError(err)
 	}
 
-	if err := configmap.ReconcileScriptsConfigMap(ctx, d.Client, d.ES); err != nil {
-		return results.WithError(err)
-	}
-
-	_, err := common.ReconcileService(ctx, d.Client, services.NewTransportService(d.ES), &d.ES)
-	if err != nil {
-		return results.WithError(err)
-	}
-
-	externalService, err := common.ReconcileService(ctx, d.Client, services.NewExternalService(d.ES), &d.ES)
-	if err != nil {
-		return results.WithError(err)
-	}
-
-	var internalService *corev1.Service
-	internalService, err = common.ReconcileService(ctx, d.Client, services.NewInternalService(d.ES), &d.ES)
+	externalService, internalService, err := d.reconcileConfigAndServices(ctx)
 	if err != nil {
 		return results.WithError(err)
 	}
@@ -161,19 +153,15 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		return results.WithError(err)
 	}
 
-	trustedHTTPCertificates, res := certificates.ReconcileHTTP(
-		ctx,
-		d,
-		d.ES,
-		[]corev1.Service{*externalService, *internalService},
-		d.OperatorParameters.GlobalCA,
-		d.OperatorParameters.CACertRotation,
-		d.OperatorParameters.CertRotation,
-	)
+	trustedHTTPCertificates, res := d.reconcileHTTPCertificates(ctx, *externalService, *internalService)
 	results.WithResults(res)
 	if res != nil && res.HasError() {
+		if _, err := res.Aggregate(); errors.Is(err, commoncerts.ErrInvalidCustomCertificate) {
+			d.ReconcileState.ReportCondition(esv1.HTTPCertificatesValid, corev1.ConditionFalse, err.Error())
+		}
 		return results
 	}
+	d.ReconcileState.ReportCondition(esv1.HTTPCertificatesValid, corev1.ConditionTrue, httpCertificatesConditionMessage(d.ES.Spec.HTTP.TLS.Enabled(), trustedHTTPCertificates))
 
 	// start the ES observer
 	minVersion, err := version.MinInPods(resourcesState.CurrentPods, label.VersionLabelName)
@@ -206,21 +194,17 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 	d.ReconcileState.
 		UpdateClusterHealth(observedState()).         // Elasticsearch cluster health
 		UpdateAvailableNodes(*resourcesState).        // Available nodes
+		UpdateNodeSets(*resourcesState).              // Replicas of each nodeSet
 		UpdateMinRunningVersion(ctx, *resourcesState) // Min running version
 
-	res = certificates.ReconcileTransport(
-		ctx,
-		d,
-		d.ES,
-		d.OperatorParameters.GlobalCA,
-		d.OperatorParameters.CACertRotation,
-		d.OperatorParameters.CertRotation,
-	)
+	transportCACert, res := d.reconcileTransportCertificates(ctx)
 	results.WithResults(res)
 	if res != nil && res.HasError() {
 		return results
 	}
 
+	d.reportCertificatesExpiration(time.Now(), trustedHTTPCertificates, transportCACert)
+
 	// Patch the Pods to add the expected node labels as annotations. Record the error, if any, but do not stop the
 	// reconciliation loop as we don't want to prevent other updates from being applied to the cluster.
 	results.WithResults(annotatePodsWithNodeLabels(ctx, d.Client, d.ES))
@@ -310,32 +294,46 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		}
 	}
 
+	// reconcile snapshot repositories, ingest pipelines and index lifecycle policies once the cluster is healthy
+	if health := observedState(); esReachable && (health == esv1.ElasticsearchGreenHealth || health == esv1.ElasticsearchYellowHealth) {
+		if err := snapshotrepository.Reconcile(ctx, esClient, d.ES); err != nil {
+			msg := "Could not reconcile snapshot repositories, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+		if err := ingestpipeline.Reconcile(ctx, d.Client, esClient, &d.ES); err != nil {
+			msg := "Could not reconcile ingest pipelines, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+		if err := securityrole.Reconcile(ctx, d.Client, esClient, &d.ES); err != nil {
+			msg := "Could not reconcile security roles, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+		if err := user.ReconcileBuiltinUserPasswords(ctx, d.Client, esClient, &d.ES); err != nil {
+			msg := "Could not reconcile built-in user passwords, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+		if err := ilmpolicy.Reconcile(ctx, esClient, d.ES); err != nil {
+			msg := "Could not reconcile index lifecycle policies, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+	}
+
 	// Compute seed hosts based on current masters with a podIP
 	if err := settings.UpdateSeedHostsConfigMap(ctx, d.Client, d.ES, resourcesState.AllPods); err != nil {
 		return results.WithError(err)
 	}
 
-	// reconcile an empty File based settings Secret if it doesn't exist
-	if d.Version.GTE(filesettings.FileBasedSettingsMinPreVersion) {
-		err = filesettings.ReconcileEmptyFileSettingsSecret(ctx, d.Client, d.ES, true)
-		if err != nil {
-			return results.WithError(err)
-		}
-	}
-
-	keystoreParams := initcontainer.KeystoreParams
-	keystoreSecurityContext := securitycontext.For(d.Version, true)
-	keystoreParams.SecurityContext = &keystoreSecurityContext
-
-	// setup a keystore with secure settings in an init container, if specified by the user
-	keystoreResources, err := keystore.ReconcileResources(
-		ctx,
-		d,
-		&d.ES,
-		esv1.ESNamer,
-		label.NewLabels(k8s.ExtractNamespacedName(&d.ES)),
-		keystoreParams,
-	)
+	keystoreResources, err := d.reconcileFileSettingsAndKeystore(ctx)
 	if err != nil {
 		return results.WithError(err)
 	}
@@ -348,6 +346,7 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 	if requeue {
 		results = results.WithReconciliationState(defaultRequeue.WithReason("Elasticsearch cluster UUID is not reconciled"))
 	}
+	d.ReconcileState.ReportBootstrapping(bootstrap.AnnotatedForBootstrap(d.ES))
 
 	// reconcile beats config secrets if Stack Monitoring is defined
 	err = stackmon.ReconcileConfigSecrets(ctx, d.Client, d.ES)
@@ -375,6 +374,88 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 	return results.WithResults(d.reconcileNodeSpecs(ctx, esReachable, esClient, d.ReconcileState, *resourcesState, keystoreResources))
 }
 
+// The following steps reconcile Kubernetes resources without calling the Elasticsearch API. They are shared by
+// Reconcile and Plan.
+
+// reconcileConfigAndServices reconciles the scripts ConfigMap, the log4j2 configuration, the additional trusted CA
+// certificates and the Services of the cluster. It returns the external and the internal HTTP Services.
+func (d *defaultDriver) reconcileConfigAndServices(ctx context.Context) (*corev1.Service, *corev1.Service, error) {
+	if err := configmap.ReconcileScriptsConfigMap(ctx, d.Client, d.ES); err != nil {
+		return nil, nil, err
+	}
+
+	if err := settings.ReconcileLog4j2Config(ctx, d.Client, d.ES, d.DynamicWatches(), d.Version); err != nil {
+		return nil, nil, err
+	}
+
+	if err := settings.ReconcileTrustedCA(ctx, d.Client, d.ES, d.DynamicWatches()); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := common.ReconcileService(ctx, d.Client, services.NewTransportService(ctx, d.ES), &d.ES); err != nil {
+		return nil, nil, err
+	}
+
+	externalService, err := common.ReconcileService(ctx, d.Client, services.NewExternalService(ctx, d.ES), &d.ES)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	internalService, err := common.ReconcileService(ctx, d.Client, services.NewInternalService(d.ES), &d.ES)
+	if err != nil {
+		return nil, nil, err
+	}
+	return externalService, internalService, nil
+}
+
+// reconcileHTTPCertificates reconciles the HTTP layer certificates of the cluster, valid for the given Services.
+func (d *defaultDriver) reconcileHTTPCertificates(ctx context.Context, externalService, internalService corev1.Service) ([]*x509.Certificate, *reconciler.Results) {
+	return certificates.ReconcileHTTP(
+		ctx,
+		d,
+		d.ES,
+		[]corev1.Service{externalService, internalService},
+		d.OperatorParameters.GlobalCA,
+		d.OperatorParameters.CACertRotation,
+		d.OperatorParameters.CertRotation,
+	)
+}
+
+// reconcileTransportCertificates reconciles the transport layer certificates of the cluster.
+func (d *defaultDriver) reconcileTransportCertificates(ctx context.Context) (*x509.Certificate, *reconciler.Results) {
+	return certificates.ReconcileTransport(
+		ctx,
+		d,
+		d.ES,
+		d.OperatorParameters.GlobalCA,
+		d.OperatorParameters.CACertRotation,
+		d.OperatorParameters.CertRotation,
+	)
+}
+
+// reconcileFileSettingsAndKeystore reconciles an empty file based settings Secret if it doesn't exist, and the keystore
+// resources of the init container adding the secure settings specified by the user to the keystore.
+func (d *defaultDriver) reconcileFileSettingsAndKeystore(ctx context.Context) (*keystore.Resources, error) {
+	if d.Version.GTE(filesettings.FileBasedSettingsMinPreVersion) {
+		if err := filesettings.ReconcileEmptyFileSettingsSecret(ctx, d.Client, d.ES, true); err != nil {
+			return nil, err
+		}
+	}
+
+	keystoreParams := initcontainer.KeystoreParams
+	keystoreSecurityContext := securitycontext.For(d.Version, true)
+	keystoreParams.SecurityContext = &keystoreSecurityContext
+
+	return keystore.ReconcileResources(
+		ctx,
+		d,
+		&d.ES,
+		esv1.ESNamer,
+		label.NewLabels(k8s.ExtractNamespacedName(&d.ES)),
+		keystoreParams,
+	)
+}
+
 // newElasticsearchClient creates a new Elasticsearch HTTP client for this cluster using the provided user
 func (d *defaultDriver) newElasticsearchClient(
 	ctx context.Context,
@@ -534,6 +615,57 @@ func warnUnsupportedDistro(pods []corev1.Pod, recorder *events.Recorder) {
 	}
 }
 
+// httpCertificatesConditionMessage reports the expiration date of the certificate served on the HTTP layer, which is
+// the first one of the certificate chain.
+func httpCertificatesConditionMessage(tlsEnabled bool, certs []*x509.Certificate) string {
+	switch {
+	case !tlsEnabled:
+		return "TLS is disabled on the HTTP layer"
+	case len(certs) == 0:
+		return "No HTTP certificate"
+	default:
+		return fmt.Sprintf("HTTP certificate is valid until %s", certs[0].NotAfter.UTC().Format(time.RFC3339))
+	}
+}
+
+// reportCertificatesExpiration reports the remaining validity of the HTTP certificate and of the transport CA
+// certificate in the metrics, and whether one of them expires within its rotation threshold in the status.
+func (d *defaultDriver) reportCertificatesExpiration(now time.Time, httpCerts []*x509.Certificate, transportCACert *x509.Certificate) {
+	var expirations []certificates.Expiration
+	if d.ES.Spec.HTTP.TLS.Enabled() && len(httpCerts) > 0 {
+		expirations = append(expirations, certificates.Expiration{
+			Type:         certificates.HTTPCertificateType,
+			NotAfter:     httpCerts[0].NotAfter,
+			RotateBefore: d.OperatorParameters.CertRotation.RotateBefore,
+		})
+	}
+	if transportCACert != nil {
+		expirations = append(expirations, certificates.Expiration{
+			Type:         certificates.TransportCACertificateType,
+			NotAfter:     transportCACert.NotAfter,
+			RotateBefore: d.OperatorParameters.CACertRotation.RotateBefore,
+		})
+	}
+	certificates.ReportRemainingValidity(k8s.ExtractNamespacedName(&d.ES), now, expirations)
+	status, message := certificatesExpiringCondition(now, expirations)
+	d.ReconcileState.ReportCondition(esv1.CertificatesExpiring, status, message)
+}
+
+// certificatesExpiringCondition returns the status and message of the CertificatesExpiring condition: true if one of
+// the given certificates expires within its rotation threshold.
+func certificatesExpiringCondition(now time.Time, expirations []certificates.Expiration) (corev1.ConditionStatus, string) {
+	var expiring []string
+	for _, expiration := range expirations {
+		if expiration.WithinRotationThreshold(now) {
+			expiring = append(expiring, fmt.Sprintf("%s certificate expires at %s", expiration.Type, expiration.NotAfter.UTC().Format(time.RFC3339)))
+		}
+	}
+	if len(expiring) == 0 {
+		return corev1.ConditionFalse, "No certificate expires within its rotation threshold"
+	}
+	return corev1.ConditionTrue, strings.Join(expiring, ", ")
+}
+
 func esReachableConditionMessage(internalService *corev1.Service, isServiceReady bool, isRespondingToRequests bool) string {
 	switch {
 	case !isServiceReady:
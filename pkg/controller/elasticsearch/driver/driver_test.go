@@ -6,13 +6,16 @@ package driver
 
 import (
 	"context"
+	"crypto/x509"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
@@ -63,6 +66,88 @@ func Test_esReachableConditionMessage(t *testing.T) {
 	}
 }
 
+func Test_httpCertificatesConditionMessage(t *testing.T) {
+	notAfter := time.Date(2027, 3, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+	tests := []struct {
+		name       string
+		tlsEnabled bool
+		certs      []*x509.Certificate
+		want       string
+	}{
+		{
+			name:       "TLS disabled",
+			tlsEnabled: false,
+			certs:      []*x509.Certificate{{NotAfter: notAfter}},
+			want:       "TLS is disabled on the HTTP layer",
+		},
+		{
+			name:       "no certificate",
+			tlsEnabled: true,
+			want:       "No HTTP certificate",
+		},
+		{
+			name:       "expiration of the leaf certificate",
+			tlsEnabled: true,
+			certs:      []*x509.Certificate{{NotAfter: notAfter}, {NotAfter: notAfter.Add(24 * time.Hour)}},
+			want:       "HTTP certificate is valid until 2027-03-01T11:30:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, httpCertificatesConditionMessage(tt.tlsEnabled, tt.certs))
+		})
+	}
+}
+
+func Test_certificatesExpiringCondition(t *testing.T) {
+	now := time.Date(2027, 3, 1, 12, 0, 0, 0, time.UTC)
+	httpCert := certificates.Expiration{Type: certificates.HTTPCertificateType, NotAfter: now.Add(48 * time.Hour), RotateBefore: 24 * time.Hour}
+	transportCA := certificates.Expiration{Type: certificates.TransportCACertificateType, NotAfter: now.Add(72 * time.Hour), RotateBefore: 24 * time.Hour}
+	tests := []struct {
+		name        string
+		expirations []certificates.Expiration
+		wantStatus  corev1.ConditionStatus
+		wantMessage string
+	}{
+		{
+			name:        "no certificate",
+			wantStatus:  corev1.ConditionFalse,
+			wantMessage: "No certificate expires within its rotation threshold",
+		},
+		{
+			name:        "certificates outside of the rotation threshold",
+			expirations: []certificates.Expiration{httpCert, transportCA},
+			wantStatus:  corev1.ConditionFalse,
+			wantMessage: "No certificate expires within its rotation threshold",
+		},
+		{
+			name: "certificate expiring exactly at the rotation threshold",
+			expirations: []certificates.Expiration{
+				{Type: certificates.HTTPCertificateType, NotAfter: now.Add(24 * time.Hour), RotateBefore: 24 * time.Hour},
+				transportCA,
+			},
+			wantStatus:  corev1.ConditionTrue,
+			wantMessage: "http certificate expires at 2027-03-02T12:00:00Z",
+		},
+		{
+			name: "expired and expiring certificates",
+			expirations: []certificates.Expiration{
+				{Type: certificates.HTTPCertificateType, NotAfter: now.Add(-time.Hour), RotateBefore: 24 * time.Hour},
+				{Type: certificates.TransportCACertificateType, NotAfter: now.Add(72 * time.Hour), RotateBefore: 96 * time.Hour},
+			},
+			wantStatus:  corev1.ConditionTrue,
+			wantMessage: "http certificate expires at 2027-03-01T11:00:00Z, transport_ca certificate expires at 2027-03-04T12:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, message := certificatesExpiringCondition(now, tt.expirations)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantMessage, message)
+		})
+	}
+}
+
 func Test_allNodesRunningServiceAccounts(t *testing.T) {
 	type args struct {
 		saTokens       user.ServiceAccountTokens
@@ -165,6 +250,10 @@ func (f *fakeSecurityClient) GetServiceAccountCredentials(_ context.Context, nam
 	return serviceAccountCredential, nil
 }
 
+func (f *fakeSecurityClient) ChangePassword(_ context.Context, _ string, _ []byte) error {
+	return nil
+}
+
 func newFakeSecurityClient() *fakeSecurityClient {
 	return &fakeSecurityClient{
 		serviceAccountCredentials: make(map[string]esclient.ServiceAccountCredential),
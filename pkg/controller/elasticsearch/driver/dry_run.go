@@ -0,0 +1,84 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	"go.elastic.co/apm/v2"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// Plan reconciles the Kubernetes resources of the cluster (scripts, services, certificates, keystore, configuration
+// and StatefulSets) towards their expected state, without calling the Elasticsearch API. It is meant to be called
+// with a dry-run client that records the write operations instead of applying them (see reconciler.NewDryRunClient).
+// It goes through the same steps as Reconcile, but StatefulSets are reconciled to their expected spec directly,
+// regardless of the change budget and of the orchestration steps (upscale, rolling upgrade, downscale) the regular
+// reconciliation goes through. The dynamic watches registered by these steps are discarded.
+func (d *defaultDriver) Plan(ctx context.Context) error {
+	span, ctx := apm.StartSpan(ctx, "plan", tracing.SpanTypeApp)
+	defer span.End()
+
+	// the resources watched on behalf of the cluster are left untouched
+	planner := &defaultDriver{DefaultDriverParameters: d.DefaultDriverParameters}
+	planner.DefaultDriverParameters.DynamicWatches = watches.NewDynamicWatches()
+	return planner.plan(ctx)
+}
+
+func (d *defaultDriver) plan(ctx context.Context) error {
+	externalService, internalService, err := d.reconcileConfigAndServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, res := d.reconcileHTTPCertificates(ctx, *externalService, *internalService); res != nil && res.HasError() {
+		_, err := res.Aggregate()
+		return err
+	}
+	if _, res := d.reconcileTransportCertificates(ctx); res != nil && res.HasError() {
+		_, err := res.Aggregate()
+		return err
+	}
+
+	keystoreResources, err := d.reconcileFileSettingsAndKeystore(ctx)
+	if err != nil {
+		return err
+	}
+
+	actualStatefulSets, err := es_sset.RetrieveActualStatefulSets(d.Client, k8s.ExtractNamespacedName(&d.ES))
+	if err != nil {
+		return err
+	}
+	expectedResources, err := d.buildExpectedResources(ctx, keystoreResources, actualStatefulSets)
+	if err != nil {
+		return err
+	}
+	for _, nodeSpecRes := range expectedResources {
+		if err := reconcileConfigAndHeadlessService(ctx, d.Client, d.ES, nodeSpecRes); err != nil {
+			return err
+		}
+		// expectations are not updated, since the StatefulSet is not actually updated
+		if _, err := es_sset.ReconcileStatefulSet(ctx, d.Client, d.ES, nodeSpecRes.StatefulSet, nil); err != nil {
+			return fmt.Errorf("while planning StatefulSet %s: %w", nodeSpecRes.StatefulSet.Name, err)
+		}
+	}
+
+	// StatefulSets that are not expected anymore would eventually be removed once their Pods are migrated away
+	expectedNames := expectedResources.StatefulSets().Names()
+	for i := range actualStatefulSets {
+		if expectedNames.Has(actualStatefulSets[i].Name) {
+			continue
+		}
+		if err := d.Client.Delete(ctx, &actualStatefulSets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
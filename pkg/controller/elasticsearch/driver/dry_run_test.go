@@ -0,0 +1,140 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_defaultDriver_Plan(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			Version: "8.12.0",
+			NodeSets: []esv1.NodeSet{
+				{Name: "default", Count: 3},
+			},
+		},
+	}
+	k8sClient := k8s.NewFakeClient(&es)
+	dryRunClient, plan := reconciler.NewDryRunClient(k8sClient)
+	rotation := certificates.RotationParams{
+		Validity:     certificates.DefaultCertValidity,
+		RotateBefore: certificates.DefaultRotateBefore,
+	}
+	d := &defaultDriver{
+		DefaultDriverParameters: DefaultDriverParameters{
+			OperatorParameters: operator.Parameters{
+				CACertRotation: rotation,
+				CertRotation:   rotation,
+				IPFamily:       corev1.IPv4Protocol,
+			},
+			ES:             es,
+			Version:        version.MustParse(es.Spec.Version),
+			Client:         dryRunClient,
+			Recorder:       record.NewFakeRecorder(100),
+			ReconcileState: reconcile.MustNewState(es),
+			DynamicWatches: watches.NewDynamicWatches(),
+			Expectations:   expectations.NewExpectations(dryRunClient),
+		},
+	}
+
+	require.NoError(t, d.Plan(context.Background()))
+
+	// the plan includes the creation of the resources of the cluster
+	planned := make(map[string]bool)
+	for _, action := range plan.Actions() {
+		planned[action.String()] = true
+	}
+	for _, expected := range []string{
+		"Create ConfigMap ns/es-es-scripts",
+		"Create Service ns/es-es-transport",
+		"Create Service ns/es-es-http",
+		"Create Service ns/es-es-internal-http",
+		"Create Service ns/es-es-default",
+		"Create Secret ns/es-es-http-certs-internal",
+		"Create Secret ns/es-es-default-es-config",
+		"Create StatefulSet ns/es-es-default",
+	} {
+		require.True(t, planned[expected], "%s not planned in %v", expected, plan.Actions())
+	}
+
+	// but none of them has actually been created
+	var statefulSets appsv1.StatefulSetList
+	require.NoError(t, k8sClient.List(context.Background(), &statefulSets))
+	require.Empty(t, statefulSets.Items)
+	var services corev1.ServiceList
+	require.NoError(t, k8sClient.List(context.Background(), &services))
+	require.Empty(t, services.Items)
+	var secrets corev1.SecretList
+	require.NoError(t, k8sClient.List(context.Background(), &secrets))
+	require.Empty(t, secrets.Items)
+	var configMaps corev1.ConfigMapList
+	require.NoError(t, k8sClient.List(context.Background(), &configMaps))
+	require.Empty(t, configMaps.Items)
+}
+
+func Test_defaultDriver_Plan_watches(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			Version:                       "8.12.0",
+			TrustedCertificateAuthorities: &commonv1.SecretRef{SecretName: "trusted-ca"},
+			NodeSets: []esv1.NodeSet{
+				{Name: "default", Count: 3},
+			},
+		},
+	}
+	trustedCA := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "trusted-ca"},
+		Data:       map[string][]byte{certificates.CAFileName: []byte("ca")},
+	}
+	dryRunClient, _ := reconciler.NewDryRunClient(k8s.NewFakeClient(&es, &trustedCA))
+	rotation := certificates.RotationParams{
+		Validity:     certificates.DefaultCertValidity,
+		RotateBefore: certificates.DefaultRotateBefore,
+	}
+	dynamicWatches := watches.NewDynamicWatches()
+	d := &defaultDriver{
+		DefaultDriverParameters: DefaultDriverParameters{
+			OperatorParameters: operator.Parameters{
+				CACertRotation: rotation,
+				CertRotation:   rotation,
+				IPFamily:       corev1.IPv4Protocol,
+			},
+			ES:             es,
+			Version:        version.MustParse(es.Spec.Version),
+			Client:         dryRunClient,
+			Recorder:       record.NewFakeRecorder(100),
+			ReconcileState: reconcile.MustNewState(es),
+			DynamicWatches: dynamicWatches,
+			Expectations:   expectations.NewExpectations(dryRunClient),
+		},
+	}
+
+	require.NoError(t, d.Plan(context.Background()))
+
+	// planning does not register any watch on behalf of the cluster
+	require.Empty(t, dynamicWatches.Secrets.Registrations())
+	require.Empty(t, dynamicWatches.ConfigMaps.Registrations())
+}
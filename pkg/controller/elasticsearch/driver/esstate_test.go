@@ -7,6 +7,7 @@ package driver
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -110,7 +111,13 @@ func (f *fakeESClient) GetClusterHealthWaitForAllEvents(_ context.Context) (escl
 	return f.health, nil
 }
 
-func (f *fakeESClient) PutShutdown(_ context.Context, _ string, _ esclient.ShutdownType, _ string) error {
+func (f *fakeESClient) PutShutdown(_ context.Context, nodeID string, shutdownType esclient.ShutdownType, _ string, _ time.Duration) error {
+	if f.Shutdowns == nil {
+		f.Shutdowns = map[string]esclient.NodeShutdown{}
+	}
+	if _, exists := f.Shutdowns[nodeID]; !exists {
+		f.Shutdowns[nodeID] = esclient.NodeShutdown{NodeID: nodeID, Type: string(shutdownType), Status: esclient.ShutdownInProgress}
+	}
 	return nil
 }
 
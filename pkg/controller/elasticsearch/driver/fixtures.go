@@ -340,13 +340,14 @@ func (t testPod) toPodPtr() *corev1.Pod {
 }
 
 type testESState struct {
-	inCluster []string
-	health    client.Health
+	inCluster          []string
+	health             client.Health
+	allocationDisabled bool
 	ESState
 }
 
 func (t *testESState) ShardAllocationsEnabled() (bool, error) {
-	return true, nil
+	return !t.allocationDisabled, nil
 }
 
 func (t *testESState) Health() (client.Health, error) {
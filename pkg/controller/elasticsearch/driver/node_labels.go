@@ -41,7 +41,7 @@ func annotatePodsWithNodeLabels(ctx context.Context, c k8s.Client, es esv1.Elast
 	span, ctx := apm.StartSpan(ctx, "annotate_pods_with_node_labels", tracing.SpanTypeApp)
 	defer span.End()
 	results := reconciler.NewResult(ctx)
-	if !es.HasDownwardNodeLabels() {
+	if len(es.NodeLabelsAsPodAnnotations()) == 0 {
 		return results
 	}
 	actualPods, err := sset.GetActualPodsForCluster(c, es)
@@ -64,7 +64,7 @@ func annotatePodWithNodeLabels(ctx context.Context, c k8s.Client, pod corev1.Pod
 		return err
 	}
 	// Get the missing annotations.
-	podAnnotations, err := getPodAnnotations(&pod, es.DownwardNodeLabels(), node.Labels)
+	podAnnotations, err := getPodAnnotations(&pod, es.DownwardNodeLabels(), nodeAttributesLabels(es), node.Labels)
 	if err != nil {
 		return err
 	}
@@ -87,10 +87,31 @@ func annotatePodWithNodeLabels(ctx context.Context, c k8s.Client, pod corev1.Pod
 	return nil
 }
 
+// nodeAttributesLabels returns the node labels used as node attributes.
+func nodeAttributesLabels(es esv1.Elasticsearch) []string {
+	nodeLabels := make([]string, 0, len(es.NodeAttributes()))
+	for _, nodeLabel := range es.NodeAttributes() {
+		nodeLabels = append(nodeLabels, nodeLabel)
+	}
+	return nodeLabels
+}
+
 // getPodAnnotations returns missing annotations, and their values, expected on a given Pod.
-// It also ensures that labels exist on the K8S node, if not the case an error is returned.
-func getPodAnnotations(pod *corev1.Pod, expectedAnnotations []string, nodeLabels map[string]string) (map[string]string, error) {
+// It also ensures that expected labels exist on the K8S node, if not the case an error is returned.
+// Optional labels that do not exist on the K8S node are set as empty annotations, so the Pod does not wait for them.
+func getPodAnnotations(
+	pod *corev1.Pod,
+	expectedAnnotations []string,
+	optionalAnnotations []string,
+	nodeLabels map[string]string,
+) (map[string]string, error) {
 	podAnnotations := make(map[string]string)
+	for _, optionalAnnotation := range optionalAnnotations {
+		if _, alreadyExists := pod.Annotations[optionalAnnotation]; alreadyExists {
+			continue
+		}
+		podAnnotations[optionalAnnotation] = nodeLabels[optionalAnnotation]
+	}
 	var missingLabels []string
 	for _, expectedAnnotation := range expectedAnnotations {
 		value, ok := nodeLabels[expectedAnnotation]
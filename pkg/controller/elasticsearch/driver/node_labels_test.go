@@ -169,6 +169,54 @@ func Test_annotatePodsWithNodeLabels(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Node attributes labels missing on K8S nodes are set as empty annotations",
+			args: args{
+				es: &esv1.Elasticsearch{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        esName,
+						Namespace:   "ns",
+						Annotations: map[string]string{"eck.k8s.elastic.co/node-attributes": "zone=topology.kubernetes.io/zone"},
+					},
+				},
+				objects: []client.Object{
+					newPodBuilder("elasticsearch-sample-es-default-0").scheduledOn("k8s-node-0").build(),
+					newPodBuilder("elasticsearch-sample-es-default-1").scheduledOn("k8s-node-1").build(),
+					&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "k8s-node-0", Labels: map[string]string{"topology.kubernetes.io/zone": "europe-west1-a"}}},
+					&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "k8s-node-1"}},
+				},
+				ctx: context.Background(),
+			},
+			expectedAnnotations: expectedPodsAnnotations{
+				"elasticsearch-sample-es-default-0": {
+					"topology.kubernetes.io/zone": "europe-west1-a",
+				},
+				"elasticsearch-sample-es-default-1": {
+					"topology.kubernetes.io/zone": "",
+				},
+			},
+		},
+		{
+			name: "Downward node labels are still required when also used as node attributes",
+			args: args{
+				es: &esv1.Elasticsearch{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      esName,
+						Namespace: "ns",
+						Annotations: map[string]string{
+							"eck.k8s.elastic.co/downward-node-labels": "topology.kubernetes.io/zone",
+							"eck.k8s.elastic.co/node-attributes":      "zone=topology.kubernetes.io/zone",
+						},
+					},
+				},
+				objects: []client.Object{
+					newPodBuilder("elasticsearch-sample-es-default-0").scheduledOn("k8s-node-0").build(),
+					&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "k8s-node-0"}},
+				},
+				ctx: context.Background(),
+			},
+			wantErrMsg: "following annotations are expected to be set on Pod ns/elasticsearch-sample-es-default-0 but do not exist as node labels: topology.kubernetes.io/zone",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
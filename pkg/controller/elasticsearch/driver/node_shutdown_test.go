@@ -5,9 +5,16 @@
 package driver
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 )
 
 func Test_supportsNodeshutdown(t *testing.T) {
@@ -56,3 +63,80 @@ func Test_supportsNodeshutdown(t *testing.T) {
 		})
 	}
 }
+
+// nodeIDsESState is an ESState only resolving the node IDs.
+type nodeIDsESState struct {
+	ESState
+	nodeNameToID map[string]string
+	err          error
+}
+
+func (s nodeIDsESState) NodeNameToID() (map[string]string, error) {
+	return s.nodeNameToID, s.err
+}
+
+func Test_newShutdownInterface(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	leavingNode := "es-default-0"
+	tests := []struct {
+		name                   string
+		version                string
+		state                  nodeIDsESState
+		shutdowns              map[string]esclient.NodeShutdown
+		wantAllocationExcludes bool
+		wantStatus             esclient.ShutdownStatus
+		wantErr                bool
+	}{
+		{
+			name:                   "falls back to shard allocation excludes before 7.15.2",
+			version:                "7.15.1",
+			state:                  nodeIDsESState{err: errors.New("node IDs should not be needed")},
+			wantAllocationExcludes: true,
+		},
+		{
+			name:    "uses the node shutdown API from 7.15.2",
+			version: "7.15.2",
+			state:   nodeIDsESState{nodeNameToID: map[string]string{leavingNode: "id-0"}},
+			shutdowns: map[string]esclient.NodeShutdown{
+				"id-0": {NodeID: "id-0", Type: "REMOVE", Status: esclient.ShutdownInProgress},
+			},
+			wantStatus: esclient.ShutdownInProgress,
+		},
+		{
+			name:    "reports stalled shutdowns",
+			version: "8.13.0",
+			state:   nodeIDsESState{nodeNameToID: map[string]string{leavingNode: "id-0"}},
+			shutdowns: map[string]esclient.NodeShutdown{
+				"id-0": {NodeID: "id-0", Type: "REMOVE", Status: esclient.ShutdownStalled},
+			},
+			wantStatus: esclient.ShutdownStalled,
+		},
+		{
+			name:    "node shutdown API requires the node IDs",
+			version: "8.13.0",
+			state:   nodeIDsESState{err: errors.New("cluster unreachable")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esClient := &fakeESClient{version: version.MustParse(tt.version), Shutdowns: tt.shutdowns}
+			got, err := newShutdownInterface(context.Background(), es, esClient, tt.state, nil)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.NoError(t, got.ReconcileShutdowns(context.Background(), []string{leavingNode}, nil))
+			require.Equal(t, tt.wantAllocationExcludes, esClient.ExcludeFromShardAllocationCalled)
+			if tt.wantAllocationExcludes {
+				require.Equal(t, leavingNode, esClient.ExcludeFromShardAllocationCalledWith)
+				return
+			}
+			status, err := got.ShutdownStatus(context.Background(), leavingNode)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatus, status.Status)
+		})
+	}
+}
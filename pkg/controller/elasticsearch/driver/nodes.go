@@ -82,7 +82,7 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		return results.WithError(err)
 	}
 
-	expectedResources, err := nodespec.BuildExpectedResources(ctx, d.Client, d.ES, keystoreResources, actualStatefulSets, d.OperatorParameters.IPFamily, d.OperatorParameters.SetDefaultSecurityContext)
+	expectedResources, err := d.buildExpectedResources(ctx, keystoreResources, actualStatefulSets)
 	if err != nil {
 		return results.WithError(err)
 	}
@@ -138,9 +138,14 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		return results.WithError(err)
 	}
 
-	if err := GarbageCollectPVCs(ctx, d.K8sClient(), d.ES, actualStatefulSets, expectedResources.StatefulSets()); err != nil {
+	pvcRetentionRequeue, err := GarbageCollectPVCs(ctx, d.K8sClient(), d.ES, actualStatefulSets, expectedResources.StatefulSets())
+	if err != nil {
 		return results.WithError(err)
 	}
+	if pvcRetentionRequeue > 0 {
+		// delete the retained PVCs once their retention expires, without considering the reconciliation incomplete
+		results.WithReconciliationState(reconciler.RequeueAfter(pvcRetentionRequeue).ReconciliationComplete())
+	}
 
 	// Phase 2: if there is any Pending or bootlooping Pod to upgrade, do it.
 	attempted, err := d.MaybeForceUpgrade(ctx, actualStatefulSets)
@@ -262,3 +267,12 @@ func (d *defaultDriver) isNodeSpecsReconciled(ctx context.Context, actualStatefu
 
 	return true
 }
+
+// buildExpectedResources builds the expected resources of each NodeSet, on top of the given actual StatefulSets.
+func (d *defaultDriver) buildExpectedResources(
+	ctx context.Context,
+	keystoreResources *keystore.Resources,
+	actualStatefulSets es_sset.StatefulSetList,
+) (nodespec.ResourcesList, error) {
+	return nodespec.BuildExpectedResources(ctx, d.Client, d.ES, keystoreResources, actualStatefulSets, d.OperatorParameters.IPFamily, d.OperatorParameters.SetDefaultSecurityContext)
+}
@@ -6,6 +6,7 @@ package driver
 
 import (
 	"context"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,9 +16,14 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
 )
 
+// PVCUnusedSinceAnnotation is set on the PersistentVolumeClaims retained after the removal of their Pod on scale down, to
+// the time from which they are not used anymore.
+const PVCUnusedSinceAnnotation = "elasticsearch.k8s.elastic.co/pvc-unused-since"
+
 // GarbageCollectPVCs ensures PersistentVolumeClaims created for the given es resource are deleted
 // when no longer used, since this is not done automatically by the StatefulSet controller.
 // Related issue in the k8s repo: https://github.com/kubernetes/kubernetes/issues/55045
@@ -25,28 +31,92 @@ import (
 // This covers:
 // * leftover PVCs created for StatefulSets that do not exist anymore
 // * leftover PVCs created for StatefulSets replicas that don't exist anymore (eg. downscale from 5 to 3 nodes)
+// If a scale down retention is configured, these PVCs are only removed once unused for that long, so that they can be
+// attached again to their Pods if the cluster is scaled up in the meantime. It returns the duration after which the
+// retention of a PVC expires, if any.
 func GarbageCollectPVCs(
 	ctx context.Context,
 	k8sClient k8s.Client,
 	es esv1.Elasticsearch,
 	actualStatefulSets sset.StatefulSetList,
 	expectedStatefulSets sset.StatefulSetList,
-) error {
+) (time.Duration, error) {
 	// PVCs are using the same labels as their corresponding StatefulSet, so we can filter on ES cluster name.
 	var pvcs corev1.PersistentVolumeClaimList
 	ns := client.InNamespace(es.Namespace)
 	matchLabels := label.NewLabelSelectorForElasticsearch(es)
 	if err := k8sClient.List(ctx, &pvcs, ns, matchLabels); err != nil {
-		return err
+		return 0, err
+	}
+	log := ulog.FromContext(ctx)
+	unused := pvcsToRemove(pvcs.Items, actualStatefulSets, expectedStatefulSets)
+	unusedNames := make(map[string]struct{}, len(unused))
+	for _, pvc := range unused {
+		unusedNames[pvc.Name] = struct{}{}
 	}
-	for _, pvc := range pvcsToRemove(pvcs.Items, actualStatefulSets, expectedStatefulSets) {
+
+	// PVCs retained on scale down and used again by the StatefulSets are not retained anymore
+	for _, pvc := range pvcs.Items {
+		pvc := pvc
+		if _, isUnused := unusedNames[pvc.Name]; isUnused {
+			continue
+		}
+		if _, retained := pvc.Annotations[PVCUnusedSinceAnnotation]; !retained {
+			continue
+		}
+		log.Info("Reattaching retained PVC", "namespace", pvc.Namespace, "pvc_name", pvc.Name)
+		delete(pvc.Annotations, PVCUnusedSinceAnnotation)
+		if err := k8sClient.Update(ctx, &pvc); err != nil {
+			return 0, err
+		}
+	}
+
+	now := time.Now()
+	retention := es.Spec.VolumeClaimScaleDownRetentionOrDefault()
+	var requeueAfter time.Duration
+	for _, pvc := range unused {
 		pvc := pvc
-		ulog.FromContext(ctx).Info("Deleting PVC", "namespace", pvc.Namespace, "pvc_name", pvc.Name)
+		remaining, err := retainUnusedPVC(ctx, k8sClient, &pvc, retention, now)
+		if err != nil {
+			return 0, err
+		}
+		if remaining > 0 {
+			if requeueAfter == 0 || remaining < requeueAfter {
+				requeueAfter = remaining
+			}
+			continue
+		}
+		log.Info("Deleting PVC", "namespace", pvc.Namespace, "pvc_name", pvc.Name)
 		if err := k8sClient.Delete(ctx, &pvc); err != nil {
-			return err
+			return 0, err
+		}
+	}
+	return requeueAfter, nil
+}
+
+// retainUnusedPVC returns for how long the given unused PVC must still be retained, given the scale down retention.
+// PVCs not retained yet are annotated with the current time as the start of their retention.
+func retainUnusedPVC(
+	ctx context.Context,
+	k8sClient k8s.Client,
+	pvc *corev1.PersistentVolumeClaim,
+	retention time.Duration,
+	now time.Time,
+) (time.Duration, error) {
+	if retention <= 0 {
+		return 0, nil
+	}
+	unusedSince, err := time.Parse(time.RFC3339, pvc.Annotations[PVCUnusedSinceAnnotation])
+	if err != nil {
+		// not retained yet, or the annotation has been tampered with: start the retention now
+		ulog.FromContext(ctx).Info("Retaining unused PVC", "namespace", pvc.Namespace, "pvc_name", pvc.Name, "retention", retention)
+		unusedSince = now
+		pvc.Annotations = maps.Merge(pvc.Annotations, map[string]string{PVCUnusedSinceAnnotation: now.UTC().Format(time.RFC3339)})
+		if err := k8sClient.Update(ctx, pvc); err != nil {
+			return 0, err
 		}
 	}
-	return nil
+	return unusedSince.Add(retention).Sub(now), nil
 }
 
 // pvcsToRemove filters the given pvcs to ones that can be safely removed based on Pods
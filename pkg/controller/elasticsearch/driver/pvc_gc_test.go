@@ -8,11 +8,14 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -177,7 +180,7 @@ func TestGarbageCollectPVCs(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := GarbageCollectPVCs(context.Background(), tt.args.k8sClient, tt.args.es, tt.args.actualStatefulSets, tt.args.expectedStatefulSets); (err != nil) != tt.wantErr {
+			if _, err := GarbageCollectPVCs(context.Background(), tt.args.k8sClient, tt.args.es, tt.args.actualStatefulSets, tt.args.expectedStatefulSets); (err != nil) != tt.wantErr {
 				t.Errorf("GarbageCollectPVCs() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			var retrievedPVCs corev1.PersistentVolumeClaimList
@@ -186,3 +189,127 @@ func TestGarbageCollectPVCs(t *testing.T) {
 		})
 	}
 }
+
+func TestGarbageCollectPVCs_ScaleDownRetention(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{VolumeClaimScaleDownRetention: &metav1.Duration{Duration: time.Hour}},
+	}
+	c := k8s.NewFakeClient(buildPVCPtr("claim1-sset1-0"), buildPVCPtr("claim1-sset1-1"), buildPVCPtr("claim1-sset1-2"))
+	gc := func(replicas int32) time.Duration {
+		t.Helper()
+		ssets := sset.StatefulSetList{buildSsetWithClaims("sset1", replicas, "claim1")}
+		requeueAfter, err := GarbageCollectPVCs(context.Background(), c, es, ssets, ssets)
+		require.NoError(t, err)
+		return requeueAfter
+	}
+	getPVC := func(name string) (corev1.PersistentVolumeClaim, bool) {
+		t.Helper()
+		var pvc corev1.PersistentVolumeClaim
+		err := c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: name}, &pvc)
+		if apierrors.IsNotFound(err) {
+			return pvc, false
+		}
+		require.NoError(t, err)
+		return pvc, true
+	}
+
+	// the PVCs of the Pods removed on scale down are retained
+	requeueAfter := gc(1)
+	require.InDelta(t, time.Hour, requeueAfter, float64(time.Minute))
+	for _, name := range []string{"claim1-sset1-1", "claim1-sset1-2"} {
+		pvc, exists := getPVC(name)
+		require.True(t, exists)
+		require.Contains(t, pvc.Annotations, PVCUnusedSinceAnnotation)
+	}
+	used, _ := getPVC("claim1-sset1-0")
+	require.NotContains(t, used.Annotations, PVCUnusedSinceAnnotation)
+
+	// and attached again to the Pods recreated on scale up
+	require.InDelta(t, time.Hour, gc(2), float64(time.Minute))
+	reattached, exists := getPVC("claim1-sset1-1")
+	require.True(t, exists)
+	require.NotContains(t, reattached.Annotations, PVCUnusedSinceAnnotation)
+
+	// PVCs still unused are deleted once the retention expires
+	retained, _ := getPVC("claim1-sset1-2")
+	retained.Annotations[PVCUnusedSinceAnnotation] = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	require.NoError(t, c.Update(context.Background(), &retained))
+	require.Zero(t, gc(2))
+	_, exists = getPVC("claim1-sset1-2")
+	require.False(t, exists)
+	_, exists = getPVC("claim1-sset1-1")
+	require.True(t, exists)
+}
+
+func Test_retainUnusedPVC(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	pvcUnusedSince := func(unusedSince string) *corev1.PersistentVolumeClaim {
+		pvc := buildPVCPtr("claim1-sset1-1")
+		if unusedSince != "" {
+			pvc.Annotations = map[string]string{PVCUnusedSinceAnnotation: unusedSince}
+		}
+		return pvc
+	}
+	tests := []struct {
+		name            string
+		pvc             *corev1.PersistentVolumeClaim
+		retention       time.Duration
+		want            time.Duration
+		wantUnusedSince string
+	}{
+		{
+			name:      "no retention",
+			pvc:       pvcUnusedSince(""),
+			retention: 0,
+			want:      0,
+		},
+		{
+			name:            "no retention anymore for a retained PVC",
+			pvc:             pvcUnusedSince("2026-10-14T11:00:00Z"),
+			retention:       0,
+			want:            0,
+			wantUnusedSince: "2026-10-14T11:00:00Z",
+		},
+		{
+			name:            "start the retention of a PVC not retained yet",
+			pvc:             pvcUnusedSince(""),
+			retention:       time.Hour,
+			want:            time.Hour,
+			wantUnusedSince: "2026-10-14T12:00:00Z",
+		},
+		{
+			name:            "restart the retention if the annotation is invalid",
+			pvc:             pvcUnusedSince("yesterday"),
+			retention:       time.Hour,
+			want:            time.Hour,
+			wantUnusedSince: "2026-10-14T12:00:00Z",
+		},
+		{
+			name:            "retention in progress",
+			pvc:             pvcUnusedSince("2026-10-14T11:30:00Z"),
+			retention:       time.Hour,
+			want:            30 * time.Minute,
+			wantUnusedSince: "2026-10-14T11:30:00Z",
+		},
+		{
+			name:            "retention expired",
+			pvc:             pvcUnusedSince("2026-10-14T10:00:00Z"),
+			retention:       time.Hour,
+			want:            -time.Hour,
+			wantUnusedSince: "2026-10-14T10:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(tt.pvc.DeepCopy())
+			got, err := retainUnusedPVC(context.Background(), c, tt.pvc, tt.retention, now)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+
+			var retrieved corev1.PersistentVolumeClaim
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(tt.pvc), &retrieved))
+			require.Equal(t, tt.wantUnusedSince, retrieved.Annotations[PVCUnusedSinceAnnotation])
+		})
+	}
+}
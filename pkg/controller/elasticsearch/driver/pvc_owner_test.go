@@ -94,6 +94,16 @@ func Test_reconcilePVCOwnerRefs(t *testing.T) {
 			wantErr:    false,
 			wantUpdate: true,
 		},
+		{
+			name: "add references by default",
+			args: args{
+				c:  k8s.NewFakeClient(pvcFixturePtr("es-data-0"), pvcFixturePtr("es-data-1")),
+				es: esFixture(""),
+			},
+			want:       []corev1.PersistentVolumeClaim{pvcFixture("es-data-0", "es"), pvcFixture("es-data-1", "es")},
+			wantErr:    false,
+			wantUpdate: true,
+		},
 		{
 			name: "avoid unnecessary updates when reference already added",
 			args: args{
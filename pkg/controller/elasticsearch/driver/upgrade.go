@@ -7,6 +7,7 @@ package driver
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -25,6 +26,7 @@ import (
 	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 func (d *defaultDriver) handleUpgrades(
@@ -69,6 +71,8 @@ func (d *defaultDriver) handleUpgrades(
 	}
 	logger := log.WithValues("namespace", d.ES.Namespace, "es_name", d.ES.Name)
 	nodeShutdown := shutdown.NewNodeShutdown(esClient, nodeNameToID, esclient.Restart, d.ES.ResourceVersion, logger)
+	// nodes with an ephemeral data volume lose their data on restart, they are shut down as if they were removed instead
+	nodeRemoval := shutdown.NewNodeShutdown(esClient, nodeNameToID, esclient.Remove, d.ES.ResourceVersion, logger)
 
 	// Maybe re-enable shards allocation and delete shutdowns if upgraded nodes are back into the cluster.
 	results.WithResults(d.maybeCompleteNodeUpgrades(ctx, esClient, esState, nodeShutdown))
@@ -90,12 +94,24 @@ func (d *defaultDriver) handleUpgrades(
 		esClient,
 		esState,
 		nodeShutdown,
+		nodeRemoval,
 		expectedMasters,
 		podsToUpgrade,
 		healthyPods,
 		currentPods,
 	)
 
+	deferred, untilOpen, err := upgrade.deferredByMaintenanceWindow(time.Now())
+	if err != nil {
+		return results.WithError(err)
+	}
+	if deferred {
+		reason := fmt.Sprintf("Nodes upgrade deferred until the next maintenance window opens in %s", untilOpen.Round(time.Minute))
+		logger.Info(reason)
+		d.ReconcileState.RecordNodesToBeUpgradedWithMessage(k8s.PodNames(podsToUpgrade), reason)
+		return results.WithReconciliationState(reconciler.RequeueAfter(untilOpen).WithReason(reason))
+	}
+
 	var deletedPods []corev1.Pod
 
 	isVersionUpgrade, err := isVersionUpgrade(d.ES)
@@ -107,8 +123,8 @@ func (d *defaultDriver) handleUpgrades(
 		// unconditional full cluster upgrade
 		deletedPods, err = run(upgrade.DeleteAll)
 	} else {
-		// regular rolling upgrade
-		deletedPods, err = run(upgrade.Delete)
+		// regular rolling upgrade, provided the cluster is healthy enough to start it
+		deletedPods, err = run(upgrade.DeleteIfHealthy)
 	}
 	if err != nil {
 		return results.WithError(err)
@@ -125,14 +141,17 @@ func (d *defaultDriver) handleUpgrades(
 }
 
 type upgradeCtx struct {
-	parentCtx       context.Context
-	client          k8s.Client
-	ES              esv1.Elasticsearch
-	resourcesList   nodespec.ResourcesList
-	statefulSets    es_sset.StatefulSetList
-	esClient        esclient.Client
-	shardLister     esclient.ShardLister
-	nodeShutdown    *shutdown.NodeShutdown
+	parentCtx     context.Context
+	client        k8s.Client
+	ES            esv1.Elasticsearch
+	resourcesList nodespec.ResourcesList
+	statefulSets  es_sset.StatefulSetList
+	esClient      esclient.Client
+	shardLister   esclient.ShardLister
+	nodeShutdown  *shutdown.NodeShutdown
+	nodeRemoval   *shutdown.NodeShutdown
+	// fullRestart is true if all the Pods to upgrade are deleted at once.
+	fullRestart     bool
 	esState         ESState
 	expectations    *expectations.Expectations
 	reconcileState  *reconcile.State
@@ -150,6 +169,7 @@ func newUpgrade(
 	esClient esclient.Client,
 	esState ESState,
 	nodeShutdown *shutdown.NodeShutdown,
+	nodeRemoval *shutdown.NodeShutdown,
 	expectedMaster []string,
 	podsToUpgrade []corev1.Pod,
 	healthyPods map[string]corev1.Pod,
@@ -164,6 +184,7 @@ func newUpgrade(
 		esClient:        esClient,
 		shardLister:     esClient,
 		nodeShutdown:    nodeShutdown,
+		nodeRemoval:     nodeRemoval,
 		esState:         esState,
 		expectations:    d.Expectations,
 		reconcileState:  d.ReconcileState,
@@ -417,15 +438,32 @@ func (ctx *upgradeCtx) readyToDelete(pod corev1.Pod) (bool, error) {
 		// there is no point in trying to query the shutdown status of a Pod that is not ready
 		return true, nil
 	}
-	response, err := ctx.nodeShutdown.ShutdownStatus(ctx.parentCtx, pod.Name)
+	nodeShutdown := ctx.nodeShutdown
+	if ctx.podsToRemove([]corev1.Pod{pod}).Has(pod.Name) {
+		nodeShutdown = ctx.nodeRemoval
+	}
+	response, err := nodeShutdown.ShutdownStatus(ctx.parentCtx, pod.Name)
 	if err != nil {
 		return false, err
 	}
 	return response.Status == esclient.ShutdownComplete, nil
 }
 
+// podsToRemove returns the names of the given Pods which are shut down as if they were removed from the cluster: Pods
+// with an ephemeral data volume lose their data on restart, their shards must be migrated to other nodes first. This
+// does not apply to full cluster restarts, where there is no other node to migrate the shards to.
+func (ctx *upgradeCtx) podsToRemove(pods []corev1.Pod) set.StringSet {
+	if ctx.fullRestart {
+		return set.Make()
+	}
+	return set.Make(podsWithEphemeralData(ctx.ES, pods)...)
+}
+
+// requestNodeRestarts requests the shutdown of the given Pods, using a shutdown of type remove for the Pods returned by
+// podsToRemove.
 func (ctx *upgradeCtx) requestNodeRestarts(podsToRestart []corev1.Pod) error {
-	var podNames []string //nolint:prealloc
+	toRemove := ctx.podsToRemove(podsToRestart)
+	var restarts, removals []string
 	for _, p := range podsToRestart {
 		if !k8s.IsPodReady(p) {
 			// There is no point in trying to shut down a Pod that is not running.
@@ -436,23 +474,55 @@ func (ctx *upgradeCtx) requestNodeRestarts(podsToRestart []corev1.Pod) error {
 			// due to misconfiguration, for example unfulfillable node selectors, seems worth it.
 			continue
 		}
-		podNames = append(podNames, p.Name)
+		if toRemove.Has(p.Name) {
+			removals = append(removals, p.Name)
+			continue
+		}
+		restarts = append(restarts, p.Name)
 	}
+	terminating := k8s.PodNames(k8s.TerminatingPods(ctx.currentPods))
 	// Note that ReconcileShutdowns would cancel ongoing shutdowns when called with no podNames
 	// this is however not the case in the rolling upgrade logic where we exit early if no pod needs to be rotated.
-	return ctx.nodeShutdown.ReconcileShutdowns(ctx.parentCtx, podNames, k8s.PodNames(k8s.TerminatingPods(ctx.currentPods)))
+	// Removals are only requested when needed not to cancel the shutdowns of nodes being removed by a downscale.
+	if len(removals) > 0 {
+		if err := ctx.nodeRemoval.ReconcileShutdowns(ctx.parentCtx, removals, terminating); err != nil {
+			return err
+		}
+		if len(restarts) == 0 {
+			return nil
+		}
+	}
+	return ctx.nodeShutdown.ReconcileShutdowns(ctx.parentCtx, restarts, terminating)
 }
 
 func (ctx *upgradeCtx) prepareClusterForNodeRestart(podsToUpgrade []corev1.Pod) error {
 	// use client.Version here as we want the minimal version in the cluster not the one in the spec.
 	if supportsNodeShutdown(ctx.esClient.Version()) {
+		allocationDelay, err := ctx.restartInPlaceAllocationDelay(podsToUpgrade)
+		if err != nil {
+			return err
+		}
+		if allocationDelay > 0 {
+			// restarting in place only changes the allocation delay of the restart shutdowns requested below
+			ctx.nodeShutdown.WithAllocationDelay(allocationDelay)
+		}
 		return ctx.requestNodeRestarts(podsToUpgrade)
 	}
-	// Disable shard allocations to avoid shards moving around while the node is temporarily down
 	shardsAllocationEnabled, err := ctx.esState.ShardAllocationsEnabled()
 	if err != nil {
 		return err
 	}
+	if toRemove := ctx.podsToRemove(podsToUpgrade); toRemove.Count() > 0 {
+		// The data of these nodes is lost on restart: keep shards allocation enabled so that their shards are
+		// replicated again to other nodes, there is nothing to flush.
+		if !shardsAllocationEnabled {
+			ulog.FromContext(ctx.parentCtx).Info("Enabling shards allocation before restarting nodes with ephemeral data",
+				"es_name", ctx.ES.Name, "namespace", ctx.ES.Namespace, "pods", toRemove.AsSortedSlice())
+			return ctx.esClient.EnableShardAllocation(ctx.parentCtx)
+		}
+		return nil
+	}
+	// Disable shard allocations to avoid shards moving around while the node is temporarily down
 	if shardsAllocationEnabled {
 		ulog.FromContext(ctx.parentCtx).Info("Disabling shards allocation", "es_name", ctx.ES.Name, "namespace", ctx.ES.Namespace)
 		if err := ctx.esClient.DisableReplicaShardsAllocation(ctx.parentCtx); err != nil {
@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+// DeleteIfHealthy runs a rolling upgrade, unless it has not started yet and the cluster does not report the health
+// required by the update strategy, in which case the reason is recorded in the status and no Pod is deleted.
+func (ctx *upgradeCtx) DeleteIfHealthy() ([]corev1.Pod, error) {
+	canStart, reason, err := ctx.canStartRollingUpgrade()
+	if err != nil {
+		return nil, err
+	}
+	if !canStart {
+		ulog.FromContext(ctx.parentCtx).Info(reason, "namespace", ctx.ES.Namespace, "es_name", ctx.ES.Name)
+		ctx.reconcileState.RecordNodesToBeUpgradedWithMessage(k8s.PodNames(ctx.podsToUpgrade), reason)
+		return nil, nil
+	}
+	return ctx.Delete()
+}
+
+// canStartRollingUpgrade returns true if the cluster health allows the rolling upgrade to proceed, or a reason otherwise.
+// The health is only checked before the first Pod is upgraded, while all the Pods to upgrade are healthy: a degraded
+// cluster must not prevent the operator from replacing unhealthy Pods, or from completing an upgrade in progress.
+func (ctx *upgradeCtx) canStartRollingUpgrade() (bool, string, error) {
+	if ctx.ES.IsUpgradeHealthCheckSkipped() || !ctx.upgradeNotStarted() {
+		return true, "", nil
+	}
+	health, err := ctx.esState.Health()
+	if err != nil {
+		return false, "", err
+	}
+	required := ctx.ES.Spec.UpdateStrategy.RequiredHealthOrDefault()
+	if health.Status == required || required.Less(health.Status) {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("Rolling upgrade not started: cluster health is %s, %s is required", health.Status, required), nil
+}
+
+// upgradeNotStarted returns true if there are Pods to upgrade, all of them are healthy, and none of the Pods of their
+// StatefulSets has been upgraded yet.
+func (ctx *upgradeCtx) upgradeNotStarted() bool {
+	if len(ctx.podsToUpgrade) == 0 {
+		return false
+	}
+	for _, pod := range ctx.podsToUpgrade {
+		if _, healthy := ctx.healthyPods[pod.Name]; !healthy {
+			return false
+		}
+	}
+	return !ctx.rollingUpgradeInProgress()
+}
+
+// rollingUpgradeInProgress returns true if at least one Pod of a StatefulSet with Pods to upgrade already runs the
+// latest revision.
+func (ctx *upgradeCtx) rollingUpgradeInProgress() bool {
+	upgrading := set.Make()
+	for _, pod := range ctx.podsToUpgrade {
+		upgrading.Add(pod.Labels[label.StatefulSetNameLabelName])
+	}
+	for _, pod := range ctx.currentPods {
+		ssetName := pod.Labels[label.StatefulSetNameLabelName]
+		if !upgrading.Has(ssetName) {
+			continue
+		}
+		statefulSet, found := ctx.statefulSets.GetByName(ssetName)
+		if !found || statefulSet.Status.UpdateRevision == "" {
+			continue
+		}
+		if sset.PodRevision(pod) == statefulSet.Status.UpdateRevision {
+			return true
+		}
+	}
+	return false
+}
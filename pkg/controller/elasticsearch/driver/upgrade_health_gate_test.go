@@ -0,0 +1,196 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/migration"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/shutdown"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_upgradeCtx_canStartRollingUpgrade(t *testing.T) {
+	newPod := func(name, revision string) corev1.Pod {
+		return sset.TestPod{Namespace: TestEsNamespace, Name: name, ClusterName: TestEsName, StatefulSetName: "data", Revision: revision}.Build()
+	}
+	statefulSets := es_sset.StatefulSetList{
+		sset.TestSset{Namespace: TestEsNamespace, Name: "data", ClusterName: TestEsName, Replicas: 2, Status: appsv1.StatefulSetStatus{UpdateRevision: "new"}}.Build(),
+	}
+	notStarted := []corev1.Pod{newPod("data-0", "old"), newPod("data-1", "old")}
+	started := []corev1.Pod{newPod("data-0", "old"), newPod("data-1", "new")}
+
+	tests := []struct {
+		name           string
+		requiredHealth esv1.ElasticsearchHealth
+		annotations    map[string]string
+		health         esv1.ElasticsearchHealth
+		currentPods    []corev1.Pod
+		podsToUpgrade  []corev1.Pod
+		unhealthyPods  []string
+		want           bool
+		wantReason     string
+	}{
+		{
+			name:          "no Pod to upgrade",
+			health:        esv1.ElasticsearchYellowHealth,
+			currentPods:   notStarted,
+			podsToUpgrade: nil,
+			want:          true,
+		},
+		{
+			name:          "green cluster: upgrade can start",
+			health:        esv1.ElasticsearchGreenHealth,
+			currentPods:   notStarted,
+			podsToUpgrade: notStarted,
+			want:          true,
+		},
+		{
+			name:          "yellow cluster: upgrade is deferred",
+			health:        esv1.ElasticsearchYellowHealth,
+			currentPods:   notStarted,
+			podsToUpgrade: notStarted,
+			want:          false,
+			wantReason:    "Rolling upgrade not started: cluster health is yellow, green is required",
+		},
+		{
+			name:           "yellow cluster with yellow required: upgrade can start",
+			requiredHealth: esv1.ElasticsearchYellowHealth,
+			health:         esv1.ElasticsearchYellowHealth,
+			currentPods:    notStarted,
+			podsToUpgrade:  notStarted,
+			want:           true,
+		},
+		{
+			name:           "red cluster with yellow required: upgrade is deferred",
+			requiredHealth: esv1.ElasticsearchYellowHealth,
+			health:         esv1.ElasticsearchRedHealth,
+			currentPods:    notStarted,
+			podsToUpgrade:  notStarted,
+			want:           false,
+			wantReason:     "Rolling upgrade not started: cluster health is red, yellow is required",
+		},
+		{
+			name:          "unknown health: upgrade is deferred",
+			health:        esv1.ElasticsearchUnknownHealth,
+			currentPods:   notStarted,
+			podsToUpgrade: notStarted,
+			want:          false,
+			wantReason:    "Rolling upgrade not started: cluster health is unknown, green is required",
+		},
+		{
+			name:          "yellow cluster with the override annotation: upgrade can start",
+			annotations:   map[string]string{esv1.SkipUpgradeHealthCheckAnnotation: "true"},
+			health:        esv1.ElasticsearchYellowHealth,
+			currentPods:   notStarted,
+			podsToUpgrade: notStarted,
+			want:          true,
+		},
+		{
+			name:          "yellow cluster with an upgrade in progress: upgrade proceeds",
+			health:        esv1.ElasticsearchYellowHealth,
+			currentPods:   started,
+			podsToUpgrade: started[:1],
+			want:          true,
+		},
+		{
+			name:          "yellow cluster with an unhealthy Pod to upgrade: upgrade can start",
+			health:        esv1.ElasticsearchYellowHealth,
+			currentPods:   notStarted,
+			podsToUpgrade: notStarted,
+			unhealthyPods: []string{"data-1"},
+			want:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{}
+			es.Annotations = tt.annotations
+			es.Spec.UpdateStrategy.RequiredHealth = tt.requiredHealth
+			healthyPods := make(map[string]corev1.Pod)
+			for _, pod := range tt.currentPods {
+				healthyPods[pod.Name] = pod
+			}
+			for _, name := range tt.unhealthyPods {
+				delete(healthyPods, name)
+			}
+			ctx := upgradeCtx{
+				ES:            es,
+				statefulSets:  statefulSets,
+				esState:       &testESState{health: client.Health{Status: tt.health}},
+				podsToUpgrade: tt.podsToUpgrade,
+				healthyPods:   healthyPods,
+				currentPods:   tt.currentPods,
+			}
+			got, reason, err := ctx.canStartRollingUpgrade()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func Test_upgradeCtx_DeleteIfHealthy(t *testing.T) {
+	upgradeTestPods := newUpgradeTestPods(
+		newTestPod("masters-0").withRoles(esv1.MasterRole, esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+	)
+	newUpgradeCtx := func(health esv1.ElasticsearchHealth) upgradeCtx {
+		esState := &testESState{
+			inCluster: upgradeTestPods.podsInCluster(),
+			health:    client.Health{Status: health},
+		}
+		esClient := &fakeESClient{version: version.MustParse("7.15.2")}
+		k8sClient := k8s.NewFakeClient(upgradeTestPods.toClientObjects("7.15.2", 1, nothing, nil)...)
+		es := upgradeTestPods.toES("7.15.2", 1, nil)
+		return upgradeCtx{
+			parentCtx:       context.Background(),
+			reconcileState:  reconcile.MustNewState(es),
+			client:          k8sClient,
+			ES:              es,
+			resourcesList:   upgradeTestPods.toResourcesList(t),
+			statefulSets:    upgradeTestPods.toStatefulSetList(),
+			esClient:        esClient,
+			shardLister:     migration.NewFakeShardLister(client.Shards{}),
+			esState:         esState,
+			expectations:    expectations.NewExpectations(k8sClient),
+			expectedMasters: upgradeTestPods.toMasters(noMutation),
+			podsToUpgrade:   upgradeTestPods.toUpgrade(),
+			healthyPods:     upgradeTestPods.toHealthyPods(),
+			currentPods:     upgradeTestPods.toCurrentPods(),
+			nodeShutdown:    shutdown.NewNodeShutdown(esClient, upgradeTestPods.podNamesToESNodeID(), client.Restart, "", crlog.Log),
+		}
+	}
+
+	// the upgrade is deferred while the cluster is yellow, and the reason is reported in the status
+	ctx := newUpgradeCtx(esv1.ElasticsearchYellowHealth)
+	deleted, err := ctx.DeleteIfHealthy()
+	require.NoError(t, err)
+	require.Empty(t, deleted)
+	_, es := ctx.reconcileState.Apply()
+	require.NotNil(t, es)
+	require.Len(t, es.Status.InProgressOperations.UpgradeOperation.Nodes, 1)
+	require.Equal(t, "PENDING", es.Status.InProgressOperations.UpgradeOperation.Nodes[0].Status)
+	require.Equal(t, "Rolling upgrade not started: cluster health is yellow, green is required", *es.Status.InProgressOperations.UpgradeOperation.Nodes[0].Message)
+
+	// the upgrade proceeds once the cluster is green
+	ctx = newUpgradeCtx(esv1.ElasticsearchGreenHealth)
+	ctx.esClient.(*fakeESClient).Shutdowns = map[string]client.NodeShutdown{"masters-0": {Status: client.ShutdownComplete}}
+	deleted, err = ctx.DeleteIfHealthy()
+	require.NoError(t, err)
+	require.Equal(t, []string{"masters-0"}, names(deleted))
+}
@@ -0,0 +1,24 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"time"
+)
+
+// deferredByMaintenanceWindow returns true if the upgrade of the Pods must be deferred because it has not started yet
+// and the maintenance window of the cluster is closed at the given time, along with how long it takes for the window
+// to open. As for the health gate, an upgrade in progress or the replacement of unhealthy Pods is never deferred.
+func (ctx *upgradeCtx) deferredByMaintenanceWindow(now time.Time) (bool, time.Duration, error) {
+	window := ctx.ES.Spec.MaintenanceWindow
+	if window == nil || len(window.Windows) == 0 || !ctx.upgradeNotStarted() {
+		return false, 0, nil
+	}
+	open, untilOpen, err := window.Check(now)
+	if err != nil || open {
+		return false, 0, err
+	}
+	return true, untilOpen, nil
+}
@@ -0,0 +1,115 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+)
+
+func Test_upgradeCtx_deferredByMaintenanceWindow(t *testing.T) {
+	newPod := func(name, revision string) corev1.Pod {
+		return sset.TestPod{Namespace: TestEsNamespace, Name: name, ClusterName: TestEsName, StatefulSetName: "data", Revision: revision}.Build()
+	}
+	statefulSets := es_sset.StatefulSetList{
+		sset.TestSset{Namespace: TestEsNamespace, Name: "data", ClusterName: TestEsName, Replicas: 2, Status: appsv1.StatefulSetStatus{UpdateRevision: "new"}}.Build(),
+	}
+	notStarted := []corev1.Pod{newPod("data-0", "old"), newPod("data-1", "old")}
+	started := []corev1.Pod{newPod("data-0", "old"), newPod("data-1", "new")}
+	nightly := &commonv1.MaintenanceWindow{Windows: []commonv1.TimeWindow{
+		{Start: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+	}}
+	inWindow := time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC)
+	outsideWindow := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		window        *commonv1.MaintenanceWindow
+		now           time.Time
+		currentPods   []corev1.Pod
+		podsToUpgrade []corev1.Pod
+		unhealthyPods []string
+		wantDeferred  bool
+		wantUntilOpen time.Duration
+	}{
+		{
+			name:          "no maintenance window: upgrade proceeds",
+			now:           outsideWindow,
+			currentPods:   notStarted,
+			podsToUpgrade: notStarted,
+		},
+		{
+			name:          "inside the maintenance window: upgrade proceeds",
+			window:        nightly,
+			now:           inWindow,
+			currentPods:   notStarted,
+			podsToUpgrade: notStarted,
+		},
+		{
+			name:          "outside the maintenance window: upgrade is deferred",
+			window:        nightly,
+			now:           outsideWindow,
+			currentPods:   notStarted,
+			podsToUpgrade: notStarted,
+			wantDeferred:  true,
+			wantUntilOpen: 10 * time.Hour,
+		},
+		{
+			name:        "outside the maintenance window with no Pod to upgrade",
+			window:      nightly,
+			now:         outsideWindow,
+			currentPods: notStarted,
+		},
+		{
+			name:          "outside the maintenance window with an upgrade in progress: upgrade proceeds",
+			window:        nightly,
+			now:           outsideWindow,
+			currentPods:   started,
+			podsToUpgrade: started[:1],
+		},
+		{
+			name:          "outside the maintenance window with an unhealthy Pod to upgrade: upgrade proceeds",
+			window:        nightly,
+			now:           outsideWindow,
+			currentPods:   notStarted,
+			podsToUpgrade: notStarted,
+			unhealthyPods: []string{"data-0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{}
+			es.Spec.MaintenanceWindow = tt.window
+			healthyPods := make(map[string]corev1.Pod)
+			for _, pod := range tt.currentPods {
+				healthyPods[pod.Name] = pod
+			}
+			for _, name := range tt.unhealthyPods {
+				delete(healthyPods, name)
+			}
+			ctx := upgradeCtx{
+				ES:            es,
+				statefulSets:  statefulSets,
+				podsToUpgrade: tt.podsToUpgrade,
+				healthyPods:   healthyPods,
+				currentPods:   tt.currentPods,
+			}
+			deferred, untilOpen, err := ctx.deferredByMaintenanceWindow(tt.now)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDeferred, deferred)
+			require.Equal(t, tt.wantUntilOpen, untilOpen)
+		})
+	}
+}
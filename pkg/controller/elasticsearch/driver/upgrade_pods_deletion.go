@@ -32,8 +32,8 @@ func (ctx *upgradeCtx) Delete() ([]corev1.Pod, error) {
 		ctx.reconcileState.RecordPredicatesResult(map[string]string{})
 		return nil, nil
 	}
-	// Get allowed deletions and check if maxUnavailable has been reached.
-	allowedDeletions, maxUnavailableReached := ctx.getAllowedDeletions()
+	// Get allowed deletions for the cluster and the NodeSets with their own maxUnavailable.
+	budget := ctx.getDeletionBudget()
 
 	// Step 1. Sort the Pods to get the ones with the higher priority
 	candidates := make([]corev1.Pod, len(ctx.podsToUpgrade)) // work on a copy in order to have no side effect
@@ -53,10 +53,9 @@ func (ctx *upgradeCtx) Delete() ([]corev1.Pod, error) {
 		ctx.currentPods,
 	)
 	log.V(1).Info("Applying predicates",
-		"maxUnavailableReached", maxUnavailableReached,
-		"allowedDeletions", allowedDeletions,
+		"allowedDeletions", budget.allowed,
 	)
-	podsToDelete, err := applyPredicates(predicateContext, candidates, maxUnavailableReached, allowedDeletions, ctx.reconcileState)
+	podsToDelete, err := applyPredicates(predicateContext, candidates, budget, ctx.reconcileState)
 	if err != nil {
 		return podsToDelete, err
 	}
@@ -97,6 +96,7 @@ func (ctx *upgradeCtx) DeleteAll() ([]corev1.Pod, error) {
 	if len(ctx.podsToUpgrade) == 0 {
 		return nil, nil
 	}
+	ctx.fullRestart = true
 
 	if err := ctx.prepareClusterForNodeRestart(ctx.podsToUpgrade); err != nil {
 		return nil, err
@@ -134,23 +134,95 @@ func (ctx *upgradeCtx) DeleteAll() ([]corev1.Pod, error) {
 	return deletedPods, nil
 }
 
-// getAllowedDeletions returns the number of deletions that can be done and if maxUnavailable has been reached.
-func (ctx *upgradeCtx) getAllowedDeletions() (int, bool) {
+// clusterBudgetKey identifies the cluster-wide deletion budget, which applies to the Pods of all NodeSets.
+const clusterBudgetKey = ""
+
+// deletionBudget holds the number of Pods which can still be deleted, cluster-wide and for each NodeSet with its own
+// maxUnavailable. The deletion of a Pod must fit within both the cluster-wide budget and the budget of its NodeSet.
+type deletionBudget struct {
+	// allowed is the number of allowed deletions, keyed by StatefulSet name for NodeSets with their own maxUnavailable.
+	allowed map[string]int
+	// exhausted records the budgets consumed by the deletions of the current reconciliation.
+	exhausted map[string]bool
+}
+
+// keys returns the keys of the budgets applying to this Pod.
+func (b deletionBudget) keys(pod corev1.Pod) []string {
+	ssetName := pod.Labels[label.StatefulSetNameLabelName]
+	if _, exists := b.allowed[ssetName]; exists && ssetName != clusterBudgetKey {
+		return []string{clusterBudgetKey, ssetName}
+	}
+	return []string{clusterBudgetKey}
+}
+
+// maxUnavailableReached returns true if maxUnavailable was already reached before any deletion applying to this Pod.
+// The deletion driver still allows one unhealthy Pod to be restarted in that case.
+func (b deletionBudget) maxUnavailableReached(pod corev1.Pod) bool {
+	for _, key := range b.keys(pod) {
+		if b.allowed[key] <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isExhausted returns true if no more deletion is allowed for this Pod during the current reconciliation.
+func (b deletionBudget) isExhausted(pod corev1.Pod) bool {
+	for _, key := range b.keys(pod) {
+		if b.exhausted[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// consume records the deletion of the given Pod.
+func (b deletionBudget) consume(pod corev1.Pod) {
+	for _, key := range b.keys(pod) {
+		b.allowed[key]--
+		if b.allowed[key] <= 0 {
+			b.exhausted[key] = true
+		}
+	}
+}
+
+// getDeletionBudget returns the number of deletions that can be done, cluster-wide and for the NodeSets with their own
+// maxUnavailable.
+func (ctx *upgradeCtx) getDeletionBudget() deletionBudget {
+	budget := deletionBudget{allowed: make(map[string]int), exhausted: make(map[string]bool)}
 	// Check if we are not over disruption budget
 	// Upscale is done, we should have the required number of Pods
-	actualPods := ctx.statefulSets.PodNames()
-	unhealthyPods := len(actualPods) - len(ctx.healthyPods)
+	budget.allowed[clusterBudgetKey] = allowedDeletions(
+		ctx.ES.Spec.UpdateStrategy.ChangeBudget.GetMaxUnavailableOrDefault(), ctx.statefulSets.PodNames(), ctx.healthyPods,
+	)
+	for _, nodeSet := range ctx.ES.Spec.NodeSets {
+		if nodeSet.MaxUnavailable == nil {
+			continue
+		}
+		statefulSet, exists := ctx.statefulSets.GetByName(esv1.StatefulSet(ctx.ES.Name, nodeSet.Name))
+		if !exists {
+			continue
+		}
+		budget.allowed[statefulSet.Name] = allowedDeletions(
+			nodeSet.MaxUnavailable, sset.StatefulSetList{statefulSet}.PodNames(), ctx.healthyPods,
+		)
+	}
+	return budget
+}
 
-	maxUnavailable := ctx.ES.Spec.UpdateStrategy.ChangeBudget.GetMaxUnavailableOrDefault()
+// allowedDeletions returns the number of Pods which can be deleted among the given ones to stay within maxUnavailable.
+// A nil maxUnavailable is unbounded and allows removing all Pods.
+func allowedDeletions(maxUnavailable *int32, podNames []string, healthyPods map[string]corev1.Pod) int {
 	if maxUnavailable == nil {
-		// maxUnavailable is unbounded, we allow removing all pods
-		return len(actualPods), false
+		return len(podNames)
 	}
-
-	allowedDeletions := int(*maxUnavailable) - unhealthyPods
-	// If maxUnavailable is reached the deletion driver still allows one unhealthy Pod to be restarted.
-	maxUnavailableReached := allowedDeletions <= 0
-	return allowedDeletions, maxUnavailableReached
+	unhealthyPods := 0
+	for _, name := range podNames {
+		if _, healthy := healthyPods[name]; !healthy {
+			unhealthyPods++
+		}
+	}
+	return int(*maxUnavailable) - unhealthyPods
 }
 
 // sortCandidates is the default sort function, masters have lower priority as
@@ -0,0 +1,158 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/migration"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/shutdown"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestUpgradePodsDeletion_NodeSetMaxUnavailable(t *testing.T) {
+	dataSset := esv1.StatefulSet(TestEsName, "data")
+	otherSset := esv1.StatefulSet(TestEsName, "other")
+	podIn := func(ssetName string, ordinal string) testPod {
+		return newTestPod(ssetName + "-" + ordinal).inStatefulset(ssetName).withRoles(esv1.DataRole).
+			isHealthy(true).needsUpgrade(true).isInCluster(true)
+	}
+	dataPod := func(ordinal string) testPod {
+		return podIn(dataSset, ordinal)
+	}
+	shard := func(nodeName string, shardType client.ShardType) client.Shard {
+		return client.Shard{Index: "index_a", Shard: "0", State: "STARTED", NodeName: nodeName, Type: shardType}
+	}
+
+	tests := []struct {
+		name                  string
+		upgradeTestPods       upgradeTestPods
+		maxUnavailable        int
+		nodeSetMaxUnavailable *int32
+		shards                client.Shards
+		deleted               []string
+	}{
+		{
+			name: "cluster-wide maxUnavailable applies by default",
+			upgradeTestPods: newUpgradeTestPods(
+				dataPod("0"), dataPod("1"), dataPod("2"), dataPod("3"),
+			),
+			maxUnavailable: 1,
+			deleted:        []string{dataSset + "-3"},
+		},
+		{
+			name: "NodeSet maxUnavailable allows several concurrent deletions",
+			upgradeTestPods: newUpgradeTestPods(
+				dataPod("0"), dataPod("1"), dataPod("2"), dataPod("3"),
+			),
+			maxUnavailable:        4,
+			nodeSetMaxUnavailable: ptr.To[int32](3),
+			deleted:               []string{dataSset + "-3", dataSset + "-2", dataSset + "-1"},
+		},
+		{
+			name: "NodeSet maxUnavailable accounts for unavailable Pods",
+			upgradeTestPods: newUpgradeTestPods(
+				dataPod("0"), dataPod("1"), dataPod("2"), dataPod("3").isHealthy(false).isInCluster(false),
+			),
+			maxUnavailable:        4,
+			nodeSetMaxUnavailable: ptr.To[int32](3),
+			// the unhealthy Pod is restarted first, and counts as unavailable
+			deleted: []string{dataSset + "-3", dataSset + "-2"},
+		},
+		{
+			name: "cluster-wide maxUnavailable bounds the NodeSet maxUnavailable",
+			upgradeTestPods: newUpgradeTestPods(
+				dataPod("0"), dataPod("1"), dataPod("2"), dataPod("3"),
+			),
+			maxUnavailable:        2,
+			nodeSetMaxUnavailable: ptr.To[int32](3),
+			deleted:               []string{dataSset + "-3", dataSset + "-2"},
+		},
+		{
+			name: "deletions in a NodeSet with its own maxUnavailable consume the cluster-wide budget",
+			upgradeTestPods: newUpgradeTestPods(
+				dataPod("0"), dataPod("1"), dataPod("2"), dataPod("3"),
+				podIn(otherSset, "0"), podIn(otherSset, "1"), podIn(otherSset, "2"),
+			),
+			maxUnavailable:        3,
+			nodeSetMaxUnavailable: ptr.To[int32](2),
+			// two deletions in the NodeSet with its own budget, the last one in the other NodeSet
+			deleted: []string{dataSset + "-3", dataSset + "-2", otherSset + "-2"},
+		},
+		{
+			name: "unavailable Pods of a NodeSet with its own maxUnavailable consume the cluster-wide budget",
+			upgradeTestPods: newUpgradeTestPods(
+				dataPod("0"), dataPod("1"), dataPod("2"), dataPod("3").isHealthy(false).isInCluster(false),
+				podIn(otherSset, "0"), podIn(otherSset, "1"),
+			),
+			maxUnavailable:        3,
+			nodeSetMaxUnavailable: ptr.To[int32](4),
+			// the unhealthy Pod is restarted first, and counts as unavailable in the whole cluster
+			deleted: []string{dataSset + "-3", dataSset + "-2"},
+		},
+		{
+			name: "concurrency is reduced to never restart Pods holding copies of the same shard",
+			upgradeTestPods: newUpgradeTestPods(
+				dataPod("0"), dataPod("1"), dataPod("2"), dataPod("3"),
+			),
+			maxUnavailable:        4,
+			nodeSetMaxUnavailable: ptr.To[int32](3),
+			shards: client.Shards{
+				shard(dataSset+"-3", "p"),
+				shard(dataSset+"-2", "r"),
+				shard(dataSset+"-1", "r"),
+			},
+			deleted: []string{dataSset + "-3", dataSset + "-0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esVersion := "7.5.0"
+			esState := &testESState{
+				inCluster: tt.upgradeTestPods.podsInCluster(),
+				health:    client.Health{Status: esv1.ElasticsearchGreenHealth},
+			}
+			esClient := &fakeESClient{version: version.MustParse(esVersion)}
+			k8sClient := k8s.NewFakeClient(tt.upgradeTestPods.toClientObjects(esVersion, tt.maxUnavailable, nothing, nil)...)
+			es := tt.upgradeTestPods.toES(esVersion, tt.maxUnavailable, nil)
+			es.Spec.NodeSets = []esv1.NodeSet{
+				{Name: "data", Count: 4, MaxUnavailable: tt.nodeSetMaxUnavailable},
+				{Name: "other", Count: 3},
+			}
+			ctx := upgradeCtx{
+				parentCtx:       context.Background(),
+				reconcileState:  reconcile.MustNewState(es),
+				client:          k8sClient,
+				ES:              es,
+				resourcesList:   tt.upgradeTestPods.toResourcesList(t),
+				statefulSets:    tt.upgradeTestPods.toStatefulSetList(),
+				esClient:        esClient,
+				shardLister:     migration.NewFakeShardLister(tt.shards),
+				esState:         esState,
+				expectations:    expectations.NewExpectations(k8sClient),
+				expectedMasters: tt.upgradeTestPods.toMasters(noMutation),
+				podsToUpgrade:   tt.upgradeTestPods.toUpgrade(),
+				healthyPods:     tt.upgradeTestPods.toHealthyPods(),
+				currentPods:     tt.upgradeTestPods.toCurrentPods(),
+				nodeShutdown:    shutdown.NewNodeShutdown(esClient, tt.upgradeTestPods.podNamesToESNodeID(), client.Restart, "", crlog.Log),
+			}
+
+			deleted, err := ctx.Delete()
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.deleted, names(deleted))
+		})
+	}
+}
@@ -159,15 +159,16 @@ func NewPredicateContext(
 func applyPredicates(
 	ctx PredicateContext,
 	candidates []corev1.Pod,
-	maxUnavailableReached bool,
-	allowedDeletions int,
+	budget deletionBudget,
 	reconcileState *reconcile.State,
 ) (deletedPods []corev1.Pod, err error) {
 	failedPredicates := make(failedPredicates)
 
-Loop:
 	for _, candidate := range candidates {
-		switch predicateErr, err := runPredicates(ctx, candidate, deletedPods, maxUnavailableReached); {
+		if budget.isExhausted(candidate) {
+			continue
+		}
+		switch predicateErr, err := runPredicates(ctx, candidate, deletedPods, budget.maxUnavailableReached(candidate)); {
 		case err != nil:
 			return deletedPods, err
 		case predicateErr != nil:
@@ -183,10 +184,7 @@ Loop:
 			delete(ctx.healthyPods, candidate.Name)
 			// Append to the deletedPods list
 			deletedPods = append(deletedPods, candidate)
-			allowedDeletions--
-			if allowedDeletions <= 0 {
-				break Loop
-			}
+			budget.consume(candidate)
 		}
 	}
 
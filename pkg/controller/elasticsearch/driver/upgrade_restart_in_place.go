@@ -0,0 +1,77 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+// restartInPlaceAllocationDelay returns the allocation delay to request when restarting the given Pods in place. Zero is
+// returned if restarting in place is not enabled, if some Pods store their data in an ephemeral volume and come back
+// without it, or if some indices have no replica to serve their data while a node restarts, in which case the default
+// restart procedure applies.
+func (ctx *upgradeCtx) restartInPlaceAllocationDelay(podsToRestart []corev1.Pod) (time.Duration, error) {
+	restartInPlace := ctx.ES.Spec.UpdateStrategy.RestartInPlace
+	if !restartInPlace.Enabled {
+		return 0, nil
+	}
+	if pods := podsWithEphemeralData(ctx.ES, podsToRestart); len(pods) > 0 {
+		ulog.FromContext(ctx.parentCtx).Info(
+			"Not restarting nodes in place as their data is lost on restart",
+			"namespace", ctx.ES.Namespace, "es_name", ctx.ES.Name, "pods", pods,
+		)
+		return 0, nil
+	}
+	shards, err := ctx.shardLister.GetShards(ctx.parentCtx)
+	if err != nil {
+		return 0, err
+	}
+	if indices := indicesWithoutReplicas(shards); len(indices) > 0 {
+		ulog.FromContext(ctx.parentCtx).Info(
+			"Not restarting nodes in place as some indices have no replica",
+			"namespace", ctx.ES.Namespace, "es_name", ctx.ES.Name, "indices", indices,
+		)
+		return 0, nil
+	}
+	return restartInPlace.GetAllocationDelay(), nil
+}
+
+// podsWithEphemeralData returns the names of the given Pods which belong to a NodeSet with an ephemeral data volume.
+func podsWithEphemeralData(es esv1.Elasticsearch, pods []corev1.Pod) []string {
+	ephemeral := set.Make()
+	for _, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.HasEphemeralDataVolume() {
+			ephemeral.Add(esv1.StatefulSet(es.Name, nodeSet.Name))
+		}
+	}
+	var names []string
+	for _, pod := range pods {
+		if ephemeral.Has(pod.Labels[label.StatefulSetNameLabelName]) {
+			names = append(names, pod.Name)
+		}
+	}
+	return names
+}
+
+// indicesWithoutReplicas returns the sorted names of the indices which do not have any replica shard.
+func indicesWithoutReplicas(shards esclient.Shards) []string {
+	indices := set.Make()
+	replicated := set.Make()
+	for _, shard := range shards {
+		indices.Add(shard.Index)
+		if shard.Type == esclient.Replica {
+			replicated.Add(shard.Index)
+		}
+	}
+	return indices.Diff(replicated).AsSortedSlice()
+}
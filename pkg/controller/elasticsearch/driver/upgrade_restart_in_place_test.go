@@ -0,0 +1,106 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/migration"
+)
+
+func Test_upgradeCtx_restartInPlaceAllocationDelay(t *testing.T) {
+	replicatedShards := client.Shards{
+		{Index: "index_a", Shard: "0", State: client.STARTED, NodeName: "node-0", Type: client.Primary},
+		{Index: "index_a", Shard: "0", State: client.STARTED, NodeName: "node-1", Type: client.Replica},
+		{Index: "index_b", Shard: "0", State: client.STARTED, NodeName: "node-1", Type: client.Primary},
+		{Index: "index_b", Shard: "0", State: client.UNASSIGNED, Type: client.Replica},
+	}
+	tests := []struct {
+		name           string
+		restartInPlace esv1.RestartInPlace
+		ephemeral      bool
+		shards         client.Shards
+		want           time.Duration
+	}{
+		{
+			name:           "disabled",
+			restartInPlace: esv1.RestartInPlace{},
+			shards:         replicatedShards,
+			want:           0,
+		},
+		{
+			name:           "enabled with every index replicated: default allocation delay",
+			restartInPlace: esv1.RestartInPlace{Enabled: true},
+			shards:         replicatedShards,
+			want:           esv1.DefaultRestartInPlaceAllocationDelay,
+		},
+		{
+			name:           "enabled with every index replicated: custom allocation delay",
+			restartInPlace: esv1.RestartInPlace{Enabled: true, AllocationDelay: &metav1.Duration{Duration: 30 * time.Minute}},
+			shards:         replicatedShards,
+			want:           30 * time.Minute,
+		},
+		{
+			name:           "enabled with a Pod storing its data in an ephemeral volume: fall back to the default restart procedure",
+			restartInPlace: esv1.RestartInPlace{Enabled: true},
+			ephemeral:      true,
+			shards:         replicatedShards,
+			want:           0,
+		},
+		{
+			name:           "enabled with an index without replica: fall back to the default restart procedure",
+			restartInPlace: esv1.RestartInPlace{Enabled: true},
+			shards: append(client.Shards{
+				{Index: "index_c", Shard: "0", State: client.STARTED, NodeName: "node-0", Type: client.Primary},
+			}, replicatedShards...),
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Name: "es"},
+				Spec: esv1.ElasticsearchSpec{
+					UpdateStrategy: esv1.UpdateStrategy{RestartInPlace: tt.restartInPlace},
+					NodeSets: []esv1.NodeSet{
+						{Name: "hot"},
+						{Name: "search", EphemeralDataVolume: &esv1.EphemeralDataVolume{AcknowledgeDataLoss: true}},
+					},
+				},
+			}
+			podsToRestart := []corev1.Pod{newTestPod("es-es-hot-0").inStatefulset("es-es-hot").toPod()}
+			if tt.ephemeral {
+				podsToRestart = append(podsToRestart, newTestPod("es-es-search-0").inStatefulset("es-es-search").toPod())
+			}
+			ctx := upgradeCtx{
+				parentCtx:   context.Background(),
+				ES:          es,
+				shardLister: migration.NewFakeShardLister(tt.shards),
+			}
+			got, err := ctx.restartInPlaceAllocationDelay(podsToRestart)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_indicesWithoutReplicas(t *testing.T) {
+	require.Empty(t, indicesWithoutReplicas(nil))
+	require.Equal(t, []string{"index_a", "index_c"}, indicesWithoutReplicas(client.Shards{
+		{Index: "index_c", Shard: "0", Type: client.Primary},
+		{Index: "index_a", Shard: "0", Type: client.Primary},
+		{Index: "index_a", Shard: "1", Type: client.Primary},
+		{Index: "index_b", Shard: "0", Type: client.Primary},
+		{Index: "index_b", Shard: "0", Type: client.Replica},
+	}))
+}
@@ -242,6 +242,109 @@ func Test_doFlush(t *testing.T) {
 	}
 }
 
+func Test_upgradeCtx_prepareClusterForNodeRestart(t *testing.T) {
+	hotPod := newTestPod("es-es-hot-0").inStatefulset("es-es-hot").isHealthy(true).toPod()
+	searchPod := newTestPod("es-es-search-0").inStatefulset("es-es-search").isHealthy(true).toPod()
+	tests := []struct {
+		name               string
+		version            string
+		pods               []corev1.Pod
+		fullRestart        bool
+		allocationDisabled bool
+		wantShutdowns      map[string]esclient.ShutdownType
+		wantDisableCalled  bool
+		wantEnableCalled   bool
+		wantFlushCalled    bool
+	}{
+		{
+			name:          "node shutdown: remove Pods with ephemeral data, restart the others",
+			version:       "7.17.0",
+			pods:          []corev1.Pod{hotPod, searchPod},
+			wantShutdowns: map[string]esclient.ShutdownType{"es-es-hot-0": esclient.Restart, "es-es-search-0": esclient.Remove},
+		},
+		{
+			name:          "node shutdown: restart all Pods during a full cluster restart",
+			version:       "7.17.0",
+			pods:          []corev1.Pod{hotPod, searchPod},
+			fullRestart:   true,
+			wantShutdowns: map[string]esclient.ShutdownType{"es-es-hot-0": esclient.Restart, "es-es-search-0": esclient.Restart},
+		},
+		{
+			name:              "no node shutdown: disable shards allocation and flush",
+			version:           "7.10.2",
+			pods:              []corev1.Pod{hotPod},
+			wantDisableCalled: true,
+			wantFlushCalled:   true,
+		},
+		{
+			name:    "no node shutdown: keep shards allocation enabled for Pods with ephemeral data",
+			version: "7.10.2",
+			pods:    []corev1.Pod{hotPod, searchPod},
+		},
+		{
+			name:               "no node shutdown: enable shards allocation for Pods with ephemeral data",
+			version:            "7.10.2",
+			pods:               []corev1.Pod{searchPod},
+			allocationDisabled: true,
+			wantEnableCalled:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Name: "es"},
+				Spec: esv1.ElasticsearchSpec{
+					Version: tt.version,
+					NodeSets: []esv1.NodeSet{
+						{Name: "hot"},
+						{Name: "search", EphemeralDataVolume: &esv1.EphemeralDataVolume{AcknowledgeDataLoss: true}},
+					},
+				},
+			}
+			esClient := &fakeESClient{version: version.MustParse(tt.version)}
+			podToNodeID := map[string]string{hotPod.Name: hotPod.Name, searchPod.Name: searchPod.Name}
+			ctx := upgradeCtx{
+				parentCtx:    context.Background(),
+				ES:           es,
+				esClient:     esClient,
+				esState:      &testESState{allocationDisabled: tt.allocationDisabled},
+				nodeShutdown: shutdown.NewNodeShutdown(esClient, podToNodeID, esclient.Restart, "", crlog.Log),
+				nodeRemoval:  shutdown.NewNodeShutdown(esClient, podToNodeID, esclient.Remove, "", crlog.Log),
+				fullRestart:  tt.fullRestart,
+				currentPods:  tt.pods,
+			}
+			require.NoError(t, ctx.prepareClusterForNodeRestart(tt.pods))
+
+			shutdowns := map[string]esclient.ShutdownType{}
+			for nodeID, s := range esClient.Shutdowns {
+				shutdowns[nodeID] = esclient.ShutdownType(s.Type)
+			}
+			if tt.wantShutdowns == nil {
+				tt.wantShutdowns = map[string]esclient.ShutdownType{}
+			}
+			require.Equal(t, tt.wantShutdowns, shutdowns)
+			require.Equal(t, tt.wantDisableCalled, esClient.DisableReplicaShardsAllocationCalled)
+			require.Equal(t, tt.wantEnableCalled, esClient.EnableShardAllocationCalled)
+			require.Equal(t, tt.wantFlushCalled, esClient.SyncedFlushCalled)
+
+			if supportsNodeShutdown(esClient.Version()) {
+				// Pods can be deleted once the shutdown of the expected type completes
+				for nodeID, s := range esClient.Shutdowns {
+					s.Status = esclient.ShutdownComplete
+					esClient.Shutdowns[nodeID] = s
+				}
+				ctx.nodeShutdown = shutdown.NewNodeShutdown(esClient, podToNodeID, esclient.Restart, "", crlog.Log)
+				ctx.nodeRemoval = shutdown.NewNodeShutdown(esClient, podToNodeID, esclient.Remove, "", crlog.Log)
+				for _, pod := range tt.pods {
+					ready, err := ctx.readyToDelete(pod)
+					require.NoError(t, err)
+					require.True(t, ready)
+				}
+			}
+		})
+	}
+}
+
 func Test_isNonHACluster(t *testing.T) {
 	type args struct {
 		actualPods      []corev1.Pod
@@ -67,11 +67,8 @@ func HandleUpscaleAndSpecChanges(
 	// reconcile all resources
 	for _, res := range adjusted {
 		res := res
-		if err := settings.ReconcileConfig(ctx.parentCtx, ctx.k8sClient, ctx.es, res.StatefulSet.Name, res.Config); err != nil {
-			return results, fmt.Errorf("reconcile config: %w", err)
-		}
-		if _, err := common.ReconcileService(ctx.parentCtx, ctx.k8sClient, &res.HeadlessService, &ctx.es); err != nil {
-			return results, fmt.Errorf("reconcile service: %w", err)
+		if err := reconcileConfigAndHeadlessService(ctx.parentCtx, ctx.k8sClient, ctx.es, res); err != nil {
+			return results, err
 		}
 		if actualSset, exists := actualStatefulSets.GetByName(res.StatefulSet.Name); exists {
 			recreateSset, err := handleVolumeExpansion(ctx.parentCtx, ctx.k8sClient, ctx.es, res.StatefulSet, actualSset, ctx.validateStorageClass)
@@ -95,6 +92,18 @@ func HandleUpscaleAndSpecChanges(
 	return results, nil
 }
 
+// reconcileConfigAndHeadlessService reconciles the configuration Secret and the headless Service of the given expected
+// NodeSet resources.
+func reconcileConfigAndHeadlessService(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, res nodespec.Resources) error {
+	if err := settings.ReconcileConfig(ctx, k8sClient, es, res.StatefulSet.Name, res.Config, res.JVMOptions); err != nil {
+		return fmt.Errorf("reconcile config: %w", err)
+	}
+	if _, err := common.ReconcileService(ctx, k8sClient, &res.HeadlessService, &es); err != nil {
+		return fmt.Errorf("reconcile service: %w", err)
+	}
+	return nil
+}
+
 func podsToCreate(
 	actualStatefulSets, expectedStatefulSets es_sset.StatefulSetList,
 ) []string {
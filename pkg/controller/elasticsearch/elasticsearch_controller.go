@@ -6,14 +6,17 @@ package elasticsearch
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"sync/atomic"
+	"time"
 
 	pkgerrors "github.com/pkg/errors"
 	"go.elastic.co/apm/v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -23,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
@@ -41,12 +45,15 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/observer"
 	esreconcile "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/remotecluster"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/validation"
 	esversion "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 const name = "elasticsearch-controller"
@@ -56,7 +63,7 @@ const name = "elasticsearch-controller"
 // this is also called by cmd/main.go
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	reconciler := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, name, reconciler, params)
+	c, err := common.NewControllerWithRateLimiter(mgr, name, reconciler, &esv1.Elasticsearch{}, params, reconciler.backoff.RateLimiter())
 	if err != nil {
 		return err
 	}
@@ -74,6 +81,7 @@ func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileEl
 
 		dynamicWatches: watches.NewDynamicWatches(),
 		expectations:   expectations.NewClustersExpectations(client),
+		backoff:        reconciler.NewDefaultBackoff(),
 
 		Parameters: params,
 	}
@@ -87,6 +95,13 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileElasti
 		return err
 	}
 
+	// Watch remote Elasticsearch clusters to update the seeds of the clusters referencing them
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &esv1.Elasticsearch{}), remotecluster.RequestsForReferencingClusters(r.Client),
+	); err != nil {
+		return err
+	}
+
 	// Watch StatefulSets
 	if err := c.Watch(
 		source.Kind(mgr.GetCache(), &appsv1.StatefulSet{}), handler.EnqueueRequestForOwner(
@@ -152,6 +167,9 @@ type ReconcileElasticsearch struct {
 	// by marking resources updates as expected, and skipping some operations if the cache is not up-to-date.
 	expectations *expectations.ClustersExpectation
 
+	// backoff slows down the reconciliation of clusters whose reconciliations keep failing for the same reason.
+	backoff *reconciler.Backoff
+
 	// iteration is the number of times this controller has run its Reconcile method
 	iteration uint64
 }
@@ -171,9 +189,21 @@ func (r *ReconcileElasticsearch) Reconcile(ctx context.Context, request reconcil
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
-	if common.IsUnmanaged(ctx, &es) {
+	unmanaged := common.IsUnmanaged(ctx, &es)
+	dryRun := reconciler.IsDryRun(&es)
+	if (unmanaged || dryRun) && es.IsMarkedForDeletion() {
+		// neither pausing the reconciliation nor a dry-run reconciliation must prevent the cluster from being deleted
+		return reconcile.Result{}, tracing.CaptureError(ctx, r.cleanupDeleted(ctx, es))
+	}
+
+	if unmanaged {
 		log.Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", es.Namespace, "es_name", es.Name)
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, tracing.CaptureError(ctx, r.reportPaused(ctx, es))
+	}
+
+	if dryRun {
+		log.Info("Object is annotated for a dry-run reconciliation. Planning changes without applying them", "namespace", es.Namespace, "es_name", es.Name)
+		return reconcile.Result{}, tracing.CaptureError(ctx, r.dryRun(ctx, es))
 	}
 
 	// Remove any previous Finalizers
@@ -188,6 +218,15 @@ func (r *ReconcileElasticsearch) Reconcile(ctx context.Context, request reconcil
 
 	// ReconciliationComplete is initially set to True until another condition with the same type is reported.
 	state.ReportCondition(esv1.ReconciliationComplete, corev1.ConditionTrue, "")
+	if es.Status.Conditions.Index(esv1.ReconciliationPaused) >= 0 {
+		// the reconciliation is resumed
+		state.ReportCondition(esv1.ReconciliationPaused, corev1.ConditionFalse, "")
+	}
+
+	if _, requested := common.ReconcileNowRequested(&es); requested {
+		// an explicitly requested reconciliation is not delayed by previous failures
+		r.backoff.Reset(k8s.ExtractNamespacedName(&es))
+	}
 
 	results := r.internalReconcile(ctx, es, state)
 
@@ -209,6 +248,10 @@ func (r *ReconcileElasticsearch) Reconcile(ctx context.Context, request reconcil
 	} else {
 		state.UpdateWithPhase(esv1.ElasticsearchReadyPhase)
 	}
+	_, reconcileErr := results.Aggregate()
+	state.ReportReconciliationOutcome(reconcileErr)
+
+	backoffInterval := r.reportFailures(es, state, results)
 
 	// Last step of the reconciliation loop is always to update the Elasticsearch resource status.
 	err = r.updateStatus(ctx, es, state)
@@ -219,7 +262,63 @@ func (r *ReconcileElasticsearch) Reconcile(ctx context.Context, request reconcil
 		}
 		k8s.MaybeEmitErrorEvent(r.recorder, err, &es, events.EventReconciliationError, "Reconciliation error: %v", err)
 	}
-	return results.WithError(err).Aggregate()
+	// record the requested reconciliation as observed once the status is updated, to avoid conflicting with the status update
+	if observeErr := common.ObserveReconcileNow(ctx, r.Client, &es); observeErr != nil && err == nil {
+		err = observeErr
+	}
+	if backoffInterval > 0 {
+		// the error is still returned, for the controller to report it: its rate limiter delays the next reconciliation
+		// by the backoff interval
+		log.Info("Reconciliation repeatedly failing, backing off", "namespace", es.Namespace, "es_name", es.Name, "requeue_after", backoffInterval)
+	}
+	result, err := results.WithError(err).Aggregate()
+	return common.WithResync(ctx, &es, r.ResyncPeriod, result), err
+}
+
+// reportFailures records the outcome of the reconciliation in the backoff, reports the ReconciliationDegraded
+// condition accordingly, and returns the interval to wait for before the next reconciliation, if any.
+func (r *ReconcileElasticsearch) reportFailures(es esv1.Elasticsearch, state *esreconcile.State, results *reconciler.Results) time.Duration {
+	key := k8s.ExtractNamespacedName(&es)
+	var interval time.Duration
+	degraded := false
+	if _, err := results.Aggregate(); err != nil {
+		interval, degraded = r.backoff.Failure(key, es.Generation, err.Error())
+		if degraded {
+			state.ReportCondition(esv1.ReconciliationDegraded, corev1.ConditionTrue, fmt.Sprintf("Reconciliation repeatedly failing: %s", err.Error()))
+		}
+	} else {
+		r.backoff.Reset(key)
+	}
+	if !degraded && es.Status.Conditions.Index(esv1.ReconciliationDegraded) >= 0 {
+		// the cluster recovered, or its spec changed since it was reported as degraded
+		state.ReportCondition(esv1.ReconciliationDegraded, corev1.ConditionFalse, "")
+	}
+	return interval
+}
+
+// reportPaused records the ReconciliationPaused condition in the status of the given cluster, whose reconciliation is
+// skipped since it is not managed by the operator anymore.
+func (r *ReconcileElasticsearch) reportPaused(ctx context.Context, es esv1.Elasticsearch) error {
+	if i := es.Status.Conditions.Index(esv1.ReconciliationPaused); i >= 0 && es.Status.Conditions[i].Status == corev1.ConditionTrue {
+		return nil
+	}
+	r.recorder.Event(&es, corev1.EventTypeNormal, events.EventReasonPaused, common.PausedMessage)
+	es.Status.Conditions = es.Status.Conditions.MergeWith(commonv1alpha1.Condition{
+		Type:               esv1.ReconciliationPaused,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Message:            common.PausedMessage,
+	})
+	return common.UpdateStatus(ctx, r.Client, &es)
+}
+
+// cleanupDeleted removes any previous finalizers and garbage collects the resources of the given cluster, which is
+// being deleted.
+func (r *ReconcileElasticsearch) cleanupDeleted(ctx context.Context, es esv1.Elasticsearch) error {
+	if err := finalizer.RemoveAll(ctx, r.Client, &es); err != nil {
+		return err
+	}
+	return r.onDelete(ctx, k8s.ExtractNamespacedName(&es))
 }
 
 func (r *ReconcileElasticsearch) fetchElasticsearchWithAssociations(ctx context.Context, request reconcile.Request, es *esv1.Elasticsearch) (bool, error) {
@@ -304,6 +403,57 @@ func (r *ReconcileElasticsearch) internalReconcile(
 	}).Reconcile(ctx)
 }
 
+// dryRun computes the changes the reconciliation would apply to the Kubernetes resources of the cluster and reports
+// them as events on the Elasticsearch resource, without applying them.
+func (r *ReconcileElasticsearch) dryRun(ctx context.Context, es esv1.Elasticsearch) error {
+	if err := validation.ValidateElasticsearch(ctx, es, r.licenseChecker, r.ExposedNodeLabels); err != nil {
+		r.recorder.Event(&es, corev1.EventTypeWarning, events.EventReasonValidation, err.Error())
+		return nil
+	}
+
+	ver, err := commonversion.Parse(es.Spec.Version)
+	if err != nil {
+		return err
+	}
+	supported := esversion.SupportedVersions(ver)
+	if supported == nil {
+		return pkgerrors.Errorf("unsupported version: %s", ver)
+	}
+
+	state, err := esreconcile.NewState(es)
+	if err != nil {
+		return err
+	}
+
+	dryRunClient, plan := reconciler.NewDryRunClient(r.Client)
+	err = driver.NewDefaultDriver(driver.DefaultDriverParameters{
+		OperatorParameters: r.Parameters,
+		ES:                 es,
+		ReconcileState:     state,
+		Client:             dryRunClient,
+		Recorder:           r.recorder,
+		Version:            ver,
+		Expectations:       expectations.NewExpectations(dryRunClient),
+		Observers:          r.esObservers,
+		DynamicWatches:     r.dynamicWatches,
+		SupportedVersions:  *supported,
+		LicenseChecker:     r.licenseChecker,
+	}).Plan(ctx)
+	if err != nil {
+		return err
+	}
+
+	actions := plan.Actions()
+	ulog.FromContext(ctx).Info("Dry-run reconciliation planned", "namespace", es.Namespace, "es_name", es.Name, "planned_actions", len(actions))
+	if len(actions) == 0 {
+		r.recorder.Event(&es, corev1.EventTypeNormal, events.EventReasonDryRun, "No changes planned")
+	}
+	for _, action := range actions {
+		r.recorder.Event(&es, corev1.EventTypeNormal, events.EventReasonDryRun, "Planned: "+action.String())
+	}
+	return nil
+}
+
 func (r *ReconcileElasticsearch) updateStatus(
 	ctx context.Context,
 	es esv1.Elasticsearch,
@@ -362,11 +512,17 @@ func (r *ReconcileElasticsearch) annotateResource(
 func (r *ReconcileElasticsearch) onDelete(ctx context.Context, es types.NamespacedName) error {
 	r.expectations.RemoveCluster(es)
 	r.esObservers.StopObserving(es)
+	r.backoff.Reset(es)
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(es))
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.PasswordWatchName(es))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(certificates.CertificateWatchKey(esv1.ESNamer, es.Name))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(transport.CustomTransportCertsWatchKey(es))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.UserProvidedRolesWatchName(es))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.UserProvidedFileRealmWatchName(es))
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.BuiltinUserPasswordsWatchName(es))
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(settings.UserProvidedLog4j2ConfigWatchName(es))
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(settings.UserProvidedTrustedCAWatchName(es))
 	r.dynamicWatches.ConfigMaps.RemoveHandlerForKey(transport.AdditionalCAWatchKey(es))
+	metrics.DeleteCertificatesRemainingValidity(es.Namespace, es.Name)
 	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, es, esv1.Kind)
 }
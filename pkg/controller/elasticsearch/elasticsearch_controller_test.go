@@ -5,8 +5,13 @@ package elasticsearch
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -17,7 +22,13 @@ import (
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/comparison"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/observer"
+	esreconcile "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -114,7 +125,7 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 		expected        esv1.Elasticsearch
 	}{
 		{
-			name: "unmanaged ES has no error, and no observedGeneration update",
+			name: "unmanaged ES has no error, is reported as paused, and no observedGeneration update",
 			k8sClientFields: k8sClientFields{
 				[]client.Object{
 					newBuilder("testES", "test").
@@ -134,7 +145,12 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 			expected: newBuilder("testES", "test").
 				WithGeneration(2).
 				WithAnnotations(map[string]string{common.ManagedAnnotation: "false"}).
-				WithStatus(esv1.ElasticsearchStatus{ObservedGeneration: 1}).BuildAndCopy(),
+				WithStatus(esv1.ElasticsearchStatus{
+					ObservedGeneration: 1,
+					Conditions: commonv1alpha1.Conditions{
+						{Type: esv1.ReconciliationPaused, Status: corev1.ConditionTrue, Message: common.PausedMessage},
+					},
+				}).BuildAndCopy(),
 		},
 		{
 			name: "ES with too long name, fails initial reconcile, but has observedGeneration updated",
@@ -160,10 +176,14 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 				WithAnnotations(map[string]string{hints.OrchestrationsHintsAnnotation: `{"no_transient_settings":false}`}).
 				WithStatus(
 					esv1.ElasticsearchStatus{
-						ObservedGeneration:   2,
-						Phase:                esv1.ElasticsearchResourceInvalid,
-						Health:               esv1.ElasticsearchUnknownHealth,
-						Conditions:           commonv1alpha1.Conditions{commonv1alpha1.Condition{Type: "ReconciliationComplete", Status: "True"}},
+						ObservedGeneration: 2,
+						Phase:              esv1.ElasticsearchResourceInvalid,
+						Health:             esv1.ElasticsearchUnknownHealth,
+						Conditions: commonv1alpha1.Conditions{
+							commonv1alpha1.Condition{Type: "ReconciliationComplete", Status: "True"},
+							commonv1alpha1.Condition{Type: "ReconciliationError", Status: "False", Reason: "ReconciliationSucceeded"},
+							commonv1alpha1.Condition{Type: "Ready", Status: "False", Reason: "Invalid"},
+						},
 						InProgressOperations: noInProgressOperations,
 					},
 				).BuildAndCopy(),
@@ -192,10 +212,14 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 				WithAnnotations(map[string]string{hints.OrchestrationsHintsAnnotation: `{"no_transient_settings":false}`}).
 				WithStatus(
 					esv1.ElasticsearchStatus{
-						ObservedGeneration:   2,
-						Phase:                esv1.ElasticsearchResourceInvalid,
-						Health:               esv1.ElasticsearchUnknownHealth,
-						Conditions:           commonv1alpha1.Conditions{commonv1alpha1.Condition{Type: "ReconciliationComplete", Status: "True"}},
+						ObservedGeneration: 2,
+						Phase:              esv1.ElasticsearchResourceInvalid,
+						Health:             esv1.ElasticsearchUnknownHealth,
+						Conditions: commonv1alpha1.Conditions{
+							commonv1alpha1.Condition{Type: "ReconciliationComplete", Status: "True"},
+							commonv1alpha1.Condition{Type: "ReconciliationError", Status: "False", Reason: "ReconciliationSucceeded"},
+							commonv1alpha1.Condition{Type: "Ready", Status: "False", Reason: "Invalid"},
+						},
 						InProgressOperations: noInProgressOperations,
 					},
 				).BuildAndCopy(),
@@ -225,10 +249,14 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 				WithAnnotations(map[string]string{hints.OrchestrationsHintsAnnotation: `{"no_transient_settings":false}`}).
 				WithStatus(
 					esv1.ElasticsearchStatus{
-						ObservedGeneration:   2,
-						Phase:                esv1.ElasticsearchResourceInvalid,
-						Health:               esv1.ElasticsearchUnknownHealth,
-						Conditions:           commonv1alpha1.Conditions{commonv1alpha1.Condition{Type: "ReconciliationComplete", Status: "True"}},
+						ObservedGeneration: 2,
+						Phase:              esv1.ElasticsearchResourceInvalid,
+						Health:             esv1.ElasticsearchUnknownHealth,
+						Conditions: commonv1alpha1.Conditions{
+							commonv1alpha1.Condition{Type: "ReconciliationComplete", Status: "True"},
+							commonv1alpha1.Condition{Type: "ReconciliationError", Status: "False", Reason: "ReconciliationSucceeded"},
+							commonv1alpha1.Condition{Type: "Ready", Status: "False", Reason: "Invalid"},
+						},
 						InProgressOperations: noInProgressOperations,
 					},
 				).BuildAndCopy(),
@@ -251,3 +279,176 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileElasticsearch_reportFailures(t *testing.T) {
+	es := newBuilder("es", "ns").WithGeneration(1).BuildAndCopy()
+	r := newTestReconciler()
+	r.backoff = reconciler.NewBackoff(time.Second, time.Minute, 3)
+
+	// degradedCondition reports the outcome of a reconciliation and returns the resulting ReconciliationDegraded condition
+	degradedCondition := func(es esv1.Elasticsearch, err error) (time.Duration, *commonv1alpha1.Condition) {
+		t.Helper()
+		state := esreconcile.MustNewState(es)
+		results := &reconciler.Results{}
+		if err != nil {
+			results.WithError(err)
+		}
+		interval := r.reportFailures(es, state, results)
+		_, updated := state.Apply()
+		if updated == nil {
+			updated = &es
+		}
+		if i := updated.Status.Conditions.Index(esv1.ReconciliationDegraded); i >= 0 {
+			return interval, &updated.Status.Conditions[i]
+		}
+		return interval, nil
+	}
+
+	// successive failures back off, and the cluster is reported as degraded past the threshold
+	wantIntervals := []time.Duration{0, time.Second, 2 * time.Second}
+	for i, want := range wantIntervals {
+		interval, condition := degradedCondition(es, errors.New("failure"))
+		require.Equal(t, want, interval)
+		if i < 2 {
+			require.Nil(t, condition)
+			continue
+		}
+		require.NotNil(t, condition)
+		require.Equal(t, corev1.ConditionTrue, condition.Status)
+		require.Equal(t, "Reconciliation repeatedly failing: failure", condition.Message)
+		es.Status.Conditions = es.Status.Conditions.MergeWith(*condition)
+	}
+
+	// a spec change resets the backoff and the degraded condition
+	es.Generation = 2
+	interval, condition := degradedCondition(es, errors.New("failure"))
+	require.Equal(t, time.Duration(0), interval)
+	require.NotNil(t, condition)
+	require.Equal(t, corev1.ConditionFalse, condition.Status)
+
+	// a successful reconciliation resets the backoff
+	_, _ = degradedCondition(es, errors.New("failure"))
+	interval, _ = degradedCondition(es, nil)
+	require.Equal(t, time.Duration(0), interval)
+	interval, _ = degradedCondition(es, errors.New("failure"))
+	require.Equal(t, time.Duration(0), interval)
+}
+
+func TestReconcileElasticsearch_Reconcile_reconcileNow(t *testing.T) {
+	es := newBuilder("testESwithtoolongofanamereallylongname", "test").
+		WithGeneration(1).
+		WithAnnotations(map[string]string{
+			hints.OrchestrationsHintsAnnotation: `{"no_transient_settings":false}`,
+			common.ReconcileNowAnnotation:       "2024-01-01T00:00:00Z",
+		}).
+		Build()
+	r := newTestReconciler(es)
+	request := reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(es)}
+	get := func() esv1.Elasticsearch {
+		var current esv1.Elasticsearch
+		require.NoError(t, r.Client.Get(context.Background(), request.NamespacedName, &current))
+		return current
+	}
+
+	// the requested reconciliation is recorded as observed
+	_, err := r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	reconciled := get()
+	require.Equal(t, "2024-01-01T00:00:00Z", reconciled.Annotations[common.ObservedReconcileNowAnnotation])
+
+	// the resource is left untouched by the next reconciliation, which does not loop
+	_, err = r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, reconciled.ResourceVersion, get().ResourceVersion)
+}
+
+func TestReconcileElasticsearch_Reconcile_paused(t *testing.T) {
+	es := newBuilder("testESwithtoolongofanamereallylongname", "test").
+		WithGeneration(2).
+		WithAnnotations(map[string]string{
+			hints.OrchestrationsHintsAnnotation: `{"no_transient_settings":false}`,
+			common.ManagedAnnotation:            "false",
+		}).
+		WithStatus(esv1.ElasticsearchStatus{ObservedGeneration: 1}).
+		Build()
+	r := newTestReconciler(es)
+	request := reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(es)}
+	get := func() esv1.Elasticsearch {
+		var current esv1.Elasticsearch
+		require.NoError(t, r.Client.Get(context.Background(), request.NamespacedName, &current))
+		return current
+	}
+	conditionStatus := func(es esv1.Elasticsearch) corev1.ConditionStatus {
+		i := es.Status.Conditions.Index(esv1.ReconciliationPaused)
+		require.GreaterOrEqual(t, i, 0)
+		return es.Status.Conditions[i].Status
+	}
+
+	// the paused cluster is skipped, and reported as paused
+	_, err := r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	paused := get()
+	require.Equal(t, int64(1), paused.Status.ObservedGeneration)
+	require.Equal(t, corev1.ConditionTrue, conditionStatus(paused))
+
+	// the status is left untouched by the next reconciliation
+	_, err = r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, paused.ResourceVersion, get().ResourceVersion)
+
+	// removing the annotation resumes the reconciliation
+	paused.Annotations = map[string]string{hints.OrchestrationsHintsAnnotation: `{"no_transient_settings":false}`}
+	require.NoError(t, r.Client.Update(context.Background(), &paused))
+	_, err = r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	resumed := get()
+	require.Equal(t, int64(2), resumed.Status.ObservedGeneration)
+	require.Equal(t, corev1.ConditionFalse, conditionStatus(resumed))
+}
+
+func TestReconcileElasticsearch_Reconcile_pausedDeletion(t *testing.T) {
+	now := metav1.Now()
+	es := newBuilder("testES", "test").
+		WithAnnotations(map[string]string{common.ManagedAnnotation: "false"}).
+		Build()
+	es.DeletionTimestamp = &now
+	es.Finalizers = []string{"finalizer.elasticsearch.k8s.elastic.co/secure-settings-secret"}
+	r := newTestReconciler(es)
+	r.expectations = expectations.NewClustersExpectations(r.Client)
+	r.esObservers = observer.NewManager(10*time.Second, nil)
+	r.dynamicWatches = watches.NewDynamicWatches()
+	request := reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(es)}
+
+	// the legacy finalizer is removed, which lets the cluster be deleted
+	_, err := r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	var current esv1.Elasticsearch
+	require.True(t, apierrors.IsNotFound(r.Client.Get(context.Background(), request.NamespacedName, &current)))
+}
+
+func TestReconcileElasticsearch_Reconcile_dryRunDeletion(t *testing.T) {
+	now := metav1.Now()
+	es := newBuilder("testES", "test").
+		WithAnnotations(map[string]string{reconciler.DryRunAnnotation: "true"}).
+		Build()
+	es.DeletionTimestamp = &now
+	es.Finalizers = []string{"finalizer.elasticsearch.k8s.elastic.co/secure-settings-secret"}
+	r := newTestReconciler(es)
+	r.expectations = expectations.NewClustersExpectations(r.Client)
+	r.esObservers = observer.NewManager(10*time.Second, nil)
+	r.dynamicWatches = watches.NewDynamicWatches()
+	request := reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(es)}
+	trustedCAWatch := settings.UserProvidedTrustedCAWatchName(request.NamespacedName)
+	require.NoError(t, r.dynamicWatches.Secrets.AddHandler(watches.NamedWatch{
+		Name:    trustedCAWatch,
+		Watched: []types.NamespacedName{{Namespace: es.Namespace, Name: "trusted-ca"}},
+		Watcher: request.NamespacedName,
+	}))
+
+	// the legacy finalizer is removed, which lets the cluster be deleted, and the watches of the cluster are removed
+	_, err := r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	var current esv1.Elasticsearch
+	require.True(t, apierrors.IsNotFound(r.Client.Get(context.Background(), request.NamespacedName, &current)))
+	require.NotContains(t, r.dynamicWatches.Secrets.Registrations(), trustedCAWatch)
+}
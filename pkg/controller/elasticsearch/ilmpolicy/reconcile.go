@@ -0,0 +1,150 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ilmpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"go.elastic.co/apm/v2"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// Reconcile creates the index lifecycle policies declared in the Elasticsearch specification through the
+// Elasticsearch API, and updates the ones whose definition drifted from the specification. Policies which are not
+// declared in the specification are left untouched. Nothing is done if the version of Elasticsearch does not support
+// index lifecycle management.
+func Reconcile(ctx context.Context, esClient esclient.ILMPolicyClient, es esv1.Elasticsearch) error {
+	if len(es.Spec.IndexLifecyclePolicies) == 0 {
+		// nothing to do, skip
+		return nil
+	}
+
+	log := ulog.FromContext(ctx)
+	if !esClient.IsILMSupported() {
+		log.V(1).Info("Index lifecycle management is not supported, skipping index lifecycle policies",
+			"namespace", es.Namespace, "es_name", es.Name, "version", es.Spec.Version)
+		return nil
+	}
+
+	span, ctx := apm.StartSpan(ctx, "reconcile_ilm_policies", tracing.SpanTypeApp)
+	defer span.End()
+
+	current, err := esClient.GetILMPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range es.Spec.IndexLifecyclePolicies {
+		expected := expectedPolicy(policy)
+		if existing, exists := current[policy.Name]; exists && equal(existing, expected) {
+			continue
+		}
+		log.Info("Updating index lifecycle policy", "namespace", es.Namespace, "es_name", es.Name, "policy", policy.Name)
+		if err := esClient.UpdateILMPolicy(ctx, policy.Name, expected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expectedPolicy returns the body of the request to create the given index lifecycle policy.
+func expectedPolicy(policy esv1.IndexLifecyclePolicy) esclient.ILMPolicy {
+	if policy.Policy == nil || policy.Policy.Data == nil {
+		return esclient.ILMPolicy{Policy: map[string]interface{}{}}
+	}
+	return esclient.ILMPolicy{Policy: policy.Policy.Data}
+}
+
+// equal returns true if the stored policy matches the expected one. Elasticsearch stores policies with default values
+// which depend on its version, such as the minimum age of the phases or additional settings of some actions. Both
+// policies must therefore declare the same phases with the same actions, but the settings of the phases and actions
+// only need to be a subset of the stored ones.
+func equal(existing, expected esclient.ILMPolicy) bool {
+	normalizedExisting, err := normalize(existing.Policy)
+	if err != nil {
+		return false
+	}
+	normalizedExpected, err := normalize(expected.Policy)
+	if err != nil {
+		return false
+	}
+	existingPhases, _ := normalizedExisting["phases"].(map[string]interface{})
+	expectedPhases, _ := normalizedExpected["phases"].(map[string]interface{})
+	if !sameKeys(existingPhases, expectedPhases) {
+		return false
+	}
+	for name, expectedPhase := range expectedPhases {
+		existingActions, _ := asMap(existingPhases[name])["actions"].(map[string]interface{})
+		expectedActions, _ := asMap(expectedPhase)["actions"].(map[string]interface{})
+		if !sameKeys(existingActions, expectedActions) {
+			return false
+		}
+	}
+	return isSubset(normalizedExpected, normalizedExisting)
+}
+
+func normalize(policy map[string]interface{}) (map[string]interface{}, error) {
+	bytes, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	normalized := map[string]interface{}{}
+	err = json.Unmarshal(bytes, &normalized)
+	return normalized, err
+}
+
+func asMap(value interface{}) map[string]interface{} {
+	m, _ := value.(map[string]interface{})
+	return m
+}
+
+func sameKeys(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if _, exists := b[k]; !exists {
+			return false
+		}
+	}
+	return true
+}
+
+// isSubset returns true if all the keys of the expected maps are set to the same values in the existing ones.
+func isSubset(expected, existing interface{}) bool {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		existingValue, isMap := existing.(map[string]interface{})
+		if !isMap {
+			return false
+		}
+		for k, v := range expectedValue {
+			existingV, exists := existingValue[k]
+			if !exists || !isSubset(v, existingV) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		existingValue, isSlice := existing.([]interface{})
+		if !isSlice || len(existingValue) != len(expectedValue) {
+			return false
+		}
+		for i := range expectedValue {
+			if !isSubset(expectedValue[i], existingValue[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(expected, existing)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ilmpolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+)
+
+type fakeESClient struct {
+	esclient.Client
+	unsupported bool
+	existing    esclient.ILMPolicies
+	getErr      error
+	getCalled   bool
+	updated     map[string]esclient.ILMPolicy
+	updateErr   error
+}
+
+func (f *fakeESClient) IsILMSupported() bool {
+	return !f.unsupported
+}
+
+func (f *fakeESClient) GetILMPolicies(_ context.Context) (esclient.ILMPolicies, error) {
+	f.getCalled = true
+	return f.existing, f.getErr
+}
+
+func (f *fakeESClient) UpdateILMPolicy(_ context.Context, name string, policy esclient.ILMPolicy) error {
+	if f.updated == nil {
+		f.updated = map[string]esclient.ILMPolicy{}
+	}
+	f.updated[name] = policy
+	return f.updateErr
+}
+
+func esWithPolicies(policies ...esv1.IndexLifecyclePolicy) esv1.Elasticsearch {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	es.Spec.IndexLifecyclePolicies = policies
+	return es
+}
+
+var (
+	retentionDefinition = map[string]interface{}{
+		"phases": map[string]interface{}{
+			"hot": map[string]interface{}{
+				"actions": map[string]interface{}{
+					"rollover": map[string]interface{}{"max_age": "1d", "max_docs": 1000000},
+				},
+			},
+			"delete": map[string]interface{}{
+				"min_age": "30d",
+				"actions": map[string]interface{}{
+					"delete": map[string]interface{}{},
+				},
+			},
+		},
+	}
+	retentionPolicy = esv1.IndexLifecyclePolicy{
+		Name:   "retention",
+		Policy: &commonv1.Config{Data: retentionDefinition},
+	}
+	// the policy as stored by Elasticsearch, with default values and numbers decoded as float64
+	storedRetentionPolicy = esclient.ILMPolicy{Policy: map[string]interface{}{
+		"phases": map[string]interface{}{
+			"hot": map[string]interface{}{
+				"min_age": "0ms",
+				"actions": map[string]interface{}{
+					"rollover": map[string]interface{}{"max_age": "1d", "max_docs": float64(1000000)},
+				},
+			},
+			"delete": map[string]interface{}{
+				"min_age": "30d",
+				"actions": map[string]interface{}{
+					"delete": map[string]interface{}{"delete_searchable_snapshot": true},
+				},
+			},
+		},
+	}}
+)
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name        string
+		es          esv1.Elasticsearch
+		client      *fakeESClient
+		wantErr     bool
+		wantGet     bool
+		wantUpdated map[string]esclient.ILMPolicy
+	}{
+		{
+			name:    "no policies: no API call",
+			es:      esWithPolicies(),
+			client:  &fakeESClient{},
+			wantGet: false,
+		},
+		{
+			name:    "ILM not supported: no API call",
+			es:      esWithPolicies(retentionPolicy),
+			client:  &fakeESClient{unsupported: true},
+			wantGet: false,
+		},
+		{
+			name:        "create a new policy",
+			es:          esWithPolicies(retentionPolicy),
+			client:      &fakeESClient{existing: esclient.ILMPolicies{}},
+			wantGet:     true,
+			wantUpdated: map[string]esclient.ILMPolicy{"retention": {Policy: retentionDefinition}},
+		},
+		{
+			name:    "policy already in sync, including default values added by Elasticsearch",
+			es:      esWithPolicies(retentionPolicy),
+			client:  &fakeESClient{existing: esclient.ILMPolicies{"retention": storedRetentionPolicy}},
+			wantGet: true,
+		},
+		{
+			name: "update a policy with a different setting",
+			es:   esWithPolicies(retentionPolicy),
+			client: &fakeESClient{existing: esclient.ILMPolicies{"retention": {Policy: map[string]interface{}{
+				"phases": map[string]interface{}{
+					"hot": map[string]interface{}{
+						"actions": map[string]interface{}{"rollover": map[string]interface{}{"max_age": "7d", "max_docs": float64(1000000)}},
+					},
+					"delete": map[string]interface{}{
+						"min_age": "30d",
+						"actions": map[string]interface{}{"delete": map[string]interface{}{}},
+					},
+				},
+			}}}},
+			wantGet:     true,
+			wantUpdated: map[string]esclient.ILMPolicy{"retention": {Policy: retentionDefinition}},
+		},
+		{
+			name: "update a policy with an additional phase",
+			es:   esWithPolicies(retentionPolicy),
+			client: &fakeESClient{existing: esclient.ILMPolicies{"retention": {Policy: map[string]interface{}{
+				"phases": map[string]interface{}{
+					"hot": map[string]interface{}{
+						"actions": map[string]interface{}{"rollover": map[string]interface{}{"max_age": "1d", "max_docs": float64(1000000)}},
+					},
+					"warm": map[string]interface{}{
+						"min_age": "7d",
+						"actions": map[string]interface{}{"forcemerge": map[string]interface{}{"max_num_segments": float64(1)}},
+					},
+					"delete": map[string]interface{}{
+						"min_age": "30d",
+						"actions": map[string]interface{}{"delete": map[string]interface{}{}},
+					},
+				},
+			}}}},
+			wantGet:     true,
+			wantUpdated: map[string]esclient.ILMPolicy{"retention": {Policy: retentionDefinition}},
+		},
+		{
+			name: "update a policy with an additional action",
+			es:   esWithPolicies(retentionPolicy),
+			client: &fakeESClient{existing: esclient.ILMPolicies{"retention": {Policy: map[string]interface{}{
+				"phases": map[string]interface{}{
+					"hot": map[string]interface{}{
+						"actions": map[string]interface{}{
+							"rollover":     map[string]interface{}{"max_age": "1d", "max_docs": float64(1000000)},
+							"set_priority": map[string]interface{}{"priority": float64(100)},
+						},
+					},
+					"delete": map[string]interface{}{
+						"min_age": "30d",
+						"actions": map[string]interface{}{"delete": map[string]interface{}{}},
+					},
+				},
+			}}}},
+			wantGet:     true,
+			wantUpdated: map[string]esclient.ILMPolicy{"retention": {Policy: retentionDefinition}},
+		},
+		{
+			name:    "error while retrieving policies",
+			es:      esWithPolicies(retentionPolicy),
+			client:  &fakeESClient{getErr: errors.New("boom")},
+			wantErr: true,
+			wantGet: true,
+		},
+		{
+			name:        "error while updating a policy",
+			es:          esWithPolicies(retentionPolicy),
+			client:      &fakeESClient{existing: esclient.ILMPolicies{}, updateErr: errors.New("boom")},
+			wantErr:     true,
+			wantGet:     true,
+			wantUpdated: map[string]esclient.ILMPolicy{"retention": {Policy: retentionDefinition}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Reconcile(context.Background(), tt.client, tt.es)
+			require.Equal(t, tt.wantErr, err != nil, err)
+			require.Equal(t, tt.wantGet, tt.client.getCalled)
+			require.Equal(t, tt.wantUpdated, tt.client.updated)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ingestpipeline
+
+import (
+	"context"
+	"strings"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	// ManagedIngestPipelinesAnnotationName holds the list of the ingest pipelines which have been created by the operator
+	ManagedIngestPipelinesAnnotationName = "elasticsearch.k8s.elastic.co/managed-ingest-pipelines"
+)
+
+// getPipelinesInAnnotation returns the set of the ingest pipelines which may have been created by the operator.
+// If there's no ingest pipeline the set is empty but not nil.
+func getPipelinesInAnnotation(es esv1.Elasticsearch) set.StringSet {
+	pipelines := set.Make()
+	serializedPipelines, ok := es.Annotations[ManagedIngestPipelinesAnnotationName]
+	if !ok || strings.TrimSpace(serializedPipelines) == "" {
+		return pipelines
+	}
+	for _, pipeline := range strings.Split(serializedPipelines, ",") {
+		pipelines.Add(pipeline)
+	}
+	return pipelines
+}
+
+// annotateWithManagedPipelines stores the given set of ingest pipelines in the annotation of the Elasticsearch resource,
+// or removes the annotation if the set is empty. The annotation is patched, not to conflict with other updates of the
+// Elasticsearch resource during the reconciliation, and is not patched if already up to date.
+func annotateWithManagedPipelines(ctx context.Context, c k8s.Client, es *esv1.Elasticsearch, pipelines set.StringSet) error {
+	current, exists := es.Annotations[ManagedIngestPipelinesAnnotationName]
+	if pipelines.Count() == 0 {
+		if !exists {
+			return nil
+		}
+		return k8s.PatchAnnotation(ctx, c, es, ManagedIngestPipelinesAnnotationName, nil)
+	}
+
+	expected := strings.Join(pipelines.AsSortedSlice(), ",")
+	if exists && current == expected {
+		return nil
+	}
+	return k8s.PatchAnnotation(ctx, c, es, ManagedIngestPipelinesAnnotationName, &expected)
+}
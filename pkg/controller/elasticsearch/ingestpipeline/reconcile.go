@@ -0,0 +1,114 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ingestpipeline
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"go.elastic.co/apm/v2"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// Reconcile creates the ingest pipelines declared in the Elasticsearch specification through the Elasticsearch API,
+// and updates the ones whose definition drifted from the specification. The pipelines created by the operator are
+// tracked in an annotation of the Elasticsearch resource, so that the ones removed from the specification can be
+// deleted from Elasticsearch if PruneIngestPipelines is enabled, without removing the pipelines created by users.
+// The following algorithm is used:
+//  1. Ensure that all the pipelines of the specification are tracked in the annotation before creating them
+//  2. Create or update the pipelines of the specification
+//  3. For each tracked pipeline which is not in the specification anymore, delete it from Elasticsearch if pruning is
+//     enabled, and stop tracking it
+//  4. Update the annotation with the remaining pipelines
+//
+// The given Elasticsearch resource is updated in place with the patched annotation.
+func Reconcile(ctx context.Context, c k8s.Client, esClient esclient.IngestPipelineClient, es *esv1.Elasticsearch) error {
+	managed := getPipelinesInAnnotation(*es)
+	if len(es.Spec.IngestPipelines) == 0 && managed.Count() == 0 {
+		// nothing to do, skip
+		return nil
+	}
+
+	span, ctx := apm.StartSpan(ctx, "reconcile_ingest_pipelines", tracing.SpanTypeApp)
+	defer span.End()
+	log := ulog.FromContext(ctx)
+
+	current, err := esClient.GetIngestPipelines(ctx)
+	if err != nil {
+		return err
+	}
+
+	inSpec := make(map[string]struct{}, len(es.Spec.IngestPipelines))
+	for _, pipeline := range es.Spec.IngestPipelines {
+		inSpec[pipeline.Name] = struct{}{}
+		managed.Add(pipeline.Name)
+	}
+	if err := annotateWithManagedPipelines(ctx, c, es, managed); err != nil {
+		return err
+	}
+
+	for _, pipeline := range es.Spec.IngestPipelines {
+		expected := expectedPipeline(pipeline)
+		if existing, exists := current[pipeline.Name]; exists && equal(existing, expected) {
+			continue
+		}
+		log.Info("Updating ingest pipeline", "namespace", es.Namespace, "es_name", es.Name, "pipeline", pipeline.Name)
+		if err := esClient.UpdateIngestPipeline(ctx, pipeline.Name, expected); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range managed.AsSortedSlice() {
+		if _, exists := inSpec[name]; exists {
+			continue
+		}
+		if _, exists := current[name]; exists && es.Spec.PruneIngestPipelines {
+			log.Info("Deleting ingest pipeline", "namespace", es.Namespace, "es_name", es.Name, "pipeline", name)
+			if err := esClient.DeleteIngestPipeline(ctx, name); err != nil && !esclient.IsNotFound(err) {
+				return err
+			}
+		}
+		managed.Del(name)
+	}
+	return annotateWithManagedPipelines(ctx, c, es, managed)
+}
+
+// expectedPipeline returns the body of the request to create the given ingest pipeline.
+func expectedPipeline(pipeline esv1.IngestPipeline) esclient.IngestPipeline {
+	if pipeline.Definition == nil || pipeline.Definition.Data == nil {
+		return esclient.IngestPipeline{}
+	}
+	return pipeline.Definition.Data
+}
+
+// equal returns true if both ingest pipelines have the same definition. Definitions are compared in their JSON
+// representation, to ignore differences in the Go types used for numbers.
+func equal(existing, expected esclient.IngestPipeline) bool {
+	normalizedExisting, err := normalize(existing)
+	if err != nil {
+		return false
+	}
+	normalizedExpected, err := normalize(expected)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(normalizedExisting, normalizedExpected)
+}
+
+func normalize(pipeline esclient.IngestPipeline) (map[string]interface{}, error) {
+	bytes, err := json.Marshal(pipeline)
+	if err != nil {
+		return nil, err
+	}
+	normalized := map[string]interface{}{}
+	err = json.Unmarshal(bytes, &normalized)
+	return normalized, err
+}
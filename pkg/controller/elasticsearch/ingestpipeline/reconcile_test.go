@@ -0,0 +1,217 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ingestpipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+type fakeESClient struct {
+	esclient.Client
+	existing  esclient.IngestPipelines
+	getErr    error
+	getCalled bool
+	updated   map[string]esclient.IngestPipeline
+	updateErr error
+	deleted   []string
+	deleteErr error
+}
+
+func (f *fakeESClient) GetIngestPipelines(_ context.Context) (esclient.IngestPipelines, error) {
+	f.getCalled = true
+	return f.existing, f.getErr
+}
+
+func (f *fakeESClient) UpdateIngestPipeline(_ context.Context, name string, pipeline esclient.IngestPipeline) error {
+	if f.updated == nil {
+		f.updated = map[string]esclient.IngestPipeline{}
+	}
+	f.updated[name] = pipeline
+	return f.updateErr
+}
+
+func (f *fakeESClient) DeleteIngestPipeline(_ context.Context, name string) error {
+	f.deleted = append(f.deleted, name)
+	return f.deleteErr
+}
+
+func esWithPipelines(managed string, prune bool, pipelines ...esv1.IngestPipeline) esv1.Elasticsearch {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	if managed != "" {
+		es.Annotations = map[string]string{ManagedIngestPipelinesAnnotationName: managed}
+	}
+	es.Spec.IngestPipelines = pipelines
+	es.Spec.PruneIngestPipelines = prune
+	return es
+}
+
+var (
+	timestampDefinition = map[string]interface{}{
+		"description": "Add the ingest timestamp",
+		"processors": []interface{}{
+			map[string]interface{}{"set": map[string]interface{}{"field": "ingested_at", "value": "{{_ingest.timestamp}}"}},
+		},
+		"version": 1,
+	}
+	timestampPipeline = esv1.IngestPipeline{
+		Name:       "add-timestamp",
+		Definition: &commonv1.Config{Data: timestampDefinition},
+	}
+	// the pipeline definition as returned by Elasticsearch or read from the API server, with numbers decoded as float64
+	existingTimestampPipeline = esclient.IngestPipeline{
+		"description": "Add the ingest timestamp",
+		"processors": []interface{}{
+			map[string]interface{}{"set": map[string]interface{}{"field": "ingested_at", "value": "{{_ingest.timestamp}}"}},
+		},
+		"version": float64(1),
+	}
+	userPipeline = esclient.IngestPipeline{"processors": []interface{}{}}
+)
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name           string
+		es             esv1.Elasticsearch
+		esClient       *fakeESClient
+		wantGetCalled  bool
+		wantUpdated    map[string]esclient.IngestPipeline
+		wantDeleted    []string
+		wantAnnotation string
+		wantErr        bool
+	}{
+		{
+			name:          "no pipeline in the spec nor in the annotation: nothing to do",
+			es:            esWithPipelines("", true),
+			esClient:      &fakeESClient{},
+			wantGetCalled: false,
+		},
+		{
+			name:           "create a new pipeline",
+			es:             esWithPipelines("", false, timestampPipeline),
+			esClient:       &fakeESClient{existing: esclient.IngestPipelines{}},
+			wantGetCalled:  true,
+			wantUpdated:    map[string]esclient.IngestPipeline{"add-timestamp": existingTimestampPipeline},
+			wantAnnotation: "add-timestamp",
+		},
+		{
+			name:           "pipeline already created with the same definition: nothing to update",
+			es:             esWithPipelines("add-timestamp", false, timestampPipeline),
+			esClient:       &fakeESClient{existing: esclient.IngestPipelines{"add-timestamp": existingTimestampPipeline}},
+			wantGetCalled:  true,
+			wantAnnotation: "add-timestamp",
+		},
+		{
+			name: "pipeline definition drifted: update it",
+			es:   esWithPipelines("add-timestamp", false, timestampPipeline),
+			esClient: &fakeESClient{existing: esclient.IngestPipelines{
+				"add-timestamp": {"description": "Modified through the API", "processors": []interface{}{}},
+			}},
+			wantGetCalled:  true,
+			wantUpdated:    map[string]esclient.IngestPipeline{"add-timestamp": existingTimestampPipeline},
+			wantAnnotation: "add-timestamp",
+		},
+		{
+			name: "pipeline removed from the spec without pruning: retain it and stop tracking it",
+			es:   esWithPipelines("add-timestamp,removed", false, timestampPipeline),
+			esClient: &fakeESClient{existing: esclient.IngestPipelines{
+				"add-timestamp": existingTimestampPipeline,
+				"removed":       userPipeline,
+			}},
+			wantGetCalled:  true,
+			wantAnnotation: "add-timestamp",
+		},
+		{
+			name: "pipeline removed from the spec with pruning: delete it",
+			es:   esWithPipelines("add-timestamp,removed", true, timestampPipeline),
+			esClient: &fakeESClient{existing: esclient.IngestPipelines{
+				"add-timestamp": existingTimestampPipeline,
+				"removed":       userPipeline,
+			}},
+			wantGetCalled:  true,
+			wantDeleted:    []string{"removed"},
+			wantAnnotation: "add-timestamp",
+		},
+		{
+			name: "all pipelines removed from the spec with pruning: delete them and remove the annotation",
+			es:   esWithPipelines("add-timestamp", true),
+			esClient: &fakeESClient{existing: esclient.IngestPipelines{
+				"add-timestamp": existingTimestampPipeline,
+			}},
+			wantGetCalled: true,
+			wantDeleted:   []string{"add-timestamp"},
+		},
+		{
+			name:          "tracked pipeline already deleted from Elasticsearch: stop tracking it",
+			es:            esWithPipelines("add-timestamp", true),
+			esClient:      &fakeESClient{existing: esclient.IngestPipelines{}},
+			wantGetCalled: true,
+		},
+		{
+			name: "pipelines not created by the operator are never deleted",
+			es:   esWithPipelines("add-timestamp", true, timestampPipeline),
+			esClient: &fakeESClient{existing: esclient.IngestPipelines{
+				"add-timestamp": existingTimestampPipeline,
+				"user-pipeline": userPipeline,
+			}},
+			wantGetCalled:  true,
+			wantAnnotation: "add-timestamp",
+		},
+		{
+			name:          "error while retrieving the pipelines",
+			es:            esWithPipelines("", false, timestampPipeline),
+			esClient:      &fakeESClient{getErr: errors.New("boom")},
+			wantGetCalled: true,
+			wantErr:       true,
+		},
+		{
+			name:           "error while creating a pipeline: the pipeline is tracked anyway",
+			es:             esWithPipelines("", false, timestampPipeline),
+			esClient:       &fakeESClient{existing: esclient.IngestPipelines{}, updateErr: errors.New("boom")},
+			wantGetCalled:  true,
+			wantUpdated:    map[string]esclient.IngestPipeline{"add-timestamp": existingTimestampPipeline},
+			wantAnnotation: "add-timestamp",
+			wantErr:        true,
+		},
+		{
+			name:           "error while deleting a pipeline: keep tracking it",
+			es:             esWithPipelines("removed", true),
+			esClient:       &fakeESClient{existing: esclient.IngestPipelines{"removed": userPipeline}, deleteErr: errors.New("boom")},
+			wantGetCalled:  true,
+			wantDeleted:    []string{"removed"},
+			wantAnnotation: "removed",
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := k8s.NewFakeClient(tt.es.DeepCopy())
+			var es esv1.Elasticsearch
+			require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&tt.es), &es))
+			err := Reconcile(context.Background(), k8sClient, tt.esClient, &es)
+			require.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+			require.Equal(t, tt.wantGetCalled, tt.esClient.getCalled)
+			require.Equal(t, tt.wantUpdated, tt.esClient.updated)
+			require.Equal(t, tt.wantDeleted, tt.esClient.deleted)
+
+			require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&tt.es), &es))
+			require.Equal(t, tt.wantAnnotation, es.Annotations[ManagedIngestPipelinesAnnotationName])
+		})
+	}
+}
+
+func Test_equal(t *testing.T) {
+	require.True(t, equal(existingTimestampPipeline, timestampDefinition))
+	require.False(t, equal(existingTimestampPipeline, esclient.IngestPipeline{"processors": []interface{}{}}))
+}
@@ -0,0 +1,69 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package initcontainer
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+const (
+	// DataPermissionsContainerName is the name of the init container fixing the ownership and permissions of the data
+	// volume.
+	DataPermissionsContainerName = "elastic-internal-init-data-permissions"
+
+	dataOwnerUIDEnvVar = "DATA_OWNER_UID"
+	dataOwnerGIDEnvVar = "DATA_OWNER_GID"
+)
+
+// dataPermissionsScript changes the ownership and permissions of the data volume, unless its root directory is already
+// owned and writable by the expected user and group.
+var dataPermissionsScript = `#!/usr/bin/env bash
+set -eu
+
+data_dir=` + esvolume.ElasticsearchDataMountPath + `
+owner="${` + dataOwnerUIDEnvVar + `}:${` + dataOwnerGIDEnvVar + `}"
+
+if [[ -n "$(find "${data_dir}" -maxdepth 0 -user "${` + dataOwnerUIDEnvVar + `}" -group "${` + dataOwnerGIDEnvVar + `}" -perm -u=rwx,g=rwx)" ]]; then
+  echo "${data_dir} is already owned by ${owner}, nothing to do"
+  exit 0
+fi
+
+echo "Changing the ownership of ${data_dir} to ${owner}"
+chown -R "${owner}" "${data_dir}"
+chmod -R u+rwX,g+rwX "${data_dir}"
+`
+
+// NewDataPermissionsInitContainer creates an init container giving the ownership of the data volume to the given user
+// and group. It runs as root, with only the capabilities required to change the ownership and permissions of files
+// owned by other users. Volume mounts are inherited from the Elasticsearch container.
+func NewDataPermissionsInitContainer(uid, gid int64) corev1.Container {
+	return corev1.Container{
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            DataPermissionsContainerName,
+		Env: []corev1.EnvVar{
+			{Name: dataOwnerUIDEnvVar, Value: strconv.FormatInt(uid, 10)},
+			{Name: dataOwnerGIDEnvVar, Value: strconv.FormatInt(gid, 10)},
+		},
+		Command:   []string{"bash", "-c", dataPermissionsScript},
+		Resources: defaultResources,
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:                ptr.To[int64](0),
+			RunAsGroup:               ptr.To[int64](0),
+			RunAsNonRoot:             ptr.To[bool](false),
+			Privileged:               ptr.To[bool](false),
+			AllowPrivilegeEscalation: ptr.To[bool](false),
+			ReadOnlyRootFilesystem:   ptr.To[bool](true),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+				Add:  []corev1.Capability{"CHOWN", "DAC_OVERRIDE", "FOWNER"},
+			},
+		},
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package initcontainer
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+func TestNewDataPermissionsInitContainer(t *testing.T) {
+	c := NewDataPermissionsInitContainer(1000, 0)
+	require.Equal(t, DataPermissionsContainerName, c.Name)
+	require.Equal(t, []string{"bash", "-c", dataPermissionsScript}, c.Command)
+	require.Equal(t, []corev1.EnvVar{
+		{Name: "DATA_OWNER_UID", Value: "1000"},
+		{Name: "DATA_OWNER_GID", Value: "0"},
+	}, c.Env)
+	require.Contains(t, c.Command[2], "chown -R \"${owner}\" \"${data_dir}\"")
+	// runs as root, with the capabilities required to change the ownership of the files only
+	require.Equal(t, ptr.To[int64](0), c.SecurityContext.RunAsUser)
+	require.Equal(t, ptr.To[bool](false), c.SecurityContext.RunAsNonRoot)
+	require.Equal(t, ptr.To[bool](false), c.SecurityContext.Privileged)
+	require.Equal(t, []corev1.Capability{"ALL"}, c.SecurityContext.Capabilities.Drop)
+	require.Equal(t, []corev1.Capability{"CHOWN", "DAC_OVERRIDE", "FOWNER"}, c.SecurityContext.Capabilities.Add)
+}
+
+func Test_dataPermissionsScript(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+	dataDir := t.TempDir()
+	script := strings.ReplaceAll(dataPermissionsScript, esvolume.ElasticsearchDataMountPath, dataDir)
+	run := func() string {
+		cmd := exec.Command("bash", "-c", script)
+		cmd.Env = append(os.Environ(),
+			"DATA_OWNER_UID="+strconv.Itoa(os.Getuid()),
+			"DATA_OWNER_GID="+strconv.Itoa(os.Getgid()),
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return string(out)
+	}
+
+	// the data directory is not writable by the group: permissions are fixed
+	require.NoError(t, os.Chmod(dataDir, 0o700))
+	require.Contains(t, run(), "Changing the ownership")
+	info, err := os.Stat(dataDir)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o770), info.Mode().Perm()&0o770)
+
+	// permissions are already correct: no-op
+	require.Contains(t, run(), "nothing to do")
+}
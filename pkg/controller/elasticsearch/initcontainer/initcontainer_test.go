@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 )
@@ -16,6 +17,8 @@ import (
 func TestNewInitContainers(t *testing.T) {
 	type args struct {
 		keystoreResources *keystore.Resources
+		withTruststore    bool
+		offlinePlugins    *esv1.OfflinePlugins
 	}
 	tests := []struct {
 		name                       string
@@ -36,10 +39,24 @@ func TestNewInitContainers(t *testing.T) {
 			},
 			expectedNumberOfContainers: 2,
 		},
+		{
+			name: "with truststore",
+			args: args{
+				withTruststore: true,
+			},
+			expectedNumberOfContainers: 3,
+		},
+		{
+			name: "with offline plugins",
+			args: args{
+				offlinePlugins: &esv1.OfflinePlugins{VolumeName: "plugins", Archives: []string{"analysis-icu.zip"}},
+			},
+			expectedNumberOfContainers: 3,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			containers, err := NewInitContainers(volume.SecretVolume{}, tt.args.keystoreResources, []string{})
+			containers, err := NewInitContainers(volume.SecretVolume{}, tt.args.keystoreResources, []string{}, tt.args.withTruststore, tt.args.offlinePlugins)
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedNumberOfContainers, len(containers))
 		})
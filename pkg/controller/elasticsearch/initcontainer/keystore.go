@@ -32,4 +32,8 @@ var KeystoreParams = keystore.InitContainerParameters{
 			corev1.ResourceCPU:    resource.MustParse("500m"),
 		},
 	},
+	// the password is read from the standard input, twice for confirmation on creation, through here-strings which are
+	// not printed when tracing the commands of the script
+	PasswordProtectedKeystoreCreateCommand: KeystoreBinPath + ` create --password <<< "${KEYSTORE_PASSWORD}"$'\n'"${KEYSTORE_PASSWORD}"`,
+	PasswordProtectedKeystoreAddCommand:    KeystoreBinPath + ` add-file "$key" "$filename" <<< "${KEYSTORE_PASSWORD}"`,
 }
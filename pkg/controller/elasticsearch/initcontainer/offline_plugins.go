@@ -0,0 +1,78 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package initcontainer
+
+import (
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+const (
+	// OfflinePluginsContainerName is the name of the init container installing plugins from archives stored in a volume.
+	OfflinePluginsContainerName = "elastic-internal-init-offline-plugins"
+
+	pluginBinPath = "/usr/share/elasticsearch/bin/elasticsearch-plugin"
+	// offlinePluginsMountPath is where the directory holding the plugin archives is mounted in the init container.
+	offlinePluginsMountPath = "/mnt/elastic-internal/offline-plugins"
+	// installedPluginsFile lists the plugin archives already installed in the shared volumes, to skip them if the
+	// init container runs again in the same Pod.
+	installedPluginsFile = esvolume.ConfigVolumeMountPath + "/.elastic-internal-installed-plugins"
+)
+
+// renderOfflinePluginsScript renders the script installing the given plugin archives with the offline install syntax
+// of elasticsearch-plugin, skipping the ones already installed.
+func renderOfflinePluginsScript(archives []string) string {
+	quoted := make([]string, 0, len(archives))
+	for _, archive := range archives {
+		quoted = append(quoted, shellQuote("file://"+path.Join(offlinePluginsMountPath, archive)))
+	}
+	return `#!/usr/bin/env bash
+set -eu
+
+installed=` + installedPluginsFile + `
+touch "${installed}"
+
+for archive in ` + strings.Join(quoted, " ") + `; do
+  if grep -Fxq "${archive}" "${installed}"; then
+    echo "Plugin ${archive} is already installed"
+    continue
+  fi
+  echo "Installing plugin ${archive}"
+  ` + pluginBinPath + ` install --batch "${archive}"
+  echo "${archive}" >> "${installed}"
+done
+`
+}
+
+// shellQuote quotes the given string for bash, as a single word without any expansion.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// NewOfflinePluginsInitContainer creates an init container installing plugins from the archives stored in the given
+// volume. The plugins are installed in the plugins/ directory of the shared volumes populated by the prepare-fs init
+// container, and used by the Elasticsearch container. Other volume mounts are inherited from the Elasticsearch
+// container.
+func NewOfflinePluginsInitContainer(offlinePlugins esv1.OfflinePlugins) corev1.Container {
+	return corev1.Container{
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            OfflinePluginsContainerName,
+		Command:         []string{"bash", "-c", renderOfflinePluginsScript(offlinePlugins.Archives)},
+		VolumeMounts: append(
+			PluginVolumes.ContainerVolumeMounts(),
+			corev1.VolumeMount{
+				Name:      offlinePlugins.VolumeName,
+				ReadOnly:  true,
+				MountPath: offlinePluginsMountPath,
+				SubPath:   offlinePlugins.Path,
+			},
+		),
+	}
+}
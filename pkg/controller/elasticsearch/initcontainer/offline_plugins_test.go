@@ -0,0 +1,75 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package initcontainer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func Test_renderOfflinePluginsScript(t *testing.T) {
+	script := renderOfflinePluginsScript([]string{"analysis-icu-8.13.0.zip", "custom/it's-a-plugin.zip"})
+	require.Contains(t, script,
+		`for archive in 'file:///mnt/elastic-internal/offline-plugins/analysis-icu-8.13.0.zip' `+
+			`'file:///mnt/elastic-internal/offline-plugins/custom/it'\''s-a-plugin.zip'; do`)
+	require.Contains(t, script, `/usr/share/elasticsearch/bin/elasticsearch-plugin install --batch "${archive}"`)
+}
+
+func Test_offlinePluginsScript(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+	dir := t.TempDir()
+	// fake elasticsearch-plugin tool recording the installed plugins
+	pluginBin := filepath.Join(dir, "elasticsearch-plugin")
+	calls := filepath.Join(dir, "calls")
+	require.NoError(t, os.WriteFile(pluginBin, []byte("#!/usr/bin/env bash\necho \"$@\" >> "+calls+"\n"), 0o700))
+	script := renderOfflinePluginsScript([]string{"a.zip", "b.zip"})
+	script = strings.ReplaceAll(script, pluginBinPath, pluginBin)
+	script = strings.ReplaceAll(script, installedPluginsFile, filepath.Join(dir, "installed"))
+
+	run := func() {
+		out, err := exec.Command("bash", "-c", script).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	expectedCalls := "install --batch file:///mnt/elastic-internal/offline-plugins/a.zip\n" +
+		"install --batch file:///mnt/elastic-internal/offline-plugins/b.zip\n"
+
+	run()
+	got, err := os.ReadFile(calls)
+	require.NoError(t, err)
+	require.Equal(t, expectedCalls, string(got))
+
+	// plugins already installed in the shared volumes are not installed again
+	run()
+	got, err = os.ReadFile(calls)
+	require.NoError(t, err)
+	require.Equal(t, expectedCalls, string(got))
+}
+
+func TestNewOfflinePluginsInitContainer(t *testing.T) {
+	c := NewOfflinePluginsInitContainer(esv1.OfflinePlugins{
+		VolumeName: "plugin-bundle",
+		Path:       "8.13.0",
+		Archives:   []string{"analysis-icu.zip"},
+	})
+	require.Equal(t, OfflinePluginsContainerName, c.Name)
+	require.Equal(t, []string{"bash", "-c", renderOfflinePluginsScript([]string{"analysis-icu.zip"})}, c.Command)
+	// plugins are installed in the volumes shared with the Elasticsearch container
+	require.Equal(t, []corev1.VolumeMount{
+		EsConfigSharedVolume.VolumeMount(),
+		EsPluginsSharedVolume.VolumeMount(),
+		EsBinSharedVolume.VolumeMount(),
+		{Name: "plugin-bundle", ReadOnly: true, MountPath: "/mnt/elastic-internal/offline-plugins", SubPath: "8.13.0"},
+	}, c.VolumeMounts)
+}
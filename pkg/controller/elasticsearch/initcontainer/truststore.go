@@ -0,0 +1,55 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package initcontainer
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+// TruststoreContainerName is the name of the init container generating the truststore of the JVM.
+const TruststoreContainerName = "elastic-internal-init-truststore"
+
+// truststoreScript generates a truststore holding the default CA certificates of the JDK bundled with Elasticsearch,
+// and each of the additional CA certificates, which keytool can only import one at a time.
+var truststoreScript = `#!/usr/bin/env bash
+set -eu
+
+keytool=/usr/share/elasticsearch/jdk/bin/keytool
+truststore=` + path.Join(esvolume.TruststoreVolumeMountPath, esvolume.TruststoreFile) + `
+certs_dir=` + path.Join(esvolume.TruststoreVolumeMountPath, "certs") + `
+
+rm -rf "${truststore}" "${certs_dir}"
+mkdir -p "${certs_dir}"
+
+echo "Importing the default CA certificates of the JVM"
+"${keytool}" -importkeystore -noprompt \
+  -srckeystore /usr/share/elasticsearch/jdk/lib/security/cacerts -srcstorepass changeit \
+  -destkeystore "${truststore}" -deststoretype PKCS12 -deststorepass changeit
+
+awk -v dir="${certs_dir}" '/-----BEGIN CERTIFICATE-----/ { n++ } n { print > (dir "/ca-" n ".crt") }' ` + path.Join(esvolume.TrustedCAVolumeMountPath, certificates.CAFileName) + `
+
+for cert in "${certs_dir}"/ca-*.crt; do
+  [[ -f "${cert}" ]] || continue
+  echo "Importing ${cert}"
+  "${keytool}" -importcert -noprompt -alias "eck-trusted-$(basename "${cert}" .crt)" -file "${cert}" \
+    -keystore "${truststore}" -storetype PKCS12 -storepass changeit
+done
+rm -rf "${certs_dir}"
+`
+
+// NewTruststoreInitContainer creates an init container generating the truststore of the JVM from the additional CA
+// certificates trusted by Elasticsearch. Volume mounts are inherited from the Elasticsearch container.
+func NewTruststoreInitContainer() corev1.Container {
+	return corev1.Container{
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            TruststoreContainerName,
+		Command:         []string{"bash", "-c", truststoreScript},
+	}
+}
@@ -5,13 +5,16 @@
 package nodespec
 
 import (
+	"fmt"
 	"path"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
@@ -39,6 +42,9 @@ var (
 			corev1.ResourceMemory: DefaultMemoryLimits,
 		},
 	}
+	// maxDefaultHeapSize is the maximum JVM heap size we set by default, to stay below the threshold above which
+	// the JVM stops using compressed ordinary object pointers.
+	maxDefaultHeapSize = resource.MustParse("31Gi")
 )
 
 // DefaultEnvVars are environment variables injected into Elasticsearch pods.
@@ -66,6 +72,25 @@ func DefaultEnvVars(httpCfg commonv1.HTTPConfig, headlessServiceName string) []c
 	)
 }
 
+// NodeAttributesEnvVars returns the environment variables holding the values of the node attributes of the
+// Elasticsearch nodes. They are read from the Pod annotations the operator copies the node labels to.
+func NodeAttributesEnvVars(es esv1.Elasticsearch) []corev1.EnvVar {
+	nodeAttributes := es.NodeAttributes()
+	envVars := make([]corev1.EnvVar, 0, len(nodeAttributes))
+	for name, nodeLabel := range nodeAttributes {
+		envVars = append(envVars, corev1.EnvVar{
+			Name: settings.NodeAttributeEnvVar(name),
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: fmt.Sprintf("metadata.annotations['%s']", nodeLabel)},
+			},
+		})
+	}
+	sort.Slice(envVars, func(i, j int) bool {
+		return envVars[i].Name < envVars[j].Name
+	})
+	return envVars
+}
+
 // DefaultAffinity returns the default affinity for pods in a cluster.
 func DefaultAffinity(esName string) *corev1.Affinity {
 	return &corev1.Affinity{
@@ -87,3 +112,25 @@ func DefaultAffinity(esName string) *corev1.Affinity {
 		},
 	}
 }
+
+// DefaultTopologySpreadConstraints returns the default topology spread constraints for the Pods of a NodeSet that
+// should be evenly spread across zones. The constraint is soft unless DoNotSchedule is requested, so that Pods are not
+// left Pending on clusters with fewer zones than Pods.
+func DefaultTopologySpreadConstraints(esName string, statefulSetName string, whenUnsatisfiable corev1.UnsatisfiableConstraintAction) []corev1.TopologySpreadConstraint {
+	if whenUnsatisfiable == "" {
+		whenUnsatisfiable = corev1.ScheduleAnyway
+	}
+	return []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelTopologyZone,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					label.ClusterNameLabelName:     esName,
+					label.StatefulSetNameLabelName: statefulSetName,
+				},
+			},
+		},
+	}
+}
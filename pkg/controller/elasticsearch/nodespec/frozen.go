@@ -0,0 +1,66 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+// sharedCacheSize returns the size of the shared cache of searchable snapshots for the nodes of the given NodeSet:
+// the size specified in the NodeSet for nodes with the data_frozen role, or a percentage of the data volume size, or of
+// the size limit of the ephemeral data volume, for dedicated frozen nodes. It returns nil if the size should be left to Elasticsearch.
+func sharedCacheSize(nodeSet esv1.NodeSet, ver version.Version) (*resource.Quantity, error) {
+	if ver.LT(settings.MinFrozenTierVersion) {
+		return nil, nil
+	}
+	var cfg esv1.ElasticsearchSettings
+	if err := esv1.UnpackConfig(nodeSet.Config, ver, &cfg); err != nil {
+		return nil, err
+	}
+	if !cfg.Node.HasRole(esv1.DataFrozenRole) {
+		return nil, nil
+	}
+	if nodeSet.SharedCacheSize != nil {
+		return nodeSet.SharedCacheSize, nil
+	}
+	if !cfg.Node.IsDedicatedFrozen() {
+		// the data volume is shared with the other tiers
+		return nil, nil
+	}
+
+	if nodeSet.HasEphemeralDataVolume() {
+		return percentOfDataVolume(nodeSet.EphemeralDataVolume.SizeLimit), nil
+	}
+
+	claims := defaults.AppendDefaultPVCs(nodeSet.VolumeClaimTemplates, nodeSet.PodTemplate.Spec, esvolume.DefaultVolumeClaimTemplates...)
+	for _, claim := range claims {
+		if claim.Name != esvolume.ElasticsearchDataVolumeName {
+			continue
+		}
+		storage, exists := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+		if !exists {
+			return nil, nil
+		}
+		return percentOfDataVolume(&storage), nil
+	}
+	// no persistent data volume, rely on the Elasticsearch default
+	return nil, nil
+}
+
+// percentOfDataVolume returns the default share of a data volume of the given size to use for the shared cache, or nil
+// if the size is unknown.
+func percentOfDataVolume(size *resource.Quantity) *resource.Quantity {
+	if size == nil || size.IsZero() {
+		return nil
+	}
+	return resource.NewQuantity(size.Value()*settings.DefaultSharedCacheSizePercent/100, resource.BinarySI)
+}
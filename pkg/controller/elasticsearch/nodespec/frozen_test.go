@@ -0,0 +1,136 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func Test_sharedCacheSize(t *testing.T) {
+	roles := func(roles ...string) *commonv1.Config {
+		return &commonv1.Config{Data: map[string]interface{}{"node.roles": roles}}
+	}
+	dataClaim := func(size string) []corev1.PersistentVolumeClaim {
+		return []corev1.PersistentVolumeClaim{{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		}}
+	}
+	quantity := func(q string) *resource.Quantity {
+		v := resource.MustParse(q)
+		return &v
+	}
+	tests := []struct {
+		name    string
+		version string
+		nodeSet esv1.NodeSet
+		want    *resource.Quantity
+	}{
+		{
+			name:    "dedicated frozen node: 90% of the data volume",
+			version: "8.12.0",
+			nodeSet: esv1.NodeSet{Config: roles("data_frozen"), VolumeClaimTemplates: dataClaim("100Gi")},
+			want:    resource.NewQuantity(96636764160, resource.BinarySI),
+		},
+		{
+			name:    "dedicated frozen node with other non-data roles: 90% of the data volume",
+			version: "8.12.0",
+			nodeSet: esv1.NodeSet{Config: roles("data_frozen", "remote_cluster_client"), VolumeClaimTemplates: dataClaim("10Gi")},
+			want:    resource.NewQuantity(9663676416, resource.BinarySI),
+		},
+		{
+			name:    "dedicated frozen node with the default data volume",
+			version: "8.12.0",
+			nodeSet: esv1.NodeSet{Config: roles("data_frozen")},
+			want:    resource.NewQuantity(966367641, resource.BinarySI),
+		},
+		{
+			name:    "dedicated frozen node without persistent data volume: Elasticsearch default",
+			version: "8.12.0",
+			nodeSet: esv1.NodeSet{
+				Config: roles("data_frozen"),
+				PodTemplate: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+					Name:         "elasticsearch-data",
+					VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				}}}},
+			},
+			want: nil,
+		},
+		{
+			name:    "dedicated frozen node with an ephemeral data volume: 90% of the size limit",
+			version: "8.12.0",
+			nodeSet: esv1.NodeSet{
+				Config:              roles("data_frozen"),
+				EphemeralDataVolume: &esv1.EphemeralDataVolume{SizeLimit: quantity("10Gi"), AcknowledgeDataLoss: true},
+			},
+			want: resource.NewQuantity(9663676416, resource.BinarySI),
+		},
+		{
+			name:    "dedicated frozen node with an unlimited ephemeral data volume: Elasticsearch default",
+			version: "8.12.0",
+			nodeSet: esv1.NodeSet{
+				Config:              roles("data_frozen"),
+				EphemeralDataVolume: &esv1.EphemeralDataVolume{AcknowledgeDataLoss: true},
+			},
+			want: nil,
+		},
+		{
+			name:    "frozen node with other data roles: Elasticsearch default",
+			version: "8.12.0",
+			nodeSet: esv1.NodeSet{Config: roles("data_cold", "data_frozen"), VolumeClaimTemplates: dataClaim("100Gi")},
+			want:    nil,
+		},
+		{
+			name:    "default roles: Elasticsearch default",
+			version: "8.12.0",
+			nodeSet: esv1.NodeSet{VolumeClaimTemplates: dataClaim("100Gi")},
+			want:    nil,
+		},
+		{
+			name:    "specified size on a frozen node",
+			version: "8.12.0",
+			nodeSet: esv1.NodeSet{Config: roles("data_cold", "data_frozen"), SharedCacheSize: quantity("20Gi")},
+			want:    quantity("20Gi"),
+		},
+		{
+			name:    "specified size on a node with default roles",
+			version: "8.12.0",
+			nodeSet: esv1.NodeSet{SharedCacheSize: quantity("20Gi")},
+			want:    quantity("20Gi"),
+		},
+		{
+			name:    "specified size on a node without the frozen role",
+			version: "8.12.0",
+			nodeSet: esv1.NodeSet{Config: roles("data_hot"), SharedCacheSize: quantity("20Gi")},
+			want:    nil,
+		},
+		{
+			name:    "frozen tier not supported before 7.12.0",
+			version: "7.11.2",
+			nodeSet: esv1.NodeSet{Config: roles("data_frozen"), SharedCacheSize: quantity("20Gi")},
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sharedCacheSize(tt.nodeSet, version.MustParse(tt.version))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"path"
 	"path/filepath"
+	"strconv"
 	"text/template"
 
 	v1 "k8s.io/api/core/v1"
@@ -24,7 +25,25 @@ func NewPreStopHook() *v1.LifecycleHandler {
 	}
 }
 
-const PreStopHookScriptConfigKey = "pre-stop-hook-script.sh"
+const (
+	PreStopHookScriptConfigKey = "pre-stop-hook-script.sh"
+
+	// EnvPreStopTerminationGracePeriodSeconds holds the termination grace period of the Pod, which bounds the duration of
+	// the pre-stop hook: the container is killed once it has elapsed, regardless of the hook still running.
+	EnvPreStopTerminationGracePeriodSeconds = "PRE_STOP_TERMINATION_GRACE_PERIOD_SECONDS"
+)
+
+// PreStopHookEnvVars returns the environment variables controlling the pre-stop hook for the given termination grace
+// period. The script defaults to DefaultTerminationGracePeriodSeconds: the variable is only set for a different value,
+// so that Pods using the default grace period are not rotated.
+func PreStopHookEnvVars(terminationGracePeriodSeconds int64) []v1.EnvVar {
+	if terminationGracePeriodSeconds == DefaultTerminationGracePeriodSeconds {
+		return nil
+	}
+	return []v1.EnvVar{
+		{Name: EnvPreStopTerminationGracePeriodSeconds, Value: strconv.FormatInt(terminationGracePeriodSeconds, 10)},
+	}
+}
 
 var preStopHookScriptTemplate = template.Must(template.New("pre-stop").Parse(`#!/usr/bin/env bash
 
@@ -42,6 +61,12 @@ set -uo pipefail
 # target the Pod IP before Elasticsearch stops.
 PRE_STOP_ADDITIONAL_WAIT_SECONDS=${PRE_STOP_ADDITIONAL_WAIT_SECONDS:=50}
 
+# PRE_STOP_TERMINATION_GRACE_PERIOD_SECONDS is the termination grace period of the Pod, set by the operator if it differs
+# from the default. The container is killed once it has elapsed: this script does not wait longer than that and leaves
+# some time to Elasticsearch to stop gracefully. 0 means the duration of the script is not bounded.
+termination_grace_period=${PRE_STOP_TERMINATION_GRACE_PERIOD_SECONDS:={{.DefaultTerminationGracePeriodSeconds}}}
+termination_margin_seconds=10
+
 # PRE_STOP_SHUTDOWN_TYPE controls the type of shutdown that will be communicated to Elasticsearch. This should not be
 # changed to anything but restart. Specifically setting remove can lead to extensive data migration that might exceed the
 # terminationGracePeriodSeconds and lead to an incomplete shutdown.
@@ -61,6 +86,11 @@ function duration() {
   echo $((end-start))
 }
 
+# returns successfully if the termination grace period is about to elapse
+function grace_period_elapsing() {
+  (( termination_grace_period > 0 )) && (( $(duration "$script_start") + termination_margin_seconds >= termination_grace_period ))
+}
+
 # use DNS errors as a proxy to abort this script early if there is no chance of successful completion
 # DNS errors are for example expected when the whole cluster including its service is being deleted
 # and the service URL can no longer be resolved even though we still have running Pods.
@@ -121,6 +151,12 @@ function delayed_exit() {
   local elapsed
   elapsed=$(duration "$script_start")
   local remaining=$((PRE_STOP_ADDITIONAL_WAIT_SECONDS - elapsed))
+  if (( termination_grace_period > 0 )); then
+    local remaining_grace_period=$((termination_grace_period - termination_margin_seconds - elapsed))
+    if (( remaining_grace_period < remaining )); then
+      remaining=$remaining_grace_period
+    fi
+  fi
   if (( remaining < 0 )); then
     exit ${1-0}
   fi
@@ -137,7 +173,7 @@ function supports_node_shutdown() {
   minor="${minor%.*}"
   patch="${version##*.}"
   # node shutdown is supported as of 7.15.2
-  if [ "$major" -lt 7 ]  || { [ "$major" -eq 7 ] && [ "$minor" -eq 15 ] && [ "$patch" -lt 2 ]; }; then
+  if [ "$major" -lt 7 ] || { [ "$major" -eq 7 ] && [ "$minor" -lt 15 ]; } || { [ "$major" -eq 7 ] && [ "$minor" -eq 15 ] && [ "$patch" -lt 2 ]; }; then
     return 1
   fi
   return 0
@@ -151,11 +187,6 @@ if [[ -f "{{.LabelsFile}}" ]]; then
   version=$(echo "${version}" | tr -d '"')
 fi
 
-# if ES version does not support node shutdown exit early
-if ! supports_node_shutdown "$version"; then
-  delayed_exit 
-fi
-
 # setup basic auth if credentials are available
 if [ -f "{{.PreStopUserPasswordPath}}" ]; then
   PROBE_PASSWORD=$(<{{.PreStopUserPasswordPath}})
@@ -168,6 +199,16 @@ fi
 
 ES_URL={{.ServiceURL}}
 
+# flush the shards so that the last indexed documents are persisted and recover quickly, on versions without node shutdown
+if ! supports_node_shutdown "$version"; then
+  log "flushing shards"
+  # best effort: a synced flush fails with a conflict if documents are indexed concurrently
+  if ! retry 3 request -X POST "$ES_URL/_flush/synced" $BASIC_AUTH; then
+    log "failed to flush shards"
+  fi
+  delayed_exit
+fi
+
 log "retrieving node ID"
 retry 10 request -X GET "$ES_URL/_cat/nodes?full_id=true&h=id,name" $BASIC_AUTH
 if [ "$?" -ne 0 ]; then
@@ -201,6 +242,10 @@ do
    if [ "$?" -eq 0 ] && grep -q -v 'IN_PROGRESS\|STALLED' "$resp_body"; then
       break
    fi
+   if grace_period_elapsing; then
+      log "termination grace period about to elapse, giving up waiting for node shutdown"
+      break
+   fi
    sleep 10 
 done
 
@@ -214,9 +259,10 @@ func RenderPreStopHookScript(svcURL string) (string, error) {
 		// edge case: protocol change (http/https) combined with external node shutdown might not work out well due to
 		// script propagation delays. But it is not a legitimate production use case as users are not expected to change
 		// protocol on production systems
-		"ServiceURL":       svcURL,
-		"LabelsFile":       filepath.Join(volume.DownwardAPIMountPath, volume.LabelsFile),
-		"VersionLabelName": label.VersionLabelName,
+		"ServiceURL":                           svcURL,
+		"LabelsFile":                           filepath.Join(volume.DownwardAPIMountPath, volume.LabelsFile),
+		"VersionLabelName":                     label.VersionLabelName,
+		"DefaultTerminationGracePeriodSeconds": strconv.FormatInt(DefaultTerminationGracePeriodSeconds, 10),
 	}
 	var script bytes.Buffer
 	err := preStopHookScriptTemplate.Execute(&script, vars)
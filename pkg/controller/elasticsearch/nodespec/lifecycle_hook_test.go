@@ -0,0 +1,48 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPreStopHookEnvVars(t *testing.T) {
+	tests := []struct {
+		name                          string
+		terminationGracePeriodSeconds int64
+		want                          []corev1.EnvVar
+	}{
+		{
+			name:                          "default grace period: no env var",
+			terminationGracePeriodSeconds: DefaultTerminationGracePeriodSeconds,
+			want:                          nil,
+		},
+		{
+			name:                          "custom grace period",
+			terminationGracePeriodSeconds: 600,
+			want: []corev1.EnvVar{
+				{Name: EnvPreStopTerminationGracePeriodSeconds, Value: "600"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, PreStopHookEnvVars(tt.terminationGracePeriodSeconds))
+		})
+	}
+}
+
+func TestRenderPreStopHookScript(t *testing.T) {
+	script, err := RenderPreStopHookScript("https://es-es-internal-http.ns.svc:9200")
+	require.NoError(t, err)
+	require.Contains(t, script, "ES_URL=https://es-es-internal-http.ns.svc:9200")
+	// the termination grace period defaults to the one set by the operator
+	require.Contains(t, script, "termination_grace_period=${"+EnvPreStopTerminationGracePeriodSeconds+":=180}")
+	// versions without node shutdown request a synced flush
+	require.Contains(t, script, `request -X POST "$ES_URL/_flush/synced"`)
+}
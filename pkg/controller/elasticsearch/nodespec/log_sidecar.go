@@ -0,0 +1,38 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/stackmon"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+// logSidecarConfigVolumeName is the name of the volume holding the configuration of the log shipping sidecar.
+const logSidecarConfigVolumeName = "log-sidecar-config"
+
+// withLogSidecar injects the log shipping sidecar in the Pod template, sharing the logs volume of the Elasticsearch
+// container. Values set by the user on a container with the same name in the Pod template take precedence, other
+// containers are left untouched.
+func withLogSidecar(builder *defaults.PodTemplateBuilder, es esv1.Elasticsearch) *defaults.PodTemplateBuilder {
+	if !es.HasLogSidecar() {
+		return builder
+	}
+	sidecar := corev1.Container{
+		Name:         esv1.LogSidecarContainerName,
+		Image:        es.Spec.LogSidecar.Image,
+		VolumeMounts: []corev1.VolumeMount{esvolume.DefaultLogsVolumeMount},
+	}
+	if secretName := es.Spec.LogSidecar.ConfigSecretName; secretName != "" {
+		configVolume := volume.NewSecretVolumeWithMountPath(secretName, logSidecarConfigVolumeName, esv1.LogSidecarConfigMountPath)
+		sidecar.VolumeMounts = append(sidecar.VolumeMounts, configVolume.VolumeMount())
+		builder = builder.WithVolumes(configVolume.Volume())
+	}
+	return builder.WithEnv(stackmon.FileLogStyleEnvVar()).WithContainers(sidecar)
+}
@@ -17,6 +17,7 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/annotation"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
@@ -25,7 +26,6 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/securitycontext"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/stackmon"
@@ -36,7 +36,10 @@ import (
 
 const (
 	defaultFsGroup                    = 1000
+	elasticsearchUID                  = 1000
 	log4j2FormatMsgNoLookupsParamName = "-Dlog4j2.formatMsgNoLookups"
+	jvmMinHeapSizeParamName           = "-Xms"
+	jvmMaxHeapSizeParamName           = "-Xmx"
 	// ConfigHashAnnotationName is an annotation used to store a hash of the Elasticsearch configuration.
 	configHashAnnotationName = "elasticsearch.k8s.elastic.co/config-hash"
 )
@@ -50,6 +53,11 @@ const (
 // podTemplate securityContext to an empty value.
 var minDefaultSecurityContextVersion = version.MinFor(8, 0, 0)
 
+// Starting 7.11.0, Elasticsearch automatically sizes the JVM heap based on the container memory limit.
+// Before that version we set a default heap size ourselves, to prevent the JVM default heap settings
+// from exceeding the container memory limit.
+var minAutomaticHeapSizingVersion = version.MinFor(7, 11, 0)
+
 // BuildPodTemplateSpec builds a new PodTemplateSpec for an Elasticsearch node.
 func BuildPodTemplateSpec(
 	ctx context.Context,
@@ -59,6 +67,7 @@ func BuildPodTemplateSpec(
 	cfg settings.CanonicalConfig,
 	keystoreResources *keystore.Resources,
 	setDefaultSecurityContext bool,
+	defaultHeapSize bool,
 	policyConfig PolicyConfig,
 ) (corev1.PodTemplateSpec, error) {
 	ver, err := version.Parse(es.Spec.Version)
@@ -66,8 +75,8 @@ func BuildPodTemplateSpec(
 		return corev1.PodTemplateSpec{}, err
 	}
 
-	downwardAPIVolume := volume.DownwardAPI{}.WithAnnotations(es.HasDownwardNodeLabels())
-	volumes, volumeMounts := buildVolumes(es.Name, ver, nodeSet, keystoreResources, downwardAPIVolume, policyConfig.AdditionalVolumes)
+	downwardAPIVolume := volume.DownwardAPI{}.WithAnnotations(len(es.NodeLabelsAsPodAnnotations()) > 0)
+	volumes, volumeMounts := buildVolumes(es.Name, ver, nodeSet, keystoreResources, downwardAPIVolume, realmsVolumes(es.Spec.Auth.Realms), policyConfig.AdditionalVolumes)
 
 	labels, err := buildLabels(es, cfg, nodeSet)
 	if err != nil {
@@ -80,7 +89,9 @@ func BuildPodTemplateSpec(
 	initContainers, err := initcontainer.NewInitContainers(
 		transportCertificatesVolume(esv1.StatefulSet(es.Name, nodeSet.Name)),
 		keystoreResources,
-		es.DownwardNodeLabels(),
+		es.NodeLabelsAsPodAnnotations(),
+		es.HasTrustedCertificateAuthorities(),
+		es.Spec.OfflinePlugins,
 	)
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err
@@ -93,6 +104,12 @@ func BuildPodTemplateSpec(
 			FSGroup: ptr.To[int64](defaultFsGroup),
 		})
 	}
+	builder = withVolumeOwnership(builder, nodeSet.VolumeOwnership)
+	if nodeSet.VolumeOwnership != nil && nodeSet.VolumeOwnership.FixDataVolumePermissions {
+		// run first, before the other init containers access the data volume
+		uid, gid := dataVolumeOwner(builder.PodTemplate)
+		initContainers = append([]corev1.Container{initcontainer.NewDataPermissionsInitContainer(uid, gid)}, initContainers...)
+	}
 
 	headlessServiceName := HeadlessServiceName(esv1.StatefulSet(es.Name, nodeSet.Name))
 
@@ -101,7 +118,41 @@ func BuildPodTemplateSpec(
 	if err := client.Get(context.Background(), types.NamespacedName{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}, esScripts); err != nil {
 		return corev1.PodTemplateSpec{}, err
 	}
-	annotations := buildAnnotations(es, cfg, keystoreResources, getScriptsConfigMapContent(esScripts), policyConfig.PolicyAnnotations)
+	// We retrieve the Secret that holds the log4j2 configuration to trigger a Pod restart if it is updated.
+	var log4j2Config []byte
+	if es.HasLog4j2Config() {
+		log4j2Volume := settings.Log4j2ConfigSecretVolume(es.Name)
+		volumes = append(volumes, log4j2Volume.Volume())
+		volumeMounts = append(volumeMounts, log4j2Volume.VolumeMount())
+
+		var log4j2Secret corev1.Secret
+		if err := client.Get(context.Background(), types.NamespacedName{Namespace: es.Namespace, Name: esv1.Log4j2ConfigSecret(es.Name)}, &log4j2Secret); err != nil {
+			return corev1.PodTemplateSpec{}, err
+		}
+		log4j2Config = log4j2Secret.Data[esv1.Log4j2ConfigFileName]
+	}
+	// The JVM options are stored in the configuration Secret of the StatefulSet, along with elasticsearch.yml.
+	jvmOptions := settings.RenderJVMOptions(nodeSet.JVMOptions)
+	if len(jvmOptions) > 0 {
+		jvmOptionsVolume := settings.JVMOptionsSecretVolume(esv1.StatefulSet(es.Name, nodeSet.Name))
+		volumes = append(volumes, jvmOptionsVolume.Volume())
+		volumeMounts = append(volumeMounts, jvmOptionsVolume.VolumeMount())
+	}
+	// We retrieve the Secret that holds the additional trusted CA certificates to trigger a Pod restart if it is updated.
+	var trustedCA []byte
+	if es.HasTrustedCertificateAuthorities() {
+		trustedCAVolume := settings.TrustedCASecretVolume(es.Name)
+		truststoreVolume := settings.TruststoreVolume()
+		volumes = append(volumes, trustedCAVolume.Volume(), truststoreVolume.Volume())
+		volumeMounts = append(volumeMounts, trustedCAVolume.VolumeMount(), truststoreVolume.VolumeMount())
+
+		var trustedCASecret corev1.Secret
+		if err := client.Get(context.Background(), types.NamespacedName{Namespace: es.Namespace, Name: esv1.TrustedCASecret(es.Name)}, &trustedCASecret); err != nil {
+			return corev1.PodTemplateSpec{}, err
+		}
+		trustedCA = trustedCASecret.Data[certificates.CAFileName]
+	}
+	annotations := buildAnnotations(es, cfg, keystoreResources, getScriptsConfigMapContent(esScripts), log4j2Config, jvmOptions, trustedCA, policyConfig.PolicyAnnotations)
 
 	// Attempt to detect if the default data directory is mounted in a volume.
 	// If not, it could be a bug, a misconfiguration, or a custom storage configuration that requires the user to
@@ -119,6 +170,11 @@ func BuildPodTemplateSpec(
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
 
+	unpackedCfg, err := cfg.Unpack(ver)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
 	// build the podTemplate until we have the effective resources configured
 	builder = builder.
 		WithLabels(labels).
@@ -127,9 +183,11 @@ func BuildPodTemplateSpec(
 		WithResources(DefaultResources).
 		WithTerminationGracePeriod(DefaultTerminationGracePeriodSeconds).
 		WithPorts(defaultContainerPorts).
-		WithReadinessProbe(*NewReadinessProbe()).
+		WithMergedReadinessProbe(*NewReadinessProbe()).
 		WithAffinity(DefaultAffinity(es.Name)).
 		WithEnv(DefaultEnvVars(es.Spec.HTTP, headlessServiceName)...).
+		WithEnv(NodeAttributesEnvVars(es)...).
+		WithEnv(keystorePasswordEnvVars(keystoreResources)...).
 		WithVolumes(volumes...).
 		WithVolumeMounts(volumeMounts...).
 		WithInitContainers(initContainers...).
@@ -138,24 +196,66 @@ func BuildPodTemplateSpec(
 		// set a default security context for both the Containers and the InitContainers
 		WithContainersSecurityContext(securitycontext.For(ver, enableReadOnlyRootFilesystem)).
 		WithPreStopHook(*NewPreStopHook())
+	// the pre-stop hook must complete within the effective termination grace period
+	builder = builder.WithEnv(PreStopHookEnvVars(*builder.PodTemplate.Spec.TerminationGracePeriodSeconds)...)
+	// the HTTP request of the readiness probe script must complete within the effective probe timeout
+	builder = builder.WithEnv(ReadinessProbeTimeoutEnvVars(builder.MainContainer().ReadinessProbe)...)
+
+	if nodeSet.SpreadAcrossZones {
+		builder = builder.WithTopologySpreadConstraints(DefaultTopologySpreadConstraints(es.Name, esv1.StatefulSet(es.Name, nodeSet.Name), nodeSet.ZoneSpreadWhenUnsatisfiable)...)
+	}
+
+	builder = withLogSidecar(builder, es)
 
 	builder, err = stackmon.WithMonitoring(ctx, client, builder, es)
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err
 	}
 
+	mlNativeMemoryPercent, err := cfg.MLNativeMemoryPercent()
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+	switch {
+	case settings.HasHeapSizeOption(nodeSet.JVMOptions):
+		// heap size specified by the user in the JVM options
+	case ver.GTE(minAutomaticHeapSizingVersion):
+		// heap size automatically computed by Elasticsearch, which already accounts for the machine learning jobs
+	case unpackedCfg.Node.HasRole(esv1.MLRole) && mlNativeMemoryPercent > 0 && mlNativeMemoryPercent < 100:
+		holdsData := unpackedCfg.Node.CanContainData()
+		setHeapSize(builder, func(memoryLimit int64) int64 {
+			return mlHeapSize(memoryLimit, mlNativeMemoryPercent, holdsData)
+		})
+	case defaultHeapSize:
+		setDefaultHeapSize(builder)
+	}
+
 	if ver.LT(version.From(7, 2, 0)) {
 		// mitigate CVE-2021-44228
 		enableLog4JFormatMsgNoLookups(builder)
 	}
 
+	if es.HasTrustedCertificateAuthorities() {
+		// options set by the user in ES_JAVA_OPTS still take precedence
+		prependJavaOpts(builder, settings.TruststoreJavaOpts()...)
+	}
+
 	return builder.PodTemplate, nil
 }
 
+// keystorePasswordEnvVars returns the environment variable the Docker entrypoint of Elasticsearch reads the keystore
+// password from, if the keystore is password protected.
+func keystorePasswordEnvVars(keystoreResources *keystore.Resources) []corev1.EnvVar {
+	if keystoreResources == nil || keystoreResources.Password == nil {
+		return nil
+	}
+	return []corev1.EnvVar{keystoreResources.Password.EnvVar}
+}
+
 func getDefaultContainerPorts(es esv1.Elasticsearch) []corev1.ContainerPort {
 	return []corev1.ContainerPort{
-		{Name: es.Spec.HTTP.Protocol(), ContainerPort: network.HTTPPort, Protocol: corev1.ProtocolTCP},
-		{Name: "transport", ContainerPort: network.TransportPort, Protocol: corev1.ProtocolTCP},
+		{Name: es.Spec.HTTP.Protocol(), ContainerPort: es.HTTPPort(), Protocol: corev1.ProtocolTCP},
+		{Name: "transport", ContainerPort: es.TransportPort(), Protocol: corev1.ProtocolTCP},
 	}
 }
 
@@ -198,6 +298,9 @@ func buildAnnotations(
 	cfg settings.CanonicalConfig,
 	keystoreResources *keystore.Resources,
 	scriptsContent string,
+	log4j2Config []byte,
+	jvmOptions []byte,
+	trustedCA []byte,
 	policyAnnotations map[string]string,
 ) map[string]string {
 	// start from our defaults
@@ -210,6 +313,12 @@ func buildAnnotations(
 	hash.WriteHashObject(configHash, cfg)
 	// hash of the scripts' content to rotate the pod if the scripts have changed
 	_, _ = configHash.Write([]byte(scriptsContent))
+	// hash of the log4j2 configuration to rotate the pod if it has changed
+	_, _ = configHash.Write(log4j2Config)
+	// hash of the JVM options to rotate the pod if they have changed
+	_, _ = configHash.Write(jvmOptions)
+	// hash of the additional trusted CA certificates to rotate the pod if they have changed
+	_, _ = configHash.Write(trustedCA)
 
 	if es.HasDownwardNodeLabels() {
 		// list of node labels expected on the pod to rotate the pod when the list is updated
@@ -219,6 +328,10 @@ func buildAnnotations(
 	if keystoreResources != nil {
 		// resource version of the secure settings secret to rotate the pod on secure settings change
 		_, _ = configHash.Write([]byte(keystoreResources.Version))
+		if keystoreResources.Password != nil {
+			// resource version of the keystore password secret to rotate the pod on password change
+			_, _ = configHash.Write([]byte(keystoreResources.Password.Version))
+		}
 	}
 
 	// set the annotation in place
@@ -234,7 +347,26 @@ func buildAnnotations(
 // in order to mitigate the Log4Shell vulnerability CVE-2021-44228, if it is not yet defined by the user, for
 // versions of Elasticsearch before 7.2.0.
 func enableLog4JFormatMsgNoLookups(builder *defaults.PodTemplateBuilder) {
-	log4j2Param := fmt.Sprintf("%s=true", log4j2FormatMsgNoLookupsParamName)
+	for _, esContainer := range builder.PodTemplate.Spec.Containers {
+		if esContainer.Name != esv1.ElasticsearchContainerName {
+			continue
+		}
+		for _, envVar := range esContainer.Env {
+			if envVar.Name == settings.EnvEsJavaOpts && strings.Contains(envVar.Value, log4j2FormatMsgNoLookupsParamName) {
+				return
+			}
+		}
+	}
+	prependJavaOpts(builder, fmt.Sprintf("%s=true", log4j2FormatMsgNoLookupsParamName))
+}
+
+// prependJavaOpts prepends the given JVM parameters to the environment variable `ES_JAVA_OPTS` of the Elasticsearch
+// container, so that the parameters set by the user take precedence.
+func prependJavaOpts(builder *defaults.PodTemplateBuilder, params ...string) {
+	if len(params) == 0 {
+		return
+	}
+	opts := strings.Join(params, " ")
 	for c, esContainer := range builder.PodTemplate.Spec.Containers {
 		if esContainer.Name != esv1.ElasticsearchContainerName {
 			continue
@@ -245,19 +377,93 @@ func enableLog4JFormatMsgNoLookups(builder *defaults.PodTemplateBuilder) {
 				continue
 			}
 			currentJvmOpts = envVar.Value
-			if !strings.Contains(currentJvmOpts, log4j2FormatMsgNoLookupsParamName) {
-				builder.PodTemplate.Spec.Containers[c].Env[e].Value = log4j2Param + " " + currentJvmOpts
-			}
+			builder.PodTemplate.Spec.Containers[c].Env[e].Value = opts + " " + currentJvmOpts
 		}
 		if currentJvmOpts == "" {
 			builder.PodTemplate.Spec.Containers[c].Env = append(
 				builder.PodTemplate.Spec.Containers[c].Env,
-				corev1.EnvVar{Name: settings.EnvEsJavaOpts, Value: log4j2Param},
+				corev1.EnvVar{Name: settings.EnvEsJavaOpts, Value: opts},
 			)
 		}
 	}
 }
 
+// setDefaultHeapSize appends the JVM parameters `-Xms` and `-Xmx` to the environment variable `ES_JAVA_OPTS`, set to half
+// of the memory limit of the Elasticsearch container capped to maxDefaultHeapSize, if no heap size is specified by the user
+// and a memory limit is set.
+func setDefaultHeapSize(builder *defaults.PodTemplateBuilder) {
+	setHeapSize(builder, func(memoryLimit int64) int64 {
+		return memoryLimit / 2
+	})
+}
+
+// hasHeapSizeEnv returns true if the heap size is set in the ES_JAVA_OPTS environment variable of the Elasticsearch
+// container of the given Pod template.
+func hasHeapSizeEnv(podTemplate corev1.PodTemplateSpec) bool {
+	for _, c := range podTemplate.Spec.Containers {
+		if c.Name != esv1.ElasticsearchContainerName {
+			continue
+		}
+		for _, envVar := range c.Env {
+			if envVar.Name == settings.EnvEsJavaOpts &&
+				(strings.Contains(envVar.Value, jvmMinHeapSizeParamName) || strings.Contains(envVar.Value, jvmMaxHeapSizeParamName)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mlHeapSize returns the JVM heap size of a machine learning node, leaving the given percentage of the memory limit
+// to the native processes of machine learning jobs. Dedicated machine learning nodes get half of the remaining memory,
+// the other half covering the memory used by the JVM outside the heap. Nodes that also hold data keep half of the
+// memory limit for the heap, as any other data node, at the expense of the filesystem cache: the heap only shrinks
+// if the native memory would not fit in the other half.
+func mlHeapSize(memoryLimit int64, nativeMemoryPercent int, holdsData bool) int64 {
+	available := memoryLimit * int64(100-nativeMemoryPercent) / 100
+	if holdsData {
+		return min(memoryLimit/2, available)
+	}
+	return available / 2
+}
+
+// setHeapSize appends the JVM parameters `-Xms` and `-Xmx` to the environment variable `ES_JAVA_OPTS`, set to the heap
+// size computed from the memory limit of the Elasticsearch container capped to maxDefaultHeapSize, if no heap size is
+// specified by the user and a memory limit is set.
+func setHeapSize(builder *defaults.PodTemplateBuilder, heapSizeFor func(memoryLimit int64) int64) {
+	for c, esContainer := range builder.PodTemplate.Spec.Containers {
+		if esContainer.Name != esv1.ElasticsearchContainerName {
+			continue
+		}
+		memoryLimit, exists := esContainer.Resources.Limits[corev1.ResourceMemory]
+		if !exists || memoryLimit.IsZero() {
+			// no memory limit, rely on the JVM defaults
+			return
+		}
+		heapSize := heapSizeFor(memoryLimit.Value())
+		if heapSize > maxDefaultHeapSize.Value() {
+			heapSize = maxDefaultHeapSize.Value()
+		}
+		heapSizeMi := heapSize / (1024 * 1024)
+		heapParams := fmt.Sprintf("%s%dm %s%dm", jvmMinHeapSizeParamName, heapSizeMi, jvmMaxHeapSizeParamName, heapSizeMi)
+		for e, envVar := range esContainer.Env {
+			if envVar.Name != settings.EnvEsJavaOpts {
+				continue
+			}
+			if strings.Contains(envVar.Value, jvmMinHeapSizeParamName) || strings.Contains(envVar.Value, jvmMaxHeapSizeParamName) {
+				// heap size specified by the user
+				return
+			}
+			builder.PodTemplate.Spec.Containers[c].Env[e].Value = strings.TrimSpace(envVar.Value + " " + heapParams)
+			return
+		}
+		builder.PodTemplate.Spec.Containers[c].Env = append(
+			builder.PodTemplate.Spec.Containers[c].Env,
+			corev1.EnvVar{Name: settings.EnvEsJavaOpts, Value: heapParams},
+		)
+	}
+}
+
 // Get contents of the script ConfigMap to generate config hash, excluding the suspended_pods.txt, as it may change over time.
 func getScriptsConfigMapContent(cm *corev1.ConfigMap) string {
 	var builder strings.Builder
@@ -275,3 +481,47 @@ func getScriptsConfigMapContent(cm *corev1.ConfigMap) string {
 	}
 	return builder.String()
 }
+
+// withVolumeOwnership sets the volume ownership settings of the node set in the Pod security context, overriding the
+// default ones and those of the Pod template.
+func withVolumeOwnership(builder *defaults.PodTemplateBuilder, ownership *esv1.VolumeOwnership) *defaults.PodTemplateBuilder {
+	if ownership == nil {
+		return builder
+	}
+	builder = builder.WithPodSecurityContext(corev1.PodSecurityContext{})
+	securityContext := builder.PodTemplate.Spec.SecurityContext
+	if ownership.FSGroup != nil {
+		securityContext.FSGroup = ptr.To[int64](*ownership.FSGroup)
+	}
+	if ownership.FSGroupChangePolicy != nil {
+		securityContext.FSGroupChangePolicy = ptr.To[corev1.PodFSGroupChangePolicy](*ownership.FSGroupChangePolicy)
+	}
+	if len(ownership.SupplementalGroups) > 0 {
+		securityContext.SupplementalGroups = append([]int64(nil), ownership.SupplementalGroups...)
+	}
+	return builder
+}
+
+// dataVolumeOwner returns the user and group expected to own the data volume: the user of the Elasticsearch process,
+// 1000 in the Elasticsearch image, and the FSGroup, or the group of the process, defaulting to the root group of which
+// the Elasticsearch user is a member.
+func dataVolumeOwner(podTemplate corev1.PodTemplateSpec) (int64, int64) {
+	uid, gid := int64(elasticsearchUID), int64(0)
+	if podSecurityContext := podTemplate.Spec.SecurityContext; podSecurityContext != nil {
+		switch {
+		case podSecurityContext.FSGroup != nil:
+			gid = *podSecurityContext.FSGroup
+		case podSecurityContext.RunAsGroup != nil:
+			gid = *podSecurityContext.RunAsGroup
+		}
+		if podSecurityContext.RunAsUser != nil {
+			uid = *podSecurityContext.RunAsUser
+		}
+	}
+	for _, c := range podTemplate.Spec.Containers {
+		if c.Name == esv1.ElasticsearchContainerName && c.SecurityContext != nil && c.SecurityContext.RunAsUser != nil {
+			uid = *c.SecurityContext.RunAsUser
+		}
+	}
+	return uid, gid
+}
@@ -8,12 +8,15 @@ import (
 	"context"
 	"path"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/go-test/deep"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
@@ -21,6 +24,7 @@ import (
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
 	commonannotation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/annotation"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
@@ -29,6 +33,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/stackmon"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
 	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
@@ -141,6 +146,7 @@ func TestBuildPodTemplateSpecWithDefaultSecurityContext(t *testing.T) {
 		version             version.Version
 		setDefaultFSGroup   bool
 		userSecurityContext *corev1.PodSecurityContext
+		volumeOwnership     *esv1.VolumeOwnership
 		wantSecurityContext *corev1.PodSecurityContext
 	}{
 		{
@@ -206,17 +212,62 @@ func TestBuildPodTemplateSpecWithDefaultSecurityContext(t *testing.T) {
 			userSecurityContext: &corev1.PodSecurityContext{},
 			wantSecurityContext: &corev1.PodSecurityContext{},
 		},
+		{
+			name:                "8.0+, setting on, fsGroup change policy",
+			version:             version.MustParse("8.0.0"),
+			setDefaultFSGroup:   true,
+			volumeOwnership:     &esv1.VolumeOwnership{FSGroupChangePolicy: ptr.To(corev1.FSGroupChangeOnRootMismatch)},
+			wantSecurityContext: &corev1.PodSecurityContext{FSGroup: ptr.To[int64](1000), FSGroupChangePolicy: ptr.To(corev1.FSGroupChangeOnRootMismatch)},
+		},
+		{
+			name:              "8.0+, setting on, volume ownership",
+			version:           version.MustParse("8.0.0"),
+			setDefaultFSGroup: true,
+			volumeOwnership: &esv1.VolumeOwnership{
+				FSGroup:             ptr.To[int64](2000),
+				FSGroupChangePolicy: ptr.To(corev1.FSGroupChangeOnRootMismatch),
+				SupplementalGroups:  []int64{3000, 4000},
+			},
+			wantSecurityContext: &corev1.PodSecurityContext{
+				FSGroup:             ptr.To[int64](2000),
+				FSGroupChangePolicy: ptr.To(corev1.FSGroupChangeOnRootMismatch),
+				SupplementalGroups:  []int64{3000, 4000},
+			},
+		},
+		{
+			name:                "8.0+, setting on, volume ownership overrides the user context",
+			version:             version.MustParse("8.0.0"),
+			setDefaultFSGroup:   true,
+			userSecurityContext: &corev1.PodSecurityContext{RunAsUser: ptr.To[int64](1000), FSGroup: ptr.To[int64](123)},
+			volumeOwnership:     &esv1.VolumeOwnership{FSGroup: ptr.To[int64](2000)},
+			wantSecurityContext: &corev1.PodSecurityContext{RunAsUser: ptr.To[int64](1000), FSGroup: ptr.To[int64](2000)},
+		},
+		{
+			name:                "pre-8.0, setting on, fsGroup change policy",
+			version:             version.MustParse("7.8.0"),
+			setDefaultFSGroup:   true,
+			volumeOwnership:     &esv1.VolumeOwnership{FSGroupChangePolicy: ptr.To(corev1.FSGroupChangeOnRootMismatch)},
+			wantSecurityContext: &corev1.PodSecurityContext{FSGroupChangePolicy: ptr.To(corev1.FSGroupChangeOnRootMismatch)},
+		},
+		{
+			name:                "8.0+, setting off, fsGroup",
+			version:             version.MustParse("8.0.0"),
+			setDefaultFSGroup:   false,
+			volumeOwnership:     &esv1.VolumeOwnership{FSGroup: ptr.To[int64](2000)},
+			wantSecurityContext: &corev1.PodSecurityContext{FSGroup: ptr.To[int64](2000)},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			es := newEsSampleBuilder().build()
 			es.Spec.Version = tt.version.String()
 			es.Spec.NodeSets[0].PodTemplate.Spec.SecurityContext = tt.userSecurityContext
+			es.Spec.NodeSets[0].VolumeOwnership = tt.volumeOwnership
 
-			cfg, err := settings.NewMergedESConfig(es.Name, tt.version, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config, nil)
+			cfg, err := settings.NewMergedESConfig(es.Name, tt.version, corev1.IPv4Protocol, es.Spec.HTTP, es.Spec.Ports, es.Spec.Audit, es.Spec.Auth, es.Spec.Discovery, nil, nil, *es.Spec.NodeSets[0].Config, nil)
 			require.NoError(t, err)
 
 			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}})
-			actual, err := BuildPodTemplateSpec(context.Background(), client, es, es.Spec.NodeSets[0], cfg, nil, tt.setDefaultFSGroup, PolicyConfig{})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, es, es.Spec.NodeSets[0], cfg, nil, tt.setDefaultFSGroup, true, PolicyConfig{})
 			require.NoError(t, err)
 			require.Equal(t, tt.wantSecurityContext, actual.Spec.SecurityContext)
 		})
@@ -248,11 +299,11 @@ func TestBuildPodTemplateSpec(t *testing.T) {
 		},
 	}
 
-	cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, *nodeSet.Config, policyConfig.ElasticsearchConfig)
+	cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, *nodeSet.Config, policyConfig.ElasticsearchConfig)
 	require.NoError(t, err)
 
 	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
-	actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, policyConfig)
+	actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, true, policyConfig)
 	require.NoError(t, err)
 
 	// build expected PodTemplateSpec
@@ -260,12 +311,12 @@ func TestBuildPodTemplateSpec(t *testing.T) {
 	terminationGracePeriodSeconds := DefaultTerminationGracePeriodSeconds
 	varFalse := false
 
-	volumes, volumeMounts := buildVolumes(sampleES.Name, ver, nodeSet, nil, volume.DownwardAPI{}, policyConfig.AdditionalVolumes)
+	volumes, volumeMounts := buildVolumes(sampleES.Name, ver, nodeSet, nil, volume.DownwardAPI{}, nil, policyConfig.AdditionalVolumes)
 	// should be sorted
 	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
 	sort.Slice(volumeMounts, func(i, j int) bool { return volumeMounts[i].Name < volumeMounts[j].Name })
 
-	initContainers, err := initcontainer.NewInitContainers(transportCertificatesVolume(sampleES.Name), nil, nil)
+	initContainers, err := initcontainer.NewInitContainers(transportCertificatesVolume(sampleES.Name), nil, nil, false, nil)
 	require.NoError(t, err)
 	// init containers should be patched with volume and inherited env vars and image
 	// init container env vars come in a slightly different order than main container ones which is an artefact of how the pod template builder works
@@ -357,9 +408,12 @@ func TestBuildPodTemplateSpec(t *testing.T) {
 						{Name: "https", HostPort: 0, ContainerPort: 9200, Protocol: "TCP", HostIP: ""},
 						{Name: "transport", HostPort: 0, ContainerPort: 9300, Protocol: "TCP", HostIP: ""},
 					},
-					Env: append(
+					Env: append(append(append(
 						[]corev1.EnvVar{{Name: "my-env", Value: "my-value"}},
 						DefaultEnvVars(sampleES.Spec.HTTP, HeadlessServiceName(esv1.StatefulSet(sampleES.Name, nodeSet.Name)))...),
+						PreStopHookEnvVars(terminationGracePeriodSeconds)...),
+						// half of the default 2Gi memory limit
+						corev1.EnvVar{Name: settings.EnvEsJavaOpts, Value: "-Xms1024m -Xmx1024m"}),
 					Resources:      DefaultResources,
 					VolumeMounts:   volumeMounts,
 					ReadinessProbe: NewReadinessProbe(),
@@ -487,9 +541,9 @@ func Test_buildAnnotations(t *testing.T) {
 			es := newEsSampleBuilder().withKeystoreResources(tt.args.keystoreResources).withUserConfig(tt.args.cfg).addEsAnnotations(tt.args.esAnnotations).build()
 			ver, err := version.Parse(sampleES.Spec.Version)
 			require.NoError(t, err)
-			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config, nil)
+			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, es.Spec.Ports, es.Spec.Audit, es.Spec.Auth, es.Spec.Discovery, nil, nil, *es.Spec.NodeSets[0].Config, nil)
 			require.NoError(t, err)
-			got := buildAnnotations(es, cfg, tt.args.keystoreResources, tt.args.scriptsContent, tt.args.policyAnnotations)
+			got := buildAnnotations(es, cfg, tt.args.keystoreResources, tt.args.scriptsContent, nil, nil, nil, tt.args.policyAnnotations)
 
 			for expectedAnnotation, expectedValue := range tt.expectedAnnotations {
 				actualValue, exists := got[expectedAnnotation]
@@ -532,6 +586,18 @@ func Test_getDefaultContainerPorts(t *testing.T) {
 				{Name: "transport", HostPort: 0, ContainerPort: 9300, Protocol: "TCP", HostIP: ""},
 			},
 		},
+		{
+			name: "overridden ports",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Ports: esv1.PortsConfig{HTTP: 19200, Transport: 19300},
+				},
+			},
+			want: []corev1.ContainerPort{
+				{Name: "https", HostPort: 0, ContainerPort: 19200, Protocol: "TCP", HostIP: ""},
+				{Name: "transport", HostPort: 0, ContainerPort: 19300, Protocol: "TCP", HostIP: ""},
+			},
+		},
 	}
 
 	for _, tc := range tt {
@@ -552,7 +618,7 @@ func Test_enableLog4JFormatMsgNoLookups(t *testing.T) {
 			name:                       "before 7.2.0, JVM log4j2.formatMsgNoLookups parameter is set by default",
 			version:                    "7.0.0",
 			userEnv:                    []corev1.EnvVar{{Name: "YO", Value: "LO"}},
-			expectedEsJavaOptsEnvValue: "-Dlog4j2.formatMsgNoLookups=true",
+			expectedEsJavaOptsEnvValue: "-Dlog4j2.formatMsgNoLookups=true -Xms1024m -Xmx1024m",
 		},
 		{
 			name:                       "before 7.2.0, JVM log4j2.formatMsgNoLookups parameter is merged with user-provided JVM parameters",
@@ -584,10 +650,10 @@ func Test_enableLog4JFormatMsgNoLookups(t *testing.T) {
 
 			ver, err := version.Parse(sampleES.Spec.Version)
 			require.NoError(t, err)
-			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, *sampleES.Spec.NodeSets[0].Config, nil)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, *sampleES.Spec.NodeSets[0].Config, nil)
 			require.NoError(t, err)
 			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
-			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, PolicyConfig{})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, true, PolicyConfig{})
 			require.NoError(t, err)
 
 			env := actual.Spec.Containers[1].Env
@@ -601,6 +667,876 @@ func Test_enableLog4JFormatMsgNoLookups(t *testing.T) {
 	}
 }
 
+func Test_setDefaultHeapSize(t *testing.T) {
+	memoryLimit := func(limit string) corev1.ResourceRequirements {
+		return corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(limit)},
+		}
+	}
+	tt := []struct {
+		name                       string
+		version                    string
+		resources                  corev1.ResourceRequirements
+		userEnv                    []corev1.EnvVar
+		expectedEsJavaOptsEnvValue string
+	}{
+		{
+			name:                       "default memory limit: half of it",
+			version:                    "7.10.2",
+			expectedEsJavaOptsEnvValue: "-Xms1024m -Xmx1024m",
+		},
+		{
+			name:                       "user-provided memory limit: half of it",
+			version:                    "7.10.2",
+			resources:                  memoryLimit("5Gi"),
+			expectedEsJavaOptsEnvValue: "-Xms2560m -Xmx2560m",
+		},
+		{
+			name:                       "large memory limit: capped",
+			version:                    "7.10.2",
+			resources:                  memoryLimit("128Gi"),
+			expectedEsJavaOptsEnvValue: "-Xms31744m -Xmx31744m",
+		},
+		{
+			name:                       "merged with user-provided JVM parameters",
+			version:                    "7.10.2",
+			resources:                  memoryLimit("4Gi"),
+			userEnv:                    []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: "-XX:+UseG1GC"}},
+			expectedEsJavaOptsEnvValue: "-XX:+UseG1GC -Xms2048m -Xmx2048m",
+		},
+		{
+			name:                       "user-provided heap size is not overridden",
+			version:                    "7.10.2",
+			resources:                  memoryLimit("4Gi"),
+			userEnv:                    []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: "-Xms3g -Xmx3g"}},
+			expectedEsJavaOptsEnvValue: "-Xms3g -Xmx3g",
+		},
+		{
+			name:    "no memory limit: rely on the JVM defaults",
+			version: "7.10.2",
+			resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+			},
+			expectedEsJavaOptsEnvValue: "",
+		},
+		{
+			name:                       "since 7.11.0, the heap is sized by Elasticsearch",
+			version:                    "7.11.0",
+			resources:                  memoryLimit("4Gi"),
+			expectedEsJavaOptsEnvValue: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sampleES := newEsSampleBuilder().build()
+			sampleES.Spec.Version = tc.version
+			esContainer := &sampleES.Spec.NodeSets[0].PodTemplate.Spec.Containers[1]
+			esContainer.Resources = tc.resources
+			esContainer.Env = tc.userEnv
+
+			ver, err := version.Parse(sampleES.Spec.Version)
+			require.NoError(t, err)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, *sampleES.Spec.NodeSets[0].Config, nil)
+			require.NoError(t, err)
+			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, true, PolicyConfig{})
+			require.NoError(t, err)
+
+			envMap := make(map[string]string)
+			for _, e := range actual.Spec.Containers[1].Env {
+				envMap[e.Name] = e.Value
+			}
+			assert.Equal(t, tc.expectedEsJavaOptsEnvValue, envMap[settings.EnvEsJavaOpts])
+		})
+	}
+}
+
+func Test_mlHeapSize(t *testing.T) {
+	tt := []struct {
+		name                       string
+		version                    string
+		userConfig                 map[string]interface{}
+		expectedEsJavaOptsEnvValue string
+	}{
+		{
+			name:       "ml node: half of the memory left by native processes",
+			version:    "7.10.2",
+			userConfig: map[string]interface{}{"node.roles": []string{"ml"}},
+			// (4096 - 30%) / 2
+			expectedEsJavaOptsEnvValue: "-Xms1433m -Xmx1433m",
+		},
+		{
+			name:                       "data and ml node: native processes use the filesystem cache half",
+			version:                    "7.10.2",
+			userConfig:                 map[string]interface{}{"node.roles": []string{"data", "ml"}},
+			expectedEsJavaOptsEnvValue: "-Xms2048m -Xmx2048m",
+		},
+		{
+			name:    "data and ml node: heap reduced if native processes need more than half of the memory",
+			version: "7.10.2",
+			userConfig: map[string]interface{}{
+				"node.roles":                          []string{"data", "ml"},
+				"xpack.ml.max_machine_memory_percent": 60,
+			},
+			// 4096 - 60%
+			expectedEsJavaOptsEnvValue: "-Xms1638m -Xmx1638m",
+		},
+		{
+			name:    "ml node: user-provided native memory percentage",
+			version: "7.10.2",
+			userConfig: map[string]interface{}{
+				"node.roles":                          []string{"ml"},
+				"xpack.ml.max_machine_memory_percent": 50,
+			},
+			expectedEsJavaOptsEnvValue: "-Xms1024m -Xmx1024m",
+		},
+		{
+			name:                       "ml node before 7.9: legacy role settings",
+			version:                    "7.8.0",
+			userConfig:                 map[string]interface{}{"node.ml": true, "node.data": false},
+			expectedEsJavaOptsEnvValue: "-Xms1433m -Xmx1433m",
+		},
+		{
+			name:                       "default roles: heap sized by Elasticsearch",
+			version:                    "8.12.0",
+			expectedEsJavaOptsEnvValue: "",
+		},
+		{
+			name:                       "default roles before 7.11: half of the memory",
+			version:                    "7.10.2",
+			expectedEsJavaOptsEnvValue: "-Xms2048m -Xmx2048m",
+		},
+		{
+			name:                       "ml node from 7.11: heap sized by Elasticsearch",
+			version:                    "7.11.0",
+			userConfig:                 map[string]interface{}{"node.roles": []string{"ml"}},
+			expectedEsJavaOptsEnvValue: "",
+		},
+		{
+			name:    "ml node from 7.11 with a user-provided native memory percentage: heap sized by Elasticsearch",
+			version: "8.12.0",
+			userConfig: map[string]interface{}{
+				"node.roles":                          []string{"ml"},
+				"xpack.ml.max_machine_memory_percent": 50,
+			},
+			expectedEsJavaOptsEnvValue: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sampleES := newEsSampleBuilder().withUserConfig(tc.userConfig).build()
+			sampleES.Spec.Version = tc.version
+			esContainer := &sampleES.Spec.NodeSets[0].PodTemplate.Spec.Containers[1]
+			esContainer.Resources = corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+			}
+			esContainer.Env = nil
+
+			ver, err := version.Parse(sampleES.Spec.Version)
+			require.NoError(t, err)
+			userCfg := commonv1.Config{}
+			if sampleES.Spec.NodeSets[0].Config != nil {
+				userCfg = *sampleES.Spec.NodeSets[0].Config
+			}
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, userCfg, nil)
+			require.NoError(t, err)
+			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, true, PolicyConfig{})
+			require.NoError(t, err)
+
+			envMap := make(map[string]string)
+			for _, e := range actual.Spec.Containers[1].Env {
+				envMap[e.Name] = e.Value
+			}
+			assert.Equal(t, tc.expectedEsJavaOptsEnvValue, envMap[settings.EnvEsJavaOpts])
+		})
+	}
+}
+
+func Test_topologySpreadConstraints(t *testing.T) {
+	userConstraints := []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           2,
+			TopologyKey:       "kubernetes.io/hostname",
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+		},
+	}
+	zoneConstraints := func(whenUnsatisfiable corev1.UnsatisfiableConstraintAction) []corev1.TopologySpreadConstraint {
+		return []corev1.TopologySpreadConstraint{
+			{
+				MaxSkew:           1,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: whenUnsatisfiable,
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"elasticsearch.k8s.elastic.co/cluster-name":     "name",
+						"elasticsearch.k8s.elastic.co/statefulset-name": "name-es-nodeset-1",
+					},
+				},
+			},
+		}
+	}
+	tt := []struct {
+		name              string
+		spreadAcrossZones bool
+		whenUnsatisfiable corev1.UnsatisfiableConstraintAction
+		userConstraints   []corev1.TopologySpreadConstraint
+		expected          []corev1.TopologySpreadConstraint
+	}{
+		{
+			name:     "no constraints by default",
+			expected: nil,
+		},
+		{
+			name:              "soft default zone constraint when enabled",
+			spreadAcrossZones: true,
+			expected:          zoneConstraints(corev1.ScheduleAnyway),
+		},
+		{
+			name:              "hard default zone constraint when requested",
+			spreadAcrossZones: true,
+			whenUnsatisfiable: corev1.DoNotSchedule,
+			expected:          zoneConstraints(corev1.DoNotSchedule),
+		},
+		{
+			name:              "user-provided constraints take precedence",
+			spreadAcrossZones: true,
+			userConstraints:   userConstraints,
+			expected:          userConstraints,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sampleES := newEsSampleBuilder().build()
+			nodeSet := sampleES.Spec.NodeSets[0]
+			nodeSet.SpreadAcrossZones = tc.spreadAcrossZones
+			nodeSet.ZoneSpreadWhenUnsatisfiable = tc.whenUnsatisfiable
+			nodeSet.PodTemplate.Spec.TopologySpreadConstraints = tc.userConstraints
+
+			ver, err := version.Parse(sampleES.Spec.Version)
+			require.NoError(t, err)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, *nodeSet.Config, nil)
+			require.NoError(t, err)
+			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, nodeSet, cfg, nil, false, true, PolicyConfig{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual.Spec.TopologySpreadConstraints)
+		})
+	}
+}
+
+func Test_nodeAttributes(t *testing.T) {
+	sampleES := newEsSampleBuilder().addEsAnnotations(map[string]string{
+		esv1.NodeAttributesAnnotation: "zone=topology.kubernetes.io/zone",
+	}).build()
+	nodeSet := sampleES.Spec.NodeSets[0]
+	ver, err := version.Parse(sampleES.Spec.Version)
+	require.NoError(t, err)
+	cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, sampleES.NodeAttributes(), nil, *nodeSet.Config, nil)
+	require.NoError(t, err)
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+	actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, nodeSet, cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+
+	// the attribute value is read from the Pod annotation the node label is copied to
+	esContainer := getElasticsearchContainer(actual.Spec.Containers)
+	require.NotNil(t, esContainer)
+	require.Contains(t, esContainer.Env, corev1.EnvVar{
+		Name: "NODE_ATTR_zone",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations['topology.kubernetes.io/zone']"},
+		},
+	})
+	// annotations are exposed to the init container waiting for them to be set
+	require.Contains(t, actual.Spec.Volumes, volume.DownwardAPI{}.WithAnnotations(true).Volume())
+	var prepareFsMounts []corev1.VolumeMount
+	for _, c := range actual.Spec.InitContainers {
+		if c.Name == initcontainer.PrepareFilesystemContainerName {
+			prepareFsMounts = c.VolumeMounts
+		}
+	}
+	require.Contains(t, prepareFsMounts, corev1.VolumeMount{
+		Name:      esvolume.DownwardAPIVolumeName,
+		ReadOnly:  true,
+		MountPath: esvolume.DownwardAPIMountPath,
+	})
+}
+
+func Test_log4j2Config(t *testing.T) {
+	sampleES := newEsSampleBuilder().build()
+	nodeSet := sampleES.Spec.NodeSets[0]
+	ver, err := version.Parse(sampleES.Spec.Version)
+	require.NoError(t, err)
+	cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, *nodeSet.Config, nil)
+	require.NoError(t, err)
+	scripts := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}}
+	log4j2Secret := func(content string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.Log4j2ConfigSecret(sampleES.Name)},
+			Data:       map[string][]byte{esv1.Log4j2ConfigFileName: []byte(content)},
+		}
+	}
+	log4j2Mount := corev1.VolumeMount{
+		Name:      settings.Log4j2ConfigVolumeName,
+		ReadOnly:  true,
+		MountPath: "/usr/share/elasticsearch/config/log4j2.properties",
+		SubPath:   "log4j2.properties",
+	}
+
+	// the default log4j2 configuration is used if none is specified
+	actual, err := BuildPodTemplateSpec(context.Background(), k8s.NewFakeClient(scripts), sampleES, nodeSet, cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.NotContains(t, getElasticsearchContainer(actual.Spec.Containers).VolumeMounts, log4j2Mount)
+	defaultConfigHash := actual.Annotations[configHashAnnotationName]
+
+	// the log4j2 configuration is mounted over the default one
+	es := sampleES.DeepCopy()
+	es.Spec.Log4j2 = &esv1.Log4j2Config{Config: "rootLogger.level = info"}
+	actual, err = BuildPodTemplateSpec(context.Background(), k8s.NewFakeClient(scripts, log4j2Secret("rootLogger.level = info")), *es, nodeSet, cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.Contains(t, actual.Spec.Volumes, settings.Log4j2ConfigSecretVolume(sampleES.Name).Volume())
+	require.Contains(t, getElasticsearchContainer(actual.Spec.Containers).VolumeMounts, log4j2Mount)
+	configHash := actual.Annotations[configHashAnnotationName]
+	require.NotEqual(t, defaultConfigHash, configHash)
+
+	// a change of the log4j2 configuration rotates the Pods
+	es.Spec.Log4j2 = &esv1.Log4j2Config{Config: "rootLogger.level = debug"}
+	actual, err = BuildPodTemplateSpec(context.Background(), k8s.NewFakeClient(scripts, log4j2Secret("rootLogger.level = debug")), *es, nodeSet, cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.NotEqual(t, configHash, actual.Annotations[configHashAnnotationName])
+}
+
+func Test_httpTLSDisabled(t *testing.T) {
+	sampleES := newEsSampleBuilder().build()
+	nodeSet := sampleES.Spec.NodeSets[0]
+	ver, err := version.Parse(sampleES.Spec.Version)
+	require.NoError(t, err)
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+	httpScheme := func(es esv1.Elasticsearch) (probeProtocol string, portName string) {
+		cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, es.Spec.Ports, es.Spec.Audit, es.Spec.Auth, es.Spec.Discovery, nil, nil, *nodeSet.Config, nil)
+		require.NoError(t, err)
+		actual, err := BuildPodTemplateSpec(context.Background(), client, es, nodeSet, cfg, nil, false, true, PolicyConfig{})
+		require.NoError(t, err)
+		esContainer := getElasticsearchContainer(actual.Spec.Containers)
+		for _, env := range esContainer.Env {
+			if env.Name == settings.EnvReadinessProbeProtocol {
+				return env.Value, esContainer.Ports[0].Name
+			}
+		}
+		t.Fatalf("%s not found", settings.EnvReadinessProbeProtocol)
+		return "", ""
+	}
+
+	// the readiness probe uses HTTPS by default
+	protocol, portName := httpScheme(sampleES)
+	require.Equal(t, "https", protocol)
+	require.Equal(t, "https", portName)
+
+	// and plain HTTP once TLS is disabled
+	es := sampleES.DeepCopy()
+	es.Spec.HTTP.TLS.SelfSignedCertificate = &commonv1.SelfSignedCertificate{Disabled: true}
+	protocol, portName = httpScheme(*es)
+	require.Equal(t, "http", protocol)
+	require.Equal(t, "http", portName)
+}
+
+func Test_logSidecar(t *testing.T) {
+	sampleES := newEsSampleBuilder().build()
+	sampleES.Spec.Version = "8.14.0"
+	nodeSet := sampleES.Spec.NodeSets[0]
+	ver, err := version.Parse(sampleES.Spec.Version)
+	require.NoError(t, err)
+	cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, *nodeSet.Config, nil)
+	require.NoError(t, err)
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+	containerNames := func(podTemplate corev1.PodTemplateSpec) []string {
+		names := make([]string, 0, len(podTemplate.Spec.Containers))
+		for _, c := range podTemplate.Spec.Containers {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+	getContainer := func(podTemplate corev1.PodTemplateSpec, name string) corev1.Container {
+		for _, c := range podTemplate.Spec.Containers {
+			if c.Name == name {
+				return c
+			}
+		}
+		t.Fatalf("container %s not found", name)
+		return corev1.Container{}
+	}
+	configMount := corev1.VolumeMount{Name: logSidecarConfigVolumeName, ReadOnly: true, MountPath: esv1.LogSidecarConfigMountPath}
+
+	// no sidecar by default
+	actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, nodeSet, cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"additional-container", "elasticsearch"}, containerNames(actual))
+	require.NotContains(t, getElasticsearchContainer(actual.Spec.Containers).Env, stackmon.FileLogStyleEnvVar())
+
+	// the sidecar shares the logs volume, user-defined sidecars are preserved
+	es := sampleES.DeepCopy()
+	es.Spec.LogSidecar = &esv1.LogSidecar{Image: "fluent/fluent-bit:3.0", ConfigSecretName: "fluent-bit-config"}
+	actual, err = BuildPodTemplateSpec(context.Background(), client, *es, nodeSet, cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"additional-container", "elasticsearch", esv1.LogSidecarContainerName}, containerNames(actual))
+	require.Contains(t, getElasticsearchContainer(actual.Spec.Containers).Env, stackmon.FileLogStyleEnvVar())
+	require.Contains(t, getElasticsearchContainer(actual.Spec.Containers).VolumeMounts, esvolume.DefaultLogsVolumeMount)
+	sidecar := getContainer(actual, esv1.LogSidecarContainerName)
+	require.Equal(t, "fluent/fluent-bit:3.0", sidecar.Image)
+	require.Contains(t, sidecar.VolumeMounts, esvolume.DefaultLogsVolumeMount)
+	require.Contains(t, sidecar.VolumeMounts, configMount)
+	require.Contains(t, actual.Spec.Volumes, esvolume.DefaultLogsVolume)
+	require.Contains(t, actual.Spec.Volumes, volume.NewSecretVolumeWithMountPath("fluent-bit-config", logSidecarConfigVolumeName, esv1.LogSidecarConfigMountPath).Volume())
+
+	// the Pod template is stable across reconciliations
+	again, err := BuildPodTemplateSpec(context.Background(), client, *es, nodeSet, cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.Equal(t, actual, again)
+	reinjected := withLogSidecar(defaults.NewPodTemplateBuilder(*actual.DeepCopy(), esv1.ElasticsearchContainerName), *es).PodTemplate
+	require.Equal(t, actual, reinjected)
+
+	// the sidecar can be customized in the Pod template
+	customNodeSet := *nodeSet.DeepCopy()
+	customNodeSet.PodTemplate.Spec.Containers = append(customNodeSet.PodTemplate.Spec.Containers, corev1.Container{
+		Name: esv1.LogSidecarContainerName,
+		Args: []string{"-c", "/etc/log-sidecar/fluent-bit.conf"},
+	})
+	actual, err = BuildPodTemplateSpec(context.Background(), client, *es, customNodeSet, cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"additional-container", "elasticsearch", esv1.LogSidecarContainerName}, containerNames(actual))
+	sidecar = getContainer(actual, esv1.LogSidecarContainerName)
+	require.Equal(t, "fluent/fluent-bit:3.0", sidecar.Image)
+	require.Equal(t, []string{"-c", "/etc/log-sidecar/fluent-bit.conf"}, sidecar.Args)
+	require.Contains(t, sidecar.VolumeMounts, esvolume.DefaultLogsVolumeMount)
+}
+
+func Test_dataPermissionsInitContainer(t *testing.T) {
+	sampleES := newEsSampleBuilder().build()
+	sampleES.Spec.Version = "8.14.0"
+	ver, err := version.Parse(sampleES.Spec.Version)
+	require.NoError(t, err)
+	cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, *sampleES.Spec.NodeSets[0].Config, nil)
+	require.NoError(t, err)
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+
+	tests := []struct {
+		name                      string
+		volumeOwnership           *esv1.VolumeOwnership
+		podSecurityContext        *corev1.PodSecurityContext
+		setDefaultSecurityContext bool
+		wantInitContainer         bool
+		wantOwner                 []corev1.EnvVar
+	}{
+		{
+			name:                      "omitted by default",
+			setDefaultSecurityContext: true,
+			wantInitContainer:         false,
+		},
+		{
+			name:                      "omitted if not enabled",
+			volumeOwnership:           &esv1.VolumeOwnership{FSGroupChangePolicy: ptr.To(corev1.FSGroupChangeOnRootMismatch)},
+			setDefaultSecurityContext: true,
+			wantInitContainer:         false,
+		},
+		{
+			name:                      "default fsGroup",
+			volumeOwnership:           &esv1.VolumeOwnership{FixDataVolumePermissions: true},
+			setDefaultSecurityContext: true,
+			wantInitContainer:         true,
+			wantOwner:                 []corev1.EnvVar{{Name: "DATA_OWNER_UID", Value: "1000"}, {Name: "DATA_OWNER_GID", Value: "1000"}},
+		},
+		{
+			name:                      "no fsGroup",
+			volumeOwnership:           &esv1.VolumeOwnership{FixDataVolumePermissions: true},
+			setDefaultSecurityContext: false,
+			wantInitContainer:         true,
+			wantOwner:                 []corev1.EnvVar{{Name: "DATA_OWNER_UID", Value: "1000"}, {Name: "DATA_OWNER_GID", Value: "0"}},
+		},
+		{
+			name:                      "user and fsGroup of the volume ownership and Pod template",
+			volumeOwnership:           &esv1.VolumeOwnership{FixDataVolumePermissions: true, FSGroup: ptr.To[int64](2000)},
+			podSecurityContext:        &corev1.PodSecurityContext{RunAsUser: ptr.To[int64](1234), RunAsGroup: ptr.To[int64](5678)},
+			setDefaultSecurityContext: true,
+			wantInitContainer:         true,
+			wantOwner:                 []corev1.EnvVar{{Name: "DATA_OWNER_UID", Value: "1234"}, {Name: "DATA_OWNER_GID", Value: "2000"}},
+		},
+		{
+			name:                      "group of the Pod template",
+			volumeOwnership:           &esv1.VolumeOwnership{FixDataVolumePermissions: true},
+			podSecurityContext:        &corev1.PodSecurityContext{RunAsGroup: ptr.To[int64](5678)},
+			setDefaultSecurityContext: true,
+			wantInitContainer:         true,
+			wantOwner:                 []corev1.EnvVar{{Name: "DATA_OWNER_UID", Value: "1000"}, {Name: "DATA_OWNER_GID", Value: "5678"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeSet := *sampleES.Spec.NodeSets[0].DeepCopy()
+			nodeSet.VolumeOwnership = tt.volumeOwnership
+			nodeSet.PodTemplate.Spec.SecurityContext = tt.podSecurityContext
+			nodeSet.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: esvolume.ElasticsearchDataVolumeName}}}
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, nodeSet, cfg, nil, tt.setDefaultSecurityContext, true, PolicyConfig{})
+			require.NoError(t, err)
+
+			var initContainer *corev1.Container
+			for i, c := range actual.Spec.InitContainers {
+				if c.Name == initcontainer.DataPermissionsContainerName {
+					// runs before the other init containers
+					require.Equal(t, 0, i)
+					initContainer = &actual.Spec.InitContainers[i]
+				}
+			}
+			if !tt.wantInitContainer {
+				require.Nil(t, initContainer)
+				return
+			}
+			require.NotNil(t, initContainer)
+			require.Subset(t, initContainer.Env, tt.wantOwner)
+			require.Contains(t, initContainer.VolumeMounts, corev1.VolumeMount{Name: esvolume.ElasticsearchDataVolumeName, MountPath: esvolume.ElasticsearchDataMountPath})
+			require.Equal(t, ptr.To[int64](0), initContainer.SecurityContext.RunAsUser)
+		})
+	}
+}
+
+func Test_jvmOptions(t *testing.T) {
+	sampleES := newEsSampleBuilder().build()
+	sampleES.Spec.Version = "7.10.2"
+	esContainer := &sampleES.Spec.NodeSets[0].PodTemplate.Spec.Containers[1]
+	esContainer.Resources = corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+	}
+	esContainer.Env = nil
+	ver, err := version.Parse(sampleES.Spec.Version)
+	require.NoError(t, err)
+	cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, *sampleES.Spec.NodeSets[0].Config, nil)
+	require.NoError(t, err)
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+	jvmOptionsMount := corev1.VolumeMount{
+		Name:      settings.JVMOptionsVolumeName,
+		ReadOnly:  true,
+		MountPath: "/usr/share/elasticsearch/config/jvm.options.d/eck.options",
+		SubPath:   "eck.options",
+	}
+	esJavaOpts := func(podTemplate corev1.PodTemplateSpec) string {
+		for _, e := range getElasticsearchContainer(podTemplate.Spec.Containers).Env {
+			if e.Name == settings.EnvEsJavaOpts {
+				return e.Value
+			}
+		}
+		return ""
+	}
+
+	// no JVM options file without JVM options
+	actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.NotContains(t, getElasticsearchContainer(actual.Spec.Containers).VolumeMounts, jvmOptionsMount)
+	require.Equal(t, "-Xms2048m -Xmx2048m", esJavaOpts(actual))
+	defaultConfigHash := actual.Annotations[configHashAnnotationName]
+
+	// JVM options are mounted into the jvm.options.d directory, the operator-managed heap size is preserved
+	nodeSet := *sampleES.Spec.NodeSets[0].DeepCopy()
+	nodeSet.JVMOptions = []string{"-Dfoo=bar"}
+	actual, err = BuildPodTemplateSpec(context.Background(), client, sampleES, nodeSet, cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.Contains(t, actual.Spec.Volumes, settings.JVMOptionsSecretVolume(esv1.StatefulSet(sampleES.Name, nodeSet.Name)).Volume())
+	require.Contains(t, getElasticsearchContainer(actual.Spec.Containers).VolumeMounts, jvmOptionsMount)
+	require.Equal(t, "-Xms2048m -Xmx2048m", esJavaOpts(actual))
+	configHash := actual.Annotations[configHashAnnotationName]
+	require.NotEqual(t, defaultConfigHash, configHash)
+
+	// the heap size set in the JVM options replaces the one managed by the operator
+	nodeSet.JVMOptions = []string{"-Dfoo=bar", "-Xms1g", "-Xmx1g"}
+	actual, err = BuildPodTemplateSpec(context.Background(), client, sampleES, nodeSet, cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.Equal(t, "", esJavaOpts(actual))
+	require.NotEqual(t, configHash, actual.Annotations[configHashAnnotationName])
+}
+
+func Test_trustedCertificateAuthorities(t *testing.T) {
+	sampleES := newEsSampleBuilder().build()
+	sampleES.Spec.NodeSets[0].PodTemplate.Spec.Containers[1].Env = nil
+	nodeSet := sampleES.Spec.NodeSets[0]
+	ver, err := version.Parse(sampleES.Spec.Version)
+	require.NoError(t, err)
+	cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, *nodeSet.Config, nil)
+	require.NoError(t, err)
+	scripts := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}}
+	trustedCASecret := func(content string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.TrustedCASecret(sampleES.Name)},
+			Data:       map[string][]byte{certificates.CAFileName: []byte(content)},
+		}
+	}
+	esJavaOpts := func(podTemplate corev1.PodTemplateSpec) string {
+		for _, e := range getElasticsearchContainer(podTemplate.Spec.Containers).Env {
+			if e.Name == settings.EnvEsJavaOpts {
+				return e.Value
+			}
+		}
+		return ""
+	}
+	truststoreMount := settings.TruststoreVolume().VolumeMount()
+
+	// the default truststore of the JVM is used if no additional CA certificates are trusted
+	actual, err := BuildPodTemplateSpec(context.Background(), k8s.NewFakeClient(scripts), sampleES, nodeSet, cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.NotContains(t, getElasticsearchContainer(actual.Spec.Containers).VolumeMounts, truststoreMount)
+	require.NotContains(t, esJavaOpts(actual), "-Djavax.net.ssl.trustStore=")
+	defaultConfigHash := actual.Annotations[configHashAnnotationName]
+
+	// a truststore is generated by an init container and used by the JVM, prior to the options of ES_JAVA_OPTS
+	es := sampleES.DeepCopy()
+	es.Spec.TrustedCertificateAuthorities = &commonv1.SecretRef{SecretName: "my-ca"}
+	actual, err = BuildPodTemplateSpec(context.Background(), k8s.NewFakeClient(scripts, trustedCASecret("ca-1")), *es, nodeSet, cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.Contains(t, actual.Spec.Volumes, settings.TrustedCASecretVolume(sampleES.Name).Volume())
+	require.Contains(t, actual.Spec.Volumes, settings.TruststoreVolume().Volume())
+	require.Contains(t, getElasticsearchContainer(actual.Spec.Containers).VolumeMounts, truststoreMount)
+	var truststoreContainer *corev1.Container
+	for i := range actual.Spec.InitContainers {
+		if actual.Spec.InitContainers[i].Name == initcontainer.TruststoreContainerName {
+			truststoreContainer = &actual.Spec.InitContainers[i]
+		}
+	}
+	require.NotNil(t, truststoreContainer)
+	require.Contains(t, truststoreContainer.VolumeMounts, truststoreMount)
+	require.True(t, strings.HasPrefix(esJavaOpts(actual), strings.Join(settings.TruststoreJavaOpts(), " ")))
+	configHash := actual.Annotations[configHashAnnotationName]
+	require.NotEqual(t, defaultConfigHash, configHash)
+
+	// a change of the CA certificates rotates the Pods
+	actual, err = BuildPodTemplateSpec(context.Background(), k8s.NewFakeClient(scripts, trustedCASecret("ca-2")), *es, nodeSet, cfg, nil, false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.NotEqual(t, configHash, actual.Annotations[configHashAnnotationName])
+}
+
+func Test_terminationGracePeriod(t *testing.T) {
+	tt := []struct {
+		name                string
+		userConfig          map[string]interface{}
+		userGracePeriod     *int64
+		expectedGracePeriod int64
+	}{
+		{
+			name:                "data node set: default",
+			userConfig:          map[string]interface{}{"node.master": "false", "node.data": "true"},
+			expectedGracePeriod: DefaultTerminationGracePeriodSeconds,
+		},
+		{
+			name:                "coordinating node set: same default",
+			userConfig:          map[string]interface{}{"node.master": "false", "node.data": "false", "node.ingest": "false", "node.ml": "false"},
+			expectedGracePeriod: DefaultTerminationGracePeriodSeconds,
+		},
+		{
+			name:                "explicit value wins over the default",
+			userConfig:          map[string]interface{}{"node.master": "false", "node.data": "true"},
+			userGracePeriod:     ptr.To[int64](900),
+			expectedGracePeriod: 900,
+		},
+		{
+			name:                "explicit value for a coordinating node set",
+			userConfig:          map[string]interface{}{"node.master": "false", "node.data": "false", "node.ingest": "false", "node.ml": "false"},
+			userGracePeriod:     ptr.To[int64](60),
+			expectedGracePeriod: 60,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sampleES := newEsSampleBuilder().withUserConfig(tc.userConfig).build()
+			nodeSet := sampleES.Spec.NodeSets[0]
+			nodeSet.PodTemplate.Spec.TerminationGracePeriodSeconds = tc.userGracePeriod
+
+			ver, err := version.Parse(sampleES.Spec.Version)
+			require.NoError(t, err)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, *nodeSet.Config, nil)
+			require.NoError(t, err)
+			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, nodeSet, cfg, nil, false, true, PolicyConfig{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedGracePeriod, *actual.Spec.TerminationGracePeriodSeconds)
+			// the pre-stop hook is bounded by the effective grace period, which defaults to the one of the script
+			esContainer := *getElasticsearchContainer(actual.Spec.Containers)
+			envVar := corev1.EnvVar{Name: EnvPreStopTerminationGracePeriodSeconds, Value: strconv.FormatInt(tc.expectedGracePeriod, 10)}
+			if tc.expectedGracePeriod == DefaultTerminationGracePeriodSeconds {
+				assert.NotContains(t, esContainer.Env, envVar)
+			} else {
+				assert.Contains(t, esContainer.Env, envVar)
+			}
+		})
+	}
+}
+
+func Test_readinessProbe(t *testing.T) {
+	tt := []struct {
+		name          string
+		userProbe     *corev1.Probe
+		expectedProbe *corev1.Probe
+		expectedEnv   []corev1.EnvVar
+	}{
+		{
+			name:          "defaults when unspecified",
+			expectedProbe: NewReadinessProbe(),
+		},
+		{
+			name:      "user-provided thresholds override the defaults",
+			userProbe: &corev1.Probe{PeriodSeconds: 30, FailureThreshold: 6, TimeoutSeconds: 25},
+			expectedProbe: func() *corev1.Probe {
+				probe := NewReadinessProbe()
+				probe.PeriodSeconds = 30
+				probe.FailureThreshold = 6
+				probe.TimeoutSeconds = 25
+				return probe
+			}(),
+			expectedEnv: []corev1.EnvVar{{Name: EnvReadinessProbeTimeout, Value: "23"}},
+		},
+		{
+			name:      "default timeout",
+			userProbe: &corev1.Probe{FailureThreshold: 10},
+			expectedProbe: func() *corev1.Probe {
+				probe := NewReadinessProbe()
+				probe.FailureThreshold = 10
+				return probe
+			}(),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sampleES := newEsSampleBuilder().build()
+			nodeSet := sampleES.Spec.NodeSets[0]
+			nodeSet.PodTemplate.Spec.Containers = []corev1.Container{{Name: esv1.ElasticsearchContainerName, ReadinessProbe: tc.userProbe}}
+
+			ver, err := version.Parse(sampleES.Spec.Version)
+			require.NoError(t, err)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, *nodeSet.Config, nil)
+			require.NoError(t, err)
+			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, nodeSet, cfg, nil, false, true, PolicyConfig{})
+			require.NoError(t, err)
+			esContainer := *getElasticsearchContainer(actual.Spec.Containers)
+			// the probe still runs the readiness probe script
+			assert.Equal(t, tc.expectedProbe, esContainer.ReadinessProbe)
+			for _, env := range esContainer.Env {
+				if env.Name == EnvReadinessProbeTimeout {
+					assert.Contains(t, tc.expectedEnv, env)
+					return
+				}
+			}
+			assert.Empty(t, tc.expectedEnv)
+		})
+	}
+}
+
+func Test_keystoreInitContainerResources(t *testing.T) {
+	customResources := corev1.ResourceRequirements{
+		Requests: map[corev1.ResourceName]resource.Quantity{
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+			corev1.ResourceCPU:    resource.MustParse("50m"),
+		},
+		Limits: map[corev1.ResourceName]resource.Quantity{
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+	}
+	tt := []struct {
+		name               string
+		userInitContainers []corev1.Container
+		expectedResources  corev1.ResourceRequirements
+	}{
+		{
+			name:              "defaults to the keystore init container resources",
+			expectedResources: initcontainer.KeystoreParams.Resources,
+		},
+		{
+			name: "resources from the pod template take precedence",
+			userInitContainers: []corev1.Container{
+				{
+					Name:      keystore.InitContainerName,
+					Resources: customResources,
+				},
+			},
+			expectedResources: customResources,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sampleES := newEsSampleBuilder().build()
+			sampleES.Spec.NodeSets[0].PodTemplate.Spec.InitContainers = tc.userInitContainers
+			keystoreResources := &keystore.Resources{
+				InitContainer: corev1.Container{
+					Name:      keystore.InitContainerName,
+					Resources: initcontainer.KeystoreParams.Resources,
+				},
+			}
+
+			ver, err := version.Parse(sampleES.Spec.Version)
+			require.NoError(t, err)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, *sampleES.Spec.NodeSets[0].Config, nil)
+			require.NoError(t, err)
+			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, keystoreResources, false, true, PolicyConfig{})
+			require.NoError(t, err)
+
+			var keystoreInitContainer *corev1.Container
+			for i, c := range actual.Spec.InitContainers {
+				if c.Name == keystore.InitContainerName {
+					keystoreInitContainer = &actual.Spec.InitContainers[i]
+				}
+			}
+			require.NotNil(t, keystoreInitContainer)
+			assert.Equal(t, tc.expectedResources, keystoreInitContainer.Resources)
+		})
+	}
+}
+
+func Test_keystorePassword(t *testing.T) {
+	sampleES := newEsSampleBuilder().build()
+	nodeSet := sampleES.Spec.NodeSets[0]
+	ver, err := version.Parse(sampleES.Spec.Version)
+	require.NoError(t, err)
+	cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.Ports, sampleES.Spec.Audit, sampleES.Spec.Auth, sampleES.Spec.Discovery, nil, nil, *nodeSet.Config, nil)
+	require.NoError(t, err)
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+	passwordEnvVar := corev1.EnvVar{
+		Name: keystore.PasswordEnvVarName,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "keystore-password"},
+				Key:                  keystore.PasswordSecretKey,
+			},
+		},
+	}
+	keystoreResources := func(password *keystore.Password) *keystore.Resources {
+		return &keystore.Resources{
+			InitContainer: corev1.Container{Name: keystore.InitContainerName},
+			Version:       "1",
+			Password:      password,
+		}
+	}
+
+	// the keystore is not password protected
+	actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, nodeSet, cfg, keystoreResources(nil), false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.NotContains(t, getElasticsearchContainer(actual.Spec.Containers).Env, passwordEnvVar)
+	unprotectedConfigHash := actual.Annotations[configHashAnnotationName]
+
+	// the password is exposed to the Docker entrypoint of Elasticsearch
+	actual, err = BuildPodTemplateSpec(context.Background(), client, sampleES, nodeSet, cfg, keystoreResources(&keystore.Password{EnvVar: passwordEnvVar, Version: "1"}), false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.Contains(t, getElasticsearchContainer(actual.Spec.Containers).Env, passwordEnvVar)
+	configHash := actual.Annotations[configHashAnnotationName]
+	require.NotEqual(t, unprotectedConfigHash, configHash)
+
+	// a change of the password rotates the Pods
+	actual, err = BuildPodTemplateSpec(context.Background(), client, sampleES, nodeSet, cfg, keystoreResources(&keystore.Password{EnvVar: passwordEnvVar, Version: "2"}), false, true, PolicyConfig{})
+	require.NoError(t, err)
+	require.NotEqual(t, configHash, actual.Annotations[configHashAnnotationName])
+}
+
 func Test_getScriptsConfigMapContent(t *testing.T) {
 	cm := &corev1.ConfigMap{
 		Data: map[string]string{
@@ -5,7 +5,10 @@
 package nodespec
 
 import (
+	"bytes"
 	"path"
+	"strconv"
+	"text/template"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -14,13 +17,22 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
 )
 
+const (
+	// EnvReadinessProbeTimeout is the timeout in seconds of the HTTP request issued by the readiness probe script.
+	EnvReadinessProbeTimeout = "READINESS_PROBE_TIMEOUT"
+
+	defaultReadinessProbeTimeoutSeconds = 5
+	// readinessProbeRequestMarginSeconds is the time left to the readiness probe script to run besides the HTTP request.
+	readinessProbeRequestMarginSeconds = 2
+)
+
 func NewReadinessProbe() *corev1.Probe {
 	return &corev1.Probe{
 		FailureThreshold:    3,
 		InitialDelaySeconds: 10,
 		PeriodSeconds:       5,
 		SuccessThreshold:    1,
-		TimeoutSeconds:      5,
+		TimeoutSeconds:      defaultReadinessProbeTimeoutSeconds,
 		ProbeHandler: corev1.ProbeHandler{
 			Exec: &corev1.ExecAction{
 				Command: []string{"bash", "-c", path.Join(volume.ScriptsVolumeMountPath, ReadinessProbeScriptConfigKey)},
@@ -29,8 +41,32 @@ func NewReadinessProbe() *corev1.Probe {
 	}
 }
 
+// ReadinessProbeTimeoutEnvVars returns the environment variable adjusting the timeout of the HTTP request issued by
+// the readiness probe script to the timeout of the given probe, if it differs from the default one. This allows users
+// to only tune the probe thresholds, the script still checking that Elasticsearch responds to HTTP requests.
+func ReadinessProbeTimeoutEnvVars(probe *corev1.Probe) []corev1.EnvVar {
+	if probe == nil || probe.TimeoutSeconds == 0 || probe.TimeoutSeconds == defaultReadinessProbeTimeoutSeconds {
+		return nil
+	}
+	timeout := max(probe.TimeoutSeconds-readinessProbeRequestMarginSeconds, 1)
+	return []corev1.EnvVar{{Name: EnvReadinessProbeTimeout, Value: strconv.Itoa(int(timeout))}}
+}
+
 const ReadinessProbeScriptConfigKey = "readiness-probe-script.sh"
-const ReadinessProbeScript = `#!/usr/bin/env bash
+
+// RenderReadinessProbeScript renders the readiness probe script requesting Elasticsearch on the given HTTP port.
+// If waitForShardRecovery is true, the script also checks that no shard recovery targeting the local node is still
+// in progress before reporting the Pod as ready.
+func RenderReadinessProbeScript(httpPort int32, waitForShardRecovery bool) (string, error) {
+	var script bytes.Buffer
+	err := readinessProbeScriptTemplate.Execute(&script, map[string]interface{}{
+		"HTTPPort":             httpPort,
+		"WaitForShardRecovery": waitForShardRecovery,
+	})
+	return script.String(), err
+}
+
+var readinessProbeScriptTemplate = template.Must(template.New("readiness-probe").Parse(`#!/usr/bin/env bash
 
 # fail should be called as a last resort to help the user to understand why the probe failed
 function fail {
@@ -75,7 +111,7 @@ fi
 
 # request Elasticsearch on /
 # we are turning globbing off to allow for unescaped [] in case of IPv6
-ENDPOINT="${READINESS_PROBE_PROTOCOL:-https}://${LOOPBACK}:9200/"
+ENDPOINT="${READINESS_PROBE_PROTOCOL:-https}://${LOOPBACK}:{{ .HTTPPort }}/"
 ORIGIN_HEADER="` + http.InternalProductRequestHeaderString + `"
 status=$(curl -o /dev/null -w "%{http_code}" --max-time ${READINESS_PROBE_TIMEOUT} -H "${ORIGIN_HEADER}" -XGET -g -s -k ${BASIC_AUTH} $ENDPOINT)
 curl_rc=$?
@@ -85,9 +121,24 @@ if [[ ${curl_rc} -ne 0 ]]; then
 fi
 
 # ready if status code 200, 503 is tolerable if ES version is 6.x
-if [[ ${status} == "200" ]] || [[ ${status} == "503" && ${version:0:2} == "6." ]]; then
-  exit 0
-else
+if [[ ${status} != "200" ]] && [[ ${status} != "503" || ${version:0:2} != "6." ]]; then
   fail " \"status\": \"${status}\", \"version\":\"${version}\" "
 fi
-`
+{{- if .WaitForShardRecovery }}
+
+# not ready as long as shards are being recovered onto the local node
+recovery=$(curl --max-time ${READINESS_PROBE_TIMEOUT} -H "${ORIGIN_HEADER}" -XGET -g -s -k ${BASIC_AUTH} "${ENDPOINT}_nodes/_local/stats/indices/recovery?filter_path=nodes.*.indices.recovery.current_as_target")
+curl_rc=$?
+
+if [[ ${curl_rc} -ne 0 ]]; then
+  fail "\"curl_rc\": \"${curl_rc}\", \"check\": \"shard_recovery\""
+fi
+
+current_recoveries=$(echo "${recovery}" | grep -o '"current_as_target":[0-9]*' | cut -d ':' -f 2)
+if [[ "${current_recoveries}" != "0" ]]; then
+  fail "\"current_recoveries\": \"${current_recoveries}\""
+fi
+{{- end }}
+
+exit 0
+`))
@@ -0,0 +1,83 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRenderReadinessProbeScript(t *testing.T) {
+	tests := []struct {
+		name                 string
+		httpPort             int32
+		waitForShardRecovery bool
+	}{
+		{
+			name:                 "ungated",
+			httpPort:             9200,
+			waitForShardRecovery: false,
+		},
+		{
+			name:                 "gated on shard recovery",
+			httpPort:             9200,
+			waitForShardRecovery: true,
+		},
+		{
+			name:                 "custom HTTP port",
+			httpPort:             19200,
+			waitForShardRecovery: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := RenderReadinessProbeScript(tt.httpPort, tt.waitForShardRecovery)
+			require.NoError(t, err)
+			require.Contains(t, script, fmt.Sprintf(`ENDPOINT="${READINESS_PROBE_PROTOCOL:-https}://${LOOPBACK}:%d/"`, tt.httpPort))
+			// both variants check that Elasticsearch responds to HTTP requests
+			require.Contains(t, script, `status=$(curl`)
+			require.Contains(t, script, `fail " \"status\": \"${status}\", \"version\":\"${version}\" "`)
+			require.Contains(t, script, "\nexit 0\n")
+			recoveryCheck := `_nodes/_local/stats/indices/recovery?filter_path=nodes.*.indices.recovery.current_as_target`
+			if tt.waitForShardRecovery {
+				require.Contains(t, script, recoveryCheck)
+				require.Contains(t, script, `if [[ "${current_recoveries}" != "0" ]]; then`)
+			} else {
+				require.NotContains(t, script, recoveryCheck)
+				require.NotContains(t, script, "current_recoveries")
+			}
+		})
+	}
+}
+
+func TestReadinessProbeTimeoutEnvVars(t *testing.T) {
+	tests := []struct {
+		name  string
+		probe *corev1.Probe
+		want  []corev1.EnvVar
+	}{
+		{name: "no probe"},
+		{name: "default probe", probe: NewReadinessProbe()},
+		{name: "unset timeout", probe: &corev1.Probe{}},
+		{
+			name:  "longer timeout",
+			probe: &corev1.Probe{TimeoutSeconds: 30},
+			want:  []corev1.EnvVar{{Name: EnvReadinessProbeTimeout, Value: "28"}},
+		},
+		{
+			name:  "very short timeout",
+			probe: &corev1.Probe{TimeoutSeconds: 1},
+			want:  []corev1.EnvVar{{Name: EnvReadinessProbeTimeout, Value: "1"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, ReadinessProbeTimeoutEnvVars(tt.probe))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+// realmsVolumes returns the volumes of the user-provided secrets referenced by the SAML realms: the metadata of the
+// identity providers and the signing and encryption credentials. Only the expected entries of the secrets are mounted.
+func realmsVolumes(realms esv1.Realms) []volume.VolumeLike {
+	var volumes []volume.VolumeLike
+	for _, realm := range realms.SAML {
+		if realm.IdP.MetadataSecretRef != nil && realm.IdP.MetadataSecretRef.SecretName != "" {
+			volumes = append(volumes, volume.NewSelectiveSecretVolumeWithMountPath(
+				realm.IdP.MetadataSecretRef.SecretName,
+				esvolume.SAMLRealmVolumeName(realm.Name, esvolume.SAMLRealmMetadata),
+				esvolume.SAMLRealmMountPath(realm.Name, esvolume.SAMLRealmMetadata),
+				[]string{esv1.SAMLMetadataSecretKey},
+			))
+		}
+		for _, credentials := range []struct {
+			secret string
+			ref    *esv1.RealmCredentials
+		}{
+			{secret: esvolume.SAMLRealmSigning, ref: realm.Signing},
+			{secret: esvolume.SAMLRealmEncryption, ref: realm.Encryption},
+		} {
+			if credentials.ref == nil || credentials.ref.SecretName == "" {
+				continue
+			}
+			volumes = append(volumes, volume.NewSelectiveSecretVolumeWithMountPath(
+				credentials.ref.SecretName,
+				esvolume.SAMLRealmVolumeName(realm.Name, credentials.secret),
+				esvolume.SAMLRealmMountPath(realm.Name, credentials.secret),
+				[]string{certificates.CertFileName, certificates.KeyFileName},
+			))
+		}
+	}
+	return volumes
+}
@@ -0,0 +1,81 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+)
+
+func Test_realmsVolumes(t *testing.T) {
+	realms := esv1.Realms{
+		SAML: []esv1.SAMLRealm{
+			{
+				Name:       "saml1",
+				IdP:        esv1.SAMLIdentityProvider{MetadataSecretRef: &commonv1.SecretRef{SecretName: "idp-metadata"}},
+				Signing:    &esv1.RealmCredentials{SecretRef: commonv1.SecretRef{SecretName: "saml-signing"}},
+				Encryption: &esv1.RealmCredentials{SecretRef: commonv1.SecretRef{SecretName: "saml-encryption"}},
+			},
+			{
+				// nothing to mount
+				Name: "saml2",
+				IdP:  esv1.SAMLIdentityProvider{MetadataURL: "https://idp.example.com/metadata"},
+			},
+		},
+		OIDC: []esv1.OIDCRealm{{Name: "oidc1", RP: esv1.OIDCRelyingParty{ClientSecretRef: commonv1.SecretRef{SecretName: "oidc-client"}}}},
+	}
+
+	wantVolumes := []corev1.Volume{
+		{
+			Name: "saml-saml1-metadata",
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+				SecretName: "idp-metadata",
+				Items:      []corev1.KeyToPath{{Key: "metadata.xml", Path: "metadata.xml"}},
+			}},
+		},
+		{
+			Name: "saml-saml1-signing",
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+				SecretName: "saml-signing",
+				Items:      []corev1.KeyToPath{{Key: "tls.crt", Path: "tls.crt"}, {Key: "tls.key", Path: "tls.key"}},
+			}},
+		},
+		{
+			Name: "saml-saml1-encryption",
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+				SecretName: "saml-encryption",
+				Items:      []corev1.KeyToPath{{Key: "tls.crt", Path: "tls.crt"}, {Key: "tls.key", Path: "tls.key"}},
+			}},
+		},
+	}
+	wantVolumeMounts := []corev1.VolumeMount{
+		{Name: "saml-saml1-metadata", ReadOnly: true, MountPath: "/usr/share/elasticsearch/config/realms/saml/saml1/metadata"},
+		{Name: "saml-saml1-signing", ReadOnly: true, MountPath: "/usr/share/elasticsearch/config/realms/saml/saml1/signing"},
+		{Name: "saml-saml1-encryption", ReadOnly: true, MountPath: "/usr/share/elasticsearch/config/realms/saml/saml1/encryption"},
+	}
+
+	got := realmsVolumes(realms)
+	require.Len(t, got, len(wantVolumes))
+	for i := range got {
+		require.Equal(t, wantVolumes[i].Name, got[i].Volume().Name)
+		require.Equal(t, wantVolumes[i].Secret.SecretName, got[i].Volume().Secret.SecretName)
+		require.Equal(t, wantVolumes[i].Secret.Items, got[i].Volume().Secret.Items)
+		require.Equal(t, wantVolumeMounts[i], got[i].VolumeMount())
+	}
+
+	// the volumes are mounted in the Elasticsearch container
+	volumes, volumeMounts := buildVolumes("esname", version.MustParse("8.8.0"), esv1.NodeSet{}, nil, volume.DownwardAPI{}, got, nil)
+	for i := range wantVolumes {
+		require.Contains(t, volumes, got[i].Volume())
+		require.Contains(t, volumeMounts, wantVolumeMounts[i])
+	}
+}
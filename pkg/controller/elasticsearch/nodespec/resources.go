@@ -28,6 +28,7 @@ type Resources struct {
 	StatefulSet     appsv1.StatefulSet
 	HeadlessService corev1.Service
 	Config          settings.CanonicalConfig
+	JVMOptions      []string
 }
 
 type ResourcesList []Resources
@@ -81,7 +82,11 @@ func BuildExpectedResources(
 		if nodeSpec.Config != nil {
 			userCfg = *nodeSpec.Config
 		}
-		cfg, err := settings.NewMergedESConfig(es.Name, ver, ipFamily, es.Spec.HTTP, userCfg, policyConfig.ElasticsearchConfig)
+		cacheSize, err := sharedCacheSize(nodeSpec, ver)
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := settings.NewMergedESConfig(es.ClusterName(), ver, ipFamily, es.Spec.HTTP, es.Spec.Ports, es.Spec.Audit, es.Spec.Auth, es.Spec.Discovery, es.NodeAttributes(), cacheSize, userCfg, policyConfig.ElasticsearchConfig)
 		if err != nil {
 			return nil, err
 		}
@@ -98,6 +103,7 @@ func BuildExpectedResources(
 			StatefulSet:     statefulSet,
 			HeadlessService: headlessSvc,
 			Config:          cfg,
+			JVMOptions:      nodeSpec.JVMOptions,
 		})
 	}
 
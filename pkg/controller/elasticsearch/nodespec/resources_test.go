@@ -5,10 +5,17 @@
 package nodespec
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
 func TestResourcesList_MasterNodesNames(t *testing.T) {
@@ -43,3 +50,38 @@ func TestResourcesList_MasterNodesNames(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildExpectedResources_ClusterName(t *testing.T) {
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+	tests := []struct {
+		name        string
+		clusterName string
+		want        string
+	}{
+		{
+			name: "defaults to the name of the Elasticsearch resource",
+			want: sampleES.Name,
+		},
+		{
+			name:        "explicit cluster name",
+			clusterName: "production-logs",
+			want:        "production-logs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := newEsSampleBuilder().build()
+			es.Spec.ClusterName = tt.clusterName
+			resources, err := BuildExpectedResources(context.Background(), client, es, nil, nil, corev1.IPv4Protocol, false)
+			require.NoError(t, err)
+			require.Len(t, resources, len(es.Spec.NodeSets))
+			for i, resource := range resources {
+				clusterName, err := resource.Config.String(esv1.ClusterName)
+				require.NoError(t, err)
+				require.Equal(t, tt.want, clusterName)
+				// the Kubernetes resources keep the name of the Elasticsearch resource
+				require.Equal(t, esv1.StatefulSet(sampleES.Name, es.Spec.NodeSets[i].Name), resource.StatefulSet.Name)
+			}
+		})
+	}
+}
@@ -13,11 +13,9 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
 	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
 	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
@@ -48,7 +46,7 @@ func HeadlessService(es *esv1.Elasticsearch, ssetName string) corev1.Service {
 				{
 					Name:     es.Spec.HTTP.Protocol(),
 					Protocol: corev1.ProtocolTCP,
-					Port:     network.HTTPPort,
+					Port:     es.HTTPPort(),
 				},
 			},
 			// allow nodes to discover themselves via DNS while they are booting up ie. are not ready yet
@@ -73,6 +71,9 @@ func BuildStatefulSet(
 	// ssetSelector is used to match the sset pods
 	ssetSelector := label.NewStatefulSetLabels(k8s.ExtractNamespacedName(&es), statefulSetName)
 
+	// store the data in an emptyDir volume rather than in the default PVC if requested
+	nodeSet = withEphemeralDataVolume(nodeSet)
+
 	// add default PVCs to the node spec only if no user defined PVCs exist
 	nodeSet.VolumeClaimTemplates = defaults.AppendDefaultPVCs(
 		nodeSet.VolumeClaimTemplates,
@@ -80,8 +81,15 @@ func BuildStatefulSet(
 		esvolume.DefaultVolumeClaimTemplates...,
 	)
 
+	// The default heap size is only set in the Pods of new StatefulSets, and of the StatefulSets it is already set in,
+	// not to restart the Pods of the existing clusters when the operator is upgraded.
+	defaultHeapSize := true
+	if existingSset, exists := existingStatefulSets.GetByName(statefulSetName); exists {
+		defaultHeapSize = hasHeapSizeEnv(existingSset.Spec.Template)
+	}
+
 	// build pod template
-	podTemplate, err := BuildPodTemplateSpec(ctx, client, es, nodeSet, cfg, keystoreResources, setDefaultSecurityContext, policyConfig)
+	podTemplate, err := BuildPodTemplateSpec(ctx, client, es, nodeSet, cfg, keystoreResources, setDefaultSecurityContext, defaultHeapSize, policyConfig)
 	if err != nil {
 		return appsv1.StatefulSet{}, err
 	}
@@ -127,7 +135,7 @@ func BuildStatefulSet(
 	}
 
 	// store a hash of the sset resource in its labels for comparison purposes
-	sset.Labels = hash.SetTemplateHashLabel(sset.Labels, sset.Spec)
+	sset.Labels = es_sset.SetTemplateHashLabel(sset.Labels, sset.Spec)
 
 	return sset, nil
 }
@@ -161,5 +169,5 @@ func preserveExistingVolumeClaimsOwnerRefs(
 // and modifies the template hash label accordingly.
 func UpdateReplicas(statefulSet *appsv1.StatefulSet, replicas *int32) {
 	statefulSet.Spec.Replicas = replicas
-	statefulSet.Labels = hash.SetTemplateHashLabel(statefulSet.Labels, statefulSet.Spec)
+	statefulSet.Labels = es_sset.SetTemplateHashLabel(statefulSet.Labels, statefulSet.Spec)
 }
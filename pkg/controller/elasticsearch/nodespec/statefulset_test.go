@@ -5,16 +5,160 @@
 package nodespec
 
 import (
+	"context"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/pod"
 	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
+func TestBuildStatefulSet_VolumeClaimTemplatesStorageClass(t *testing.T) {
+	dataClaim := func(storageClassName *string) []corev1.PersistentVolumeClaim {
+		return []corev1.PersistentVolumeClaim{{
+			ObjectMeta: metav1.ObjectMeta{Name: esvolume.ElasticsearchDataVolumeName},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				StorageClassName: storageClassName,
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			},
+		}}
+	}
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			Version: "8.11.0",
+			NodeSets: []esv1.NodeSet{
+				{Name: "hot", Count: 1, VolumeClaimTemplates: dataClaim(ptr.To("fast-ssd"))},
+				{Name: "cold", Count: 1, VolumeClaimTemplates: dataClaim(ptr.To("standard-hdd"))},
+				{Name: "warm", Count: 1, VolumeClaimTemplates: dataClaim(nil)},
+				{Name: "default", Count: 1},
+			},
+		},
+	}
+	ver := version.MustParse(es.Spec.Version)
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}})
+
+	cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, es.Spec.Ports, es.Spec.Audit, es.Spec.Auth, es.Spec.Discovery, nil, nil, commonv1.Config{}, nil)
+	require.NoError(t, err)
+
+	wantStorageClass := map[string]*string{
+		"hot":  ptr.To("fast-ssd"),
+		"cold": ptr.To("standard-hdd"),
+		// no storage class specified: rely on the cluster default storage class
+		"warm":    nil,
+		"default": nil,
+	}
+	for _, nodeSet := range es.Spec.NodeSets {
+		sset, err := BuildStatefulSet(context.Background(), client, es, nodeSet, cfg, nil, nil, false, PolicyConfig{})
+		require.NoError(t, err)
+		require.Len(t, sset.Spec.VolumeClaimTemplates, 1, nodeSet.Name)
+		claim := sset.Spec.VolumeClaimTemplates[0]
+		require.Equal(t, esvolume.ElasticsearchDataVolumeName, claim.Name, nodeSet.Name)
+		require.Equal(t, wantStorageClass[nodeSet.Name], claim.Spec.StorageClassName, nodeSet.Name)
+		if len(nodeSet.VolumeClaimTemplates) == 0 {
+			// the nodeSet without any claim template gets the default one
+			require.Equal(t, esvolume.DefaultDataVolumeClaim.Spec, claim.Spec)
+		}
+	}
+}
+
+func TestBuildStatefulSet_EphemeralDataVolume(t *testing.T) {
+	sizeLimit := resource.MustParse("50Gi")
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			Version: "8.11.0",
+			NodeSets: []esv1.NodeSet{{
+				Name:                "search",
+				Count:               2,
+				EphemeralDataVolume: &esv1.EphemeralDataVolume{SizeLimit: &sizeLimit, AcknowledgeDataLoss: true},
+			}},
+		},
+	}
+	ver := version.MustParse(es.Spec.Version)
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}})
+	cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, es.Spec.Ports, es.Spec.Audit, es.Spec.Auth, es.Spec.Discovery, nil, nil, commonv1.Config{}, nil)
+	require.NoError(t, err)
+
+	sset, err := BuildStatefulSet(context.Background(), client, es, es.Spec.NodeSets[0], cfg, nil, nil, false, PolicyConfig{})
+	require.NoError(t, err)
+
+	// no PVC is managed for the nodeSet
+	require.Empty(t, sset.Spec.VolumeClaimTemplates)
+	// the data volume is an emptyDir volume with the requested size limit, mounted in the Elasticsearch container
+	var dataVolumes []corev1.Volume
+	for _, v := range sset.Spec.Template.Spec.Volumes {
+		if v.Name == esvolume.ElasticsearchDataVolumeName {
+			dataVolumes = append(dataVolumes, v)
+		}
+	}
+	require.Equal(t, []corev1.Volume{{
+		Name:         esvolume.ElasticsearchDataVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &sizeLimit}},
+	}}, dataVolumes)
+	esContainer := pod.ContainerByName(sset.Spec.Template.Spec, esv1.ElasticsearchContainerName)
+	require.NotNil(t, esContainer)
+	require.Contains(t, esContainer.VolumeMounts, esvolume.DefaultDataVolumeMount)
+	// the nodeSet of the Elasticsearch resource is left untouched
+	require.Empty(t, es.Spec.NodeSets[0].PodTemplate.Spec.Volumes)
+}
+
+func TestBuildStatefulSet_DefaultHeapSize(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			Version:  "7.10.2",
+			NodeSets: []esv1.NodeSet{{Name: "default", Count: 1}},
+		},
+	}
+	ver := version.MustParse(es.Spec.Version)
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}})
+	cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, es.Spec.Ports, es.Spec.Audit, es.Spec.Auth, es.Spec.Discovery, nil, nil, commonv1.Config{}, nil)
+	require.NoError(t, err)
+	build := func(existing es_sset.StatefulSetList) appsv1.StatefulSet {
+		sset, err := BuildStatefulSet(context.Background(), client, es, es.Spec.NodeSets[0], cfg, nil, existing, false, PolicyConfig{})
+		require.NoError(t, err)
+		return sset
+	}
+
+	// new StatefulSet: the default heap size is set
+	newSset := build(nil)
+	require.True(t, hasHeapSizeEnv(newSset.Spec.Template))
+	// and kept in the next reconciliations
+	require.Equal(t, newSset.Labels[hash.TemplateHashLabelName], build(es_sset.StatefulSetList{newSset}).Labels[hash.TemplateHashLabelName])
+
+	// existing StatefulSet created by a previous version of the operator, without the default heap size
+	existingSset := *newSset.DeepCopy()
+	esContainer := pod.ContainerByName(existingSset.Spec.Template.Spec, esv1.ElasticsearchContainerName)
+	require.NotNil(t, esContainer)
+	esContainer.Env = slices.DeleteFunc(esContainer.Env, func(e corev1.EnvVar) bool { return e.Name == settings.EnvEsJavaOpts })
+	existingSset.Labels = es_sset.SetTemplateHashLabel(existingSset.Labels, existingSset.Spec)
+	require.False(t, hasHeapSizeEnv(existingSset.Spec.Template))
+	// the unchanged spec keeps its template hash, the Pods are not restarted
+	actual := build(es_sset.StatefulSetList{existingSset})
+	require.False(t, hasHeapSizeEnv(actual.Spec.Template))
+	require.Equal(t, existingSset.Labels[hash.TemplateHashLabelName], actual.Labels[hash.TemplateHashLabelName])
+}
+
 func Test_setVolumeClaimsControllerReference(t *testing.T) {
 	controllerscheme.SetupScheme()
 	varTrue := true
@@ -133,3 +277,26 @@ func Test_setVolumeClaimsControllerReference(t *testing.T) {
 		})
 	}
 }
+
+func TestHeadlessService(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			NodeSets: []esv1.NodeSet{{Name: "masters"}, {Name: "data"}},
+		},
+	}
+	for _, nodeSet := range es.Spec.NodeSets {
+		ssetName := esv1.StatefulSet(es.Name, nodeSet.Name)
+		svc := HeadlessService(&es, ssetName)
+		// each node set gets its own DNS domain
+		require.Equal(t, ssetName, svc.Name)
+		require.Equal(t, corev1.ClusterIPNone, svc.Spec.ClusterIP)
+		require.True(t, svc.Spec.PublishNotReadyAddresses)
+		// selecting only the Pods of the node set
+		require.Equal(t, map[string]string{
+			label.ClusterNameLabelName:     "es",
+			label.StatefulSetNameLabelName: ssetName,
+			commonv1.TypeLabelName:         label.Type,
+		}, svc.Spec.Selector)
+	}
+}
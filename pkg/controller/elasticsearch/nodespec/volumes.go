@@ -25,6 +25,7 @@ func buildVolumes(
 	nodeSpec esv1.NodeSet,
 	keystoreResources *keystore.Resources,
 	downwardAPIVolume volume.DownwardAPI,
+	realmsVolumes []volume.VolumeLike,
 	additionalMountsFromPolicy []volume.VolumeLike,
 ) ([]corev1.Volume, []corev1.VolumeMount) {
 	configVolume := settings.ConfigSecretVolume(esv1.StatefulSet(esName, nodeSpec.Name))
@@ -121,6 +122,12 @@ func buildVolumes(
 		volumeMounts = append(volumeMounts, fileSettingsVolume.VolumeMount())
 	}
 
+	// user-provided secrets of the authentication realms
+	for _, volume := range realmsVolumes {
+		volumes = append(volumes, volume.Volume())
+		volumeMounts = append(volumeMounts, volume.VolumeMount())
+	}
+
 	// additional volumes from stack config policy
 	for _, volume := range additionalMountsFromPolicy {
 		volumes = append(volumes, volume.Volume())
@@ -132,3 +139,26 @@ func buildVolumes(
 
 	return volumes, volumeMounts
 }
+
+// withEphemeralDataVolume returns a copy of the given NodeSet with an emptyDir data volume declared in its Pod template
+// if the NodeSet has an ephemeral data volume, which prevents the default data volume claim from being added.
+func withEphemeralDataVolume(nodeSet esv1.NodeSet) esv1.NodeSet {
+	if !nodeSet.HasEphemeralDataVolume() {
+		return nodeSet
+	}
+	for _, v := range nodeSet.PodTemplate.Spec.Volumes {
+		if v.Name == esvolume.ElasticsearchDataVolumeName {
+			// rejected by the validation, keep the user-provided volume
+			return nodeSet
+		}
+	}
+	volumes := make([]corev1.Volume, 0, len(nodeSet.PodTemplate.Spec.Volumes)+1)
+	volumes = append(volumes, nodeSet.PodTemplate.Spec.Volumes...)
+	nodeSet.PodTemplate.Spec.Volumes = append(volumes, corev1.Volume{
+		Name: esvolume.ElasticsearchDataVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: nodeSet.EphemeralDataVolume.SizeLimit},
+		},
+	})
+	return nodeSet
+}
@@ -11,6 +11,7 @@ import (
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,10 +39,16 @@ func Reconcile(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch,
 		return deleteDefaultPDB(ctx, k8sClient, es)
 	}
 
+	return ReconcileResource(ctx, k8sClient, &es, expected)
+}
+
+// ReconcileResource reconciles the expected PDB of the given owner. The PDB is converted to v1beta1 if the v1 version
+// of the resource is not available.
+func ReconcileResource(ctx context.Context, k8sClient k8s.Client, owner client.Object, expected *policyv1.PodDisruptionBudget) error {
 	// label the PDB with a hash of its content, for comparison purposes
 	expected.Labels = hash.SetTemplateHashLabel(expected.Labels, expected)
 
-	v1Available, err := isPDBV1Available(k8sClient)
+	v1Available, err := IsV1Available(k8sClient)
 	if err != nil {
 		return err
 	}
@@ -52,7 +59,7 @@ func Reconcile(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch,
 			reconciler.Params{
 				Context:    ctx,
 				Client:     k8sClient,
-				Owner:      &es,
+				Owner:      owner,
 				Expected:   expected,
 				Reconciled: reconciled,
 				NeedsUpdate: func() bool {
@@ -72,7 +79,7 @@ func Reconcile(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch,
 		reconciler.Params{
 			Context:    ctx,
 			Client:     k8sClient,
-			Owner:      &es,
+			Owner:      owner,
 			Expected:   converted,
 			Reconciled: reconciled,
 			NeedsUpdate: func() bool {
@@ -87,10 +94,15 @@ func Reconcile(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch,
 
 // deleteDefaultPDB deletes the default pdb if it exists.
 func deleteDefaultPDB(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch) error {
+	return DeleteIfExists(ctx, k8sClient, types.NamespacedName{Namespace: es.Namespace, Name: esv1.DefaultPodDisruptionBudget(es.Name)})
+}
+
+// DeleteIfExists deletes the PDB with the given name if it exists, in the available version of the resource.
+func DeleteIfExists(ctx context.Context, k8sClient k8s.Client, pdbName types.NamespacedName) error {
 	// we do this by getting first because that is a local cache read,
 	// versus a Delete call, which would hit the API.
 
-	v1Available, err := isPDBV1Available(k8sClient)
+	v1Available, err := IsV1Available(k8sClient)
 	if err != nil {
 		return err
 	}
@@ -98,15 +110,15 @@ func deleteDefaultPDB(ctx context.Context, k8sClient k8s.Client, es esv1.Elastic
 	if v1Available {
 		pdb = &policyv1.PodDisruptionBudget{
 			ObjectMeta: metav1.ObjectMeta{
-				Namespace: es.Namespace,
-				Name:      esv1.DefaultPodDisruptionBudget(es.Name),
+				Namespace: pdbName.Namespace,
+				Name:      pdbName.Name,
 			},
 		}
 	} else {
 		pdb = &policyv1beta1.PodDisruptionBudget{
 			ObjectMeta: metav1.ObjectMeta{
-				Namespace: es.Namespace,
-				Name:      esv1.DefaultPodDisruptionBudget(es.Name),
+				Namespace: pdbName.Namespace,
+				Name:      pdbName.Name,
 			},
 		}
 	}
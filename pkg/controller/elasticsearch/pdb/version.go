@@ -31,7 +31,8 @@ func convert(toConvert *policyv1.PodDisruptionBudget) *policyv1beta1.PodDisrupti
 	return v1beta1
 }
 
-func isPDBV1Available(k8sClient k8s.Client) (bool, error) {
+// IsV1Available returns true if the v1 version of the PodDisruptionBudget resource is available, false if only v1beta1 is.
+func IsV1Available(k8sClient k8s.Client) (bool, error) {
 	isPDBV1Available := getPDBV1Available()
 	if isPDBV1Available != nil {
 		return *isPDBV1Available, nil
@@ -8,9 +8,12 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
@@ -123,6 +126,41 @@ func (s *State) UpdateAvailableNodes(
 	return s
 }
 
+// UpdateNodeSets updates the replica counts of each nodeSet from the status of its StatefulSet. NodeSets are listed in
+// the order of the specification, followed by the ones being removed.
+func (s *State) UpdateNodeSets(resourcesState ResourcesState) *State {
+	statefulSets := make(map[string]appsv1.StatefulSet, len(resourcesState.StatefulSets))
+	for _, statefulSet := range resourcesState.StatefulSets {
+		statefulSets[statefulSet.Name] = statefulSet
+	}
+	nodeSets := make([]esv1.NodeSetStatus, 0, len(s.cluster.Spec.NodeSets))
+	for _, nodeSet := range s.cluster.Spec.NodeSets {
+		ssetName := esv1.StatefulSet(s.cluster.Name, nodeSet.Name)
+		statefulSet := statefulSets[ssetName]
+		delete(statefulSets, ssetName)
+		nodeSets = append(nodeSets, esv1.NodeSetStatus{
+			Name:            nodeSet.Name,
+			DesiredReplicas: nodeSet.Count,
+			CurrentReplicas: statefulSet.Status.Replicas,
+			ReadyReplicas:   statefulSet.Status.ReadyReplicas,
+		})
+	}
+	// StatefulSets left over are the ones of nodeSets being removed
+	for _, statefulSet := range resourcesState.StatefulSets {
+		if _, removed := statefulSets[statefulSet.Name]; !removed {
+			continue
+		}
+		nodeSets = append(nodeSets, esv1.NodeSetStatus{
+			Name:            strings.TrimPrefix(statefulSet.Name, esv1.ESNamer.Suffix(s.cluster.Name)+"-"),
+			DesiredReplicas: 0,
+			CurrentReplicas: statefulSet.Status.Replicas,
+			ReadyReplicas:   statefulSet.Status.ReadyReplicas,
+		})
+	}
+	s.status.NodeSets = nodeSets
+	return s
+}
+
 func (s *State) UpdateMinRunningVersion(
 	ctx context.Context,
 	resourcesState ResourcesState,
@@ -132,37 +170,107 @@ func (s *State) UpdateMinRunningVersion(
 	if err == nil && lowestVersion != nil {
 		s.status.Version = lowestVersion.String()
 	}
-	// Update the related condition.
+	// Update the related conditions.
 	if s.status.Version == "" {
-		s.ReportCondition(esv1.RunningDesiredVersion, corev1.ConditionUnknown, "No running version reported")
+		s.reportRunningVersion(corev1.ConditionUnknown, "No running version reported")
 		return s
 	}
 
 	desiredVersion, err := version.Parse(s.cluster.Spec.Version)
 	if err != nil {
-		s.ReportCondition(esv1.RunningDesiredVersion, corev1.ConditionUnknown, fmt.Sprintf("Error while parsing desired version: %s", err.Error()))
+		s.reportRunningVersion(corev1.ConditionUnknown, fmt.Sprintf("Error while parsing desired version: %s", err.Error()))
 		return s
 	}
 
 	runningVersion, err := version.Parse(s.status.Version)
 	if err != nil {
-		s.ReportCondition(esv1.RunningDesiredVersion, corev1.ConditionUnknown, fmt.Sprintf("Error while parsing running version: %s", err.Error()))
+		s.reportRunningVersion(corev1.ConditionUnknown, fmt.Sprintf("Error while parsing running version: %s", err.Error()))
 		return s
 	}
 
 	if desiredVersion.GT(runningVersion) {
-		s.ReportCondition(
-			esv1.RunningDesiredVersion,
+		s.reportRunningVersion(
 			corev1.ConditionFalse,
 			fmt.Sprintf("Upgrading from %s to %s", runningVersion.String(), desiredVersion.String()),
 		)
 		return s
 	}
-	s.ReportCondition(esv1.RunningDesiredVersion, corev1.ConditionTrue, fmt.Sprintf("All nodes are running version %s", runningVersion))
+	s.reportRunningVersion(corev1.ConditionTrue, fmt.Sprintf("All nodes are running version %s", runningVersion))
 
 	return s
 }
 
+// reportRunningVersion reports the RunningDesiredVersion condition with the given status, and the Upgrading condition
+// with the opposite one.
+func (s *State) reportRunningVersion(status corev1.ConditionStatus, message string) {
+	s.ReportCondition(esv1.RunningDesiredVersion, status, message)
+	switch status {
+	case corev1.ConditionTrue:
+		s.ReportConditionWithReason(esv1.Upgrading, corev1.ConditionFalse, esv1.DesiredVersionRunningReason, message)
+	case corev1.ConditionFalse:
+		s.ReportConditionWithReason(esv1.Upgrading, corev1.ConditionTrue, esv1.UpgradeInProgressReason, message)
+	default:
+		s.ReportConditionWithReason(esv1.Upgrading, corev1.ConditionUnknown, esv1.RunningVersionUnknownReason, message)
+	}
+}
+
+// ReportBootstrapping reports the Bootstrapping condition depending on whether the cluster has already been formed.
+func (s *State) ReportBootstrapping(bootstrapped bool) {
+	if bootstrapped {
+		s.ReportConditionWithReason(esv1.Bootstrapping, corev1.ConditionFalse, esv1.ClusterBootstrappedReason, "Cluster bootstrapped")
+		return
+	}
+	s.ReportConditionWithReason(esv1.Bootstrapping, corev1.ConditionTrue, esv1.ClusterBootstrappingReason, "Waiting for the nodes to form the cluster")
+}
+
+// ReportReconciliationOutcome reports the ReconciliationError and Ready conditions given the error returned by the
+// reconciliation, if any. It must be called once the phase and the other conditions of the reconciliation are reported.
+func (s *State) ReportReconciliationOutcome(err error) {
+	if err != nil {
+		s.ReportConditionWithReason(esv1.ReconciliationError, corev1.ConditionTrue, esv1.ReconciliationFailedReason, err.Error())
+		s.ReportConditionWithReason(esv1.Ready, corev1.ConditionFalse, esv1.ReconciliationFailedReason, "Reconciliation failed")
+		return
+	}
+	s.ReportConditionWithReason(esv1.ReconciliationError, corev1.ConditionFalse, esv1.ReconciliationSucceededReason, "")
+
+	switch {
+	case s.hasCondition(esv1.Bootstrapping, corev1.ConditionTrue):
+		s.ReportConditionWithReason(esv1.Ready, corev1.ConditionFalse, esv1.ClusterBootstrappingReason, "Waiting for the nodes to form the cluster")
+	case s.hasCondition(esv1.Upgrading, corev1.ConditionTrue):
+		s.ReportConditionWithReason(esv1.Ready, corev1.ConditionFalse, esv1.UpgradeInProgressReason, s.conditionMessage(esv1.Upgrading))
+	case s.status.Phase != esv1.ElasticsearchReadyPhase:
+		// the phase is already a machine-readable reason, e.g. ApplyingChanges or MigratingData
+		s.ReportConditionWithReason(esv1.Ready, corev1.ConditionFalse, string(s.status.Phase), s.conditionMessage(esv1.ReconciliationComplete))
+	case s.status.Health != esv1.ElasticsearchGreenHealth:
+		s.ReportConditionWithReason(esv1.Ready, corev1.ConditionFalse, esv1.ClusterNotHealthyReason, fmt.Sprintf("Cluster health is %s", s.status.Health))
+	default:
+		s.ReportConditionWithReason(esv1.Ready, corev1.ConditionTrue, esv1.ClusterReadyReason, "")
+	}
+}
+
+// hasCondition returns true if the condition of the given type has been reported with the given status during this
+// reconciliation, or in a previous one otherwise.
+func (s *State) hasCondition(conditionType commonv1alpha1.ConditionType, status corev1.ConditionStatus) bool {
+	condition, exists := s.condition(conditionType)
+	return exists && condition.Status == status
+}
+
+// conditionMessage returns the message of the condition of the given type, if any.
+func (s *State) conditionMessage(conditionType commonv1alpha1.ConditionType) string {
+	condition, _ := s.condition(conditionType)
+	return condition.Message
+}
+
+// condition returns the condition of the given type reported during this reconciliation, or in a previous one otherwise.
+func (s *State) condition(conditionType commonv1alpha1.ConditionType) (commonv1alpha1.Condition, bool) {
+	for _, conditions := range []commonv1alpha1.Conditions{s.Conditions, s.status.Conditions} {
+		if i := conditions.Index(conditionType); i >= 0 {
+			return conditions[i], true
+		}
+	}
+	return commonv1alpha1.Condition{}, false
+}
+
 // UpdateElasticsearchInvalidWithEvent is a convenient method to set the phase to esv1.ElasticsearchResourceInvalid
 // and generate an event at the same time.
 func (s *State) UpdateElasticsearchInvalidWithEvent(msg string) {
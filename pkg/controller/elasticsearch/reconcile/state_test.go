@@ -6,6 +6,7 @@ package reconcile
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
@@ -468,6 +469,187 @@ func TestState_UpdateMinRunningVersion(t *testing.T) {
 	}
 }
 
+func TestState_UpdateNodeSets(t *testing.T) {
+	sset := func(name string, replicas, readyReplicas int32) appsv1.StatefulSet {
+		return appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Status:     appsv1.StatefulSetStatus{Replicas: replicas, ReadyReplicas: readyReplicas},
+		}
+	}
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{
+			{Name: "hot", Count: 3},
+			{Name: "cold", Count: 2},
+			{Name: "frozen", Count: 1},
+		}},
+	}
+	tests := []struct {
+		name         string
+		statefulSets []appsv1.StatefulSet
+		want         []esv1.NodeSetStatus
+	}{
+		{
+			name:         "no StatefulSet created yet",
+			statefulSets: nil,
+			want: []esv1.NodeSetStatus{
+				{Name: "hot", DesiredReplicas: 3},
+				{Name: "cold", DesiredReplicas: 2},
+				{Name: "frozen", DesiredReplicas: 1},
+			},
+		},
+		{
+			name: "cold tier lagging behind",
+			statefulSets: []appsv1.StatefulSet{
+				sset("es-es-cold", 2, 1),
+				sset("es-es-hot", 3, 3),
+				sset("es-es-frozen", 1, 1),
+			},
+			want: []esv1.NodeSetStatus{
+				{Name: "hot", DesiredReplicas: 3, CurrentReplicas: 3, ReadyReplicas: 3},
+				{Name: "cold", DesiredReplicas: 2, CurrentReplicas: 2, ReadyReplicas: 1},
+				{Name: "frozen", DesiredReplicas: 1, CurrentReplicas: 1, ReadyReplicas: 1},
+			},
+		},
+		{
+			name: "nodeSet being removed",
+			statefulSets: []appsv1.StatefulSet{
+				sset("es-es-hot", 3, 3),
+				sset("es-es-warm", 2, 2),
+				sset("es-es-cold", 1, 1),
+			},
+			want: []esv1.NodeSetStatus{
+				{Name: "hot", DesiredReplicas: 3, CurrentReplicas: 3, ReadyReplicas: 3},
+				{Name: "cold", DesiredReplicas: 2, CurrentReplicas: 1, ReadyReplicas: 1},
+				{Name: "frozen", DesiredReplicas: 1},
+				{Name: "warm", DesiredReplicas: 0, CurrentReplicas: 2, ReadyReplicas: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := MustNewState(es)
+			s.UpdateNodeSets(ResourcesState{StatefulSets: tt.statefulSets})
+			_, updated := s.Apply()
+			assert.NotNil(t, updated)
+			assert.Equal(t, tt.want, updated.Status.NodeSets)
+		})
+	}
+}
+
+func TestState_ReportReconciliationOutcome(t *testing.T) {
+	podWithVersion := func(value string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{label.VersionLabelName: value}}}
+	}
+	type step struct {
+		name           string
+		bootstrapped   bool
+		resourcesState ResourcesState
+		health         esv1.ElasticsearchHealth
+		reconciled     bool
+		err            error
+		// expected status and reason of the conditions
+		want map[commonv1alpha1.ConditionType][2]string
+	}
+	steps := []step{
+		{
+			name:       "new cluster bootstrapping",
+			health:     esv1.ElasticsearchUnknownHealth,
+			reconciled: false,
+			want: map[commonv1alpha1.ConditionType][2]string{
+				esv1.Bootstrapping:       {"True", esv1.ClusterBootstrappingReason},
+				esv1.Upgrading:           {"Unknown", esv1.RunningVersionUnknownReason},
+				esv1.ReconciliationError: {"False", esv1.ReconciliationSucceededReason},
+				esv1.Ready:               {"False", esv1.ClusterBootstrappingReason},
+			},
+		},
+		{
+			name:           "reconciliation error while bootstrapping",
+			resourcesState: ResourcesState{AllPods: []corev1.Pod{podWithVersion("8.12.0")}},
+			health:         esv1.ElasticsearchRedHealth,
+			err:            errors.New("boom"),
+			want: map[commonv1alpha1.ConditionType][2]string{
+				esv1.Bootstrapping:       {"True", esv1.ClusterBootstrappingReason},
+				esv1.Upgrading:           {"False", esv1.DesiredVersionRunningReason},
+				esv1.ReconciliationError: {"True", esv1.ReconciliationFailedReason},
+				esv1.Ready:               {"False", esv1.ReconciliationFailedReason},
+			},
+		},
+		{
+			name:           "cluster bootstrapped but not healthy yet",
+			bootstrapped:   true,
+			resourcesState: ResourcesState{AllPods: []corev1.Pod{podWithVersion("8.12.0")}},
+			health:         esv1.ElasticsearchYellowHealth,
+			reconciled:     true,
+			want: map[commonv1alpha1.ConditionType][2]string{
+				esv1.Bootstrapping:       {"False", esv1.ClusterBootstrappedReason},
+				esv1.Upgrading:           {"False", esv1.DesiredVersionRunningReason},
+				esv1.ReconciliationError: {"False", esv1.ReconciliationSucceededReason},
+				esv1.Ready:               {"False", esv1.ClusterNotHealthyReason},
+			},
+		},
+		{
+			name:           "cluster ready",
+			bootstrapped:   true,
+			resourcesState: ResourcesState{AllPods: []corev1.Pod{podWithVersion("8.12.0")}},
+			health:         esv1.ElasticsearchGreenHealth,
+			reconciled:     true,
+			want: map[commonv1alpha1.ConditionType][2]string{
+				esv1.Bootstrapping:       {"False", esv1.ClusterBootstrappedReason},
+				esv1.Upgrading:           {"False", esv1.DesiredVersionRunningReason},
+				esv1.ReconciliationError: {"False", esv1.ReconciliationSucceededReason},
+				esv1.Ready:               {"True", esv1.ClusterReadyReason},
+			},
+		},
+		{
+			name:           "cluster upgrading",
+			bootstrapped:   true,
+			resourcesState: ResourcesState{AllPods: []corev1.Pod{podWithVersion("8.12.0"), podWithVersion("8.11.0")}},
+			health:         esv1.ElasticsearchGreenHealth,
+			reconciled:     false,
+			want: map[commonv1alpha1.ConditionType][2]string{
+				esv1.Bootstrapping:       {"False", esv1.ClusterBootstrappedReason},
+				esv1.Upgrading:           {"True", esv1.UpgradeInProgressReason},
+				esv1.ReconciliationError: {"False", esv1.ReconciliationSucceededReason},
+				esv1.Ready:               {"False", esv1.UpgradeInProgressReason},
+			},
+		},
+	}
+
+	es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.12.0"}}
+	var previous commonv1alpha1.Conditions
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			s := MustNewState(es)
+			s.ReportBootstrapping(step.bootstrapped)
+			s.UpdateClusterHealth(step.health).UpdateMinRunningVersion(context.Background(), step.resourcesState)
+			if step.reconciled {
+				s.UpdateWithPhase(esv1.ElasticsearchReadyPhase)
+			} else {
+				s.UpdateWithPhase(esv1.ElasticsearchApplyingChangesPhase)
+			}
+			s.ReportReconciliationOutcome(step.err)
+			_, updated := s.Apply()
+			assert.NotNil(t, updated)
+
+			conditions := updated.Status.Conditions
+			for conditionType, want := range step.want {
+				i := conditions.Index(conditionType)
+				if !assert.GreaterOrEqual(t, i, 0, "missing condition %s", conditionType) {
+					continue
+				}
+				assert.Equal(t, want, [2]string{string(conditions[i].Status), conditions[i].Reason}, "condition %s", conditionType)
+				// the transition time is only updated along with the condition
+				if j := previous.Index(conditionType); j >= 0 && previous[j].Status == conditions[i].Status && previous[j].Reason == conditions[i].Reason && previous[j].Message == conditions[i].Message {
+					assert.Equal(t, previous[j].LastTransitionTime, conditions[i].LastTransitionTime, "condition %s", conditionType)
+				}
+			}
+			previous = conditions
+			es = *updated
+		})
+	}
+}
+
 func conditionsEqual(c1, c2 commonv1alpha1.Condition) bool {
 	return c1.Message == c2.Message &&
 		c1.Type == c2.Type &&
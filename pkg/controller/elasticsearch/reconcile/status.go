@@ -55,6 +55,22 @@ func (s *StatusReporter) ReportCondition(
 	})
 }
 
+// ReportConditionWithReason records a condition with a machine-readable reason to be reported in the status.
+// Any existing condition with the same Type is overridden.
+func (s *StatusReporter) ReportConditionWithReason(
+	conditionType commonv1alpha1.ConditionType,
+	status corev1.ConditionStatus,
+	reason string,
+	message string) {
+	s.Conditions = s.Conditions.MergeWith(commonv1alpha1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: metav1.Now(),
+		Message:            message,
+	})
+}
+
 // -- Upscale status
 
 type UpscaleReporter struct {
@@ -10,7 +10,10 @@ import (
 
 	"go.elastic.co/apm/v2"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
@@ -22,7 +25,12 @@ import (
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 )
 
-const enterpriseFeaturesDisabledMsg = "Remote cluster is an enterprise feature. Enterprise features are disabled"
+const (
+	enterpriseFeaturesDisabledMsg = "Remote cluster is an enterprise feature. Enterprise features are disabled"
+
+	// proxyMode is the connection mode of remote clusters reached through a single address, such as a load balancer.
+	proxyMode = "proxy"
+)
 
 // UpdateSettings updates the remote clusters in the persistent settings by calling the Elasticsearch API.
 // A boolean is returned to indicate if a requeue should be scheduled to sync the annotation on the Elasticsearch object
@@ -114,15 +122,18 @@ func updateSettingsInternal(
 	for name, remoteCluster := range remoteClustersInSpec {
 		remoteClustersToUpdate = append(remoteClustersToUpdate, name)
 		// Declare remote cluster in ES
-		seedHosts := []string{services.ExternalTransportServiceHost(remoteCluster.ElasticsearchRef.NamespacedName())}
-		remoteClustersToApply[name] = esclient.RemoteCluster{Seeds: seedHosts}
+		remoteClusterSettings, err := getRemoteClusterSettings(ctx, c, remoteCluster)
+		if err != nil {
+			return true, err
+		}
+		remoteClustersToApply[name] = withProxyModeReset(remoteClusterSettings, remoteClustersInEs[name])
 		// Ensure this cluster is tracked in the annotation
 		remoteClustersInAnnotation[name] = struct{}{}
 	}
 
 	// RemoteClusters to remove from Elasticsearch
 	for _, name := range remoteClustersToDelete {
-		remoteClustersToApply[name] = esclient.RemoteCluster{Seeds: nil}
+		remoteClustersToApply[name] = withProxyModeReset(esclient.RemoteCluster{Seeds: nil}, remoteClustersInEs[name])
 	}
 
 	// Update the annotation
@@ -149,23 +160,37 @@ func updateSettingsInternal(
 }
 
 // getRemoteClustersInElasticsearch returns all the remote clusters currently declared in Elasticsearch
-func getRemoteClustersInElasticsearch(ctx context.Context, esClient esclient.Client) (map[string]struct{}, error) {
-	remoteClustersInEs := make(map[string]struct{})
+func getRemoteClustersInElasticsearch(ctx context.Context, esClient esclient.Client) (map[string]esclient.RemoteCluster, error) {
+	remoteClustersInEs := make(map[string]esclient.RemoteCluster)
 	remoteClusterSettings, err := esClient.GetRemoteClusterSettings(ctx)
 	if err != nil {
 		return remoteClustersInEs, err
 	}
-	for remoteClusterName := range remoteClusterSettings.PersistentSettings.Cluster.RemoteClusters {
-		remoteClustersInEs[remoteClusterName] = struct{}{}
+	for remoteClusterName, remoteCluster := range remoteClusterSettings.PersistentSettings.Cluster.RemoteClusters {
+		remoteClustersInEs[remoteClusterName] = remoteCluster
 	}
 	return remoteClustersInEs, nil
 }
 
+// withProxyModeReset returns the given remote cluster settings, resetting the proxy mode settings if the remote cluster
+// is currently connected in proxy mode in Elasticsearch but is not expected to be anymore. The proxy mode settings are
+// omitted otherwise, as they are not supported before Elasticsearch 7.7.0.
+func withProxyModeReset(expected esclient.RemoteCluster, current esclient.RemoteCluster) esclient.RemoteCluster {
+	if expected.Mode == nil && current.Mode != nil && *current.Mode == proxyMode {
+		expected.ResetProxyMode = true
+	}
+	return expected
+}
+
 // getRemoteClustersInSpec returns a map with the expected remote clusters as declared by the user in the Elasticsearch specification.
 // A map is returned here because it will be used to quickly compare with the ones that are new or missing.
 func getRemoteClustersInSpec(es esv1.Elasticsearch) map[string]esv1.RemoteCluster {
 	remoteClusters := make(map[string]esv1.RemoteCluster)
 	for _, remoteCluster := range es.Spec.RemoteClusters {
+		if remoteCluster.IsExternal() {
+			remoteClusters[remoteCluster.Name] = remoteCluster
+			continue
+		}
 		if !remoteCluster.ElasticsearchRef.IsDefined() {
 			continue
 		}
@@ -185,3 +210,31 @@ func updateSettings(ctx context.Context, esClient esclient.Client, remoteCluster
 		},
 	})
 }
+
+// getRemoteClusterSettings returns the settings to connect to the given remote cluster: in proxy mode through the
+// user-provided address for a cluster running outside of this k8s cluster, or in sniff mode through the transport
+// Service of the referenced Elasticsearch cluster otherwise.
+func getRemoteClusterSettings(ctx context.Context, c k8s.Client, remoteCluster esv1.RemoteCluster) (esclient.RemoteCluster, error) {
+	if remoteCluster.IsExternal() {
+		return esclient.RemoteCluster{Mode: ptr.To(proxyMode), ProxyAddress: ptr.To(remoteCluster.ExternalAddress)}, nil
+	}
+	remoteEs, err := getRemoteElasticsearch(ctx, c, remoteCluster)
+	if err != nil {
+		return esclient.RemoteCluster{}, err
+	}
+	return esclient.RemoteCluster{Seeds: []string{services.ExternalTransportServiceHost(remoteEs)}}, nil
+}
+
+// getRemoteElasticsearch returns the remote Elasticsearch resource referenced by the given remote cluster, to retrieve
+// its transport port. If it does not exist (yet), a resource with only its name and namespace set is returned.
+func getRemoteElasticsearch(ctx context.Context, c k8s.Client, remoteCluster esv1.RemoteCluster) (esv1.Elasticsearch, error) {
+	nsn := remoteCluster.ElasticsearchRef.NamespacedName()
+	var remoteEs esv1.Elasticsearch
+	if err := c.Get(ctx, nsn, &remoteEs); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return esv1.Elasticsearch{}, err
+		}
+		remoteEs = esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: nsn.Namespace, Name: nsn.Name}}
+	}
+	return remoteEs, nil
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -115,6 +117,7 @@ func TestUpdateSettings(t *testing.T) {
 	type args struct {
 		esClient       *fakeESClient
 		es             *esv1.Elasticsearch
+		remoteEs       *esv1.Elasticsearch
 		licenseChecker license.Checker
 	}
 	tests := []struct {
@@ -200,6 +203,66 @@ func TestUpdateSettings(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Create a new remote cluster with a custom transport port",
+			args: args{
+				esClient:       &fakeESClient{existingSettings: emptySettings},
+				licenseChecker: &license.MockLicenseChecker{EnterpriseEnabled: true},
+				es: newEsWithRemoteClusters(
+					"ns1",
+					"es1",
+					nil,
+					esv1.RemoteCluster{
+						Name:             "ns2-es2",
+						ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es2", Namespace: "ns2"},
+					},
+				),
+				remoteEs: &esv1.Elasticsearch{
+					ObjectMeta: metav1.ObjectMeta{Name: "es2", Namespace: "ns2"},
+					Spec:       esv1.ElasticsearchSpec{Ports: esv1.PortsConfig{Transport: 9400}},
+				},
+			},
+			wantAnnotation:                        "ns2-es2",
+			wantGetRemoteClusterSettingsCalled:    true,
+			wantUpdateRemoteClusterSettingsCalled: true,
+			wantSettings: esclient.RemoteClustersSettings{
+				PersistentSettings: &esclient.SettingsGroup{
+					Cluster: esclient.RemoteClusters{
+						RemoteClusters: map[string]esclient.RemoteCluster{
+							"ns2-es2": {Seeds: []string{"es2-es-transport.ns2.svc:9400"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Create a new remote cluster running outside of the k8s cluster",
+			args: args{
+				esClient:       &fakeESClient{existingSettings: emptySettings},
+				licenseChecker: &license.MockLicenseChecker{EnterpriseEnabled: true},
+				es: newEsWithRemoteClusters(
+					"ns1",
+					"es1",
+					nil,
+					esv1.RemoteCluster{
+						Name:            "external",
+						ExternalAddress: "es.example.com:9300",
+					},
+				),
+			},
+			wantAnnotation:                        "external",
+			wantGetRemoteClusterSettingsCalled:    true,
+			wantUpdateRemoteClusterSettingsCalled: true,
+			wantSettings: esclient.RemoteClustersSettings{
+				PersistentSettings: &esclient.SettingsGroup{
+					Cluster: esclient.RemoteClusters{
+						RemoteClusters: map[string]esclient.RemoteCluster{
+							"external": {Mode: ptr.To("proxy"), ProxyAddress: ptr.To("es.example.com:9300")},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "Create a new remote cluster with no namespace",
 			args: args{
@@ -396,6 +459,48 @@ func TestUpdateSettings(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Reset the proxy mode of remote clusters previously connected in proxy mode",
+			args: args{
+				esClient: &fakeESClient{
+					existingSettings: esclient.RemoteClustersSettings{
+						PersistentSettings: &esclient.SettingsGroup{
+							Cluster: esclient.RemoteClusters{
+								RemoteClusters: map[string]esclient.RemoteCluster{
+									"ns1-es2":       {Mode: ptr.To("proxy"), ProxyAddress: ptr.To("es2.example.com:9300")},
+									"to-be-deleted": {Mode: ptr.To("proxy"), ProxyAddress: ptr.To("somewhere:9300")},
+								},
+							},
+						},
+					},
+				},
+				licenseChecker: &license.MockLicenseChecker{EnterpriseEnabled: true},
+				es: newEsWithRemoteClusters(
+					"ns1",
+					"es1",
+					map[string]string{
+						"elasticsearch.k8s.elastic.co/managed-remote-clusters": `ns1-es2,to-be-deleted`,
+					},
+					esv1.RemoteCluster{
+						Name:             "ns1-es2",
+						ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es2"},
+					}),
+			},
+			wantRequeue:                           true,
+			wantAnnotation:                        "ns1-es2,to-be-deleted",
+			wantGetRemoteClusterSettingsCalled:    true,
+			wantUpdateRemoteClusterSettingsCalled: true,
+			wantSettings: esclient.RemoteClustersSettings{
+				PersistentSettings: &esclient.SettingsGroup{
+					Cluster: esclient.RemoteClusters{
+						RemoteClusters: map[string]esclient.RemoteCluster{
+							"ns1-es2":       {Seeds: []string{"es2-es-transport.ns1.svc:9300"}, ResetProxyMode: true},
+							"to-be-deleted": {Seeds: nil, ResetProxyMode: true},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "No valid license to create a new remote cluster",
 			args: args{
@@ -481,7 +586,11 @@ func TestUpdateSettings(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client := k8s.NewFakeClient(tt.args.es)
+			objs := []client.Object{tt.args.es}
+			if tt.args.remoteEs != nil {
+				objs = append(objs, tt.args.remoteEs)
+			}
+			client := k8s.NewFakeClient(objs...)
 			shouldRequeue, err := UpdateSettings(
 				context.Background(),
 				client,
@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package remotecluster
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// RequestsForReferencingClusters returns an event handler which enqueues the Elasticsearch clusters declaring the
+// Elasticsearch cluster of the event as a remote cluster, so that their seeds are updated when the remote changes.
+func RequestsForReferencingClusters(c k8s.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, object client.Object) []reconcile.Request {
+		return referencingClusters(ctx, c, k8s.ExtractNamespacedName(object))
+	})
+}
+
+func referencingClusters(ctx context.Context, c k8s.Client, remote types.NamespacedName) []reconcile.Request {
+	var esList esv1.ElasticsearchList
+	if err := c.List(ctx, &esList); err != nil {
+		ulog.FromContext(ctx).Error(err, "Fail to list Elasticsearch clusters while watching remote clusters")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, es := range esList.Items {
+		es := es
+		for _, remoteCluster := range getRemoteClustersInSpec(es) {
+			if remoteCluster.ElasticsearchRef.NamespacedName() == remote {
+				requests = append(requests, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&es)})
+				break
+			}
+		}
+	}
+	return requests
+}
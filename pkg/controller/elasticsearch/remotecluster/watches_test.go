@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package remotecluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_referencingClusters(t *testing.T) {
+	newES := func(namespace, name string, remotes ...esv1.RemoteCluster) *esv1.Elasticsearch {
+		return &esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       esv1.ElasticsearchSpec{RemoteClusters: remotes},
+		}
+	}
+	c := k8s.NewFakeClient(
+		newES("ns1", "remote"),
+		// reference in the same namespace
+		newES("ns1", "local1", esv1.RemoteCluster{Name: "r", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "remote"}}),
+		// reference across namespaces
+		newES("ns2", "local2", esv1.RemoteCluster{Name: "r", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "remote", Namespace: "ns1"}}),
+		// reference to another cluster with the same name in another namespace
+		newES("ns2", "local3", esv1.RemoteCluster{Name: "r", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "remote"}}),
+	)
+
+	requests := referencingClusters(context.Background(), c, types.NamespacedName{Namespace: "ns1", Name: "remote"})
+	require.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "local1"}},
+		{NamespacedName: types.NamespacedName{Namespace: "ns2", Name: "local2"}},
+	}, requests)
+
+	require.Empty(t, referencingClusters(context.Background(), c, types.NamespacedName{Namespace: "ns1", Name: "local1"}))
+}
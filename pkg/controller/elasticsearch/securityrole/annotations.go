@@ -0,0 +1,54 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package securityrole
+
+import (
+	"context"
+	"strings"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	// ManagedSecurityRolesAnnotationName holds the list of the roles which have been created by the operator
+	ManagedSecurityRolesAnnotationName = "elasticsearch.k8s.elastic.co/managed-security-roles"
+	// ManagedRoleMappingsAnnotationName holds the list of the role mappings which have been created by the operator
+	ManagedRoleMappingsAnnotationName = "elasticsearch.k8s.elastic.co/managed-role-mappings"
+)
+
+// getNamesInAnnotation returns the set of names stored in the given annotation of the Elasticsearch resource.
+// If there's no name the set is empty but not nil.
+func getNamesInAnnotation(es esv1.Elasticsearch, annotation string) set.StringSet {
+	names := set.Make()
+	serializedNames, ok := es.Annotations[annotation]
+	if !ok || strings.TrimSpace(serializedNames) == "" {
+		return names
+	}
+	for _, name := range strings.Split(serializedNames, ",") {
+		names.Add(name)
+	}
+	return names
+}
+
+// annotateWithNames stores the given set of names in the given annotation of the Elasticsearch resource, or removes
+// the annotation if the set is empty. The annotation is patched, not to conflict with other updates of the
+// Elasticsearch resource during the reconciliation, and is not patched if already up to date.
+func annotateWithNames(ctx context.Context, c k8s.Client, es *esv1.Elasticsearch, annotation string, names set.StringSet) error {
+	current, exists := es.Annotations[annotation]
+	if names.Count() == 0 {
+		if !exists {
+			return nil
+		}
+		return k8s.PatchAnnotation(ctx, c, es, annotation, nil)
+	}
+
+	expected := strings.Join(names.AsSortedSlice(), ",")
+	if exists && current == expected {
+		return nil
+	}
+	return k8s.PatchAnnotation(ctx, c, es, annotation, &expected)
+}
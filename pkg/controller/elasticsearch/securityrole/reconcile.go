@@ -0,0 +1,213 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package securityrole
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.elastic.co/apm/v2"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// definitions maps the names of roles or role mappings to their definition.
+type definitions map[string]map[string]interface{}
+
+// kind describes how to reconcile either the roles or the role mappings through the Elasticsearch security API.
+type kind struct {
+	name       string
+	annotation string
+	declared   []declared
+	get        func(ctx context.Context) (definitions, error)
+	update     func(ctx context.Context, name string, definition map[string]interface{}) error
+	delete     func(ctx context.Context, name string) error
+}
+
+// declared is a role or a role mapping declared in the Elasticsearch specification.
+type declared struct {
+	name       string
+	definition *commonv1.Config
+}
+
+// Reconcile creates the roles and role mappings declared in the Elasticsearch specification through the Elasticsearch
+// security API, and updates the ones whose definition drifted from the specification. The roles and role mappings
+// created by the operator are tracked in annotations of the Elasticsearch resource, so that the ones removed from the
+// specification can be deleted from Elasticsearch if PruneSecurityRoles is enabled, without removing the ones created
+// by users. Roles are reconciled before role mappings, which may reference them. The given Elasticsearch resource is
+// updated in place with the patched annotations.
+func Reconcile(ctx context.Context, c k8s.Client, esClient esclient.SecurityRoleClient, es *esv1.Elasticsearch) error {
+	roles := kind{
+		name:       "role",
+		annotation: ManagedSecurityRolesAnnotationName,
+		get: func(ctx context.Context) (definitions, error) {
+			existing, err := esClient.GetSecurityRoles(ctx)
+			result := make(definitions, len(existing))
+			for name, role := range existing {
+				result[name] = role
+			}
+			return result, err
+		},
+		update: func(ctx context.Context, name string, definition map[string]interface{}) error {
+			return esClient.UpdateSecurityRole(ctx, name, definition)
+		},
+		delete: esClient.DeleteSecurityRole,
+	}
+	for _, role := range es.Spec.SecurityRoles {
+		roles.declared = append(roles.declared, declared{name: role.Name, definition: role.Definition})
+	}
+
+	mappings := kind{
+		name:       "role mapping",
+		annotation: ManagedRoleMappingsAnnotationName,
+		get: func(ctx context.Context) (definitions, error) {
+			existing, err := esClient.GetRoleMappings(ctx)
+			result := make(definitions, len(existing))
+			for name, mapping := range existing {
+				result[name] = mapping
+			}
+			return result, err
+		},
+		update: func(ctx context.Context, name string, definition map[string]interface{}) error {
+			return esClient.UpdateRoleMapping(ctx, name, definition)
+		},
+		delete: esClient.DeleteRoleMapping,
+	}
+	for _, mapping := range es.Spec.RoleMappings {
+		mappings.declared = append(mappings.declared, declared{name: mapping.Name, definition: mapping.Definition})
+	}
+
+	for _, k := range []kind{roles, mappings} {
+		if err := reconcileKind(ctx, c, es, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileKind reconciles either the roles or the role mappings with the following algorithm:
+//  1. Ensure that all the declared ones are tracked in the annotation before creating them
+//  2. Create or update the declared ones
+//  3. For each tracked one which is not declared anymore, delete it from Elasticsearch if pruning is enabled, and stop
+//     tracking it
+//  4. Update the annotation with the remaining ones
+func reconcileKind(ctx context.Context, c k8s.Client, es *esv1.Elasticsearch, k kind) error {
+	managed := getNamesInAnnotation(*es, k.annotation)
+	if len(k.declared) == 0 && managed.Count() == 0 {
+		// nothing to do, skip
+		return nil
+	}
+
+	span, ctx := apm.StartSpan(ctx, "reconcile_security_roles", tracing.SpanTypeApp)
+	defer span.End()
+	log := ulog.FromContext(ctx)
+
+	current, err := k.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	inSpec := make(map[string]struct{}, len(k.declared))
+	for _, d := range k.declared {
+		inSpec[d.name] = struct{}{}
+		managed.Add(d.name)
+	}
+	if err := annotateWithNames(ctx, c, es, k.annotation, managed); err != nil {
+		return err
+	}
+
+	for _, d := range k.declared {
+		expected := expectedDefinition(d.definition)
+		if existing, exists := current[d.name]; exists && isSubset(expected, existing) {
+			continue
+		}
+		log.Info("Updating "+k.name, "namespace", es.Namespace, "es_name", es.Name, "name", d.name)
+		if err := k.update(ctx, d.name, expected); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range managed.AsSortedSlice() {
+		if _, exists := inSpec[name]; exists {
+			continue
+		}
+		if _, exists := current[name]; exists && es.Spec.PruneSecurityRoles {
+			log.Info("Deleting "+k.name, "namespace", es.Namespace, "es_name", es.Name, "name", name)
+			if err := k.delete(ctx, name); err != nil && !esclient.IsNotFound(err) {
+				return err
+			}
+		}
+		managed.Del(name)
+	}
+	return annotateWithNames(ctx, c, es, k.annotation, managed)
+}
+
+// expectedDefinition returns the body of the request to create the given role or role mapping.
+func expectedDefinition(definition *commonv1.Config) map[string]interface{} {
+	if definition == nil || definition.Data == nil {
+		return map[string]interface{}{}
+	}
+	return definition.Data
+}
+
+// isSubset returns true if all the fields of the expected definition are set to the same value in the existing one.
+// Elasticsearch returns the definitions with their default values, such as empty metadata or run_as privileges, which
+// are ignored if they are not in the expected definition. Definitions are compared in their JSON representation, to
+// ignore differences in the Go types used for numbers.
+func isSubset(expected, existing map[string]interface{}) bool {
+	normalizedExpected, err := normalize(expected)
+	if err != nil {
+		return false
+	}
+	normalizedExisting, err := normalize(existing)
+	if err != nil {
+		return false
+	}
+	return isSubsetValue(normalizedExpected, normalizedExisting)
+}
+
+func isSubsetValue(expected, existing interface{}) bool {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		existingValue, ok := existing.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range expectedValue {
+			if !isSubsetValue(value, existingValue[key]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		existingValue, ok := existing.([]interface{})
+		if !ok || len(existingValue) != len(expectedValue) {
+			return false
+		}
+		for i := range expectedValue {
+			if !isSubsetValue(expectedValue[i], existingValue[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return expected == existing
+	}
+}
+
+func normalize(definition map[string]interface{}) (interface{}, error) {
+	bytes, err := json.Marshal(definition)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(bytes, &normalized)
+	return normalized, err
+}
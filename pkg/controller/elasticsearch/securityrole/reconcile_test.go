@@ -0,0 +1,287 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package securityrole
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+type fakeESClient struct {
+	esclient.Client
+	existingRoles    esclient.SecurityRoles
+	getRolesCalled   bool
+	updatedRoles     map[string]esclient.SecurityRole
+	deletedRoles     []string
+	existingMappings esclient.RoleMappings
+	updatedMappings  map[string]esclient.RoleMapping
+	deletedMappings  []string
+	getErr           error
+	updateErr        error
+	deleteErr        error
+}
+
+func (f *fakeESClient) GetSecurityRoles(_ context.Context) (esclient.SecurityRoles, error) {
+	f.getRolesCalled = true
+	return f.existingRoles, f.getErr
+}
+
+func (f *fakeESClient) UpdateSecurityRole(_ context.Context, name string, role esclient.SecurityRole) error {
+	if f.updatedRoles == nil {
+		f.updatedRoles = map[string]esclient.SecurityRole{}
+	}
+	f.updatedRoles[name] = role
+	return f.updateErr
+}
+
+func (f *fakeESClient) DeleteSecurityRole(_ context.Context, name string) error {
+	f.deletedRoles = append(f.deletedRoles, name)
+	return f.deleteErr
+}
+
+func (f *fakeESClient) GetRoleMappings(_ context.Context) (esclient.RoleMappings, error) {
+	return f.existingMappings, f.getErr
+}
+
+func (f *fakeESClient) UpdateRoleMapping(_ context.Context, name string, mapping esclient.RoleMapping) error {
+	if f.updatedMappings == nil {
+		f.updatedMappings = map[string]esclient.RoleMapping{}
+	}
+	f.updatedMappings[name] = mapping
+	return f.updateErr
+}
+
+func (f *fakeESClient) DeleteRoleMapping(_ context.Context, name string) error {
+	f.deletedMappings = append(f.deletedMappings, name)
+	return f.deleteErr
+}
+
+func esWithRoles(managed string, prune bool, roles ...esv1.SecurityRole) esv1.Elasticsearch {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	if managed != "" {
+		es.Annotations = map[string]string{ManagedSecurityRolesAnnotationName: managed}
+	}
+	es.Spec.SecurityRoles = roles
+	es.Spec.PruneSecurityRoles = prune
+	return es
+}
+
+var (
+	logsReaderDefinition = map[string]interface{}{
+		"cluster": []interface{}{"monitor"},
+		"indices": []interface{}{
+			map[string]interface{}{"names": []interface{}{"logs-*"}, "privileges": []interface{}{"read"}},
+		},
+	}
+	logsReaderRole = esv1.SecurityRole{
+		Name:       "logs-reader",
+		Definition: &commonv1.Config{Data: logsReaderDefinition},
+	}
+	// the role as returned by Elasticsearch, with the default values of the fields not in the specification
+	existingLogsReaderRole = esclient.SecurityRole{
+		"cluster": []interface{}{"monitor"},
+		"indices": []interface{}{
+			map[string]interface{}{"names": []interface{}{"logs-*"}, "privileges": []interface{}{"read"}, "allow_restricted_indices": false},
+		},
+		"applications":       []interface{}{},
+		"run_as":             []interface{}{},
+		"metadata":           map[string]interface{}{},
+		"transient_metadata": map[string]interface{}{"enabled": true},
+	}
+	userRole = esclient.SecurityRole{"cluster": []interface{}{"all"}}
+)
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name           string
+		es             esv1.Elasticsearch
+		esClient       *fakeESClient
+		wantGetCalled  bool
+		wantUpdated    map[string]esclient.SecurityRole
+		wantDeleted    []string
+		wantAnnotation string
+		wantErr        bool
+	}{
+		{
+			name:          "no role in the spec nor in the annotation: nothing to do",
+			es:            esWithRoles("", true),
+			esClient:      &fakeESClient{},
+			wantGetCalled: false,
+		},
+		{
+			name:           "create a new role",
+			es:             esWithRoles("", false, logsReaderRole),
+			esClient:       &fakeESClient{existingRoles: esclient.SecurityRoles{}},
+			wantGetCalled:  true,
+			wantUpdated:    map[string]esclient.SecurityRole{"logs-reader": logsReaderDefinition},
+			wantAnnotation: "logs-reader",
+		},
+		{
+			name:           "role already created with the same definition and default values: nothing to update",
+			es:             esWithRoles("logs-reader", false, logsReaderRole),
+			esClient:       &fakeESClient{existingRoles: esclient.SecurityRoles{"logs-reader": existingLogsReaderRole}},
+			wantGetCalled:  true,
+			wantAnnotation: "logs-reader",
+		},
+		{
+			name: "role definition drifted: update it",
+			es:   esWithRoles("logs-reader", false, logsReaderRole),
+			esClient: &fakeESClient{existingRoles: esclient.SecurityRoles{
+				"logs-reader": {"cluster": []interface{}{"monitor", "manage"}, "indices": existingLogsReaderRole["indices"]},
+			}},
+			wantGetCalled:  true,
+			wantUpdated:    map[string]esclient.SecurityRole{"logs-reader": logsReaderDefinition},
+			wantAnnotation: "logs-reader",
+		},
+		{
+			name: "role removed from the spec without pruning: retain it and stop tracking it",
+			es:   esWithRoles("logs-reader,removed", false, logsReaderRole),
+			esClient: &fakeESClient{existingRoles: esclient.SecurityRoles{
+				"logs-reader": existingLogsReaderRole,
+				"removed":     userRole,
+			}},
+			wantGetCalled:  true,
+			wantAnnotation: "logs-reader",
+		},
+		{
+			name: "role removed from the spec with pruning: delete it",
+			es:   esWithRoles("logs-reader,removed", true, logsReaderRole),
+			esClient: &fakeESClient{existingRoles: esclient.SecurityRoles{
+				"logs-reader": existingLogsReaderRole,
+				"removed":     userRole,
+			}},
+			wantGetCalled:  true,
+			wantDeleted:    []string{"removed"},
+			wantAnnotation: "logs-reader",
+		},
+		{
+			name:          "all roles removed from the spec with pruning: delete them and remove the annotation",
+			es:            esWithRoles("logs-reader", true),
+			esClient:      &fakeESClient{existingRoles: esclient.SecurityRoles{"logs-reader": existingLogsReaderRole}},
+			wantGetCalled: true,
+			wantDeleted:   []string{"logs-reader"},
+		},
+		{
+			name:          "tracked role already deleted from Elasticsearch: stop tracking it",
+			es:            esWithRoles("logs-reader", true),
+			esClient:      &fakeESClient{existingRoles: esclient.SecurityRoles{}},
+			wantGetCalled: true,
+		},
+		{
+			name: "roles not created by the operator are never deleted",
+			es:   esWithRoles("logs-reader", true, logsReaderRole),
+			esClient: &fakeESClient{existingRoles: esclient.SecurityRoles{
+				"logs-reader": existingLogsReaderRole,
+				"user-role":   userRole,
+			}},
+			wantGetCalled:  true,
+			wantAnnotation: "logs-reader",
+		},
+		{
+			name:          "error while retrieving the roles",
+			es:            esWithRoles("", false, logsReaderRole),
+			esClient:      &fakeESClient{getErr: errors.New("boom")},
+			wantGetCalled: true,
+			wantErr:       true,
+		},
+		{
+			name:           "error while creating a role: the role is tracked anyway",
+			es:             esWithRoles("", false, logsReaderRole),
+			esClient:       &fakeESClient{existingRoles: esclient.SecurityRoles{}, updateErr: errors.New("boom")},
+			wantGetCalled:  true,
+			wantUpdated:    map[string]esclient.SecurityRole{"logs-reader": logsReaderDefinition},
+			wantAnnotation: "logs-reader",
+			wantErr:        true,
+		},
+		{
+			name:           "error while deleting a role: keep tracking it",
+			es:             esWithRoles("removed", true),
+			esClient:       &fakeESClient{existingRoles: esclient.SecurityRoles{"removed": userRole}, deleteErr: errors.New("boom")},
+			wantGetCalled:  true,
+			wantDeleted:    []string{"removed"},
+			wantAnnotation: "removed",
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := k8s.NewFakeClient(tt.es.DeepCopy())
+			var es esv1.Elasticsearch
+			require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&tt.es), &es))
+			err := Reconcile(context.Background(), k8sClient, tt.esClient, &es)
+			require.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+			require.Equal(t, tt.wantGetCalled, tt.esClient.getRolesCalled)
+			require.Equal(t, tt.wantUpdated, tt.esClient.updatedRoles)
+			require.Equal(t, tt.wantDeleted, tt.esClient.deletedRoles)
+
+			require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&tt.es), &es))
+			require.Equal(t, tt.wantAnnotation, es.Annotations[ManagedSecurityRolesAnnotationName])
+		})
+	}
+}
+
+func TestReconcile_roleMappings(t *testing.T) {
+	mappingDefinition := map[string]interface{}{
+		"roles": []interface{}{"logs-reader"},
+		"rules": map[string]interface{}{"field": map[string]interface{}{"realm.name": "saml1"}},
+	}
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "es",
+			Annotations: map[string]string{ManagedRoleMappingsAnnotationName: "removed,saml-readers"},
+		},
+		Spec: esv1.ElasticsearchSpec{
+			SecurityRoles:      []esv1.SecurityRole{logsReaderRole},
+			RoleMappings:       []esv1.RoleMapping{{Name: "saml-readers", Definition: &commonv1.Config{Data: mappingDefinition}}},
+			PruneSecurityRoles: true,
+		},
+	}
+	esClient := &fakeESClient{
+		existingRoles: esclient.SecurityRoles{"logs-reader": existingLogsReaderRole},
+		existingMappings: esclient.RoleMappings{
+			"saml-readers": {"enabled": true, "roles": []interface{}{"other-role"}, "rules": mappingDefinition["rules"], "metadata": map[string]interface{}{}},
+			"removed":      {"enabled": true, "roles": []interface{}{"superuser"}},
+		},
+	}
+	k8sClient := k8s.NewFakeClient(&es)
+	require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&es), &es))
+	// the Elasticsearch resource is updated after being retrieved for the reconciliation
+	var concurrent esv1.Elasticsearch
+	require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&es), &concurrent))
+	concurrent.Labels = map[string]string{"updated": "true"}
+	require.NoError(t, k8sClient.Update(context.Background(), &concurrent))
+
+	require.NoError(t, Reconcile(context.Background(), k8sClient, esClient, &es))
+	require.Empty(t, esClient.updatedRoles)
+	require.Equal(t, map[string]esclient.RoleMapping{"saml-readers": mappingDefinition}, esClient.updatedMappings)
+	require.Equal(t, []string{"removed"}, esClient.deletedMappings)
+
+	var updated esv1.Elasticsearch
+	require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&es), &updated))
+	require.Equal(t, "logs-reader", updated.Annotations[ManagedSecurityRolesAnnotationName])
+	require.Equal(t, "saml-readers", updated.Annotations[ManagedRoleMappingsAnnotationName])
+	require.Equal(t, map[string]string{"updated": "true"}, updated.Labels)
+	// the reconciled resource is up to date
+	require.Equal(t, updated, es)
+}
+
+func Test_isSubset(t *testing.T) {
+	require.True(t, isSubset(logsReaderDefinition, existingLogsReaderRole))
+	require.True(t, isSubset(map[string]interface{}{"metadata": map[string]interface{}{"version": 1}}, map[string]interface{}{"metadata": map[string]interface{}{"version": float64(1)}}))
+	require.False(t, isSubset(logsReaderDefinition, userRole))
+	// arrays are compared element by element
+	require.False(t, isSubset(map[string]interface{}{"cluster": []interface{}{"monitor"}}, map[string]interface{}{"cluster": []interface{}{"monitor", "manage"}}))
+	require.False(t, isSubset(map[string]interface{}{"run_as": []interface{}{"user"}}, map[string]interface{}{}))
+}
@@ -17,7 +17,6 @@ import (
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
 )
@@ -33,7 +32,7 @@ func TransportServiceName(esName string) string {
 
 // NewTransportService returns the transport service associated with the given cluster.
 // It is used by Elasticsearch nodes to talk to remote cluster nodes.
-func NewTransportService(es esv1.Elasticsearch) *corev1.Service {
+func NewTransportService(ctx context.Context, es esv1.Elasticsearch) *corev1.Service {
 	nsn := k8s.ExtractNamespacedName(&es)
 	svc := corev1.Service{
 		ObjectMeta: es.Spec.Transport.Service.ObjectMeta,
@@ -55,11 +54,11 @@ func NewTransportService(es esv1.Elasticsearch) *corev1.Service {
 		{
 			Name:     "tls-transport", // prefix with protocol for Istio compatibility
 			Protocol: corev1.ProtocolTCP,
-			Port:     network.TransportPort,
+			Port:     es.TransportPort(),
 		},
 	}
 
-	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
+	return defaults.SetServiceDefaults(ctx, &svc, labels, labels, ports)
 }
 
 // ExternalServiceName returns the name for the external service
@@ -75,23 +74,23 @@ func InternalServiceName(esName string) string {
 }
 
 // ExternalTransportServiceHost returns the hostname and the port used to reach Elasticsearch's transport endpoint.
-func ExternalTransportServiceHost(es types.NamespacedName) string {
-	return stringsutil.Concat(TransportServiceName(es.Name), ".", es.Namespace, globalServiceSuffix, ":", strconv.Itoa(network.TransportPort))
+func ExternalTransportServiceHost(es esv1.Elasticsearch) string {
+	return stringsutil.Concat(TransportServiceName(es.Name), ".", es.Namespace, globalServiceSuffix, ":", strconv.Itoa(int(es.TransportPort())))
 }
 
 // ExternalServiceURL returns the URL used to reach Elasticsearch's external endpoint.
 func ExternalServiceURL(es esv1.Elasticsearch) string {
-	return stringsutil.Concat(es.Spec.HTTP.Protocol(), "://", ExternalServiceName(es.Name), ".", es.Namespace, globalServiceSuffix, ":", strconv.Itoa(network.HTTPPort))
+	return stringsutil.Concat(es.Spec.HTTP.Protocol(), "://", ExternalServiceName(es.Name), ".", es.Namespace, globalServiceSuffix, ":", strconv.Itoa(int(es.HTTPPort())))
 }
 
 // InternalServiceURL returns the URL used to reach Elasticsearch's internally managed service
 func InternalServiceURL(es esv1.Elasticsearch) string {
-	return stringsutil.Concat(es.Spec.HTTP.Protocol(), "://", InternalServiceName(es.Name), ".", es.Namespace, globalServiceSuffix, ":", strconv.Itoa(network.HTTPPort))
+	return stringsutil.Concat(es.Spec.HTTP.Protocol(), "://", InternalServiceName(es.Name), ".", es.Namespace, globalServiceSuffix, ":", strconv.Itoa(int(es.HTTPPort())))
 }
 
 // NewExternalService returns the external service associated to the given cluster.
 // It is used by users to perform requests against one of the cluster nodes.
-func NewExternalService(es esv1.Elasticsearch) *corev1.Service {
+func NewExternalService(ctx context.Context, es esv1.Elasticsearch) *corev1.Service {
 	nsn := k8s.ExtractNamespacedName(&es)
 
 	svc := corev1.Service{
@@ -107,11 +106,11 @@ func NewExternalService(es esv1.Elasticsearch) *corev1.Service {
 		{
 			Name:     es.Spec.HTTP.Protocol(),
 			Protocol: corev1.ProtocolTCP,
-			Port:     network.HTTPPort,
+			Port:     es.HTTPPort(),
 		},
 	}
 
-	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
+	return defaults.SetServiceDefaults(ctx, &svc, labels, labels, ports)
 }
 
 // NewInternalService returns the internal service associated to the given cluster.
@@ -131,7 +130,7 @@ func NewInternalService(es esv1.Elasticsearch) *corev1.Service {
 				{
 					Name:     es.Spec.HTTP.Protocol(),
 					Protocol: corev1.ProtocolTCP,
-					Port:     network.HTTPPort,
+					Port:     es.HTTPPort(),
 				},
 			},
 			Selector:                 label.NewLabels(k8s.ExtractNamespacedName(&es)),
@@ -196,7 +195,7 @@ func ElasticsearchURL(es esv1.Elasticsearch, pods []corev1.Pod) string {
 	if schemeChange {
 		// switch to sending requests directly to a random pod instead of going through the service
 		randomPod := pods[rand.Intn(len(pods))] //nolint:gosec
-		if podURL := ElasticsearchPodURL(randomPod); podURL != "" {
+		if podURL := ElasticsearchPodURL(es, randomPod); podURL != "" {
 			return podURL
 		}
 	}
@@ -204,11 +203,11 @@ func ElasticsearchURL(es esv1.Elasticsearch, pods []corev1.Pod) string {
 }
 
 // ElasticsearchPodURL calculates the URL for the given Pod based on the Pods metadata.
-func ElasticsearchPodURL(pod corev1.Pod) string {
+func ElasticsearchPodURL(es esv1.Elasticsearch, pod corev1.Pod) string {
 	scheme, hasSchemeLabel := pod.Labels[label.HTTPSchemeLabelName]
 	sset, hasSsetLabel := pod.Labels[label.StatefulSetNameLabelName]
 	if hasSsetLabel && hasSchemeLabel {
-		return fmt.Sprintf("%s://%s.%s.%s:%d", scheme, pod.Name, sset, pod.Namespace, network.HTTPPort)
+		return fmt.Sprintf("%s://%s.%s.%s:%d", scheme, pod.Name, sset, pod.Namespace, es.HTTPPort())
 	}
 	return ""
 }
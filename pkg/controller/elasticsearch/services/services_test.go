@@ -5,6 +5,7 @@
 package services
 
 import (
+	"context"
 	"testing"
 
 	"github.com/go-test/deep"
@@ -210,12 +211,34 @@ func TestNewExternalService(t *testing.T) {
 				return svc
 			},
 		},
+		{
+			name: "user-provided labels and annotations take precedence over operator-managed labels",
+			httpConf: commonv1.HTTPConfig{
+				Service: commonv1.ServiceTemplate{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"mesh":                     "enabled",
+							label.ClusterNameLabelName: "another-cluster",
+						},
+						Annotations: map[string]string{"cost-center": "search"},
+					},
+				},
+			},
+			wantSvc: func() corev1.Service {
+				svc := mkHTTPService()
+				svc.Labels["mesh"] = "enabled"
+				svc.Labels[label.ClusterNameLabelName] = "another-cluster"
+				svc.Annotations = map[string]string{"cost-center": "search"}
+				svc.Spec.Ports[0].Name = "https"
+				return svc
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			es := mkElasticsearch(tc.httpConf)
-			haveSvc := NewExternalService(es)
+			haveSvc := NewExternalService(context.Background(), es)
 			compare.JSONEqual(t, tc.wantSvc(), haveSvc)
 		})
 	}
@@ -358,6 +381,41 @@ func TestNewTransportService(t *testing.T) {
 				return svc
 			},
 		},
+		{
+			name: "Respects user provided NodePort type",
+			transportCfg: esv1.TransportConfig{
+				Service: commonv1.ServiceTemplate{
+					Spec: corev1.ServiceSpec{
+						Type: corev1.ServiceTypeNodePort,
+					},
+				},
+			},
+			want: func() corev1.Service {
+				svc := mkTransportService()
+				svc.Spec.Type = corev1.ServiceTypeNodePort
+				svc.Spec.ClusterIP = ""
+				return svc
+			},
+		},
+		{
+			name: "Merges user provided labels, which take precedence over operator-managed labels",
+			transportCfg: esv1.TransportConfig{
+				Service: commonv1.ServiceTemplate{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"mesh":                 "enabled",
+							commonv1.TypeLabelName: "something-else",
+						},
+					},
+				},
+			},
+			want: func() corev1.Service {
+				svc := mkTransportService()
+				svc.ObjectMeta.Labels["mesh"] = "enabled"
+				svc.ObjectMeta.Labels[commonv1.TypeLabelName] = "something-else"
+				return svc
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -371,8 +429,43 @@ func TestNewTransportService(t *testing.T) {
 				},
 			}
 			want := tt.want()
-			got := NewTransportService(es)
+			got := NewTransportService(context.Background(), es)
 			require.Nil(t, deep.Equal(*got, want))
 		})
 	}
 }
+
+func TestOverriddenPorts(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "an-es-name",
+			Namespace: "default",
+		},
+		Spec: esv1.ElasticsearchSpec{
+			Ports: esv1.PortsConfig{HTTP: 19200, Transport: 19300},
+		},
+	}
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "an-es-name-es-default-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				label.HTTPSchemeLabelName:      "https",
+				label.StatefulSetNameLabelName: "an-es-name-es-default",
+			},
+		},
+	}
+
+	assert.Equal(t, "https://an-es-name-es-http.default.svc:19200", ExternalServiceURL(es))
+	assert.Equal(t, "https://an-es-name-es-internal-http.default.svc:19200", InternalServiceURL(es))
+	assert.Equal(t, "an-es-name-es-transport.default.svc:19300", ExternalTransportServiceHost(es))
+	assert.Equal(t, "https://an-es-name-es-default-0.an-es-name-es-default.default:19200", ElasticsearchPodURL(es, pod))
+
+	for _, svc := range []*corev1.Service{NewExternalService(context.Background(), es), NewInternalService(es)} {
+		require.Len(t, svc.Spec.Ports, 1)
+		assert.Equal(t, int32(19200), svc.Spec.Ports[0].Port)
+	}
+	transportSvc := NewTransportService(context.Background(), es)
+	require.Len(t, transportSvc.Spec.Ports, 1)
+	assert.Equal(t, int32(19300), transportSvc.Spec.Ports[0].Port)
+}
@@ -75,26 +75,31 @@ func GetESConfigSecret(client k8s.Client, namespace string, ssetName string) (co
 	return secret, nil
 }
 
-func ConfigSecret(es esv1.Elasticsearch, ssetName string, configData []byte) corev1.Secret {
+// ConfigSecret returns the secret holding the ES configuration and the optional JVM options of the given StatefulSet.
+func ConfigSecret(es esv1.Elasticsearch, ssetName string, configData []byte, jvmOptions []byte) corev1.Secret {
+	data := map[string][]byte{
+		ConfigFileName: configData,
+	}
+	if len(jvmOptions) > 0 {
+		data[JVMOptionsFileName] = jvmOptions
+	}
 	return corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: es.Namespace,
 			Name:      ConfigSecretName(ssetName),
 			Labels:    label.NewConfigLabels(k8s.ExtractNamespacedName(&es), ssetName),
 		},
-		Data: map[string][]byte{
-			ConfigFileName: configData,
-		},
+		Data: data,
 	}
 }
 
-// ReconcileConfig ensures the ES config for the pod is set in the apiserver.
-func ReconcileConfig(ctx context.Context, client k8s.Client, es esv1.Elasticsearch, ssetName string, config CanonicalConfig) error {
+// ReconcileConfig ensures the ES config and the JVM options for the pod are set in the apiserver.
+func ReconcileConfig(ctx context.Context, client k8s.Client, es esv1.Elasticsearch, ssetName string, config CanonicalConfig, jvmOptions []string) error {
 	rendered, err := config.Render()
 	if err != nil {
 		return err
 	}
-	expected := ConfigSecret(es, ssetName, rendered)
+	expected := ConfigSecret(es, ssetName, rendered, RenderJVMOptions(jvmOptions))
 	_, err = reconciler.ReconcileSecret(ctx, client, expected, &es)
 	return err
 }
@@ -116,12 +116,13 @@ func TestReconcileConfig(t *testing.T) {
 		},
 	}
 	tests := []struct {
-		name     string
-		client   k8s.Client
-		es       esv1.Elasticsearch
-		ssetName string
-		config   CanonicalConfig
-		wantErr  bool
+		name       string
+		client     k8s.Client
+		es         esv1.Elasticsearch
+		ssetName   string
+		config     CanonicalConfig
+		jvmOptions []string
+		wantErr    bool
 	}{
 		{
 			name:     "config does not exist",
@@ -147,16 +148,29 @@ func TestReconcileConfig(t *testing.T) {
 			config:   CanonicalConfig{common.MustCanonicalConfig(map[string]string{"a": "b", "c": "different"})},
 			wantErr:  false,
 		},
+		{
+			name:       "config with JVM options",
+			client:     k8s.NewFakeClient(&configSecret),
+			es:         es,
+			ssetName:   ssetName,
+			config:     config,
+			jvmOptions: []string{"-Dfoo=bar", "-Xmx2g"},
+			wantErr:    false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := ReconcileConfig(context.Background(), tt.client, tt.es, tt.ssetName, tt.config); (err != nil) != tt.wantErr {
+			if err := ReconcileConfig(context.Background(), tt.client, tt.es, tt.ssetName, tt.config, tt.jvmOptions); (err != nil) != tt.wantErr {
 				t.Errorf("ReconcileConfig() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			// config in the apiserver should be the expected one
 			parsed, err := GetESConfigContent(tt.client, tt.es.Namespace, tt.ssetName)
 			require.NoError(t, err)
 			require.Equal(t, tt.config, parsed)
+			// JVM options should be stored along with the config
+			secret, err := GetESConfigSecret(tt.client, tt.es.Namespace, tt.ssetName)
+			require.NoError(t, err)
+			require.Equal(t, RenderJVMOptions(tt.jvmOptions), secret.Data[JVMOptionsFileName])
 		})
 	}
 }
@@ -20,3 +20,9 @@ const (
 	EnvNodeName  = "NODE_NAME"
 	EnvNamespace = "NAMESPACE"
 )
+
+// NodeAttributeEnvVar returns the name of the environment variable holding the value of the given node attribute, set
+// from the corresponding node label copied as a Pod annotation.
+func NodeAttributeEnvVar(attributeName string) string {
+	return "NODE_ATTR_" + attributeName
+}
@@ -0,0 +1,33 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+// DefaultSharedCacheSizePercent is the percentage of the data volume of dedicated frozen nodes used by default for
+// the shared cache of searchable snapshots. It matches the Elasticsearch default for the disk of dedicated frozen nodes.
+const DefaultSharedCacheSizePercent = 90
+
+// MinFrozenTierVersion is the first version of Elasticsearch supporting the data_frozen role.
+var MinFrozenTierVersion = version.MinFor(7, 12, 0)
+
+// frozenConfig returns the configuration of the shared cache of searchable snapshots, or nil if its size is not set.
+func frozenConfig(sharedCacheSize *resource.Quantity) *common.CanonicalConfig {
+	if sharedCacheSize == nil {
+		return nil
+	}
+	return common.MustCanonicalConfig(map[string]interface{}{
+		// Elasticsearch does not understand the Kubernetes quantity suffixes, use a number of bytes instead
+		esv1.XPackSearchableSnapshotSharedCacheSize: fmt.Sprintf("%db", sharedCacheSize.Value()),
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"path"
+	"strings"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	commonvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+const (
+	// JVMOptionsFileName is the name of the file holding the user-provided JVM options in the jvm.options.d directory.
+	// Elasticsearch only reads the files of this directory with the .options extension.
+	JVMOptionsFileName   = "eck.options"
+	JVMOptionsVolumeName = "elastic-internal-jvm-options"
+
+	jvmOptionsDirName  = "jvm.options.d"
+	jvmMinHeapSizeFlag = "-Xms"
+	jvmMaxHeapSizeFlag = "-Xmx"
+)
+
+// MinJVMOptionsDirVersion is the first version of Elasticsearch reading JVM options from the jvm.options.d directory.
+var MinJVMOptionsDirVersion = version.MinFor(7, 7, 0)
+
+// JVMOptionsSecretVolume returns the volume mounting the user-provided JVM options of the given StatefulSet, stored
+// along with its configuration, into the jvm.options.d directory of Elasticsearch.
+func JVMOptionsSecretVolume(ssetName string) commonvolume.SecretVolume {
+	return commonvolume.NewSecretVolume(
+		ConfigSecretName(ssetName),
+		JVMOptionsVolumeName,
+		path.Join(volume.ConfigVolumeMountPath, jvmOptionsDirName, JVMOptionsFileName),
+		JVMOptionsFileName,
+		0644,
+	)
+}
+
+// RenderJVMOptions returns the content of the jvm.options.d file holding the given JVM options, or nil if there is none.
+func RenderJVMOptions(options []string) []byte {
+	if len(options) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(options, "\n") + "\n")
+}
+
+// JVMOptionName returns the name identifying the given JVM option, regardless of its value, in order to detect
+// options set several times.
+func JVMOptionName(option string) string {
+	option = strings.TrimSpace(option)
+	switch {
+	case strings.HasPrefix(option, "-XX:"):
+		// -XX:+Flag, -XX:-Flag or -XX:Flag=value
+		name, _, _ := strings.Cut(strings.TrimLeft(strings.TrimPrefix(option, "-XX:"), "+-"), "=")
+		return "-XX:" + name
+	case strings.HasPrefix(option, "-D"):
+		name, _, _ := strings.Cut(option, "=")
+		return name
+	case strings.HasPrefix(option, jvmMinHeapSizeFlag), strings.HasPrefix(option, jvmMaxHeapSizeFlag), strings.HasPrefix(option, "-Xss"):
+		return option[:4]
+	default:
+		return option
+	}
+}
+
+// HasHeapSizeOption returns true if the heap size is set in the given JVM options.
+func HasHeapSizeOption(options []string) bool {
+	for _, option := range options {
+		if name := JVMOptionName(option); name == jvmMinHeapSizeFlag || name == jvmMaxHeapSizeFlag {
+			return true
+		}
+	}
+	return false
+}
+
+// OverriddenJVMOptions returns the JVM options also set in the given value of the ES_JAVA_OPTS environment variable,
+// which Elasticsearch applies after the jvm.options.d files.
+func OverriddenJVMOptions(options []string, esJavaOpts string) []string {
+	envOptions := make(map[string]struct{})
+	for _, option := range strings.Fields(esJavaOpts) {
+		envOptions[JVMOptionName(option)] = struct{}{}
+	}
+	var overridden []string
+	for _, option := range options {
+		if _, exists := envOptions[JVMOptionName(option)]; exists {
+			overridden = append(overridden, option)
+		}
+	}
+	return overridden
+}
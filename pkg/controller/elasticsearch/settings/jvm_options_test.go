@@ -0,0 +1,53 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderJVMOptions(t *testing.T) {
+	require.Nil(t, RenderJVMOptions(nil))
+	require.Equal(t, []byte("-Dfoo=bar\n-XX:+UseG1GC\n"), RenderJVMOptions([]string{"-Dfoo=bar", "-XX:+UseG1GC"}))
+}
+
+func TestJVMOptionName(t *testing.T) {
+	tests := []struct {
+		option string
+		want   string
+	}{
+		{option: "-Xms2g", want: "-Xms"},
+		{option: "-Xmx2g", want: "-Xmx"},
+		{option: "-Xss1m", want: "-Xss"},
+		{option: "-Dfoo=bar", want: "-Dfoo"},
+		{option: "-Dfoo", want: "-Dfoo"},
+		{option: "-XX:+UseG1GC", want: "-XX:UseG1GC"},
+		{option: "-XX:-UseG1GC", want: "-XX:UseG1GC"},
+		{option: "-XX:MaxGCPauseMillis=200", want: "-XX:MaxGCPauseMillis"},
+		{option: " -ea ", want: "-ea"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.option, func(t *testing.T) {
+			require.Equal(t, tt.want, JVMOptionName(tt.option))
+		})
+	}
+}
+
+func TestHasHeapSizeOption(t *testing.T) {
+	require.False(t, HasHeapSizeOption(nil))
+	require.False(t, HasHeapSizeOption([]string{"-Dfoo=bar", "-Xss1m"}))
+	require.True(t, HasHeapSizeOption([]string{"-Dfoo=bar", "-Xmx2g"}))
+	require.True(t, HasHeapSizeOption([]string{"-Xms2g"}))
+}
+
+func TestOverriddenJVMOptions(t *testing.T) {
+	options := []string{"-Xms2g", "-Xmx2g", "-Dfoo=bar", "-XX:+UseG1GC"}
+	require.Empty(t, OverriddenJVMOptions(options, ""))
+	require.Empty(t, OverriddenJVMOptions(options, "-Dbar=baz -XX:MaxGCPauseMillis=200"))
+	require.Equal(t, []string{"-Xms2g", "-Xmx2g"}, OverriddenJVMOptions(options, "-Xms1g  -Xmx1g"))
+	require.Equal(t, []string{"-Dfoo=bar", "-XX:+UseG1GC"}, OverriddenJVMOptions(options, "-XX:-UseG1GC -Dfoo=baz"))
+}
@@ -0,0 +1,11 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+
+// MinKeystorePasswordVersion is the first version of Elasticsearch whose Docker entrypoint reads the password
+// protecting the keystore from the KEYSTORE_PASSWORD environment variable.
+var MinKeystorePasswordVersion = version.MinFor(7, 9, 0)
@@ -0,0 +1,158 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	pkgerrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	commonvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// Log4j2ConfigVolumeName is the name of the volume holding the log4j2 configuration of Elasticsearch.
+const Log4j2ConfigVolumeName = "elastic-internal-log4j2-config"
+
+// log4j2ConfigHeader is common to all the built-in log4j2 configurations, which print the logs to the console.
+const log4j2ConfigHeader = `status = error
+
+appender.console.type = Console
+appender.console.name = console
+`
+
+const log4j2ConfigFooter = `
+rootLogger.level = info
+rootLogger.appenderRef.console.ref = console
+`
+
+// jsonLog4j2Layouts are the built-in JSON layouts, from the most recent to the oldest supported Elasticsearch version.
+var jsonLog4j2Layouts = []struct {
+	minVersion version.Version
+	layout     string
+}{
+	{
+		// ECS formatted logs
+		minVersion: version.MinFor(8, 0, 0),
+		layout: `appender.console.layout.type = ECSJsonLayout
+appender.console.layout.dataset = elasticsearch.server
+`,
+	},
+	{
+		minVersion: version.MinFor(7, 0, 0),
+		layout: `appender.console.layout.type = ESJsonLayout
+appender.console.layout.type_name = server
+`,
+	},
+	{
+		// no JSON layout is bundled with Elasticsearch before 7.0.0, fall back to a pattern encoding the fields as JSON
+		minVersion: version.MinFor(0, 0, 0),
+		layout: `appender.console.layout.type = PatternLayout
+appender.console.layout.pattern = {"type": "server", "timestamp": "%d{ISO8601}", "level": "%p", "component": "%c{1.}", "cluster.name": "${sys:es.logs.cluster_name}", "node.name": "%node_name", "message": "%enc{%m}{JSON}", "stacktrace": "%enc{%throwable}{JSON}"}%n
+`,
+	},
+}
+
+// Log4j2ConfigSecretVolume returns the volume mounting the log4j2 configuration over the default one of Elasticsearch.
+func Log4j2ConfigSecretVolume(esName string) commonvolume.SecretVolume {
+	return commonvolume.NewSecretVolume(
+		esv1.Log4j2ConfigSecret(esName),
+		Log4j2ConfigVolumeName,
+		path.Join(volume.ConfigVolumeMountPath, esv1.Log4j2ConfigFileName),
+		esv1.Log4j2ConfigFileName,
+		0644,
+	)
+}
+
+// UserProvidedLog4j2ConfigWatchName returns the watch registered for the user-provided log4j2 configuration secret.
+func UserProvidedLog4j2ConfigWatchName(es types.NamespacedName) string { //nolint:revive
+	return fmt.Sprintf("%s-%s-user-log4j2-config", es.Namespace, es.Name)
+}
+
+// JSONLog4j2Config returns the built-in log4j2 configuration printing the logs as JSON for the given version.
+func JSONLog4j2Config(ver version.Version) string {
+	for _, l := range jsonLog4j2Layouts {
+		if ver.GTE(l.minVersion) {
+			return log4j2ConfigHeader + l.layout + log4j2ConfigFooter
+		}
+	}
+	return ""
+}
+
+// Log4j2Config returns the content of the log4j2 configuration to use for the given Elasticsearch cluster, or nil if
+// the default configuration of Elasticsearch should be used.
+func Log4j2Config(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, ver version.Version) ([]byte, error) {
+	switch {
+	case es.Spec.Log4j2 != nil && es.Spec.Log4j2.SecretName != "":
+		var secret corev1.Secret
+		key := types.NamespacedName{Namespace: es.Namespace, Name: es.Spec.Log4j2.SecretName}
+		if err := c.Get(ctx, key, &secret); err != nil {
+			return nil, err
+		}
+		content, exists := secret.Data[esv1.Log4j2ConfigFileName]
+		if !exists {
+			return nil, pkgerrors.Errorf("no %s entry found in secret %s", esv1.Log4j2ConfigFileName, key)
+		}
+		return content, nil
+	case es.Spec.Log4j2 != nil:
+		return []byte(es.Spec.Log4j2.Config), nil
+	case es.Spec.LogFormat == esv1.JSONLogFormat:
+		return []byte(JSONLog4j2Config(ver)), nil
+	default:
+		return nil, nil
+	}
+}
+
+// ReconcileLog4j2Config ensures the Secret holding the log4j2 configuration of the Elasticsearch nodes exists if
+// a log4j2 configuration is specified, and is deleted otherwise.
+// It also ensures the user-provided secret is watched for future reconciliations to be triggered on any change.
+func ReconcileLog4j2Config(
+	ctx context.Context,
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	watched watches.DynamicWatches,
+	ver version.Version,
+) error {
+	esKey := k8s.ExtractNamespacedName(&es)
+	var userSecrets []string
+	if es.Spec.Log4j2 != nil && es.Spec.Log4j2.SecretName != "" {
+		userSecrets = append(userSecrets, es.Spec.Log4j2.SecretName)
+	}
+	if err := watches.WatchUserProvidedSecrets(esKey, watched, UserProvidedLog4j2ConfigWatchName(esKey), userSecrets); err != nil {
+		return err
+	}
+
+	secretKey := types.NamespacedName{Namespace: es.Namespace, Name: esv1.Log4j2ConfigSecret(es.Name)}
+	if !es.HasLog4j2Config() {
+		return k8s.DeleteSecretIfExists(ctx, c, secretKey)
+	}
+	content, err := Log4j2Config(ctx, c, es, ver)
+	if err != nil {
+		return err
+	}
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: secretKey.Namespace,
+			Name:      secretKey.Name,
+			Labels:    label.NewLabels(esKey),
+		},
+		Data: map[string][]byte{
+			esv1.Log4j2ConfigFileName: content,
+		},
+	}
+	_, err = reconciler.ReconcileSecret(ctx, c, expected, &es)
+	return err
+}
@@ -0,0 +1,120 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestJSONLog4j2Config(t *testing.T) {
+	tests := []struct {
+		version string
+		layout  string
+	}{
+		{version: "6.8.0", layout: "appender.console.layout.type = PatternLayout"},
+		{version: "7.0.0", layout: "appender.console.layout.type = ESJsonLayout"},
+		{version: "7.17.18", layout: "appender.console.layout.type = ESJsonLayout"},
+		{version: "8.0.0", layout: "appender.console.layout.type = ECSJsonLayout"},
+		{version: "8.12.0", layout: "appender.console.layout.type = ECSJsonLayout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			cfg := JSONLog4j2Config(version.MustParse(tt.version))
+			require.Contains(t, cfg, tt.layout)
+			require.Contains(t, cfg, "appender.console.type = Console")
+			require.Contains(t, cfg, "rootLogger.appenderRef.console.ref = console")
+		})
+	}
+}
+
+func TestLog4j2Config(t *testing.T) {
+	userSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "log4j2"},
+		Data:       map[string][]byte{esv1.Log4j2ConfigFileName: []byte("rootLogger.level = warn")},
+	}
+	tests := []struct {
+		name    string
+		spec    esv1.ElasticsearchSpec
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "no log4j2 configuration",
+			want: nil,
+		},
+		{
+			name: "JSON log format",
+			spec: esv1.ElasticsearchSpec{LogFormat: esv1.JSONLogFormat},
+			want: []byte(JSONLog4j2Config(version.MustParse("8.12.0"))),
+		},
+		{
+			name: "inline configuration",
+			spec: esv1.ElasticsearchSpec{Log4j2: &esv1.Log4j2Config{Config: "rootLogger.level = debug"}},
+			want: []byte("rootLogger.level = debug"),
+		},
+		{
+			name: "configuration from a secret",
+			spec: esv1.ElasticsearchSpec{Log4j2: &esv1.Log4j2Config{SecretName: "log4j2"}},
+			want: []byte("rootLogger.level = warn"),
+		},
+		{
+			name:    "secret does not exist",
+			spec:    esv1.ElasticsearchSpec{Log4j2: &esv1.Log4j2Config{SecretName: "missing"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}, Spec: tt.spec}
+			got, err := Log4j2Config(context.Background(), k8s.NewFakeClient(userSecret), es, version.MustParse("8.12.0"))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReconcileLog4j2Config(t *testing.T) {
+	ctx := context.Background()
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{Log4j2: &esv1.Log4j2Config{SecretName: "log4j2"}},
+	}
+	userSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "log4j2"},
+		Data:       map[string][]byte{esv1.Log4j2ConfigFileName: []byte("rootLogger.level = warn")},
+	}
+	c := k8s.NewFakeClient(&es, userSecret)
+	w := watches.NewDynamicWatches()
+	key := types.NamespacedName{Namespace: "ns", Name: esv1.Log4j2ConfigSecret("es")}
+
+	// the user-provided configuration is copied and watched
+	require.NoError(t, ReconcileLog4j2Config(ctx, c, es, w, version.MustParse("8.12.0")))
+	var secret corev1.Secret
+	require.NoError(t, c.Get(ctx, key, &secret))
+	require.Equal(t, []byte("rootLogger.level = warn"), secret.Data[esv1.Log4j2ConfigFileName])
+	require.Equal(t, []string{UserProvidedLog4j2ConfigWatchName(k8s.ExtractNamespacedName(&es))}, w.Secrets.Registrations())
+
+	// the secret and the watch are removed once the configuration is not specified anymore
+	es.Spec.Log4j2 = nil
+	require.NoError(t, ReconcileLog4j2Config(ctx, c, es, w, version.MustParse("8.12.0")))
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, key, &secret)))
+	require.Empty(t, w.Secrets.Registrations())
+}
@@ -44,6 +44,8 @@ func TestUpdateSeedHostsConfigMap(t *testing.T) {
 			Namespace: "ns1",
 		},
 	}
+	esWithTransportPort := *es.DeepCopy()
+	esWithTransportPort.Spec.Ports.Transport = 19300
 	type args struct {
 		c    k8s.Client
 		es   esv1.Elasticsearch
@@ -116,6 +118,19 @@ func TestUpdateSeedHostsConfigMap(t *testing.T) {
 			wantErr:         false,
 			expectedContent: "10.0.3.3:9300\n10.0.6.5:9300\n10.0.9.2:9300",
 		},
+		{
+			name: "Uses the overridden transport port",
+			args: args{
+				pods: []corev1.Pod{ //
+					newPodWithIP("master1", "10.0.9.2", true),
+					newPodWithIP("master2", "10.0.6.5", true),
+				},
+				c:  k8s.NewFakeClient(),
+				es: esWithTransportPort,
+			},
+			wantErr:         false,
+			expectedContent: "10.0.6.5:19300\n10.0.9.2:19300",
+		},
 		{
 			name: "Ordering of pods should not matter",
 			args: args{
@@ -7,8 +7,11 @@ package settings
 import (
 	"fmt"
 	"path"
+	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -16,14 +19,15 @@ import (
 	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	esversion "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
 	netutil "github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
 )
 
-// the name of the ES attribute indicating the pod's current k8s node
-const nodeAttrK8sNodeName = "k8s_node_name"
+// NodeAttrK8sNodeName is the name of the ES attribute indicating the pod's current k8s node
+const NodeAttrK8sNodeName = "k8s_node_name"
 
-var nodeAttrNodeName = fmt.Sprintf("%s.%s", esv1.NodeAttr, nodeAttrK8sNodeName)
+var nodeAttrNodeName = fmt.Sprintf("%s.%s", esv1.NodeAttr, NodeAttrK8sNodeName)
 
 // NewMergedESConfig merges user provided Elasticsearch configuration with configuration derived from the given
 // parameters. The user provided config overrides have precedence over the ECK config.
@@ -32,6 +36,12 @@ func NewMergedESConfig(
 	ver version.Version,
 	ipFamily corev1.IPFamily,
 	httpConfig commonv1.HTTPConfig,
+	ports esv1.PortsConfig,
+	audit esv1.AuditConfig,
+	auth esv1.Auth,
+	discovery esv1.DiscoveryConfig,
+	nodeAttributes map[string]string,
+	sharedCacheSize *resource.Quantity,
 	userConfig commonv1.Config,
 	esConfigFromStackConfigPolicy *common.CanonicalConfig,
 ) (CanonicalConfig, error) {
@@ -39,10 +49,23 @@ func NewMergedESConfig(
 	if err != nil {
 		return CanonicalConfig{}, err
 	}
+	if err := esversion.ExpandCoordinatingOnlyRole(userCfg, ver); err != nil {
+		return CanonicalConfig{}, err
+	}
+
+	realmsCfg, err := realmsConfig(auth.Realms)
+	if err != nil {
+		return CanonicalConfig{}, err
+	}
 
-	config := baseConfig(clusterName, ver, ipFamily).CanonicalConfig
+	config := baseConfig(clusterName, ver, ipFamily, ports, nodeAttributes).CanonicalConfig
 	err = config.MergeWith(
+		discoveryConfig(ver, discovery),
 		xpackConfig(ver, httpConfig).CanonicalConfig,
+		auditConfig(ver, audit),
+		anonymousConfig(auth.Anonymous),
+		realmsCfg,
+		frozenConfig(sharedCacheSize),
 		userCfg,
 		esConfigFromStackConfigPolicy,
 	)
@@ -53,7 +76,13 @@ func NewMergedESConfig(
 }
 
 // baseConfig returns the base ES configuration to apply for the given cluster
-func baseConfig(clusterName string, ver version.Version, ipFamily corev1.IPFamily) *CanonicalConfig {
+func baseConfig(
+	clusterName string,
+	ver version.Version,
+	ipFamily corev1.IPFamily,
+	ports esv1.PortsConfig,
+	nodeAttributes map[string]string,
+) *CanonicalConfig {
 	cfg := map[string]interface{}{
 		// derive node name dynamically from the pod name, injected as env var
 		esv1.NodeName:    "${" + EnvPodName + "}",
@@ -65,27 +94,68 @@ func baseConfig(clusterName string, ver version.Version, ipFamily corev1.IPFamil
 		esv1.NetworkHost:        "0",
 
 		// allow ES to be aware of k8s node the pod is running on when allocating shards
-		esv1.ShardAwarenessAttributes: nodeAttrK8sNodeName,
-		nodeAttrNodeName:              "${" + EnvNodeName + "}",
+		nodeAttrNodeName: "${" + EnvNodeName + "}",
 
 		esv1.PathData: volume.ElasticsearchDataMountPath,
 		esv1.PathLogs: volume.ElasticsearchLogsMountPath,
 	}
 
-	// seed hosts setting name changed starting ES 7.X
-	fileProvider := "file"
-	if ver.Major < 7 {
-		cfg[esv1.DiscoveryZenHostsProvider] = fileProvider
-	} else {
-		cfg[esv1.DiscoverySeedProviders] = fileProvider
-		// to avoid misleading error messages about the inability to connect to localhost for discovery despite us using
-		// file based discovery
-		cfg[esv1.DiscoverySeedHosts] = []string{}
+	// as well as of the node attributes derived from the labels of the k8s node
+	attributeNames := make([]string, 0, len(nodeAttributes))
+	for name := range nodeAttributes {
+		cfg[esv1.NodeAttr+"."+name] = "${" + NodeAttributeEnvVar(name) + "}"
+		attributeNames = append(attributeNames, name)
+	}
+	sort.Strings(attributeNames)
+	cfg[esv1.ShardAwarenessAttributes] = strings.Join(append([]string{NodeAttrK8sNodeName}, attributeNames...), ",")
+
+	// only set the ports if they differ from the Elasticsearch defaults
+	if ports.HTTP != 0 {
+		cfg[esv1.HTTPPort] = ports.HTTP
+	}
+	if ports.Transport != 0 {
+		cfg[esv1.TransportPort] = ports.Transport
 	}
 
 	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
 }
 
+// discoveryConfig returns the seed hosts discovery settings matching the given discovery configuration.
+func discoveryConfig(ver version.Version, discovery esv1.DiscoveryConfig) *common.CanonicalConfig {
+	var providers []string
+	switch discovery.Provider() {
+	case esv1.FileSeedHostsProvider:
+		providers = []string{string(esv1.FileSeedHostsProvider)}
+	case esv1.CustomSeedHostsProvider:
+		providers = discovery.SeedProviders
+	case esv1.SettingsSeedHostsProvider:
+		// the seed hosts of the configuration are always discovered
+	}
+	// to avoid misleading error messages about the inability to connect to localhost for discovery despite us using
+	// file based discovery, seed hosts are always set
+	seedHosts := discovery.SeedHosts
+	if seedHosts == nil {
+		seedHosts = []string{}
+	}
+
+	cfg := map[string]interface{}{}
+	// seed hosts setting names changed starting ES 7.X
+	if ver.Major < 7 {
+		if len(providers) > 0 {
+			cfg[esv1.DiscoveryZenHostsProvider] = strings.Join(providers, ",")
+		}
+		if len(seedHosts) > 0 {
+			cfg[esv1.DiscoveryZenPingUnicastHosts] = seedHosts
+		}
+	} else {
+		if len(providers) > 0 {
+			cfg[esv1.DiscoverySeedProviders] = strings.Join(providers, ",")
+		}
+		cfg[esv1.DiscoverySeedHosts] = seedHosts
+	}
+	return common.MustCanonicalConfig(cfg)
+}
+
 // xpackConfig returns the configuration bit related to XPack settings
 func xpackConfig(ver version.Version, httpCfg commonv1.HTTPConfig) *CanonicalConfig {
 	// enable x-pack security, including TLS
@@ -132,6 +202,14 @@ func xpackConfig(ver version.Version, httpCfg commonv1.HTTPConfig) *CanonicalCon
 		cfg[esv1.XPackSecurityAuthcRealmsNativeNative1Order] = -99
 	}
 
+	// restrict the TLS protocols and cipher suites accepted on the HTTP layer if requested
+	if protocols := SupportedProtocols(httpCfg.TLS.MinVersion); protocols != nil {
+		cfg[esv1.XPackSecurityHttpSslSupportedProtocols] = protocols
+	}
+	if len(httpCfg.TLS.CipherSuites) > 0 {
+		cfg[esv1.XPackSecurityHttpSslCipherSuites] = httpCfg.TLS.CipherSuites
+	}
+
 	if ver.GTE(version.MustParse("7.8.1")) {
 		cfg[esv1.XPackLicenseUploadTypes] = []string{
 			string(client.ElasticsearchLicenseTypeTrial), string(client.ElasticsearchLicenseTypeEnterprise),
@@ -140,3 +218,41 @@ func xpackConfig(ver version.Version, httpCfg commonv1.HTTPConfig) *CanonicalCon
 
 	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
 }
+
+// auditConfig returns the audit logging settings, or nil if audit logging is not enabled
+func auditConfig(ver version.Version, audit esv1.AuditConfig) *common.CanonicalConfig {
+	if !audit.Enabled {
+		return nil
+	}
+	cfg := map[string]interface{}{
+		esv1.XPackSecurityAuditEnabled: true,
+	}
+	// audit outputs can only be configured before 7.x, where the logfile output became the only one
+	if ver.Major < 7 {
+		cfg[esv1.XPackSecurityAuditOutputs] = []string{"logfile"}
+	}
+	if len(audit.Include) > 0 {
+		cfg[esv1.XPackSecurityAuditLogfileEventsInclude] = audit.Include
+	}
+	if len(audit.Exclude) > 0 {
+		cfg[esv1.XPackSecurityAuditLogfileEventsExclude] = audit.Exclude
+	}
+	return common.MustCanonicalConfig(cfg)
+}
+
+// anonymousConfig returns the anonymous access settings, or nil if anonymous access is not enabled
+func anonymousConfig(anonymous *esv1.AnonymousAccess) *common.CanonicalConfig {
+	if anonymous == nil {
+		return nil
+	}
+	cfg := map[string]interface{}{
+		esv1.XPackSecurityAuthcAnonymousRoles: anonymous.Roles,
+	}
+	if anonymous.Username != "" {
+		cfg[esv1.XPackSecurityAuthcAnonymousUsername] = anonymous.Username
+	}
+	if anonymous.AuthzException != nil {
+		cfg[esv1.XPackSecurityAuthcAnonymousAuthzException] = *anonymous.AuthzException
+	}
+	return common.MustCanonicalConfig(cfg)
+}
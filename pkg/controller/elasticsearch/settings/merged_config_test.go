@@ -11,6 +11,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -31,10 +33,41 @@ func TestNewMergedESConfig(t *testing.T) {
 		} `yaml:"discovery"`
 		HTTP struct {
 			PublishHost string `yaml:"publish_host"`
+			Port        int32  `yaml:"port"`
 		} `yaml:"http"`
+		Transport struct {
+			Port int32 `yaml:"port"`
+		} `yaml:"transport"`
 		Network struct {
 			PublishHost string `yaml:"publish_host"`
 		} `yaml:"network"`
+		XPack struct {
+			Security struct {
+				HTTP struct {
+					SSL struct {
+						SupportedProtocols []string `yaml:"supported_protocols"`
+						CipherSuites       []string `yaml:"cipher_suites"`
+					} `yaml:"ssl"`
+				} `yaml:"http"`
+				Audit struct {
+					Enabled bool     `yaml:"enabled"`
+					Outputs []string `yaml:"outputs"`
+					Logfile struct {
+						Events struct {
+							Include []string `yaml:"include"`
+							Exclude []string `yaml:"exclude"`
+						} `yaml:"events"`
+					} `yaml:"logfile"`
+				} `yaml:"audit"`
+				Authc struct {
+					Anonymous struct {
+						Username       string   `yaml:"username"`
+						Roles          []string `yaml:"roles"`
+						AuthzException *bool    `yaml:"authz_exception"`
+					} `yaml:"anonymous"`
+				} `yaml:"authc"`
+			} `yaml:"security"`
+		} `yaml:"xpack"`
 	}
 
 	policyCfg := common.MustCanonicalConfig(map[string]interface{}{
@@ -42,12 +75,19 @@ func TestNewMergedESConfig(t *testing.T) {
 	})
 
 	tests := []struct {
-		name          string
-		version       string
-		ipFamily      corev1.IPFamily
-		cfgData       map[string]interface{}
-		policyCfgData *common.CanonicalConfig
-		assert        func(cfg CanonicalConfig)
+		name            string
+		version         string
+		ipFamily        corev1.IPFamily
+		ports           esv1.PortsConfig
+		audit           esv1.AuditConfig
+		anonymous       *esv1.AnonymousAccess
+		discovery       esv1.DiscoveryConfig
+		httpConfig      commonv1.HTTPConfig
+		nodeAttributes  map[string]string
+		sharedCacheSize *resource.Quantity
+		cfgData         map[string]interface{}
+		policyCfgData   *common.CanonicalConfig
+		assert          func(cfg CanonicalConfig)
 	}{
 		{
 			name:     "in 6.x, empty config should have the default file and native realm settings configured",
@@ -236,14 +276,440 @@ func TestNewMergedESConfig(t *testing.T) {
 				require.Equal(t, "[${POD_IP}]", esCfg.Network.PublishHost)
 			},
 		},
+		{
+			name:     "default ports are not set",
+			version:  "7.6.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData:  map[string]interface{}{},
+			assert: func(cfg CanonicalConfig) {
+				require.Equal(t, 0, len(cfg.HasKeys([]string{esv1.HTTPPort, esv1.TransportPort})))
+			},
+		},
+		{
+			name:     "overridden ports are set",
+			version:  "7.6.0",
+			ipFamily: corev1.IPv4Protocol,
+			ports:    esv1.PortsConfig{HTTP: 19200, Transport: 19300},
+			cfgData:  map[string]interface{}{},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &elasticsearchCfg{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.Equal(t, int32(19200), esCfg.HTTP.Port)
+				require.Equal(t, int32(19300), esCfg.Transport.Port)
+			},
+		},
+		{
+			name:     "coordinating only shortcut is expanded into an empty list of roles",
+			version:  "7.9.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData: map[string]interface{}{
+				esv1.NodeRoles: []string{"coordinating"},
+			},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				require.Contains(t, string(cfgBytes), "roles: []")
+				require.Equal(t, 0, len(cfg.HasKeys([]string{esv1.NodeMaster, esv1.NodeData})))
+			},
+		},
+		{
+			name:     "coordinating only shortcut is expanded into legacy role settings before 7.9",
+			version:  "7.8.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData: map[string]interface{}{
+				esv1.NodeRoles: []string{"coordinating"},
+			},
+			assert: func(cfg CanonicalConfig) {
+				require.Equal(t, 0, len(cfg.HasKeys([]string{esv1.NodeRoles})))
+				settings, err := cfg.Unpack(version.MustParse("7.8.0"))
+				require.NoError(t, err)
+				require.False(t, settings.Node.IsConfiguredWithRole(esv1.MasterRole))
+				require.False(t, settings.Node.IsConfiguredWithRole(esv1.DataRole))
+				require.False(t, settings.Node.IsConfiguredWithRole(esv1.IngestRole))
+				require.False(t, settings.Node.IsConfiguredWithRole(esv1.MLRole))
+				require.False(t, settings.Node.IsConfiguredWithRole(esv1.TransformRole))
+				require.False(t, settings.Node.IsConfiguredWithRole(esv1.RemoteClusterClientRole))
+			},
+		},
+		{
+			name:     "HTTP TLS protocols and cipher suites are not restricted by default",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			assert: func(cfg CanonicalConfig) {
+				require.Equal(t, 0, len(cfg.HasKeys([]string{esv1.XPackSecurityHttpSslSupportedProtocols})))
+				require.Equal(t, 0, len(cfg.HasKeys([]string{esv1.XPackSecurityHttpSslCipherSuites})))
+			},
+		},
+		{
+			name:     "HTTP TLS minimum version and cipher suites are set from the HTTP TLS options",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			httpConfig: commonv1.HTTPConfig{TLS: commonv1.TLSOptions{
+				MinVersion:   "TLSv1.2",
+				CipherSuites: []string{"TLS_AES_256_GCM_SHA384", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+			}},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &elasticsearchCfg{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.Equal(t, []string{"TLSv1.3", "TLSv1.2"}, esCfg.XPack.Security.HTTP.SSL.SupportedProtocols)
+				require.Equal(t, []string{"TLS_AES_256_GCM_SHA384", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}, esCfg.XPack.Security.HTTP.SSL.CipherSuites)
+			},
+		},
+		{
+			name:     "HTTP TLS is enabled by default",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			assert: func(cfg CanonicalConfig) {
+				enabled, err := cfg.String(esv1.XPackSecurityHttpSslEnabled)
+				require.NoError(t, err)
+				require.Equal(t, "true", enabled)
+			},
+		},
+		{
+			name:     "HTTP TLS is disabled if the self-signed certificate is disabled without a user-provided certificate",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			httpConfig: commonv1.HTTPConfig{TLS: commonv1.TLSOptions{
+				SelfSignedCertificate: &commonv1.SelfSignedCertificate{Disabled: true},
+			}},
+			assert: func(cfg CanonicalConfig) {
+				enabled, err := cfg.String(esv1.XPackSecurityHttpSslEnabled)
+				require.NoError(t, err)
+				require.Equal(t, "false", enabled)
+			},
+		},
+		{
+			name:     "HTTP TLS is enabled with a user-provided certificate",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			httpConfig: commonv1.HTTPConfig{TLS: commonv1.TLSOptions{
+				SelfSignedCertificate: &commonv1.SelfSignedCertificate{Disabled: true},
+				Certificate:           commonv1.SecretRef{SecretName: "my-cert"},
+			}},
+			assert: func(cfg CanonicalConfig) {
+				enabled, err := cfg.String(esv1.XPackSecurityHttpSslEnabled)
+				require.NoError(t, err)
+				require.Equal(t, "true", enabled)
+			},
+		},
+		{
+			name:     "shard allocation awareness only relies on the k8s node name by default",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			assert: func(cfg CanonicalConfig) {
+				awarenessAttributes, err := cfg.String(esv1.ShardAwarenessAttributes)
+				require.NoError(t, err)
+				require.Equal(t, "k8s_node_name", awarenessAttributes)
+			},
+		},
+		{
+			name:     "node attributes are set from environment variables and used for shard allocation awareness",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			nodeAttributes: map[string]string{
+				"zone": "topology.kubernetes.io/zone",
+				"rack": "example.com/rack",
+			},
+			assert: func(cfg CanonicalConfig) {
+				awarenessAttributes, err := cfg.String(esv1.ShardAwarenessAttributes)
+				require.NoError(t, err)
+				require.Equal(t, "k8s_node_name,rack,zone", awarenessAttributes)
+				zone, err := cfg.String("node.attr.zone")
+				require.NoError(t, err)
+				require.Equal(t, "${NODE_ATTR_zone}", zone)
+				rack, err := cfg.String("node.attr.rack")
+				require.NoError(t, err)
+				require.Equal(t, "${NODE_ATTR_rack}", rack)
+			},
+		},
+		{
+			name:     "user provided shard allocation awareness attributes take precedence",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			nodeAttributes: map[string]string{
+				"zone": "topology.kubernetes.io/zone",
+			},
+			cfgData: map[string]interface{}{
+				esv1.ShardAwarenessAttributes: "zone",
+			},
+			assert: func(cfg CanonicalConfig) {
+				awarenessAttributes, err := cfg.String(esv1.ShardAwarenessAttributes)
+				require.NoError(t, err)
+				require.Equal(t, "zone", awarenessAttributes)
+				require.Equal(t, 1, len(cfg.HasKeys([]string{"node.attr.zone"})))
+			},
+		},
+		{
+			name:     "audit logging is not configured by default",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			assert: func(cfg CanonicalConfig) {
+				require.Equal(t, 0, len(cfg.HasKeys([]string{
+					esv1.XPackSecurityAuditEnabled,
+					esv1.XPackSecurityAuditOutputs,
+					esv1.XPackSecurityAuditLogfileEventsInclude,
+					esv1.XPackSecurityAuditLogfileEventsExclude,
+				})))
+			},
+		},
+		{
+			name:     "audit logging is enabled with the given event types",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			audit: esv1.AuditConfig{
+				Enabled: true,
+				Include: []string{"access_denied", "authentication_failed"},
+				Exclude: []string{"access_granted"},
+			},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &elasticsearchCfg{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.True(t, esCfg.XPack.Security.Audit.Enabled)
+				require.Equal(t, []string{"access_denied", "authentication_failed"}, esCfg.XPack.Security.Audit.Logfile.Events.Include)
+				require.Equal(t, []string{"access_granted"}, esCfg.XPack.Security.Audit.Logfile.Events.Exclude)
+				// outputs cannot be configured as of 7.x
+				require.Equal(t, 0, len(cfg.HasKeys([]string{esv1.XPackSecurityAuditOutputs})))
+			},
+		},
+		{
+			name:     "in 6.x, audit logging is enabled with the logfile output",
+			version:  "6.8.0",
+			ipFamily: corev1.IPv4Protocol,
+			audit:    esv1.AuditConfig{Enabled: true},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &elasticsearchCfg{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.True(t, esCfg.XPack.Security.Audit.Enabled)
+				require.Equal(t, []string{"logfile"}, esCfg.XPack.Security.Audit.Outputs)
+				require.Empty(t, esCfg.XPack.Security.Audit.Logfile.Events.Include)
+				require.Empty(t, esCfg.XPack.Security.Audit.Logfile.Events.Exclude)
+			},
+		},
+		{
+			name:     "anonymous access is not configured by default",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			assert: func(cfg CanonicalConfig) {
+				require.Equal(t, 0, len(cfg.HasKeys([]string{
+					esv1.XPackSecurityAuthcAnonymousUsername,
+					esv1.XPackSecurityAuthcAnonymousRoles,
+					esv1.XPackSecurityAuthcAnonymousAuthzException,
+				})))
+			},
+		},
+		{
+			name:      "anonymous access is enabled with the given roles",
+			version:   "8.12.0",
+			ipFamily:  corev1.IPv4Protocol,
+			anonymous: &esv1.AnonymousAccess{Roles: []string{"dashboards-reader"}},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &elasticsearchCfg{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.Equal(t, []string{"dashboards-reader"}, esCfg.XPack.Security.Authc.Anonymous.Roles)
+				// the Elasticsearch defaults apply to the other settings
+				require.Equal(t, 0, len(cfg.HasKeys([]string{
+					esv1.XPackSecurityAuthcAnonymousUsername,
+					esv1.XPackSecurityAuthcAnonymousAuthzException,
+				})))
+			},
+		},
+		{
+			name:     "anonymous access is enabled with a custom user name and without authorization exception",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			anonymous: &esv1.AnonymousAccess{
+				Username:       "dashboards",
+				Roles:          []string{"dashboards-reader", "viewer"},
+				AuthzException: ptr.To(false),
+			},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &elasticsearchCfg{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.Equal(t, "dashboards", esCfg.XPack.Security.Authc.Anonymous.Username)
+				require.Equal(t, []string{"dashboards-reader", "viewer"}, esCfg.XPack.Security.Authc.Anonymous.Roles)
+				require.Equal(t, ptr.To(false), esCfg.XPack.Security.Authc.Anonymous.AuthzException)
+			},
+		},
+		{
+			name:     "user provided audit event types are appended to the ones from the spec",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			audit: esv1.AuditConfig{
+				Enabled: true,
+				Include: []string{"access_denied"},
+			},
+			cfgData: map[string]interface{}{
+				esv1.XPackSecurityAuditLogfileEventsInclude: []string{"authentication_failed"},
+			},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &elasticsearchCfg{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.True(t, esCfg.XPack.Security.Audit.Enabled)
+				require.Equal(t, []string{"access_denied", "authentication_failed"}, esCfg.XPack.Security.Audit.Logfile.Events.Include)
+			},
+		},
+		{
+			name:     "shared cache size is not configured by default",
+			version:  "8.12.0",
+			ipFamily: corev1.IPv4Protocol,
+			assert: func(cfg CanonicalConfig) {
+				require.Equal(t, 0, len(cfg.HasKeys([]string{esv1.XPackSearchableSnapshotSharedCacheSize})))
+			},
+		},
+		{
+			name:            "shared cache size is set in bytes",
+			version:         "8.12.0",
+			ipFamily:        corev1.IPv4Protocol,
+			sharedCacheSize: resource.NewQuantity(9*1024*1024*1024, resource.BinarySI),
+			assert: func(cfg CanonicalConfig) {
+				size, err := cfg.String(esv1.XPackSearchableSnapshotSharedCacheSize)
+				require.NoError(t, err)
+				require.Equal(t, "9663676416b", size)
+			},
+		},
+		{
+			name:            "user provided shared cache size takes precedence",
+			version:         "8.12.0",
+			ipFamily:        corev1.IPv4Protocol,
+			sharedCacheSize: resource.NewQuantity(9*1024*1024*1024, resource.BinarySI),
+			cfgData: map[string]interface{}{
+				esv1.XPackSearchableSnapshotSharedCacheSize: "50%",
+			},
+			assert: func(cfg CanonicalConfig) {
+				size, err := cfg.String(esv1.XPackSearchableSnapshotSharedCacheSize)
+				require.NoError(t, err)
+				require.Equal(t, "50%", size)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ver, err := version.Parse(tt.version)
 			require.NoError(t, err)
-			cfg, err := NewMergedESConfig("clusterName", ver, tt.ipFamily, commonv1.HTTPConfig{}, commonv1.Config{Data: tt.cfgData}, tt.policyCfgData)
+			cfg, err := NewMergedESConfig("clusterName", ver, tt.ipFamily, tt.httpConfig, tt.ports, tt.audit, esv1.Auth{Anonymous: tt.anonymous}, tt.discovery, tt.nodeAttributes, tt.sharedCacheSize, commonv1.Config{Data: tt.cfgData}, tt.policyCfgData)
 			require.NoError(t, err)
 			tt.assert(cfg)
 		})
 	}
 }
+
+func Test_discoveryConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   string
+		discovery esv1.DiscoveryConfig
+		want      map[string]interface{}
+	}{
+		{
+			name:    "file provider by default",
+			version: "8.12.0",
+			want: map[string]interface{}{
+				esv1.DiscoverySeedProviders: "file",
+				esv1.DiscoverySeedHosts:     []string{},
+			},
+		},
+		{
+			name:      "file provider with additional seed hosts",
+			version:   "8.12.0",
+			discovery: esv1.DiscoveryConfig{SeedHostsProvider: esv1.FileSeedHostsProvider, SeedHosts: []string{"10.0.0.1:9300"}},
+			want: map[string]interface{}{
+				esv1.DiscoverySeedProviders: "file",
+				esv1.DiscoverySeedHosts:     []string{"10.0.0.1:9300"},
+			},
+		},
+		{
+			name:      "settings provider",
+			version:   "8.12.0",
+			discovery: esv1.DiscoveryConfig{SeedHostsProvider: esv1.SettingsSeedHostsProvider, SeedHosts: []string{"10.0.0.1:9300", "10.0.0.2:9300"}},
+			want: map[string]interface{}{
+				esv1.DiscoverySeedHosts: []string{"10.0.0.1:9300", "10.0.0.2:9300"},
+			},
+		},
+		{
+			name:      "custom providers",
+			version:   "8.12.0",
+			discovery: esv1.DiscoveryConfig{SeedHostsProvider: esv1.CustomSeedHostsProvider, SeedProviders: []string{"ec2", "file"}},
+			want: map[string]interface{}{
+				esv1.DiscoverySeedProviders: "ec2,file",
+				esv1.DiscoverySeedHosts:     []string{},
+			},
+		},
+		{
+			name:    "file provider before 7.0.0",
+			version: "6.8.0",
+			want: map[string]interface{}{
+				esv1.DiscoveryZenHostsProvider: "file",
+			},
+		},
+		{
+			name:      "settings provider before 7.0.0",
+			version:   "6.8.0",
+			discovery: esv1.DiscoveryConfig{SeedHostsProvider: esv1.SettingsSeedHostsProvider, SeedHosts: []string{"10.0.0.1:9300"}},
+			want: map[string]interface{}{
+				esv1.DiscoveryZenPingUnicastHosts: []string{"10.0.0.1:9300"},
+			},
+		},
+		{
+			name:      "custom providers before 7.0.0",
+			version:   "6.8.0",
+			discovery: esv1.DiscoveryConfig{SeedHostsProvider: esv1.CustomSeedHostsProvider, SeedProviders: []string{"ec2"}, SeedHosts: []string{"10.0.0.1:9300"}},
+			want: map[string]interface{}{
+				esv1.DiscoveryZenHostsProvider:    "ec2",
+				esv1.DiscoveryZenPingUnicastHosts: []string{"10.0.0.1:9300"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := NewMergedESConfig(
+				"clusterName", version.MustParse(tt.version), corev1.IPv4Protocol, commonv1.HTTPConfig{}, esv1.PortsConfig{},
+				esv1.AuditConfig{}, esv1.Auth{}, tt.discovery, nil, nil, commonv1.Config{}, nil,
+			)
+			require.NoError(t, err)
+			var got struct {
+				SeedProviders    string   `config:"discovery.seed_providers"`
+				SeedHosts        []string `config:"discovery.seed_hosts"`
+				ZenHostsProvider string   `config:"discovery.zen.hosts_provider"`
+				ZenUnicastHosts  []string `config:"discovery.zen.ping.unicast.hosts"`
+			}
+			require.NoError(t, cfg.CanonicalConfig.Unpack(&got))
+			values := map[string]interface{}{
+				esv1.DiscoverySeedProviders:       got.SeedProviders,
+				esv1.DiscoverySeedHosts:           got.SeedHosts,
+				esv1.DiscoveryZenHostsProvider:    got.ZenHostsProvider,
+				esv1.DiscoveryZenPingUnicastHosts: got.ZenUnicastHosts,
+			}
+			for key, value := range values {
+				want, isExpected := tt.want[key]
+				if !isExpected {
+					require.Empty(t, cfg.HasKeys([]string{key}), key)
+					continue
+				}
+				if hosts, isList := want.([]string); isList && len(hosts) == 0 {
+					// empty lists are not reported by HasKeys, but are rendered
+					require.Empty(t, value, key)
+					rendered, err := cfg.Render()
+					require.NoError(t, err)
+					require.Contains(t, string(rendered), "seed_hosts: []")
+					continue
+				}
+				require.NotEmpty(t, cfg.HasKeys([]string{key}), key)
+				require.Equal(t, want, value, key)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"strconv"
+
+	"k8s.io/utils/ptr"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+)
+
+// DefaultMLNativeMemoryPercent is the default percentage of the memory of machine learning nodes reserved for the
+// native processes of machine learning jobs. It matches the Elasticsearch default of xpack.ml.max_machine_memory_percent.
+const DefaultMLNativeMemoryPercent = 30
+
+// hasExplicitMLRole returns true if the ml role is explicitly enabled in the given user-provided configuration, as
+// opposed to nodes relying on the default roles.
+func hasExplicitMLRole(userCfg *common.CanonicalConfig) (bool, error) {
+	// unpack without the default roles
+	var cfg esv1.ElasticsearchSettings
+	if err := userCfg.Unpack(&cfg); err != nil {
+		return false, err
+	}
+	if cfg.Node == nil {
+		return false, nil
+	}
+	if cfg.Node.Roles != nil {
+		return cfg.Node.IsConfiguredWithRole(esv1.MLRole), nil
+	}
+	return ptr.Deref(cfg.Node.ML, false), nil
+}
+
+// MLNativeMemoryPercent returns the percentage of memory reserved for the native processes of machine learning jobs:
+// the value of xpack.ml.max_machine_memory_percent if set in this configuration, the Elasticsearch default for nodes
+// explicitly configured with the ml role, or 0 for other nodes.
+func (c CanonicalConfig) MLNativeMemoryPercent() (int, error) {
+	if len(c.HasKeys([]string{esv1.XPackMLMaxMachineMemoryPercent})) == 0 {
+		isMLNode, err := hasExplicitMLRole(c.CanonicalConfig)
+		if err != nil || !isMLNode {
+			return 0, err
+		}
+		return DefaultMLNativeMemoryPercent, nil
+	}
+	percent, err := c.String(esv1.XPackMLMaxMachineMemoryPercent)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(percent)
+}
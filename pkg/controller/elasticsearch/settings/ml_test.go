@@ -0,0 +1,83 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestMLNativeMemoryPercent(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		cfg     map[string]interface{}
+		want    int
+	}{
+		{
+			name:    "default roles: no memory reserved",
+			version: "8.12.0",
+			cfg:     map[string]interface{}{},
+			want:    0,
+		},
+		{
+			name:    "roles without ml: no memory reserved",
+			version: "8.12.0",
+			cfg:     map[string]interface{}{"node.roles": []string{"master", "data"}},
+			want:    0,
+		},
+		{
+			name:    "ml node: default percentage",
+			version: "8.12.0",
+			cfg:     map[string]interface{}{"node.roles": []string{"ml"}},
+			want:    DefaultMLNativeMemoryPercent,
+		},
+		{
+			name:    "data and ml node: default percentage",
+			version: "8.12.0",
+			cfg:     map[string]interface{}{"node.roles": []string{"data", "ml"}},
+			want:    DefaultMLNativeMemoryPercent,
+		},
+		{
+			name:    "ml node with legacy role settings: default percentage",
+			version: "7.8.0",
+			cfg:     map[string]interface{}{"node.ml": true},
+			want:    DefaultMLNativeMemoryPercent,
+		},
+		{
+			name:    "user-provided percentage takes precedence",
+			version: "8.12.0",
+			cfg:     map[string]interface{}{"node.roles": []string{"ml"}, esv1.XPackMLMaxMachineMemoryPercent: 40},
+			want:    40,
+		},
+		{
+			name:    "user-provided percentage on a node with default roles",
+			version: "8.12.0",
+			cfg:     map[string]interface{}{esv1.XPackMLMaxMachineMemoryPercent: 40},
+			want:    40,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := NewMergedESConfig(
+				"clusterName", version.MustParse(tt.version), corev1.IPv4Protocol, commonv1.HTTPConfig{}, esv1.PortsConfig{},
+				esv1.AuditConfig{}, esv1.Auth{}, esv1.DiscoveryConfig{}, nil, nil, commonv1.Config{Data: tt.cfg}, nil,
+			)
+			require.NoError(t, err)
+			got, err := cfg.MLNativeMemoryPercent()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			// the Elasticsearch default is not injected in the configuration
+			_, hasSetting := tt.cfg[esv1.XPackMLMaxMachineMemoryPercent]
+			require.Equal(t, hasSetting, len(cfg.HasKeys([]string{esv1.XPackMLMaxMachineMemoryPercent})) > 0)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"path"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+// realmsConfig returns the configuration of the SAML and OpenID Connect realms. The secrets of the SAML realms are
+// expected to be mounted in the directories returned by volume.SAMLRealmMountPath, the secure settings of the realms
+// are added to the keystore. The additional settings of each realm override the generated ones.
+func realmsConfig(realms esv1.Realms) (*common.CanonicalConfig, error) {
+	if len(realms.SAML) == 0 && len(realms.OIDC) == 0 {
+		return nil, nil
+	}
+	cfg := map[string]interface{}{}
+	additional := map[string]interface{}{}
+
+	for _, realm := range realms.SAML {
+		cfg[realm.Setting("order")] = realm.Order
+		cfg[realm.Setting("idp.entity_id")] = realm.IdP.EntityID
+		if realm.IdP.MetadataSecretRef != nil && realm.IdP.MetadataSecretRef.SecretName != "" {
+			cfg[realm.Setting("idp.metadata.path")] = path.Join(volume.SAMLRealmMountPath(realm.Name, volume.SAMLRealmMetadata), esv1.SAMLMetadataSecretKey)
+		} else {
+			cfg[realm.Setting("idp.metadata.path")] = realm.IdP.MetadataURL
+		}
+		cfg[realm.Setting("sp.entity_id")] = realm.SP.EntityID
+		cfg[realm.Setting("sp.acs")] = realm.SP.ACS
+		if realm.SP.Logout != "" {
+			cfg[realm.Setting("sp.logout")] = realm.SP.Logout
+		}
+		for property, attribute := range realm.Attributes {
+			cfg[realm.Setting("attributes."+property)] = attribute
+		}
+		for secret, credentials := range map[string]*esv1.RealmCredentials{
+			volume.SAMLRealmSigning:    realm.Signing,
+			volume.SAMLRealmEncryption: realm.Encryption,
+		} {
+			if credentials == nil || credentials.SecretName == "" {
+				continue
+			}
+			mountPath := volume.SAMLRealmMountPath(realm.Name, secret)
+			cfg[realm.Setting(secret+".certificate")] = path.Join(mountPath, certificates.CertFileName)
+			cfg[realm.Setting(secret+".key")] = path.Join(mountPath, certificates.KeyFileName)
+		}
+		if realm.Config != nil {
+			for k, v := range realm.Config.Data {
+				additional[realm.Setting(k)] = v
+			}
+		}
+	}
+
+	for _, realm := range realms.OIDC {
+		cfg[realm.Setting("order")] = realm.Order
+		cfg[realm.Setting("rp.client_id")] = realm.RP.ClientID
+		cfg[realm.Setting("rp.response_type")] = realm.RP.ResponseType
+		cfg[realm.Setting("rp.redirect_uri")] = realm.RP.RedirectURI
+		if realm.RP.PostLogoutRedirectURI != "" {
+			cfg[realm.Setting("rp.post_logout_redirect_uri")] = realm.RP.PostLogoutRedirectURI
+		}
+		if len(realm.RP.RequestedScopes) > 0 {
+			cfg[realm.Setting("rp.requested_scopes")] = realm.RP.RequestedScopes
+		}
+		cfg[realm.Setting("op.issuer")] = realm.OP.Issuer
+		cfg[realm.Setting("op.authorization_endpoint")] = realm.OP.AuthorizationEndpoint
+		cfg[realm.Setting("op.jwkset_path")] = realm.OP.JWKSetPath
+		for setting, value := range map[string]string{
+			"op.token_endpoint":      realm.OP.TokenEndpoint,
+			"op.userinfo_endpoint":   realm.OP.UserinfoEndpoint,
+			"op.endsession_endpoint": realm.OP.EndSessionEndpoint,
+		} {
+			if value != "" {
+				cfg[realm.Setting(setting)] = value
+			}
+		}
+		for property, claim := range realm.Claims {
+			cfg[realm.Setting("claims."+property)] = claim
+		}
+		if realm.Config != nil {
+			for k, v := range realm.Config.Data {
+				additional[realm.Setting(k)] = v
+			}
+		}
+	}
+
+	config := common.MustCanonicalConfig(cfg)
+	additionalConfig, err := common.NewCanonicalConfigFrom(additional)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.MergeWith(additionalConfig); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
@@ -0,0 +1,129 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+)
+
+func Test_realmsConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		realms esv1.Realms
+		want   string
+	}{
+		{
+			name:   "no realm",
+			realms: esv1.Realms{},
+		},
+		{
+			name: "SAML realm with the metadata in a secret and signing credentials",
+			realms: esv1.Realms{SAML: []esv1.SAMLRealm{{
+				Name:  "saml1",
+				Order: 2,
+				IdP: esv1.SAMLIdentityProvider{
+					EntityID:          "https://idp.example.com",
+					MetadataSecretRef: &commonv1.SecretRef{SecretName: "idp-metadata"},
+				},
+				SP: esv1.SAMLServiceProvider{
+					EntityID: "https://kibana.example.com",
+					ACS:      "https://kibana.example.com/api/security/saml/callback",
+					Logout:   "https://kibana.example.com/logout",
+				},
+				Attributes: map[string]string{"principal": "nameid", "groups": "groups"},
+				Signing:    &esv1.RealmCredentials{SecretRef: commonv1.SecretRef{SecretName: "saml-signing"}},
+			}}},
+			want: `
+xpack.security.authc.realms.saml.saml1:
+  order: 2
+  idp.entity_id: https://idp.example.com
+  idp.metadata.path: /usr/share/elasticsearch/config/realms/saml/saml1/metadata/metadata.xml
+  sp.entity_id: https://kibana.example.com
+  sp.acs: https://kibana.example.com/api/security/saml/callback
+  sp.logout: https://kibana.example.com/logout
+  attributes.principal: nameid
+  attributes.groups: groups
+  signing.certificate: /usr/share/elasticsearch/config/realms/saml/saml1/signing/tls.crt
+  signing.key: /usr/share/elasticsearch/config/realms/saml/saml1/signing/tls.key
+`,
+		},
+		{
+			name: "SAML realm with the metadata URL and additional settings",
+			realms: esv1.Realms{SAML: []esv1.SAMLRealm{{
+				Name:  "saml1",
+				Order: 2,
+				IdP:   esv1.SAMLIdentityProvider{EntityID: "https://idp.example.com", MetadataURL: "https://idp.example.com/metadata"},
+				SP:    esv1.SAMLServiceProvider{EntityID: "https://kibana.example.com", ACS: "https://kibana.example.com/acs"},
+				Config: &commonv1.Config{Data: map[string]interface{}{
+					"sp.entity_id":      "https://kibana.example.com/overridden",
+					"nameid_format":     "urn:oasis:names:tc:SAML:2.0:nameid-format:transient",
+					"attributes.groups": "groups",
+				}},
+			}}},
+			want: `
+xpack.security.authc.realms.saml.saml1:
+  order: 2
+  idp.entity_id: https://idp.example.com
+  idp.metadata.path: https://idp.example.com/metadata
+  sp.entity_id: https://kibana.example.com/overridden
+  sp.acs: https://kibana.example.com/acs
+  nameid_format: urn:oasis:names:tc:SAML:2.0:nameid-format:transient
+  attributes.groups: groups
+`,
+		},
+		{
+			name: "OpenID Connect realm",
+			realms: esv1.Realms{OIDC: []esv1.OIDCRealm{{
+				Name:  "oidc1",
+				Order: 3,
+				RP: esv1.OIDCRelyingParty{
+					ClientID:        "kibana",
+					ClientSecretRef: commonv1.SecretRef{SecretName: "oidc-client"},
+					ResponseType:    "code",
+					RedirectURI:     "https://kibana.example.com/api/security/oidc/callback",
+					RequestedScopes: []string{"profile", "email"},
+				},
+				OP: esv1.OIDCProvider{
+					Issuer:                "https://op.example.com",
+					AuthorizationEndpoint: "https://op.example.com/auth",
+					TokenEndpoint:         "https://op.example.com/token",
+					JWKSetPath:            "https://op.example.com/jwks",
+				},
+				Claims: map[string]string{"principal": "sub"},
+			}}},
+			want: `
+xpack.security.authc.realms.oidc.oidc1:
+  order: 3
+  rp.client_id: kibana
+  rp.response_type: code
+  rp.redirect_uri: https://kibana.example.com/api/security/oidc/callback
+  rp.requested_scopes: [profile, email]
+  op.issuer: https://op.example.com
+  op.authorization_endpoint: https://op.example.com/auth
+  op.token_endpoint: https://op.example.com/token
+  op.jwkset_path: https://op.example.com/jwks
+  claims.principal: sub
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := realmsConfig(tt.realms)
+			require.NoError(t, err)
+			if tt.want == "" {
+				require.Nil(t, got)
+				return
+			}
+			want := common.MustParseConfig([]byte(tt.want))
+			require.Empty(t, got.Diff(want, nil))
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"strings"
+)
+
+const (
+	TLSv11 = "TLSv1.1"
+	TLSv12 = "TLSv1.2"
+	TLSv13 = "TLSv1.3"
+)
+
+// tlsVersions are the TLS protocol versions supported by Elasticsearch, from the most recent to the oldest.
+var tlsVersions = []string{TLSv13, TLSv12, TLSv11}
+
+// SupportedProtocols returns the TLS protocol versions at or above the given minimum version, or nil if the version
+// is not a known TLS version.
+func SupportedProtocols(minVersion string) []string {
+	for i, v := range tlsVersions {
+		if v == minVersion {
+			return append([]string(nil), tlsVersions[:i+1]...)
+		}
+	}
+	return nil
+}
+
+// KnownCipherSuites are the cipher suites Elasticsearch can be configured with on a JVM supporting them.
+var KnownCipherSuites = map[string]struct{}{
+	// TLSv1.3
+	"TLS_AES_256_GCM_SHA384":       {},
+	"TLS_AES_128_GCM_SHA256":       {},
+	"TLS_CHACHA20_POLY1305_SHA256": {},
+	// TLSv1.2 and lower
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384":       {},
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256":       {},
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256": {},
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":         {},
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":         {},
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256":   {},
+	"TLS_DHE_RSA_WITH_AES_256_GCM_SHA384":           {},
+	"TLS_DHE_RSA_WITH_AES_128_GCM_SHA256":           {},
+	"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA384":       {},
+	"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA384":         {},
+	"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256":       {},
+	"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256":         {},
+	"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA":          {},
+	"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA":            {},
+	"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA":          {},
+	"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA":            {},
+	"TLS_RSA_WITH_AES_256_GCM_SHA384":               {},
+	"TLS_RSA_WITH_AES_128_GCM_SHA256":               {},
+	"TLS_RSA_WITH_AES_256_CBC_SHA256":               {},
+	"TLS_RSA_WITH_AES_128_CBC_SHA256":               {},
+	"TLS_RSA_WITH_AES_256_CBC_SHA":                  {},
+	"TLS_RSA_WITH_AES_128_CBC_SHA":                  {},
+}
+
+// IsTLSv13CipherSuite returns true if the given cipher suite can only be negotiated with TLSv1.3.
+// TLSv1.3 cipher suites do not specify the key exchange and authentication algorithms.
+func IsTLSv13CipherSuite(cipherSuite string) bool {
+	return !strings.Contains(cipherSuite, "_WITH_")
+}
@@ -0,0 +1,102 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	pkgerrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	commonvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// truststorePassword protects the integrity of the generated truststore, which only holds public certificates.
+// It is the well-known password of the default truststore of the JVM.
+const truststorePassword = "changeit" //nolint:gosec
+
+// MinTrustedCAVersion is the first version of Elasticsearch bundling the JDK used to generate the truststore.
+var MinTrustedCAVersion = version.MinFor(7, 0, 0)
+
+// TrustedCASecretVolume returns the volume mounting the additional CA certificates trusted by Elasticsearch.
+func TrustedCASecretVolume(esName string) commonvolume.SecretVolume {
+	return commonvolume.NewSecretVolumeWithMountPath(
+		esv1.TrustedCASecret(esName),
+		volume.TrustedCAVolumeName,
+		volume.TrustedCAVolumeMountPath,
+	)
+}
+
+// TruststoreVolume returns the volume holding the truststore generated from the default CA certificates of the JVM and
+// the additional ones.
+func TruststoreVolume() commonvolume.EmptyDirVolume {
+	return commonvolume.NewEmptyDirVolume(volume.TruststoreVolumeName, volume.TruststoreVolumeMountPath)
+}
+
+// TruststoreJavaOpts returns the JVM options making the generated truststore the default one of the JVM.
+func TruststoreJavaOpts() []string {
+	return []string{
+		"-Djavax.net.ssl.trustStore=" + path.Join(volume.TruststoreVolumeMountPath, volume.TruststoreFile),
+		"-Djavax.net.ssl.trustStoreType=PKCS12",
+		"-Djavax.net.ssl.trustStorePassword=" + truststorePassword,
+	}
+}
+
+// UserProvidedTrustedCAWatchName returns the watch registered for the user-provided CA certificates secret.
+func UserProvidedTrustedCAWatchName(es types.NamespacedName) string { //nolint:revive
+	return fmt.Sprintf("%s-%s-user-trusted-ca", es.Namespace, es.Name)
+}
+
+// ReconcileTrustedCA ensures the Secret holding the additional CA certificates trusted by the Elasticsearch nodes
+// exists if the user references such certificates, and is deleted otherwise.
+// It also ensures the user-provided secret is watched for future reconciliations to be triggered on any change.
+func ReconcileTrustedCA(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, watched watches.DynamicWatches) error {
+	esKey := k8s.ExtractNamespacedName(&es)
+	var userSecrets []string
+	if es.HasTrustedCertificateAuthorities() {
+		userSecrets = append(userSecrets, es.Spec.TrustedCertificateAuthorities.SecretName)
+	}
+	if err := watches.WatchUserProvidedSecrets(esKey, watched, UserProvidedTrustedCAWatchName(esKey), userSecrets); err != nil {
+		return err
+	}
+
+	secretKey := types.NamespacedName{Namespace: es.Namespace, Name: esv1.TrustedCASecret(es.Name)}
+	if !es.HasTrustedCertificateAuthorities() {
+		return k8s.DeleteSecretIfExists(ctx, c, secretKey)
+	}
+	var userSecret corev1.Secret
+	userSecretKey := types.NamespacedName{Namespace: es.Namespace, Name: es.Spec.TrustedCertificateAuthorities.SecretName}
+	if err := c.Get(ctx, userSecretKey, &userSecret); err != nil {
+		return err
+	}
+	content, exists := userSecret.Data[certificates.CAFileName]
+	if !exists {
+		return pkgerrors.Errorf("no %s entry found in secret %s", certificates.CAFileName, userSecretKey)
+	}
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: secretKey.Namespace,
+			Name:      secretKey.Name,
+			Labels:    label.NewLabels(esKey),
+		},
+		Data: map[string][]byte{
+			certificates.CAFileName: content,
+		},
+	}
+	_, err := reconciler.ReconcileSecret(ctx, c, expected, &es)
+	return err
+}
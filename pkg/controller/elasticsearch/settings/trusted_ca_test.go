@@ -0,0 +1,58 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestReconcileTrustedCA(t *testing.T) {
+	ctx := context.Background()
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{TrustedCertificateAuthorities: &commonv1.SecretRef{SecretName: "my-ca"}},
+	}
+	userSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "my-ca"},
+		Data:       map[string][]byte{certificates.CAFileName: []byte("-----BEGIN CERTIFICATE-----")},
+	}
+	invalidSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "invalid-ca"},
+		Data:       map[string][]byte{"tls.crt": []byte("-----BEGIN CERTIFICATE-----")},
+	}
+	c := k8s.NewFakeClient(&es, userSecret, invalidSecret)
+	w := watches.NewDynamicWatches()
+	key := types.NamespacedName{Namespace: "ns", Name: esv1.TrustedCASecret("es")}
+
+	// the user-provided certificates are copied and watched
+	require.NoError(t, ReconcileTrustedCA(ctx, c, es, w))
+	var secret corev1.Secret
+	require.NoError(t, c.Get(ctx, key, &secret))
+	require.Equal(t, []byte("-----BEGIN CERTIFICATE-----"), secret.Data[certificates.CAFileName])
+	require.Equal(t, []string{UserProvidedTrustedCAWatchName(k8s.ExtractNamespacedName(&es))}, w.Secrets.Registrations())
+
+	// the user-provided secret must hold the certificates under the ca.crt key
+	es.Spec.TrustedCertificateAuthorities = &commonv1.SecretRef{SecretName: "invalid-ca"}
+	require.Error(t, ReconcileTrustedCA(ctx, c, es, w))
+
+	// the secret and the watch are removed once the certificates are not referenced anymore
+	es.Spec.TrustedCertificateAuthorities = nil
+	require.NoError(t, ReconcileTrustedCA(ctx, c, es, w))
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, key, &secret)))
+	require.Empty(t, w.Secrets.Registrations())
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 
@@ -17,13 +18,16 @@ import (
 // NodeShutdown implements the shutdown.Interface with the Elasticsearch node shutdown API. It is not safe to call methods
 // on this struct concurrently from multiple go-routines.
 type NodeShutdown struct {
-	c           esclient.Client
-	typ         esclient.ShutdownType
-	reason      string
-	podToNodeID map[string]string
-	shutdowns   map[string]esclient.NodeShutdown
-	once        sync.Once
-	log         logr.Logger
+	c      esclient.Client
+	typ    esclient.ShutdownType
+	reason string
+	// allocationDelay is the delay to wait for before reallocating the shards of a restarting node, zero to rely on the
+	// Elasticsearch default.
+	allocationDelay time.Duration
+	podToNodeID     map[string]string
+	shutdowns       map[string]esclient.NodeShutdown
+	once            sync.Once
+	log             logr.Logger
 }
 
 var _ Interface = &NodeShutdown{}
@@ -41,6 +45,13 @@ func NewNodeShutdown(c esclient.Client, podToNodeID map[string]string, typ escli
 	}
 }
 
+// WithAllocationDelay sets the delay Elasticsearch waits for before reallocating the shards of nodes restarted through
+// new shutdown requests. Only relevant for the restart shutdown type.
+func (ns *NodeShutdown) WithAllocationDelay(allocationDelay time.Duration) *NodeShutdown {
+	ns.allocationDelay = allocationDelay
+	return ns
+}
+
 func (ns *NodeShutdown) initOnce(ctx context.Context) error {
 	var err error
 	ns.once.Do(func() {
@@ -96,10 +107,10 @@ func (ns *NodeShutdown) ReconcileShutdowns(ctx context.Context, leavingNodes []s
 		if shutdown, exists := ns.shutdowns[nodeID]; exists && shutdown.Is(ns.typ) {
 			continue
 		}
-		ns.log.V(1).Info("Requesting shutdown", "type", ns.typ, "node", node, "node_id", nodeID)
-		// in case of type=restart we are relying on the default allocation_delay of 5 min see
+		ns.log.V(1).Info("Requesting shutdown", "type", ns.typ, "node", node, "node_id", nodeID, "allocation_delay", ns.allocationDelay)
+		// in case of type=restart and no explicit allocation delay we are relying on the default allocation_delay of 5 min see
 		// https://www.elastic.co/guide/en/elasticsearch/reference/7.15/put-shutdown.html
-		if err := ns.c.PutShutdown(ctx, nodeID, ns.typ, ns.reason); err != nil {
+		if err := ns.c.PutShutdown(ctx, nodeID, ns.typ, ns.reason, ns.allocationDelay); err != nil {
 			return fmt.Errorf("on put shutdown (type: %s) for node %s: %w", ns.typ, node, err)
 		}
 		// update the internal cache with the information about the new shutdown
@@ -11,6 +11,9 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
@@ -495,3 +498,59 @@ func TestNodeShutdown_ReconcileShutdowns(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeShutdown_ReconcileShutdowns_AllocationDelay(t *testing.T) {
+	tests := []struct {
+		name            string
+		allocationDelay time.Duration
+		wantBody        string
+	}{
+		{
+			name:            "default allocation delay",
+			allocationDelay: 0,
+			wantBody:        `{"type":"restart","reason":""}`,
+		},
+		{
+			name:            "explicit allocation delay",
+			allocationDelay: 10 * time.Minute,
+			wantBody:        `{"type":"restart","reason":"","allocation_delay":"600s"}`,
+		},
+		{
+			name:            "sub-second allocation delay is rounded up",
+			allocationDelay: 500 * time.Millisecond,
+			wantBody:        `{"type":"restart","reason":"","allocation_delay":"1s"}`,
+		},
+		{
+			name:            "fractional allocation delay is rounded up",
+			allocationDelay: 90*time.Second + time.Millisecond,
+			wantBody:        `{"type":"restart","reason":"","allocation_delay":"91s"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixtures := []string{noShutdownFixture, ackFixture, singleRestartShutdownFixture}
+			i := 0
+			var putBody string
+			client := esclient.NewMockClient(version.MustParse("7.15.2"), func(req *http.Request) *http.Response {
+				defer func() {
+					i++
+				}()
+				if req.Method == http.MethodPut {
+					body, err := io.ReadAll(req.Body)
+					require.NoError(t, err)
+					putBody = string(body)
+				}
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewBuffer([]byte(fixtures[i]))),
+					Header:     make(http.Header),
+					Request:    req,
+				}
+			})
+			ns := NewNodeShutdown(client, map[string]string{"pod-1": "txXw-Kd2Q6K0PbYMAPzH-Q"}, esclient.Restart, "", log.Log.WithName("test")).
+				WithAllocationDelay(tt.allocationDelay)
+			require.NoError(t, ns.ReconcileShutdowns(context.Background(), []string{"pod-1"}, nil))
+			require.JSONEq(t, tt.wantBody, putBody)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.elastic.co/apm/v2"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// Reconcile registers the snapshot repositories declared in the Elasticsearch specification through the Elasticsearch
+// API, and updates the ones whose type or settings drifted from the specification. Snapshot repositories which are not
+// declared in the specification are left untouched.
+func Reconcile(ctx context.Context, esClient esclient.SnapshotRepositoryClient, es esv1.Elasticsearch) error {
+	if len(es.Spec.SnapshotRepositories) == 0 {
+		// nothing to do, skip
+		return nil
+	}
+
+	span, ctx := apm.StartSpan(ctx, "reconcile_snapshot_repositories", tracing.SpanTypeApp)
+	defer span.End()
+
+	current, err := esClient.GetSnapshotRepositories(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, repository := range es.Spec.SnapshotRepositories {
+		expected := expectedRepository(repository)
+		if existing, exists := current[repository.Name]; exists && equal(existing, expected) {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Updating snapshot repository",
+			"namespace", es.Namespace, "es_name", es.Name, "repository", repository.Name, "type", repository.Type)
+		if err := esClient.UpdateSnapshotRepository(ctx, repository.Name, expected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expectedRepository returns the body of the request to register the given snapshot repository.
+func expectedRepository(repository esv1.SnapshotRepository) esclient.SnapshotRepository {
+	expected := esclient.SnapshotRepository{Type: repository.Type}
+	if repository.Settings != nil {
+		expected.Settings = repository.Settings.Data
+	}
+	return expected
+}
+
+// equal returns true if both snapshot repositories have the same type and settings. Settings are compared after being
+// flattened and converted to strings, since Elasticsearch returns all the setting values as strings.
+func equal(existing, expected esclient.SnapshotRepository) bool {
+	return existing.Type == expected.Type &&
+		reflect.DeepEqual(flatten("", existing.Settings), flatten("", expected.Settings))
+}
+
+func flatten(prefix string, settings map[string]interface{}) map[string]string {
+	flattened := make(map[string]string, len(settings))
+	for k, v := range settings {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, isMap := v.(map[string]interface{}); isMap {
+			for nk, nv := range flatten(key, nested) {
+				flattened[nk] = nv
+			}
+			continue
+		}
+		flattened[key] = fmt.Sprint(v)
+	}
+	return flattened
+}
@@ -0,0 +1,161 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+)
+
+type fakeESClient struct {
+	esclient.Client
+	existing  esclient.SnapshotRepositories
+	getErr    error
+	getCalled bool
+	updated   map[string]esclient.SnapshotRepository
+	updateErr error
+}
+
+func (f *fakeESClient) GetSnapshotRepositories(_ context.Context) (esclient.SnapshotRepositories, error) {
+	f.getCalled = true
+	return f.existing, f.getErr
+}
+
+func (f *fakeESClient) UpdateSnapshotRepository(_ context.Context, name string, repository esclient.SnapshotRepository) error {
+	if f.updated == nil {
+		f.updated = map[string]esclient.SnapshotRepository{}
+	}
+	f.updated[name] = repository
+	return f.updateErr
+}
+
+func esWithRepositories(repositories ...esv1.SnapshotRepository) esv1.Elasticsearch {
+	es := esv1.Elasticsearch{}
+	es.Spec.SnapshotRepositories = repositories
+	return es
+}
+
+var s3Repository = esv1.SnapshotRepository{
+	Name: "s3-backups",
+	Type: "s3",
+	Settings: &commonv1.Config{Data: map[string]interface{}{
+		"bucket":   "my-bucket",
+		"compress": true,
+		"client":   "default",
+	}},
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name          string
+		es            esv1.Elasticsearch
+		esClient      *fakeESClient
+		wantGetCalled bool
+		wantUpdated   map[string]esclient.SnapshotRepository
+		wantErr       bool
+	}{
+		{
+			name:          "no repository in the spec: nothing to do",
+			es:            esWithRepositories(),
+			esClient:      &fakeESClient{},
+			wantGetCalled: false,
+		},
+		{
+			name:          "register a new repository",
+			es:            esWithRepositories(s3Repository),
+			esClient:      &fakeESClient{existing: esclient.SnapshotRepositories{}},
+			wantGetCalled: true,
+			wantUpdated: map[string]esclient.SnapshotRepository{
+				"s3-backups": {Type: "s3", Settings: s3Repository.Settings.Data},
+			},
+		},
+		{
+			name: "repository already registered with the same settings: nothing to update",
+			es:   esWithRepositories(s3Repository),
+			esClient: &fakeESClient{existing: esclient.SnapshotRepositories{
+				"s3-backups": {Type: "s3", Settings: map[string]interface{}{"bucket": "my-bucket", "compress": "true", "client": "default"}},
+			}},
+			wantGetCalled: true,
+		},
+		{
+			name: "repository settings drifted: update it",
+			es:   esWithRepositories(s3Repository),
+			esClient: &fakeESClient{existing: esclient.SnapshotRepositories{
+				"s3-backups": {Type: "s3", Settings: map[string]interface{}{"bucket": "another-bucket", "compress": "true", "client": "default"}},
+			}},
+			wantGetCalled: true,
+			wantUpdated: map[string]esclient.SnapshotRepository{
+				"s3-backups": {Type: "s3", Settings: s3Repository.Settings.Data},
+			},
+		},
+		{
+			name: "repository type drifted: update it",
+			es:   esWithRepositories(s3Repository),
+			esClient: &fakeESClient{existing: esclient.SnapshotRepositories{
+				"s3-backups": {Type: "fs", Settings: map[string]interface{}{"bucket": "my-bucket", "compress": "true", "client": "default"}},
+			}},
+			wantGetCalled: true,
+			wantUpdated: map[string]esclient.SnapshotRepository{
+				"s3-backups": {Type: "s3", Settings: s3Repository.Settings.Data},
+			},
+		},
+		{
+			name: "repositories not in the spec are left untouched",
+			es:   esWithRepositories(esv1.SnapshotRepository{Name: "fs-backups", Type: "fs", Settings: &commonv1.Config{Data: map[string]interface{}{"location": "/mnt/backups"}}}),
+			esClient: &fakeESClient{existing: esclient.SnapshotRepositories{
+				"fs-backups":      {Type: "fs", Settings: map[string]interface{}{"location": "/mnt/backups"}},
+				"user-repository": {Type: "s3", Settings: map[string]interface{}{"bucket": "user-bucket"}},
+			}},
+			wantGetCalled: true,
+		},
+		{
+			name:          "error while retrieving the repositories",
+			es:            esWithRepositories(s3Repository),
+			esClient:      &fakeESClient{getErr: errors.New("boom")},
+			wantGetCalled: true,
+			wantErr:       true,
+		},
+		{
+			name:          "error while registering a repository",
+			es:            esWithRepositories(s3Repository),
+			esClient:      &fakeESClient{existing: esclient.SnapshotRepositories{}, updateErr: errors.New("boom")},
+			wantGetCalled: true,
+			wantUpdated: map[string]esclient.SnapshotRepository{
+				"s3-backups": {Type: "s3", Settings: s3Repository.Settings.Data},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Reconcile(context.Background(), tt.esClient, tt.es)
+			require.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+			require.Equal(t, tt.wantGetCalled, tt.esClient.getCalled)
+			require.Equal(t, tt.wantUpdated, tt.esClient.updated)
+		})
+	}
+}
+
+func Test_equal(t *testing.T) {
+	require.True(t, equal(
+		esclient.SnapshotRepository{Type: "s3", Settings: map[string]interface{}{"a": map[string]interface{}{"b": "1"}}},
+		esclient.SnapshotRepository{Type: "s3", Settings: map[string]interface{}{"a": map[string]interface{}{"b": float64(1)}}},
+	))
+	require.True(t, equal(
+		esclient.SnapshotRepository{Type: "fs"},
+		esclient.SnapshotRepository{Type: "fs", Settings: map[string]interface{}{}},
+	))
+	require.False(t, equal(
+		esclient.SnapshotRepository{Type: "s3", Settings: map[string]interface{}{"a": "1"}},
+		esclient.SnapshotRepository{Type: "s3", Settings: map[string]interface{}{"a": "1", "b": "2"}},
+	))
+}
@@ -0,0 +1,67 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package sset
+
+import (
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+)
+
+// SetTemplateHashLabel stores the hash of the canonical form of the given StatefulSet spec in the given labels, so that
+// semantically equal specs produce the same hash and do not trigger an update of the StatefulSet.
+func SetTemplateHashLabel(labels map[string]string, spec appsv1.StatefulSetSpec) map[string]string {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[hash.TemplateHashLabelName] = hash.HashObject(canonicalSpec(spec))
+	return labels
+}
+
+// canonicalSpec returns a copy of the given StatefulSet spec where the environment variables of the containers are
+// sorted by name and the resource quantities use their canonical representation.
+func canonicalSpec(spec appsv1.StatefulSetSpec) appsv1.StatefulSetSpec {
+	canonical := spec.DeepCopy()
+	podSpec := &canonical.Template.Spec
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			sortEnv(containers[i].Env)
+			canonicalizeResourceList(containers[i].Resources.Limits)
+			canonicalizeResourceList(containers[i].Resources.Requests)
+		}
+	}
+	for i := range canonical.VolumeClaimTemplates {
+		canonicalizeResourceList(canonical.VolumeClaimTemplates[i].Spec.Resources.Limits)
+		canonicalizeResourceList(canonical.VolumeClaimTemplates[i].Spec.Resources.Requests)
+	}
+	return *canonical
+}
+
+// sortEnv sorts the given environment variables by name, unless one of them references another variable: the
+// expansion of $(VAR_NAME) depends on the order of the variables.
+func sortEnv(env []corev1.EnvVar) {
+	for _, v := range env {
+		if strings.Contains(v.Value, "$(") {
+			return
+		}
+	}
+	// stable sort to preserve the order of duplicated variables, the last one taking precedence
+	sort.SliceStable(env, func(i, j int) bool {
+		return env[i].Name < env[j].Name
+	})
+}
+
+// canonicalizeResourceList replaces the quantities of the given resource list by their canonical representation, for
+// example 1024Mi by 1Gi or 1000m by 1.
+func canonicalizeResourceList(resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		resources[name] = resource.MustParse(quantity.String())
+	}
+}
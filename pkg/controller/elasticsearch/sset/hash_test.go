@@ -0,0 +1,116 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package sset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+)
+
+func specWith(env []corev1.EnvVar, memory string, cpu string, storage string) appsv1.StatefulSetSpec {
+	return appsv1.StatefulSetSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init", Env: env}},
+				Containers: []corev1.Container{{
+					Name: "elasticsearch",
+					Env:  env,
+					Resources: corev1.ResourceRequirements{
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+					},
+				}},
+			},
+		},
+		VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+				},
+			},
+		}},
+	}
+}
+
+func templateHash(spec appsv1.StatefulSetSpec) string {
+	return SetTemplateHashLabel(nil, spec)[hash.TemplateHashLabelName]
+}
+
+func TestSetTemplateHashLabel(t *testing.T) {
+	env := []corev1.EnvVar{{Name: "A", Value: "a"}, {Name: "B", Value: "b"}, {Name: "C", Value: "c"}}
+	reversedEnv := []corev1.EnvVar{{Name: "C", Value: "c"}, {Name: "B", Value: "b"}, {Name: "A", Value: "a"}}
+	referencingEnv := []corev1.EnvVar{{Name: "A", Value: "a"}, {Name: "B", Value: "$(A)"}}
+	reversedReferencingEnv := []corev1.EnvVar{{Name: "B", Value: "$(A)"}, {Name: "A", Value: "a"}}
+
+	reference := specWith(env, "2Gi", "1", "1Gi")
+
+	tests := []struct {
+		name     string
+		a, b     appsv1.StatefulSetSpec
+		wantSame bool
+	}{
+		{
+			name:     "same spec",
+			a:        reference,
+			b:        specWith(env, "2Gi", "1", "1Gi"),
+			wantSame: true,
+		},
+		{
+			name:     "reordered env vars",
+			a:        reference,
+			b:        specWith(reversedEnv, "2Gi", "1", "1Gi"),
+			wantSame: true,
+		},
+		{
+			name:     "equivalent quantities",
+			a:        reference,
+			b:        specWith(env, "2048Mi", "1000m", "1024Mi"),
+			wantSame: true,
+		},
+		{
+			name:     "different env var value",
+			a:        reference,
+			b:        specWith([]corev1.EnvVar{{Name: "A", Value: "a"}, {Name: "B", Value: "b"}, {Name: "C", Value: "d"}}, "2Gi", "1", "1Gi"),
+			wantSame: false,
+		},
+		{
+			name:     "different quantities",
+			a:        reference,
+			b:        specWith(env, "2G", "1", "1Gi"),
+			wantSame: false,
+		},
+		{
+			name:     "reordered env vars referencing each other",
+			a:        specWith(referencingEnv, "2Gi", "1", "1Gi"),
+			b:        specWith(reversedReferencingEnv, "2Gi", "1", "1Gi"),
+			wantSame: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aHash, bHash := templateHash(tt.a), templateHash(tt.b)
+			require.NotEmpty(t, aHash)
+			if tt.wantSame {
+				require.Equal(t, aHash, bHash)
+			} else {
+				require.NotEqual(t, aHash, bHash)
+			}
+		})
+	}
+}
+
+func TestSetTemplateHashLabel_DoesNotMutateSpec(t *testing.T) {
+	spec := specWith([]corev1.EnvVar{{Name: "B", Value: "b"}, {Name: "A", Value: "a"}}, "2048Mi", "1", "1Gi")
+	expected := spec.DeepCopy()
+	labels := SetTemplateHashLabel(map[string]string{"a": "b"}, spec)
+	require.Equal(t, "b", labels["a"])
+	require.Equal(t, *expected, spec)
+}
@@ -8,7 +8,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-// fileLogStyleEnvVar returns the environment variable to configure the Elasticsearch container to write logs to disk
-func fileLogStyleEnvVar() corev1.EnvVar {
+// FileLogStyleEnvVar returns the environment variable to configure the Elasticsearch container to write logs to disk
+func FileLogStyleEnvVar() corev1.EnvVar {
 	return corev1.EnvVar{Name: "ES_LOG_STYLE", Value: "file"}
 }
@@ -17,7 +17,6 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/monitoring"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/securitycontext"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
 	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
@@ -43,7 +42,7 @@ func Metricbeat(ctx context.Context, client k8s.Client, es esv1.Elasticsearch) (
 		es.Spec.Version,
 		metricbeatConfigTemplate,
 		esv1.ESNamer,
-		fmt.Sprintf("%s://localhost:%d", es.Spec.HTTP.Protocol(), network.HTTPPort),
+		fmt.Sprintf("%s://localhost:%d", es.Spec.HTTP.Protocol(), es.HTTPPort()),
 		username,
 		password,
 		es.Spec.HTTP.TLS.Enabled(),
@@ -99,7 +98,7 @@ func WithMonitoring(ctx context.Context, client k8s.Client, builder *defaults.Po
 
 	if monitoring.IsLogsDefined(&es) {
 		// enable Stack logging to write Elasticsearch logs to disk
-		builder.WithEnv(fileLogStyleEnvVar())
+		builder.WithEnv(FileLogStyleEnvVar())
 
 		b, err := Filebeat(ctx, client, es)
 		if err != nil {
@@ -0,0 +1,149 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// BuiltinUserPasswordSecretKey is the key of the user-provided secrets holding the password of a built-in user.
+	BuiltinUserPasswordSecretKey = "password"
+	// AppliedBuiltinUserPasswordsAnnotationName holds the resource versions of the user-provided secrets whose password
+	// has been applied to the built-in users through the change password API, indexed by user name.
+	AppliedBuiltinUserPasswordsAnnotationName = "elasticsearch.k8s.elastic.co/applied-builtin-user-passwords"
+)
+
+// BuiltinUserPasswordsWatchName returns the watch registered for the user-provided built-in user password secrets.
+func BuiltinUserPasswordsWatchName(es types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-builtin-user-passwords", es.Namespace, es.Name)
+}
+
+// watchBuiltinUserPasswords ensures the secrets holding the passwords of the built-in users are watched for future
+// reconciliations to be triggered on any change.
+func watchBuiltinUserPasswords(es esv1.Elasticsearch, watched watches.DynamicWatches) error {
+	esKey := k8s.ExtractNamespacedName(&es)
+	secretNames := make([]string, 0, len(es.Spec.Auth.BuiltinUserPasswords))
+	for _, source := range es.Spec.Auth.BuiltinUserPasswords {
+		if source.SecretName == "" {
+			continue
+		}
+		secretNames = append(secretNames, source.SecretName)
+	}
+	return watches.WatchUserProvidedSecrets(esKey, watched, BuiltinUserPasswordsWatchName(esKey), secretNames)
+}
+
+// getBuiltinUserPassword returns the password held by the given user-provided secret, along with the resource version
+// of the secret.
+func getBuiltinUserPassword(ctx context.Context, c k8s.Client, namespace string, source esv1.BuiltinUserPasswordSource) ([]byte, string, error) {
+	var secret corev1.Secret
+	secretKey := types.NamespacedName{Namespace: namespace, Name: source.SecretName}
+	if err := c.Get(ctx, secretKey, &secret); err != nil {
+		return nil, "", err
+	}
+	password := secret.Data[BuiltinUserPasswordSecretKey]
+	if len(password) == 0 {
+		return nil, "", errors.Errorf("no %s entry found in the password secret %s of user %s", BuiltinUserPasswordSecretKey, secretKey, source.Username)
+	}
+	return password, secret.ResourceVersion, nil
+}
+
+// ReconcileBuiltinUserPasswords applies the user-provided passwords of the built-in users other than elastic through
+// the change password API. The elastic user is defined in the file realm, its password is handled with the other
+// predefined users.
+// The resource versions of the applied secrets are tracked in an annotation of the Elasticsearch resource, so that
+// a password is only applied again when its secret changes. The passwords of the users removed from the specification
+// are left untouched. The given Elasticsearch resource is updated in place with the patched annotation.
+func ReconcileBuiltinUserPasswords(ctx context.Context, c k8s.Client, esClient esclient.SecurityClient, es *esv1.Elasticsearch) error {
+	applied, err := getAppliedBuiltinUserPasswords(*es)
+	if err != nil {
+		return err
+	}
+	if len(es.Spec.Auth.BuiltinUserPasswords) == 0 && len(applied) == 0 {
+		// nothing to do, skip
+		return nil
+	}
+
+	span, ctx := apm.StartSpan(ctx, "reconcile_builtin_user_passwords", tracing.SpanTypeApp)
+	defer span.End()
+	log := ulog.FromContext(ctx)
+
+	expected := make(map[string]string, len(es.Spec.Auth.BuiltinUserPasswords))
+	for _, source := range es.Spec.Auth.BuiltinUserPasswords {
+		if source.Username == ElasticUserName || source.SecretName == "" {
+			continue
+		}
+		password, version, err := getBuiltinUserPassword(ctx, c, es.Namespace, source)
+		if err != nil {
+			return err
+		}
+		expected[source.Username] = version
+		if applied[source.Username] == version {
+			continue
+		}
+		log.Info("Changing built-in user password", "namespace", es.Namespace, "es_name", es.Name, "user_name", source.Username)
+		if err := esClient.ChangePassword(ctx, source.Username, password); err != nil {
+			return err
+		}
+		// record the change right away, not to apply the same password again if a later one fails
+		applied[source.Username] = version
+		if err := annotateWithAppliedBuiltinUserPasswords(ctx, c, es, applied); err != nil {
+			return err
+		}
+	}
+	return annotateWithAppliedBuiltinUserPasswords(ctx, c, es, expected)
+}
+
+// getAppliedBuiltinUserPasswords returns the resource versions of the secrets whose password has been applied,
+// indexed by user name. If there's no applied password the map is empty but not nil.
+func getAppliedBuiltinUserPasswords(es esv1.Elasticsearch) (map[string]string, error) {
+	applied := make(map[string]string)
+	serialized, ok := es.Annotations[AppliedBuiltinUserPasswordsAnnotationName]
+	if !ok || serialized == "" {
+		return applied, nil
+	}
+	if err := json.Unmarshal([]byte(serialized), &applied); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s annotation", AppliedBuiltinUserPasswordsAnnotationName)
+	}
+	return applied, nil
+}
+
+// annotateWithAppliedBuiltinUserPasswords stores the given resource versions in the annotation of the Elasticsearch
+// resource, or removes the annotation if there is none. The annotation is patched, not to conflict with other updates of
+// the Elasticsearch resource during the reconciliation, and is not patched if already up to date.
+func annotateWithAppliedBuiltinUserPasswords(ctx context.Context, c k8s.Client, es *esv1.Elasticsearch, applied map[string]string) error {
+	current, exists := es.Annotations[AppliedBuiltinUserPasswordsAnnotationName]
+	if len(applied) == 0 {
+		if !exists {
+			return nil
+		}
+		return k8s.PatchAnnotation(ctx, c, es, AppliedBuiltinUserPasswordsAnnotationName, nil)
+	}
+
+	// json.Marshal sorts the keys of the map, the serialized value is stable
+	serialized, err := json.Marshal(applied)
+	if err != nil {
+		return err
+	}
+	if exists && current == string(serialized) {
+		return nil
+	}
+	value := string(serialized)
+	return k8s.PatchAnnotation(ctx, c, es, AppliedBuiltinUserPasswordsAnnotationName, &value)
+}
@@ -0,0 +1,209 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user/filerealm"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// fakeSecurityClient records the password changes.
+type fakeSecurityClient struct {
+	esclient.SecurityClient
+	changes map[string]string
+	err     error
+}
+
+func (f *fakeSecurityClient) ChangePassword(_ context.Context, username string, password []byte) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.changes[username] = string(password)
+	return nil
+}
+
+func builtinUserPasswordSecret(name, password string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Data:       map[string][]byte{BuiltinUserPasswordSecretKey: []byte(password)},
+	}
+}
+
+func esWithBuiltinUserPasswords(sources ...esv1.BuiltinUserPasswordSource) esv1.Elasticsearch {
+	return esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{Auth: esv1.Auth{BuiltinUserPasswords: sources}},
+	}
+}
+
+func Test_reconcileElasticUser_builtinUserPassword(t *testing.T) {
+	es := esWithBuiltinUserPasswords(esv1.BuiltinUserPasswordSource{
+		Username:  ElasticUserName,
+		SecretRef: commonv1.SecretRef{SecretName: "elastic-password"},
+	})
+	operatorSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: esv1.ElasticUserSecret(es.Name)}}
+	c := k8s.NewFakeClient(builtinUserPasswordSecret("elastic-password", "s3cr3t"), operatorSecret)
+
+	got, err := reconcileElasticUser(context.Background(), c, es, filerealm.New(), filerealm.New(), testPasswordHasher)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, ElasticUserName, got[0].Name)
+	require.Equal(t, []byte("s3cr3t"), got[0].Password)
+	require.NoError(t, bcrypt.CompareHashAndPassword(got[0].PasswordHash, []byte("s3cr3t")))
+	// the operator managed secret is removed not to expose a stale password
+	err = c.Get(context.Background(), k8s.ExtractNamespacedName(operatorSecret), &corev1.Secret{})
+	require.True(t, apierrors.IsNotFound(err))
+
+	// the password is not regenerated and its hash is reused on subsequent reconciliations
+	existingFileRealm := got.fileRealm()
+	again, err := reconcileElasticUser(context.Background(), c, es, existingFileRealm, filerealm.New(), testPasswordHasher)
+	require.NoError(t, err)
+	require.Equal(t, got, again)
+	err = c.Get(context.Background(), k8s.ExtractNamespacedName(operatorSecret), &corev1.Secret{})
+	require.True(t, apierrors.IsNotFound(err))
+
+	// a rotated password is picked up
+	require.NoError(t, c.Update(context.Background(), builtinUserPasswordSecret("elastic-password", "r0t4t3d")))
+	rotated, err := reconcileElasticUser(context.Background(), c, es, existingFileRealm, filerealm.New(), testPasswordHasher)
+	require.NoError(t, err)
+	require.Equal(t, []byte("r0t4t3d"), rotated[0].Password)
+	require.NoError(t, bcrypt.CompareHashAndPassword(rotated[0].PasswordHash, []byte("r0t4t3d")))
+
+	// the user-provided file realm still takes precedence
+	userFileRealm := filerealm.New().WithUser(ElasticUserName, []byte("$2a$10$hash"))
+	overridden, err := reconcileElasticUser(context.Background(), c, es, existingFileRealm, userFileRealm, testPasswordHasher)
+	require.NoError(t, err)
+	require.Empty(t, overridden)
+}
+
+func Test_reconcileElasticUser_builtinUserPasswordErrors(t *testing.T) {
+	es := esWithBuiltinUserPasswords(esv1.BuiltinUserPasswordSource{
+		Username:  ElasticUserName,
+		SecretRef: commonv1.SecretRef{SecretName: "elastic-password"},
+	})
+	// the secret does not exist yet: do not generate a password to be overwritten later
+	_, err := reconcileElasticUser(context.Background(), k8s.NewFakeClient(), es, filerealm.New(), filerealm.New(), testPasswordHasher)
+	require.Error(t, err)
+
+	// the secret does not hold any password
+	secret := builtinUserPasswordSecret("elastic-password", "")
+	_, err = reconcileElasticUser(context.Background(), k8s.NewFakeClient(secret), es, filerealm.New(), filerealm.New(), testPasswordHasher)
+	require.Error(t, err)
+}
+
+func TestReconcileBuiltinUserPasswords(t *testing.T) {
+	es := esWithBuiltinUserPasswords(
+		esv1.BuiltinUserPasswordSource{Username: ElasticUserName, SecretRef: commonv1.SecretRef{SecretName: "elastic-password"}},
+		esv1.BuiltinUserPasswordSource{Username: "kibana_system", SecretRef: commonv1.SecretRef{SecretName: "kibana-password"}},
+		esv1.BuiltinUserPasswordSource{Username: "beats_system", SecretRef: commonv1.SecretRef{SecretName: "beats-password"}},
+	)
+	c := k8s.NewFakeClient(
+		&es,
+		builtinUserPasswordSecret("elastic-password", "elastic-s3cr3t"),
+		builtinUserPasswordSecret("kibana-password", "kibana-s3cr3t"),
+		builtinUserPasswordSecret("beats-password", "beats-s3cr3t"),
+	)
+	reconcile := func(esClient *fakeSecurityClient) (esv1.Elasticsearch, error) {
+		t.Helper()
+		var current esv1.Elasticsearch
+		require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(&es), &current))
+		err := ReconcileBuiltinUserPasswords(context.Background(), c, esClient, &current)
+		require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(&es), &current))
+		return current, err
+	}
+
+	// the passwords are applied, except the elastic one which is part of the file realm
+	esClient := &fakeSecurityClient{changes: map[string]string{}}
+	_, err := reconcile(esClient)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"kibana_system": "kibana-s3cr3t", "beats_system": "beats-s3cr3t"}, esClient.changes)
+
+	// the passwords are not applied again on subsequent reconciliations
+	esClient = &fakeSecurityClient{changes: map[string]string{}}
+	_, err = reconcile(esClient)
+	require.NoError(t, err)
+	require.Empty(t, esClient.changes)
+
+	// a rotated password is applied
+	require.NoError(t, c.Update(context.Background(), builtinUserPasswordSecret("kibana-password", "kibana-r0t4t3d")))
+	esClient = &fakeSecurityClient{changes: map[string]string{}}
+	_, err = reconcile(esClient)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"kibana_system": "kibana-r0t4t3d"}, esClient.changes)
+
+	// a failure to apply a password is retried
+	require.NoError(t, c.Update(context.Background(), builtinUserPasswordSecret("beats-password", "beats-r0t4t3d")))
+	_, err = reconcile(&fakeSecurityClient{err: errors.New("boom")})
+	require.Error(t, err)
+	esClient = &fakeSecurityClient{changes: map[string]string{}}
+	_, err = reconcile(esClient)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"beats_system": "beats-r0t4t3d"}, esClient.changes)
+
+	// users removed from the specification are not tracked anymore, and their password is left untouched
+	var current esv1.Elasticsearch
+	require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(&es), &current))
+	current.Spec.Auth.BuiltinUserPasswords = nil
+	require.NoError(t, c.Update(context.Background(), &current))
+	esClient = &fakeSecurityClient{changes: map[string]string{}}
+	current, err = reconcile(esClient)
+	require.NoError(t, err)
+	require.Empty(t, esClient.changes)
+	require.NotContains(t, current.Annotations, AppliedBuiltinUserPasswordsAnnotationName)
+}
+
+func TestReconcileBuiltinUserPasswords_missingSecret(t *testing.T) {
+	es := esWithBuiltinUserPasswords(
+		esv1.BuiltinUserPasswordSource{Username: "kibana_system", SecretRef: commonv1.SecretRef{SecretName: "kibana-password"}},
+	)
+	c := k8s.NewFakeClient(&es)
+	esClient := &fakeSecurityClient{changes: map[string]string{}}
+	require.Error(t, ReconcileBuiltinUserPasswords(context.Background(), c, esClient, &es))
+	require.Empty(t, esClient.changes)
+
+	var current esv1.Elasticsearch
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "es"}, &current))
+	require.NotContains(t, current.Annotations, AppliedBuiltinUserPasswordsAnnotationName)
+}
+
+func TestReconcileBuiltinUserPasswords_concurrentUpdate(t *testing.T) {
+	es := esWithBuiltinUserPasswords(
+		esv1.BuiltinUserPasswordSource{Username: "kibana_system", SecretRef: commonv1.SecretRef{SecretName: "kibana-password"}},
+	)
+	c := k8s.NewFakeClient(&es, builtinUserPasswordSecret("kibana-password", "kibana-s3cr3t"))
+	var current esv1.Elasticsearch
+	require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(&es), &current))
+
+	// the Elasticsearch resource is updated after being retrieved for the reconciliation
+	var updated esv1.Elasticsearch
+	require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(&es), &updated))
+	updated.Labels = map[string]string{"updated": "true"}
+	require.NoError(t, c.Update(context.Background(), &updated))
+
+	// the annotation does not conflict with the update
+	esClient := &fakeSecurityClient{changes: map[string]string{}}
+	require.NoError(t, ReconcileBuiltinUserPasswords(context.Background(), c, esClient, &current))
+	require.Equal(t, map[string]string{"kibana_system": "kibana-s3cr3t"}, esClient.changes)
+	require.Contains(t, current.Annotations, AppliedBuiltinUserPasswordsAnnotationName)
+	require.Equal(t, map[string]string{"updated": "true"}, current.Labels)
+
+	// the reconciled resource is up to date and can be updated later in the reconciliation
+	current.Annotations["other"] = "value"
+	require.NoError(t, c.Update(context.Background(), &current))
+}
@@ -54,6 +54,25 @@ func reconcileElasticUser(
 			Name:      secretName,
 		})
 	}
+	// if user has provided the password of the elastic user use it instead of the operator managed secret
+	if source := es.Spec.Auth.BuiltinUserPassword(ElasticUserName); source != nil {
+		password, _, err := getBuiltinUserPassword(ctx, c, es.Namespace, *source)
+		if err != nil {
+			return nil, err
+		}
+		elasticUser, err := reuseOrGenerateHashes(
+			users{{Name: ElasticUserName, Password: password, Roles: []string{SuperUserBuiltinRole}}},
+			existingFileRealm,
+			passwordHasher,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return elasticUser, k8s.DeleteSecretIfExists(ctx, c, types.NamespacedName{
+			Namespace: es.Namespace,
+			Name:      secretName,
+		})
+	}
 	// regular reconciliation if user did not choose to set a password for the elastic user
 	return reconcilePredefinedUsers(
 		ctx,
@@ -86,7 +105,7 @@ func reconcileInternalUsers(
 		existingFileRealm,
 		users{
 			{Name: ControllerUserName, Roles: []string{SuperUserBuiltinRole}},
-			{Name: PreStopUserName, Roles: []string{ClusterManageRole}},
+			{Name: PreStopUserName, Roles: []string{ClusterManageRole, PreStopFlushRole}},
 			{Name: ProbeUserName, Roles: []string{ProbeUserRole}},
 			{Name: MonitoringUserName, Roles: []string{RemoteMonitoringCollectorBuiltinRole}},
 		},
@@ -6,6 +6,7 @@ package user
 
 import (
 	"context"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -16,6 +17,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user/filerealm"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
@@ -286,3 +288,34 @@ func Test_reconcileInternalUsers(t *testing.T) {
 		})
 	}
 }
+
+func Test_reconcileInternalUsers_PreStopUserCanFlush(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	got, err := reconcileInternalUsers(context.Background(), k8s.NewFakeClient(), es, filerealm.New(), testPasswordHasher)
+	require.NoError(t, err)
+
+	// a synced flush of all the indices, called by the pre-stop hook of versions without node shutdown support,
+	// requires the maintenance index privilege (or a superset of it) on all the indices
+	canFlush := func(roleNames []string) bool {
+		for _, roleName := range roleNames {
+			role, ok := PredefinedRoles[roleName].(esclient.Role)
+			if !ok {
+				continue
+			}
+			for _, indices := range role.Indices {
+				if slices.Contains(indices.Names, "*") &&
+					slices.ContainsFunc(indices.Privileges, func(p string) bool { return p == "maintenance" || p == "manage" || p == "all" }) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for _, u := range got {
+		if u.Name == PreStopUserName {
+			require.True(t, canFlush(u.Roles), "the pre-stop user cannot flush the shards with roles %v", u.Roles)
+			return
+		}
+	}
+	t.Fatalf("pre-stop user %s not found", PreStopUserName)
+}
@@ -31,7 +31,8 @@ import (
 // Kubernetes secret mounted in the Elasticsearch Pods.
 // That secret contains the file realm files (`users` and `users_roles`) and the file roles (`roles.yml`).
 // Users are aggregated from various sources:
-// - predefined users include the controller user, the probe user, and the public-facing elastic user
+// - predefined users include the controller user, the probe user, and the public-facing elastic user whose password
+// may be provided by the user
 // - associated users come from resource associations (eg. Kibana or APMServer)
 // - user-provided users from file realms referenced in the Elasticsearch spec
 // Roles are aggregated from:
@@ -90,6 +91,11 @@ func aggregateFileRealm(
 	recorder record.EventRecorder,
 	passwordHasher cryptutil.PasswordHasher,
 ) (filerealm.Realm, esclient.BasicAuth, error) {
+	// watch user-provided built-in user passwords, the elastic one is part of the file realm
+	if err := watchBuiltinUserPasswords(es, watched); err != nil {
+		return filerealm.Realm{}, esclient.BasicAuth{}, err
+	}
+
 	// retrieve existing file realm to reuse predefined users password hashes if possible
 	existingFileRealm, err := getExistingFileRealm(c, es)
 	if err != nil && apierrors.IsNotFound(err) {
@@ -21,6 +21,9 @@ const (
 	SuperUserBuiltinRole = "superuser"
 	// ClusterManageRole is the name of a custom role to manage the cluster.
 	ClusterManageRole = "elastic-internal_cluster_manage"
+	// PreStopFlushRole is the name of the role allowing the pre-stop hook to flush the shards of Elasticsearch versions
+	// that do not support the node shutdown API, which requires index privileges.
+	PreStopFlushRole = "elastic-internal_pre_stop_flush"
 	// ProbeUserRole is the name of the role used by the internal probe user.
 	ProbeUserRole = "elastic_internal_probe_user"
 	// RemoteMonitoringCollectorBuiltinRole is the name of the built-in remote_monitoring_collector role.
@@ -66,6 +69,14 @@ var (
 	PredefinedRoles = RolesFileContent{
 		ProbeUserRole:     esclient.Role{Cluster: []string{"monitor"}},
 		ClusterManageRole: esclient.Role{Cluster: []string{"manage"}},
+		PreStopFlushRole: esclient.Role{
+			Indices: []esclient.IndexRole{
+				{
+					Names:      []string{"*"},
+					Privileges: []string{"maintenance"},
+				},
+			},
+		},
 		ApmUserRoleV6: esclient.Role{
 			Cluster: []string{"monitor", "manage_index_templates"},
 			Indices: []esclient.IndexRole{
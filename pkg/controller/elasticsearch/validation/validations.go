@@ -8,15 +8,23 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
 	stackmon "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	essettings "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
 	esversion "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
@@ -26,21 +34,71 @@ import (
 const (
 	cfgInvalidMsg                          = "Configuration invalid"
 	duplicateNodeSets                      = "NodeSet names must be unique"
+	duplicateSnapshotRepositories          = "Snapshot repository names must be unique"
+	duplicateIngestPipelines               = "Ingest pipeline names must be unique"
+	duplicateIndexLifecyclePolicies        = "Index lifecycle policy names must be unique"
+	duplicateSecurityRoles                 = "Security role names must be unique"
+	duplicateRoleMappings                  = "Role mapping names must be unique"
+	duplicateBuiltinUserPasswords          = "Only one password can be provided per built-in user"
+	duplicateRealmNames                    = "Realm names must be unique"
+	samlMetadataSourceMsg                  = "Exactly one of metadataURL or metadataSecretRef must be set"
+	realmsInOldVersionMsg                  = "SAML and OpenID Connect realms are only supported from Elasticsearch 7.0.0"
 	invalidNamesErrMsg                     = "Elasticsearch configuration would generate resources with invalid names"
 	invalidSanIPErrMsg                     = "Invalid SAN IP address. Must be a valid IPv4 address"
+	conflictingPortsErrMsg                 = "HTTP and transport ports must be different"
 	masterRequiredMsg                      = "Elasticsearch needs to have at least one master node"
 	mixedRoleConfigMsg                     = "Detected a combination of node.roles and %s. Use only node.roles"
+	mixedCoordinatingRoleMsg               = "The coordinating role cannot be combined with other roles in node.roles"
 	noDowngradesMsg                        = "Downgrades are not supported"
 	nodeRolesInOldVersionMsg               = "node.roles setting is not available in this version of Elasticsearch"
 	parseStoredVersionErrMsg               = "Cannot parse current Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
 	parseVersionErrMsg                     = "Cannot parse Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
 	pvcNotMountedErrMsg                    = "volume claim declared but volume not mounted in any container. Note that the Elasticsearch data volume should be named 'elasticsearch-data'"
+	storageDecreaseErrMsg                  = "Decreasing the storage request of claim %s in nodeSet %s below %s is not supported, create a new nodeSet with the expected storage instead"
 	unsupportedConfigErrMsg                = "Configuration setting is reserved for internal use. User-configured use is unsupported"
 	unsupportedUpgradeMsg                  = "Unsupported version upgrade path. Check the Elasticsearch documentation for supported upgrade paths."
 	unsupportedVersionMsg                  = "Unsupported version"
 	notAllowedNodesLabelMsg                = "Node label not in the exposed node labels list"
+	invalidNodeAttributeMsg                = "Node attributes must be a comma-separated list of <attribute>=<node label> pairs, attribute names can only contain alphanumeric characters, '_' and '-'"
+	duplicateNodeAttributeMsg              = "Node attribute names must be unique"
+	reservedNodeAttributeMsg               = "Node attribute is reserved for internal use"
 	unsupportedClientAuthenticationMsg     = "Mandatory client authentication is not supported"
+	unknownCipherSuiteMsg                  = "Unknown cipher suite. Use the IANA name of a cipher suite supported by Elasticsearch"
+	cipherSuiteBelowMinTLSVersionMsg       = "Cipher suite cannot be negotiated with the minimum TLS version %s"
+	tlsOptionsWithoutTLSMsg                = "TLS minimum version and cipher suites cannot be set when TLS is disabled"
+	tlsOptionsConflictMsg                  = "Setting is already configured through spec.http.tls.%s"
 	autoscalingAnnotationUnsupportedErrMsg = "autoscaling annotation is no longer supported"
+	invalidAllocationDelayMsg              = "Allocation delay must be greater than 0"
+	log4j2ConfigConflictMsg                = "A custom log4j2 configuration cannot be combined with a log format"
+	invalidLog4j2ConfigMsg                 = "Exactly one of config or secretName must be set"
+	logSidecarLogFormatConflictMsg         = "The log sidecar requires the Elasticsearch logs to be written to files, it cannot be combined with a log format"
+	missingLogSidecarImageMsg              = "The image of the log sidecar must be set"
+	logSidecarInOldVersionMsg              = "The log sidecar requires Elasticsearch 7.14.0 or later"
+	jvmOptionsInOldVersionMsg              = "JVM options require Elasticsearch 7.7.0 or later"
+	invalidJVMOptionMsg                    = "JVM options must be non-empty and fit on a single line"
+	frozenRoleInOldVersionMsg              = "The data_frozen role requires Elasticsearch 7.12.0 or later"
+	sharedCacheSizeWithoutFrozenRoleMsg    = "The shared cache size can only be set on nodes with the data_frozen role"
+	sharedCacheSizeConflictMsg             = "Setting is already configured through spec.nodeSets[%d].sharedCacheSize"
+	invalidSharedCacheSizeMsg              = "The shared cache size must be greater than 0"
+	overriddenJVMOptionMsg                 = "JVM option overridden by the ES_JAVA_OPTS environment variable of the Elasticsearch container"
+	unknownAnonymousRoleMsg                = "Role is neither a built-in role nor declared in spec.securityRoles, it must be defined in a roles file or through the Elasticsearch API"
+	remoteClusterAddressConflictMsg        = "An external address cannot be combined with elasticsearchRef"
+	invalidRemoteClusterAddressMsg         = "The external address must be a transport address in the host:port format"
+	remoteClusterAddressInOldVersionMsg    = "External addresses of remote clusters require Elasticsearch 7.7.0 or later"
+	trustedCAInOldVersionMsg               = "Trusted certificate authorities require Elasticsearch 7.0.0 or later"
+	keystorePasswordInOldVersionMsg        = "Keystore password protection requires Elasticsearch 7.9.0 or later"
+	missingSeedHostsMsg                    = "Seed hosts must be specified with the settings seed hosts provider"
+	missingSeedProvidersMsg                = "Seed providers must be specified with the custom seed hosts provider"
+	seedProvidersConflictMsg               = "Seed providers can only be specified with the custom seed hosts provider"
+	invalidSeedHostMsg                     = "Seed hosts must be non-empty transport addresses"
+	missingOfflinePluginsVolumeMsg         = "The volume holding the plugin archives must be declared in the PodTemplate"
+	invalidOfflinePluginPathMsg            = "Paths must be relative and cannot reference a parent directory"
+	clusterNameChangeMsg                   = "The cluster name cannot be changed after the creation of the cluster"
+	negativeVolumeClaimRetentionMsg        = "The volume claim scale down retention cannot be negative"
+	ephemeralDataLossNotAcknowledgedMsg    = "The loss of the data of the nodes on restart must be acknowledged by setting acknowledgeDataLoss to true"
+	ephemeralDataVolumeConflictMsg         = "An ephemeral data volume cannot be combined with an elasticsearch-data volume claim template or Pod template volume"
+	negativeEphemeralDataVolumeSizeMsg     = "The ephemeral data volume size limit cannot be negative"
+	ephemeralDataVolumeChangeMsg           = "The ephemeral data volume cannot be enabled or disabled on an existing NodeSet, use a new NodeSet instead"
 )
 
 type validation func(esv1.Elasticsearch) field.ErrorList
@@ -52,9 +110,11 @@ func updateValidations(ctx context.Context, k8sClient k8s.Client, validateStorag
 	return []updateValidation{
 		noDowngrades,
 		validUpgradePath,
+		noClusterNameChange,
 		func(current esv1.Elasticsearch, proposed esv1.Elasticsearch) field.ErrorList {
 			return validPVCModification(ctx, current, proposed, k8sClient, validateStorageClass)
 		},
+		noEphemeralDataVolumeChange,
 	}
 }
 
@@ -66,11 +126,34 @@ func validations(ctx context.Context, checker license.Checker, exposedNodeLabels
 		},
 		noUnknownFields,
 		validName,
+		validNodeAttributes,
 		hasCorrectNodeRoles,
 		supportedVersion,
 		validSanIP,
+		validHTTPTLSOptions,
+		validPorts,
+		validRestartInPlace,
+		validMaintenanceWindow,
+		validLog4j2Config,
+		validLogSidecar,
+		validJVMOptions,
+		validTrustedCertificateAuthorities,
+		validKeystorePassword,
+		validDiscovery,
+		validOfflinePlugins,
+		validFrozenTier,
+		validRemoteClusters,
+		checkSnapshotRepositoryNameUniqueness,
+		checkIngestPipelineNameUniqueness,
+		checkIndexLifecyclePolicyNameUniqueness,
+		checkSecurityRoleNameUniqueness,
+		checkRoleMappingNameUniqueness,
+		checkBuiltinUserPasswordUniqueness,
+		validRealms,
 		validAutoscalingConfiguration,
 		validPVCNaming,
+		validVolumeClaimScaleDownRetention,
+		validEphemeralDataVolumes,
 		validMonitoring,
 		validAssociations,
 		func(proposed esv1.Elasticsearch) field.ErrorList {
@@ -94,6 +177,57 @@ func validNodeLabels(proposed esv1.Elasticsearch, exposedNodeLabels NodeLabels)
 			),
 		)
 	}
+	nodeAttributes := proposed.NodeAttributes()
+	attributeNames := make([]string, 0, len(nodeAttributes))
+	for name := range nodeAttributes {
+		attributeNames = append(attributeNames, name)
+	}
+	sort.Strings(attributeNames)
+	for _, name := range attributeNames {
+		nodeLabel := nodeAttributes[name]
+		if exposedNodeLabels.IsAllowed(nodeLabel) {
+			continue
+		}
+		errs = append(
+			errs,
+			field.Invalid(
+				field.NewPath("metadata").Child("annotations", esv1.NodeAttributesAnnotation),
+				nodeLabel,
+				notAllowedNodesLabelMsg,
+			),
+		)
+	}
+	return errs
+}
+
+var nodeAttributeNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// validNodeAttributes checks the format of the node attributes annotation.
+func validNodeAttributes(proposed esv1.Elasticsearch) field.ErrorList {
+	value := strings.TrimSpace(proposed.Annotations[esv1.NodeAttributesAnnotation])
+	if value == "" {
+		return nil
+	}
+	var errs field.ErrorList
+	annotationPath := field.NewPath("metadata").Child("annotations", esv1.NodeAttributesAnnotation)
+	names := make(map[string]struct{})
+	for _, entry := range strings.Split(value, ",") {
+		name, nodeLabel, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || !nodeAttributeNameRegexp.MatchString(name) {
+			errs = append(errs, field.Invalid(annotationPath, entry, invalidNodeAttributeMsg))
+			continue
+		}
+		for _, msg := range utilvalidation.IsQualifiedName(nodeLabel) {
+			errs = append(errs, field.Invalid(annotationPath, nodeLabel, msg))
+		}
+		if name == essettings.NodeAttrK8sNodeName {
+			errs = append(errs, field.Forbidden(annotationPath, fmt.Sprintf("%s: %s", reservedNodeAttributeMsg, name)))
+		}
+		if _, exists := names[name]; exists {
+			errs = append(errs, field.Duplicate(annotationPath, name))
+		}
+		names[name] = struct{}{}
+	}
 	return errs
 }
 
@@ -160,15 +294,24 @@ func hasCorrectNodeRoles(es esv1.Elasticsearch) field.ErrorList {
 			continue
 		}
 
-		// check that node.roles is not used with an older Elasticsearch version
-		if cfg.Node != nil && cfg.Node.Roles != nil && !v.GTE(version.From(7, 9, 0)) {
+		// check that the coordinating only shortcut is not mixed with other roles
+		coordinatingOnly := esversion.HasCoordinatingOnlyRole(cfg.Node)
+		if coordinatingOnly && len(cfg.Node.Roles) > 1 {
+			errs = append(errs, field.Forbidden(confField(i), mixedCoordinatingRoleMsg))
+
+			continue
+		}
+
+		// check that node.roles is not used with an older Elasticsearch version, except for the coordinating only
+		// shortcut which is expanded by the operator
+		if cfg.Node != nil && cfg.Node.Roles != nil && !coordinatingOnly && !v.GTE(version.From(7, 9, 0)) {
 			errs = append(errs, field.Invalid(confField(i), ns.Config, nodeRolesInOldVersionMsg))
 
 			continue
 		}
 
 		// check that node.roles and node attributes are not mixed
-		nodeRoleAttrs := getNodeRoleAttrs(cfg)
+		nodeRoleAttrs := getNodeRoleAttrs(cfg, v)
 		if cfg.Node != nil && len(cfg.Node.Roles) > 0 && len(nodeRoleAttrs) > 0 {
 			errs = append(errs, field.Forbidden(confField(i), fmt.Sprintf(mixedRoleConfigMsg, strings.Join(nodeRoleAttrs, ","))))
 		}
@@ -184,7 +327,7 @@ func hasCorrectNodeRoles(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
-func getNodeRoleAttrs(cfg esv1.ElasticsearchSettings) []string {
+func getNodeRoleAttrs(cfg esv1.ElasticsearchSettings, v version.Version) []string {
 	var nodeRoleAttrs []string
 
 	//nolint:nestif
@@ -209,7 +352,8 @@ func getNodeRoleAttrs(cfg esv1.ElasticsearchSettings) []string {
 			nodeRoleAttrs = append(nodeRoleAttrs, esv1.NodeRemoteClusterClient)
 		}
 
-		if cfg.Node.Transform != nil {
+		// node.transform is explicitly set to false when unpacking the configuration of versions before 7.7.0
+		if cfg.Node.Transform != nil && v.GTE(version.From(7, 7, 0)) {
 			nodeRoleAttrs = append(nodeRoleAttrs, esv1.NodeTransform)
 		}
 
@@ -237,6 +381,341 @@ func validSanIP(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+// validHTTPTLSOptions checks that the minimum TLS version and the cipher suites of the HTTP layer can be applied.
+func validHTTPTLSOptions(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	tls := es.Spec.HTTP.TLS
+	tlsPath := field.NewPath("spec").Child("http", "tls")
+	if tls.MinVersion == "" && len(tls.CipherSuites) == 0 {
+		return nil
+	}
+	if !tls.Enabled() {
+		return field.ErrorList{field.Forbidden(tlsPath, tlsOptionsWithoutTLSMsg)}
+	}
+	if tls.MinVersion != "" && essettings.SupportedProtocols(tls.MinVersion) == nil {
+		errs = append(errs, field.NotSupported(tlsPath.Child("minVersion"), tls.MinVersion, []string{essettings.TLSv11, essettings.TLSv12, essettings.TLSv13}))
+	}
+	for i, cipherSuite := range tls.CipherSuites {
+		if _, known := essettings.KnownCipherSuites[cipherSuite]; !known {
+			errs = append(errs, field.Invalid(tlsPath.Child("cipherSuites").Index(i), cipherSuite, unknownCipherSuiteMsg))
+			continue
+		}
+		if tls.MinVersion == essettings.TLSv13 && !essettings.IsTLSv13CipherSuite(cipherSuite) {
+			errs = append(errs, field.Invalid(tlsPath.Child("cipherSuites").Index(i), cipherSuite, fmt.Sprintf(cipherSuiteBelowMinTLSVersionMsg, tls.MinVersion)))
+		}
+	}
+
+	// the same settings in the node sets configuration would be merged with the ones derived from the spec
+	var conflicts [][2]string // pairs of setting and spec field
+	if tls.MinVersion != "" {
+		conflicts = append(conflicts, [2]string{esv1.XPackSecurityHttpSslSupportedProtocols, "minVersion"})
+	}
+	if len(tls.CipherSuites) > 0 {
+		conflicts = append(conflicts, [2]string{esv1.XPackSecurityHttpSslCipherSuites, "cipherSuites"})
+	}
+	for i, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.Config == nil {
+			continue
+		}
+		config, err := common.NewCanonicalConfigFrom(nodeSet.Config.Data)
+		if err != nil {
+			// reported by noUnsupportedSettings
+			continue
+		}
+		for _, conflict := range conflicts {
+			if len(config.HasKeys([]string{conflict[0]})) > 0 {
+				errs = append(errs, field.Forbidden(field.NewPath("spec").Child("nodeSets").Index(i).Child("config").Child(conflict[0]), fmt.Sprintf(tlsOptionsConflictMsg, conflict[1])))
+			}
+		}
+	}
+	return errs
+}
+
+// validPorts checks that the HTTP and transport ports do not conflict.
+func validPorts(es esv1.Elasticsearch) field.ErrorList {
+	if es.HTTPPort() == es.TransportPort() {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("ports"), es.Spec.Ports, conflictingPortsErrMsg)}
+	}
+	return nil
+}
+
+// validRestartInPlace checks that the allocation delay of nodes restarted in place is positive.
+func validRestartInPlace(es esv1.Elasticsearch) field.ErrorList {
+	allocationDelay := es.Spec.UpdateStrategy.RestartInPlace.AllocationDelay
+	if allocationDelay != nil && allocationDelay.Duration <= 0 {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec").Child("updateStrategy", "restartInPlace", "allocationDelay"), allocationDelay.Duration.String(), invalidAllocationDelayMsg,
+		)}
+	}
+	return nil
+}
+
+// validMaintenanceWindow checks that the maintenance window restricting the upgrades is valid.
+func validMaintenanceWindow(es esv1.Elasticsearch) field.ErrorList {
+	return commonv1.CheckMaintenanceWindow(field.NewPath("spec").Child("maintenanceWindow"), es.Spec.MaintenanceWindow)
+}
+
+// validLog4j2Config checks that the log4j2 configuration comes from a single source.
+func validLog4j2Config(es esv1.Elasticsearch) field.ErrorList {
+	log4j2 := es.Spec.Log4j2
+	if log4j2 == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if es.Spec.LogFormat != "" {
+		errs = append(errs, field.Invalid(field.NewPath("spec").Child("logFormat"), es.Spec.LogFormat, log4j2ConfigConflictMsg))
+	}
+	if (log4j2.Config == "") == (log4j2.SecretName == "") {
+		errs = append(errs, field.Invalid(field.NewPath("spec").Child("log4j2"), log4j2.SecretName, invalidLog4j2ConfigMsg))
+	}
+	return errs
+}
+
+// validLogSidecar checks that the log shipping sidecar has an image and that the Elasticsearch logs are written to files,
+// which can only be configured through the environment from the minimum Stack Monitoring version.
+func validLogSidecar(es esv1.Elasticsearch) field.ErrorList {
+	if !es.HasLogSidecar() {
+		return nil
+	}
+	var errs field.ErrorList
+	// an invalid version is reported by supportedVersion
+	if ver, err := version.Parse(es.Spec.Version); err == nil && ver.LT(stackmon.MinStackVersion) {
+		errs = append(errs, field.Invalid(field.NewPath("spec").Child("version"), es.Spec.Version, logSidecarInOldVersionMsg))
+	}
+	if es.Spec.LogSidecar.Image == "" {
+		errs = append(errs, field.Required(field.NewPath("spec").Child("logSidecar").Child("image"), missingLogSidecarImageMsg))
+	}
+	if es.Spec.LogFormat != "" {
+		errs = append(errs, field.Invalid(field.NewPath("spec").Child("logFormat"), es.Spec.LogFormat, logSidecarLogFormatConflictMsg))
+	}
+	return errs
+}
+
+// validJVMOptions checks that the JVM options of the node sets can be written to the jvm.options.d directory.
+func validJVMOptions(es esv1.Elasticsearch) field.ErrorList {
+	ver, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		// reported by supportedVersion
+		return nil
+	}
+	var errs field.ErrorList
+	for i, nodeSet := range es.Spec.NodeSets {
+		if len(nodeSet.JVMOptions) == 0 {
+			continue
+		}
+		jvmOptionsPath := field.NewPath("spec").Child("nodeSets").Index(i).Child("jvmOptions")
+		if ver.LT(essettings.MinJVMOptionsDirVersion) {
+			errs = append(errs, field.Invalid(jvmOptionsPath, nodeSet.JVMOptions, jvmOptionsInOldVersionMsg))
+			continue
+		}
+		for j, option := range nodeSet.JVMOptions {
+			if strings.TrimSpace(option) == "" || strings.ContainsAny(option, "\r\n") {
+				errs = append(errs, field.Invalid(jvmOptionsPath.Index(j), option, invalidJVMOptionMsg))
+			}
+		}
+	}
+	return errs
+}
+
+// validTrustedCertificateAuthorities checks that the JDK bundled with Elasticsearch can generate the truststore holding
+// the additional CA certificates.
+func validTrustedCertificateAuthorities(es esv1.Elasticsearch) field.ErrorList {
+	if !es.HasTrustedCertificateAuthorities() {
+		return nil
+	}
+	ver, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		// reported by supportedVersion
+		return nil
+	}
+	if ver.LT(essettings.MinTrustedCAVersion) {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec").Child("trustedCertificateAuthorities"),
+			es.Spec.TrustedCertificateAuthorities.SecretName,
+			trustedCAInOldVersionMsg,
+		)}
+	}
+	return nil
+}
+
+// validKeystorePassword checks that the Docker entrypoint of Elasticsearch can read the password protecting the keystore.
+func validKeystorePassword(es esv1.Elasticsearch) field.ErrorList {
+	if es.KeystorePassword() == nil {
+		return nil
+	}
+	ver, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		// reported by supportedVersion
+		return nil
+	}
+	if ver.LT(essettings.MinKeystorePasswordVersion) {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec").Child("keystorePassword"),
+			es.Spec.KeystorePassword.SecretName,
+			keystorePasswordInOldVersionMsg,
+		)}
+	}
+	return nil
+}
+
+// validDiscovery checks that the seed hosts discovery configuration is consistent with the seed hosts provider.
+func validDiscovery(es esv1.Elasticsearch) field.ErrorList {
+	discovery := es.Spec.Discovery
+	discoveryPath := field.NewPath("spec").Child("discovery")
+	var errs field.ErrorList
+	switch discovery.Provider() {
+	case esv1.SettingsSeedHostsProvider:
+		if len(discovery.SeedHosts) == 0 {
+			errs = append(errs, field.Required(discoveryPath.Child("seedHosts"), missingSeedHostsMsg))
+		}
+	case esv1.CustomSeedHostsProvider:
+		if len(discovery.SeedProviders) == 0 {
+			errs = append(errs, field.Required(discoveryPath.Child("seedProviders"), missingSeedProvidersMsg))
+		}
+	case esv1.FileSeedHostsProvider:
+	}
+	if discovery.Provider() != esv1.CustomSeedHostsProvider && len(discovery.SeedProviders) > 0 {
+		errs = append(errs, field.Invalid(discoveryPath.Child("seedProviders"), discovery.SeedProviders, seedProvidersConflictMsg))
+	}
+	for i, host := range discovery.SeedHosts {
+		if strings.TrimSpace(host) == "" {
+			errs = append(errs, field.Invalid(discoveryPath.Child("seedHosts").Index(i), host, invalidSeedHostMsg))
+		}
+	}
+	return errs
+}
+
+// validOfflinePlugins checks that the volume holding the plugin archives is declared in all the node sets, and that the
+// plugin archives are stored in this volume.
+func validOfflinePlugins(es esv1.Elasticsearch) field.ErrorList {
+	if !es.HasOfflinePlugins() {
+		return nil
+	}
+	offlinePlugins := es.Spec.OfflinePlugins
+	offlinePluginsPath := field.NewPath("spec").Child("offlinePlugins")
+	var errs field.ErrorList
+	for i, nodeSet := range es.Spec.NodeSets {
+		if !hasVolume(nodeSet.PodTemplate.Spec.Volumes, offlinePlugins.VolumeName) {
+			errs = append(errs, field.Invalid(
+				field.NewPath("spec").Child("nodeSets").Index(i).Child("podTemplate", "spec", "volumes"),
+				offlinePlugins.VolumeName,
+				missingOfflinePluginsVolumeMsg,
+			))
+		}
+	}
+	if offlinePlugins.Path != "" && !isLocalPath(offlinePlugins.Path) {
+		errs = append(errs, field.Invalid(offlinePluginsPath.Child("path"), offlinePlugins.Path, invalidOfflinePluginPathMsg))
+	}
+	for i, archive := range offlinePlugins.Archives {
+		if !isLocalPath(archive) {
+			errs = append(errs, field.Invalid(offlinePluginsPath.Child("archives").Index(i), archive, invalidOfflinePluginPathMsg))
+		}
+	}
+	return errs
+}
+
+func hasVolume(volumes []corev1.Volume, name string) bool {
+	for _, v := range volumes {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isLocalPath returns true if the given path is a non-empty relative path that does not reference a parent directory.
+func isLocalPath(p string) bool {
+	if p == "" || path.IsAbs(p) {
+		return false
+	}
+	for _, segment := range strings.Split(p, "/") {
+		if segment == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// minRemoteClusterProxyModeVersion is the first version supporting remote clusters connected in proxy mode, used for
+// external addresses.
+var minRemoteClusterProxyModeVersion = version.MinFor(7, 7, 0)
+
+// validRemoteClusters checks that remote clusters running outside of the k8s cluster are declared with a valid address.
+func validRemoteClusters(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, remoteCluster := range es.Spec.RemoteClusters {
+		if !remoteCluster.IsExternal() {
+			continue
+		}
+		addressPath := field.NewPath("spec").Child("remoteClusters").Index(i).Child("externalAddress")
+		if remoteCluster.ElasticsearchRef.IsDefined() {
+			errs = append(errs, field.Invalid(addressPath, remoteCluster.ExternalAddress, remoteClusterAddressConflictMsg))
+		}
+		// invalid versions are reported by supportedVersion
+		if ver, err := version.Parse(es.Spec.Version); err == nil && ver.LT(minRemoteClusterProxyModeVersion) {
+			errs = append(errs, field.Invalid(addressPath, remoteCluster.ExternalAddress, remoteClusterAddressInOldVersionMsg))
+		}
+		host, port, err := net.SplitHostPort(remoteCluster.ExternalAddress)
+		if err != nil || host == "" {
+			errs = append(errs, field.Invalid(addressPath, remoteCluster.ExternalAddress, invalidRemoteClusterAddressMsg))
+			continue
+		}
+		if portNum, err := strconv.Atoi(port); err != nil || len(utilvalidation.IsValidPortNum(portNum)) > 0 {
+			errs = append(errs, field.Invalid(addressPath, remoteCluster.ExternalAddress, invalidRemoteClusterAddressMsg))
+		}
+	}
+	return errs
+}
+
+// validFrozenTier checks that the frozen tier is supported by the Elasticsearch version, and that the shared cache
+// size is only set on frozen nodes.
+func validFrozenTier(es esv1.Elasticsearch) field.ErrorList {
+	ver, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		// reported by supportedVersion
+		return nil
+	}
+	var errs field.ErrorList
+	for i, nodeSet := range es.Spec.NodeSets {
+		nodeSetPath := field.NewPath("spec").Child("nodeSets").Index(i)
+		cfg := esv1.ElasticsearchSettings{}
+		if err := esv1.UnpackConfig(nodeSet.Config, ver, &cfg); err != nil {
+			// reported by hasCorrectNodeRoles
+			continue
+		}
+		frozenRoleConfigured := cfg.Node != nil && cfg.Node.Roles != nil && cfg.Node.IsConfiguredWithRole(esv1.DataFrozenRole)
+		if frozenRoleConfigured && ver.LT(essettings.MinFrozenTierVersion) {
+			errs = append(errs, field.Invalid(nodeSetPath.Child("config"), esv1.DataFrozenRole, frozenRoleInOldVersionMsg))
+		}
+
+		sharedCacheSize := nodeSet.SharedCacheSize
+		if sharedCacheSize == nil {
+			continue
+		}
+		sizePath := nodeSetPath.Child("sharedCacheSize")
+		switch {
+		case ver.LT(essettings.MinFrozenTierVersion):
+			errs = append(errs, field.Invalid(sizePath, sharedCacheSize.String(), frozenRoleInOldVersionMsg))
+		case !cfg.Node.HasRole(esv1.DataFrozenRole):
+			errs = append(errs, field.Invalid(sizePath, sharedCacheSize.String(), sharedCacheSizeWithoutFrozenRoleMsg))
+		case sharedCacheSize.Sign() <= 0:
+			errs = append(errs, field.Invalid(sizePath, sharedCacheSize.String(), invalidSharedCacheSizeMsg))
+		}
+		if nodeSet.Config == nil {
+			continue
+		}
+		config, err := common.NewCanonicalConfigFrom(nodeSet.Config.Data)
+		if err != nil {
+			// reported by noUnsupportedSettings
+			continue
+		}
+		if len(config.HasKeys([]string{esv1.XPackSearchableSnapshotSharedCacheSize})) > 0 {
+			errs = append(errs, field.Forbidden(nodeSetPath.Child("config").Child(esv1.XPackSearchableSnapshotSharedCacheSize), fmt.Sprintf(sharedCacheSizeConflictMsg, i)))
+		}
+	}
+	return errs
+}
+
 func checkNodeSetNameUniqueness(es esv1.Elasticsearch) field.ErrorList {
 	var errs field.ErrorList
 	nodeSets := es.Spec.NodeSets
@@ -254,6 +733,118 @@ func checkNodeSetNameUniqueness(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+func checkSnapshotRepositoryNameUniqueness(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{})
+	for i, repository := range es.Spec.SnapshotRepositories {
+		if _, found := names[repository.Name]; found {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("snapshotRepositories").Index(i).Child("name"), repository.Name, duplicateSnapshotRepositories))
+		}
+		names[repository.Name] = struct{}{}
+	}
+	return errs
+}
+
+func checkIngestPipelineNameUniqueness(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{})
+	for i, pipeline := range es.Spec.IngestPipelines {
+		if _, found := names[pipeline.Name]; found {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("ingestPipelines").Index(i).Child("name"), pipeline.Name, duplicateIngestPipelines))
+		}
+		names[pipeline.Name] = struct{}{}
+	}
+	return errs
+}
+
+func checkIndexLifecyclePolicyNameUniqueness(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{})
+	for i, policy := range es.Spec.IndexLifecyclePolicies {
+		if _, found := names[policy.Name]; found {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("indexLifecyclePolicies").Index(i).Child("name"), policy.Name, duplicateIndexLifecyclePolicies))
+		}
+		names[policy.Name] = struct{}{}
+	}
+	return errs
+}
+
+func checkSecurityRoleNameUniqueness(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{})
+	for i, role := range es.Spec.SecurityRoles {
+		if _, found := names[role.Name]; found {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("securityRoles").Index(i).Child("name"), role.Name, duplicateSecurityRoles))
+		}
+		names[role.Name] = struct{}{}
+	}
+	return errs
+}
+
+func checkRoleMappingNameUniqueness(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{})
+	for i, mapping := range es.Spec.RoleMappings {
+		if _, found := names[mapping.Name]; found {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("roleMappings").Index(i).Child("name"), mapping.Name, duplicateRoleMappings))
+		}
+		names[mapping.Name] = struct{}{}
+	}
+	return errs
+}
+
+func checkBuiltinUserPasswordUniqueness(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	usernames := make(map[string]struct{})
+	for i, source := range es.Spec.Auth.BuiltinUserPasswords {
+		if _, found := usernames[source.Username]; found {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("auth", "builtinUserPasswords").Index(i).Child("username"), source.Username, duplicateBuiltinUserPasswords))
+		}
+		usernames[source.Username] = struct{}{}
+	}
+	return errs
+}
+
+// validRealms checks that the realm names are unique among the SAML and OpenID Connect realms, and that the metadata
+// of each SAML identity provider is provided either by URL or by secret.
+func validRealms(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	realmsPath := field.NewPath("spec").Child("auth", "realms")
+	if len(es.Spec.Auth.Realms.Names()) == 0 {
+		return errs
+	}
+	// the realms settings are generated with the syntax introduced in Elasticsearch 7.0.0
+	if v, err := version.Parse(es.Spec.Version); err == nil && v.LT(version.From(7, 0, 0)) {
+		errs = append(errs, field.Invalid(realmsPath, es.Spec.Version, realmsInOldVersionMsg))
+	}
+	names := make(map[string]struct{})
+	checkName := func(path *field.Path, name string) {
+		if _, found := names[name]; found {
+			errs = append(errs, field.Invalid(path.Child("name"), name, duplicateRealmNames))
+		}
+		names[name] = struct{}{}
+	}
+	for i, realm := range es.Spec.Auth.Realms.SAML {
+		checkName(realmsPath.Child("saml").Index(i), realm.Name)
+		hasMetadataSecret := realm.IdP.MetadataSecretRef != nil && realm.IdP.MetadataSecretRef.SecretName != ""
+		if (realm.IdP.MetadataURL != "") == hasMetadataSecret {
+			errs = append(errs, field.Invalid(realmsPath.Child("saml").Index(i).Child("idp"), realm.IdP, samlMetadataSourceMsg))
+		}
+	}
+	for i, realm := range es.Spec.Auth.Realms.OIDC {
+		checkName(realmsPath.Child("oidc").Index(i), realm.Name)
+	}
+	return errs
+}
+
+// noClusterNameChange checks that the name of the Elasticsearch cluster is not changed, which would form a new cluster.
+func noClusterNameChange(current, proposed esv1.Elasticsearch) field.ErrorList {
+	if current.ClusterName() == proposed.ClusterName() {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(field.NewPath("spec").Child("clusterName"), proposed.Spec.ClusterName, clusterNameChangeMsg)}
+}
+
 func noDowngrades(current, proposed esv1.Elasticsearch) field.ErrorList {
 	var errs field.ErrorList
 
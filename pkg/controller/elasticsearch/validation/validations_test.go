@@ -6,9 +6,11 @@ package validation
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -149,170 +151,1146 @@ func Test_hasCorrectNodeRoles(t *testing.T) {
 			es:           esWithRoles("7.6.0", 1, m{esv1.NodeRoles: []esv1.NodeRole{esv1.MasterRole}}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.DataRole}}),
 			expectErrors: true,
 		},
+		{
+			name:         "coordinating role mixed with other node roles",
+			es:           esWithRoles("7.9.0", 1, m{esv1.NodeRoles: []esv1.NodeRole{esv1.MasterRole}}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.CoordinatingOnlyRole, esv1.DataRole}}),
+			expectErrors: true,
+		},
+		{
+			name:         "coordinating role mixed with node attributes",
+			es:           esWithRoles("7.6.0", 1, m{esv1.NodeMaster: "true"}, m{esv1.NodeData: "true", esv1.NodeRoles: []esv1.NodeRole{esv1.CoordinatingOnlyRole}}),
+			expectErrors: true,
+		},
+		{
+			name: "valid configuration (coordinating role)",
+			es:   esWithRoles("7.9.0", 1, m{esv1.NodeRoles: []esv1.NodeRole{esv1.MasterRole, esv1.DataRole}}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.CoordinatingOnlyRole}}),
+		},
+		{
+			name: "valid configuration (coordinating role on older version)",
+			es:   esWithRoles("7.6.0", 1, m{esv1.NodeMaster: "true"}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.CoordinatingOnlyRole}}),
+		},
 		{
 			name: "valid configuration (node attributes)",
 			es:   esWithRoles("7.6.0", 3, m{esv1.NodeMaster: "true", esv1.NodeData: "true"}, m{esv1.NodeData: "true"}),
 		},
 		{
-			name: "valid configuration (node roles)",
-			es:   esWithRoles("7.9.0", 4, m{esv1.NodeRoles: []esv1.NodeRole{esv1.MasterRole, esv1.DataRole}}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.DataRole}}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.RemoteClusterClientRole}}),
+			name: "valid configuration (node roles)",
+			es:   esWithRoles("7.9.0", 4, m{esv1.NodeRoles: []esv1.NodeRole{esv1.MasterRole, esv1.DataRole}}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.DataRole}}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.RemoteClusterClientRole}}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := hasCorrectNodeRoles(tt.es)
+			hasErrors := len(result) > 0
+			if tt.expectErrors != hasErrors {
+				t.Errorf("expectedErrors=%t hasErrors=%t result=%+v", tt.expectErrors, hasErrors, result)
+			}
+		})
+	}
+}
+
+func Test_supportedVersion(t *testing.T) {
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name: "unsupported minor version should fail",
+			es:   es("6.0.0"),
+
+			expectErrors: true,
+		},
+		{
+			name:         "unsupported major should fail",
+			es:           es("1.0.0"),
+			expectErrors: true,
+		},
+		{
+			name:         "supported OK",
+			es:           es("6.8.0"),
+			expectErrors: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := supportedVersion(tt.es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed supportedVersion(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.es.Spec.Version)
+			}
+		})
+	}
+}
+
+func Test_validName(t *testing.T) {
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name: "name length too long",
+			es: esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "that-is-a-very-long-name-with-37chars",
+				},
+			},
+			expectErrors: true,
+		},
+		{
+			name: "name length OK",
+			es: esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "that-is-a-very-long-name-with-36char",
+				},
+			},
+			expectErrors: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := validName(tt.es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validName(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.es.Name)
+			}
+		})
+	}
+}
+
+func Test_validSanIP(t *testing.T) {
+	validIP := "3.4.5.6"
+	validIP2 := "192.168.12.13"
+	validIPv6 := "2001:db8:0:85a3:0:0:ac1f:8001"
+	invalidIP := "notanip"
+
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name: "no SAN IP: OK",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{},
+			},
+			expectErrors: false,
+		},
+		{
+			name: "valid SAN IPs: OK",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					HTTP: commonv1.HTTPConfig{
+						TLS: commonv1.TLSOptions{
+							SelfSignedCertificate: &commonv1.SelfSignedCertificate{
+								SubjectAlternativeNames: []commonv1.SubjectAlternativeName{
+									{
+										IP: validIP,
+									},
+									{
+										IP: validIP2,
+									},
+									{
+										IP: validIPv6,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErrors: false,
+		},
+		{
+			name: "invalid SAN IPs: NOT OK",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					HTTP: commonv1.HTTPConfig{
+						TLS: commonv1.TLSOptions{
+							SelfSignedCertificate: &commonv1.SelfSignedCertificate{
+								SubjectAlternativeNames: []commonv1.SubjectAlternativeName{
+									{
+										IP: invalidIP,
+									},
+									{
+										IP: validIP2,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := validSanIP(tt.es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validSanIP(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.es.Spec)
+			}
+		})
+	}
+}
+
+func Test_validHTTPTLSOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		tls          commonv1.TLSOptions
+		config       map[string]interface{}
+		expectErrors bool
+	}{
+		{
+			name:         "no TLS options: OK",
+			expectErrors: false,
+		},
+		{
+			name: "known minimum version and cipher suites: OK",
+			tls: commonv1.TLSOptions{
+				MinVersion:   "TLSv1.2",
+				CipherSuites: []string{"TLS_AES_256_GCM_SHA384", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+			},
+			expectErrors: false,
+		},
+		{
+			name:         "unknown minimum version: NOT OK",
+			tls:          commonv1.TLSOptions{MinVersion: "SSLv3"},
+			expectErrors: true,
+		},
+		{
+			name:         "unknown cipher suite: NOT OK",
+			tls:          commonv1.TLSOptions{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384", "ECDHE-RSA-AES256-GCM-SHA384"}},
+			expectErrors: true,
+		},
+		{
+			name: "TLSv1.2 cipher suite with TLSv1.3 minimum version: NOT OK",
+			tls: commonv1.TLSOptions{
+				MinVersion:   "TLSv1.3",
+				CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+			},
+			expectErrors: true,
+		},
+		{
+			name: "TLS options with TLS disabled: NOT OK",
+			tls: commonv1.TLSOptions{
+				SelfSignedCertificate: &commonv1.SelfSignedCertificate{Disabled: true},
+				MinVersion:            "TLSv1.2",
+			},
+			expectErrors: true,
+		},
+		{
+			name:         "minimum version also set in the node set config: NOT OK",
+			tls:          commonv1.TLSOptions{MinVersion: "TLSv1.2"},
+			config:       map[string]interface{}{esv1.XPackSecurityHttpSslSupportedProtocols: []string{"TLSv1.3"}},
+			expectErrors: true,
+		},
+		{
+			name:         "other TLS settings in the node set config: OK",
+			tls:          commonv1.TLSOptions{MinVersion: "TLSv1.2"},
+			config:       map[string]interface{}{esv1.XPackSecurityHttpSslClientAuthentication: "optional"},
+			expectErrors: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{
+				HTTP:     commonv1.HTTPConfig{TLS: tt.tls},
+				NodeSets: []esv1.NodeSet{{Name: "default", Count: 1, Config: &commonv1.Config{Data: tt.config}}},
+			}}
+			actual := validHTTPTLSOptions(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validHTTPTLSOptions(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.tls)
+			}
+		})
+	}
+}
+
+func Test_checkSnapshotRepositoryNameUniqueness(t *testing.T) {
+	tests := []struct {
+		name         string
+		repositories []esv1.SnapshotRepository
+		expectErrors bool
+	}{
+		{
+			name:         "no repository: OK",
+			expectErrors: false,
+		},
+		{
+			name:         "unique names: OK",
+			repositories: []esv1.SnapshotRepository{{Name: "a", Type: "s3"}, {Name: "b", Type: "gcs"}},
+			expectErrors: false,
+		},
+		{
+			name:         "duplicate names: NOT OK",
+			repositories: []esv1.SnapshotRepository{{Name: "a", Type: "s3"}, {Name: "a", Type: "gcs"}},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{SnapshotRepositories: tt.repositories}}
+			actual := checkSnapshotRepositoryNameUniqueness(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed checkSnapshotRepositoryNameUniqueness(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.repositories)
+			}
+		})
+	}
+}
+
+func Test_checkIngestPipelineNameUniqueness(t *testing.T) {
+	tests := []struct {
+		name         string
+		pipelines    []esv1.IngestPipeline
+		expectErrors bool
+	}{
+		{
+			name:         "no pipeline: OK",
+			expectErrors: false,
+		},
+		{
+			name:         "unique names: OK",
+			pipelines:    []esv1.IngestPipeline{{Name: "a"}, {Name: "b"}},
+			expectErrors: false,
+		},
+		{
+			name:         "duplicate names: NOT OK",
+			pipelines:    []esv1.IngestPipeline{{Name: "a"}, {Name: "a"}},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{IngestPipelines: tt.pipelines}}
+			actual := checkIngestPipelineNameUniqueness(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed checkIngestPipelineNameUniqueness(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.pipelines)
+			}
+		})
+	}
+}
+
+func Test_checkIndexLifecyclePolicyNameUniqueness(t *testing.T) {
+	tests := []struct {
+		name         string
+		policies     []esv1.IndexLifecyclePolicy
+		expectErrors bool
+	}{
+		{
+			name:         "no policy: OK",
+			expectErrors: false,
+		},
+		{
+			name:         "unique names: OK",
+			policies:     []esv1.IndexLifecyclePolicy{{Name: "a"}, {Name: "b"}},
+			expectErrors: false,
+		},
+		{
+			name:         "duplicate names: NOT OK",
+			policies:     []esv1.IndexLifecyclePolicy{{Name: "a"}, {Name: "a"}},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{IndexLifecyclePolicies: tt.policies}}
+			actual := checkIndexLifecyclePolicyNameUniqueness(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed checkIndexLifecyclePolicyNameUniqueness(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.policies)
+			}
+		})
+	}
+}
+
+func Test_checkSecurityRoleNameUniqueness(t *testing.T) {
+	tests := []struct {
+		name         string
+		roles        []esv1.SecurityRole
+		expectErrors bool
+	}{
+		{
+			name:         "no role: OK",
+			expectErrors: false,
+		},
+		{
+			name:         "unique names: OK",
+			roles:        []esv1.SecurityRole{{Name: "a"}, {Name: "b"}},
+			expectErrors: false,
+		},
+		{
+			name:         "duplicate names: NOT OK",
+			roles:        []esv1.SecurityRole{{Name: "a"}, {Name: "a"}},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{SecurityRoles: tt.roles}}
+			actual := checkSecurityRoleNameUniqueness(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed checkSecurityRoleNameUniqueness(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.roles)
+			}
+		})
+	}
+}
+
+func Test_checkRoleMappingNameUniqueness(t *testing.T) {
+	tests := []struct {
+		name         string
+		mappings     []esv1.RoleMapping
+		expectErrors bool
+	}{
+		{
+			name:         "no role mapping: OK",
+			expectErrors: false,
+		},
+		{
+			name:         "unique names: OK",
+			mappings:     []esv1.RoleMapping{{Name: "a"}, {Name: "b"}},
+			expectErrors: false,
+		},
+		{
+			name:         "duplicate names: NOT OK",
+			mappings:     []esv1.RoleMapping{{Name: "a"}, {Name: "a"}},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{RoleMappings: tt.mappings}}
+			actual := checkRoleMappingNameUniqueness(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed checkRoleMappingNameUniqueness(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.mappings)
+			}
+		})
+	}
+}
+
+func Test_checkBuiltinUserPasswordUniqueness(t *testing.T) {
+	tests := []struct {
+		name         string
+		passwords    []esv1.BuiltinUserPasswordSource
+		expectErrors bool
+	}{
+		{
+			name:         "no password: OK",
+			expectErrors: false,
+		},
+		{
+			name: "unique users: OK",
+			passwords: []esv1.BuiltinUserPasswordSource{
+				{Username: "elastic", SecretRef: commonv1.SecretRef{SecretName: "a"}},
+				{Username: "kibana_system", SecretRef: commonv1.SecretRef{SecretName: "b"}},
+			},
+			expectErrors: false,
+		},
+		{
+			name: "duplicate users: NOT OK",
+			passwords: []esv1.BuiltinUserPasswordSource{
+				{Username: "elastic", SecretRef: commonv1.SecretRef{SecretName: "a"}},
+				{Username: "elastic", SecretRef: commonv1.SecretRef{SecretName: "b"}},
+			},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Auth: esv1.Auth{BuiltinUserPasswords: tt.passwords}}}
+			actual := checkBuiltinUserPasswordUniqueness(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed checkBuiltinUserPasswordUniqueness(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.passwords)
+			}
+		})
+	}
+}
+
+func Test_validRealms(t *testing.T) {
+	metadataSecret := &commonv1.SecretRef{SecretName: "idp-metadata"}
+	tests := []struct {
+		name         string
+		version      string
+		realms       esv1.Realms
+		expectErrors bool
+	}{
+		{
+			name:         "no realm: OK",
+			expectErrors: false,
+		},
+		{
+			name: "unique realms: OK",
+			realms: esv1.Realms{
+				SAML: []esv1.SAMLRealm{
+					{Name: "saml1", IdP: esv1.SAMLIdentityProvider{MetadataURL: "https://idp/metadata"}},
+					{Name: "saml2", IdP: esv1.SAMLIdentityProvider{MetadataSecretRef: metadataSecret}},
+				},
+				OIDC: []esv1.OIDCRealm{{Name: "oidc1"}},
+			},
+			expectErrors: false,
+		},
+		{
+			name: "duplicate realm names across realm types: NOT OK",
+			realms: esv1.Realms{
+				SAML: []esv1.SAMLRealm{{Name: "realm1", IdP: esv1.SAMLIdentityProvider{MetadataURL: "https://idp/metadata"}}},
+				OIDC: []esv1.OIDCRealm{{Name: "realm1"}},
+			},
+			expectErrors: true,
+		},
+		{
+			name: "no SAML metadata: NOT OK",
+			realms: esv1.Realms{
+				SAML: []esv1.SAMLRealm{{Name: "saml1"}},
+			},
+			expectErrors: true,
+		},
+		{
+			name: "both SAML metadata URL and secret: NOT OK",
+			realms: esv1.Realms{
+				SAML: []esv1.SAMLRealm{{Name: "saml1", IdP: esv1.SAMLIdentityProvider{MetadataURL: "https://idp/metadata", MetadataSecretRef: metadataSecret}}},
+			},
+			expectErrors: true,
+		},
+		{
+			name:    "realms with Elasticsearch 6.x: NOT OK",
+			version: "6.8.0",
+			realms: esv1.Realms{
+				OIDC: []esv1.OIDCRealm{{Name: "oidc1"}},
+			},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: tt.version, Auth: esv1.Auth{Realms: tt.realms}}}
+			actual := validRealms(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validRealms(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.realms)
+			}
+		})
+	}
+}
+
+func Test_validPorts(t *testing.T) {
+	tests := []struct {
+		name         string
+		ports        esv1.PortsConfig
+		expectErrors bool
+	}{
+		{
+			name:         "default ports: OK",
+			expectErrors: false,
+		},
+		{
+			name:         "overridden ports: OK",
+			ports:        esv1.PortsConfig{HTTP: 9201, Transport: 9301},
+			expectErrors: false,
+		},
+		{
+			name:         "HTTP port conflicting with the default transport port: NOT OK",
+			ports:        esv1.PortsConfig{HTTP: 9300},
+			expectErrors: true,
+		},
+		{
+			name:         "identical ports: NOT OK",
+			ports:        esv1.PortsConfig{HTTP: 9400, Transport: 9400},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Ports: tt.ports}}
+			actual := validPorts(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validPorts(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.ports)
+			}
+		})
+	}
+}
+
+func Test_validRestartInPlace(t *testing.T) {
+	tests := []struct {
+		name           string
+		restartInPlace esv1.RestartInPlace
+		expectErrors   bool
+	}{
+		{
+			name:         "not set: OK",
+			expectErrors: false,
+		},
+		{
+			name:           "default allocation delay: OK",
+			restartInPlace: esv1.RestartInPlace{Enabled: true},
+			expectErrors:   false,
+		},
+		{
+			name:           "positive allocation delay: OK",
+			restartInPlace: esv1.RestartInPlace{Enabled: true, AllocationDelay: &metav1.Duration{Duration: 15 * time.Minute}},
+			expectErrors:   false,
+		},
+		{
+			name:           "zero allocation delay: NOT OK",
+			restartInPlace: esv1.RestartInPlace{Enabled: true, AllocationDelay: &metav1.Duration{}},
+			expectErrors:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{UpdateStrategy: esv1.UpdateStrategy{RestartInPlace: tt.restartInPlace}}}
+			actual := validRestartInPlace(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validRestartInPlace(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.restartInPlace)
+			}
+		})
+	}
+}
+
+func Test_validLog4j2Config(t *testing.T) {
+	tests := []struct {
+		name         string
+		logFormat    esv1.LogFormat
+		log4j2       *esv1.Log4j2Config
+		expectErrors bool
+	}{
+		{
+			name:         "not set: OK",
+			expectErrors: false,
+		},
+		{
+			name:         "log format: OK",
+			logFormat:    esv1.JSONLogFormat,
+			expectErrors: false,
+		},
+		{
+			name:         "inline config: OK",
+			log4j2:       &esv1.Log4j2Config{Config: "rootLogger.level = info"},
+			expectErrors: false,
+		},
+		{
+			name:         "config from a secret: OK",
+			log4j2:       &esv1.Log4j2Config{SecretName: "log4j2"},
+			expectErrors: false,
+		},
+		{
+			name:         "empty config: NOT OK",
+			log4j2:       &esv1.Log4j2Config{},
+			expectErrors: true,
+		},
+		{
+			name:         "both inline and secret config: NOT OK",
+			log4j2:       &esv1.Log4j2Config{Config: "rootLogger.level = info", SecretName: "log4j2"},
+			expectErrors: true,
+		},
+		{
+			name:         "custom config combined with a log format: NOT OK",
+			logFormat:    esv1.JSONLogFormat,
+			log4j2:       &esv1.Log4j2Config{SecretName: "log4j2"},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{LogFormat: tt.logFormat, Log4j2: tt.log4j2}}
+			actual := validLog4j2Config(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validLog4j2Config(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
+			}
+		})
+	}
+}
+
+func Test_validLogSidecar(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      string
+		logFormat    esv1.LogFormat
+		logSidecar   *esv1.LogSidecar
+		expectErrors bool
+	}{
+		{
+			name:         "not set: OK",
+			expectErrors: false,
+		},
+		{
+			name:         "image: OK",
+			logSidecar:   &esv1.LogSidecar{Image: "fluent/fluent-bit:3.0"},
+			expectErrors: false,
+		},
+		{
+			name:         "image and config: OK",
+			logSidecar:   &esv1.LogSidecar{Image: "fluent/fluent-bit:3.0", ConfigSecretName: "fluent-bit-config"},
+			expectErrors: false,
+		},
+		{
+			name:         "no image: NOT OK",
+			logSidecar:   &esv1.LogSidecar{ConfigSecretName: "fluent-bit-config"},
+			expectErrors: true,
+		},
+		{
+			name:         "combined with a log format: NOT OK",
+			logFormat:    esv1.JSONLogFormat,
+			logSidecar:   &esv1.LogSidecar{Image: "fluent/fluent-bit:3.0"},
+			expectErrors: true,
+		},
+		{
+			name:         "7.14.0: OK",
+			version:      "7.14.0",
+			logSidecar:   &esv1.LogSidecar{Image: "fluent/fluent-bit:3.0"},
+			expectErrors: false,
+		},
+		{
+			name:         "before 7.14.0: NOT OK",
+			version:      "7.13.4",
+			logSidecar:   &esv1.LogSidecar{Image: "fluent/fluent-bit:3.0"},
+			expectErrors: true,
+		},
+		{
+			name:         "not set before 7.14.0: OK",
+			version:      "7.13.4",
+			expectErrors: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.version == "" {
+				tt.version = "8.14.0"
+			}
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: tt.version, LogFormat: tt.logFormat, LogSidecar: tt.logSidecar}}
+			actual := validLogSidecar(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validLogSidecar(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
+			}
+		})
+	}
+}
+
+func Test_validJVMOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      string
+		jvmOptions   []string
+		expectErrors bool
+	}{
+		{
+			name:         "not set: OK",
+			version:      "7.0.0",
+			expectErrors: false,
+		},
+		{
+			name:         "system properties: OK",
+			version:      "7.7.0",
+			jvmOptions:   []string{"-Dfoo=bar", "-XX:+UseG1GC"},
+			expectErrors: false,
+		},
+		{
+			name:         "before 7.7.0: NOT OK",
+			version:      "7.6.2",
+			jvmOptions:   []string{"-Dfoo=bar"},
+			expectErrors: true,
+		},
+		{
+			name:         "empty option: NOT OK",
+			version:      "8.12.0",
+			jvmOptions:   []string{" "},
+			expectErrors: true,
+		},
+		{
+			name:         "multi-line option: NOT OK",
+			version:      "8.12.0",
+			jvmOptions:   []string{"-Dfoo=bar\n-Dbar=baz"},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{
+				Version:  tt.version,
+				NodeSets: []esv1.NodeSet{{Name: "default", JVMOptions: tt.jvmOptions}},
+			}}
+			actual := validJVMOptions(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validJVMOptions(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
+			}
+		})
+	}
+}
+
+func Test_validDiscovery(t *testing.T) {
+	tests := []struct {
+		name         string
+		discovery    esv1.DiscoveryConfig
+		expectErrors bool
+	}{
+		{
+			name:         "not set: OK",
+			expectErrors: false,
+		},
+		{
+			name:         "file provider with seed hosts: OK",
+			discovery:    esv1.DiscoveryConfig{SeedHostsProvider: esv1.FileSeedHostsProvider, SeedHosts: []string{"10.0.0.1:9300"}},
+			expectErrors: false,
+		},
+		{
+			name:         "settings provider with seed hosts: OK",
+			discovery:    esv1.DiscoveryConfig{SeedHostsProvider: esv1.SettingsSeedHostsProvider, SeedHosts: []string{"10.0.0.1:9300"}},
+			expectErrors: false,
+		},
+		{
+			name:         "settings provider without seed hosts: NOT OK",
+			discovery:    esv1.DiscoveryConfig{SeedHostsProvider: esv1.SettingsSeedHostsProvider},
+			expectErrors: true,
+		},
+		{
+			name:         "custom provider with seed providers: OK",
+			discovery:    esv1.DiscoveryConfig{SeedHostsProvider: esv1.CustomSeedHostsProvider, SeedProviders: []string{"ec2"}},
+			expectErrors: false,
+		},
+		{
+			name:         "custom provider without seed providers: NOT OK",
+			discovery:    esv1.DiscoveryConfig{SeedHostsProvider: esv1.CustomSeedHostsProvider},
+			expectErrors: true,
+		},
+		{
+			name:         "seed providers with the default provider: NOT OK",
+			discovery:    esv1.DiscoveryConfig{SeedProviders: []string{"ec2"}},
+			expectErrors: true,
+		},
+		{
+			name:         "empty seed host: NOT OK",
+			discovery:    esv1.DiscoveryConfig{SeedHostsProvider: esv1.SettingsSeedHostsProvider, SeedHosts: []string{" "}},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.12.0", Discovery: tt.discovery}}
+			actual := validDiscovery(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validDiscovery(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
+			}
+		})
+	}
+}
+
+func Test_validOfflinePlugins(t *testing.T) {
+	nodeSetWithVolume := esv1.NodeSet{Name: "default", PodTemplate: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+		Volumes: []corev1.Volume{{Name: "plugin-bundle"}},
+	}}}
+	tests := []struct {
+		name           string
+		nodeSets       []esv1.NodeSet
+		offlinePlugins *esv1.OfflinePlugins
+		expectErrors   bool
+	}{
+		{
+			name:         "not set: OK",
+			nodeSets:     []esv1.NodeSet{{Name: "default"}},
+			expectErrors: false,
+		},
+		{
+			name:           "volume declared in all the node sets: OK",
+			nodeSets:       []esv1.NodeSet{nodeSetWithVolume, nodeSetWithVolume},
+			offlinePlugins: &esv1.OfflinePlugins{VolumeName: "plugin-bundle", Path: "8.13.0", Archives: []string{"analysis-icu.zip", "custom/plugin.zip"}},
+			expectErrors:   false,
+		},
+		{
+			name:           "volume missing in a node set: NOT OK",
+			nodeSets:       []esv1.NodeSet{nodeSetWithVolume, {Name: "other"}},
+			offlinePlugins: &esv1.OfflinePlugins{VolumeName: "plugin-bundle", Archives: []string{"analysis-icu.zip"}},
+			expectErrors:   true,
+		},
+		{
+			name:           "absolute path: NOT OK",
+			nodeSets:       []esv1.NodeSet{nodeSetWithVolume},
+			offlinePlugins: &esv1.OfflinePlugins{VolumeName: "plugin-bundle", Path: "/plugins", Archives: []string{"analysis-icu.zip"}},
+			expectErrors:   true,
+		},
+		{
+			name:           "archive outside of the volume: NOT OK",
+			nodeSets:       []esv1.NodeSet{nodeSetWithVolume},
+			offlinePlugins: &esv1.OfflinePlugins{VolumeName: "plugin-bundle", Archives: []string{"../analysis-icu.zip"}},
+			expectErrors:   true,
+		},
+		{
+			name:           "empty archive: NOT OK",
+			nodeSets:       []esv1.NodeSet{nodeSetWithVolume},
+			offlinePlugins: &esv1.OfflinePlugins{VolumeName: "plugin-bundle", Archives: []string{""}},
+			expectErrors:   true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := hasCorrectNodeRoles(tt.es)
-			hasErrors := len(result) > 0
-			if tt.expectErrors != hasErrors {
-				t.Errorf("expectedErrors=%t hasErrors=%t result=%+v", tt.expectErrors, hasErrors, result)
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: tt.nodeSets, OfflinePlugins: tt.offlinePlugins}}
+			actual := validOfflinePlugins(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validOfflinePlugins(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
 			}
 		})
 	}
 }
 
-func Test_supportedVersion(t *testing.T) {
+func Test_validTrustedCertificateAuthorities(t *testing.T) {
 	tests := []struct {
 		name         string
-		es           esv1.Elasticsearch
+		version      string
+		trustedCA    *commonv1.SecretRef
 		expectErrors bool
 	}{
 		{
-			name: "unsupported minor version should fail",
-			es:   es("6.0.0"),
-
-			expectErrors: true,
+			name:         "not set: OK",
+			version:      "6.8.0",
+			expectErrors: false,
 		},
 		{
-			name:         "unsupported major should fail",
-			es:           es("1.0.0"),
+			name:         "7.0.0: OK",
+			version:      "7.0.0",
+			trustedCA:    &commonv1.SecretRef{SecretName: "ca"},
+			expectErrors: false,
+		},
+		{
+			name:         "before 7.0.0: NOT OK",
+			version:      "6.8.0",
+			trustedCA:    &commonv1.SecretRef{SecretName: "ca"},
 			expectErrors: true,
 		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{
+				Version:                       tt.version,
+				TrustedCertificateAuthorities: tt.trustedCA,
+			}}
+			actual := validTrustedCertificateAuthorities(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validTrustedCertificateAuthorities(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
+			}
+		})
+	}
+}
+
+func Test_validKeystorePassword(t *testing.T) {
+	tests := []struct {
+		name             string
+		version          string
+		keystorePassword *commonv1.SecretRef
+		expectErrors     bool
+	}{
 		{
-			name:         "supported OK",
-			es:           es("6.8.0"),
+			name:         "not set: OK",
+			version:      "7.8.0",
 			expectErrors: false,
 		},
+		{
+			name:             "7.9.0: OK",
+			version:          "7.9.0",
+			keystorePassword: &commonv1.SecretRef{SecretName: "keystore-password"},
+			expectErrors:     false,
+		},
+		{
+			name:             "before 7.9.0: NOT OK",
+			version:          "7.8.1",
+			keystorePassword: &commonv1.SecretRef{SecretName: "keystore-password"},
+			expectErrors:     true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			actual := supportedVersion(tt.es)
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{
+				Version:          tt.version,
+				KeystorePassword: tt.keystorePassword,
+			}}
+			actual := validKeystorePassword(es)
 			actualErrors := len(actual) > 0
 			if tt.expectErrors != actualErrors {
-				t.Errorf("failed supportedVersion(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.es.Spec.Version)
+				t.Errorf("failed validKeystorePassword(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
 			}
 		})
 	}
 }
 
-func Test_validName(t *testing.T) {
+func Test_validFrozenTier(t *testing.T) {
+	roles := func(roles ...string) *commonv1.Config {
+		return &commonv1.Config{Data: map[string]interface{}{"node.roles": roles}}
+	}
+	size := func(q string) *resource.Quantity {
+		v := resource.MustParse(q)
+		return &v
+	}
 	tests := []struct {
 		name         string
-		es           esv1.Elasticsearch
+		version      string
+		nodeSet      esv1.NodeSet
 		expectErrors bool
 	}{
 		{
-			name: "name length too long",
-			es: esv1.Elasticsearch{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: "default",
-					Name:      "that-is-a-very-long-name-with-37chars",
-				},
-			},
+			name:         "frozen role: OK",
+			version:      "7.12.0",
+			nodeSet:      esv1.NodeSet{Config: roles("data_frozen")},
+			expectErrors: false,
+		},
+		{
+			name:         "frozen role before 7.12.0: NOT OK",
+			version:      "7.11.2",
+			nodeSet:      esv1.NodeSet{Config: roles("data_frozen")},
 			expectErrors: true,
 		},
 		{
-			name: "name length OK",
-			es: esv1.Elasticsearch{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: "default",
-					Name:      "that-is-a-very-long-name-with-36char",
-				},
-			},
+			name:         "other data roles before 7.12.0: OK",
+			version:      "7.11.2",
+			nodeSet:      esv1.NodeSet{Config: roles("master", "data_hot")},
+			expectErrors: false,
+		},
+		{
+			name:         "default roles before 7.12.0: OK",
+			version:      "7.11.2",
+			nodeSet:      esv1.NodeSet{},
+			expectErrors: false,
+		},
+		{
+			name:         "shared cache size on a frozen node: OK",
+			version:      "8.12.0",
+			nodeSet:      esv1.NodeSet{Config: roles("data_frozen"), SharedCacheSize: size("20Gi")},
+			expectErrors: false,
+		},
+		{
+			name:         "shared cache size on a node with default roles: OK",
+			version:      "8.12.0",
+			nodeSet:      esv1.NodeSet{SharedCacheSize: size("20Gi")},
 			expectErrors: false,
 		},
+		{
+			name:         "shared cache size before 7.12.0: NOT OK",
+			version:      "7.11.2",
+			nodeSet:      esv1.NodeSet{SharedCacheSize: size("20Gi")},
+			expectErrors: true,
+		},
+		{
+			name:         "shared cache size on a node without the frozen role: NOT OK",
+			version:      "8.12.0",
+			nodeSet:      esv1.NodeSet{Config: roles("data_hot"), SharedCacheSize: size("20Gi")},
+			expectErrors: true,
+		},
+		{
+			name:         "zero shared cache size: NOT OK",
+			version:      "8.12.0",
+			nodeSet:      esv1.NodeSet{Config: roles("data_frozen"), SharedCacheSize: size("0")},
+			expectErrors: true,
+		},
+		{
+			name:    "shared cache size also set in the config: NOT OK",
+			version: "8.12.0",
+			nodeSet: esv1.NodeSet{
+				Config: &commonv1.Config{Data: map[string]interface{}{
+					"node.roles": []string{"data_frozen"},
+					esv1.XPackSearchableSnapshotSharedCacheSize: "50%",
+				}},
+				SharedCacheSize: size("20Gi"),
+			},
+			expectErrors: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			actual := validName(tt.es)
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: tt.version, NodeSets: []esv1.NodeSet{tt.nodeSet}}}
+			actual := validFrozenTier(es)
 			actualErrors := len(actual) > 0
 			if tt.expectErrors != actualErrors {
-				t.Errorf("failed validName(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.es.Name)
+				t.Errorf("failed validFrozenTier(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
 			}
 		})
 	}
 }
 
-func Test_validSanIP(t *testing.T) {
-	validIP := "3.4.5.6"
-	validIP2 := "192.168.12.13"
-	validIPv6 := "2001:db8:0:85a3:0:0:ac1f:8001"
-	invalidIP := "notanip"
-
+func Test_validRemoteClusters(t *testing.T) {
 	tests := []struct {
-		name         string
-		es           esv1.Elasticsearch
-		expectErrors bool
+		name          string
+		version       string
+		remoteCluster esv1.RemoteCluster
+		expectErrors  bool
 	}{
 		{
-			name: "no SAN IP: OK",
-			es: esv1.Elasticsearch{
-				Spec: esv1.ElasticsearchSpec{},
-			},
-			expectErrors: false,
+			name:          "elasticsearchRef: OK",
+			remoteCluster: esv1.RemoteCluster{Name: "remote", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es"}},
+			expectErrors:  false,
 		},
 		{
-			name: "valid SAN IPs: OK",
-			es: esv1.Elasticsearch{
-				Spec: esv1.ElasticsearchSpec{
-					HTTP: commonv1.HTTPConfig{
-						TLS: commonv1.TLSOptions{
-							SelfSignedCertificate: &commonv1.SelfSignedCertificate{
-								SubjectAlternativeNames: []commonv1.SubjectAlternativeName{
-									{
-										IP: validIP,
-									},
-									{
-										IP: validIP2,
-									},
-									{
-										IP: validIPv6,
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-			expectErrors: false,
+			name:          "external address: OK",
+			remoteCluster: esv1.RemoteCluster{Name: "remote", ExternalAddress: "remote.example.com:9300"},
+			expectErrors:  false,
 		},
 		{
-			name: "invalid SAN IPs: NOT OK",
-			es: esv1.Elasticsearch{
-				Spec: esv1.ElasticsearchSpec{
-					HTTP: commonv1.HTTPConfig{
-						TLS: commonv1.TLSOptions{
-							SelfSignedCertificate: &commonv1.SelfSignedCertificate{
-								SubjectAlternativeNames: []commonv1.SubjectAlternativeName{
-									{
-										IP: invalidIP,
-									},
-									{
-										IP: validIP2,
-									},
-								},
-							},
-						},
-					},
-				},
+			name:          "external IPv6 address: OK",
+			remoteCluster: esv1.RemoteCluster{Name: "remote", ExternalAddress: "[2001:db8::1]:9300"},
+			expectErrors:  false,
+		},
+		{
+			name: "external address and elasticsearchRef: NOT OK",
+			remoteCluster: esv1.RemoteCluster{
+				Name:             "remote",
+				ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es"},
+				ExternalAddress:  "remote.example.com:9300",
 			},
 			expectErrors: true,
 		},
+		{
+			name:          "external address in 7.7.0: OK",
+			version:       "7.7.0",
+			remoteCluster: esv1.RemoteCluster{Name: "remote", ExternalAddress: "remote.example.com:9300"},
+			expectErrors:  false,
+		},
+		{
+			name:          "external address before 7.7.0: NOT OK",
+			version:       "7.6.2",
+			remoteCluster: esv1.RemoteCluster{Name: "remote", ExternalAddress: "remote.example.com:9300"},
+			expectErrors:  true,
+		},
+		{
+			name:          "elasticsearchRef before 7.7.0: OK",
+			version:       "6.8.23",
+			remoteCluster: esv1.RemoteCluster{Name: "remote", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es"}},
+			expectErrors:  false,
+		},
+		{
+			name:          "external address without port: NOT OK",
+			remoteCluster: esv1.RemoteCluster{Name: "remote", ExternalAddress: "remote.example.com"},
+			expectErrors:  true,
+		},
+		{
+			name:          "external address without host: NOT OK",
+			remoteCluster: esv1.RemoteCluster{Name: "remote", ExternalAddress: ":9300"},
+			expectErrors:  true,
+		},
+		{
+			name:          "external address with an invalid port: NOT OK",
+			remoteCluster: esv1.RemoteCluster{Name: "remote", ExternalAddress: "remote.example.com:99999"},
+			expectErrors:  true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			actual := validSanIP(tt.es)
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: tt.version, RemoteClusters: []esv1.RemoteCluster{tt.remoteCluster}}}
+			actual := validRemoteClusters(es)
 			actualErrors := len(actual) > 0
 			if tt.expectErrors != actualErrors {
-				t.Errorf("failed validSanIP(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.es.Spec)
+				t.Errorf("failed validRemoteClusters(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
 			}
 		})
 	}
@@ -349,6 +1327,67 @@ func TestValidation_noDowngrades(t *testing.T) {
 	}
 }
 
+func Test_noClusterNameChange(t *testing.T) {
+	withClusterName := func(name string, clusterName string) esv1.Elasticsearch {
+		return esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       esv1.ElasticsearchSpec{ClusterName: clusterName},
+		}
+	}
+	tests := []struct {
+		name         string
+		current      esv1.Elasticsearch
+		proposed     esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name:         "default cluster name unchanged",
+			current:      withClusterName("foo", ""),
+			proposed:     withClusterName("foo", ""),
+			expectErrors: false,
+		},
+		{
+			name:         "cluster name unchanged",
+			current:      withClusterName("foo", "bar"),
+			proposed:     withClusterName("foo", "bar"),
+			expectErrors: false,
+		},
+		{
+			name:         "default cluster name explicitly set",
+			current:      withClusterName("foo", ""),
+			proposed:     withClusterName("foo", "foo"),
+			expectErrors: false,
+		},
+		{
+			name:         "cluster name set after creation",
+			current:      withClusterName("foo", ""),
+			proposed:     withClusterName("foo", "bar"),
+			expectErrors: true,
+		},
+		{
+			name:         "cluster name changed",
+			current:      withClusterName("foo", "bar"),
+			proposed:     withClusterName("foo", "baz"),
+			expectErrors: true,
+		},
+		{
+			name:         "cluster name removed",
+			current:      withClusterName("foo", "bar"),
+			proposed:     withClusterName("foo", ""),
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := noClusterNameChange(tt.current, tt.proposed)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed noClusterNameChange(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.proposed)
+			}
+		})
+	}
+}
+
 func Test_validUpgradePath(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -497,6 +1536,29 @@ func Test_validNodeLabels(t *testing.T) {
 				exposedNodeLabels: []string{"topology.kubernetes.io/*", "failure-domain.beta.kubernetes.io/*"},
 			},
 		},
+		{
+			name: "Invalid node attribute label",
+			args: args{
+				proposed: esv1.Elasticsearch{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{esv1.NodeAttributesAnnotation: "zone=failure-domain.beta.kubernetes.io/zone"},
+					},
+				},
+				exposedNodeLabels: []string{"topology.kubernetes.io/*"},
+			},
+			expectErrors: true,
+		},
+		{
+			name: "Valid node attribute label",
+			args: args{
+				proposed: esv1.Elasticsearch{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{esv1.NodeAttributesAnnotation: "zone=topology.kubernetes.io/zone"},
+					},
+				},
+				exposedNodeLabels: []string{"topology.kubernetes.io/*"},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -511,6 +1573,58 @@ func Test_validNodeLabels(t *testing.T) {
 	}
 }
 
+func Test_validNodeAttributes(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		wantErrs   int
+	}{
+		{
+			name:       "no annotation",
+			annotation: "",
+		},
+		{
+			name:       "valid node attributes",
+			annotation: "zone=topology.kubernetes.io/zone, rack_id=example.com/rack",
+		},
+		{
+			name:       "missing node label",
+			annotation: "zone",
+			wantErrs:   1,
+		},
+		{
+			name:       "invalid attribute name",
+			annotation: "zone.name=topology.kubernetes.io/zone",
+			wantErrs:   1,
+		},
+		{
+			name:       "invalid node label",
+			annotation: "zone=invalid label",
+			wantErrs:   1,
+		},
+		{
+			name:       "reserved attribute name",
+			annotation: "k8s_node_name=kubernetes.io/hostname",
+			wantErrs:   1,
+		},
+		{
+			name:       "duplicate attribute name",
+			annotation: "zone=topology.kubernetes.io/zone,zone=topology.kubernetes.io/region",
+			wantErrs:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{esv1.NodeAttributesAnnotation: tt.annotation},
+				},
+			}
+			assert.Len(t, validNodeAttributes(es), tt.wantErrs)
+		})
+	}
+}
+
 func Test_validAssociations(t *testing.T) {
 	type args struct {
 		name         string
@@ -6,6 +6,7 @@ package validation
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/autoscaling"
 	volumevalidations "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume/validations"
@@ -43,6 +44,61 @@ func validPVCNaming(proposed esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+func validVolumeClaimScaleDownRetention(proposed esv1.Elasticsearch) field.ErrorList {
+	retention := proposed.Spec.VolumeClaimScaleDownRetention
+	if retention != nil && retention.Duration < 0 {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec").Child("volumeClaimScaleDownRetention"), retention.Duration.String(), negativeVolumeClaimRetentionMsg,
+		)}
+	}
+	return nil
+}
+
+func validEphemeralDataVolumes(proposed esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, ns := range proposed.Spec.NodeSets {
+		if !ns.HasEphemeralDataVolume() {
+			continue
+		}
+		path := field.NewPath("spec").Child("nodeSets").Index(i).Child("ephemeralDataVolume")
+		if !ns.EphemeralDataVolume.AcknowledgeDataLoss {
+			errs = append(errs, field.Invalid(path.Child("acknowledgeDataLoss"), false, ephemeralDataLossNotAcknowledgedMsg))
+		}
+		if sizeLimit := ns.EphemeralDataVolume.SizeLimit; sizeLimit != nil && sizeLimit.Sign() < 0 {
+			errs = append(errs, field.Invalid(path.Child("sizeLimit"), sizeLimit.String(), negativeEphemeralDataVolumeSizeMsg))
+		}
+		if hasDefaultClaim(ns.VolumeClaimTemplates) || hasDataVolume(ns.PodTemplate.Spec.Volumes) {
+			errs = append(errs, field.Forbidden(path, ephemeralDataVolumeConflictMsg))
+		}
+	}
+	return errs
+}
+
+// noEphemeralDataVolumeChange prevents switching the data volume of an existing NodeSet between a PersistentVolumeClaim
+// and an emptyDir volume, as the volume claim templates of the StatefulSet cannot be updated.
+func noEphemeralDataVolumeChange(current, proposed esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, proposedNodeSet := range proposed.Spec.NodeSets {
+		currentNodeSet := getNodeSet(proposedNodeSet.Name, current)
+		if currentNodeSet == nil || currentNodeSet.HasEphemeralDataVolume() == proposedNodeSet.HasEphemeralDataVolume() {
+			continue
+		}
+		errs = append(errs, field.Forbidden(
+			field.NewPath("spec").Child("nodeSets").Index(i).Child("ephemeralDataVolume"), ephemeralDataVolumeChangeMsg,
+		))
+	}
+	return errs
+}
+
+func hasDataVolume(volumes []corev1.Volume) bool {
+	for _, v := range volumes {
+		if v.Name == volume.ElasticsearchDataVolumeName {
+			return true
+		}
+	}
+	return false
+}
+
 func unmountedClaims(ns esv1.NodeSet) []corev1.PersistentVolumeClaim {
 	templates := ns.VolumeClaimTemplates
 	for _, c := range ns.PodTemplate.Spec.Containers {
@@ -68,7 +124,8 @@ func hasDefaultClaim(templates []corev1.PersistentVolumeClaim) bool {
 
 // validPVCModification ensures the only part of volume claim templates that can be changed is storage requests.
 // Storage increase is allowed as long as the storage class supports volume expansion.
-// Storage decrease is not supported if the corresponding StatefulSet has been resized already.
+// Storage decrease is not supported if the corresponding StatefulSet has been resized already, or compared to the current
+// Elasticsearch if the StatefulSet does not exist yet.
 func validPVCModification(ctx context.Context, current esv1.Elasticsearch, proposed esv1.Elasticsearch, k8sClient k8s.Client, validateStorageClass bool) field.ErrorList {
 	log := ulog.FromContext(ctx)
 	var errs field.ErrorList
@@ -125,7 +182,9 @@ func validPVCModification(ctx context.Context, current esv1.Elasticsearch, propo
 		var matchingSset appsv1.StatefulSet
 		err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: proposed.Namespace, Name: matchingSsetName}, &matchingSset)
 		if err != nil && apierrors.IsNotFound(err) {
-			// matching StatefulSet does not exist, this is likely the initial creation
+			// matching StatefulSet does not exist, there is no volume to expand yet but the storage still cannot be
+			// decreased compared to the current Elasticsearch
+			errs = append(errs, storageDecreaseErrors(i, proposedNodeSet, currentNodeSet.VolumeClaimTemplates)...)
 			continue
 		} else if err != nil {
 			// k8s client error - unlikely to happen since we used a cached client, but if it does happen
@@ -135,6 +194,10 @@ func validPVCModification(ctx context.Context, current esv1.Elasticsearch, propo
 			continue
 		}
 
+		if decreaseErrs := storageDecreaseErrors(i, proposedNodeSet, matchingSset.Spec.VolumeClaimTemplates); len(decreaseErrs) > 0 {
+			errs = append(errs, decreaseErrs...)
+			continue
+		}
 		if err := volumevalidations.ValidateClaimsStorageUpdate(ctx, k8sClient, matchingSset.Spec.VolumeClaimTemplates, proposedNodeSet.VolumeClaimTemplates, validateStorageClass); err != nil {
 			errs = append(errs, field.Invalid(
 				field.NewPath("spec").Child("nodeSet").Index(i).Child("volumeClaimTemplates"),
@@ -146,6 +209,25 @@ func validPVCModification(ctx context.Context, current esv1.Elasticsearch, propo
 	return errs
 }
 
+// storageDecreaseErrors returns an error for each claim of the given node set requesting less storage than the matching
+// reference claim.
+func storageDecreaseErrors(index int, nodeSet esv1.NodeSet, referenceClaims []corev1.PersistentVolumeClaim) field.ErrorList {
+	var errs field.ErrorList
+	for j, claim := range nodeSet.VolumeClaimTemplates {
+		for _, referenceClaim := range referenceClaims {
+			if referenceClaim.Name != claim.Name || !k8s.CompareStorageRequests(referenceClaim.Spec.Resources, claim.Spec.Resources).Decrease {
+				continue
+			}
+			errs = append(errs, field.Invalid(
+				field.NewPath("spec").Child("nodeSet").Index(index).Child("volumeClaimTemplates").Index(j).Child("spec", "resources", "requests", "storage"),
+				claim.Spec.Resources.Requests.Storage().String(),
+				fmt.Sprintf(storageDecreaseErrMsg, claim.Name, nodeSet.Name, referenceClaim.Spec.Resources.Requests.Storage().String()),
+			))
+		}
+	}
+	return errs
+}
+
 func getNodeSet(name string, es esv1.Elasticsearch) *esv1.NodeSet {
 	for i := range es.Spec.NodeSets {
 		if es.Spec.NodeSets[i].Name == name {
@@ -7,6 +7,7 @@ package validation
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
@@ -60,9 +61,11 @@ func Test_validPVCModification(t *testing.T) {
 		validateStorageClass bool
 	}
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name         string
+		args         args
+		wantErr      bool
+		wantErrField string
+		wantErrMsg   string
 	}{
 		{
 			name: "no changes in the claims: ok",
@@ -189,12 +192,70 @@ func Test_validPVCModification(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "storage increase in the proposed elasticsearch with a storage class supporting volume expansion: ok",
+			args: args{
+				current: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim, sampleClaim2}},
+				}),
+				proposed: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim, withStorageReq(sampleClaim2, "2Gi")}}, // increase
+				}),
+				k8sClient: k8s.NewFakeClient(
+					&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: sampleStorageClass.Name}, AllowVolumeExpansion: ptr.To[bool](true)},
+					&appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster-es-set1"},
+						Spec: appsv1.StatefulSetSpec{VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+							sampleClaim, sampleClaim2,
+						}},
+					}),
+				validateStorageClass: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "storage increase in the proposed elasticsearch, statefulSet does not exist: ok",
+			args: args{
+				current: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim, sampleClaim2}},
+				}),
+				proposed: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim, withStorageReq(sampleClaim2, "2Gi")}}, // increase
+				}),
+				k8sClient:            k8s.NewFakeClient(),
+				validateStorageClass: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "storage decrease in the proposed elasticsearch, statefulSet does not exist: error",
+			args: args{
+				current: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+					{Name: "set2", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim, sampleClaim2}},
+				}),
+				proposed: es([]esv1.NodeSet{
+					{Name: "set1", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim}},
+					{Name: "set2", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{sampleClaim, withStorageReq(sampleClaim2, "0.5Gi")}}, // decrease
+				}),
+				k8sClient:            k8s.NewFakeClient(),
+				validateStorageClass: true,
+			},
+			wantErr:      true,
+			wantErrField: "spec.nodeSet[1].volumeClaimTemplates[1].spec.resources.requests.storage",
+			wantErrMsg:   "Decreasing the storage request of claim sample-claim-2 in nodeSet set2 below 1Gi is not supported",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			errs := validPVCModification(context.Background(), tt.args.current, tt.args.proposed, tt.args.k8sClient, tt.args.validateStorageClass)
 			if tt.wantErr {
 				require.NotEmpty(t, errs)
+				if tt.wantErrMsg != "" {
+					require.Len(t, errs, 1)
+					require.Equal(t, tt.wantErrField, errs[0].Field)
+					require.Contains(t, errs[0].Detail, tt.wantErrMsg)
+				}
 			} else {
 				require.Empty(t, errs)
 			}
@@ -298,3 +359,170 @@ func Test_validPVCNaming(t *testing.T) {
 		})
 	}
 }
+
+func Test_validVolumeClaimScaleDownRetention(t *testing.T) {
+	tests := []struct {
+		name         string
+		retention    *metav1.Duration
+		expectErrors bool
+	}{
+		{
+			name:         "not set",
+			expectErrors: false,
+		},
+		{
+			name:         "zero",
+			retention:    &metav1.Duration{},
+			expectErrors: false,
+		},
+		{
+			name:         "positive",
+			retention:    &metav1.Duration{Duration: 24 * time.Hour},
+			expectErrors: false,
+		},
+		{
+			name:         "negative",
+			retention:    &metav1.Duration{Duration: -time.Hour},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{VolumeClaimScaleDownRetention: tt.retention}}
+			actual := validVolumeClaimScaleDownRetention(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validVolumeClaimScaleDownRetention(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.retention)
+			}
+		})
+	}
+}
+
+func Test_validEphemeralDataVolumes(t *testing.T) {
+	acknowledged := &esv1.EphemeralDataVolume{AcknowledgeDataLoss: true}
+	dataClaim := corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"}}
+	tests := []struct {
+		name         string
+		nodeSet      esv1.NodeSet
+		expectErrors bool
+	}{
+		{
+			name:         "persistent data volume",
+			nodeSet:      esv1.NodeSet{Name: "hot", VolumeClaimTemplates: []corev1.PersistentVolumeClaim{dataClaim}},
+			expectErrors: false,
+		},
+		{
+			name:         "ephemeral data volume",
+			nodeSet:      esv1.NodeSet{Name: "search", EphemeralDataVolume: acknowledged},
+			expectErrors: false,
+		},
+		{
+			name: "ephemeral data volume with a size limit",
+			nodeSet: esv1.NodeSet{Name: "search", EphemeralDataVolume: &esv1.EphemeralDataVolume{
+				SizeLimit: resource.NewQuantity(10<<30, resource.BinarySI), AcknowledgeDataLoss: true,
+			}},
+			expectErrors: false,
+		},
+		{
+			name: "ephemeral data volume with other claims",
+			nodeSet: esv1.NodeSet{Name: "search", EphemeralDataVolume: acknowledged, VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{ObjectMeta: metav1.ObjectMeta{Name: "snapshots"}},
+			}},
+			expectErrors: false,
+		},
+		{
+			name:         "data loss not acknowledged",
+			nodeSet:      esv1.NodeSet{Name: "search", EphemeralDataVolume: &esv1.EphemeralDataVolume{}},
+			expectErrors: true,
+		},
+		{
+			name: "negative size limit",
+			nodeSet: esv1.NodeSet{Name: "search", EphemeralDataVolume: &esv1.EphemeralDataVolume{
+				SizeLimit: resource.NewQuantity(-1, resource.BinarySI), AcknowledgeDataLoss: true,
+			}},
+			expectErrors: true,
+		},
+		{
+			name:         "conflicting data volume claim template",
+			nodeSet:      esv1.NodeSet{Name: "search", EphemeralDataVolume: acknowledged, VolumeClaimTemplates: []corev1.PersistentVolumeClaim{dataClaim}},
+			expectErrors: true,
+		},
+		{
+			name: "conflicting Pod template data volume",
+			nodeSet: esv1.NodeSet{Name: "search", EphemeralDataVolume: acknowledged, PodTemplate: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+					Name:         "elasticsearch-data",
+					VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				}}},
+			}},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{tt.nodeSet}}}
+			actual := validEphemeralDataVolumes(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validEphemeralDataVolumes(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
+			}
+		})
+	}
+}
+
+func Test_noEphemeralDataVolumeChange(t *testing.T) {
+	es := func(nodeSets ...esv1.NodeSet) esv1.Elasticsearch {
+		return esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: nodeSets}}
+	}
+	persistent := esv1.NodeSet{Name: "search"}
+	ephemeral := esv1.NodeSet{Name: "search", EphemeralDataVolume: &esv1.EphemeralDataVolume{AcknowledgeDataLoss: true}}
+	resized := esv1.NodeSet{Name: "search", EphemeralDataVolume: &esv1.EphemeralDataVolume{
+		SizeLimit: resource.NewQuantity(10<<30, resource.BinarySI), AcknowledgeDataLoss: true,
+	}}
+	tests := []struct {
+		name         string
+		current      esv1.Elasticsearch
+		proposed     esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name:         "ephemeral data volume unchanged",
+			current:      es(ephemeral),
+			proposed:     es(ephemeral),
+			expectErrors: false,
+		},
+		{
+			name:         "size limit changed",
+			current:      es(ephemeral),
+			proposed:     es(resized),
+			expectErrors: false,
+		},
+		{
+			name:         "new nodeSet with an ephemeral data volume",
+			current:      es(esv1.NodeSet{Name: "hot"}),
+			proposed:     es(esv1.NodeSet{Name: "hot"}, ephemeral),
+			expectErrors: false,
+		},
+		{
+			name:         "ephemeral data volume enabled on an existing nodeSet",
+			current:      es(persistent),
+			proposed:     es(ephemeral),
+			expectErrors: true,
+		},
+		{
+			name:         "ephemeral data volume disabled on an existing nodeSet",
+			current:      es(ephemeral),
+			proposed:     es(persistent),
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := noEphemeralDataVolumeChange(tt.current, tt.proposed)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed noEphemeralDataVolumeChange(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
+			}
+		})
+	}
+}
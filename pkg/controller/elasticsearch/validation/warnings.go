@@ -9,10 +9,23 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	essettings "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 var warnings = []validation{
 	noUnsupportedSettings,
+	noOverriddenJVMOptions,
+	noUnknownAnonymousRoles,
+}
+
+// builtinRoles are the roles reserved by Elasticsearch, which do not need to be declared.
+var builtinRoles = []string{
+	"apm_system", "apm_user", "beats_admin", "beats_system", "editor", "enrich_user", "ingest_admin", "kibana_admin",
+	"kibana_system", "kibana_user", "logstash_admin", "logstash_system", "machine_learning_admin",
+	"machine_learning_user", "monitoring_user", "remote_monitoring_agent", "remote_monitoring_collector",
+	"reporting_user", "rollup_admin", "rollup_user", "snapshot_user", "superuser", "transform_admin", "transform_user",
+	"transport_client", "viewer", "watcher_admin", "watcher_user",
 }
 
 func noUnsupportedSettings(es esv1.Elasticsearch) field.ErrorList {
@@ -56,6 +69,31 @@ func validateClientAuthentication(config *common.CanonicalConfig, index int) fie
 	return errs
 }
 
+// noOverriddenJVMOptions warns about JVM options also set in the ES_JAVA_OPTS environment variable of the
+// Elasticsearch container, which takes precedence over them.
+func noOverriddenJVMOptions(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, nodeSet := range es.Spec.NodeSets {
+		if len(nodeSet.JVMOptions) == 0 {
+			continue
+		}
+		for _, c := range nodeSet.PodTemplate.Spec.Containers {
+			if c.Name != esv1.ElasticsearchContainerName {
+				continue
+			}
+			for _, envVar := range c.Env {
+				if envVar.Name != essettings.EnvEsJavaOpts {
+					continue
+				}
+				for _, option := range essettings.OverriddenJVMOptions(nodeSet.JVMOptions, envVar.Value) {
+					errs = append(errs, field.Invalid(field.NewPath("spec").Child("nodeSets").Index(i).Child("jvmOptions"), option, overriddenJVMOptionMsg))
+				}
+			}
+		}
+	}
+	return errs
+}
+
 func CheckForWarnings(es esv1.Elasticsearch) error {
 	warnings := check(es, warnings)
 	if len(warnings) > 0 {
@@ -63,3 +101,23 @@ func CheckForWarnings(es esv1.Elasticsearch) error {
 	}
 	return nil
 }
+
+// noUnknownAnonymousRoles warns about roles granted to the anonymous user which are neither built-in nor declared in
+// the specification. They may still be defined in a user-provided roles file or through the Elasticsearch API, which
+// cannot be checked here.
+func noUnknownAnonymousRoles(es esv1.Elasticsearch) field.ErrorList {
+	if es.Spec.Auth.Anonymous == nil {
+		return nil
+	}
+	known := set.Make(builtinRoles...)
+	for _, role := range es.Spec.SecurityRoles {
+		known.Add(role.Name)
+	}
+	var errs field.ErrorList
+	for i, role := range es.Spec.Auth.Anonymous.Roles {
+		if !known.Has(role) {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("auth", "anonymous", "roles").Index(i), role, unknownAnonymousRoleMsg))
+		}
+	}
+	return errs
+}
@@ -7,6 +7,8 @@ package validation
 import (
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
+
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 )
@@ -171,3 +173,105 @@ func Test_noUnsupportedSettings(t *testing.T) {
 		})
 	}
 }
+
+func Test_noOverriddenJVMOptions(t *testing.T) {
+	esWithJVMOptions := func(jvmOptions []string, esJavaOpts string) esv1.Elasticsearch {
+		nodeSet := esv1.NodeSet{Name: "default", JVMOptions: jvmOptions}
+		if esJavaOpts != "" {
+			nodeSet.PodTemplate.Spec.Containers = []corev1.Container{{
+				Name: esv1.ElasticsearchContainerName,
+				Env:  []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: esJavaOpts}},
+			}}
+		}
+		return esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.12.0", NodeSets: []esv1.NodeSet{nodeSet}}}
+	}
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name:         "no JVM options OK",
+			es:           esWithJVMOptions(nil, "-Xms1g -Xmx1g"),
+			expectErrors: false,
+		},
+		{
+			name:         "no ES_JAVA_OPTS OK",
+			es:           esWithJVMOptions([]string{"-Xms2g", "-Xmx2g"}, ""),
+			expectErrors: false,
+		},
+		{
+			name:         "distinct options OK",
+			es:           esWithJVMOptions([]string{"-Dfoo=bar", "-XX:+UseG1GC"}, "-Dbar=baz -XX:MaxGCPauseMillis=200"),
+			expectErrors: false,
+		},
+		{
+			name:         "heap size overridden by ES_JAVA_OPTS",
+			es:           esWithJVMOptions([]string{"-Xmx2g"}, "-Xms1g -Xmx1g"),
+			expectErrors: true,
+		},
+		{
+			name:         "system property overridden by ES_JAVA_OPTS",
+			es:           esWithJVMOptions([]string{"-Dfoo=bar"}, "-Dfoo=baz"),
+			expectErrors: true,
+		},
+		{
+			name:         "JVM flag overridden by ES_JAVA_OPTS",
+			es:           esWithJVMOptions([]string{"-XX:+UseG1GC"}, "-XX:-UseG1GC"),
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := noOverriddenJVMOptions(tt.es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed noOverriddenJVMOptions(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
+			}
+		})
+	}
+}
+
+func Test_noUnknownAnonymousRoles(t *testing.T) {
+	esWithAnonymous := func(anonymous *esv1.AnonymousAccess, roles ...esv1.SecurityRole) esv1.Elasticsearch {
+		return esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{
+			Version:       "8.12.0",
+			Auth:          esv1.Auth{Anonymous: anonymous},
+			SecurityRoles: roles,
+		}}
+	}
+	tests := []struct {
+		name       string
+		es         esv1.Elasticsearch
+		wantErrors int
+	}{
+		{
+			name:       "no anonymous access OK",
+			es:         esWithAnonymous(nil),
+			wantErrors: 0,
+		},
+		{
+			name:       "built-in role OK",
+			es:         esWithAnonymous(&esv1.AnonymousAccess{Roles: []string{"viewer"}}),
+			wantErrors: 0,
+		},
+		{
+			name:       "role declared in the spec OK",
+			es:         esWithAnonymous(&esv1.AnonymousAccess{Roles: []string{"dashboards-reader"}}, esv1.SecurityRole{Name: "dashboards-reader"}),
+			wantErrors: 0,
+		},
+		{
+			name:       "unknown roles",
+			es:         esWithAnonymous(&esv1.AnonymousAccess{Roles: []string{"viewer", "dashboards-reader", "other"}}),
+			wantErrors: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := noUnknownAnonymousRoles(tt.es)
+			if len(actual) != tt.wantErrors {
+				t.Errorf("failed noUnknownAnonymousRoles(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package version
+
+import (
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
+)
+
+var (
+	// nodeRolesMinVersion is the first version of Elasticsearch supporting the node.roles setting.
+	nodeRolesMinVersion = version.From(7, 9, 0)
+	// transformRoleMinVersion is the first version of Elasticsearch supporting the transform and
+	// remote_cluster_client roles.
+	transformRoleMinVersion = version.From(7, 7, 0)
+)
+
+// HasCoordinatingOnlyRole returns true if the coordinating only shortcut is used in node.roles.
+func HasCoordinatingOnlyRole(node *esv1.Node) bool {
+	return node != nil && stringsutil.StringInSlice(string(esv1.CoordinatingOnlyRole), node.Roles)
+}
+
+// ExpandCoordinatingOnlyRole replaces the coordinating only shortcut in the node.roles setting of the given configuration
+// with the settings disabling all the roles for the given version of Elasticsearch. The configuration is left untouched
+// if the shortcut is not used.
+func ExpandCoordinatingOnlyRole(cfg *common.CanonicalConfig, ver version.Version) error {
+	if cfg == nil {
+		return nil
+	}
+	var settings esv1.ElasticsearchSettings
+	if err := cfg.Unpack(&settings); err != nil {
+		return err
+	}
+	if !HasCoordinatingOnlyRole(settings.Node) {
+		return nil
+	}
+	if _, err := cfg.Remove(esv1.NodeRoles); err != nil {
+		return err
+	}
+	return cfg.MergeWith(common.MustCanonicalConfig(CoordinatingOnlySettings(ver)))
+}
+
+// CoordinatingOnlySettings returns the settings to configure a coordinating only node for the given version of Elasticsearch.
+func CoordinatingOnlySettings(ver version.Version) map[string]interface{} {
+	if ver.GTE(nodeRolesMinVersion) {
+		return map[string]interface{}{
+			esv1.NodeRoles: []string{},
+		}
+	}
+	cfg := map[string]interface{}{
+		esv1.NodeMaster: false,
+		esv1.NodeData:   false,
+		esv1.NodeIngest: false,
+		esv1.NodeML:     false,
+	}
+	if ver.GTE(transformRoleMinVersion) {
+		cfg[esv1.NodeTransform] = false
+		cfg[esv1.NodeRemoteClusterClient] = false
+	}
+	return cfg
+}
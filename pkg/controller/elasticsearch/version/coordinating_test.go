@@ -0,0 +1,104 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestExpandCoordinatingOnlyRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		cfg     map[string]interface{}
+		want    map[string]interface{}
+	}{
+		{
+			name:    "no shortcut: config is left untouched",
+			version: "7.9.0",
+			cfg:     map[string]interface{}{"node.roles": []string{"master"}, "foo": "bar"},
+			want:    map[string]interface{}{"node.roles": []string{"master"}, "foo": "bar"},
+		},
+		{
+			name:    "no node settings: config is left untouched",
+			version: "7.9.0",
+			cfg:     map[string]interface{}{"foo": "bar"},
+			want:    map[string]interface{}{"foo": "bar"},
+		},
+		{
+			name:    "6.8.0: legacy role settings without transform and remote_cluster_client",
+			version: "6.8.0",
+			cfg:     map[string]interface{}{"node.roles": []string{"coordinating"}, "foo": "bar"},
+			want: map[string]interface{}{
+				"node.master": false,
+				"node.data":   false,
+				"node.ingest": false,
+				"node.ml":     false,
+				"foo":         "bar",
+			},
+		},
+		{
+			name:    "7.6.2: legacy role settings without transform and remote_cluster_client",
+			version: "7.6.2",
+			cfg:     map[string]interface{}{"node.roles": []string{"coordinating"}},
+			want: map[string]interface{}{
+				"node.master": false,
+				"node.data":   false,
+				"node.ingest": false,
+				"node.ml":     false,
+			},
+		},
+		{
+			name:    "7.7.0: legacy role settings including transform and remote_cluster_client",
+			version: "7.7.0",
+			cfg:     map[string]interface{}{"node.roles": []string{"coordinating"}},
+			want: map[string]interface{}{
+				"node.master":                false,
+				"node.data":                  false,
+				"node.ingest":                false,
+				"node.ml":                    false,
+				"node.transform":             false,
+				"node.remote_cluster_client": false,
+			},
+		},
+		{
+			name:    "7.8.1: legacy role settings including transform and remote_cluster_client",
+			version: "7.8.1",
+			cfg:     map[string]interface{}{"node": map[string]interface{}{"roles": []string{"coordinating"}}},
+			want: map[string]interface{}{
+				"node.master":                false,
+				"node.data":                  false,
+				"node.ingest":                false,
+				"node.ml":                    false,
+				"node.transform":             false,
+				"node.remote_cluster_client": false,
+			},
+		},
+		{
+			name:    "7.9.0: empty node.roles",
+			version: "7.9.0",
+			cfg:     map[string]interface{}{"node.roles": []string{"coordinating"}, "foo": "bar"},
+			want:    map[string]interface{}{"node.roles": []string{}, "foo": "bar"},
+		},
+		{
+			name:    "8.x: empty node.roles",
+			version: "8.11.0",
+			cfg:     map[string]interface{}{"node.roles": []string{"coordinating"}},
+			want:    map[string]interface{}{"node.roles": []string{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := common.MustCanonicalConfig(tt.cfg)
+			require.NoError(t, ExpandCoordinatingOnlyRole(cfg, version.MustParse(tt.version)))
+			require.Empty(t, cfg.Diff(common.MustCanonicalConfig(tt.want), nil))
+		})
+	}
+}
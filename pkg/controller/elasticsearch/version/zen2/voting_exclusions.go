@@ -6,12 +6,26 @@ package zen2
 
 import (
 	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/annotation"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
+)
+
+const (
+	// VotingConfigExclusionsGracePeriodAnnotation is the name of the annotation used to set for how long the expected
+	// master nodes must have been stable in the cluster before voting config exclusions are cleared.
+	VotingConfigExclusionsGracePeriodAnnotation = "eck.k8s.elastic.co/voting-config-exclusions-grace-period"
+	// DefaultVotingConfigExclusionsGracePeriod is the default grace period: exclusions are cleared as soon as
+	// the expected nodes are in the cluster.
+	DefaultVotingConfigExclusionsGracePeriod = 0 * time.Second
 )
 
 // AddToVotingConfigExclusions adds the given node names to exclude from voting config exclusions.
@@ -65,6 +79,72 @@ func ClearVotingConfigExclusions(ctx context.Context, es esv1.Elasticsearch, c k
 		return true, nil // requeue
 	}
 
+	gracePeriod := annotation.ExtractTimeout(ctx, es.ObjectMeta, VotingConfigExclusionsGracePeriodAnnotation, DefaultVotingConfigExclusionsGracePeriod)
+	if gracePeriod > 0 {
+		stable, err := mastersStableFor(ctx, c, esClient, es, actualStatefulSets, gracePeriod, time.Now())
+		if err != nil {
+			return false, err
+		}
+		if !stable {
+			log.V(1).Info("Master nodes not stable for the voting exclusions grace period yet",
+				"namespace", es.Namespace, "es_name", es.Name, "grace_period", gracePeriod)
+			return true, nil // requeue
+		}
+	}
+
 	log.Info("Ensuring no voting exclusions are set", "namespace", es.Namespace, "es_name", es.Name)
 	return false, esClient.DeleteVotingConfigExclusions(ctx, false)
 }
+
+// mastersStableFor returns true if the cluster reports the expected number of master nodes and if all the master Pods
+// have been ready for at least the given duration.
+func mastersStableFor(
+	ctx context.Context,
+	c k8s.Client,
+	esClient client.Client,
+	es esv1.Elasticsearch,
+	actualStatefulSets sset.StatefulSetList,
+	duration time.Duration,
+	now time.Time,
+) (bool, error) {
+	expectedMasters := int(actualStatefulSets.ExpectedMasterNodesCount())
+
+	nodes, err := esClient.GetNodes(ctx)
+	if err != nil {
+		return false, err
+	}
+	masters := 0
+	for _, node := range nodes.Nodes {
+		if stringsutil.StringInSlice(string(esv1.MasterRole), node.Roles) {
+			masters++
+		}
+	}
+	if masters != expectedMasters {
+		return false, nil
+	}
+
+	masterPods, err := sset.GetActualMastersForCluster(c, es)
+	if err != nil {
+		return false, err
+	}
+	if len(masterPods) != expectedMasters {
+		return false, nil
+	}
+	for _, pod := range masterPods {
+		readySince, ready := podReadySince(pod)
+		if !ready || now.Sub(readySince) < duration {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// podReadySince returns the time at which the given Pod became ready, and false if it is not ready.
+func podReadySince(pod corev1.Pod) (time.Time, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.LastTransitionTime.Time, condition.Status == corev1.ConditionTrue
+		}
+	}
+	return time.Time{}, false
+}
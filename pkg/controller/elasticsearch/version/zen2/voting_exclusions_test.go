@@ -6,11 +6,14 @@ package zen2
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
@@ -22,9 +25,14 @@ import (
 type fakeVotingConfigExclusionsESClient struct {
 	called        bool
 	excludedNodes []string
+	nodes         client.Nodes
 	client.Client
 }
 
+func (f *fakeVotingConfigExclusionsESClient) GetNodes(_ context.Context) (client.Nodes, error) {
+	return f.nodes, nil
+}
+
 func (f *fakeVotingConfigExclusionsESClient) DeleteVotingConfigExclusions(_ context.Context, _ bool) error {
 	f.called = true
 	return nil
@@ -110,6 +118,86 @@ func Test_ClearVotingConfigExclusions(t *testing.T) {
 	}
 }
 
+func Test_ClearVotingConfigExclusionsGracePeriod(t *testing.T) {
+	statefulSet := sset.TestSset{Name: "nodes", Version: "7.2.0", Replicas: 3, Master: true, Data: true}.Build()
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{
+		Name:        "es",
+		Namespace:   statefulSet.Namespace,
+		Annotations: map[string]string{VotingConfigExclusionsGracePeriodAnnotation: "5m"},
+	}}
+	// build ready master Pods, the last one becoming ready at the given time
+	podsReadySince := func(lastReadyTime time.Time) []crclient.Object {
+		podNames := sset.PodNames(statefulSet)
+		pods := make([]crclient.Object, 0, len(podNames))
+		for i, podName := range podNames {
+			pod := sset.TestPod{
+				Namespace:       statefulSet.Namespace,
+				Name:            podName,
+				ClusterName:     es.Name,
+				Version:         "7.2.0",
+				Master:          true,
+				Ready:           true,
+				StatefulSetName: statefulSet.Name,
+			}.Build()
+			readyTime := time.Now().Add(-1 * time.Hour)
+			if i == len(podNames)-1 {
+				readyTime = lastReadyTime
+			}
+			for j := range pod.Status.Conditions {
+				pod.Status.Conditions[j].LastTransitionTime = metav1.NewTime(readyTime)
+			}
+			pods = append(pods, &pod)
+		}
+		return pods
+	}
+	esNodes := func(masters int) client.Nodes {
+		nodes := client.Nodes{Nodes: map[string]client.Node{}}
+		for i := 0; i < masters; i++ {
+			nodes.Nodes[fmt.Sprintf("node-%d", i)] = client.Node{Roles: []string{"master", "data"}}
+		}
+		return nodes
+	}
+	tests := []struct {
+		name        string
+		objects     []crclient.Object
+		esNodes     client.Nodes
+		wantCall    bool
+		wantRequeue bool
+	}{
+		{
+			name:        "masters stable for longer than the grace period: should clear",
+			objects:     podsReadySince(time.Now().Add(-10 * time.Minute)),
+			esNodes:     esNodes(3),
+			wantCall:    true,
+			wantRequeue: false,
+		},
+		{
+			name:        "a master became ready within the grace period: cannot clear, should requeue",
+			objects:     podsReadySince(time.Now().Add(-1 * time.Minute)),
+			esNodes:     esNodes(3),
+			wantCall:    false,
+			wantRequeue: true,
+		},
+		{
+			name:        "cluster does not report the expected master count yet: cannot clear, should requeue",
+			objects:     podsReadySince(time.Now().Add(-10 * time.Minute)),
+			esNodes:     esNodes(2),
+			wantCall:    false,
+			wantRequeue: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(append([]crclient.Object{&es, &statefulSet}, tt.objects...)...)
+			clientMock := &fakeVotingConfigExclusionsESClient{nodes: tt.esNodes}
+			requeue, err := ClearVotingConfigExclusions(context.Background(), es, c, clientMock, es_sset.StatefulSetList{statefulSet})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRequeue, requeue)
+			require.Equal(t, tt.wantCall, clientMock.called)
+		})
+	}
+}
+
 func TestAddToVotingConfigExclusions(t *testing.T) {
 	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "ns"}}
 	masterPod := sset.TestPod{
@@ -4,6 +4,8 @@
 
 package volume
 
+import "path"
+
 // Default values for the volume name and paths
 const (
 	PodMountedUsersSecretMountPath = "/mnt/elastic-internal/pod-mounted-users" //nolint:gosec
@@ -51,4 +53,31 @@ const (
 
 	TempVolumeName      = "tmp-volume"
 	TempVolumeMountPath = "/tmp"
+
+	TrustedCAVolumeName      = "elastic-internal-trusted-ca"
+	TrustedCAVolumeMountPath = "/mnt/elastic-internal/trusted-ca"
+
+	TruststoreVolumeName      = "elastic-internal-truststore"
+	TruststoreVolumeMountPath = "/mnt/elastic-internal/truststore"
+	TruststoreFile            = "truststore.p12"
 )
+
+const (
+	// RealmsVolumeMountPath is the directory where the secrets of the authentication realms are mounted.
+	RealmsVolumeMountPath = "/usr/share/elasticsearch/config/realms"
+
+	// secrets of a SAML realm
+	SAMLRealmMetadata   = "metadata"
+	SAMLRealmSigning    = "signing"
+	SAMLRealmEncryption = "encryption"
+)
+
+// SAMLRealmVolumeName returns the name of the volume holding the given secret of a SAML realm.
+func SAMLRealmVolumeName(realmName, secret string) string {
+	return "saml-" + realmName + "-" + secret
+}
+
+// SAMLRealmMountPath returns the directory where the given secret of a SAML realm is mounted.
+func SAMLRealmMountPath(realmName, secret string) string {
+	return path.Join(RealmsVolumeMountPath, "saml", realmName, secret)
+}
@@ -48,7 +48,7 @@ const (
 // The Manager will set fields on the Controller and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	reconciler := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, controllerName, reconciler, params)
+	c, err := common.NewController(mgr, controllerName, reconciler, &entv1.EnterpriseSearch{}, params)
 	if err != nil {
 		return err
 	}
@@ -167,7 +167,8 @@ func (r *ReconcileEnterpriseSearch) Reconcile(ctx context.Context, request recon
 		}
 		results.WithError(err)
 	}
-	return results.Aggregate()
+	result, err := results.Aggregate()
+	return common.WithResync(ctx, &ent, r.ResyncPeriod, result), err
 }
 
 func (r *ReconcileEnterpriseSearch) onDelete(ctx context.Context, obj types.NamespacedName) error {
@@ -195,7 +196,7 @@ func (r *ReconcileEnterpriseSearch) doReconcile(ctx context.Context, ent entv1.E
 		return results.WithError(err), status
 	}
 
-	svc, err := common.ReconcileService(ctx, r.Client, NewService(ent), &ent)
+	svc, err := common.ReconcileService(ctx, r.Client, NewService(ctx, ent), &ent)
 	if err != nil {
 		return results.WithError(err), status
 	}
@@ -314,7 +315,7 @@ func (r *ReconcileEnterpriseSearch) updateStatus(ctx context.Context, ent entv1.
 	return common.UpdateStatus(ctx, r.Client, &ent)
 }
 
-func NewService(ent entv1.EnterpriseSearch) *corev1.Service {
+func NewService(ctx context.Context, ent entv1.EnterpriseSearch) *corev1.Service {
 	svc := corev1.Service{
 		ObjectMeta: ent.Spec.HTTP.Service.ObjectMeta,
 		Spec:       ent.Spec.HTTP.Service.Spec,
@@ -332,7 +333,7 @@ func NewService(ent entv1.EnterpriseSearch) *corev1.Service {
 		},
 	}
 
-	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
+	return defaults.SetServiceDefaults(ctx, &svc, labels, labels, ports)
 }
 
 func buildConfigHash(c k8s.Client, ent entv1.EnterpriseSearch, configSecret corev1.Secret) (string, error) {
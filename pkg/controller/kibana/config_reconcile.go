@@ -7,6 +7,7 @@ package kibana
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"go.elastic.co/apm/v2"
 	corev1 "k8s.io/api/core/v1"
@@ -18,6 +19,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	kblabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
@@ -34,8 +36,16 @@ const (
 	InternalConfigVolumeMountPath = "/mnt/elastic-internal/kibana-config"
 
 	TelemetryFilename = "telemetry.yml"
+
+	// ConfigPathEnvVar is the environment variable Kibana reads the path of its configuration directory from.
+	ConfigPathEnvVar = "KBN_PATH_CONF"
+	// LegacyConfigPathEnvVar is the environment variable Kibana reads the path of its configuration directory from before 7.0.
+	LegacyConfigPathEnvVar = "KIBANA_PATH_CONF"
 )
 
+// kbnPathConfVersion is the version from which the configuration directory is read from KBN_PATH_CONF.
+var kbnPathConfVersion = version.From(7, 0, 0)
+
 var (
 	// ConfigSharedVolume contains the Kibana config/ directory, it's an empty volume where the required configuration
 	// is initialized by the elastic-internal-init-config init container. Its content is then shared by the init container
@@ -49,6 +59,34 @@ var (
 	}
 )
 
+// configSharedVolume returns the ConfigSharedVolume, mounted in the Kibana container at the config mount path of the
+// given Kibana resource.
+func configSharedVolume(kb kbv1.Kibana) volume.SharedVolume {
+	v := ConfigSharedVolume
+	v.ContainerMountPath = configMountPath(kb)
+	return v
+}
+
+// configMountPath returns the directory of the Kibana container holding the Kibana configuration.
+func configMountPath(kb kbv1.Kibana) string {
+	if kb.Spec.ConfigMountPath == "" {
+		return ConfigVolumeMountPath
+	}
+	return kb.Spec.ConfigMountPath
+}
+
+// configPathEnvVars returns the environment variable pointing Kibana to its configuration directory, if not the default one.
+func configPathEnvVars(kb kbv1.Kibana, v version.Version) []corev1.EnvVar {
+	if kb.Spec.ConfigMountPath == "" || kb.Spec.ConfigMountPath == ConfigVolumeMountPath {
+		return nil
+	}
+	name := ConfigPathEnvVar
+	if v.LT(kbnPathConfVersion) {
+		name = LegacyConfigPathEnvVar
+	}
+	return []corev1.EnvVar{{Name: name, Value: kb.Spec.ConfigMountPath}}
+}
+
 // ConfigVolume returns a SecretVolume to hold the Kibana config of the given Kibana resource.
 func ConfigVolume(kb kbv1.Kibana) volume.SecretVolume {
 	return volume.NewSecretVolumeWithMountPath(
@@ -99,6 +137,9 @@ func ReconcileConfigSecret(
 			Labels: labels.AddCredentialsLabel(map[string]string{
 				kblabel.KibanaNameLabelName: kb.Name,
 			}),
+			Annotations: map[string]string{
+				managedSavedObjectsKeysAnnotationName: strings.Join(kbSettings.managedSavedObjectsKeys, ","),
+			},
 		},
 		Data: data,
 	}
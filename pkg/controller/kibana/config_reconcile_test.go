@@ -105,7 +105,7 @@ func TestReconcileConfigSecret(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			k8sClient := k8s.NewFakeClient(tt.args.initialObjects...)
 
-			err := ReconcileConfigSecret(context.Background(), k8sClient, tt.args.kb, CanonicalConfig{settings.NewCanonicalConfig()})
+			err := ReconcileConfigSecret(context.Background(), k8sClient, tt.args.kb, CanonicalConfig{CanonicalConfig: settings.NewCanonicalConfig()})
 			assert.NoError(t, err)
 
 			var secrets corev1.SecretList
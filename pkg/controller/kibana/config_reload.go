@@ -0,0 +1,100 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"fmt"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+)
+
+const (
+	ConfigReloaderContainerName = "config-reloader"
+
+	// configReloaderPeriodSeconds is the interval at which the config reloader checks the Kibana configuration file.
+	configReloaderPeriodSeconds = 10
+
+	// configReloaderScript signals Kibana whenever the checksum of its configuration file changes. The signal is sent,
+	// through the process namespace shared by the containers of the pod, to the init process of the Kibana container
+	// whose command line runs the Kibana Docker entrypoint, and which forwards it to Kibana. The pattern is written so
+	// that it does not match the command line of the script itself.
+	configReloaderScript = `#!/usr/bin/env bash
+config=%s
+last=$(sha256sum "${config}")
+while true; do
+  sleep %d
+  current=$(sha256sum "${config}")
+  if [[ "${current}" == "${last}" ]]; then
+    continue
+  fi
+  last="${current}"
+  for cmdline in /proc/[0-9]*/cmdline; do
+    if tr '\0' ' ' 2>/dev/null < "${cmdline}" | grep -q 'kibana[-]docker'; then
+      pid=${cmdline#/proc/}
+      echo "Kibana configuration changed, sending SIGHUP to process ${pid%%/cmdline}"
+      kill -HUP "${pid%%/cmdline}"
+    fi
+  done
+done
+`
+)
+
+var configReloaderResources = corev1.ResourceRequirements{
+	Requests: map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceMemory: resource.MustParse("20Mi"),
+		corev1.ResourceCPU:    resource.MustParse("0.01"),
+	},
+	Limits: map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceMemory: resource.MustParse("20Mi"),
+		corev1.ResourceCPU:    resource.MustParse("0.1"),
+	},
+}
+
+// reloadableSettings are the Kibana settings, including their children, that Kibana reloads from its configuration
+// file when it receives a SIGHUP signal. Any other setting requires Kibana to be restarted.
+var reloadableSettings = []string{
+	"logging",
+}
+
+// restartRequiredConfig returns the part of the given Kibana configuration whose changes require Kibana to be restarted.
+// Settings Kibana can reload at runtime are only excluded with the Reload config reload strategy.
+func restartRequiredConfig(kb kbv1.Kibana, cfg []byte) ([]byte, error) {
+	if kb.Spec.ConfigReloadStrategy != kbv1.ReloadConfigReloadStrategy {
+		return cfg, nil
+	}
+	parsed, err := settings.ParseConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range reloadableSettings {
+		if _, err := parsed.Remove(key); err != nil {
+			return nil, err
+		}
+	}
+	return parsed.Render()
+}
+
+// configReloaderContainer returns a sidecar container sending a SIGHUP signal to Kibana when its configuration file
+// changes, so that Kibana reloads the settings it can reload at runtime. It relies on the pod sharing its process
+// namespace, and runs the given image, which is expected to be the Kibana image.
+func configReloaderContainer(kb kbv1.Kibana, image string) corev1.Container {
+	privileged := false
+	configFile := filepath.Join(InternalConfigVolumeMountPath, SettingsFilename)
+	return corev1.Container{
+		Name:  ConfigReloaderContainerName,
+		Image: image,
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: &privileged,
+		},
+		Command:      []string{"/usr/bin/env", "bash", "-c", fmt.Sprintf(configReloaderScript, configFile, configReloaderPeriodSeconds)},
+		VolumeMounts: []corev1.VolumeMount{ConfigVolume(kb).VolumeMount()},
+		Resources:    configReloaderResources,
+	}
+}
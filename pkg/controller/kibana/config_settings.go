@@ -6,8 +6,11 @@ package kibana
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/elastic/go-ucfg"
 	"github.com/pkg/errors"
@@ -18,7 +21,6 @@ import (
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
@@ -29,11 +31,15 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 const (
 	// SettingsFilename is the Kibana configuration settings file
 	SettingsFilename = "kibana.yml"
+	// managedSavedObjectsKeysAnnotationName is the annotation of the config secret holding the comma-separated hashes
+	// of the saved objects keys managed by the operator, as opposed to the keys specified by the user.
+	managedSavedObjectsKeysAnnotationName = "kibana.k8s.elastic.co/managed-saved-objects-keys"
 	// EnvNodeOptions is the environment variable name for the Node options that can be used to increase the Kibana maximum memory limit
 	EnvNodeOptions = "NODE_OPTIONS"
 
@@ -47,6 +53,11 @@ const (
 const (
 	ServerName                                     = "server.name"
 	ServerHost                                     = "server.host"
+	ServerKeepaliveTimeout                         = "server.keepaliveTimeout"
+	ServerSocketTimeout                            = "server.socketTimeout"
+	ServerPublicBaseURL                            = "server.publicBaseUrl"                                // >= 7.10
+	ServerMaxPayload                               = "server.maxPayload"                                   // >= 7.13
+	ServerMaxPayloadBytes                          = "server.maxPayloadBytes"                              // < 7.13
 	XpackMonitoringUIContainerElasticsearchEnabled = "xpack.monitoring.ui.container.elasticsearch.enabled" // <= 7.15
 	MonitoringUIContainerElasticsearchEnabled      = "monitoring.ui.container.elasticsearch.enabled"       // >= 7.16
 	XpackLicenseManagementUIEnabled                = "xpack.license_management.ui.enabled"                 // >= 7.6
@@ -54,6 +65,7 @@ const (
 	XpackReportingEncryptionKey                    = "xpack.reporting.encryptionKey"
 	XpackEncryptedSavedObjects                     = "xpack.encryptedSavedObjects"
 	XpackEncryptedSavedObjectsEncryptionKey        = "xpack.encryptedSavedObjects.encryptionKey"
+	XpackEncryptedSavedObjectsDecryptionOnlyKeys   = "xpack.encryptedSavedObjects.keyRotation.decryptionOnlyKeys" // >= 7.11
 
 	ElasticsearchSslCertificateAuthorities = "elasticsearch.ssl.certificateAuthorities"
 	ElasticsearchSslVerificationMode       = "elasticsearch.ssl.verificationMode"
@@ -77,6 +89,8 @@ const (
 // as a hierarchical key-value configuration.
 type CanonicalConfig struct {
 	*settings.CanonicalConfig
+	// managedSavedObjectsKeys are the hashes of the saved objects keys managed by the operator.
+	managedSavedObjectsKeys []string
 }
 
 // NewConfigSettings returns the Kibana configuration settings for the given Kibana resource.
@@ -84,7 +98,7 @@ func NewConfigSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v
 	span, _ := apm.StartSpan(ctx, "new_config_settings", tracing.SpanTypeApp)
 	defer span.End()
 
-	reusableSettings, err := getOrCreateReusableSettings(ctx, client, kb)
+	reusableSettings, managedSavedObjectsKeys, err := getOrCreateReusableSettings(ctx, client, kb)
 	if err != nil {
 		return CanonicalConfig{}, err
 	}
@@ -112,19 +126,35 @@ func NewConfigSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v
 
 	cfg := settings.MustCanonicalConfig(baseSettingsMap)
 	kibanaTLSCfg := settings.MustCanonicalConfig(kibanaTLSSettings(kb))
+	publicBaseURLCfg := settings.MustCanonicalConfig(publicBaseURLSettings(kb, v))
+	serverCfg := settings.MustCanonicalConfig(serverSettings(kb, v))
 	versionSpecificCfg := VersionDefaults(&kb, v)
 	entSearchCfg := settings.MustCanonicalConfig(enterpriseSearchSettings(kb))
 	monitoringCfg, err := settings.NewCanonicalConfigFrom(stackmon.MonitoringConfig(kb).Data)
 	if err != nil {
 		return CanonicalConfig{}, err
 	}
+	reportingSettingsMap, err := reportingSettings(ctx, client, kb)
+	if err != nil {
+		return CanonicalConfig{}, err
+	}
+	reportingCfg := settings.MustCanonicalConfig(reportingSettingsMap)
+	samlProvidersSettingsMap, err := samlProvidersSettings(ctx, client, kb, v)
+	if err != nil {
+		return CanonicalConfig{}, err
+	}
+	samlProvidersCfg := settings.MustCanonicalConfig(samlProvidersSettingsMap)
 
 	err = cfg.MergeWith(
 		reusableSettings,
 		versionSpecificCfg,
 		kibanaTLSCfg,
+		publicBaseURLCfg,
+		serverCfg,
 		entSearchCfg,
-		monitoringCfg)
+		monitoringCfg,
+		reportingCfg,
+		samlProvidersCfg)
 	if err != nil {
 		return CanonicalConfig{}, err
 	}
@@ -135,7 +165,7 @@ func NewConfigSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v
 		return CanonicalConfig{}, err
 	}
 	if esAssocConf.IsConfigured() {
-		credentials, err := association.ElasticsearchAuthSettings(ctx, client, kb.EsAssociation())
+		credentials, err := elasticsearchCredentials(ctx, client, kb)
 		if err != nil {
 			return CanonicalConfig{}, err
 		}
@@ -162,7 +192,7 @@ func NewConfigSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v
 		return CanonicalConfig{}, err
 	}
 
-	return CanonicalConfig{cfg}, nil
+	return CanonicalConfig{CanonicalConfig: cfg, managedSavedObjectsKeys: managedSavedObjectsKeys}, nil
 }
 
 // Some previously-unsupported keys cause Kibana to error out even if the values are empty. ucfg cannot ignore fields easily so this is necessary to
@@ -195,6 +225,12 @@ type reusableSettings struct {
 	SavedObjectsKey string `config:"xpack.encryptedSavedObjects.encryptionKey"`
 }
 
+// savedObjectsKeys captures the keys used by Kibana to encrypt and decrypt saved objects.
+type savedObjectsKeys struct {
+	EncryptionKey      string   `config:"xpack.encryptedSavedObjects.encryptionKey"`
+	DecryptionOnlyKeys []string `config:"xpack.encryptedSavedObjects.keyRotation.decryptionOnlyKeys"`
+}
+
 // getExistingConfig retrieves the canonical config for a given Kibana, if one exists
 func getExistingConfig(ctx context.Context, client k8s.Client, kb kbv1.Kibana) (*settings.CanonicalConfig, error) {
 	log := ulog.FromContext(ctx)
@@ -222,18 +258,19 @@ func getExistingConfig(ctx context.Context, client k8s.Client, kb kbv1.Kibana) (
 }
 
 // getOrCreateReusableSettings filters an existing config for only items we want to preserve between spec changes
-// because they cannot be generated deterministically, e.g. encryption keys
-func getOrCreateReusableSettings(ctx context.Context, c k8s.Client, kb kbv1.Kibana) (*settings.CanonicalConfig, error) {
+// because they cannot be generated deterministically, e.g. encryption keys. It also returns the hashes of the saved
+// objects keys managed by the operator, to be tracked in the config secret.
+func getOrCreateReusableSettings(ctx context.Context, c k8s.Client, kb kbv1.Kibana) (*settings.CanonicalConfig, []string, error) {
 	cfg, err := getExistingConfig(ctx, c, kb)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var r reusableSettings
 	if cfg == nil {
 		r = reusableSettings{}
 	} else if err := cfg.Unpack(&r); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if len(r.EncryptionKey) == 0 {
 		r.EncryptionKey = string(common.RandomBytes(64))
@@ -244,13 +281,128 @@ func getOrCreateReusableSettings(ctx context.Context, c k8s.Client, kb kbv1.Kiba
 
 	kbVer, err := version.Parse(kb.Spec.Version)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	// xpack.encryptedSavedObjects.encryptionKey was only added in 7.6.0 and earlier versions error out
 	if len(r.SavedObjectsKey) == 0 && kbVer.GTE(version.From(7, 6, 0)) {
 		r.SavedObjectsKey = string(common.RandomBytes(64))
 	}
-	return settings.MustCanonicalConfig(r), nil
+	reusableCfg := settings.MustCanonicalConfig(r)
+
+	user, err := userSavedObjectsKeys(kb)
+	if err != nil {
+		return nil, nil, err
+	}
+	var managedKeys []string
+	if len(user.EncryptionKey) == 0 && len(r.SavedObjectsKey) > 0 {
+		// the saved objects encryption key is generated or reused by the operator
+		managedKeys = append(managedKeys, r.SavedObjectsKey)
+	}
+
+	// xpack.encryptedSavedObjects.keyRotation.decryptionOnlyKeys was only added in 7.11.0
+	if kbVer.LT(version.From(7, 11, 0)) {
+		return reusableCfg, hashKeys(managedKeys), nil
+	}
+	managed, err := getManagedSavedObjectsKeys(ctx, c, kb)
+	if err != nil {
+		return nil, nil, err
+	}
+	decryptionOnlyKeys, err := savedObjectsDecryptionOnlyKeys(cfg, managed, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(decryptionOnlyKeys) > 0 {
+		err = reusableCfg.MergeWith(settings.MustCanonicalConfig(map[string]interface{}{
+			XpackEncryptedSavedObjectsDecryptionOnlyKeys: decryptionOnlyKeys,
+		}))
+	}
+	return reusableCfg, hashKeys(append(managedKeys, decryptionOnlyKeys...)), err
+}
+
+// userSavedObjectsKeys returns the saved objects keys specified in the Kibana configuration of the given Kibana resource.
+func userSavedObjectsKeys(kb kbv1.Kibana) (savedObjectsKeys, error) {
+	var user savedObjectsKeys
+	if kb.Spec.Config == nil {
+		return user, nil
+	}
+	userCfg, err := settings.NewCanonicalConfigFrom(kb.Spec.Config.Data)
+	if err != nil {
+		return user, err
+	}
+	err = userCfg.Unpack(&user)
+	return user, err
+}
+
+// getManagedSavedObjectsKeys returns the hashes of the saved objects keys managed by the operator, as tracked in the
+// existing config secret. It returns nil if the config secret does not exist or does not track them yet.
+func getManagedSavedObjectsKeys(ctx context.Context, c k8s.Client, kb kbv1.Kibana) (set.StringSet, error) {
+	var secret corev1.Secret
+	err := c.Get(ctx, types.NamespacedName{Name: SecretName(kb), Namespace: kb.Namespace}, &secret)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	value, exists := secret.Annotations[managedSavedObjectsKeysAnnotationName]
+	if !exists {
+		return nil, nil
+	}
+	managed := set.Make()
+	for _, hash := range strings.Split(value, ",") {
+		if len(hash) > 0 {
+			managed.Add(hash)
+		}
+	}
+	return managed, nil
+}
+
+// hashKeys returns the SHA-256 hashes of the given keys, so that they can be tracked without being disclosed.
+func hashKeys(keys []string) []string {
+	hashes := make([]string, 0, len(keys))
+	for _, key := range keys {
+		hash := sha256.Sum256([]byte(key))
+		hashes = append(hashes, hex.EncodeToString(hash[:]))
+	}
+	return hashes
+}
+
+// savedObjectsDecryptionOnlyKeys returns the keys Kibana must keep to decrypt the existing saved objects when the saved
+// objects encryption key is replaced through the Kibana configuration. The previous encryption key is kept, whether it
+// was generated by the operator or specified by the user, as well as the decryption keys the operator already kept,
+// unless they are the new encryption key or are already specified by the user. Decryption keys specified by the user
+// are left to the user.
+func savedObjectsDecryptionOnlyKeys(existingCfg *settings.CanonicalConfig, managed set.StringSet, user savedObjectsKeys) ([]string, error) {
+	if existingCfg == nil {
+		return nil, nil
+	}
+	var existing savedObjectsKeys
+	if err := existingCfg.Unpack(&existing); err != nil {
+		return nil, err
+	}
+	encryptionKey := user.EncryptionKey
+	if len(encryptionKey) == 0 {
+		// the existing encryption key is reused
+		encryptionKey = existing.EncryptionKey
+	}
+	isManaged := func(key string) bool {
+		// saved objects may have been encrypted with the previous encryption key
+		if key == existing.EncryptionKey {
+			return true
+		}
+		return managed != nil && managed.Has(hashKeys([]string{key})[0])
+	}
+
+	excluded := set.Make(user.DecryptionOnlyKeys...)
+	excluded.Add(encryptionKey)
+	var keys []string
+	for _, key := range append([]string{existing.EncryptionKey}, existing.DecryptionOnlyKeys...) {
+		if len(key) == 0 || excluded.Has(key) || !isManaged(key) {
+			continue
+		}
+		excluded.Add(key)
+		keys = append(keys, key)
+	}
+	return keys, nil
 }
 
 func baseSettings(kb *kbv1.Kibana, ipFamily corev1.IPFamily) (map[string]interface{}, error) {
@@ -289,6 +441,39 @@ func kibanaTLSSettings(kb kbv1.Kibana) map[string]interface{} {
 	}
 }
 
+// publicBaseURLSettings returns the server.publicBaseUrl setting from the Kibana specification, for the Kibana versions
+// supporting it.
+func publicBaseURLSettings(kb kbv1.Kibana, v version.Version) map[string]interface{} {
+	if v.LT(version.From(7, 10, 0)) || kb.Spec.PublicBaseURL == "" {
+		return nil
+	}
+	return map[string]interface{}{
+		ServerPublicBaseURL: kb.Spec.PublicBaseURL,
+	}
+}
+
+// serverSettings returns the Kibana HTTP server tunables specified in the Kibana specification.
+func serverSettings(kb kbv1.Kibana, v version.Version) map[string]interface{} {
+	if kb.Spec.Server == nil {
+		return nil
+	}
+	cfg := map[string]interface{}{}
+	if kb.Spec.Server.MaxPayload != nil {
+		if v.GTE(version.From(7, 13, 0)) {
+			cfg[ServerMaxPayload] = *kb.Spec.Server.MaxPayload
+		} else {
+			cfg[ServerMaxPayloadBytes] = *kb.Spec.Server.MaxPayload
+		}
+	}
+	if kb.Spec.Server.KeepaliveTimeout != nil {
+		cfg[ServerKeepaliveTimeout] = *kb.Spec.Server.KeepaliveTimeout
+	}
+	if kb.Spec.Server.SocketTimeout != nil {
+		cfg[ServerSocketTimeout] = *kb.Spec.Server.SocketTimeout
+	}
+	return cfg
+}
+
 func elasticsearchTLSSettings(esAssocConf commonv1.AssociationConf) map[string]interface{} {
 	cfg := map[string]interface{}{
 		ElasticsearchSslVerificationMode: "certificate",
@@ -6,7 +6,9 @@ package kibana
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	ucfg "github.com/elastic/go-ucfg"
@@ -16,11 +18,14 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -172,7 +177,7 @@ func Test_reuseOrGenerateSecrets(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getOrCreateReusableSettings(context.Background(), tt.args.c, tt.args.kibana)
+			got, _, err := getOrCreateReusableSettings(context.Background(), tt.args.c, tt.args.kibana)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getOrCreateReusableSettings() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -369,6 +374,113 @@ func TestNewConfigSettings(t *testing.T) {
 			}(),
 			wantErr: false,
 		},
+		{
+			name: "with elasticsearch Association and custom credentials",
+			args: args{
+				kb: func() kbv1.Kibana {
+					kb := mkKibana()
+					kb.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "test-es"}
+					kb.Spec.ElasticsearchCredentialsSecretName = "custom-credentials"
+					kb.EsAssociation().SetAssociationConf(&commonv1.AssociationConf{
+						AuthSecretName: "auth-secret",
+						AuthSecretKey:  "elastic",
+						CASecretName:   "ca-secret",
+						CACertProvided: true,
+						URL:            "https://es-url:9200",
+					})
+					return kb
+				},
+				client: k8s.NewFakeClient(
+					existingSecret,
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "auth-secret",
+							Namespace: mkKibana().Namespace,
+						},
+						Data: map[string][]byte{
+							"elastic": []byte("password"),
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "custom-credentials",
+							Namespace: mkKibana().Namespace,
+						},
+						Data: map[string][]byte{
+							"username": []byte("federated-user"),
+							"password": []byte("federated-password"),
+						},
+					},
+				),
+				ipFamily: corev1.IPv4Protocol,
+			},
+			want: func() []byte {
+				cfg, err := settings.ParseConfig(defaultConfig)
+				require.NoError(t, err)
+				assocCfg, err := settings.ParseConfig([]byte(`elasticsearch:
+  hosts:
+    - "https://es-url:9200"
+  username: "federated-user"
+  password: "federated-password"
+  ssl:
+    certificateAuthorities: /usr/share/kibana/config/elasticsearch-certs/ca.crt
+    verificationMode: certificate
+`))
+				require.NoError(t, err)
+				require.NoError(t, cfg.MergeWith(assocCfg))
+				bytes, err := cfg.Render()
+				require.NoError(t, err)
+				return bytes
+			}(),
+		},
+		{
+			name: "with elasticsearch Association and a custom service account token",
+			args: args{
+				kb: func() kbv1.Kibana {
+					kb := mkKibana()
+					kb.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "test-es"}
+					kb.Spec.ElasticsearchCredentialsSecretName = "custom-credentials"
+					kb.EsAssociation().SetAssociationConf(&commonv1.AssociationConf{
+						AuthSecretName: "auth-secret",
+						AuthSecretKey:  "elastic",
+						CASecretName:   "ca-secret",
+						CACertProvided: true,
+						URL:            "https://es-url:9200",
+					})
+					return kb
+				},
+				client: k8s.NewFakeClient(
+					existingSecret,
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "custom-credentials",
+							Namespace: mkKibana().Namespace,
+						},
+						Data: map[string][]byte{
+							"token": []byte("federated-token"),
+						},
+					},
+				),
+				ipFamily: corev1.IPv4Protocol,
+			},
+			want: func() []byte {
+				cfg, err := settings.ParseConfig(defaultConfig)
+				require.NoError(t, err)
+				assocCfg, err := settings.ParseConfig([]byte(`elasticsearch:
+  hosts:
+    - "https://es-url:9200"
+  serviceAccountToken: federated-token
+  ssl:
+    certificateAuthorities: /usr/share/kibana/config/elasticsearch-certs/ca.crt
+    verificationMode: certificate
+`))
+				require.NoError(t, err)
+				require.NoError(t, cfg.MergeWith(assocCfg))
+				bytes, err := cfg.Render()
+				require.NoError(t, err)
+				return bytes
+			}(),
+		},
 		{
 			name: "with Enterprise Search Association",
 			args: args{
@@ -585,6 +697,33 @@ func TestNewConfigSettings(t *testing.T) {
 	}
 }
 
+// TestNewConfigSettingsInvalidElasticsearchCredentials checks that invalid custom Elasticsearch credentials are reported
+func TestNewConfigSettingsInvalidElasticsearchCredentials(t *testing.T) {
+	kb := mkKibana()
+	kb.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "test-es"}
+	kb.Spec.ElasticsearchCredentialsSecretName = "custom-credentials"
+	kb.EsAssociation().SetAssociationConf(&commonv1.AssociationConf{
+		AuthSecretName: "auth-secret",
+		AuthSecretKey:  "elastic",
+		CASecretName:   "ca-secret",
+		CACertProvided: true,
+		URL:            "https://es-url:9200",
+	})
+	v := version.From(7, 6, 0)
+
+	// the secret does not exist
+	_, err := NewConfigSettings(context.Background(), k8s.NewFakeClient(), kb, v, corev1.IPv4Protocol, nil)
+	require.Error(t, err)
+
+	// the secret does not contain a token nor a username and a password
+	client := k8s.NewFakeClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom-credentials", Namespace: kb.Namespace},
+		Data:       map[string][]byte{"username": []byte("federated-user")},
+	})
+	_, err = NewConfigSettings(context.Background(), client, kb, v, corev1.IPv4Protocol, nil)
+	require.Error(t, err)
+}
+
 // TestNewConfigSettingsCreateEncryptionKeys checks that we generate new keys if none are specified
 func TestNewConfigSettingsCreateEncryptionKeys(t *testing.T) {
 	client := k8s.NewFakeClient()
@@ -652,6 +791,192 @@ func TestNewConfigSettingsExplicitEncryptionKey(t *testing.T) {
 	assert.Equal(t, key, val)
 }
 
+// TestNewConfigSettingsSavedObjectsKeyRotation tests that the previous saved objects encryption key and the decryption
+// keys managed by the operator are kept to decrypt existing saved objects when the encryption key is rotated
+func TestNewConfigSettingsSavedObjectsKeyRotation(t *testing.T) {
+	// existingConfig returns a config secret with the given saved objects keys, managed is nil if the managed keys are
+	// not tracked
+	existingConfig := func(managed []string, encryptionKey string, decryptionOnlyKeys ...string) *corev1.Secret {
+		kb := mkKibana()
+		cfg := settings.MustCanonicalConfig(map[string]interface{}{
+			XpackSecurityEncryptionKey:              "securityKey",
+			XpackReportingEncryptionKey:             "reportKey",
+			XpackEncryptedSavedObjectsEncryptionKey: encryptionKey,
+		})
+		if len(decryptionOnlyKeys) > 0 {
+			require.NoError(t, cfg.MergeWith(settings.MustCanonicalConfig(map[string]interface{}{
+				XpackEncryptedSavedObjectsDecryptionOnlyKeys: decryptionOnlyKeys,
+			})))
+		}
+		rendered, err := cfg.Render()
+		require.NoError(t, err)
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: SecretName(kb), Namespace: kb.Namespace},
+			Data:       map[string][]byte{SettingsFilename: rendered},
+		}
+		if managed != nil {
+			secret.Annotations = map[string]string{managedSavedObjectsKeysAnnotationName: strings.Join(hashKeys(managed), ",")}
+		}
+		return secret
+	}
+	tests := []struct {
+		name        string
+		version     string
+		existing    *corev1.Secret
+		userConfig  map[string]interface{}
+		want        savedObjectsKeys
+		wantManaged []string
+	}{
+		{
+			name:        "single key managed by the operator",
+			version:     "8.12.0",
+			existing:    existingConfig([]string{"key1"}, "key1"),
+			want:        savedObjectsKeys{EncryptionKey: "key1"},
+			wantManaged: []string{"key1"},
+		},
+		{
+			name:        "single key specified by the user",
+			version:     "8.12.0",
+			existing:    existingConfig([]string{}, "key1"),
+			userConfig:  map[string]interface{}{XpackEncryptedSavedObjectsEncryptionKey: "key1"},
+			want:        savedObjectsKeys{EncryptionKey: "key1"},
+			wantManaged: []string{},
+		},
+		{
+			name:        "user key replacing the operator key: keep the operator key for decryption",
+			version:     "8.12.0",
+			existing:    existingConfig([]string{"key1"}, "key1"),
+			userConfig:  map[string]interface{}{XpackEncryptedSavedObjectsEncryptionKey: "key2"},
+			want:        savedObjectsKeys{EncryptionKey: "key2", DecryptionOnlyKeys: []string{"key1"}},
+			wantManaged: []string{"key1"},
+		},
+		{
+			name:        "managed keys not tracked yet: keep the previous key for decryption",
+			version:     "8.12.0",
+			existing:    existingConfig(nil, "key1"),
+			userConfig:  map[string]interface{}{XpackEncryptedSavedObjectsEncryptionKey: "key2"},
+			want:        savedObjectsKeys{EncryptionKey: "key2", DecryptionOnlyKeys: []string{"key1"}},
+			wantManaged: []string{"key1"},
+		},
+		{
+			name:        "rotated key not changed: keep the operator key for decryption",
+			version:     "8.12.0",
+			existing:    existingConfig([]string{"key1"}, "key2", "key1"),
+			userConfig:  map[string]interface{}{XpackEncryptedSavedObjectsEncryptionKey: "key2"},
+			want:        savedObjectsKeys{EncryptionKey: "key2", DecryptionOnlyKeys: []string{"key1"}},
+			wantManaged: []string{"key1"},
+		},
+		{
+			name:        "user key replacing a user key: keep the previous user key and the operator key for decryption",
+			version:     "8.12.0",
+			existing:    existingConfig([]string{"key1"}, "key2", "key1"),
+			userConfig:  map[string]interface{}{XpackEncryptedSavedObjectsEncryptionKey: "key3"},
+			want:        savedObjectsKeys{EncryptionKey: "key3", DecryptionOnlyKeys: []string{"key2", "key1"}},
+			wantManaged: []string{"key2", "key1"},
+		},
+		{
+			name:        "rotated key removed from the user config: keep using it with the operator key for decryption",
+			version:     "8.12.0",
+			existing:    existingConfig([]string{"key1"}, "key2", "key1"),
+			want:        savedObjectsKeys{EncryptionKey: "key2", DecryptionOnlyKeys: []string{"key1"}},
+			wantManaged: []string{"key2", "key1"},
+		},
+		{
+			name:        "rollback to the operator key: do not use it for decryption only, keep the previous user key",
+			version:     "8.12.0",
+			existing:    existingConfig([]string{"key1"}, "key2", "key1"),
+			userConfig:  map[string]interface{}{XpackEncryptedSavedObjectsEncryptionKey: "key1"},
+			want:        savedObjectsKeys{EncryptionKey: "key1", DecryptionOnlyKeys: []string{"key2"}},
+			wantManaged: []string{"key2"},
+		},
+		{
+			name:     "decryption keys specified by the user are not duplicated",
+			version:  "8.12.0",
+			existing: existingConfig([]string{"key1"}, "key2", "key1"),
+			userConfig: map[string]interface{}{
+				XpackEncryptedSavedObjectsEncryptionKey:      "key3",
+				XpackEncryptedSavedObjectsDecryptionOnlyKeys: []interface{}{"key1", "key0"},
+			},
+			want:        savedObjectsKeys{EncryptionKey: "key3", DecryptionOnlyKeys: []string{"key2", "key1", "key0"}},
+			wantManaged: []string{"key2"},
+		},
+		{
+			name:        "decryption keys removed by the user are not kept",
+			version:     "8.12.0",
+			existing:    existingConfig([]string{"key1"}, "key3", "key1", "key0"),
+			userConfig:  map[string]interface{}{XpackEncryptedSavedObjectsEncryptionKey: "key3"},
+			want:        savedObjectsKeys{EncryptionKey: "key3", DecryptionOnlyKeys: []string{"key1"}},
+			wantManaged: []string{"key1"},
+		},
+		{
+			name:       "key rotation not supported before 7.11.0",
+			version:    "7.10.2",
+			existing:   existingConfig([]string{"key1"}, "key1"),
+			userConfig: map[string]interface{}{XpackEncryptedSavedObjectsEncryptionKey: "key2"},
+			want:       savedObjectsKeys{EncryptionKey: "key2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := mkKibana()
+			kb.Spec.Version = tt.version
+			if tt.userConfig != nil {
+				cfg := commonv1.NewConfig(tt.userConfig)
+				kb.Spec.Config = &cfg
+			}
+			got, err := NewConfigSettings(context.Background(), k8s.NewFakeClient(tt.existing), kb, version.MustParse(tt.version), corev1.IPv4Protocol, nil)
+			require.NoError(t, err)
+			var keys savedObjectsKeys
+			require.NoError(t, got.Unpack(&keys))
+			require.Equal(t, tt.want, keys)
+			require.ElementsMatch(t, hashKeys(tt.wantManaged), got.managedSavedObjectsKeys)
+		})
+	}
+}
+
+// TestReconcileConfigSecretSavedObjectsKeyRotation tests that the saved objects keys managed by the operator are kept
+// across reconciliations, while the decryption keys removed by the user are removed from the configuration.
+func TestReconcileConfigSecretSavedObjectsKeyRotation(t *testing.T) {
+	kb := mkKibana()
+	kb.Spec.Version = "8.12.0"
+	client := k8s.NewFakeClient()
+	reconcile := func(userConfig map[string]interface{}) savedObjectsKeys {
+		t.Helper()
+		cfg := commonv1.NewConfig(userConfig)
+		kb.Spec.Config = &cfg
+		kbSettings, err := NewConfigSettings(context.Background(), client, kb, version.MustParse(kb.Spec.Version), corev1.IPv4Protocol, nil)
+		require.NoError(t, err)
+		require.NoError(t, ReconcileConfigSecret(context.Background(), client, kb, kbSettings))
+		existing, err := getExistingConfig(context.Background(), client, kb)
+		require.NoError(t, err)
+		var keys savedObjectsKeys
+		require.NoError(t, existing.Unpack(&keys))
+		return keys
+	}
+
+	// the operator generates the encryption key
+	generatedKey := reconcile(nil).EncryptionKey
+	require.Len(t, generatedKey, 64)
+
+	// the user replaces it along with a decryption key, the generated key is kept for decryption
+	userConfig := map[string]interface{}{
+		XpackEncryptedSavedObjectsEncryptionKey:      "user-key",
+		XpackEncryptedSavedObjectsDecryptionOnlyKeys: []interface{}{"user-old-key"},
+	}
+	require.Equal(t, savedObjectsKeys{EncryptionKey: "user-key", DecryptionOnlyKeys: []string{generatedKey, "user-old-key"}}, reconcile(userConfig))
+	require.Equal(t, savedObjectsKeys{EncryptionKey: "user-key", DecryptionOnlyKeys: []string{generatedKey, "user-old-key"}}, reconcile(userConfig))
+
+	// the user removes its decryption key, it is removed from the configuration
+	userConfig = map[string]interface{}{XpackEncryptedSavedObjectsEncryptionKey: "user-key"}
+	require.Equal(t, savedObjectsKeys{EncryptionKey: "user-key", DecryptionOnlyKeys: []string{generatedKey}}, reconcile(userConfig))
+	require.Equal(t, savedObjectsKeys{EncryptionKey: "user-key", DecryptionOnlyKeys: []string{generatedKey}}, reconcile(userConfig))
+
+	// the user rotates its own key, the previous user key is kept for decryption along with the generated key
+	userConfig = map[string]interface{}{XpackEncryptedSavedObjectsEncryptionKey: "user-new-key"}
+	require.Equal(t, savedObjectsKeys{EncryptionKey: "user-new-key", DecryptionOnlyKeys: []string{"user-key", generatedKey}}, reconcile(userConfig))
+	require.Equal(t, savedObjectsKeys{EncryptionKey: "user-new-key", DecryptionOnlyKeys: []string{"user-key", generatedKey}}, reconcile(userConfig))
+}
+
 // Verifies that pre-7.6.0 keys are not present in the config
 func TestNewConfigSettingsPre760(t *testing.T) {
 	kb := mkKibana()
@@ -663,6 +988,91 @@ func TestNewConfigSettingsPre760(t *testing.T) {
 	assert.Equal(t, 0, len(got.CanonicalConfig.HasKeys([]string{XpackEncryptedSavedObjects})))
 }
 
+// TestNewConfigSettingsPublicBaseURL verifies that server.publicBaseUrl is only set for Kibana versions supporting it,
+// and that it can be overridden by the user provided configuration.
+func TestNewConfigSettingsPublicBaseURL(t *testing.T) {
+	selfSignedCert := func(sans ...commonv1.SubjectAlternativeName) commonv1.HTTPConfig {
+		return commonv1.HTTPConfig{TLS: commonv1.TLSOptions{
+			SelfSignedCertificate: &commonv1.SelfSignedCertificate{SubjectAlternativeNames: sans},
+		}}
+	}
+	tests := []struct {
+		name    string
+		version string
+		kb      func(kb kbv1.Kibana) kbv1.Kibana
+		want    string
+	}{
+		{
+			name:    "not set by default",
+			version: "7.10.0",
+			kb:      func(kb kbv1.Kibana) kbv1.Kibana { return kb },
+		},
+		{
+			name:    "explicit public base URL",
+			version: "7.10.0",
+			kb: func(kb kbv1.Kibana) kbv1.Kibana {
+				kb.Spec.PublicBaseURL = "https://kibana.example.com"
+				return kb
+			},
+			want: "https://kibana.example.com",
+		},
+		{
+			name:    "explicit public base URL is ignored before 7.10.0",
+			version: "7.9.3",
+			kb: func(kb kbv1.Kibana) kbv1.Kibana {
+				kb.Spec.PublicBaseURL = "https://kibana.example.com"
+				return kb
+			},
+		},
+		{
+			name:    "not derived from the self-signed certificate",
+			version: "8.11.0",
+			kb: func(kb kbv1.Kibana) kbv1.Kibana {
+				kb.Spec.HTTP = selfSignedCert(commonv1.SubjectAlternativeName{DNS: "kibana.example.com"})
+				return kb
+			},
+		},
+		{
+			name:    "explicit public base URL with a self-signed certificate",
+			version: "8.11.0",
+			kb: func(kb kbv1.Kibana) kbv1.Kibana {
+				kb.Spec.HTTP = selfSignedCert(commonv1.SubjectAlternativeName{DNS: "kibana.example.com"})
+				kb.Spec.PublicBaseURL = "https://kibana.example.org"
+				return kb
+			},
+			want: "https://kibana.example.org",
+		},
+		{
+			name:    "user provided configuration takes precedence",
+			version: "8.11.0",
+			kb: func(kb kbv1.Kibana) kbv1.Kibana {
+				kb.Spec.PublicBaseURL = "https://kibana.example.org"
+				kb.Spec.Config = &commonv1.Config{Data: map[string]interface{}{
+					ServerPublicBaseURL: "https://user.example.org",
+				}}
+				return kb
+			},
+			want: "https://user.example.org",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := mkKibana()
+			kb.Spec.Version = tt.version
+			kb = tt.kb(kb)
+			got, err := NewConfigSettings(context.Background(), k8s.NewFakeClient(), kb, version.MustParse(tt.version), corev1.IPv4Protocol, nil)
+			require.NoError(t, err)
+			if tt.want == "" {
+				assert.Equal(t, 0, len(got.CanonicalConfig.HasKeys([]string{ServerPublicBaseURL})))
+				return
+			}
+			val, err := (*ucfg.Config)(got.CanonicalConfig).String(ServerPublicBaseURL, -1, settings.Options...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, val)
+		})
+	}
+}
+
 func mkKibana() kbv1.Kibana {
 	kb := kbv1.Kibana{
 		ObjectMeta: metav1.ObjectMeta{
@@ -674,6 +1084,217 @@ func mkKibana() kbv1.Kibana {
 	return kb
 }
 
+func TestNewConfigSettingsServer(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    string
+		server     *kbv1.ServerSettings
+		userConfig map[string]interface{}
+		want       map[string]interface{}
+	}{
+		{
+			name:    "not set by default",
+			version: "8.11.0",
+			want:    map[string]interface{}{},
+		},
+		{
+			name:    "all server settings",
+			version: "8.11.0",
+			server: &kbv1.ServerSettings{
+				MaxPayload:       ptr.To[int64](10485760),
+				KeepaliveTimeout: ptr.To[int64](60000),
+				SocketTimeout:    ptr.To[int64](300000),
+			},
+			want: map[string]interface{}{
+				"maxPayload":       uint64(10485760),
+				"keepaliveTimeout": uint64(60000),
+				"socketTimeout":    uint64(300000),
+			},
+		},
+		{
+			name:    "max payload before 7.13.0",
+			version: "7.12.1",
+			server:  &kbv1.ServerSettings{MaxPayload: ptr.To[int64](10485760)},
+			want:    map[string]interface{}{"maxPayloadBytes": uint64(10485760)},
+		},
+		{
+			name:       "merged with the user provided configuration, which takes precedence",
+			version:    "8.11.0",
+			server:     &kbv1.ServerSettings{MaxPayload: ptr.To[int64](10485760), SocketTimeout: ptr.To[int64](300000)},
+			userConfig: map[string]interface{}{ServerSocketTimeout: 600000, "server.customResponseHeaders": map[string]interface{}{"x-team": "kibana"}},
+			want: map[string]interface{}{
+				"maxPayload":            uint64(10485760),
+				"socketTimeout":         uint64(600000),
+				"customResponseHeaders": map[string]interface{}{"x-team": "kibana"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := mkKibana()
+			kb.Spec.Version = tt.version
+			kb.Spec.Server = tt.server
+			if tt.userConfig != nil {
+				cfg := commonv1.NewConfig(tt.userConfig)
+				kb.Spec.Config = &cfg
+			}
+			got, err := NewConfigSettings(context.Background(), k8s.NewFakeClient(), kb, version.MustParse(tt.version), corev1.IPv4Protocol, nil)
+			require.NoError(t, err)
+			var gotCfg map[string]interface{}
+			require.NoError(t, got.Unpack(&gotCfg))
+			server, ok := gotCfg["server"].(map[string]interface{})
+			require.True(t, ok)
+			// ignore the settings that are always set
+			for _, k := range []string{"name", "host", "ssl"} {
+				delete(server, k)
+			}
+			require.Equal(t, tt.want, server)
+		})
+	}
+}
+
+func TestNewConfigSettingsReporting(t *testing.T) {
+	encryptionKeySecret := func(key string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "reporting-key", Namespace: "testns"},
+			Data:       map[string][]byte{ReportingEncryptionKeyKey: []byte(key)},
+		}
+	}
+	tests := []struct {
+		name       string
+		reporting  *kbv1.ReportingSettings
+		disableTLS bool
+		userConfig map[string]interface{}
+		secret     *corev1.Secret
+		want       map[string]interface{}
+		wantErr    bool
+	}{
+		{
+			name: "not set by default",
+			want: map[string]interface{}{},
+		},
+		{
+			name:      "local Kibana server",
+			reporting: &kbv1.ReportingSettings{},
+			want: map[string]interface{}{
+				"kibanaServer": map[string]interface{}{"hostname": "localhost", "port": uint64(5601), "protocol": "https"},
+			},
+		},
+		{
+			name:       "local Kibana server without TLS",
+			reporting:  &kbv1.ReportingSettings{},
+			disableTLS: true,
+			want: map[string]interface{}{
+				"kibanaServer": map[string]interface{}{"hostname": "localhost", "port": uint64(5601), "protocol": "http"},
+			},
+		},
+		{
+			name: "all reporting settings",
+			reporting: &kbv1.ReportingSettings{
+				EncryptionKeySecretName: "reporting-key",
+				QueueTimeout:            ptr.To[int64](240000),
+				CaptureMaxAttempts:      ptr.To[int32](3),
+			},
+			secret: encryptionKeySecret("0123456789abcdef0123456789abcdef"),
+			want: map[string]interface{}{
+				"kibanaServer":  map[string]interface{}{"hostname": "localhost", "port": uint64(5601), "protocol": "https"},
+				"queue":         map[string]interface{}{"timeout": uint64(240000)},
+				"capture":       map[string]interface{}{"maxAttempts": uint64(3)},
+				"encryptionKey": "0123456789abcdef0123456789abcdef",
+			},
+		},
+		{
+			name:       "merged with the user provided configuration, which takes precedence",
+			reporting:  &kbv1.ReportingSettings{CaptureMaxAttempts: ptr.To[int32](3)},
+			userConfig: map[string]interface{}{XpackReportingCaptureMaxAttempts: 5, XpackReportingKibanaServerHostname: "kibana.example.com"},
+			want: map[string]interface{}{
+				"kibanaServer": map[string]interface{}{"hostname": "kibana.example.com", "port": uint64(5601), "protocol": "https"},
+				"capture":      map[string]interface{}{"maxAttempts": uint64(5)},
+			},
+		},
+		{
+			name:      "encryption key secret does not exist",
+			reporting: &kbv1.ReportingSettings{EncryptionKeySecretName: "reporting-key"},
+			wantErr:   true,
+		},
+		{
+			name:      "encryption key too short",
+			reporting: &kbv1.ReportingSettings{EncryptionKeySecretName: "reporting-key"},
+			secret:    encryptionKeySecret("too-short"),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := mkKibana()
+			kb.Spec.Reporting = tt.reporting
+			if tt.disableTLS {
+				kb.Spec.HTTP.TLS.SelfSignedCertificate = &commonv1.SelfSignedCertificate{Disabled: true}
+			}
+			if tt.userConfig != nil {
+				cfg := commonv1.NewConfig(tt.userConfig)
+				kb.Spec.Config = &cfg
+			}
+			client := k8s.NewFakeClient()
+			if tt.secret != nil {
+				client = k8s.NewFakeClient(tt.secret)
+			}
+			got, err := NewConfigSettings(context.Background(), client, kb, version.MustParse(kb.Spec.Version), corev1.IPv4Protocol, nil)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var gotCfg map[string]interface{}
+			require.NoError(t, got.Unpack(&gotCfg))
+			reporting, ok := gotCfg["xpack"].(map[string]interface{})["reporting"].(map[string]interface{})
+			require.True(t, ok)
+			if tt.secret == nil {
+				// ignore the encryption key generated by the operator
+				delete(reporting, "encryptionKey")
+			}
+			require.Equal(t, tt.want, reporting)
+		})
+	}
+}
+
+// TestNewConfigSettingsReportingEncryptionKeyRotation tests that the reporting encryption key is updated when the
+// user-provided secret is, and is kept when the secret is not referenced anymore.
+func TestNewConfigSettingsReportingEncryptionKeyRotation(t *testing.T) {
+	kb := mkKibana()
+	kb.Spec.Reporting = &kbv1.ReportingSettings{EncryptionKeySecretName: "reporting-key"}
+	v := version.MustParse(kb.Spec.Version)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "reporting-key", Namespace: kb.Namespace},
+		Data:       map[string][]byte{ReportingEncryptionKeyKey: []byte("first-key-0123456789abcdef0123456789")},
+	}
+	client := k8s.NewFakeClient(secret)
+	reportingKey := func(cfg CanonicalConfig) string {
+		val, err := (*ucfg.Config)(cfg.CanonicalConfig).String(XpackReportingEncryptionKey, -1, settings.Options...)
+		require.NoError(t, err)
+		return val
+	}
+
+	got, err := NewConfigSettings(context.Background(), client, kb, v, corev1.IPv4Protocol, nil)
+	require.NoError(t, err)
+	require.Equal(t, "first-key-0123456789abcdef0123456789", reportingKey(got))
+	require.NoError(t, ReconcileConfigSecret(context.Background(), client, kb, got))
+
+	// the key is rotated by updating the secret
+	secret.Data[ReportingEncryptionKeyKey] = []byte("second-key-0123456789abcdef0123456789")
+	require.NoError(t, client.Update(context.Background(), secret))
+	got, err = NewConfigSettings(context.Background(), client, kb, v, corev1.IPv4Protocol, nil)
+	require.NoError(t, err)
+	require.Equal(t, "second-key-0123456789abcdef0123456789", reportingKey(got))
+	require.NoError(t, ReconcileConfigSecret(context.Background(), client, kb, got))
+
+	// the last key is reused once the secret is not referenced anymore
+	kb.Spec.Reporting = nil
+	got, err = NewConfigSettings(context.Background(), client, kb, v, corev1.IPv4Protocol, nil)
+	require.NoError(t, err)
+	require.Equal(t, "second-key-0123456789abcdef0123456789", reportingKey(got))
+}
+
 func Test_getExistingConfig(t *testing.T) {
 	testKb := kbv1.Kibana{
 		ObjectMeta: metav1.ObjectMeta{
@@ -767,3 +1388,129 @@ func Test_getExistingConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConfigSettingsSAMLProviders(t *testing.T) {
+	samlRealm := func(name, acs string) esv1.SAMLRealm {
+		return esv1.SAMLRealm{Name: name, SP: esv1.SAMLServiceProvider{ACS: acs}}
+	}
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "testns"},
+		Spec: esv1.ElasticsearchSpec{Auth: esv1.Auth{Realms: esv1.Realms{SAML: []esv1.SAMLRealm{
+			samlRealm("saml1", "https://kibana.example.com/api/security/saml/callback"),
+			samlRealm("saml2", "https://other-kibana.example.com/api/security/saml/callback"),
+		}}}},
+	}
+	tests := []struct {
+		name          string
+		version       string
+		esRef         commonv1.ObjectSelector
+		publicBaseURL string
+		samlProviders *kbv1.SAMLProvidersSettings
+		want          map[string]interface{}
+	}{
+		{
+			name:    "not set by default",
+			version: "8.11.0",
+			esRef:   commonv1.ObjectSelector{Name: "es"},
+		},
+		{
+			name:          "providers of all the realms if the public base URL is unknown",
+			version:       "8.11.0",
+			esRef:         commonv1.ObjectSelector{Name: "es"},
+			samlProviders: &kbv1.SAMLProvidersSettings{},
+			want: map[string]interface{}{
+				"saml": map[string]interface{}{
+					"saml1": map[string]interface{}{"order": 0, "realm": "saml1"},
+					"saml2": map[string]interface{}{"order": 1, "realm": "saml2"},
+				},
+				"basic": map[string]interface{}{"basic1": map[string]interface{}{"order": 2}},
+			},
+		},
+		{
+			name:          "providers of the realms calling back the public base URL",
+			version:       "8.11.0",
+			esRef:         commonv1.ObjectSelector{Name: "es"},
+			publicBaseURL: "https://kibana.example.com/",
+			samlProviders: &kbv1.SAMLProvidersSettings{},
+			want: map[string]interface{}{
+				"saml":  map[string]interface{}{"saml1": map[string]interface{}{"order": 0, "realm": "saml1"}},
+				"basic": map[string]interface{}{"basic1": map[string]interface{}{"order": 1}},
+			},
+		},
+		{
+			name:          "providers of the selected realms without basic provider",
+			version:       "8.11.0",
+			esRef:         commonv1.ObjectSelector{Name: "es"},
+			publicBaseURL: "https://kibana.example.com",
+			samlProviders: &kbv1.SAMLProvidersSettings{Realms: []string{"saml2"}, DisableBasicProvider: true},
+			want: map[string]interface{}{
+				"saml": map[string]interface{}{"saml2": map[string]interface{}{"order": 0, "realm": "saml2"}},
+			},
+		},
+		{
+			name:          "no provider if no realm is selected",
+			version:       "8.11.0",
+			esRef:         commonv1.ObjectSelector{Name: "es"},
+			publicBaseURL: "https://unknown.example.com",
+			samlProviders: &kbv1.SAMLProvidersSettings{},
+		},
+		{
+			name:          "no provider for a Kibana version not supporting named providers",
+			version:       "7.9.0",
+			esRef:         commonv1.ObjectSelector{Name: "es"},
+			samlProviders: &kbv1.SAMLProvidersSettings{},
+		},
+		{
+			name:          "no provider for an external Elasticsearch cluster",
+			version:       "8.11.0",
+			esRef:         commonv1.ObjectSelector{SecretName: "external-es"},
+			samlProviders: &kbv1.SAMLProvidersSettings{},
+		},
+		{
+			name:          "no provider if the Elasticsearch cluster does not exist",
+			version:       "8.11.0",
+			esRef:         commonv1.ObjectSelector{Name: "unknown"},
+			samlProviders: &kbv1.SAMLProvidersSettings{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := mkKibana()
+			kb.Spec.Version = tt.version
+			kb.Spec.ElasticsearchRef = tt.esRef
+			kb.Spec.PublicBaseURL = tt.publicBaseURL
+			kb.Spec.SAMLProviders = tt.samlProviders
+			got, err := NewConfigSettings(context.Background(), k8s.NewFakeClient(es), kb, version.MustParse(kb.Spec.Version), corev1.IPv4Protocol, nil)
+			require.NoError(t, err)
+			child, err := (*ucfg.Config)(got.CanonicalConfig).Child(XpackSecurityAuthcProviders, -1, settings.Options...)
+			if tt.want == nil {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var providers map[string]interface{}
+			require.NoError(t, child.Unpack(&providers))
+			// compare the JSON representations to ignore the integer types picked by ucfg
+			wantJSON, err := json.Marshal(tt.want)
+			require.NoError(t, err)
+			gotJSON, err := json.Marshal(providers)
+			require.NoError(t, err)
+			require.JSONEq(t, string(wantJSON), string(gotJSON))
+		})
+	}
+}
+
+func Test_watchSAMLProvidersElasticsearch(t *testing.T) {
+	kb := mkKibana()
+	kb.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "es"}
+	kb.Spec.SAMLProviders = &kbv1.SAMLProvidersSettings{}
+	dynamicWatches := watches.NewDynamicWatches()
+	watchName := samlProvidersWatchName(k8s.ExtractNamespacedName(&kb))
+
+	require.NoError(t, watchSAMLProvidersElasticsearch(kb, dynamicWatches))
+	require.Equal(t, []string{watchName}, dynamicWatches.ReferencedResources.Registrations())
+
+	kb.Spec.SAMLProviders = nil
+	require.NoError(t, watchSAMLProvidersElasticsearch(kb, dynamicWatches))
+	require.Empty(t, dynamicWatches.ReferencedResources.Registrations())
+}
@@ -13,15 +13,21 @@ import (
 	"go.elastic.co/apm/v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
@@ -32,6 +38,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/pdb"
 	kblabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
@@ -46,7 +53,7 @@ const (
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	reconciler := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, controllerName, reconciler, params)
+	c, err := common.NewController(mgr, controllerName, reconciler, &kbv1.Kibana{}, params)
 	if err != nil {
 		return err
 	}
@@ -101,8 +108,29 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileKibana
 		return err
 	}
 
+	// Watch the default PDB, in the version of the resource available in the cluster
+	pdbV1Available, err := pdb.IsV1Available(mgr.GetClient())
+	if err != nil {
+		return err
+	}
+	var pdbType client.Object = &policyv1.PodDisruptionBudget{}
+	if !pdbV1Available {
+		pdbType = &policyv1beta1.PodDisruptionBudget{}
+	}
+	if err := c.Watch(source.Kind(mgr.GetCache(), pdbType), handler.EnqueueRequestForOwner(
+		mgr.GetScheme(), mgr.GetRESTMapper(),
+		&kbv1.Kibana{}, handler.OnlyControllerOwner(),
+	)); err != nil {
+		return err
+	}
+
 	// dynamically watch referenced secrets to connect to Elasticsearch
-	return c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}), r.dynamicWatches.Secrets)
+	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}), r.dynamicWatches.Secrets); err != nil {
+		return err
+	}
+
+	// dynamically watch the Elasticsearch clusters whose SAML realms are used to generate the SAML providers
+	return c.Watch(source.Kind(mgr.GetCache(), &esv1.Elasticsearch{}), r.dynamicWatches.ReferencedResources)
 }
 
 var _ reconcile.Reconciler = &ReconcileKibana{}
@@ -141,12 +169,7 @@ func (r *ReconcileKibana) Reconcile(ctx context.Context, request reconcile.Reque
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
-	if common.IsUnmanaged(ctx, &kb) {
-		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", kb.Namespace, "kibana_name", kb.Name)
-		return reconcile.Result{}, nil
-	}
-
-	// Remove any previous Finalizers
+	// Remove any previous Finalizers, even if Kibana is not managed, not to prevent its deletion
 	if err := finalizer.RemoveAll(ctx, r.Client, &kb); err != nil {
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
@@ -156,12 +179,40 @@ func (r *ReconcileKibana) Reconcile(ctx context.Context, request reconcile.Reque
 		return reconcile.Result{}, r.onDelete(ctx, k8s.ExtractNamespacedName(&kb))
 	}
 
+	if common.IsUnmanaged(ctx, &kb) {
+		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", kb.Namespace, "kibana_name", kb.Name)
+		return reconcile.Result{}, tracing.CaptureError(ctx, r.reportPaused(ctx, kb))
+	}
+
 	// main reconciliation logic
-	return r.doReconcile(ctx, request, &kb)
+	result, err := r.doReconcile(ctx, request, &kb)
+	if observeErr := common.ObserveReconcileNow(ctx, r.Client, &kb); observeErr != nil && err == nil {
+		err = tracing.CaptureError(ctx, observeErr)
+	}
+	return common.WithResync(ctx, &kb, r.params.ResyncPeriod, result), err
+}
+
+// reportPaused records the ReconciliationPaused condition in the status of the given Kibana, whose reconciliation is
+// skipped since it is not managed by the operator anymore.
+func (r *ReconcileKibana) reportPaused(ctx context.Context, kb kbv1.Kibana) error {
+	if meta.IsStatusConditionTrue(kb.Status.Conditions, kbv1.ReconciliationPausedCondition) {
+		return nil
+	}
+	r.recorder.Event(&kb, corev1.EventTypeNormal, events.EventReasonPaused, common.PausedMessage)
+	meta.SetStatusCondition(&kb.Status.Conditions, metav1.Condition{
+		Type:               kbv1.ReconciliationPausedCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: kb.Generation,
+		Reason:             events.EventReasonPaused,
+		Message:            common.PausedMessage,
+	})
+	return common.UpdateStatus(ctx, r.Client, &kb)
 }
 
 func (r *ReconcileKibana) doReconcile(ctx context.Context, request reconcile.Request, kb *kbv1.Kibana) (result reconcile.Result, err error) {
 	state := NewState(request, kb)
+	// the reconciliation is resumed
+	meta.RemoveStatusCondition(&kb.Status.Conditions, kbv1.ReconciliationPausedCondition)
 	log := ulog.FromContext(ctx)
 	// defer the updating of status to ensure that the status is updated regardless of the outcome of the reconciliation.
 	// note that this deferred function is modifying the return values, which are named return values, which allows this
@@ -187,7 +238,7 @@ func (r *ReconcileKibana) doReconcile(ctx context.Context, request reconcile.Req
 	}
 
 	var driver *driver
-	driver, err = newDriver(r, r.dynamicWatches, r.recorder, kb, r.params.IPFamily)
+	driver, err = newDriver(r, r.dynamicWatches, r.recorder, kb, r.params.IPFamily, r.params.SetDefaultSecurityContext)
 	if err != nil {
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
@@ -237,6 +288,12 @@ func (r *ReconcileKibana) onDelete(ctx context.Context, obj types.NamespacedName
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(obj))
 	// Clean up watches set on custom http tls certificates
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(certificates.CertificateWatchKey(kbv1.KBNamer, obj.Name))
+	// Clean up watches set on custom Elasticsearch credentials
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(elasticsearchCredentialsWatchName(obj))
+	// Clean up watches set on custom reporting encryption keys
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(reportingEncryptionKeyWatchName(obj))
+	// Clean up watches set on the Elasticsearch cluster of the SAML providers
+	r.dynamicWatches.ReferencedResources.RemoveHandlerForKey(samlProvidersWatchName(obj))
 	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, obj, kbv1.Kind)
 }
 
@@ -8,12 +8,15 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -24,6 +27,7 @@ import (
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	kibanav1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
@@ -87,16 +91,30 @@ func TestReconcileKibana_Reconcile(t *testing.T) {
 				var kibana kibanav1.Kibana
 				err := f.Client.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "test-kibana"}, &kibana)
 				require.NoError(t, err)
-				require.Equal(t, kibanav1.KibanaStatus{
-					DeploymentStatus: commonv1.DeploymentStatus{
-						Selector:       "",
-						Count:          0,
-						AvailableNodes: 0,
-						Version:        "",
-						Health:         commonv1.DeploymentHealth(""),
-					},
-					ObservedGeneration: 1,
-				}, kibana.Status)
+				require.Equal(t, commonv1.DeploymentStatus{}, kibana.Status.DeploymentStatus)
+				require.Equal(t, int64(1), kibana.Status.ObservedGeneration)
+				// the reconciliation is reported as paused
+				require.True(t, meta.IsStatusConditionTrue(kibana.Status.Conditions, kibanav1.ReconciliationPausedCondition))
+			},
+		},
+		{
+			name: "unmanaged kibana instance being deleted has its legacy finalizer removed",
+			fields: fields{
+				Client: k8s.NewFakeClient(
+					&sampleElasticsearch,
+					withDeletionTimestamp(withFinalizers(
+						withAnnotations(&sampleKibana, map[string]string{common.ManagedAnnotation: "false"}),
+						[]string{"finalizer.elasticsearch.k8s.elastic.co/secure-settings-secret"},
+					)),
+				),
+			},
+			request: defaultRequest,
+			want:    reconcile.Result{},
+			wantErr: false,
+			validate: func(t *testing.T, f fields) {
+				var kibana kibanav1.Kibana
+				err := f.Client.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "test-kibana"}, &kibana)
+				require.True(t, apierrors.IsNotFound(err))
 			},
 		},
 		{
@@ -357,6 +375,91 @@ func TestReconcileKibana_Reconcile(t *testing.T) {
 	}
 }
 
+func TestReconcileKibana_Reconcile_paused(t *testing.T) {
+	kb := kibanav1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-kibana",
+			Namespace:   "test",
+			Generation:  2,
+			Annotations: map[string]string{common.ManagedAnnotation: "false"},
+		},
+		Spec:   kibanav1.KibanaSpec{Version: "7.17.0", Count: 1},
+		Status: kibanav1.KibanaStatus{ObservedGeneration: 1},
+	}
+	c := k8s.NewFakeClient(&kb)
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileKibana{
+		Client:         c,
+		recorder:       recorder,
+		dynamicWatches: watches.NewDynamicWatches(),
+		params:         operator.Parameters{},
+	}
+	request := reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)}
+
+	// the paused Kibana is skipped, and reported as paused once
+	for i := 0; i < 2; i++ {
+		_, err := r.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+	}
+	require.NoError(t, c.Get(context.Background(), request.NamespacedName, &kb))
+	require.Equal(t, int64(1), kb.Status.ObservedGeneration)
+	require.True(t, meta.IsStatusConditionTrue(kb.Status.Conditions, kibanav1.ReconciliationPausedCondition))
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, events.EventReasonPaused)
+
+	// removing the annotation resumes the reconciliation
+	kb.Annotations = nil
+	require.NoError(t, c.Update(context.Background(), &kb))
+	_, err := r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(context.Background(), request.NamespacedName, &kb))
+	require.Equal(t, int64(2), kb.Status.ObservedGeneration)
+	require.Nil(t, meta.FindStatusCondition(kb.Status.Conditions, kibanav1.ReconciliationPausedCondition))
+}
+
+func TestReconcileKibana_Reconcile_resync(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		resyncPeriod time.Duration
+		annotations  map[string]string
+		want         time.Duration
+	}{
+		{
+			name:         "operator resync period",
+			resyncPeriod: 5 * time.Minute,
+			want:         5 * time.Minute,
+		},
+		{
+			name:         "resync period overridden by the annotation",
+			resyncPeriod: 5 * time.Minute,
+			annotations:  map[string]string{common.ResyncPeriodAnnotation: "1m"},
+			want:         time.Minute,
+		},
+		{
+			name:         "resync period clamped to the lower bound",
+			resyncPeriod: 5 * time.Minute,
+			annotations:  map[string]string{common.ResyncPeriodAnnotation: "1s"},
+			want:         common.MinResyncPeriod,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := kibanav1.Kibana{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-kibana", Namespace: "test", Annotations: tt.annotations},
+				Spec:       kibanav1.KibanaSpec{Version: "7.17.0", Count: 1},
+			}
+			r := &ReconcileKibana{
+				Client:         k8s.NewFakeClient(withTLSDisabled(&kb)),
+				recorder:       record.NewFakeRecorder(100),
+				dynamicWatches: watches.NewDynamicWatches(),
+				params:         operator.Parameters{ResyncPeriod: tt.resyncPeriod},
+			}
+			got, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+			require.NoError(t, err)
+			require.Equal(t, reconcile.Result{RequeueAfter: tt.want}, got)
+		})
+	}
+}
+
 func withAnnotations(kibana *kibanav1.Kibana, annotations map[string]string) *kibanav1.Kibana {
 	obj := kibana.DeepCopy()
 	obj.ObjectMeta.Annotations = annotations
@@ -369,6 +472,13 @@ func withFinalizers(kibana *kibanav1.Kibana, finalizers []string) *kibanav1.Kiba
 	return obj
 }
 
+func withDeletionTimestamp(kibana *kibanav1.Kibana) *kibanav1.Kibana {
+	obj := kibana.DeepCopy()
+	now := metav1.Now()
+	obj.ObjectMeta.DeletionTimestamp = &now
+	return obj
+}
+
 func withName(kibana *kibanav1.Kibana, name string) *kibanav1.Kibana {
 	obj := kibana.DeepCopy()
 	obj.ObjectMeta.Name = name
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"hash/fnv"
+	"time"
 
 	pkgerrors "github.com/pkg/errors"
 	"go.elastic.co/apm/v2"
@@ -45,11 +46,12 @@ import (
 var minSupportedVersion = version.From(6, 8, 0)
 
 type driver struct {
-	client         k8s.Client
-	dynamicWatches watches.DynamicWatches
-	recorder       record.EventRecorder
-	version        version.Version
-	ipFamily       corev1.IPFamily
+	client                    k8s.Client
+	dynamicWatches            watches.DynamicWatches
+	recorder                  record.EventRecorder
+	version                   version.Version
+	ipFamily                  corev1.IPFamily
+	setDefaultSecurityContext bool
 }
 
 func (d *driver) DynamicWatches() watches.DynamicWatches {
@@ -72,6 +74,7 @@ func newDriver(
 	recorder record.EventRecorder,
 	kb *kbv1.Kibana,
 	ipFamily corev1.IPFamily,
+	setDefaultSecurityContext bool,
 ) (*driver, error) {
 	ver, err := version.Parse(kb.Spec.Version)
 	if err != nil {
@@ -86,11 +89,12 @@ func newDriver(
 	}
 
 	return &driver{
-		client:         client,
-		dynamicWatches: watches,
-		recorder:       recorder,
-		version:        ver,
-		ipFamily:       ipFamily,
+		client:                    client,
+		dynamicWatches:            watches,
+		recorder:                  recorder,
+		version:                   ver,
+		ipFamily:                  ipFamily,
+		setDefaultSecurityContext: setDefaultSecurityContext,
 	}, nil
 }
 
@@ -116,7 +120,7 @@ func (d *driver) Reconcile(
 		return results
 	}
 
-	svc, err := common.ReconcileService(ctx, d.client, NewService(*kb), kb)
+	svc, err := common.ReconcileService(ctx, d.client, NewService(ctx, *kb), kb)
 	if err != nil {
 		// TODO: consider updating some status here?
 		return results.WithError(err)
@@ -155,6 +159,18 @@ func (d *driver) Reconcile(
 		return results.WithError(err)
 	}
 
+	if err := watchElasticsearchCredentials(*kb, d.DynamicWatches()); err != nil {
+		return results.WithError(err)
+	}
+
+	if err := watchReportingEncryptionKey(*kb, d.DynamicWatches()); err != nil {
+		return results.WithError(err)
+	}
+
+	if err := watchSAMLProvidersElasticsearch(*kb, d.DynamicWatches()); err != nil {
+		return results.WithError(err)
+	}
+
 	kbSettings, err := NewConfigSettings(ctx, d.client, *kb, d.version, d.ipFamily, kibanaPolicyCfg.KibanaConfig)
 	if err != nil {
 		return results.WithError(err)
@@ -178,12 +194,26 @@ func (d *driver) Reconcile(
 		return results.WithError(err)
 	}
 
+	// pause the Deployment to defer the rollout of changes to the Pods outside of the maintenance window
+	paused, untilOpen, err := deploymentPaused(ctx, d.client, *kb, time.Now())
+	if err != nil {
+		return results.WithError(err)
+	}
+	if paused {
+		deploymentParams.Paused = true
+		results.WithReconciliationState(reconciler.RequeueAfter(untilOpen).WithReason("Kibana Pods rollout deferred until the next maintenance window"))
+	}
+
 	expectedDp := deployment.New(deploymentParams)
 	reconciledDp, err := deployment.Reconcile(ctx, d.client, expectedDp, kb)
 	if err != nil {
 		return results.WithError(err)
 	}
 
+	if err := reconcilePDB(ctx, d.client, *kb); err != nil {
+		return results.WithError(err)
+	}
+
 	existingPods, err := k8s.PodsMatchingLabels(d.K8sClient(), kb.Namespace, map[string]string{kblabel.KibanaNameLabelName: kb.Name})
 	if err != nil {
 		return results.WithError(err)
@@ -242,7 +272,7 @@ func (d *driver) deploymentParams(ctx context.Context, kb *kbv1.Kibana, policyAn
 	if err != nil {
 		return deployment.Params{}, err
 	}
-	kibanaPodSpec, err := NewPodTemplateSpec(ctx, d.client, *kb, keystoreResources, volumes)
+	kibanaPodSpec, err := NewPodTemplateSpec(ctx, d.client, *kb, keystoreResources, volumes, d.setDefaultSecurityContext)
 	if err != nil {
 		return deployment.Params{}, err
 	}
@@ -281,7 +311,12 @@ func (d *driver) deploymentParams(ctx context.Context, kb *kbv1.Kibana, policyAn
 	if err != nil {
 		return deployment.Params{}, err
 	}
-	_, _ = configHash.Write(configSecret.Data[SettingsFilename])
+	// settings Kibana can reload at runtime may be left out of the checksum, depending on the config reload strategy
+	restartRequiredCfg, err := restartRequiredConfig(*kb, configSecret.Data[SettingsFilename])
+	if err != nil {
+		return deployment.Params{}, err
+	}
+	_, _ = configHash.Write(restartRequiredCfg)
 
 	// add the checksum to an annotation for the deployment and its pods (the important bit is that the pod template
 	// changes, which will trigger a rolling update)
@@ -309,7 +344,7 @@ func (d *driver) deploymentParams(ctx context.Context, kb *kbv1.Kibana, policyAn
 }
 
 func (d *driver) buildVolumes(kb *kbv1.Kibana) ([]commonvolume.VolumeLike, error) {
-	volumes := []commonvolume.VolumeLike{DataVolume, ConfigSharedVolume, ConfigVolume(*kb)}
+	volumes := []commonvolume.VolumeLike{DataVolume, configSharedVolume(*kb), ConfigVolume(*kb)}
 
 	esAssocConf, err := kb.EsAssociation().AssociationConf()
 	if err != nil {
@@ -336,7 +371,7 @@ func (d *driver) buildVolumes(kb *kbv1.Kibana) ([]commonvolume.VolumeLike, error
 	return volumes, nil
 }
 
-func NewService(kb kbv1.Kibana) *corev1.Service {
+func NewService(ctx context.Context, kb kbv1.Kibana) *corev1.Service {
 	svc := corev1.Service{
 		ObjectMeta: kb.Spec.HTTP.Service.ObjectMeta,
 		Spec:       kb.Spec.HTTP.Service.Spec,
@@ -353,5 +388,12 @@ func NewService(kb kbv1.Kibana) *corev1.Service {
 			Port:     network.HTTPPort,
 		},
 	}
-	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
+	if kb.Spec.MetricsExporter != nil {
+		ports = append(ports, corev1.ServicePort{
+			Name:     MetricsExporterPortName,
+			Protocol: corev1.ProtocolTCP,
+			Port:     kb.Spec.MetricsExporter.MetricsPort(),
+		})
+	}
+	return defaults.SetServiceDefaults(ctx, &svc, labels, labels, ports)
 }
@@ -181,7 +181,7 @@ func Test_getStrategyType(t *testing.T) {
 				client = k8s.NewFailingClient(errors.New("client error"))
 			}
 
-			d, err := newDriver(client, w, record.NewFakeRecorder(100), kb, corev1.IPv4Protocol)
+			d, err := newDriver(client, w, record.NewFakeRecorder(100), kb, corev1.IPv4Protocol, false)
 			assert.NoError(t, err)
 
 			strategy, err := d.getStrategyType(kb)
@@ -372,7 +372,7 @@ func TestDriverDeploymentParams(t *testing.T) {
 			client := k8s.NewFakeClient(initialObjects...)
 			w := watches.NewDynamicWatches()
 
-			d, err := newDriver(client, w, record.NewFakeRecorder(100), kb, corev1.IPv4Protocol)
+			d, err := newDriver(client, w, record.NewFakeRecorder(100), kb, corev1.IPv4Protocol, false)
 			require.NoError(t, err)
 
 			got, err := d.deploymentParams(context.Background(), kb, tt.args.policyAnnotations)
@@ -388,6 +388,41 @@ func TestDriverDeploymentParams(t *testing.T) {
 	}
 }
 
+func TestDriverDeploymentParams_ConfigReloadStrategy(t *testing.T) {
+	configHash := func(t *testing.T, strategy kbv1.ConfigReloadStrategy, cfg string) string {
+		t.Helper()
+		kb := kibanaFixture()
+		kb.Spec.ConfigReloadStrategy = strategy
+		initialObjects := defaultInitialObjects()
+		for _, obj := range initialObjects {
+			if obj.GetName() == "test-kb-config" {
+				obj.(*corev1.Secret).Data[SettingsFilename] = []byte(cfg)
+			}
+		}
+		d, err := newDriver(k8s.NewFakeClient(initialObjects...), watches.NewDynamicWatches(), record.NewFakeRecorder(100), kb, corev1.IPv4Protocol, false)
+		require.NoError(t, err)
+		params, err := d.deploymentParams(context.Background(), kb, nil)
+		require.NoError(t, err)
+		return params.PodTemplateSpec.Annotations[configHashAnnotationName]
+	}
+
+	initialCfg := "server.name: test\nlogging.root.level: info"
+	reloadableChange := "server.name: test\nlogging.root.level: debug"
+	restartRequiredChange := "server.name: updated\nlogging.root.level: info"
+
+	// with the Reload strategy, only changes to settings that cannot be reloaded roll the pods
+	initialHash := configHash(t, kbv1.ReloadConfigReloadStrategy, initialCfg)
+	require.Equal(t, initialHash, configHash(t, kbv1.ReloadConfigReloadStrategy, reloadableChange))
+	require.NotEqual(t, initialHash, configHash(t, kbv1.ReloadConfigReloadStrategy, restartRequiredChange))
+
+	// with the default Restart strategy, any change rolls the pods
+	for _, strategy := range []kbv1.ConfigReloadStrategy{"", kbv1.RestartConfigReloadStrategy} {
+		initialHash := configHash(t, strategy, initialCfg)
+		require.NotEqual(t, initialHash, configHash(t, strategy, reloadableChange))
+		require.NotEqual(t, initialHash, configHash(t, strategy, restartRequiredChange))
+	}
+}
+
 func TestMinSupportedVersion(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -418,7 +453,7 @@ func TestMinSupportedVersion(t *testing.T) {
 			client := k8s.NewFakeClient(defaultInitialObjects()...)
 			w := watches.NewDynamicWatches()
 
-			_, err := newDriver(client, w, record.NewFakeRecorder(100), kb, corev1.IPv4Protocol)
+			_, err := newDriver(client, w, record.NewFakeRecorder(100), kb, corev1.IPv4Protocol, false)
 			if tc.wantErr {
 				require.Error(t, err)
 			} else {
@@ -594,6 +629,7 @@ func expectedDeploymentParams() deployment.Params {
 					Resources: DefaultResources,
 				}},
 				AutomountServiceAccountToken: &falseVal,
+				Affinity:                     defaultAffinity("test"),
 			},
 		},
 	}
@@ -698,9 +734,10 @@ func defaultInitialObjects() []client.Object {
 
 func TestNewService(t *testing.T) {
 	testCases := []struct {
-		name     string
-		httpConf commonv1.HTTPConfig
-		wantSvc  func() corev1.Service
+		name            string
+		httpConf        commonv1.HTTPConfig
+		metricsExporter *kbv1.MetricsExporter
+		wantSvc         func() corev1.Service
 	}{
 		{
 			name: "no TLS",
@@ -765,6 +802,40 @@ func TestNewService(t *testing.T) {
 				return svc
 			},
 		},
+		{
+			name: "service template labels take precedence over operator-managed labels",
+			httpConf: commonv1.HTTPConfig{
+				Service: commonv1.ServiceTemplate{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"foo":                       "bar",
+							kblabel.KibanaNameLabelName: "another-kibana",
+						},
+					},
+				},
+			},
+			wantSvc: func() corev1.Service {
+				svc := mkService()
+				svc.Labels["foo"] = "bar"
+				svc.Labels[kblabel.KibanaNameLabelName] = "another-kibana"
+				svc.Spec.Ports[0].Name = "https"
+				return svc
+			},
+		},
+		{
+			name:            "metrics exporter",
+			metricsExporter: &kbv1.MetricsExporter{Image: "registry.internal/kibana-exporter:1.0.0", Port: 9100},
+			wantSvc: func() corev1.Service {
+				svc := mkService()
+				svc.Spec.Ports[0].Name = "https"
+				svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
+					Name:     MetricsExporterPortName,
+					Protocol: corev1.ProtocolTCP,
+					Port:     9100,
+				})
+				return svc
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -775,10 +846,11 @@ func TestNewService(t *testing.T) {
 					Namespace: "test",
 				},
 				Spec: kbv1.KibanaSpec{
-					HTTP: tc.httpConf,
+					HTTP:            tc.httpConf,
+					MetricsExporter: tc.metricsExporter,
 				},
 			}
-			haveSvc := NewService(kb)
+			haveSvc := NewService(context.Background(), kb)
 			compare.JSONEqual(t, tc.wantSvc(), haveSvc)
 		})
 	}
@@ -0,0 +1,68 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	// ElasticsearchCredentialsTokenKey is the entry of the user-provided credentials Secret holding a service account token.
+	ElasticsearchCredentialsTokenKey = "token"
+	// ElasticsearchCredentialsUsernameKey is the entry of the user-provided credentials Secret holding a username.
+	ElasticsearchCredentialsUsernameKey = "username"
+	// ElasticsearchCredentialsPasswordKey is the entry of the user-provided credentials Secret holding a password.
+	ElasticsearchCredentialsPasswordKey = "password"
+)
+
+// elasticsearchCredentialsWatchName returns the name of the watch set on the user-provided Elasticsearch credentials Secret.
+func elasticsearchCredentialsWatchName(kb types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-es-credentials", kb.Namespace, kb.Name)
+}
+
+// watchElasticsearchCredentials watches the user-provided Elasticsearch credentials Secret, if any, to update the
+// configuration of Kibana when the credentials change.
+func watchElasticsearchCredentials(kb kbv1.Kibana, dynamicWatches watches.DynamicWatches) error {
+	var secrets []string
+	if kb.Spec.ElasticsearchCredentialsSecretName != "" {
+		secrets = append(secrets, kb.Spec.ElasticsearchCredentialsSecretName)
+	}
+	return watches.WatchUserProvidedSecrets(k8s.ExtractNamespacedName(&kb), dynamicWatches, elasticsearchCredentialsWatchName(k8s.ExtractNamespacedName(&kb)), secrets)
+}
+
+// elasticsearchCredentials returns the credentials Kibana uses to authenticate to Elasticsearch. Credentials from the
+// user-provided Secret take precedence over the ones of the user created through the association.
+func elasticsearchCredentials(ctx context.Context, client k8s.Client, kb kbv1.Kibana) (association.Credentials, error) {
+	if kb.Spec.ElasticsearchCredentialsSecretName == "" {
+		return association.ElasticsearchAuthSettings(ctx, client, kb.EsAssociation())
+	}
+
+	var secret corev1.Secret
+	if err := client.Get(ctx, types.NamespacedName{Namespace: kb.Namespace, Name: kb.Spec.ElasticsearchCredentialsSecretName}, &secret); err != nil {
+		return association.Credentials{}, err
+	}
+	if token, exists := secret.Data[ElasticsearchCredentialsTokenKey]; exists {
+		return association.Credentials{ServiceAccountToken: string(token)}, nil
+	}
+	username, hasUsername := secret.Data[ElasticsearchCredentialsUsernameKey]
+	password, hasPassword := secret.Data[ElasticsearchCredentialsPasswordKey]
+	if !hasUsername || !hasPassword {
+		return association.Credentials{}, errors.Errorf(
+			"Elasticsearch credentials secret %s/%s must contain either a %s entry, or %s and %s entries",
+			secret.Namespace, secret.Name, ElasticsearchCredentialsTokenKey, ElasticsearchCredentialsUsernameKey, ElasticsearchCredentialsPasswordKey,
+		)
+	}
+	return association.Credentials{Username: string(username), Password: string(password)}, nil
+}
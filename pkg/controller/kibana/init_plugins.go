@@ -0,0 +1,63 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+)
+
+const (
+	InitPluginsContainerName = "elastic-internal-init-plugins"
+
+	PluginsVolumeName      = "kibana-plugins"
+	PluginsVolumeMountPath = "/usr/share/kibana/plugins"
+
+	// initPluginsScript is a small bash script to install the Kibana plugins listed in the Kibana specification.
+	// The plugins to install are passed as arguments to the script.
+	initPluginsScript = `#!/usr/bin/env bash
+set -eux
+
+init_plugins_installed_flag=` + PluginsVolumeMountPath + `/elastic-internal-init-plugins.ok
+
+if [[ -f "${init_plugins_installed_flag}" ]]; then
+    echo "Kibana plugins already installed."
+	exit 0
+fi
+
+for plugin in "$@"; do
+    echo "Installing Kibana plugin ${plugin}"
+    /usr/share/kibana/bin/kibana-plugin install "${plugin}"
+done
+
+touch "${init_plugins_installed_flag}"
+echo "Kibana plugins successfully installed."
+`
+)
+
+// PluginsVolume is used to share the plugins installed by the init container with Kibana running in the main container.
+var PluginsVolume = volume.NewEmptyDirVolume(PluginsVolumeName, PluginsVolumeMountPath)
+
+// initPluginsContainer returns an init container that installs the Kibana plugins listed in the Kibana specification
+// into the plugins volume, later mounted in /usr/share/kibana/plugins in the main container.
+func initPluginsContainer(kb kbv1.Kibana) corev1.Container {
+	privileged := false
+
+	return corev1.Container{
+		// Image will be inherited from pod template defaults
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            InitPluginsContainerName,
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: &privileged,
+		},
+		// the script name ($0) is followed by the plugins to install ($@)
+		Command: append([]string{"/usr/bin/env", "bash", "-c", initPluginsScript, InitPluginsContainerName}, kb.Spec.Plugins...),
+		VolumeMounts: []corev1.VolumeMount{
+			PluginsVolume.VolumeMount(),
+		},
+	}
+}
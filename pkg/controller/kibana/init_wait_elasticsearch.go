@@ -0,0 +1,143 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	InitWaitForElasticsearchContainerName = "elastic-internal-wait-for-elasticsearch"
+
+	EnvWaitForElasticsearchURL            = "ES_URL"
+	EnvWaitForElasticsearchUsername       = "ES_USERNAME"
+	EnvWaitForElasticsearchPassword       = "ES_PASSWORD" //nolint:gosec
+	EnvWaitForElasticsearchToken          = "ES_TOKEN"    //nolint:gosec
+	EnvWaitForElasticsearchTimeoutSeconds = "ES_WAIT_TIMEOUT_SECONDS"
+
+	// waitForElasticsearchScript is a small bash script polling the health endpoint of Elasticsearch until it answers,
+	// or until the timeout is reached, in which case the script exits with an error.
+	waitForElasticsearchScript = `#!/usr/bin/env bash
+set -u
+
+ca_path=` + esCertsVolumeMountPath + `/` + certificates.CAFileName + `
+deadline=$(( $(date +%s) + ${` + EnvWaitForElasticsearchTimeoutSeconds + `} ))
+
+curl_opts=(--silent --fail --output /dev/null --max-time 10)
+if [[ -n "${` + EnvWaitForElasticsearchToken + `:-}" ]]; then
+    curl_opts+=(--header "Authorization: Bearer ${` + EnvWaitForElasticsearchToken + `}")
+elif [[ -n "${` + EnvWaitForElasticsearchUsername + `:-}" ]]; then
+    curl_opts+=(--user "${` + EnvWaitForElasticsearchUsername + `}:${` + EnvWaitForElasticsearchPassword + `:-}")
+fi
+if [[ -f "${ca_path}" ]]; then
+    curl_opts+=(--cacert "${ca_path}")
+fi
+
+echo "Waiting for Elasticsearch at ${` + EnvWaitForElasticsearchURL + `}"
+until curl "${curl_opts[@]}" "${` + EnvWaitForElasticsearchURL + `}/_cluster/health"; do
+    if (( $(date +%s) >= deadline )); then
+        echo "Elasticsearch not reachable after ${` + EnvWaitForElasticsearchTimeoutSeconds + `} seconds, giving up."
+        exit 1
+    fi
+    sleep 5
+done
+echo "Elasticsearch is reachable."
+`
+)
+
+// initWaitForElasticsearchContainer returns an init container that waits for the associated Elasticsearch cluster to be
+// reachable, using the CA and the credentials Kibana uses to connect to it.
+// It returns nil if waiting for Elasticsearch is not enabled, or if Kibana is not associated with Elasticsearch.
+func initWaitForElasticsearchContainer(ctx context.Context, client k8s.Client, kb kbv1.Kibana) (*corev1.Container, error) {
+	if !kb.Spec.WaitForElasticsearch.Enabled {
+		return nil, nil
+	}
+	esAssocConf, err := kb.EsAssociation().AssociationConf()
+	if err != nil {
+		return nil, err
+	}
+	if !esAssocConf.IsConfigured() {
+		return nil, nil
+	}
+
+	env := []corev1.EnvVar{
+		{Name: EnvWaitForElasticsearchURL, Value: esAssocConf.GetURL()},
+		{Name: EnvWaitForElasticsearchTimeoutSeconds, Value: strconv.Itoa(int(kb.Spec.WaitForElasticsearch.GetTimeout().Seconds()))},
+	}
+	credentialsEnv, err := waitForElasticsearchCredentialsEnv(ctx, client, kb, *esAssocConf)
+	if err != nil {
+		return nil, err
+	}
+	env = append(env, credentialsEnv...)
+
+	var volumeMounts []corev1.VolumeMount
+	if esAssocConf.CAIsConfigured() {
+		volumeMounts = append(volumeMounts, esCaCertSecretVolume(*esAssocConf).VolumeMount())
+	}
+
+	privileged := false
+	return &corev1.Container{
+		// Image will be inherited from pod template defaults
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            InitWaitForElasticsearchContainerName,
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: &privileged,
+		},
+		Command:      []string{"/usr/bin/env", "bash", "-c", waitForElasticsearchScript},
+		Env:          env,
+		VolumeMounts: volumeMounts,
+	}, nil
+}
+
+// waitForElasticsearchCredentialsEnv returns the environment variables holding the credentials Kibana uses to
+// authenticate to Elasticsearch. Secret values are referenced rather than copied into the pod spec.
+func waitForElasticsearchCredentialsEnv(ctx context.Context, client k8s.Client, kb kbv1.Kibana, esAssocConf commonv1.AssociationConf) ([]corev1.EnvVar, error) {
+	if kb.Spec.ElasticsearchCredentialsSecretName != "" {
+		// the user-provided Secret holds either a token, or a username and a password
+		return []corev1.EnvVar{
+			secretKeyRefEnvVar(EnvWaitForElasticsearchToken, kb.Spec.ElasticsearchCredentialsSecretName, ElasticsearchCredentialsTokenKey),
+			secretKeyRefEnvVar(EnvWaitForElasticsearchUsername, kb.Spec.ElasticsearchCredentialsSecretName, ElasticsearchCredentialsUsernameKey),
+			secretKeyRefEnvVar(EnvWaitForElasticsearchPassword, kb.Spec.ElasticsearchCredentialsSecretName, ElasticsearchCredentialsPasswordKey),
+		}, nil
+	}
+	if !esAssocConf.AuthIsConfigured() {
+		return nil, nil
+	}
+	if esAssocConf.IsServiceAccount {
+		return []corev1.EnvVar{secretKeyRefEnvVar(EnvWaitForElasticsearchToken, esAssocConf.AuthSecretName, esAssocConf.AuthSecretKey)}, nil
+	}
+	credentials, err := association.ElasticsearchAuthSettings(ctx, client, kb.EsAssociation())
+	if err != nil {
+		return nil, err
+	}
+	return []corev1.EnvVar{
+		{Name: EnvWaitForElasticsearchUsername, Value: credentials.Username},
+		secretKeyRefEnvVar(EnvWaitForElasticsearchPassword, esAssocConf.AuthSecretName, esAssocConf.AuthSecretKey),
+	}, nil
+}
+
+// secretKeyRefEnvVar returns an environment variable set from an optional Secret entry.
+func secretKeyRefEnvVar(name, secretName, key string) corev1.EnvVar {
+	optional := true
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+				Optional:             &optional,
+			},
+		},
+	}
+}
@@ -41,7 +41,7 @@ func newInitContainersParameters(kb *kbv1.Kibana) (keystore.InitContainerParamet
 	}
 
 	if kbVersion.GTE(keystoreInConfigDirVersion) {
-		parameters.KeystoreVolumePath = ConfigSharedVolume.ContainerMountPath
+		parameters.KeystoreVolumePath = configMountPath(*kb)
 	}
 
 	return parameters, nil
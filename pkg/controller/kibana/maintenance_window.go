@@ -0,0 +1,61 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// deploymentPaused returns true if the Kibana Deployment must be paused because the maintenance window is closed at the
+// given time, along with how long it takes for the window to open. A paused Deployment is still scaled, but changes to
+// its Pod template are only rolled out once it is resumed.
+// The Deployment is not paused when it is created, or while a rollout is in progress: pausing it in the middle of a
+// recreate rollout could leave Kibana without any Pod.
+func deploymentPaused(ctx context.Context, c k8s.Client, kb kbv1.Kibana, now time.Time) (bool, time.Duration, error) {
+	window := kb.Spec.MaintenanceWindow
+	if window == nil || len(window.Windows) == 0 {
+		return false, 0, nil
+	}
+	open, untilOpen, err := window.Check(now)
+	if err != nil || open {
+		return false, 0, err
+	}
+	var existing appsv1.Deployment
+	err = c.Get(ctx, types.NamespacedName{Namespace: kb.Namespace, Name: kbv1.KBNamer.Suffix(kb.Name)}, &existing)
+	if apierrors.IsNotFound(err) {
+		return false, 0, nil
+	}
+	if err != nil {
+		return false, 0, err
+	}
+	if !existing.Spec.Paused && !rolloutComplete(existing) {
+		return false, 0, nil
+	}
+	ulog.FromContext(ctx).V(1).Info(
+		"Kibana Deployment paused until the next maintenance window", "namespace", kb.Namespace, "kibana_name", kb.Name, "until_open", untilOpen,
+	)
+	return true, untilOpen, nil
+}
+
+// rolloutComplete returns true if all the Pods of the given Deployment run its latest Pod template and are available.
+func rolloutComplete(d appsv1.Deployment) bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.Replicas == replicas &&
+		d.Status.UpdatedReplicas == replicas &&
+		d.Status.AvailableReplicas == replicas
+}
@@ -0,0 +1,107 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_deploymentPaused(t *testing.T) {
+	nightly := &commonv1.MaintenanceWindow{Windows: []commonv1.TimeWindow{
+		{Start: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+	}}
+	inWindow := time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC)
+	outsideWindow := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	newDeployment := func(paused bool, status appsv1.DeploymentStatus) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2), Paused: paused},
+			Status:     status,
+		}
+	}
+	rolledOut := appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+	rollingOut := appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 1, AvailableReplicas: 2}
+
+	tests := []struct {
+		name          string
+		window        *commonv1.MaintenanceWindow
+		now           time.Time
+		existing      *appsv1.Deployment
+		wantPaused    bool
+		wantUntilOpen time.Duration
+	}{
+		{
+			name:     "no maintenance window",
+			now:      outsideWindow,
+			existing: newDeployment(false, rolledOut),
+		},
+		{
+			name:     "inside the maintenance window",
+			window:   nightly,
+			now:      inWindow,
+			existing: newDeployment(false, rolledOut),
+		},
+		{
+			name:     "inside the maintenance window: paused Deployment is resumed",
+			window:   nightly,
+			now:      inWindow,
+			existing: newDeployment(true, rolledOut),
+		},
+		{
+			name:          "outside the maintenance window: rollouts are deferred",
+			window:        nightly,
+			now:           outsideWindow,
+			existing:      newDeployment(false, rolledOut),
+			wantPaused:    true,
+			wantUntilOpen: 10 * time.Hour,
+		},
+		{
+			name:          "outside the maintenance window: Deployment stays paused",
+			window:        nightly,
+			now:           outsideWindow,
+			existing:      newDeployment(true, rollingOut),
+			wantPaused:    true,
+			wantUntilOpen: 10 * time.Hour,
+		},
+		{
+			name:     "outside the maintenance window: rollout in progress is not interrupted",
+			window:   nightly,
+			now:      outsideWindow,
+			existing: newDeployment(false, rollingOut),
+		},
+		{
+			name:   "outside the maintenance window: Deployment is created",
+			window: nightly,
+			now:    outsideWindow,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := kbv1.Kibana{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+				Spec:       kbv1.KibanaSpec{MaintenanceWindow: tt.window},
+			}
+			c := k8s.NewFakeClient()
+			if tt.existing != nil {
+				c = k8s.NewFakeClient(tt.existing)
+			}
+			paused, untilOpen, err := deploymentPaused(context.Background(), c, kb, tt.now)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPaused, paused)
+			require.Equal(t, tt.wantUntilOpen, untilOpen)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/network"
+)
+
+const (
+	MetricsExporterContainerName = "metrics-exporter"
+	MetricsExporterPortName      = "metrics"
+
+	// EnvKibanaURL is the environment variable holding the URL the metrics exporter reads the Kibana status API from.
+	EnvKibanaURL = "KIBANA_URL"
+	// EnvMetricsPort is the environment variable holding the port the metrics exporter serves metrics on.
+	EnvMetricsPort = "METRICS_PORT"
+	// EnvKibanaUsername is the environment variable holding the username the metrics exporter authenticates with.
+	EnvKibanaUsername = "KIBANA_USERNAME"
+	// EnvKibanaPassword is the environment variable holding the password the metrics exporter authenticates with.
+	EnvKibanaPassword = "KIBANA_PASSWORD" //nolint:gosec
+	// EnvKibanaToken is the environment variable holding the bearer token the metrics exporter authenticates with.
+	EnvKibanaToken = "KIBANA_TOKEN" //nolint:gosec
+
+	// MetricsExporterCredentialsUsernameKey is the entry of the metrics exporter credentials Secret holding a username.
+	MetricsExporterCredentialsUsernameKey = "username"
+	// MetricsExporterCredentialsPasswordKey is the entry of the metrics exporter credentials Secret holding a password.
+	MetricsExporterCredentialsPasswordKey = "password"
+	// MetricsExporterCredentialsTokenKey is the entry of the metrics exporter credentials Secret holding a token.
+	MetricsExporterCredentialsTokenKey = "token"
+)
+
+var defaultMetricsExporterResources = corev1.ResourceRequirements{
+	Requests: map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceMemory: resource.MustParse("64Mi"),
+	},
+	Limits: map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceMemory: resource.MustParse("64Mi"),
+	},
+}
+
+// metricsExporterContainer returns a sidecar container that translates the Kibana status API into Prometheus metrics.
+// The exporter authenticates to the Kibana status API with the user-provided credentials, if any.
+// The container reports ready only once it serves metrics, so that the Pod becomes ready when both Kibana and the
+// exporter are ready.
+func metricsExporterContainer(kb kbv1.Kibana, exporter kbv1.MetricsExporter) corev1.Container {
+	port := exporter.MetricsPort()
+	env := []corev1.EnvVar{
+		{Name: EnvKibanaURL, Value: fmt.Sprintf("%s://127.0.0.1:%d", kb.Spec.HTTP.Protocol(), network.HTTPPort)},
+		{Name: EnvMetricsPort, Value: strconv.Itoa(int(port))},
+	}
+	if exporter.CredentialsSecretName != "" {
+		// the user-provided Secret holds either a token, or a username and a password
+		env = append(env,
+			secretKeyRefEnvVar(EnvKibanaToken, exporter.CredentialsSecretName, MetricsExporterCredentialsTokenKey),
+			secretKeyRefEnvVar(EnvKibanaUsername, exporter.CredentialsSecretName, MetricsExporterCredentialsUsernameKey),
+			secretKeyRefEnvVar(EnvKibanaPassword, exporter.CredentialsSecretName, MetricsExporterCredentialsPasswordKey),
+		)
+	}
+	return corev1.Container{
+		Name:  MetricsExporterContainerName,
+		Image: exporter.Image,
+		Env:   env,
+		Ports: []corev1.ContainerPort{
+			{Name: MetricsExporterPortName, ContainerPort: port, Protocol: corev1.ProtocolTCP},
+		},
+		ReadinessProbe: &corev1.Probe{
+			FailureThreshold: 3,
+			PeriodSeconds:    10,
+			SuccessThreshold: 1,
+			TimeoutSeconds:   5,
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{
+					Port: intstr.FromInt(int(port)),
+				},
+			},
+		},
+		Resources: defaultMetricsExporterResources,
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/pdb"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+// reconcilePDB ensures that a PodDisruptionBudget exists for this Kibana, inheriting the spec content.
+// The default PDB adapts MinAvailable to the number of Kibana instances.
+// If the spec has disabled the default PDB, it will ensure none exist.
+func reconcilePDB(ctx context.Context, k8sClient k8s.Client, kb kbv1.Kibana) error {
+	expected, err := expectedPDB(kb)
+	if err != nil {
+		return err
+	}
+	if expected == nil {
+		return pdb.DeleteIfExists(ctx, k8sClient, types.NamespacedName{Namespace: kb.Namespace, Name: kbv1.DefaultPodDisruptionBudget(kb.Name)})
+	}
+	return pdb.ReconcileResource(ctx, k8sClient, &kb, expected)
+}
+
+// expectedPDB returns a PDB according to the given Kibana spec.
+// It may return nil if the PDB has been explicitly disabled in the Kibana spec.
+func expectedPDB(kb kbv1.Kibana) (*policyv1.PodDisruptionBudget, error) {
+	template := kb.Spec.PodDisruptionBudget.DeepCopy()
+	if template.IsDisabled() {
+		return nil, nil
+	}
+	if template == nil {
+		template = &commonv1.PodDisruptionBudgetTemplate{}
+	}
+
+	expected := policyv1.PodDisruptionBudget{
+		ObjectMeta: template.ObjectMeta,
+	}
+
+	// inherit user-provided ObjectMeta, but set our own name & namespace
+	expected.Name = kbv1.DefaultPodDisruptionBudget(kb.Name)
+	expected.Namespace = kb.Namespace
+	// and append our labels
+	expected.Labels = maps.MergePreservingExistingKeys(expected.Labels, kb.GetIdentityLabels())
+	// set owner reference for deletion upon Kibana resource deletion
+	if err := controllerutil.SetControllerReference(&kb, &expected, scheme.Scheme); err != nil {
+		return nil, err
+	}
+
+	if template.Spec.Selector != nil || template.Spec.MaxUnavailable != nil || template.Spec.MinAvailable != nil {
+		// use the user-defined spec
+		expected.Spec = template.Spec
+	} else {
+		// set our default spec
+		expected.Spec = buildPDBSpec(kb)
+	}
+
+	return &expected, nil
+}
+
+// buildPDBSpec returns a PDBSpec allowing a single Kibana instance to be disrupted at a time.
+// A single instance can be disrupted as well, to not block Kubernetes nodes operations.
+func buildPDBSpec(kb kbv1.Kibana) policyv1.PodDisruptionBudgetSpec {
+	minAvailable := kb.Spec.Count - 1
+	if minAvailable < 0 {
+		minAvailable = 0
+	}
+	minAvailableIntStr := intstr.FromInt32(minAvailable)
+
+	return policyv1.PodDisruptionBudgetSpec{
+		// match all pods for this Kibana
+		Selector: &metav1.LabelSelector{
+			MatchLabels: kb.GetIdentityLabels(),
+		},
+		MinAvailable: &minAvailableIntStr,
+	}
+}
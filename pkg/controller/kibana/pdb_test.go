@@ -0,0 +1,114 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_reconcilePDB(t *testing.T) {
+	kibana := func(count int32, pdb *commonv1.PodDisruptionBudgetTemplate) kbv1.Kibana {
+		return kbv1.Kibana{
+			ObjectMeta: metav1.ObjectMeta{Name: "kb", Namespace: "ns"},
+			Spec:       kbv1.KibanaSpec{Count: count, PodDisruptionBudget: pdb},
+		}
+	}
+	defaultSpec := func(minAvailable int) *policyv1.PodDisruptionBudgetSpec {
+		return &policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				"common.k8s.elastic.co/type": "kibana",
+				"kibana.k8s.elastic.co/name": "kb",
+			}},
+			MinAvailable: ptr.To(intstr.FromInt(minAvailable)),
+		}
+	}
+	existingPDB := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "kb-kb-default", Namespace: "ns"},
+		Spec:       *defaultSpec(2),
+	}
+	tests := []struct {
+		name     string
+		initObjs []client.Object
+		kb       kbv1.Kibana
+		wantSpec *policyv1.PodDisruptionBudgetSpec
+	}{
+		{
+			name:     "no instance",
+			kb:       kibana(0, nil),
+			wantSpec: defaultSpec(0),
+		},
+		{
+			name:     "single instance: allow it to be disrupted",
+			kb:       kibana(1, nil),
+			wantSpec: defaultSpec(0),
+		},
+		{
+			name:     "two instances",
+			kb:       kibana(2, nil),
+			wantSpec: defaultSpec(1),
+		},
+		{
+			name:     "three instances",
+			kb:       kibana(3, nil),
+			wantSpec: defaultSpec(2),
+		},
+		{
+			name:     "existing PDB is updated on scale up",
+			initObjs: []client.Object{existingPDB.DeepCopy()},
+			kb:       kibana(5, nil),
+			wantSpec: defaultSpec(4),
+		},
+		{
+			name: "user-provided spec",
+			kb: kibana(3, &commonv1.PodDisruptionBudgetTemplate{
+				Spec: policyv1.PodDisruptionBudgetSpec{MaxUnavailable: ptr.To(intstr.FromInt(2))},
+			}),
+			wantSpec: &policyv1.PodDisruptionBudgetSpec{MaxUnavailable: ptr.To(intstr.FromInt(2))},
+		},
+		{
+			name:     "disabled: no PDB",
+			kb:       kibana(3, &commonv1.PodDisruptionBudgetTemplate{}),
+			wantSpec: nil,
+		},
+		{
+			name:     "disabled: existing PDB is deleted",
+			initObjs: []client.Object{existingPDB.DeepCopy()},
+			kb:       kibana(3, &commonv1.PodDisruptionBudgetTemplate{}),
+			wantSpec: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := k8s.NewFakeClient(tt.initObjs...)
+			require.NoError(t, reconcilePDB(context.Background(), k8sClient, tt.kb))
+
+			var retrieved policyv1.PodDisruptionBudget
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "kb-kb-default"}, &retrieved)
+			if tt.wantSpec == nil {
+				require.True(t, apierrors.IsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, *tt.wantSpec, retrieved.Spec)
+			require.Equal(t, "kb", retrieved.Labels["kibana.k8s.elastic.co/name"])
+			require.Len(t, retrieved.OwnerReferences, 1)
+			require.Equal(t, "kb", retrieved.OwnerReferences[0].Name)
+		})
+	}
+}
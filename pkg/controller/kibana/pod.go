@@ -6,10 +6,15 @@ package kibana
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
@@ -18,6 +23,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/pod"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	kblabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/label"
@@ -28,6 +34,15 @@ import (
 const (
 	DataVolumeName      = "kibana-data"
 	DataVolumeMountPath = "/usr/share/kibana/data"
+
+	// defaultUser and defaultFsGroup are the user and group of the kibana user of the Kibana Docker image.
+	defaultUser    = 1000
+	defaultFsGroup = 1000
+
+	// basePathEnvName and rewriteBasePathEnvName are the environment variables of the Kibana container setting
+	// server.basePath and server.rewriteBasePath.
+	basePathEnvName        = "SERVER_BASEPATH"
+	rewriteBasePathEnvName = "SERVER_REWRITEBASEPATH"
 )
 
 var (
@@ -50,10 +65,58 @@ var (
 	DefaultAnnotations = map[string]string{
 		annotation.FilebeatModuleAnnotation: "kibana",
 	}
+
+	// DefaultSecurityContext is the default security context of the Kibana pods. The Kibana process runs as the non-root
+	// kibana user, and mounted volumes are owned by its group so that Kibana can write to them.
+	DefaultSecurityContext = corev1.PodSecurityContext{
+		RunAsNonRoot: ptr.To[bool](true),
+		RunAsUser:    ptr.To[int64](defaultUser),
+		FSGroup:      ptr.To[int64](defaultFsGroup),
+	}
 )
 
+// basePathSettings are the Kibana settings defining the path prefix of the Kibana endpoints.
+type basePathSettings struct {
+	BasePath        string `config:"server.basePath"`
+	RewriteBasePath bool   `config:"server.rewriteBasePath"`
+}
+
+// GetKibanaBasePath returns the path prefix under which Kibana serves its endpoints, which is server.basePath if
+// server.rewriteBasePath is enabled, or an empty string otherwise. The environment variables of the Kibana container
+// take precedence over the Kibana configuration, as they do in Kibana.
+func GetKibanaBasePath(kb kbv1.Kibana) (string, error) {
+	var basePath basePathSettings
+	if kb.Spec.Config != nil {
+		cfg, err := settings.NewCanonicalConfigFrom(kb.Spec.Config.Data)
+		if err != nil {
+			return "", err
+		}
+		if err := cfg.Unpack(&basePath); err != nil {
+			return "", err
+		}
+	}
+	if kibanaContainer := pod.ContainerByName(kb.Spec.PodTemplate.Spec, kbv1.KibanaContainerName); kibanaContainer != nil {
+		for _, env := range kibanaContainer.Env {
+			switch env.Name {
+			case basePathEnvName:
+				basePath.BasePath = env.Value
+			case rewriteBasePathEnvName:
+				rewrite, err := strconv.ParseBool(env.Value)
+				if err != nil {
+					return "", fmt.Errorf("while parsing %s: %w", rewriteBasePathEnvName, err)
+				}
+				basePath.RewriteBasePath = rewrite
+			}
+		}
+	}
+	if !basePath.RewriteBasePath {
+		return "", nil
+	}
+	return strings.TrimSuffix(basePath.BasePath, "/"), nil
+}
+
 // readinessProbe is the readiness probe for the Kibana container
-func readinessProbe(useTLS bool) corev1.Probe {
+func readinessProbe(useTLS bool, basePath string) corev1.Probe {
 	scheme := corev1.URISchemeHTTP
 	if useTLS {
 		scheme = corev1.URISchemeHTTPS
@@ -67,14 +130,72 @@ func readinessProbe(useTLS bool) corev1.Probe {
 		ProbeHandler: corev1.ProbeHandler{
 			HTTPGet: &corev1.HTTPGetAction{
 				Port:   intstr.FromInt(network.HTTPPort),
-				Path:   "/login",
+				Path:   basePath + "/login",
 				Scheme: scheme,
 			},
 		},
 	}
 }
 
-func NewPodTemplateSpec(ctx context.Context, client k8sclient.Client, kb kbv1.Kibana, keystore *keystore.Resources, volumes []volume.VolumeLike) (corev1.PodTemplateSpec, error) {
+// defaultAffinity returns the default affinity for the Kibana pods, to prefer to avoid two pods of the same
+// Kibana instance being co-located on a single node.
+func defaultAffinity(kbName string) *corev1.Affinity {
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: 100,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						TopologyKey: "kubernetes.io/hostname",
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								kblabel.KibanaNameLabelName: kbName,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// livenessProbeScript checks that Kibana answers HTTP requests on its status endpoint. Any HTTP status code is accepted,
+// since the status endpoint may require authentication: the probe is only meant to detect an unresponsive process.
+const livenessProbeScript = `status=$(curl -o /dev/null -w "%%{http_code}" --max-time %d -k -s '%s://127.0.0.1:%d%s/api/status')
+if [[ $? -ne 0 ]] || [[ ${status} == "000" ]]; then
+  echo "Kibana did not answer on the status endpoint"
+  exit 1
+fi`
+
+// livenessProbe is the liveness probe for the Kibana container
+func livenessProbe(useTLS bool, basePath string) corev1.Probe {
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+	timeout := int32(5)
+	return corev1.Probe{
+		FailureThreshold:    6,
+		InitialDelaySeconds: 60,
+		PeriodSeconds:       10,
+		SuccessThreshold:    1,
+		TimeoutSeconds:      timeout,
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"bash", "-c", fmt.Sprintf(livenessProbeScript, timeout, scheme, network.HTTPPort, strings.ReplaceAll(basePath, "'", `'\''`))},
+			},
+		},
+	}
+}
+
+func NewPodTemplateSpec(
+	ctx context.Context,
+	client k8sclient.Client,
+	kb kbv1.Kibana,
+	keystore *keystore.Resources,
+	volumes []volume.VolumeLike,
+	setDefaultSecurityContext bool,
+) (corev1.PodTemplateSpec, error) {
 	labels := kb.GetIdentityLabels()
 	labels[kblabel.KibanaVersionLabelName] = kb.Spec.Version
 
@@ -83,16 +204,64 @@ func NewPodTemplateSpec(ctx context.Context, client k8sclient.Client, kb kbv1.Ki
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	basePath, err := GetKibanaBasePath(kb)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	builder := defaults.NewPodTemplateBuilder(kb.Spec.PodTemplate, kbv1.KibanaContainerName).
 		WithResources(DefaultResources).
 		WithLabels(labels).
 		WithAnnotations(DefaultAnnotations).
 		WithDockerImage(kb.Spec.Image, container.ImageRepository(container.KibanaImage, v)).
-		WithReadinessProbe(readinessProbe(kb.Spec.HTTP.TLS.Enabled())).
+		WithMergedReadinessProbe(readinessProbe(kb.Spec.HTTP.TLS.Enabled(), basePath)).
+		WithAffinity(defaultAffinity(kb.Name)).
 		WithPorts(ports).
 		WithInitContainers(initConfigContainer(kb))
 
+	// point Kibana to its configuration directory, if not the default one
+	configPathEnv := configPathEnvVars(kb, v)
+	builder.WithEnv(configPathEnv...)
+
+	if setDefaultSecurityContext {
+		builder.WithPodSecurityContext(DefaultSecurityContext)
+	}
+
+	if len(kb.Spec.Plugins) > 0 {
+		builder.WithVolumes(PluginsVolume.Volume()).
+			WithVolumeMounts(PluginsVolume.VolumeMount()).
+			WithInitContainers(initPluginsContainer(kb))
+	}
+
+	waitForElasticsearchContainer, err := initWaitForElasticsearchContainer(ctx, client, kb)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+	if waitForElasticsearchContainer != nil {
+		builder.WithInitContainers(*waitForElasticsearchContainer)
+	}
+
+	if kb.Spec.LivenessProbe.Enabled {
+		builder.WithMergedLivenessProbe(livenessProbe(kb.Spec.HTTP.TLS.Enabled(), basePath))
+	}
+
+	if kb.Spec.Reporting != nil {
+		shmVolume, shmVolumeMount := reportingSharedMemoryVolume(*kb.Spec.Reporting)
+		builder.WithVolumes(shmVolume).WithVolumeMounts(shmVolumeMount)
+	}
+
+	if kb.Spec.ConfigReloadStrategy == kbv1.ReloadConfigReloadStrategy {
+		// the config reloader signals the Kibana process, which must be visible from its container
+		builder.WithContainers(configReloaderContainer(kb, builder.MainContainer().Image))
+		if builder.PodTemplate.Spec.ShareProcessNamespace == nil {
+			builder.PodTemplate.Spec.ShareProcessNamespace = ptr.To(true)
+		}
+	}
+
+	if kb.Spec.MetricsExporter != nil {
+		builder.WithContainers(metricsExporterContainer(kb, *kb.Spec.MetricsExporter))
+	}
+
 	for _, volume := range volumes {
 		builder.WithVolumes(volume.Volume()).WithVolumeMounts(volume.VolumeMount())
 	}
@@ -107,12 +276,22 @@ func NewPodTemplateSpec(ctx context.Context, client k8sclient.Client, kb kbv1.Ki
 		return corev1.PodTemplateSpec{}, err
 	}
 
-	return builder.WithInitContainerDefaults().PodTemplate, nil
+	// init containers managing the keystore rely on the configuration directory as well
+	return builder.WithInitContainerDefaults(configPathEnv...).PodTemplate, nil
 }
 
 // GetKibanaContainer returns the Kibana container from the given podSpec.
+// If no container is named after the Kibana container, for example because it has been renamed by a mutating webhook,
+// the only container of the Pod is considered to be the Kibana container. Nil is returned if the Kibana container cannot
+// be identified.
 func GetKibanaContainer(podSpec corev1.PodSpec) *corev1.Container {
-	return pod.ContainerByName(podSpec, kbv1.KibanaContainerName)
+	if c := pod.ContainerByName(podSpec, kbv1.KibanaContainerName); c != nil {
+		return c
+	}
+	if len(podSpec.Containers) == 1 {
+		return &podSpec.Containers[0]
+	}
+	return nil
 }
 
 func getDefaultContainerPorts(kb kbv1.Kibana) []corev1.ContainerPort {
@@ -7,17 +7,21 @@ package kibana
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	commonpod "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/pod"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	commonvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	kblabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/label"
@@ -30,6 +34,7 @@ func TestNewPodTemplateSpec(t *testing.T) {
 		name       string
 		kb         kbv1.Kibana
 		keystore   *keystore.Resources
+		volumes    []commonvolume.VolumeLike
 		assertions func(pod corev1.PodTemplateSpec)
 	}{
 		{
@@ -118,6 +123,176 @@ func TestNewPodTemplateSpec(t *testing.T) {
 				}, GetKibanaContainer(pod.Spec).Resources)
 			},
 		},
+		{
+			name: "with user-provided readiness probe",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version: "7.1.0",
+				PodTemplate: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name: kbv1.KibanaContainerName,
+								ReadinessProbe: &corev1.Probe{
+									FailureThreshold:    5,
+									InitialDelaySeconds: 30,
+									PeriodSeconds:       20,
+									SuccessThreshold:    2,
+									TimeoutSeconds:      15,
+									ProbeHandler: corev1.ProbeHandler{
+										TCPSocket: &corev1.TCPSocketAction{
+											Port: intstr.FromInt(network.HTTPPort),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				assert.Equal(t, &corev1.Probe{
+					FailureThreshold:    5,
+					InitialDelaySeconds: 30,
+					PeriodSeconds:       20,
+					SuccessThreshold:    2,
+					TimeoutSeconds:      15,
+					ProbeHandler: corev1.ProbeHandler{
+						TCPSocket: &corev1.TCPSocketAction{
+							Port: intstr.FromInt(network.HTTPPort),
+						},
+					},
+				}, GetKibanaContainer(pod.Spec).ReadinessProbe)
+			},
+		},
+		{
+			name: "with user-provided readiness probe thresholds only",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version: "7.1.0",
+				PodTemplate: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name: kbv1.KibanaContainerName,
+								ReadinessProbe: &corev1.Probe{
+									InitialDelaySeconds: 60,
+									TimeoutSeconds:      30,
+								},
+							},
+						},
+					},
+				},
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				expected := readinessProbe(true, "")
+				expected.InitialDelaySeconds = 60
+				expected.TimeoutSeconds = 30
+				assert.Equal(t, &expected, GetKibanaContainer(pod.Spec).ReadinessProbe)
+			},
+		},
+		{
+			name: "with empty user-provided readiness probe",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version: "7.1.0",
+				PodTemplate: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name:           kbv1.KibanaContainerName,
+								ReadinessProbe: &corev1.Probe{},
+							},
+						},
+					},
+				},
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				expected := readinessProbe(true, "")
+				assert.Equal(t, &expected, GetKibanaContainer(pod.Spec).ReadinessProbe)
+			},
+		},
+		{
+			name: "without liveness probe by default",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version: "7.1.0",
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				kibanaContainer := GetKibanaContainer(pod.Spec)
+				assert.NotNil(t, kibanaContainer.ReadinessProbe)
+				assert.Nil(t, kibanaContainer.LivenessProbe)
+			},
+		},
+		{
+			name: "with liveness probe enabled",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version:       "7.1.0",
+				LivenessProbe: kbv1.LivenessProbe{Enabled: true},
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				kibanaContainer := GetKibanaContainer(pod.Spec)
+				assert.NotNil(t, kibanaContainer.ReadinessProbe)
+				expected := livenessProbe(true, "")
+				assert.Equal(t, &expected, kibanaContainer.LivenessProbe)
+				assert.Contains(t, kibanaContainer.LivenessProbe.Exec.Command[2], "https://127.0.0.1:5601/api/status")
+			},
+		},
+		{
+			name: "with liveness probe enabled and TLS disabled",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version:       "7.1.0",
+				LivenessProbe: kbv1.LivenessProbe{Enabled: true},
+				HTTP: commonv1.HTTPConfig{
+					TLS: commonv1.TLSOptions{
+						SelfSignedCertificate: &commonv1.SelfSignedCertificate{
+							Disabled: true,
+						},
+					},
+				},
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				kibanaContainer := GetKibanaContainer(pod.Spec)
+				assert.Equal(t, corev1.URISchemeHTTP, kibanaContainer.ReadinessProbe.HTTPGet.Scheme)
+				assert.Contains(t, kibanaContainer.LivenessProbe.Exec.Command[2], "http://127.0.0.1:5601/api/status")
+			},
+		},
+		{
+			name: "with liveness probe enabled and a rewritten base path",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version:       "7.1.0",
+				LivenessProbe: kbv1.LivenessProbe{Enabled: true},
+				Config: &commonv1.Config{Data: map[string]interface{}{
+					"server.basePath":        "/monitoring/kibana",
+					"server.rewriteBasePath": true,
+				}},
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				kibanaContainer := GetKibanaContainer(pod.Spec)
+				assert.Equal(t, "/monitoring/kibana/login", kibanaContainer.ReadinessProbe.HTTPGet.Path)
+				assert.Contains(t, kibanaContainer.LivenessProbe.Exec.Command[2], "'https://127.0.0.1:5601/monitoring/kibana/api/status'")
+			},
+		},
+		{
+			name: "with liveness probe enabled and user-provided thresholds",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version:       "7.1.0",
+				LivenessProbe: kbv1.LivenessProbe{Enabled: true},
+				PodTemplate: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name: kbv1.KibanaContainerName,
+								LivenessProbe: &corev1.Probe{
+									FailureThreshold: 10,
+								},
+							},
+						},
+					},
+				},
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				expected := livenessProbe(true, "")
+				expected.FailureThreshold = 10
+				assert.Equal(t, &expected, GetKibanaContainer(pod.Spec).LivenessProbe)
+			},
+		},
 		{
 			name: "with user-provided init containers",
 			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
@@ -139,6 +314,363 @@ func TestNewPodTemplateSpec(t *testing.T) {
 				assert.Equal(t, pod.Spec.Containers[0].Image, pod.Spec.InitContainers[1].Image)
 			},
 		},
+		{
+			name: "with user-provided volume and volume mount colliding with the config volume",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				PodTemplate: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name: kbv1.KibanaContainerName,
+								VolumeMounts: []corev1.VolumeMount{
+									{
+										Name:      ConfigSharedVolume.VolumeName,
+										MountPath: ConfigSharedVolume.ContainerMountPath,
+										ReadOnly:  true,
+									},
+								},
+							},
+						},
+						Volumes: []corev1.Volume{
+							{
+								Name: ConfigSharedVolume.VolumeName,
+								VolumeSource: corev1.VolumeSource{
+									ConfigMap: &corev1.ConfigMapVolumeSource{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "user-config"},
+									},
+								},
+							},
+						},
+					},
+				},
+				Version: "8.12.0",
+			}},
+			volumes: []commonvolume.VolumeLike{DataVolume, ConfigSharedVolume},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				var configVolumes []corev1.Volume
+				for _, v := range pod.Spec.Volumes {
+					if v.Name == ConfigSharedVolume.VolumeName {
+						configVolumes = append(configVolumes, v)
+					}
+				}
+				// the user-provided volume wins over the operator-managed one
+				require.Len(t, configVolumes, 1)
+				require.NotNil(t, configVolumes[0].ConfigMap)
+				assert.Equal(t, "user-config", configVolumes[0].ConfigMap.Name)
+				assert.Len(t, pod.Spec.Volumes, 2)
+
+				var configMounts []corev1.VolumeMount
+				for _, m := range GetKibanaContainer(pod.Spec).VolumeMounts {
+					if m.Name == ConfigSharedVolume.VolumeName {
+						configMounts = append(configMounts, m)
+					}
+				}
+				require.Len(t, configMounts, 1)
+				assert.True(t, configMounts[0].ReadOnly)
+			},
+		},
+		{
+			name: "with default affinity",
+			kb: kbv1.Kibana{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "kibana-name",
+				},
+				Spec: kbv1.KibanaSpec{
+					Version: "8.12.0",
+				}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				require.NotNil(t, pod.Spec.Affinity)
+				require.NotNil(t, pod.Spec.Affinity.PodAntiAffinity)
+				assert.Nil(t, pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+				terms := pod.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+				require.Len(t, terms, 1)
+				assert.Equal(t, "kubernetes.io/hostname", terms[0].PodAffinityTerm.TopologyKey)
+				assert.Equal(t,
+					map[string]string{kblabel.KibanaNameLabelName: "kibana-name"},
+					terms[0].PodAffinityTerm.LabelSelector.MatchLabels,
+				)
+			},
+		},
+		{
+			name: "with user-provided affinity",
+			kb: kbv1.Kibana{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "kibana-name",
+				},
+				Spec: kbv1.KibanaSpec{
+					PodTemplate: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Affinity: &corev1.Affinity{
+								NodeAffinity: &corev1.NodeAffinity{},
+							},
+						},
+					},
+					Version: "8.12.0",
+				}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				assert.Equal(t, &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}}, pod.Spec.Affinity)
+			},
+		},
+		{
+			name: "with user-provided service account name",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				PodTemplate: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						ServiceAccountName: "workload-identity",
+					},
+				},
+				Version: "8.12.0",
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				assert.Equal(t, "workload-identity", pod.Spec.ServiceAccountName)
+				require.NotNil(t, pod.Spec.AutomountServiceAccountToken)
+				assert.False(t, *pod.Spec.AutomountServiceAccountToken)
+			},
+		},
+		{
+			name: "with user-provided service account name and service account token mounted",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				PodTemplate: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						ServiceAccountName:           "workload-identity",
+						AutomountServiceAccountToken: ptr.To[bool](true),
+					},
+				},
+				Version: "8.12.0",
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				assert.Equal(t, "workload-identity", pod.Spec.ServiceAccountName)
+				require.NotNil(t, pod.Spec.AutomountServiceAccountToken)
+				assert.True(t, *pod.Spec.AutomountServiceAccountToken)
+			},
+		},
+		{
+			name: "with plugins and user-provided init containers",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				PodTemplate: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						InitContainers: []corev1.Container{
+							{
+								Name: "user-init-container",
+							},
+						},
+					},
+				},
+				Plugins: []string{"https://mirror.internal/kibana-plugin-a-8.12.0.zip", "plugin-b"},
+				Version: "8.12.0",
+			}},
+			keystore: nil,
+			assertions: func(pod corev1.PodTemplateSpec) {
+				require.Len(t, pod.Spec.InitContainers, 3)
+				// operator-managed init containers run before the user-provided ones
+				assert.Equal(t, InitPluginsContainerName, pod.Spec.InitContainers[0].Name)
+				assert.Equal(t, InitConfigContainerName, pod.Spec.InitContainers[1].Name)
+				assert.Equal(t, "user-init-container", pod.Spec.InitContainers[2].Name)
+
+				pluginsContainer := pod.Spec.InitContainers[0]
+				kibanaContainer := GetKibanaContainer(pod.Spec)
+				assert.Equal(t, kibanaContainer.Image, pluginsContainer.Image)
+				assert.Equal(t,
+					[]string{InitPluginsContainerName, "https://mirror.internal/kibana-plugin-a-8.12.0.zip", "plugin-b"},
+					pluginsContainer.Command[4:],
+				)
+				assert.Contains(t, pluginsContainer.VolumeMounts, PluginsVolume.VolumeMount())
+				assert.Contains(t, kibanaContainer.VolumeMounts, PluginsVolume.VolumeMount())
+				assert.Contains(t, pod.Spec.Volumes, PluginsVolume.Volume())
+			},
+		},
+		{
+			name: "without plugins",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version: "8.12.0",
+			}},
+			keystore: nil,
+			assertions: func(pod corev1.PodTemplateSpec) {
+				for _, c := range pod.Spec.InitContainers {
+					assert.NotEqual(t, InitPluginsContainerName, c.Name)
+				}
+				assert.NotContains(t, pod.Spec.Volumes, PluginsVolume.Volume())
+			},
+		},
+		{
+			name: "with reporting",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version:   "8.12.0",
+				Reporting: &kbv1.ReportingSettings{SharedMemorySize: ptr.To(resource.MustParse("512Mi"))},
+			}},
+			keystore: nil,
+			assertions: func(pod corev1.PodTemplateSpec) {
+				sizeLimit := resource.MustParse("512Mi")
+				assert.Contains(t, pod.Spec.Volumes, corev1.Volume{
+					Name: ReportingSharedMemoryVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory, SizeLimit: &sizeLimit},
+					},
+				})
+				assert.Contains(t, GetKibanaContainer(pod.Spec).VolumeMounts, corev1.VolumeMount{
+					Name:      ReportingSharedMemoryVolumeName,
+					MountPath: "/dev/shm",
+				})
+			},
+		},
+		{
+			name: "with reporting and the default shared memory size",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version:   "8.12.0",
+				Reporting: &kbv1.ReportingSettings{},
+			}},
+			keystore: nil,
+			assertions: func(pod corev1.PodTemplateSpec) {
+				for _, v := range pod.Spec.Volumes {
+					if v.Name == ReportingSharedMemoryVolumeName {
+						assert.Equal(t, kbv1.DefaultReportingSharedMemorySize, *v.EmptyDir.SizeLimit)
+						return
+					}
+				}
+				assert.Fail(t, "shared memory volume not found")
+			},
+		},
+		{
+			name: "without reporting",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version: "8.12.0",
+			}},
+			keystore: nil,
+			assertions: func(pod corev1.PodTemplateSpec) {
+				for _, v := range pod.Spec.Volumes {
+					assert.NotEqual(t, ReportingSharedMemoryVolumeName, v.Name)
+				}
+			},
+		},
+		{
+			name: "with metrics exporter and user-provided containers",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				PodTemplate: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name: "user-container",
+							},
+						},
+					},
+				},
+				MetricsExporter: &kbv1.MetricsExporter{
+					Image: "registry.internal/kibana-exporter:1.0.0",
+					Port:  9100,
+				},
+				Version: "8.12.0",
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				require.Len(t, pod.Spec.Containers, 3)
+				assert.NotNil(t, GetKibanaContainer(pod.Spec))
+				assert.NotNil(t, commonpod.ContainerByName(pod.Spec, "user-container"))
+
+				exporter := commonpod.ContainerByName(pod.Spec, MetricsExporterContainerName)
+				require.NotNil(t, exporter)
+				assert.Equal(t, "registry.internal/kibana-exporter:1.0.0", exporter.Image)
+				assert.Equal(t, []corev1.ContainerPort{{Name: MetricsExporterPortName, ContainerPort: 9100, Protocol: corev1.ProtocolTCP}}, exporter.Ports)
+				assert.Contains(t, exporter.Env, corev1.EnvVar{Name: EnvKibanaURL, Value: "https://127.0.0.1:5601"})
+				assert.Contains(t, exporter.Env, corev1.EnvVar{Name: EnvMetricsPort, Value: "9100"})
+				require.NotNil(t, exporter.ReadinessProbe)
+				assert.Equal(t, intstr.FromInt(9100), exporter.ReadinessProbe.TCPSocket.Port)
+				// the Kibana container ports are left untouched
+				assert.Equal(t, getDefaultContainerPorts(kbv1.Kibana{}), GetKibanaContainer(pod.Spec).Ports)
+			},
+		},
+		{
+			name: "with metrics exporter and default port",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				MetricsExporter: &kbv1.MetricsExporter{
+					Image: "registry.internal/kibana-exporter:1.0.0",
+				},
+				Version: "8.12.0",
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				exporter := commonpod.ContainerByName(pod.Spec, MetricsExporterContainerName)
+				require.NotNil(t, exporter)
+				assert.Equal(t, kbv1.DefaultMetricsExporterPort, exporter.Ports[0].ContainerPort)
+			},
+		},
+		{
+			name: "with metrics exporter overridden in the pod template",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				PodTemplate: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name: MetricsExporterContainerName,
+								Args: []string{"--kibana.skip-tls-verify"},
+							},
+						},
+					},
+				},
+				MetricsExporter: &kbv1.MetricsExporter{
+					Image: "registry.internal/kibana-exporter:1.0.0",
+				},
+				Version: "8.12.0",
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				require.Len(t, pod.Spec.Containers, 2)
+				exporter := commonpod.ContainerByName(pod.Spec, MetricsExporterContainerName)
+				require.NotNil(t, exporter)
+				assert.Equal(t, []string{"--kibana.skip-tls-verify"}, exporter.Args)
+				assert.Equal(t, "registry.internal/kibana-exporter:1.0.0", exporter.Image)
+			},
+		},
+		{
+			name: "with the reload config reload strategy",
+			kb: kbv1.Kibana{
+				ObjectMeta: metav1.ObjectMeta{Name: "kb"},
+				Spec: kbv1.KibanaSpec{
+					Version:              "8.12.0",
+					Image:                "my-kibana-image",
+					ConfigReloadStrategy: kbv1.ReloadConfigReloadStrategy,
+				},
+			},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				require.Len(t, pod.Spec.Containers, 2)
+				assert.Equal(t, ptr.To(true), pod.Spec.ShareProcessNamespace)
+				reloader := commonpod.ContainerByName(pod.Spec, ConfigReloaderContainerName)
+				require.NotNil(t, reloader)
+				assert.Equal(t, "my-kibana-image", reloader.Image)
+				assert.Equal(t, []corev1.VolumeMount{{Name: InternalConfigVolumeName, ReadOnly: true, MountPath: InternalConfigVolumeMountPath}}, reloader.VolumeMounts)
+				assert.Contains(t, reloader.Command[3], `config=/mnt/elastic-internal/kibana-config/kibana.yml`)
+				assert.Contains(t, reloader.Command[3], `kill -HUP "${pid%/cmdline}"`)
+			},
+		},
+		{
+			name: "with the reload config reload strategy and a user-provided process namespace setting",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version:              "8.12.0",
+				ConfigReloadStrategy: kbv1.ReloadConfigReloadStrategy,
+				PodTemplate:          corev1.PodTemplateSpec{Spec: corev1.PodSpec{ShareProcessNamespace: ptr.To(false)}},
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				assert.Equal(t, ptr.To(false), pod.Spec.ShareProcessNamespace)
+				assert.NotNil(t, commonpod.ContainerByName(pod.Spec, ConfigReloaderContainerName))
+			},
+		},
+		{
+			name: "with the restart config reload strategy",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version:              "8.12.0",
+				ConfigReloadStrategy: kbv1.RestartConfigReloadStrategy,
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				require.Len(t, pod.Spec.Containers, 1)
+				assert.Nil(t, pod.Spec.ShareProcessNamespace)
+			},
+		},
+		{
+			name: "without metrics exporter",
+			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version: "8.12.0",
+			}},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				require.Len(t, pod.Spec.Containers, 1)
+				assert.Nil(t, commonpod.ContainerByName(pod.Spec, MetricsExporterContainerName))
+			},
+		},
 		{
 			name:     "with user-provided labels",
 			keystore: nil,
@@ -225,7 +757,7 @@ func TestNewPodTemplateSpec(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewPodTemplateSpec(context.Background(), k8s.NewFakeClient(), tt.kb, tt.keystore, []commonvolume.VolumeLike{})
+			got, err := NewPodTemplateSpec(context.Background(), k8s.NewFakeClient(), tt.kb, tt.keystore, tt.volumes, false)
 			assert.NoError(t, err)
 			tt.assertions(got)
 		})
@@ -274,3 +806,430 @@ func Test_getDefaultContainerPorts(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPodTemplateSpec_Image(t *testing.T) {
+	tests := []struct {
+		name  string
+		image string
+		want  string
+	}{
+		{
+			name:  "default image",
+			image: "",
+			want:  container.ImageRepository(container.KibanaImage, version.MustParse("8.12.0")),
+		},
+		{
+			name:  "custom image with a tag",
+			image: "my-registry:5000/kibana/kibana:8.12.0-custom",
+			want:  "my-registry:5000/kibana/kibana:8.12.0-custom",
+		},
+		{
+			name:  "custom image without tag",
+			image: "my-registry:5000/kibana/kibana",
+			want:  "my-registry:5000/kibana/kibana",
+		},
+		{
+			name:  "custom image pinned by digest",
+			image: "docker.elastic.co/kibana/kibana@sha256:4ce6b02da950f0b8ed27ff0b48558b07de5d6d774c6bd6a6d30d45d9f2a7e5d5",
+			want:  "docker.elastic.co/kibana/kibana@sha256:4ce6b02da950f0b8ed27ff0b48558b07de5d6d774c6bd6a6d30d45d9f2a7e5d5",
+		},
+		{
+			name:  "custom image with a tag and pinned by digest",
+			image: "docker.elastic.co/kibana/kibana:8.12.0@sha256:4ce6b02da950f0b8ed27ff0b48558b07de5d6d774c6bd6a6d30d45d9f2a7e5d5",
+			want:  "docker.elastic.co/kibana/kibana:8.12.0@sha256:4ce6b02da950f0b8ed27ff0b48558b07de5d6d774c6bd6a6d30d45d9f2a7e5d5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := kbv1.Kibana{Spec: kbv1.KibanaSpec{Version: "8.12.0", Image: tt.image}}
+			got, err := NewPodTemplateSpec(context.Background(), k8s.NewFakeClient(), kb, nil, []commonvolume.VolumeLike{}, false)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, GetKibanaContainer(got.Spec).Image)
+			// init containers inherit the same image reference
+			for _, c := range got.Spec.InitContainers {
+				assert.Equal(t, tt.want, c.Image)
+			}
+		})
+	}
+}
+
+func TestNewPodTemplateSpec_SecurityContext(t *testing.T) {
+	userSecurityContext := &corev1.PodSecurityContext{
+		RunAsUser: ptr.To[int64](2000),
+		FSGroup:   ptr.To[int64](3000),
+	}
+	tests := []struct {
+		name                      string
+		podTemplate               corev1.PodTemplateSpec
+		setDefaultSecurityContext bool
+		want                      *corev1.PodSecurityContext
+	}{
+		{
+			name:                      "default security context",
+			setDefaultSecurityContext: true,
+			want: &corev1.PodSecurityContext{
+				RunAsNonRoot: ptr.To[bool](true),
+				RunAsUser:    ptr.To[int64](1000),
+				FSGroup:      ptr.To[int64](1000),
+			},
+		},
+		{
+			name:                      "default security context disabled",
+			setDefaultSecurityContext: false,
+			want:                      nil,
+		},
+		{
+			name:                      "user provided security context takes precedence",
+			podTemplate:               corev1.PodTemplateSpec{Spec: corev1.PodSpec{SecurityContext: userSecurityContext}},
+			setDefaultSecurityContext: true,
+			want:                      userSecurityContext,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := kbv1.Kibana{Spec: kbv1.KibanaSpec{Version: "8.12.0", PodTemplate: tt.podTemplate}}
+			got, err := NewPodTemplateSpec(context.Background(), k8s.NewFakeClient(), kb, nil, []commonvolume.VolumeLike{}, tt.setDefaultSecurityContext)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Spec.SecurityContext)
+		})
+	}
+}
+
+func TestNewPodTemplateSpec_WaitForElasticsearch(t *testing.T) {
+	authSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kibana-user"},
+		Data:       map[string][]byte{"ns-kb-kibana-user": []byte("password")},
+	}
+	kibana := func(waitForElasticsearch kbv1.WaitForElasticsearch, assocConf *commonv1.AssociationConf) kbv1.Kibana {
+		kb := kbv1.Kibana{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+			Spec: kbv1.KibanaSpec{
+				Version:              "8.12.0",
+				ElasticsearchRef:     commonv1.ObjectSelector{Name: "es"},
+				WaitForElasticsearch: waitForElasticsearch,
+			},
+		}
+		kb.EsAssociation().SetAssociationConf(assocConf)
+		return kb
+	}
+	assocConf := &commonv1.AssociationConf{
+		AuthSecretName: "kb-kibana-user",
+		AuthSecretKey:  "ns-kb-kibana-user",
+		CASecretName:   "kb-es-ca",
+		CACertProvided: true,
+		URL:            "https://es-es-http.ns.svc:9200",
+	}
+	passwordRef := &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "kb-kibana-user"},
+		Key:                  "ns-kb-kibana-user",
+		Optional:             ptr.To(true),
+	}}
+
+	tests := []struct {
+		name       string
+		kb         kbv1.Kibana
+		wantEnv    []corev1.EnvVar
+		wantMounts []corev1.VolumeMount
+	}{
+		{
+			name: "disabled",
+			kb:   kibana(kbv1.WaitForElasticsearch{}, assocConf),
+		},
+		{
+			name: "enabled without Elasticsearch association",
+			kb:   kibana(kbv1.WaitForElasticsearch{Enabled: true}, nil),
+		},
+		{
+			name: "enabled with Elasticsearch association",
+			kb:   kibana(kbv1.WaitForElasticsearch{Enabled: true}, assocConf),
+			wantEnv: []corev1.EnvVar{
+				{Name: EnvWaitForElasticsearchURL, Value: "https://es-es-http.ns.svc:9200"},
+				{Name: EnvWaitForElasticsearchTimeoutSeconds, Value: "300"},
+				{Name: EnvWaitForElasticsearchUsername, Value: "ns-kb-kibana-user"},
+				{Name: EnvWaitForElasticsearchPassword, ValueFrom: passwordRef},
+			},
+			wantMounts: []corev1.VolumeMount{esCaCertSecretVolume(*assocConf).VolumeMount()},
+		},
+		{
+			name: "enabled with custom timeout and service account token",
+			kb: kibana(
+				kbv1.WaitForElasticsearch{Enabled: true, Timeout: &metav1.Duration{Duration: 2 * time.Minute}},
+				&commonv1.AssociationConf{
+					AuthSecretName:   "kb-kibana-user",
+					AuthSecretKey:    "ns-kb-kibana-user",
+					IsServiceAccount: true,
+					URL:              "http://es-es-http.ns.svc:9200",
+				},
+			),
+			wantEnv: []corev1.EnvVar{
+				{Name: EnvWaitForElasticsearchURL, Value: "http://es-es-http.ns.svc:9200"},
+				{Name: EnvWaitForElasticsearchTimeoutSeconds, Value: "120"},
+				{Name: EnvWaitForElasticsearchToken, ValueFrom: passwordRef},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewPodTemplateSpec(context.Background(), k8s.NewFakeClient(authSecret), tt.kb, nil, []commonvolume.VolumeLike{}, false)
+			require.NoError(t, err)
+			var initContainer *corev1.Container
+			for i, c := range got.Spec.InitContainers {
+				if c.Name == InitWaitForElasticsearchContainerName {
+					initContainer = &got.Spec.InitContainers[i]
+				}
+			}
+			if tt.wantEnv == nil {
+				require.Nil(t, initContainer)
+				return
+			}
+			require.NotNil(t, initContainer)
+			assert.Equal(t, []string{"/usr/bin/env", "bash", "-c", waitForElasticsearchScript}, initContainer.Command)
+			assert.Contains(t, waitForElasticsearchScript, `curl "${curl_opts[@]}" "${ES_URL}/_cluster/health"`)
+			assert.Equal(t, GetKibanaContainer(got.Spec).Image, initContainer.Image)
+			// init container defaults add more environment variables and volume mounts
+			assert.Subset(t, initContainer.Env, tt.wantEnv)
+			assert.Subset(t, initContainer.VolumeMounts, tt.wantMounts)
+		})
+	}
+}
+
+func TestGetKibanaBasePath(t *testing.T) {
+	kibana := func(config map[string]interface{}, env ...corev1.EnvVar) kbv1.Kibana {
+		kb := kbv1.Kibana{Spec: kbv1.KibanaSpec{
+			PodTemplate: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: kbv1.KibanaContainerName, Env: env},
+			}}},
+		}}
+		if config != nil {
+			kb.Spec.Config = &commonv1.Config{Data: config}
+		}
+		return kb
+	}
+	tests := []struct {
+		name    string
+		kb      kbv1.Kibana
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no base path",
+			kb:   kbv1.Kibana{},
+		},
+		{
+			name: "base path not rewritten by Kibana",
+			kb:   kibana(map[string]interface{}{"server.basePath": "/kibana"}),
+		},
+		{
+			name: "base path rewritten by Kibana",
+			kb:   kibana(map[string]interface{}{"server": map[string]interface{}{"basePath": "/kibana", "rewriteBasePath": true}}),
+			want: "/kibana",
+		},
+		{
+			name: "base path set in the environment",
+			kb: kibana(nil,
+				corev1.EnvVar{Name: "SERVER_BASEPATH", Value: "/kibana/"},
+				corev1.EnvVar{Name: "SERVER_REWRITEBASEPATH", Value: "true"},
+			),
+			want: "/kibana",
+		},
+		{
+			name: "environment takes precedence over the configuration",
+			kb: kibana(map[string]interface{}{"server.basePath": "/kibana", "server.rewriteBasePath": true},
+				corev1.EnvVar{Name: "SERVER_REWRITEBASEPATH", Value: "false"},
+			),
+		},
+		{
+			name:    "invalid environment",
+			kb:      kibana(nil, corev1.EnvVar{Name: "SERVER_REWRITEBASEPATH", Value: "yes please"}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetKibanaBasePath(tt.kb)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewPodTemplateSpec_MetricsExporterCredentials(t *testing.T) {
+	kibana := func(credentialsSecretName string) kbv1.Kibana {
+		kb := kbv1.Kibana{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+			Spec: kbv1.KibanaSpec{
+				Version:          "8.12.0",
+				ElasticsearchRef: commonv1.ObjectSelector{Name: "es"},
+				MetricsExporter: &kbv1.MetricsExporter{
+					Image:                 "registry.internal/kibana-exporter:1.0.0",
+					CredentialsSecretName: credentialsSecretName,
+				},
+			},
+		}
+		kb.EsAssociation().SetAssociationConf(&commonv1.AssociationConf{
+			AuthSecretName: "kb-kibana-user",
+			AuthSecretKey:  "ns-kb-kibana-user",
+			URL:            "https://es-es-http.ns.svc:9200",
+		})
+		return kb
+	}
+	secretRef := func(name, key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+			Optional:             ptr.To(true),
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		kb      kbv1.Kibana
+		wantEnv []corev1.EnvVar
+	}{
+		{
+			// the credentials Kibana uses to connect to Elasticsearch are never provided to the exporter
+			name: "without user-provided credentials",
+			kb:   kibana(""),
+		},
+		{
+			name: "with user-provided credentials",
+			kb:   kibana("kb-exporter-credentials"),
+			wantEnv: []corev1.EnvVar{
+				{Name: EnvKibanaToken, ValueFrom: secretRef("kb-exporter-credentials", MetricsExporterCredentialsTokenKey)},
+				{Name: EnvKibanaUsername, ValueFrom: secretRef("kb-exporter-credentials", MetricsExporterCredentialsUsernameKey)},
+				{Name: EnvKibanaPassword, ValueFrom: secretRef("kb-exporter-credentials", MetricsExporterCredentialsPasswordKey)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewPodTemplateSpec(context.Background(), k8s.NewFakeClient(), tt.kb, nil, []commonvolume.VolumeLike{}, false)
+			require.NoError(t, err)
+			exporter := commonpod.ContainerByName(got.Spec, MetricsExporterContainerName)
+			require.NotNil(t, exporter)
+			assert.Equal(t, append([]corev1.EnvVar{
+				{Name: EnvKibanaURL, Value: "https://127.0.0.1:5601"},
+				{Name: EnvMetricsPort, Value: "9684"},
+			}, tt.wantEnv...), exporter.Env)
+		})
+	}
+}
+
+func TestNewPodTemplateSpec_ConfigMountPath(t *testing.T) {
+	tests := []struct {
+		name            string
+		version         string
+		configMountPath string
+		wantMountPath   string
+		wantEnv         []corev1.EnvVar
+	}{
+		{
+			name:          "default config mount path",
+			version:       "8.12.0",
+			wantMountPath: "/usr/share/kibana/config",
+		},
+		{
+			name:            "default config mount path set explicitly",
+			version:         "8.12.0",
+			configMountPath: "/usr/share/kibana/config",
+			wantMountPath:   "/usr/share/kibana/config",
+		},
+		{
+			name:            "custom config mount path",
+			version:         "8.12.0",
+			configMountPath: "/etc/kibana",
+			wantMountPath:   "/etc/kibana",
+			wantEnv:         []corev1.EnvVar{{Name: "KBN_PATH_CONF", Value: "/etc/kibana"}},
+		},
+		{
+			name:            "custom config mount path before 7.0",
+			version:         "6.8.0",
+			configMountPath: "/etc/kibana",
+			wantMountPath:   "/etc/kibana",
+			wantEnv:         []corev1.EnvVar{{Name: "KIBANA_PATH_CONF", Value: "/etc/kibana"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := kbv1.Kibana{Spec: kbv1.KibanaSpec{
+				Version:         tt.version,
+				ConfigMountPath: tt.configMountPath,
+				SecureSettings:  []commonv1.SecretSource{{SecretName: "secure-settings"}},
+			}}
+			keystoreParams, err := newInitContainersParameters(&kb)
+			require.NoError(t, err)
+			keystoreResources := &keystore.Resources{InitContainer: corev1.Container{Name: keystore.InitContainerName}}
+			volumes := []commonvolume.VolumeLike{configSharedVolume(kb)}
+
+			got, err := NewPodTemplateSpec(context.Background(), k8s.NewFakeClient(), kb, keystoreResources, volumes, false)
+			require.NoError(t, err)
+
+			kibanaContainer := GetKibanaContainer(got.Spec)
+			require.NotNil(t, kibanaContainer)
+			// the keystore is created in the config directory since 7.9
+			if version.MustParse(tt.version).GTE(keystoreInConfigDirVersion) {
+				assert.Equal(t, tt.wantMountPath, keystoreParams.KeystoreVolumePath)
+			}
+			// the Kibana container and the init containers agree on the config directory
+			for _, c := range append([]corev1.Container{*kibanaContainer}, got.Spec.InitContainers...) {
+				var configPathEnv []corev1.EnvVar
+				for _, envVar := range c.Env {
+					if envVar.Name == ConfigPathEnvVar || envVar.Name == LegacyConfigPathEnvVar {
+						configPathEnv = append(configPathEnv, envVar)
+					}
+				}
+				assert.Equal(t, tt.wantEnv, configPathEnv, c.Name)
+				// the init container preparing the config directory mounts it elsewhere
+				if c.Name != InitConfigContainerName {
+					assert.Contains(t, c.VolumeMounts, corev1.VolumeMount{Name: ConfigVolumeName, MountPath: tt.wantMountPath}, c.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestGetKibanaContainer(t *testing.T) {
+	tests := []struct {
+		name    string
+		podSpec corev1.PodSpec
+		want    *corev1.Container
+	}{
+		{
+			name:    "no container",
+			podSpec: corev1.PodSpec{},
+			want:    nil,
+		},
+		{
+			name: "Kibana container among other containers",
+			podSpec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "sidecar"},
+				{Name: kbv1.KibanaContainerName, Image: "kibana"},
+			}},
+			want: &corev1.Container{Name: kbv1.KibanaContainerName, Image: "kibana"},
+		},
+		{
+			name: "single container with a different name",
+			podSpec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "renamed", Image: "kibana"},
+			}},
+			want: &corev1.Container{Name: "renamed", Image: "kibana"},
+		},
+		{
+			name: "multiple containers and none is named after the Kibana container",
+			podSpec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "renamed", Image: "kibana"},
+				{Name: "sidecar"},
+			}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetKibanaContainer(tt.podSpec))
+		})
+	}
+}
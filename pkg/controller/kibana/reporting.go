@@ -0,0 +1,117 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/network"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	// ReportingEncryptionKeyKey is the entry of the user-provided Secret holding the reporting encryption key.
+	ReportingEncryptionKeyKey = "encryptionKey"
+
+	// ReportingSharedMemoryVolumeName is the name of the memory-backed volume used by Chromium as shared memory.
+	ReportingSharedMemoryVolumeName = "kibana-reporting-shm"
+	// ReportingSharedMemoryVolumeMountPath is the mount path of the shared memory volume in the Kibana container.
+	ReportingSharedMemoryVolumeMountPath = "/dev/shm"
+
+	XpackReportingKibanaServerHostname = "xpack.reporting.kibanaServer.hostname"
+	XpackReportingKibanaServerPort     = "xpack.reporting.kibanaServer.port"
+	XpackReportingKibanaServerProtocol = "xpack.reporting.kibanaServer.protocol"
+	XpackReportingQueueTimeout         = "xpack.reporting.queue.timeout"
+	XpackReportingCaptureMaxAttempts   = "xpack.reporting.capture.maxAttempts"
+
+	// minReportingEncryptionKeyLength is the minimum length of the reporting encryption key accepted by Kibana.
+	minReportingEncryptionKeyLength = 32
+)
+
+// reportingEncryptionKeyWatchName returns the name of the watch set on the user-provided reporting encryption key Secret.
+func reportingEncryptionKeyWatchName(kb types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-reporting-encryption-key", kb.Namespace, kb.Name)
+}
+
+// watchReportingEncryptionKey watches the user-provided reporting encryption key Secret, if any, to update the
+// configuration of Kibana when the key is rotated.
+func watchReportingEncryptionKey(kb kbv1.Kibana, dynamicWatches watches.DynamicWatches) error {
+	var secrets []string
+	if kb.Spec.Reporting != nil && kb.Spec.Reporting.EncryptionKeySecretName != "" {
+		secrets = append(secrets, kb.Spec.Reporting.EncryptionKeySecretName)
+	}
+	return watches.WatchUserProvidedSecrets(k8s.ExtractNamespacedName(&kb), dynamicWatches, reportingEncryptionKeyWatchName(k8s.ExtractNamespacedName(&kb)), secrets)
+}
+
+// reportingSettings returns the Kibana Reporting settings specified in the Kibana specification. The local Kibana
+// server is used to render the reports, since Kibana listens on all interfaces which Chromium cannot connect to.
+func reportingSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana) (map[string]interface{}, error) {
+	reporting := kb.Spec.Reporting
+	if reporting == nil {
+		return nil, nil
+	}
+	cfg := map[string]interface{}{
+		XpackReportingKibanaServerHostname: "localhost",
+		XpackReportingKibanaServerPort:     network.HTTPPort,
+		XpackReportingKibanaServerProtocol: kb.Spec.HTTP.Protocol(),
+	}
+	if reporting.QueueTimeout != nil {
+		cfg[XpackReportingQueueTimeout] = *reporting.QueueTimeout
+	}
+	if reporting.CaptureMaxAttempts != nil {
+		cfg[XpackReportingCaptureMaxAttempts] = *reporting.CaptureMaxAttempts
+	}
+	if reporting.EncryptionKeySecretName != "" {
+		encryptionKey, err := reportingEncryptionKey(ctx, client, kb)
+		if err != nil {
+			return nil, err
+		}
+		cfg[XpackReportingEncryptionKey] = encryptionKey
+	}
+	return cfg, nil
+}
+
+// reportingEncryptionKey returns the reporting encryption key of the user-provided Secret.
+func reportingEncryptionKey(ctx context.Context, client k8s.Client, kb kbv1.Kibana) (string, error) {
+	var secret corev1.Secret
+	if err := client.Get(ctx, types.NamespacedName{Namespace: kb.Namespace, Name: kb.Spec.Reporting.EncryptionKeySecretName}, &secret); err != nil {
+		return "", err
+	}
+	encryptionKey, exists := secret.Data[ReportingEncryptionKeyKey]
+	if !exists || len(encryptionKey) < minReportingEncryptionKeyLength {
+		return "", errors.Errorf(
+			"reporting encryption key secret %s/%s must contain a %s entry of at least %d characters",
+			secret.Namespace, secret.Name, ReportingEncryptionKeyKey, minReportingEncryptionKeyLength,
+		)
+	}
+	return string(encryptionKey), nil
+}
+
+// reportingSharedMemoryVolume returns the memory-backed volume mounted as the shared memory of the Kibana container,
+// since the default shared memory of containers is too small for Chromium to render reports.
+func reportingSharedMemoryVolume(reporting kbv1.ReportingSettings) (corev1.Volume, corev1.VolumeMount) {
+	sizeLimit := reporting.GetSharedMemorySize()
+	shmVolume := corev1.Volume{
+		Name: ReportingSharedMemoryVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				Medium:    corev1.StorageMediumMemory,
+				SizeLimit: &sizeLimit,
+			},
+		},
+	}
+	shmVolumeMount := corev1.VolumeMount{
+		Name:      ReportingSharedMemoryVolumeName,
+		MountPath: ReportingSharedMemoryVolumeMountPath,
+	}
+	return shmVolume, shmVolumeMount
+}
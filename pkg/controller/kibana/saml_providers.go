@@ -0,0 +1,135 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
+)
+
+const (
+	XpackSecurityAuthcProviders = "xpack.security.authc.providers"
+
+	// SAMLCallbackPath is the path of the Kibana endpoint consuming the SAML assertions of the identity providers.
+	SAMLCallbackPath = "/api/security/saml/callback"
+
+	// basicProviderName is the name of the basic provider generated along with the SAML providers.
+	basicProviderName = "basic1"
+)
+
+// minSAMLProvidersVersion is the first Kibana version supporting the named authentication providers syntax.
+var minSAMLProvidersVersion = version.MinFor(7, 10, 0)
+
+// samlProvidersWatchName returns the name of the watch set on the Elasticsearch cluster whose SAML realms are used to
+// generate the SAML providers.
+func samlProvidersWatchName(kb types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-saml-providers", kb.Namespace, kb.Name)
+}
+
+// samlProvidersElasticsearch returns the namespaced name of the Elasticsearch cluster whose SAML realms are used to
+// generate the SAML providers, if any.
+func samlProvidersElasticsearch(kb kbv1.Kibana) (types.NamespacedName, bool) {
+	if kb.Spec.SAMLProviders == nil || !kb.Spec.ElasticsearchRef.IsDefined() {
+		return types.NamespacedName{}, false
+	}
+	esRef := kb.EsAssociation().AssociationRef()
+	if esRef.IsExternal() {
+		// the realms of Elasticsearch clusters not managed by ECK are unknown
+		return types.NamespacedName{}, false
+	}
+	return esRef.NamespacedName(), true
+}
+
+// watchSAMLProvidersElasticsearch watches the associated Elasticsearch cluster if SAML providers are to be generated,
+// to update the configuration of Kibana when the SAML realms change.
+func watchSAMLProvidersElasticsearch(kb kbv1.Kibana, dynamicWatches watches.DynamicWatches) error {
+	kbKey := k8s.ExtractNamespacedName(&kb)
+	watchName := samlProvidersWatchName(kbKey)
+	esKey, ok := samlProvidersElasticsearch(kb)
+	if !ok {
+		dynamicWatches.ReferencedResources.RemoveHandlerForKey(watchName)
+		return nil
+	}
+	return dynamicWatches.ReferencedResources.AddHandler(watches.NamedWatch{
+		Name:    watchName,
+		Watched: []types.NamespacedName{esKey},
+		Watcher: kbKey,
+	})
+}
+
+// samlProvidersSettings returns the Kibana authentication providers matching the SAML realms of the associated
+// Elasticsearch cluster, in the order of the realms. A basic provider is appended unless disabled, since declaring
+// providers disables the default basic one.
+func samlProvidersSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v version.Version) (map[string]interface{}, error) {
+	esKey, ok := samlProvidersElasticsearch(kb)
+	if !ok {
+		return nil, nil
+	}
+	if v.LT(minSAMLProvidersVersion) {
+		ulog.FromContext(ctx).Info("SAML providers generation is not supported by this Kibana version, skipping",
+			"namespace", kb.Namespace, "kibana_name", kb.Name, "version", v.String())
+		return nil, nil
+	}
+	var es esv1.Elasticsearch
+	if err := client.Get(ctx, esKey, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	realms := selectSAMLRealms(kb, es.Spec.Auth.Realms.SAML)
+	if len(realms) == 0 {
+		return nil, nil
+	}
+	cfg := make(map[string]interface{}, 2*len(realms)+1)
+	for i, realm := range realms {
+		provider := stringsutil.Concat(XpackSecurityAuthcProviders, ".saml.", realm.Name)
+		cfg[provider+".order"] = i
+		cfg[provider+".realm"] = realm.Name
+	}
+	if !kb.Spec.SAMLProviders.DisableBasicProvider {
+		cfg[stringsutil.Concat(XpackSecurityAuthcProviders, ".basic.", basicProviderName, ".order")] = len(realms)
+	}
+	return cfg, nil
+}
+
+// selectSAMLRealms returns the SAML realms for which a provider is generated: the realms listed in the Kibana
+// specification, or the realms whose assertion consumer service is the SAML callback of the Kibana public base URL, or
+// all the realms if the public base URL is unknown.
+func selectSAMLRealms(kb kbv1.Kibana, realms []esv1.SAMLRealm) []esv1.SAMLRealm {
+	var selected []esv1.SAMLRealm
+	if len(kb.Spec.SAMLProviders.Realms) > 0 {
+		for _, realm := range realms {
+			if stringsutil.StringInSlice(realm.Name, kb.Spec.SAMLProviders.Realms) {
+				selected = append(selected, realm)
+			}
+		}
+		return selected
+	}
+	baseURL := kb.Spec.PublicBaseURL
+	if baseURL == "" {
+		return realms
+	}
+	callbackURL := strings.TrimSuffix(baseURL, "/") + SAMLCallbackPath
+	for _, realm := range realms {
+		if strings.TrimSuffix(realm.SP.ACS, "/") == callbackURL {
+			selected = append(selected, realm)
+		}
+	}
+	return selected
+}
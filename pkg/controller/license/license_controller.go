@@ -68,7 +68,7 @@ func (r *ReconcileLicenses) Reconcile(ctx context.Context, request reconcile.Req
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, p operator.Parameters) error {
 	r := newReconciler(mgr, p)
-	c, err := common.NewController(mgr, name, r, p)
+	c, err := common.NewController(mgr, name, r, &esv1.Elasticsearch{}, p)
 	if err != nil {
 		return err
 	}
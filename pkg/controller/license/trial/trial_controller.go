@@ -271,7 +271,7 @@ func addWatches(mgr manager.Manager, c controller.Controller) error {
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	r := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, name, r, params)
+	c, err := common.NewController(mgr, name, r, &corev1.Secret{}, params)
 	if err != nil {
 		return err
 	}
@@ -44,7 +44,7 @@ const (
 // The Manager will set fields on the Controller and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	r := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, controllerName, r, params)
+	c, err := common.NewController(mgr, controllerName, r, &logstashv1alpha1.Logstash{}, params)
 	if err != nil {
 		return err
 	}
@@ -161,7 +161,8 @@ func (r *ReconcileLogstash) Reconcile(ctx context.Context, request reconcile.Req
 		}
 		k8s.MaybeEmitErrorEvent(r.recorder, err, logstash, events.EventReconciliationError, "Reconciliation error: %v", err)
 	}
-	return results.WithError(err).Aggregate()
+	result, err := results.WithError(err).Aggregate()
+	return common.WithResync(ctx, logstash, r.ResyncPeriod, result), err
 }
 
 func (r *ReconcileLogstash) doReconcile(ctx context.Context, logstash logstashv1alpha1.Logstash) (*reconciler.Results, logstashv1alpha1.LogstashStatus) {
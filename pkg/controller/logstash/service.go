@@ -5,6 +5,8 @@
 package logstash
 
 import (
+	"context"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -41,7 +43,7 @@ func reconcileServices(params Params) ([]corev1.Service, corev1.Service, error)
 		svcs = append(svcs, *svc)
 	}
 	if !createdAPIService {
-		svc := newAPIService(params.Logstash)
+		svc := newAPIService(params.Context, params.Logstash)
 		if err := reconcileService(params, svc); err != nil {
 			return []corev1.Service{}, corev1.Service{}, err
 		}
@@ -80,7 +82,7 @@ func newService(service logstashv1alpha1.LogstashService, logstash logstashv1alp
 	return &svc
 }
 
-func newAPIService(logstash logstashv1alpha1.Logstash) *corev1.Service {
+func newAPIService(ctx context.Context, logstash logstashv1alpha1.Logstash) *corev1.Service {
 	svc := corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{},
 		Spec:       corev1.ServiceSpec{ClusterIP: "None"},
@@ -97,5 +99,5 @@ func newAPIService(logstash logstashv1alpha1.Logstash) *corev1.Service {
 			Port:     network.HTTPPort,
 		},
 	}
-	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
+	return defaults.SetServiceDefaults(ctx, &svc, labels, labels, ports)
 }
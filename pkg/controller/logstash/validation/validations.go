@@ -48,6 +48,7 @@ func validations() []validation {
 		checkESRefsNamed,
 		checkAssociations,
 		checkSinglePipelineSource,
+		checkTLSOptions,
 	}
 }
 
@@ -163,3 +164,11 @@ func check(ls *lsv1alpha1.Logstash, validations []validation) field.ErrorList {
 	}
 	return errs
 }
+
+func checkTLSOptions(l *lsv1alpha1.Logstash) field.ErrorList {
+	var errs field.ErrorList
+	for i, service := range l.Spec.Services {
+		errs = append(errs, commonv1.CheckNoElasticsearchOnlyTLSOptions(field.NewPath("spec").Child("services").Index(i).Child("tls"), service.TLS)...)
+	}
+	return errs
+}
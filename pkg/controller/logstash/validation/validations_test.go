@@ -405,3 +405,38 @@ func Test_checkESRefsNamed(t *testing.T) {
 		})
 	}
 }
+
+func Test_checkTLSOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []lsv1alpha1.LogstashService
+		wantErr  bool
+	}{
+		{
+			name:     "no TLS options: OK",
+			services: []lsv1alpha1.LogstashService{{Name: "api"}},
+			wantErr:  false,
+		},
+		{
+			name: "certificate: OK",
+			services: []lsv1alpha1.LogstashService{
+				{Name: "api", TLS: commonv1.TLSOptions{Certificate: commonv1.SecretRef{SecretName: "my-cert"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Elasticsearch only TLS options: NOK",
+			services: []lsv1alpha1.LogstashService{
+				{Name: "api"},
+				{Name: "beats", TLS: commonv1.TLSOptions{MinVersion: "TLSv1.3"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkTLSOptions(&lsv1alpha1.Logstash{Spec: lsv1alpha1.LogstashSpec{Services: tt.services}})
+			assert.Equal(t, tt.wantErr, len(got) > 0)
+		})
+	}
+}
@@ -52,7 +52,7 @@ const (
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	reconciler := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, controllerName, reconciler, params)
+	c, err := common.NewController(mgr, controllerName, reconciler, &emsv1alpha1.ElasticMapsServer{}, params)
 	if err != nil {
 		return err
 	}
@@ -179,7 +179,8 @@ func (r *ReconcileMapsServer) Reconcile(ctx context.Context, request reconcile.R
 		}
 		results.WithError(err)
 	}
-	return results.Aggregate()
+	result, err := results.Aggregate()
+	return common.WithResync(ctx, &ems, r.ResyncPeriod, result), err
 }
 
 func (r *ReconcileMapsServer) doReconcile(ctx context.Context, ems emsv1alpha1.ElasticMapsServer) (*reconciler.Results, emsv1alpha1.MapsStatus) {
@@ -213,7 +214,7 @@ func (r *ReconcileMapsServer) doReconcile(ctx context.Context, ems emsv1alpha1.E
 		return results.WithError(err), status
 	}
 
-	svc, err := common.ReconcileService(ctx, r.Client, NewService(ems), &ems)
+	svc, err := common.ReconcileService(ctx, r.Client, NewService(ctx, ems), &ems)
 	if err != nil {
 		return results.WithError(err), status
 	}
@@ -294,7 +295,7 @@ func (r *ReconcileMapsServer) validate(ctx context.Context, ems emsv1alpha1.Elas
 	return nil
 }
 
-func NewService(ems emsv1alpha1.ElasticMapsServer) *corev1.Service {
+func NewService(ctx context.Context, ems emsv1alpha1.ElasticMapsServer) *corev1.Service {
 	svc := corev1.Service{
 		ObjectMeta: ems.Spec.HTTP.Service.ObjectMeta,
 		Spec:       ems.Spec.HTTP.Service.Spec,
@@ -312,7 +313,7 @@ func NewService(ems emsv1alpha1.ElasticMapsServer) *corev1.Service {
 		},
 	}
 
-	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
+	return defaults.SetServiceDefaults(ctx, &svc, labels, labels, ports)
 }
 
 func buildConfigHash(c k8s.Client, ems emsv1alpha1.ElasticMapsServer, configSecret corev1.Secret) (string, error) {
@@ -47,7 +47,7 @@ var (
 // Add creates a new RemoteCa Controller and adds it to the manager with default RBAC.
 func Add(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
 	r := NewReconciler(mgr, accessReviewer, params)
-	c, err := common.NewController(mgr, name, r, params)
+	c, err := common.NewController(mgr, name, r, &esv1.Elasticsearch{}, params)
 	if err != nil {
 		return err
 	}
@@ -60,7 +60,7 @@ var (
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	r := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, controllerName, r, params)
+	c, err := common.NewController(mgr, controllerName, r, &policyv1alpha1.StackConfigPolicy{}, params)
 	if err != nil {
 		return err
 	}
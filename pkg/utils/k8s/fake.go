@@ -7,6 +7,7 @@ package k8s
 import (
 	"context"
 
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -17,10 +18,14 @@ import (
 
 // NewFakeClient creates a new fake Kubernetes client.
 func NewFakeClient(initObjs ...client.Object) Client {
+	// the preferred version of the PodDisruptionBudget resource is looked up before reconciling PDBs
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{policyv1.SchemeGroupVersion})
+	restMapper.Add(policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"), meta.RESTScopeNamespace)
 	return fake.NewClientBuilder().
 		WithObjects(initObjs...).
 		WithStatusSubresource(initObjs...).
 		WithScheme(clientgoscheme.Scheme).
+		WithRESTMapper(restMapper).
 		Build()
 }
 
@@ -6,6 +6,7 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 
@@ -210,6 +211,21 @@ func DeleteSecretIfExists(ctx context.Context, c Client, key types.NamespacedNam
 	return err
 }
 
+// PatchAnnotation sets the given annotation of the given object through a merge patch, or removes it if the value is
+// nil. Unlike an update, the patch does not conflict with other changes made to the object since it was retrieved.
+// The object is updated in place with the patched object, including its resource version.
+func PatchAnnotation(ctx context.Context, c Client, obj client.Object, name string, value *string) error {
+	mergePatch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]*string{name: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, mergePatch))
+}
+
 // PodsMatchingLabels returns Pods from the given namespace matching the given labels.
 func PodsMatchingLabels(c Client, namespace string, labels map[string]string) ([]corev1.Pod, error) {
 	var pods corev1.PodList
@@ -5,6 +5,7 @@
 package k8s
 
 import (
+	"context"
 	"net"
 	"reflect"
 	"testing"
@@ -361,3 +362,32 @@ func TestGetSecretEntriesCount(t *testing.T) {
 		})
 	}
 }
+
+func TestPatchAnnotation(t *testing.T) {
+	value := "value"
+	existing := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:        "secret",
+		Namespace:   "ns",
+		Annotations: map[string]string{"other": "other-value"},
+	}}
+	c := NewFakeClient(existing)
+
+	// retrieve the object before it is changed by someone else
+	var stale corev1.Secret
+	require.NoError(t, c.Get(context.Background(), ExtractNamespacedName(existing), &stale))
+	var secret corev1.Secret
+	require.NoError(t, c.Get(context.Background(), ExtractNamespacedName(existing), &secret))
+	secret.Labels = map[string]string{"label": "label-value"}
+	require.NoError(t, c.Update(context.Background(), &secret))
+
+	// the annotation is set without conflicting, the object is updated in place
+	require.NoError(t, PatchAnnotation(context.Background(), c, &stale, "name", &value))
+	require.Equal(t, map[string]string{"other": "other-value", "name": "value"}, stale.Annotations)
+	require.Equal(t, map[string]string{"label": "label-value"}, stale.Labels)
+	require.NoError(t, c.Get(context.Background(), ExtractNamespacedName(existing), &secret))
+	require.Equal(t, secret.ResourceVersion, stale.ResourceVersion)
+
+	// the annotation is removed
+	require.NoError(t, PatchAnnotation(context.Background(), c, &stale, "name", nil))
+	require.Equal(t, map[string]string{"other": "other-value"}, stale.Annotations)
+}
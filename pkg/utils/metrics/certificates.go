@@ -0,0 +1,39 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	certificatesSubsystem = "certificates"
+
+	CertificateTypeLabel = "type"
+)
+
+// CertificateRemainingValidityGauge reports the remaining validity of the certificates managed for each resource.
+var CertificateRemainingValidityGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Subsystem: certificatesSubsystem,
+	Name:      "remaining_validity_seconds",
+	Help:      "Remaining validity of the certificates in seconds, negative once expired. Broken down by resource namespace and name, and certificate type.",
+}, []string{NamespaceLabel, NameLabel, CertificateTypeLabel}))
+
+// SetCertificatesRemainingValidity reports the remaining validity of the certificates of the given resource, indexed
+// by certificate type. The certificates of the resource that are not part of the given ones are not reported anymore.
+func SetCertificatesRemainingValidity(namespace, name string, remainingValidity map[string]time.Duration) {
+	DeleteCertificatesRemainingValidity(namespace, name)
+	for certType, remaining := range remainingValidity {
+		CertificateRemainingValidityGauge.WithLabelValues(namespace, name, certType).Set(remaining.Seconds())
+	}
+}
+
+// DeleteCertificatesRemainingValidity stops reporting the remaining validity of the certificates of the given resource.
+func DeleteCertificatesRemainingValidity(namespace, name string) {
+	CertificateRemainingValidityGauge.DeletePartialMatch(prometheus.Labels{NamespaceLabel: namespace, NameLabel: name})
+}
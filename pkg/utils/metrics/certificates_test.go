@@ -0,0 +1,34 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCertificatesRemainingValidity(t *testing.T) {
+	SetCertificatesRemainingValidity("ns", "es", map[string]time.Duration{
+		"http":         36 * time.Hour,
+		"transport_ca": -time.Minute,
+	})
+	SetCertificatesRemainingValidity("ns", "other", map[string]time.Duration{"http": time.Hour})
+
+	require.Equal(t, float64(129600), testutil.ToFloat64(CertificateRemainingValidityGauge.WithLabelValues("ns", "es", "http")))
+	require.Equal(t, float64(-60), testutil.ToFloat64(CertificateRemainingValidityGauge.WithLabelValues("ns", "es", "transport_ca")))
+
+	// certificates not reported anymore are removed
+	SetCertificatesRemainingValidity("ns", "es", map[string]time.Duration{"transport_ca": time.Hour})
+	require.Equal(t, 2, testutil.CollectAndCount(CertificateRemainingValidityGauge))
+	require.Equal(t, float64(3600), testutil.ToFloat64(CertificateRemainingValidityGauge.WithLabelValues("ns", "es", "transport_ca")))
+
+	// other resources are left untouched on deletion
+	DeleteCertificatesRemainingValidity("ns", "es")
+	require.Equal(t, 1, testutil.CollectAndCount(CertificateRemainingValidityGauge))
+	require.Equal(t, float64(3600), testutil.ToFloat64(CertificateRemainingValidityGauge.WithLabelValues("ns", "other", "http")))
+}
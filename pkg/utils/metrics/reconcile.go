@@ -0,0 +1,148 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	reconcileSubsystem = "reconcile"
+
+	ControllerLabel = "controller"
+	NamespaceLabel  = "namespace"
+	NameLabel       = "name"
+	ResultLabel     = "result"
+	ReasonLabel     = "reason"
+
+	// ResultSuccess and ResultError are the values of the result label of the reconciliations counter.
+	ResultSuccess = "success"
+	ResultError   = "error"
+
+	// RequeueReasonError, RequeueReasonRequested and RequeueReasonScheduled are the values of the reason label of the
+	// requeues counter: the reconciliation returned an error, asked to be requeued immediately, or asked to be requeued
+	// after a delay.
+	RequeueReasonError     = "error"
+	RequeueReasonRequested = "requeue"
+	RequeueReasonScheduled = "requeue_after"
+)
+
+var (
+	resourceLabels = []string{ControllerLabel, NamespaceLabel, NameLabel}
+
+	// ReconcileDuration reports the duration of the reconciliations of each resource.
+	ReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: reconcileSubsystem,
+		Name:      "duration_seconds",
+		Help:      "Duration of the reconciliations in seconds. Broken down by controller, and resource namespace and name.",
+		Buckets:   []float64{0.005, 0.025, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0, 30.0, 60.0, 120.0},
+	}, resourceLabels)
+
+	// ReconcileTotal counts the reconciliations of each resource by result.
+	ReconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: reconcileSubsystem,
+		Name:      "total",
+		Help:      "Total number of reconciliations. Broken down by controller, resource namespace and name, and result.",
+	}, append(resourceLabels, ResultLabel))
+
+	// ReconcileRequeuesTotal counts the requeues of each resource by reason.
+	ReconcileRequeuesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: reconcileSubsystem,
+		Name:      "requeues_total",
+		Help:      "Total number of requeued reconciliations. Broken down by controller, resource namespace and name, and reason.",
+	}, append(resourceLabels, ReasonLabel))
+)
+
+func init() {
+	// register the prometheus collectors with the controller runtime registry, exposed on the metrics endpoint
+	crmetrics.Registry.MustRegister(ReconcileDuration, ReconcileTotal, ReconcileRequeuesTotal)
+}
+
+// instrumentedReconciler records the duration, result and requeue reason of the reconciliations of the wrapped
+// reconciler.
+type instrumentedReconciler struct {
+	controllerName string
+	reconciler     reconcile.Reconciler
+	client         client.Reader
+	resource       client.Object
+}
+
+// InstrumentReconciler wraps the given reconciler of the named controller to record the reconciliation metrics.
+// The metrics of a request are not reported anymore once its resource, of the same type as the given one, is not
+// found after a successful reconciliation.
+func InstrumentReconciler(controllerName string, r reconcile.Reconciler, c client.Reader, resource client.Object) reconcile.Reconciler {
+	return &instrumentedReconciler{controllerName: controllerName, reconciler: r, client: c, resource: resource}
+}
+
+// Reconcile implements the reconcile.Reconciler interface.
+func (r *instrumentedReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	result, err := r.reconciler.Reconcile(ctx, request)
+
+	ReconcileDuration.WithLabelValues(r.controllerName, request.Namespace, request.Name).Observe(time.Since(start).Seconds())
+
+	resultLabel := ResultSuccess
+	if err != nil {
+		resultLabel = ResultError
+	}
+	ReconcileTotal.WithLabelValues(r.controllerName, request.Namespace, request.Name, resultLabel).Inc()
+
+	if reason, requeued := requeueReason(result, err); requeued {
+		ReconcileRequeuesTotal.WithLabelValues(r.controllerName, request.Namespace, request.Name, reason).Inc()
+	}
+
+	if err == nil && r.isDeleted(ctx, request) {
+		DeleteReconcileMetrics(r.controllerName, request.Namespace, request.Name)
+	}
+	return result, err
+}
+
+// isDeleted returns true if the resource of the given request does not exist anymore.
+func (r *instrumentedReconciler) isDeleted(ctx context.Context, request reconcile.Request) bool {
+	if r.client == nil || r.resource == nil {
+		return false
+	}
+	resource, ok := r.resource.DeepCopyObject().(client.Object)
+	if !ok {
+		return false
+	}
+	return apierrors.IsNotFound(r.client.Get(ctx, request.NamespacedName, resource))
+}
+
+// DeleteReconcileMetrics stops reporting the reconciliation metrics of the given resource of the named controller.
+func DeleteReconcileMetrics(controllerName, namespace, name string) {
+	labels := prometheus.Labels{ControllerLabel: controllerName, NamespaceLabel: namespace, NameLabel: name}
+	ReconcileDuration.DeletePartialMatch(labels)
+	ReconcileTotal.DeletePartialMatch(labels)
+	ReconcileRequeuesTotal.DeletePartialMatch(labels)
+}
+
+// requeueReason returns the reason why the reconciliation is requeued, if it is.
+func requeueReason(result reconcile.Result, err error) (string, bool) {
+	switch {
+	case errors.Is(err, reconcile.TerminalError(nil)):
+		// terminal errors are not retried
+		return "", false
+	case err != nil:
+		return RequeueReasonError, true
+	case result.RequeueAfter > 0:
+		return RequeueReasonScheduled, true
+	case result.Requeue:
+		return RequeueReasonRequested, true
+	default:
+		return "", false
+	}
+}
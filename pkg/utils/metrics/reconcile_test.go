@@ -0,0 +1,165 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileMetricsRegistered(t *testing.T) {
+	// observe a value so that the vectors are gathered
+	InstrumentReconciler("registered-controller", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{Requeue: true}, nil
+	}), nil, nil).Reconcile(context.Background(), reconcile.Request{}) //nolint:errcheck
+
+	families, err := crmetrics.Registry.Gather()
+	require.NoError(t, err)
+	names := map[string]bool{}
+	for _, family := range families {
+		names[family.GetName()] = true
+	}
+	for _, name := range []string{
+		"elastic_reconcile_duration_seconds",
+		"elastic_reconcile_total",
+		"elastic_reconcile_requeues_total",
+	} {
+		require.True(t, names[name], "metric %s is not registered", name)
+	}
+}
+
+func TestInstrumentReconciler(t *testing.T) {
+	tests := []struct {
+		name        string
+		result      reconcile.Result
+		err         error
+		wantResult  string
+		wantRequeue string
+	}{
+		{
+			name:       "success",
+			wantResult: ResultSuccess,
+		},
+		{
+			name:        "requeue",
+			result:      reconcile.Result{Requeue: true},
+			wantResult:  ResultSuccess,
+			wantRequeue: RequeueReasonRequested,
+		},
+		{
+			name:        "requeue after",
+			result:      reconcile.Result{RequeueAfter: time.Minute},
+			wantResult:  ResultSuccess,
+			wantRequeue: RequeueReasonScheduled,
+		},
+		{
+			name:        "error",
+			err:         errors.New("boom"),
+			wantResult:  ResultError,
+			wantRequeue: RequeueReasonError,
+		},
+		{
+			name:       "terminal error",
+			err:        reconcile.TerminalError(errors.New("boom")),
+			wantResult: ResultError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controllerName := "test-controller-" + tt.name
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "resource"}}
+			r := InstrumentReconciler(controllerName, reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return tt.result, tt.err
+			}), nil, nil)
+
+			for i := 0; i < 2; i++ {
+				result, err := r.Reconcile(context.Background(), request)
+				require.Equal(t, tt.result, result)
+				require.Equal(t, tt.err, err)
+			}
+
+			require.Equal(t, uint64(2), sampleCount(t, ReconcileDuration.WithLabelValues(controllerName, "ns", "resource")))
+			require.Equal(t, float64(2), testutil.ToFloat64(ReconcileTotal.WithLabelValues(controllerName, "ns", "resource", tt.wantResult)))
+			for _, reason := range []string{RequeueReasonError, RequeueReasonRequested, RequeueReasonScheduled} {
+				want := float64(0)
+				if reason == tt.wantRequeue {
+					want = 2
+				}
+				require.Equal(t, want, testutil.ToFloat64(ReconcileRequeuesTotal.WithLabelValues(controllerName, "ns", "resource", reason)), reason)
+			}
+		})
+	}
+}
+
+func TestInstrumentReconciler_deletedResource(t *testing.T) {
+	controllerName := "test-controller-deleted"
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "resource"}}
+	c := fake.NewClientBuilder().WithObjects(secret).Build()
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "resource"}}
+	var err error
+	r := InstrumentReconciler(controllerName, reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{}, err
+	}), c, &corev1.Secret{})
+
+	// the metrics of an existing resource are reported
+	_, _ = r.Reconcile(context.Background(), request)
+	require.Equal(t, 1, seriesCount(t, ReconcileTotal, controllerName))
+
+	// as well as the ones of a failed reconciliation of a deleted resource, to be retried
+	require.NoError(t, c.Delete(context.Background(), secret))
+	err = errors.New("boom")
+	_, _ = r.Reconcile(context.Background(), request)
+	require.Equal(t, 2, seriesCount(t, ReconcileTotal, controllerName))
+
+	// they are not reported anymore once the deleted resource is successfully reconciled
+	err = nil
+	_, _ = r.Reconcile(context.Background(), request)
+	require.Equal(t, 0, seriesCount(t, ReconcileTotal, controllerName))
+	require.Equal(t, 0, seriesCount(t, ReconcileRequeuesTotal, controllerName))
+	require.Equal(t, 0, seriesCount(t, ReconcileDuration, controllerName))
+}
+
+// seriesCount returns the number of series of the given collector reported for the named controller.
+func seriesCount(t *testing.T, collector prometheus.Collector, controllerName string) int {
+	t.Helper()
+	ch := make(chan prometheus.Metric)
+	go func() {
+		collector.Collect(ch)
+		close(ch)
+	}()
+	count := 0
+	for metric := range ch {
+		var m dto.Metric
+		require.NoError(t, metric.Write(&m))
+		for _, label := range m.GetLabel() {
+			if label.GetName() == ControllerLabel && label.GetValue() == controllerName {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func sampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	t.Helper()
+	histogram, ok := observer.(prometheus.Histogram)
+	require.True(t, ok)
+	var metric dto.Metric
+	require.NoError(t, histogram.Write(&metric))
+	return metric.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,61 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package rbac
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// AnyNamespace matches any namespace in an entry of a NamespaceAllowlist.
+const AnyNamespace = "*"
+
+// namespacePair allows the resources in the source namespace to reference the resources in the target namespace.
+type namespacePair struct {
+	source string
+	target string
+}
+
+// NamespaceAllowlist restricts the cross-namespace references between resources to the listed pairs of namespaces.
+// An empty allowlist allows all the cross-namespace references.
+type NamespaceAllowlist []namespacePair
+
+// NewNamespaceAllowlist parses the given entries in the <source namespace>:<target namespace> format, where * matches
+// any namespace.
+func NewNamespaceAllowlist(entries []string) (NamespaceAllowlist, error) {
+	allowlist := make(NamespaceAllowlist, 0, len(entries))
+	for _, entry := range entries {
+		source, target, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found {
+			return nil, fmt.Errorf("invalid cross-namespace reference allowlist entry %q, expected <source namespace>:<target namespace>", entry)
+		}
+		for _, namespace := range []string{source, target} {
+			if namespace == AnyNamespace {
+				continue
+			}
+			if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid namespace %q in cross-namespace reference allowlist entry %q: %s", namespace, entry, strings.Join(errs, ", "))
+			}
+		}
+		allowlist = append(allowlist, namespacePair{source: source, target: target})
+	}
+	return allowlist, nil
+}
+
+// Allowed returns true if the resources in the source namespace are allowed to reference the resources in the target
+// namespace. References within a namespace are always allowed.
+func (a NamespaceAllowlist) Allowed(source, target string) bool {
+	if len(a) == 0 || source == target {
+		return true
+	}
+	for _, pair := range a {
+		if (pair.source == AnyNamespace || pair.source == source) && (pair.target == AnyNamespace || pair.target == target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNamespaceAllowlist(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    NamespaceAllowlist
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			entries: nil,
+			want:    NamespaceAllowlist{},
+		},
+		{
+			name:    "namespace pairs and wildcards",
+			entries: []string{"kibana-ns:elasticsearch-ns", " *:shared "},
+			want: NamespaceAllowlist{
+				{source: "kibana-ns", target: "elasticsearch-ns"},
+				{source: "*", target: "shared"},
+			},
+		},
+		{
+			name:    "missing target namespace",
+			entries: []string{"kibana-ns"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid namespace",
+			entries: []string{"kibana-ns:Elasticsearch_NS"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewNamespaceAllowlist(tt.entries)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNamespaceAllowlist_Allowed(t *testing.T) {
+	allowlist := NamespaceAllowlist{
+		{source: "kibana-ns", target: "elasticsearch-ns"},
+		{source: "*", target: "shared"},
+		{source: "monitored", target: "*"},
+	}
+	tests := []struct {
+		name      string
+		allowlist NamespaceAllowlist
+		source    string
+		target    string
+		want      bool
+	}{
+		{name: "empty allowlist allows everything", allowlist: nil, source: "a", target: "b", want: true},
+		{name: "same namespace is always allowed", allowlist: allowlist, source: "a", target: "a", want: true},
+		{name: "allowed pair", allowlist: allowlist, source: "kibana-ns", target: "elasticsearch-ns", want: true},
+		{name: "pairs are not symmetric", allowlist: allowlist, source: "elasticsearch-ns", target: "kibana-ns", want: false},
+		{name: "any source namespace", allowlist: allowlist, source: "a", target: "shared", want: true},
+		{name: "any target namespace", allowlist: allowlist, source: "monitored", target: "b", want: true},
+		{name: "not allowed", allowlist: allowlist, source: "a", target: "elasticsearch-ns", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.allowlist.Allowed(tt.source, tt.target))
+		})
+	}
+}
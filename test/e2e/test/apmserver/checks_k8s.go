@@ -7,6 +7,7 @@ package apmserver
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -117,6 +118,7 @@ func CheckStatus(b Builder, k *test.K8sClient) test.Step {
 			// don't check association statuses that may vary across tests
 			as.Status.ElasticsearchAssociationStatus = ""
 			as.Status.KibanaAssociationStatus = ""
+			as.Status.Conditions = nil
 			as.Status.ObservedGeneration = 0
 
 			// Selector is a string built from a map, it is validated with a dedicated function.
@@ -142,7 +144,7 @@ func CheckStatus(b Builder, k *test.K8sClient) test.Step {
 					Health:         "green",
 				},
 			}
-			if as.Status != expected {
+			if !reflect.DeepEqual(as.Status, expected) {
 				return fmt.Errorf("expected status %+v but got %+v", expected, as.Status)
 			}
 			return nil
@@ -42,7 +42,7 @@ func CheckHTTPConnectivityWithCA(es esv1.Elasticsearch, k *test.K8sClient, caCer
 	}
 
 	for _, p := range reconcile.AvailableElasticsearchNodes(pods) {
-		url := services.ElasticsearchPodURL(p)
+		url := services.ElasticsearchPodURL(es, p)
 		esClient := client.NewElasticsearchClient(
 			dialer,
 			k8s.ExtractNamespacedName(&es),
@@ -20,14 +20,13 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/dev/portforward"
-	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/v2/test/e2e/test"
 )
 
 // CheckTransportCACertificate attempts a TLS handshake to inspect the peer certificates presented by the Elasticsearch
 // node to verify the expected CA certificate is among them.
 func CheckTransportCACertificate(es esv1.Elasticsearch, ca *x509.Certificate) error {
-	host := services.ExternalTransportServiceHost(k8s.ExtractNamespacedName(&es))
+	host := services.ExternalTransportServiceHost(es)
 	var conn net.Conn
 	var err error
 